@@ -0,0 +1,159 @@
+// Package canonical implements the deterministic binary encoding that is
+// hashed to produce the IDs and signatures of orders, order fragments, and
+// delta fragments. Every value has exactly one encoding, so that the encoding
+// can be reproduced by clients that are not written in Go.
+//
+// An encoding starts with the Version, as a single byte, followed by the tag
+// that identifies the type of the encoded value, and then the fields of the
+// value in the order that they are written. Fields are encoded as follows.
+//
+//	uint8    1 byte
+//	int64    8 bytes, little endian, two's complement
+//	bytes    uint32 length, 4 bytes little endian, followed by the bytes
+//	time     int64 seconds since the Unix epoch
+//	integer  bytes of the big endian absolute value, without leading zeroes
+//	share    int64 key, followed by the integer value
+//	flag     uint8 that is 1 if the optional value that follows is present,
+//	         and 0 if it is absent
+//
+// The tag is encoded as bytes. A list is encoded as its uint32 length,
+// followed by its elements. Every optional field is preceded by a flag, and
+// is only written when it is present, so that the presence of each optional
+// field is unambiguous wherever it appears in the encoding.
+package canonical
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"math"
+	"math/big"
+	"time"
+
+	"github.com/republicprotocol/republic-go/shamir"
+	"github.com/republicprotocol/republic-go/stackint"
+)
+
+// Version of the canonical encoding. It is written at the start of every
+// encoding, and is incremented whenever the encoding of any value changes.
+const Version uint8 = 3
+
+// ErrNilValue is returned when a nil integer is encoded.
+var ErrNilValue = errors.New("cannot encode nil value")
+
+// ErrValueTooLong is returned when a value is too long for its length to be
+// encoded.
+var ErrValueTooLong = errors.New("cannot encode value longer than 2^32 - 1 bytes")
+
+// An Encoder writes the canonical encoding of a value. The first error that
+// occurs is remembered and all later writes are ignored, so that the error
+// only needs to be checked once, when the encoding is returned by Bytes.
+type Encoder struct {
+	buf *bytes.Buffer
+	err error
+}
+
+// NewEncoder returns an Encoder that has written the Version and the tag.
+func NewEncoder(tag []byte) *Encoder {
+	encoder := &Encoder{
+		buf: new(bytes.Buffer),
+	}
+	encoder.WriteUint8(Version)
+	encoder.WriteBytes(tag)
+	return encoder
+}
+
+// WriteUint8 writes a single byte.
+func (encoder *Encoder) WriteUint8(value uint8) {
+	if encoder.err != nil {
+		return
+	}
+	encoder.err = encoder.buf.WriteByte(value)
+}
+
+// WriteFlag writes 1 if the optional value that follows is present, and 0
+// if it is absent.
+func (encoder *Encoder) WriteFlag(present bool) {
+	if present {
+		encoder.WriteUint8(1)
+		return
+	}
+	encoder.WriteUint8(0)
+}
+
+// WriteInt64 writes an int64 as 8 little endian bytes.
+func (encoder *Encoder) WriteInt64(value int64) {
+	if encoder.err != nil {
+		return
+	}
+	encoder.err = binary.Write(encoder.buf, binary.LittleEndian, value)
+}
+
+// WriteLength writes the length of a list, or a slice of bytes, as a uint32.
+func (encoder *Encoder) WriteLength(length int) {
+	if encoder.err != nil {
+		return
+	}
+	if length < 0 || uint64(length) > math.MaxUint32 {
+		encoder.err = ErrValueTooLong
+		return
+	}
+	encoder.err = binary.Write(encoder.buf, binary.LittleEndian, uint32(length))
+}
+
+// WriteBytes writes the length of a slice of bytes, followed by the bytes. A
+// nil slice is written in the same way as an empty slice.
+func (encoder *Encoder) WriteBytes(value []byte) {
+	encoder.WriteLength(len(value))
+	if encoder.err != nil {
+		return
+	}
+	_, encoder.err = encoder.buf.Write(value)
+}
+
+// WriteTime writes a time as the number of seconds since the Unix epoch.
+func (encoder *Encoder) WriteTime(value time.Time) {
+	encoder.WriteInt64(value.Unix())
+}
+
+// WriteInt1024 writes the big endian bytes of an Int1024. An ErrNilValue is
+// returned by Bytes if the Int1024 is nil.
+func (encoder *Encoder) WriteInt1024(value *stackint.Int1024) {
+	if value == nil {
+		encoder.fail(ErrNilValue)
+		return
+	}
+	// The Int1024 encodes zero as a single byte, which is a leading zero
+	encoder.WriteBytes(bytes.TrimLeft(value.Bytes(), "\x00"))
+}
+
+// WriteBigInt writes the big endian bytes of the absolute value of a big.Int.
+// An ErrNilValue is returned by Bytes if the big.Int is nil.
+func (encoder *Encoder) WriteBigInt(value *big.Int) {
+	if value == nil {
+		encoder.fail(ErrNilValue)
+		return
+	}
+	encoder.WriteBytes(value.Bytes())
+}
+
+// WriteShare writes the key of a shamir.Share, followed by its value.
+func (encoder *Encoder) WriteShare(share shamir.Share) {
+	encoder.WriteInt64(share.Key)
+	encoder.WriteInt1024(&share.Value)
+}
+
+// Bytes returns the encoding, or the first error that occurred while writing
+// it.
+func (encoder *Encoder) Bytes() ([]byte, error) {
+	if encoder.err != nil {
+		return nil, encoder.err
+	}
+	return encoder.buf.Bytes(), nil
+}
+
+func (encoder *Encoder) fail(err error) {
+	if encoder.err == nil {
+		encoder.err = err
+	}
+}
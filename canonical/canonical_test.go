@@ -0,0 +1,99 @@
+package canonical_test
+
+import (
+	"encoding/hex"
+	"math/big"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	. "github.com/republicprotocol/republic-go/canonical"
+
+	"github.com/republicprotocol/republic-go/shamir"
+	"github.com/republicprotocol/republic-go/stackint"
+)
+
+var _ = Describe("Canonical encoding", func() {
+
+	Context("when writing values", func() {
+
+		It("should start with the version and the tag", func() {
+			data, err := NewEncoder([]byte("tag")).Bytes()
+			Ω(err).ShouldNot(HaveOccurred())
+			Ω(hex.EncodeToString(data)).Should(Equal("0303000000746167"))
+		})
+
+		It("should write integers in little endian", func() {
+			encoder := NewEncoder(nil)
+			encoder.WriteUint8(0xAB)
+			encoder.WriteInt64(-2)
+			encoder.WriteTime(time.Unix(1500000000, 999))
+			data, err := encoder.Bytes()
+			Ω(err).ShouldNot(HaveOccurred())
+			Ω(hex.EncodeToString(data)).Should(Equal("0300000000" + "ab" + "feffffffffffffff" + "002f685900000000"))
+		})
+
+		It("should prefix bytes with their length", func() {
+			encoder := NewEncoder(nil)
+			encoder.WriteBytes([]byte("ab"))
+			encoder.WriteBytes(nil)
+			encoder.WriteBytes([]byte{})
+			data, err := encoder.Bytes()
+			Ω(err).ShouldNot(HaveOccurred())
+			Ω(hex.EncodeToString(data)).Should(Equal("0300000000" + "020000006162" + "00000000" + "00000000"))
+		})
+
+		It("should write big endian integers without leading zeroes", func() {
+			zero := stackint.Zero()
+			value := stackint.FromUint(1000)
+			encoder := NewEncoder(nil)
+			encoder.WriteInt1024(&zero)
+			encoder.WriteInt1024(&value)
+			encoder.WriteBigInt(big.NewInt(1000))
+			encoder.WriteShare(shamir.Share{Key: 1, Value: value})
+			data, err := encoder.Bytes()
+			Ω(err).ShouldNot(HaveOccurred())
+			Ω(hex.EncodeToString(data)).Should(Equal("0300000000" + "00000000" + "0200000003e8" + "0200000003e8" + "0100000000000000" + "0200000003e8"))
+		})
+
+		It("should write flags for optional values", func() {
+			encoder := NewEncoder(nil)
+			encoder.WriteFlag(true)
+			encoder.WriteFlag(false)
+			data, err := encoder.Bytes()
+			Ω(err).ShouldNot(HaveOccurred())
+			Ω(hex.EncodeToString(data)).Should(Equal("0300000000" + "01" + "00"))
+		})
+	})
+
+	Context("when writing invalid values", func() {
+
+		It("should return an error for nil integers", func() {
+			encoder := NewEncoder(nil)
+			encoder.WriteInt1024(nil)
+			_, err := encoder.Bytes()
+			Ω(err).Should(Equal(ErrNilValue))
+
+			encoder = NewEncoder(nil)
+			encoder.WriteBigInt(nil)
+			_, err = encoder.Bytes()
+			Ω(err).Should(Equal(ErrNilValue))
+		})
+
+		It("should return an error for negative lengths", func() {
+			encoder := NewEncoder(nil)
+			encoder.WriteLength(-1)
+			_, err := encoder.Bytes()
+			Ω(err).Should(Equal(ErrValueTooLong))
+		})
+
+		It("should return the first error", func() {
+			encoder := NewEncoder(nil)
+			encoder.WriteInt1024(nil)
+			encoder.WriteLength(-1)
+			encoder.WriteInt64(1)
+			_, err := encoder.Bytes()
+			Ω(err).Should(Equal(ErrNilValue))
+		})
+	})
+})
@@ -13,10 +13,13 @@ import (
 
 	"github.com/ethereum/go-ethereum/accounts/abi/bind"
 	"github.com/ethereum/go-ethereum/accounts/keystore"
+	"github.com/ethereum/go-ethereum/common"
 	"github.com/republicprotocol/republic-go/contracts/connection"
 	"github.com/republicprotocol/republic-go/contracts/dnr"
 	"github.com/republicprotocol/republic-go/dark-node"
 	"github.com/republicprotocol/republic-go/identity"
+	"github.com/republicprotocol/republic-go/order/market"
+	"github.com/republicprotocol/republic-go/settlement"
 )
 
 func main() {
@@ -42,6 +45,25 @@ func main() {
 		log.Fatal(err)
 	}
 
+	// List the markets of the market registry contract, if there is one
+	if config.MarketRegistryAddress != "" {
+		source, err := CreateEthereumMarketSource(config.EthereumRPC, config.MarketRegistryAddress)
+		if err != nil {
+			log.Fatal(err)
+		}
+		if err := node.Markets.Load(source); err != nil {
+			log.Fatal(err)
+		}
+	}
+
+	// Settle matches using the settlement contract, if there is one
+	if config.SettlementAddress != "" {
+		node.Settler, err = CreateEthereumSettler(config.EthereumKey, config.EthereumRPC, config.SettlementAddress, config.KeyPair.ID())
+		if err != nil {
+			log.Fatal(err)
+		}
+	}
+
 	go node.StartServices()
 	go node.StartUI()
 	node.StartBackgroundWorkers()
@@ -112,3 +134,24 @@ func CreateDarkNodeRegistry(ethereumKey keystore.Key, ethereumRPC string) (dnr.D
 	}
 	return dnr.NewDarkNodeRegistry(context.Background(), &client, auth, &bind.CallOpts{})
 }
+
+// CreateEthereumSettler returns a Settler that uses the settlement contract at
+// the provided address on behalf of the dark node with the provided ID
+func CreateEthereumSettler(ethereumKey keystore.Key, ethereumRPC, settlementAddress string, darkNodeID identity.ID) (*settlement.EthereumSettler, error) {
+	auth := bind.NewKeyedTransactor(ethereumKey.PrivateKey)
+	client, err := connection.FromURI(ethereumRPC, connection.ChainRopsten)
+	if err != nil {
+		return nil, err
+	}
+	return settlement.NewEthereumSettler(context.Background(), &client, auth, &bind.CallOpts{}, common.HexToAddress(settlementAddress), darkNodeID)
+}
+
+// CreateEthereumMarketSource returns a market.Source that lists the currencies
+// and markets of the market registry contract at the provided address
+func CreateEthereumMarketSource(ethereumRPC, marketRegistryAddress string) (*market.EthereumSource, error) {
+	client, err := connection.FromURI(ethereumRPC, connection.ChainRopsten)
+	if err != nil {
+		return nil, err
+	}
+	return market.NewEthereumSource(&client, &bind.CallOpts{}, common.HexToAddress(marketRegistryAddress))
+}
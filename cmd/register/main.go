@@ -92,7 +92,7 @@ func RegisterAll(secretFile string, configs []*node.Config) {
 		// }
 
 		if !isRegistered { // && !isPendingRegistration {
-			_, err = registrar.Register(keypair.ID(), append(keypair.PublicKey.X.Bytes(), keypair.PublicKey.Y.Bytes()...), &bond)
+			_, err = registrar.Register(keypair.ID(), keypair.PublicKeyBytes(), &bond)
 			if err != nil {
 				log.Printf("[%v] %sCouldn't register node%s: %v\n", base58.Encode(keypair.ID()), red, reset, err)
 			} else {
@@ -7,25 +7,43 @@ import (
 	"io/ioutil"
 	"log"
 	"net/http"
-	"strconv"
 	"time"
 
 	"github.com/republicprotocol/republic-go/stackint"
 
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
 	"github.com/jbenet/go-base58"
-	"github.com/republicprotocol/go-do"
+	"github.com/republicprotocol/republic-go/compute"
+	"github.com/republicprotocol/republic-go/contracts/connection"
+	"github.com/republicprotocol/republic-go/dark"
 	"github.com/republicprotocol/republic-go/identity"
 	"github.com/republicprotocol/republic-go/network/rpc"
 	"github.com/republicprotocol/republic-go/order"
+	"github.com/republicprotocol/republic-go/order/fixed"
+	"github.com/republicprotocol/republic-go/order/market"
+	"github.com/republicprotocol/republic-go/shamir"
+	"github.com/republicprotocol/republic-go/trader"
 )
 
-var prime, _ = stackint.FromString("179769313486231590772930519078902473361797697894230657273430081157732675805500963132708477322407536021120113879871393357658789768814416622492847430639474124377767893424865485276302219601246094119453082952085005768838150682342462881473913110540827237163350510684586298239947245938479716304835356329624224137111")
-
 const reset = "\x1b[0m"
 const yellow = "\x1b[33;1m"
 const green = "\x1b[32;1m"
 const red = "\x1b[31;1m"
 
+// orderTypes are the values of the type flag.
+var orderTypes = map[string]order.Type{
+	"limit": order.TypeLimit,
+	"ibbo":  order.TypeIBBO,
+}
+
+// timesInForce are the values of the tif flag.
+var timesInForce = map[string]order.TimeInForce{
+	"gtc": order.TimeInForceGTC,
+	"ioc": order.TimeInForceIOC,
+	"fok": order.TimeInForceFOK,
+}
+
 // OrderBook is the data returned from binance
 type OrderBook struct {
 	LastUpdateID int        `json:"lastUpdateId"`
@@ -37,35 +55,94 @@ func main() {
 	// Parse the option parameters
 	numberOfOrders := flag.Int("order", 10, "number of orders")
 	timeInterval := flag.Int("time", 15, "time interval in second")
+	expiry := flag.Duration("expiry", 24*time.Hour, "time until orders expire")
+	fieldName := flag.String("field", shamir.FieldDefault, "name of the finite field used by the dark nodes")
+	marketsFile := flag.String("markets", "", "configuration file of additional currencies and markets")
+	marketRegistryAddress := flag.String("marketRegistry", "", "address of a market registry contract that lists additional currencies and markets")
+	ethereumRPC := flag.String("ethereumRPC", "", "URI of the Ethereum node used to read the market registry contract")
+	orderTypeName := flag.String("type", "limit", "type of orders: limit, or ibbo to execute at the midpoint")
+	timeInForceName := flag.String("tif", "gtc", "time in force of orders: gtc, ioc, or fok")
+	priceFeedFile := flag.String("priceFeed", "", "key pair file of the price feed, used to send the midpoint of the order book to the dark nodes")
+	flag.Parse()
 
-	// Get nodes/darkPool details
+	orderType, ok := orderTypes[*orderTypeName]
+	if !ok {
+		log.Fatalf("unknown order type: %s", *orderTypeName)
+	}
+	timeInForce, ok := timesInForce[*timeInForceName]
+	if !ok {
+		log.Fatalf("unknown time in force: %s", *timeInForceName)
+	}
+	// Dark nodes close the window of immediate orders, and take the midpoint
+	// of IBBO orders, at their open time
+	hasOpenTime := timeInForce.IsImmediate() || orderType == order.TypeIBBO
+
+	// The dark nodes form a single dark pool
 	multiAddresses := getNodesDetails()
-	nodes := make([]identity.MultiAddress, len(multiAddresses))
+	pool := dark.NewPool()
 	for i := 0; i < len(multiAddresses); i++ {
 		multi, err := identity.NewMultiAddressFromString(multiAddresses[i])
 		if err != nil {
 			log.Fatal(err)
 		}
-		nodes[i] = multi
-		log.Println(base58.Encode(nodes[i].ID()))
+		node := dark.NewNode(multi.ID())
+		node.SetMultiAddress(multi)
+		pool.Append(node)
+		log.Println(base58.Encode(multi.ID()))
 	}
+	darkOcean := &staticDarkOcean{pools: dark.Pools{pool}}
 
-	// Create a trader address
-	keypair, err := identity.NewKeyPair()
+	// Orders can only be opened in listed markets
+	registry := market.NewDefaultRegistry()
+	if *marketsFile != "" {
+		config, err := market.LoadConfig(*marketsFile)
+		if err != nil {
+			log.Fatal(err)
+		}
+		if err := registry.Load(config); err != nil {
+			log.Fatal(err)
+		}
+	}
+	if *marketRegistryAddress != "" {
+		client, err := connection.FromURI(*ethereumRPC, connection.ChainRopsten)
+		if err != nil {
+			log.Fatal(err)
+		}
+		source, err := market.NewEthereumSource(&client, &bind.CallOpts{}, common.HexToAddress(*marketRegistryAddress))
+		if err != nil {
+			log.Fatal(err)
+		}
+		if err := registry.Load(source); err != nil {
+			log.Fatal(err)
+		}
+	}
+
+	// Sell orders are opened by a different trader, because orders from the
+	// same trader are never matched. Dark nodes only accept order fragments
+	// that were split in the same finite field that they use.
+	buyer, err := newTrader(darkOcean, registry, *fieldName)
 	if err != nil {
 		log.Fatal(err)
 	}
-	address := keypair.Address()
-	multi, err := identity.NewMultiAddressFromString("/ip4/0.0.0.0/tcp/80/republic/" + address.String())
+	seller, err := newTrader(darkOcean, registry, *fieldName)
 	if err != nil {
 		log.Fatal(err)
 	}
-	log.Println("Trader Address: ", address)
-
-	multiSignature, err := keypair.Sign(multi)
+	field, err := shamir.FieldByName(*fieldName)
+	if err != nil {
+		log.Fatal(err)
+	}
+	eth, err := registry.Currency(order.CurrencyCodeETH)
 	if err != nil {
 		log.Fatal(err)
 	}
+	var feed *priceFeed
+	if *priceFeedFile != "" {
+		feed, err = newPriceFeed(*priceFeedFile)
+		if err != nil {
+			log.Fatal(err)
+		}
+	}
 
 	// Keep sending order fragment
 	for {
@@ -79,82 +156,102 @@ func main() {
 		orderBook := new(OrderBook)
 		err = json.Unmarshal(response, orderBook)
 
+		// IBBO orders are executed at the midpoint sent by the price feed
+		if feed != nil && len(orderBook.Bids) > 0 && len(orderBook.Asks) > 0 {
+			if err := feed.updateMidpoint(pool, orderBook.Bids[0][0], orderBook.Asks[0][0]); err != nil {
+				log.Println(err)
+			}
+		}
+
 		// Generate order from the Binance data
 		sellOrders := make([]*order.Order, len(orderBook.Asks))
 		for i, j := range orderBook.Asks {
-			priceInt, err := strconv.ParseFloat(j[0], 10)
-			price := stackint.FromUint(uint(priceInt * 1000000000000))
+			price, err := fixed.EncodePrice(j[0])
 			if err != nil {
-				log.Fatal("fail to parse the price into a big int")
+				log.Fatal("fail to encode the price: ", err)
+			}
+			if orderType == order.TypeIBBO {
+				// IBBO orders are executed at the midpoint
+				price = stackint.Zero()
 			}
 
-			amountInt, err := strconv.ParseFloat(j[1], 10)
-			amount := stackint.FromUint(uint(amountInt * 1000000000000))
+			amount, err := eth.EncodeVolume(j[1])
 			if err != nil {
-				log.Fatal("fail to parse the amount into a big int")
+				log.Fatal("fail to encode the amount: ", err)
 			}
-			nonce := stackint.One()
-			order := order.NewOrder(order.TypeLimit, order.ParitySell, time.Time{},
+			order, err := registry.NewOrder(orderType, order.ParitySell, time.Now().Add(*expiry),
 				order.CurrencyCodeETH, order.CurrencyCodeBTC, &price, &amount,
-				&amount, &nonce)
+				&amount, seller.NextNonce())
+			if err != nil {
+				log.Fatal(err)
+			}
+			order.WithTimeInForce(timeInForce)
+			if hasOpenTime {
+				order.WithOpenTime(time.Now())
+			}
+			if err := fixed.VerifyOrder(order, field); err != nil {
+				log.Fatal(err)
+			}
 			sellOrders[i] = order
 		}
 
 		buyOrders := make([]*order.Order, len(orderBook.Bids))
 		//test cast for match
 		for i, j := range orderBook.Asks { //change asks/bids
-			priceInt, err := strconv.ParseFloat(j[0], 10)
-			price := stackint.FromUint(uint(priceInt * 1000000000000))
+			price, err := fixed.EncodePrice(j[0])
 			if err != nil {
-				log.Fatal("fail to parse the price into a big int")
+				log.Fatal("fail to encode the price: ", err)
+			}
+			if orderType == order.TypeIBBO {
+				// IBBO orders are executed at the midpoint
+				price = stackint.Zero()
 			}
 
-			amountInt, err := strconv.ParseFloat(j[1], 10)
-			amount := stackint.FromUint(uint(amountInt * 1000000000000))
+			amount, err := eth.EncodeVolume(j[1])
 			if err != nil {
-				log.Fatal("fail to parse the amount into a big int")
+				log.Fatal("fail to encode the amount: ", err)
 			}
-			nonce := stackint.One()
-			order := order.NewOrder(order.TypeLimit, order.ParityBuy, time.Time{},
+			order, err := registry.NewOrder(orderType, order.ParityBuy, time.Now().Add(*expiry),
 				order.CurrencyCodeETH, order.CurrencyCodeBTC, &price, &amount,
-				&amount, &nonce)
+				&amount, buyer.NextNonce())
+			if err != nil {
+				log.Fatal(err)
+			}
+			order.WithTimeInForce(timeInForce)
+			if hasOpenTime {
+				order.WithOpenTime(time.Now())
+			}
+			if err := fixed.VerifyOrder(order, field); err != nil {
+				log.Fatal(err)
+			}
 			buyOrders[i] = order
 		}
 
-		// Send order fragment to the nodes
+		// Open the orders in the dark pool
 		for _, orders := range [][]*order.Order{buyOrders, sellOrders} {
 			go func(orders []*order.Order) {
 				for _, ord := range orders {
-					//todo
+					t := buyer
 					if ord.Parity == order.ParityBuy {
 						log.Println("sending buy order :", base58.Encode(ord.ID))
 					} else {
 						log.Println("sending sell order :", base58.Encode(ord.ID))
+						t = seller
 					}
 
-					fragments, err := ord.Split(int64(len(nodes)), int64(len(nodes)*2/3), &prime)
-					if err != nil {
+					status, err := t.Open(ord)
+					if err != nil && status == nil {
+						log.Println(err)
 						continue
 					}
-
-					do.ForAll(fragments, func(i int) {
-						client, err := rpc.NewClient(nodes[i], multi, multiSignature)
-						if err != nil {
-							log.Fatal(err)
+					for _, poolStatus := range status.Pools {
+						for _, delivery := range poolStatus.Opened {
+							if delivery.Err != nil {
+								log.Println(delivery.Err)
+								log.Printf("%sCoudln't send order fragment to %v%s\n", red, base58.Encode(delivery.NodeID), reset)
+							}
 						}
-
-						// Sign order fragment
-						err = fragments[i].Sign(keypair)
-						if err != nil {
-							log.Fatal(err)
-						}
-						err = client.OpenOrder(rpc.SerializeOrderFragment(fragments[i]))
-						if err != nil {
-							log.Println(err)
-							log.Printf("%sCoudln't send order fragment to %v%s\n", red, base58.Encode(nodes[i].ID()), reset)
-							return
-						}
-					})
+					}
 				}
 			}(orders)
 		}
@@ -162,6 +259,98 @@ func main() {
 	}
 }
 
+// staticDarkOcean is a dark ocean of dark pools that do not change between
+// epochs.
+type staticDarkOcean struct {
+	pools dark.Pools
+}
+
+func (ocean *staticDarkOcean) Pools() dark.Pools {
+	return ocean.pools
+}
+
+// A priceFeed signs the midpoints of the ETH/BTC market, and sends them to the
+// dark nodes.
+type priceFeed struct {
+	keyPair    identity.KeyPair
+	clientPool *rpc.ClientPool
+}
+
+// newPriceFeed returns a priceFeed that uses the key pair in the given file.
+// The dark nodes must be configured with the address of the key pair.
+func newPriceFeed(filename string) (*priceFeed, error) {
+	data, err := ioutil.ReadFile(filename)
+	if err != nil {
+		return nil, err
+	}
+	keyPair := identity.KeyPair{}
+	if err := json.Unmarshal(data, &keyPair); err != nil {
+		return nil, err
+	}
+	multi, err := identity.NewMultiAddressFromString("/ip4/0.0.0.0/tcp/80/republic/" + keyPair.Address().String())
+	if err != nil {
+		return nil, err
+	}
+	multiSignature, err := keyPair.Sign(multi)
+	if err != nil {
+		return nil, err
+	}
+	log.Println("Price Feed Address: ", keyPair.Address())
+	return &priceFeed{
+		keyPair:    keyPair,
+		clientPool: rpc.NewClientPool(multi, multiSignature),
+	}, nil
+}
+
+// updateMidpoint sends the midpoint of the best bid and the best ask to every
+// dark node in the dark pool. The dark nodes forward it to each other, so it
+// is enough for one of them to receive it.
+func (feed *priceFeed) updateMidpoint(pool *dark.Pool, bid, ask string) error {
+	bidPrice, err := fixed.EncodePrice(bid)
+	if err != nil {
+		return err
+	}
+	askPrice, err := fixed.EncodePrice(ask)
+	if err != nil {
+		return err
+	}
+	two := stackint.FromUint(2)
+	sum := bidPrice.Add(&askPrice)
+	midpoint := sum.Div(&two)
+	midpointUpdate := compute.NewMidpointUpdate(order.CurrencyCodeETH, order.CurrencyCodeBTC, &midpoint, time.Now())
+	if err := midpointUpdate.Sign(feed.keyPair); err != nil {
+		return err
+	}
+	log.Println("sending midpoint :", fixed.DecodePrice(&midpoint))
+	pool.CoForAll(func(node *dark.Node) {
+		multiAddress := node.MultiAddress()
+		if multiAddress == nil {
+			return
+		}
+		if err := feed.clientPool.UpdateMidpoint(*multiAddress, rpc.SerializeMidpointUpdate(midpointUpdate)); err != nil {
+			log.Printf("%sCouldn't send midpoint to %v%s\n", red, base58.Encode(node.ID), reset)
+		}
+	})
+	return nil
+}
+
+// newTrader returns a trader with a new identity.
+func newTrader(darkOcean trader.DarkOcean, registry *market.Registry, fieldName string) (*trader.Trader, error) {
+	keyPair, err := identity.NewKeyPair()
+	if err != nil {
+		return nil, err
+	}
+	multi, err := identity.NewMultiAddressFromString("/ip4/0.0.0.0/tcp/80/republic/" + keyPair.Address().String())
+	if err != nil {
+		return nil, err
+	}
+	log.Println("Trader Address: ", keyPair.Address())
+
+	options := trader.DefaultOptions(multi)
+	options.Field = fieldName
+	return trader.NewTrader(keyPair, darkOcean, registry, options)
+}
+
 func getNodesDetails() []string {
 	// AWS nodes
 	//return []string{
@@ -1,21 +1,50 @@
 package compute
 
 import (
+	"errors"
+	"time"
+
+	"github.com/ethereum/go-ethereum/crypto"
 	"github.com/republicprotocol/republic-go/stackint"
 
 	"github.com/republicprotocol/go-do"
 	"github.com/republicprotocol/republic-go/order"
+	"github.com/republicprotocol/republic-go/shamir"
 )
 
+// ErrOrderFragmentNotFound is returned when an order fragment is required but
+// it is not stored in the DeltaFragmentMatrix.
+var ErrOrderFragmentNotFound = errors.New("order fragment not found")
+
+// ErrOrderFragmentExpired is returned when an order fragment is inserted after
+// its order has expired.
+var ErrOrderFragmentExpired = errors.New("order fragment expired")
+
+// CompleteOrderRetention is how long the DeltaFragmentMatrix remembers that
+// an order is complete when it does not know the expiry of the order.
+const CompleteOrderRetention = 7 * 24 * time.Hour
+
+// ImmediateOrderWindow is how long an immediate-or-cancel, or fill-or-kill,
+// order remains open after the open time that the trader signed into its order
+// fragments. Every dark node closes the window at the same time, because the
+// open time is the same in every order fragment. This is long enough for the
+// order to be compared against all open orders, after which any volume that
+// has not been filled is cancelled.
+const ImmediateOrderWindow = 30 * time.Second
+
 // A DeltaBuilder collects delta fragments and attempts to reconstruct deltas
 type DeltaBuilder struct {
 	do.GuardedObject
 
 	k                      int64
+	faults                 int64
 	prime                  *stackint.Int1024
+	lagranges              *shamir.LagrangeCache
 	deltas                 map[string]*Delta
 	deltaFragments         map[string]*DeltaFragment
 	deltasToDeltaFragments map[string][]*DeltaFragment
+	faultyDeltaFragments   map[string][]*DeltaFragment
+	removedOrders          map[string]time.Time
 }
 
 // NewDeltaBuilder returns a new DeltaBuilder which reconstructs deltas when it
@@ -25,15 +54,20 @@ func NewDeltaBuilder(k int64, prime *stackint.Int1024) *DeltaBuilder {
 		GuardedObject:          do.NewGuardedObject(),
 		k:                      k,
 		prime:                  prime,
+		lagranges:              shamir.NewLagrangeCache(prime),
 		deltas:                 map[string]*Delta{},
 		deltaFragments:         map[string]*DeltaFragment{},
 		deltasToDeltaFragments: map[string][]*DeltaFragment{},
+		faultyDeltaFragments:   map[string][]*DeltaFragment{},
+		removedOrders:          map[string]time.Time{},
 	}
 }
 
 // InsertDeltaFragment inserts a delta fragment
-// If the builder has exactly k-1 other fragments with the same ID,
-// it will reconstruct the delta and return in
+// If at least k + faults fragments with the same ID are consistent, it will
+// reconstruct the delta, correcting faulty fragments, and return it. If there
+// are too many faulty fragments, it waits for more fragments, so that every
+// faulty fragment only delays the delta by one fragment.
 func (builder *DeltaBuilder) InsertDeltaFragment(deltaFragment *DeltaFragment) *Delta {
 	builder.Enter(nil)
 	defer builder.Exit()
@@ -49,6 +83,12 @@ func (builder *DeltaBuilder) insertDeltaFragment(deltaFragment *DeltaFragment) *
 	if builder.hasDeltaFragment(deltaFragment.ID) {
 		return nil // Only return new deltas
 	}
+	if builder.isRemovedOrder(deltaFragment.BuyOrderID) || builder.isRemovedOrder(deltaFragment.SellOrderID) {
+		return nil // Do not build deltas for removed orders
+	}
+	if hasShareKey(builder.deltasToDeltaFragments[string(deltaFragment.DeltaID)], deltaFragment.MatchShare.Key) {
+		return nil // Do not accept a second share with the same index from another dark node
+	}
 
 	// Add the delta fragment to the builder and attach it to the appropriate
 	// delta
@@ -60,12 +100,16 @@ func (builder *DeltaBuilder) insertDeltaFragment(deltaFragment *DeltaFragment) *
 		builder.deltasToDeltaFragments[string(deltaFragment.DeltaID)] = []*DeltaFragment{deltaFragment}
 	}
 
-	// Build the delta if possible and return it
-	deltaFragments := builder.deltasToDeltaFragments[string(deltaFragment.DeltaID)]
-	if int64(len(deltaFragments)) >= builder.k {
-		delta := NewDelta(deltaFragments, builder.prime)
-		if delta == nil {
-			return nil
+	// Build the delta if possible and return it. Only delta fragments that
+	// were computed from the same commitments are joined.
+	deltaFragments := withCommitmentsHash(builder.deltasToDeltaFragments[string(deltaFragment.DeltaID)], deltaFragment.CommitmentsHash)
+	if int64(len(deltaFragments)) >= builder.k+builder.faults {
+		delta, faultyDeltaFragments, err := NewDeltaRobust(deltaFragments, builder.k, builder.faults, builder.lagranges)
+		if err != nil {
+			return nil // Wait for more delta fragments
+		}
+		if len(faultyDeltaFragments) > 0 {
+			builder.faultyDeltaFragments[string(delta.ID)] = faultyDeltaFragments
 		}
 		builder.deltas[string(delta.ID)] = delta
 		return delta
@@ -86,6 +130,22 @@ func (builder *DeltaBuilder) hasDelta(deltaID DeltaID) bool {
 	return ok
 }
 
+// Delta returns the delta with the given ID, or nil if the builder has not
+// reconstructed it.
+func (builder *DeltaBuilder) Delta(deltaID DeltaID) *Delta {
+	builder.EnterReadOnly(nil)
+	defer builder.ExitReadOnly()
+	return builder.deltas[string(deltaID)]
+}
+
+// FaultyDeltaFragments returns the delta fragments that were found to be
+// faulty when the delta with the given ID was reconstructed.
+func (builder *DeltaBuilder) FaultyDeltaFragments(deltaID DeltaID) []*DeltaFragment {
+	builder.EnterReadOnly(nil)
+	defer builder.ExitReadOnly()
+	return builder.faultyDeltaFragments[string(deltaID)]
+}
+
 // HasDeltaFragment returns true if the fragment has already been added to the builder
 func (builder *DeltaBuilder) HasDeltaFragment(deltaFragmentID DeltaFragmentID) bool {
 	builder.EnterReadOnly(nil)
@@ -98,6 +158,96 @@ func (builder *DeltaBuilder) hasDeltaFragment(deltaFragmentID DeltaFragmentID) b
 	return ok
 }
 
+// RemoveOrder drops all delta fragments, and deltas, that were computed using
+// the given order. Delta fragments for this order that are inserted afterwards
+// will be ignored, until the order is forgotten by RemoveExpiredOrders after
+// its expiry. When the expiry of the order is not known, the zero time can be
+// given and the order is remembered for the CompleteOrderRetention.
+func (builder *DeltaBuilder) RemoveOrder(orderID order.ID, expiry time.Time) {
+	builder.Enter(nil)
+	defer builder.Exit()
+	builder.removeOrder(orderID, expiry)
+}
+
+func (builder *DeltaBuilder) removeOrder(orderID order.ID, expiry time.Time) {
+	if expiry.IsZero() {
+		expiry = time.Now().Add(CompleteOrderRetention)
+	}
+	if removedExpiry, ok := builder.removedOrders[string(orderID)]; !ok || expiry.After(removedExpiry) {
+		builder.removedOrders[string(orderID)] = expiry
+	}
+	for deltaID, delta := range builder.deltas {
+		if delta.BuyOrderID.Equal(orderID) || delta.SellOrderID.Equal(orderID) {
+			delete(builder.deltas, deltaID)
+		}
+	}
+	for deltaID, deltaFragments := range builder.deltasToDeltaFragments {
+		if len(deltaFragments) == 0 {
+			continue
+		}
+		if !deltaFragments[0].BuyOrderID.Equal(orderID) && !deltaFragments[0].SellOrderID.Equal(orderID) {
+			continue
+		}
+		for _, deltaFragment := range deltaFragments {
+			delete(builder.deltaFragments, string(deltaFragment.ID))
+		}
+		delete(builder.deltasToDeltaFragments, deltaID)
+		delete(builder.faultyDeltaFragments, deltaID)
+	}
+}
+
+// RemoveExpiredOrders forgets the orders that were removed from the builder,
+// and that have expired at the given time. No delta fragments are computed for
+// an order after it has expired, so they no longer need to be ignored.
+func (builder *DeltaBuilder) RemoveExpiredOrders(now time.Time) {
+	builder.Enter(nil)
+	defer builder.Exit()
+	builder.removeExpiredOrders(now)
+}
+
+func (builder *DeltaBuilder) removeExpiredOrders(now time.Time) {
+	for orderID, expiry := range builder.removedOrders {
+		if !now.Before(expiry) {
+			delete(builder.removedOrders, orderID)
+		}
+	}
+}
+
+func (builder *DeltaBuilder) isRemovedOrder(orderID order.ID) bool {
+	_, ok := builder.removedOrders[string(orderID)]
+	return ok
+}
+
+// Snapshot returns an unsigned Snapshot of all delta fragments held by the
+// builder. Delta fragments for deltas that have been reconstructed are marked
+// as matched, or mismatched, depending on the result of the reconstruction.
+func (builder *DeltaBuilder) Snapshot() *Snapshot {
+	builder.EnterReadOnly(nil)
+	defer builder.ExitReadOnly()
+	return builder.snapshot()
+}
+
+func (builder *DeltaBuilder) snapshot() *Snapshot {
+	snapshot := &Snapshot{
+		Pending:    []*DeltaFragment{},
+		Matched:    []*DeltaFragment{},
+		Mismatched: []*DeltaFragment{},
+	}
+	for deltaID, deltaFragments := range builder.deltasToDeltaFragments {
+		delta, ok := builder.deltas[deltaID]
+		if !ok {
+			snapshot.Pending = append(snapshot.Pending, deltaFragments...)
+			continue
+		}
+		if delta.IsMatch() {
+			snapshot.Matched = append(snapshot.Matched, deltaFragments...)
+		} else {
+			snapshot.Mismatched = append(snapshot.Mismatched, deltaFragments...)
+		}
+	}
+	return snapshot
+}
+
 // SetK updates the required number of fragments to reconstruct a delta
 func (builder *DeltaBuilder) SetK(k int64) {
 	builder.Enter(nil)
@@ -109,31 +259,65 @@ func (builder *DeltaBuilder) setK(k int64) {
 	builder.k = k
 }
 
+// SetFaults updates the number of faulty fragments that must be tolerated
+// when a delta is reconstructed. The builder waits for k + faults consistent
+// fragments before it reconstructs a delta.
+func (builder *DeltaBuilder) SetFaults(faults int64) {
+	builder.Enter(nil)
+	defer builder.Exit()
+	builder.setFaults(faults)
+}
+
+func (builder *DeltaBuilder) setFaults(faults int64) {
+	builder.faults = faults
+}
+
 // DeltaFragmentMatrix maps order IDs to its corresponding fragments
 type DeltaFragmentMatrix struct {
 	do.GuardedObject
 
-	prime                  *stackint.Int1024
-	buyOrderFragments      map[string]*order.Fragment
-	sellOrderFragments     map[string]*order.Fragment
-	buySellDeltaFragments  map[string]map[string]*DeltaFragment
-	completeOrderFragments map[string]bool
+	prime                      *stackint.Int1024
+	buyOrderFragments          map[string]*order.Fragment
+	sellOrderFragments         map[string]*order.Fragment
+	buySellDifferenceFragments map[string]map[string]*DifferenceFragment
+	completeOrderFragments     map[string]time.Time
+	rootOrderFragments         map[string]*order.Fragment
+	residualOrderIDs           map[string]order.ID
+	parentOrderIDs             map[string]order.ID
+	matches                    map[string]*matchedOrderFragments
+}
+
+// matchedOrderFragments are the order fragments that were removed from the
+// DeltaFragmentMatrix when they were matched. Either order fragment is nil if
+// the matrix did not hold it.
+type matchedOrderFragments struct {
+	buyOrderFragment  *order.Fragment
+	sellOrderFragment *order.Fragment
 }
 
 // NewDeltaFragmentMatrix returns a new DeltaFragmentMatrix
 func NewDeltaFragmentMatrix(prime *stackint.Int1024) *DeltaFragmentMatrix {
 	return &DeltaFragmentMatrix{
-		GuardedObject:          do.NewGuardedObject(),
-		prime:                  prime,
-		buyOrderFragments:      map[string]*order.Fragment{},
-		sellOrderFragments:     map[string]*order.Fragment{},
-		buySellDeltaFragments:  map[string]map[string]*DeltaFragment{},
-		completeOrderFragments: map[string]bool{},
+		GuardedObject:              do.NewGuardedObject(),
+		prime:                      prime,
+		buyOrderFragments:          map[string]*order.Fragment{},
+		sellOrderFragments:         map[string]*order.Fragment{},
+		buySellDifferenceFragments: map[string]map[string]*DifferenceFragment{},
+		completeOrderFragments:     map[string]time.Time{},
+		rootOrderFragments:         map[string]*order.Fragment{},
+		residualOrderIDs:           map[string]order.ID{},
+		parentOrderIDs:             map[string]order.ID{},
+		matches:                    map[string]*matchedOrderFragments{},
 	}
 }
 
-// InsertOrderFragment inserts buy and sell order fragments into the Fragment Matrix
-func (matrix *DeltaFragmentMatrix) InsertOrderFragment(orderFragment *order.Fragment) ([]*DeltaFragment, error) {
+// InsertOrderFragment inserts buy and sell order fragments into the Fragment
+// Matrix and returns the DifferenceFragments between the order fragment and
+// all order fragments of the opposite parity. An ErrOrderFragmentExpired is
+// returned if the order has expired. Immediate orders are only open for the
+// ImmediateOrderWindow after their open time, and immediate orders without an
+// open time are always expired.
+func (matrix *DeltaFragmentMatrix) InsertOrderFragment(orderFragment *order.Fragment) ([]*DifferenceFragment, error) {
 	matrix.Enter(nil)
 	defer matrix.Exit()
 	if orderFragment.OrderParity == order.ParityBuy {
@@ -142,86 +326,476 @@ func (matrix *DeltaFragmentMatrix) InsertOrderFragment(orderFragment *order.Frag
 	return matrix.insertSellOrderFragment(orderFragment)
 }
 
-func (matrix *DeltaFragmentMatrix) insertBuyOrderFragment(buyOrderFragment *order.Fragment) ([]*DeltaFragment, error) {
+func (matrix *DeltaFragmentMatrix) insertBuyOrderFragment(buyOrderFragment *order.Fragment) ([]*DifferenceFragment, error) {
 	if _, ok := matrix.buyOrderFragments[string(buyOrderFragment.OrderID)]; ok {
-		return []*DeltaFragment{}, nil
+		return []*DifferenceFragment{}, nil
 	}
-	if _, ok := matrix.completeOrderFragments[string(buyOrderFragment.OrderID)]; ok {
-		return []*DeltaFragment{}, nil
+	if matrix.isCompleteOrderFragment(buyOrderFragment) {
+		return []*DifferenceFragment{}, nil
+	}
+	if matrix.isExpired(buyOrderFragment, time.Now()) {
+		return nil, ErrOrderFragmentExpired
 	}
 
-	deltaFragments := make([]*DeltaFragment, 0, len(matrix.sellOrderFragments))
-	deltaFragmentsMap := map[string]*DeltaFragment{}
+	differenceFragments := make([]*DifferenceFragment, 0, len(matrix.sellOrderFragments))
+	differenceFragmentsMap := map[string]*DifferenceFragment{}
 	for i := range matrix.sellOrderFragments {
-		deltaFragment := NewDeltaFragment(buyOrderFragment, matrix.sellOrderFragments[i], matrix.prime)
-		if deltaFragment == nil {
+		differenceFragment := matrix.newDifferenceFragment(buyOrderFragment, matrix.sellOrderFragments[i])
+		if differenceFragment == nil {
 			continue
 		}
-		deltaFragments = append(deltaFragments, deltaFragment)
-		deltaFragmentsMap[string(matrix.sellOrderFragments[i].OrderID)] = deltaFragment
+		differenceFragments = append(differenceFragments, differenceFragment)
+		differenceFragmentsMap[string(matrix.sellOrderFragments[i].OrderID)] = differenceFragment
 	}
 
 	matrix.buyOrderFragments[string(buyOrderFragment.OrderID)] = buyOrderFragment
-	matrix.buySellDeltaFragments[string(buyOrderFragment.OrderID)] = deltaFragmentsMap
-	return deltaFragments, nil
+	matrix.buySellDifferenceFragments[string(buyOrderFragment.OrderID)] = differenceFragmentsMap
+	return differenceFragments, nil
 }
 
-func (matrix *DeltaFragmentMatrix) insertSellOrderFragment(sellOrderFragment *order.Fragment) ([]*DeltaFragment, error) {
+func (matrix *DeltaFragmentMatrix) insertSellOrderFragment(sellOrderFragment *order.Fragment) ([]*DifferenceFragment, error) {
 	if _, ok := matrix.sellOrderFragments[string(sellOrderFragment.OrderID)]; ok {
-		return []*DeltaFragment{}, nil
+		return []*DifferenceFragment{}, nil
+	}
+	if matrix.isCompleteOrderFragment(sellOrderFragment) {
+		return []*DifferenceFragment{}, nil
 	}
-	if _, ok := matrix.completeOrderFragments[string(sellOrderFragment.OrderID)]; ok {
-		return []*DeltaFragment{}, nil
+	if matrix.isExpired(sellOrderFragment, time.Now()) {
+		return nil, ErrOrderFragmentExpired
 	}
 
-	deltaFragments := make([]*DeltaFragment, 0, len(matrix.buyOrderFragments))
+	differenceFragments := make([]*DifferenceFragment, 0, len(matrix.buyOrderFragments))
 	for i := range matrix.buyOrderFragments {
-		deltaFragment := NewDeltaFragment(matrix.buyOrderFragments[i], sellOrderFragment, matrix.prime)
-		if deltaFragment == nil {
+		differenceFragment := matrix.newDifferenceFragment(matrix.buyOrderFragments[i], sellOrderFragment)
+		if differenceFragment == nil {
 			continue
 		}
-		if _, ok := matrix.buySellDeltaFragments[string(matrix.buyOrderFragments[i].OrderID)]; ok {
-			deltaFragments = append(deltaFragments, deltaFragment)
-			matrix.buySellDeltaFragments[string(matrix.buyOrderFragments[i].OrderID)][string(sellOrderFragment.OrderID)] = deltaFragment
+		if _, ok := matrix.buySellDifferenceFragments[string(matrix.buyOrderFragments[i].OrderID)]; ok {
+			differenceFragments = append(differenceFragments, differenceFragment)
+			matrix.buySellDifferenceFragments[string(matrix.buyOrderFragments[i].OrderID)][string(sellOrderFragment.OrderID)] = differenceFragment
 		}
 	}
 
 	matrix.sellOrderFragments[string(sellOrderFragment.OrderID)] = sellOrderFragment
-	return deltaFragments, nil
+	return differenceFragments, nil
+}
+
+// InsertResidualOrderFragment inserts the order fragment of a residual order,
+// computed from the parent order fragment after the parent order was
+// partially filled, and returns the DifferenceFragments in the same way as
+// InsertOrderFragment. The matrix remembers the order fragment that was
+// opened by the trader, so that the trader can still cancel the residual
+// order. The residual order of an immediate order keeps the open time of the
+// parent order, so it is only open until the window of the parent order
+// closes.
+func (matrix *DeltaFragmentMatrix) InsertResidualOrderFragment(parentOrderFragment, residualOrderFragment *order.Fragment) ([]*DifferenceFragment, error) {
+	matrix.Enter(nil)
+	defer matrix.Exit()
+
+	if matrix.isExpired(residualOrderFragment, time.Now()) {
+		return nil, ErrOrderFragmentExpired
+	}
+
+	rootOrderFragment, ok := matrix.rootOrderFragments[string(parentOrderFragment.OrderID)]
+	if !ok {
+		rootOrderFragment = parentOrderFragment
+	}
+	matrix.rootOrderFragments[string(residualOrderFragment.OrderID)] = rootOrderFragment
+	matrix.residualOrderIDs[string(rootOrderFragment.OrderID)] = residualOrderFragment.OrderID
+	matrix.parentOrderIDs[string(residualOrderFragment.OrderID)] = parentOrderFragment.OrderID
+
+	if residualOrderFragment.OrderParity == order.ParityBuy {
+		return matrix.insertBuyOrderFragment(residualOrderFragment)
+	}
+	return matrix.insertSellOrderFragment(residualOrderFragment)
+}
+
+// newDifferenceFragment returns the DifferenceFragment of two order fragments,
+// bound to the hash of the Commitments that the order fragments were verified
+// against. It returns nil if the order fragments are not compatible.
+func (matrix *DeltaFragmentMatrix) newDifferenceFragment(buyOrderFragment, sellOrderFragment *order.Fragment) *DifferenceFragment {
+	differenceFragment := NewDifferenceFragment(buyOrderFragment, sellOrderFragment, matrix.prime)
+	if differenceFragment == nil {
+		return nil
+	}
+	differenceFragment.CommitmentsHash = matrix.commitmentsHash(buyOrderFragment, sellOrderFragment)
+	return differenceFragment
+}
+
+// CommitmentsHash returns the hash of the Commitments that the buy order
+// fragment, and the sell order fragment, were verified against when they
+// were opened. Residual orders use the Commitments of the order fragment that
+// was opened by the trader. Dark nodes that verified their order fragments
+// against the same Commitments return the same hash, so delta fragments are
+// only reconstructed from shares that lie on the same polynomials.
+func (matrix *DeltaFragmentMatrix) CommitmentsHash(buyOrderFragment, sellOrderFragment *order.Fragment) []byte {
+	matrix.EnterReadOnly(nil)
+	defer matrix.ExitReadOnly()
+	return matrix.commitmentsHash(buyOrderFragment, sellOrderFragment)
+}
+
+func (matrix *DeltaFragmentMatrix) commitmentsHash(buyOrderFragment, sellOrderFragment *order.Fragment) []byte {
+	hashes := make([][]byte, 0, 2)
+	for _, orderFragment := range []*order.Fragment{buyOrderFragment, sellOrderFragment} {
+		if rootOrderFragment, ok := matrix.rootOrderFragments[string(orderFragment.OrderID)]; ok {
+			orderFragment = rootOrderFragment
+		}
+		var hash []byte
+		if orderFragment.Commitments != nil {
+			// Order fragments with malformed commitments are never opened
+			hash, _ = orderFragment.Commitments.Hash()
+		}
+		hashes = append(hashes, hash)
+	}
+	return crypto.Keccak256(hashes...)
+}
+
+// OrderFragment returns the order fragment of an open order, or nil if the
+// matrix does not hold one.
+func (matrix *DeltaFragmentMatrix) OrderFragment(orderID order.ID) *order.Fragment {
+	matrix.EnterReadOnly(nil)
+	defer matrix.ExitReadOnly()
+	if orderFragment, ok := matrix.buyOrderFragments[string(orderID)]; ok {
+		return orderFragment
+	}
+	return matrix.sellOrderFragments[string(orderID)]
+}
+
+// RootOrderFragment returns the order fragment that was opened by the trader
+// for a residual order, or nil if the order is not a residual order.
+func (matrix *DeltaFragmentMatrix) RootOrderFragment(orderID order.ID) *order.Fragment {
+	matrix.EnterReadOnly(nil)
+	defer matrix.ExitReadOnly()
+	return matrix.rootOrderFragments[string(orderID)]
+}
+
+// ResidualOrderID returns the ID of the latest residual order of an order
+// that was opened by a trader. The order ID is returned unchanged if the order
+// has not been partially filled.
+func (matrix *DeltaFragmentMatrix) ResidualOrderID(orderID order.ID) order.ID {
+	matrix.EnterReadOnly(nil)
+	defer matrix.ExitReadOnly()
+	return matrix.residualOrderID(orderID)
+}
+
+func (matrix *DeltaFragmentMatrix) residualOrderID(orderID order.ID) order.ID {
+	if residualOrderID, ok := matrix.residualOrderIDs[string(orderID)]; ok {
+		return residualOrderID
+	}
+	return orderID
+}
+
+// CancelOrderFragment removes the fragment of a cancelled order from the
+// matrix and records it as being complete. The cancellation must be signed by
+// the same trader that signed the order fragment, otherwise an error is
+// returned and the matrix is not modified. If the order has been partially
+// filled, its latest residual order is cancelled.
+func (matrix *DeltaFragmentMatrix) CancelOrderFragment(cancellation *order.Cancellation) error {
+	matrix.Enter(nil)
+	defer matrix.Exit()
+	return matrix.cancelOrderFragment(cancellation)
+}
+
+func (matrix *DeltaFragmentMatrix) cancelOrderFragment(cancellation *order.Cancellation) error {
+	orderID := matrix.residualOrderID(cancellation.OrderID)
+	orderFragment, ok := matrix.buyOrderFragments[string(orderID)]
+	if !ok {
+		if orderFragment, ok = matrix.sellOrderFragments[string(orderID)]; !ok {
+			return ErrOrderFragmentNotFound
+		}
+	}
+	if rootOrderFragment, ok := matrix.rootOrderFragments[string(orderID)]; ok {
+		orderFragment = rootOrderFragment
+	}
+
+	// The trader is recovered from the signature on the order fragment that
+	// they opened
+	trader, err := orderFragment.RecoverSigner()
+	if err != nil {
+		return err
+	}
+	if err := cancellation.VerifySignature(trader); err != nil {
+		return err
+	}
+
+	if err := matrix.removeBuyOrderFragment(orderID); err != nil {
+		return err
+	}
+	return matrix.removeSellOrderFragment(orderID)
+}
+
+// HasCompleteOrderFragment returns true if the order fragment has been
+// removed from the matrix after being matched or cancelled.
+func (matrix *DeltaFragmentMatrix) HasCompleteOrderFragment(orderID order.ID) bool {
+	matrix.EnterReadOnly(nil)
+	defer matrix.ExitReadOnly()
+	return matrix.hasCompleteOrderFragment(orderID)
+}
+
+func (matrix *DeltaFragmentMatrix) hasCompleteOrderFragment(orderID order.ID) bool {
+	_, ok := matrix.completeOrderFragments[string(orderID)]
+	return ok
+}
+
+// isCompleteOrderFragment returns true if the order of an order fragment is
+// complete. The order is remembered as being complete until the order
+// fragment expires.
+func (matrix *DeltaFragmentMatrix) isCompleteOrderFragment(orderFragment *order.Fragment) bool {
+	expiry, ok := matrix.completeOrderFragments[string(orderFragment.OrderID)]
+	if !ok {
+		return false
+	}
+	if orderFragment.OrderExpiry.After(expiry) {
+		matrix.completeOrderFragments[string(orderFragment.OrderID)] = orderFragment.OrderExpiry
+	}
+	return true
 }
 
 // RemoveOrderFragment removes buy and sell fragments from the matrix
-// and records them as being complete
+// and records them as being complete. The order is recorded as being complete
+// even if the matrix does not hold a fragment for it, so that a match learnt
+// from another dark node prevents the order from being matched again. When
+// the matrix does not hold a fragment for the order, the order is remembered
+// for the CompleteOrderRetention.
 func (matrix *DeltaFragmentMatrix) RemoveOrderFragment(orderID order.ID) error {
 	matrix.Enter(nil)
 	defer matrix.Exit()
 	if err := matrix.removeBuyOrderFragment(orderID); err != nil {
 		return err
 	}
-	return matrix.removeSellOrderFragment(orderID)
+	if err := matrix.removeSellOrderFragment(orderID); err != nil {
+		return err
+	}
+	if _, ok := matrix.completeOrderFragments[string(orderID)]; !ok {
+		matrix.completeOrderFragments[string(orderID)] = time.Now().Add(CompleteOrderRetention)
+	}
+	return nil
+}
+
+// RemoveMatch removes the buy and sell fragments of a match from the matrix,
+// in the same way as RemoveOrderFragment, and remembers them so that the
+// match can be undone by RestoreMatch.
+func (matrix *DeltaFragmentMatrix) RemoveMatch(deltaID DeltaID, buyOrderID, sellOrderID order.ID) error {
+	matrix.Enter(nil)
+	defer matrix.Exit()
+	match := &matchedOrderFragments{
+		buyOrderFragment:  matrix.buyOrderFragments[string(buyOrderID)],
+		sellOrderFragment: matrix.sellOrderFragments[string(sellOrderID)],
+	}
+	for _, orderID := range []order.ID{buyOrderID, sellOrderID} {
+		if err := matrix.removeBuyOrderFragment(orderID); err != nil {
+			return err
+		}
+		if err := matrix.removeSellOrderFragment(orderID); err != nil {
+			return err
+		}
+		if _, ok := matrix.completeOrderFragments[string(orderID)]; !ok {
+			matrix.completeOrderFragments[string(orderID)] = time.Now().Add(CompleteOrderRetention)
+		}
+	}
+	if match.buyOrderFragment != nil || match.sellOrderFragment != nil {
+		matrix.matches[string(deltaID)] = match
+	}
+	return nil
+}
+
+// RestoreMatch undoes a match that was removed by RemoveMatch, because the
+// match could not be settled. The order fragments of the match are reopened,
+// and the residual order that was created when the match was filled is
+// removed, so that the remaining volume is not open twice. An order fragment
+// is not reopened if it has expired, or if its residual order has been
+// matched again. The reopened order fragments, the IDs of the removed
+// residual orders, and the DifferenceFragments of the reopened order
+// fragments are returned. The orders of the match are not compared with each
+// other again.
+func (matrix *DeltaFragmentMatrix) RestoreMatch(deltaID DeltaID) ([]*order.Fragment, []order.ID, []*DifferenceFragment) {
+	matrix.Enter(nil)
+	defer matrix.Exit()
+
+	orderFragments := []*order.Fragment{}
+	residualOrderIDs := []order.ID{}
+	differenceFragments := []*DifferenceFragment{}
+	match, ok := matrix.matches[string(deltaID)]
+	if !ok {
+		return orderFragments, residualOrderIDs, differenceFragments
+	}
+	delete(matrix.matches, string(deltaID))
+
+	for _, orderFragment := range []*order.Fragment{match.buyOrderFragment, match.sellOrderFragment} {
+		if orderFragment == nil {
+			continue
+		}
+		residualOrderID, ok := matrix.restoreResidualOrder(orderFragment)
+		if !ok {
+			continue
+		}
+		if residualOrderID != nil {
+			residualOrderIDs = append(residualOrderIDs, residualOrderID)
+		}
+		delete(matrix.completeOrderFragments, string(orderFragment.OrderID))
+		var orderDifferenceFragments []*DifferenceFragment
+		var err error
+		if orderFragment.OrderParity == order.ParityBuy {
+			orderDifferenceFragments, err = matrix.insertBuyOrderFragment(orderFragment)
+		} else {
+			orderDifferenceFragments, err = matrix.insertSellOrderFragment(orderFragment)
+		}
+		if err != nil {
+			matrix.completeOrderFragments[string(orderFragment.OrderID)] = orderFragment.OrderExpiry
+			continue
+		}
+		orderFragments = append(orderFragments, orderFragment)
+		differenceFragments = append(differenceFragments, orderDifferenceFragments...)
+	}
+
+	// The orders of the match have already been compared
+	if match.buyOrderFragment != nil && match.sellOrderFragment != nil {
+		buyOrderID, sellOrderID := match.buyOrderFragment.OrderID, match.sellOrderFragment.OrderID
+		if buyDifferenceFragments, ok := matrix.buySellDifferenceFragments[string(buyOrderID)]; ok {
+			delete(buyDifferenceFragments, string(sellOrderID))
+		}
+		for i := 0; i < len(differenceFragments); i++ {
+			if differenceFragments[i].BuyOrderID.Equal(buyOrderID) && differenceFragments[i].SellOrderID.Equal(sellOrderID) {
+				differenceFragments = append(differenceFragments[:i], differenceFragments[i+1:]...)
+				i--
+			}
+		}
+	}
+	return orderFragments, residualOrderIDs, differenceFragments
+}
+
+// restoreResidualOrder removes the open residual order that was created from
+// an order fragment, and makes the order fragment the latest residual order
+// of its root order again. It returns the ID of the removed residual order,
+// which is nil if there was no residual order. False is returned if the order
+// fragment cannot be reopened, because its residual order has been matched
+// again.
+func (matrix *DeltaFragmentMatrix) restoreResidualOrder(orderFragment *order.Fragment) (order.ID, bool) {
+	rootOrderID := orderFragment.OrderID
+	if rootOrderFragment, ok := matrix.rootOrderFragments[string(orderFragment.OrderID)]; ok {
+		rootOrderID = rootOrderFragment.OrderID
+	}
+	residualOrderID, ok := matrix.residualOrderIDs[string(rootOrderID)]
+	if !ok || residualOrderID.Equal(orderFragment.OrderID) {
+		return nil, true
+	}
+	if !matrix.parentOrderIDs[string(residualOrderID)].Equal(orderFragment.OrderID) {
+		return nil, false
+	}
+	_, isBuy := matrix.buyOrderFragments[string(residualOrderID)]
+	_, isSell := matrix.sellOrderFragments[string(residualOrderID)]
+	if !isBuy && !isSell {
+		return nil, false
+	}
+	matrix.removeBuyOrderFragment(residualOrderID)
+	matrix.removeSellOrderFragment(residualOrderID)
+	if rootOrderID.Equal(orderFragment.OrderID) {
+		delete(matrix.residualOrderIDs, string(rootOrderID))
+	} else {
+		matrix.residualOrderIDs[string(rootOrderID)] = orderFragment.OrderID
+	}
+	return residualOrderID, true
+}
+
+// RemoveExpiredOrderFragments removes all order fragments for orders that
+// have expired at the given time, including immediate orders with a closed
+// window, and returns the IDs of these orders. Complete orders, residual
+// orders, and matches, that have expired are forgotten, because their order
+// fragments can no longer be inserted.
+func (matrix *DeltaFragmentMatrix) RemoveExpiredOrderFragments(now time.Time) []order.ID {
+	matrix.Enter(nil)
+	defer matrix.Exit()
+	return matrix.removeExpiredOrderFragments(now)
+}
+
+func (matrix *DeltaFragmentMatrix) removeExpiredOrderFragments(now time.Time) []order.ID {
+	expiredOrderIDs := []order.ID{}
+	for _, buyOrderFragment := range matrix.buyOrderFragments {
+		if matrix.isExpired(buyOrderFragment, now) {
+			matrix.removeBuyOrderFragment(buyOrderFragment.OrderID)
+			expiredOrderIDs = append(expiredOrderIDs, buyOrderFragment.OrderID)
+		}
+	}
+	for _, sellOrderFragment := range matrix.sellOrderFragments {
+		if matrix.isExpired(sellOrderFragment, now) {
+			matrix.removeSellOrderFragment(sellOrderFragment.OrderID)
+			expiredOrderIDs = append(expiredOrderIDs, sellOrderFragment.OrderID)
+		}
+	}
+	for orderID, expiry := range matrix.completeOrderFragments {
+		if !now.Before(expiry) {
+			delete(matrix.completeOrderFragments, orderID)
+		}
+	}
+	for orderID, rootOrderFragment := range matrix.rootOrderFragments {
+		if rootOrderFragment.IsExpired(now) {
+			delete(matrix.rootOrderFragments, orderID)
+			delete(matrix.residualOrderIDs, string(rootOrderFragment.OrderID))
+			delete(matrix.parentOrderIDs, orderID)
+		}
+	}
+	for deltaID, match := range matrix.matches {
+		if (match.buyOrderFragment == nil || match.buyOrderFragment.IsExpired(now)) && (match.sellOrderFragment == nil || match.sellOrderFragment.IsExpired(now)) {
+			delete(matrix.matches, deltaID)
+		}
+	}
+	return expiredOrderIDs
+}
+
+// ExpiredOrderFragments returns the order fragments for orders that have
+// expired at the given time, including immediate orders with a closed window,
+// without removing them. These are the order fragments that are removed by
+// RemoveExpiredOrderFragments at the same time.
+func (matrix *DeltaFragmentMatrix) ExpiredOrderFragments(now time.Time) []*order.Fragment {
+	matrix.EnterReadOnly(nil)
+	defer matrix.ExitReadOnly()
+	expiredOrderFragments := []*order.Fragment{}
+	for _, buyOrderFragment := range matrix.buyOrderFragments {
+		if matrix.isExpired(buyOrderFragment, now) {
+			expiredOrderFragments = append(expiredOrderFragments, buyOrderFragment)
+		}
+	}
+	for _, sellOrderFragment := range matrix.sellOrderFragments {
+		if matrix.isExpired(sellOrderFragment, now) {
+			expiredOrderFragments = append(expiredOrderFragments, sellOrderFragment)
+		}
+	}
+	return expiredOrderFragments
+}
+
+// isExpired returns true if the order of an order fragment has expired at the
+// given time, or if it is an immediate order and its window has closed.
+func (matrix *DeltaFragmentMatrix) isExpired(orderFragment *order.Fragment, now time.Time) bool {
+	if orderFragment.IsExpired(now) {
+		return true
+	}
+	return orderFragment.OrderTimeInForce.IsImmediate() && !now.Before(orderFragment.OrderOpenTime.Add(ImmediateOrderWindow))
 }
 
 func (matrix *DeltaFragmentMatrix) removeBuyOrderFragment(buyOrderID order.ID) error {
-	if _, ok := matrix.buyOrderFragments[string(buyOrderID)]; !ok {
+	buyOrderFragment, ok := matrix.buyOrderFragments[string(buyOrderID)]
+	if !ok {
 		return nil
 	}
 
 	delete(matrix.buyOrderFragments, string(buyOrderID))
-	delete(matrix.buySellDeltaFragments, string(buyOrderID))
+	delete(matrix.buySellDifferenceFragments, string(buyOrderID))
 
-	matrix.completeOrderFragments[string(buyOrderID)] = true
+	matrix.completeOrderFragments[string(buyOrderID)] = buyOrderFragment.OrderExpiry
 	return nil
 }
 
 func (matrix *DeltaFragmentMatrix) removeSellOrderFragment(sellOrderID order.ID) error {
-	if _, ok := matrix.sellOrderFragments[string(sellOrderID)]; !ok {
+	sellOrderFragment, ok := matrix.sellOrderFragments[string(sellOrderID)]
+	if !ok {
 		return nil
 	}
 
-	for i := range matrix.buySellDeltaFragments {
-		delete(matrix.buySellDeltaFragments[i], string(sellOrderID))
+	delete(matrix.sellOrderFragments, string(sellOrderID))
+	for i := range matrix.buySellDifferenceFragments {
+		delete(matrix.buySellDifferenceFragments[i], string(sellOrderID))
 	}
 
-	matrix.completeOrderFragments[string(sellOrderID)] = true
+	matrix.completeOrderFragments[string(sellOrderID)] = sellOrderFragment.OrderExpiry
 	return nil
 }
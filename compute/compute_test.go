@@ -1 +1,386 @@
 package compute_test
+
+import (
+	"time"
+
+	"github.com/ethereum/go-ethereum/crypto"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	. "github.com/republicprotocol/republic-go/compute"
+	"github.com/republicprotocol/republic-go/identity"
+	"github.com/republicprotocol/republic-go/order"
+	"github.com/republicprotocol/republic-go/stackint"
+)
+
+var _ = Describe("Delta fragment matrix", func() {
+
+	n := int64(8)
+	k := int64(6)
+	primeVal, _ := stackint.FromString("179769313486231590772930519078902473361797697894230657273430081157732675805500963132708477322407536021120113879871393357658789768814416622492847430639474124377767893424865485276302219601246094119453082952085005768838150682342462881473913110540827237163350510684586298239947245938479716304835356329624224137111")
+	prime := &primeVal
+
+	Context("when cancelling orders", func() {
+
+		var trader, seller identity.KeyPair
+		var buyOrder, sellOrder *order.Order
+		var buyOrderFragments, sellOrderFragments []*order.Fragment
+
+		BeforeEach(func() {
+			var err error
+			trader, err = identity.NewKeyPair()
+			Ω(err).ShouldNot(HaveOccurred())
+			seller, err = identity.NewKeyPair()
+			Ω(err).ShouldNot(HaveOccurred())
+
+			buyOrder = order.NewOrder(order.TypeLimit, order.ParityBuy, time.Now().Add(time.Hour), order.CurrencyCodeBTC, order.CurrencyCodeETH, heapInt(10), heapInt(1000), heapInt(100), heapInt(0))
+			buyOrderFragments, err = buyOrder.Split(n, k, prime)
+			Ω(err).ShouldNot(HaveOccurred())
+			Ω(order.SignFragments(trader, buyOrderFragments)).ShouldNot(HaveOccurred())
+
+			sellOrder = order.NewOrder(order.TypeLimit, order.ParitySell, time.Now().Add(time.Hour), order.CurrencyCodeBTC, order.CurrencyCodeETH, heapInt(10), heapInt(1000), heapInt(100), heapInt(0))
+			sellOrderFragments, err = sellOrder.Split(n, k, prime)
+			Ω(err).ShouldNot(HaveOccurred())
+			Ω(order.SignFragments(seller, sellOrderFragments)).ShouldNot(HaveOccurred())
+		})
+
+		It("should remove the order fragment when the trader signed the cancellation", func() {
+			matrix := NewDeltaFragmentMatrix(prime)
+			_, err := matrix.InsertOrderFragment(buyOrderFragments[0])
+			Ω(err).ShouldNot(HaveOccurred())
+
+			cancellation := order.NewCancellation(buyOrder.ID)
+			Ω(cancellation.Sign(trader)).ShouldNot(HaveOccurred())
+			Ω(matrix.CancelOrderFragment(cancellation)).ShouldNot(HaveOccurred())
+			Ω(matrix.HasCompleteOrderFragment(buyOrder.ID)).Should(BeTrue())
+
+			// A cancelled order must not produce any more delta fragments
+			deltaFragments, err := matrix.InsertOrderFragment(sellOrderFragments[0])
+			Ω(err).ShouldNot(HaveOccurred())
+			Ω(deltaFragments).Should(BeEmpty())
+		})
+
+		It("should remove sell order fragments", func() {
+			matrix := NewDeltaFragmentMatrix(prime)
+			_, err := matrix.InsertOrderFragment(sellOrderFragments[0])
+			Ω(err).ShouldNot(HaveOccurred())
+
+			cancellation := order.NewCancellation(sellOrder.ID)
+			Ω(cancellation.Sign(seller)).ShouldNot(HaveOccurred())
+			Ω(matrix.CancelOrderFragment(cancellation)).ShouldNot(HaveOccurred())
+
+			deltaFragments, err := matrix.InsertOrderFragment(buyOrderFragments[0])
+			Ω(err).ShouldNot(HaveOccurred())
+			Ω(deltaFragments).Should(BeEmpty())
+		})
+
+		It("should return an error when the cancellation was not signed by the trader", func() {
+			matrix := NewDeltaFragmentMatrix(prime)
+			_, err := matrix.InsertOrderFragment(buyOrderFragments[0])
+			Ω(err).ShouldNot(HaveOccurred())
+
+			other, err := identity.NewKeyPair()
+			Ω(err).ShouldNot(HaveOccurred())
+			cancellation := order.NewCancellation(buyOrder.ID)
+			Ω(cancellation.Sign(other)).ShouldNot(HaveOccurred())
+			Ω(matrix.CancelOrderFragment(cancellation)).Should(Equal(identity.ErrInvalidSignature))
+			Ω(matrix.HasCompleteOrderFragment(buyOrder.ID)).Should(BeFalse())
+		})
+
+		It("should return an error when the order is unknown", func() {
+			matrix := NewDeltaFragmentMatrix(prime)
+			cancellation := order.NewCancellation(buyOrder.ID)
+			Ω(cancellation.Sign(trader)).ShouldNot(HaveOccurred())
+			Ω(matrix.CancelOrderFragment(cancellation)).Should(Equal(ErrOrderFragmentNotFound))
+		})
+
+		It("should cancel the residual order of a partially filled order", func() {
+			matrix := NewDeltaFragmentMatrix(prime)
+			_, err := matrix.InsertOrderFragment(buyOrderFragments[0])
+			Ω(err).ShouldNot(HaveOccurred())
+			Ω(matrix.OrderFragment(buyOrder.ID)).Should(Equal(buyOrderFragments[0]))
+			Ω(matrix.RemoveOrderFragment(buyOrder.ID)).ShouldNot(HaveOccurred())
+			Ω(matrix.OrderFragment(buyOrder.ID)).Should(BeNil())
+
+			residual := buyOrderFragments[0].Residual(sellOrder.ID, sellOrderFragments[0].MaxVolumeShare, prime)
+			_, err = matrix.InsertResidualOrderFragment(buyOrderFragments[0], residual)
+			Ω(err).ShouldNot(HaveOccurred())
+			Ω(matrix.ResidualOrderID(buyOrder.ID).Equal(residual.OrderID)).Should(BeTrue())
+			Ω(matrix.RootOrderFragment(residual.OrderID)).Should(Equal(buyOrderFragments[0]))
+
+			// The residual order is compared with new orders
+			otherSellOrder := order.NewOrder(order.TypeLimit, order.ParitySell, time.Now().Add(time.Hour), order.CurrencyCodeBTC, order.CurrencyCodeETH, heapInt(10), heapInt(1000), heapInt(100), heapInt(1))
+			otherSellOrderFragments, err := otherSellOrder.Split(n, k, prime)
+			Ω(err).ShouldNot(HaveOccurred())
+			signFragments(otherSellOrderFragments)
+			differenceFragments, err := matrix.InsertOrderFragment(otherSellOrderFragments[0])
+			Ω(err).ShouldNot(HaveOccurred())
+			Ω(differenceFragments).Should(HaveLen(1))
+			Ω(differenceFragments[0].BuyOrderID.Equal(residual.OrderID)).Should(BeTrue())
+
+			// The trader cancels the residual order using the original order
+			cancellation := order.NewCancellation(buyOrder.ID)
+			Ω(cancellation.Sign(trader)).ShouldNot(HaveOccurred())
+			Ω(matrix.CancelOrderFragment(cancellation)).ShouldNot(HaveOccurred())
+			Ω(matrix.HasCompleteOrderFragment(residual.OrderID)).Should(BeTrue())
+			Ω(matrix.OrderFragment(residual.OrderID)).Should(BeNil())
+		})
+
+		It("should drop delta fragments from the delta builder", func() {
+			deltaFragments := computeDeltaFragments(buyOrderFragments, sellOrderFragments, n, k, prime, true)
+			Ω(deltaFragments).ShouldNot(BeNil())
+
+			builder := NewDeltaBuilder(k, prime)
+			for i := int64(0); i < k-1; i++ {
+				Ω(builder.InsertDeltaFragment(deltaFragments[i])).Should(BeNil())
+			}
+			builder.RemoveOrder(buyOrder.ID, buyOrder.Expiry)
+			for i := int64(0); i < k-1; i++ {
+				Ω(builder.HasDeltaFragment(deltaFragments[i].ID)).Should(BeFalse())
+			}
+			for i := k - 1; i < n; i++ {
+				Ω(builder.InsertDeltaFragment(deltaFragments[i])).Should(BeNil())
+			}
+		})
+
+		It("should drop deltas from the delta builder", func() {
+			deltaFragments := computeDeltaFragments(buyOrderFragments, sellOrderFragments, n, k, prime, true)
+			Ω(deltaFragments).ShouldNot(BeNil())
+
+			builder := NewDeltaBuilder(k, prime)
+			var delta *Delta
+			for i := int64(0); i < n && delta == nil; i++ {
+				delta = builder.InsertDeltaFragment(deltaFragments[i])
+			}
+			Ω(delta).ShouldNot(BeNil())
+			builder.RemoveOrder(sellOrder.ID, sellOrder.Expiry)
+			Ω(builder.HasDelta(delta.ID)).Should(BeFalse())
+			Ω(builder.Delta(delta.ID)).Should(BeNil())
+		})
+
+		It("should forget removed orders after they expire", func() {
+			deltaFragments := computeDeltaFragments(buyOrderFragments, sellOrderFragments, n, k, prime, true)
+			Ω(deltaFragments).ShouldNot(BeNil())
+
+			builder := NewDeltaBuilder(k, prime)
+			builder.RemoveOrder(buyOrder.ID, buyOrder.Expiry)
+			builder.RemoveExpiredOrders(buyOrder.Expiry.Add(-time.Second))
+			Ω(builder.InsertDeltaFragment(deltaFragments[0])).Should(BeNil())
+			Ω(builder.HasDeltaFragment(deltaFragments[0].ID)).Should(BeFalse())
+
+			builder.RemoveExpiredOrders(buyOrder.Expiry)
+			Ω(builder.InsertDeltaFragment(deltaFragments[0])).Should(BeNil())
+			Ω(builder.HasDeltaFragment(deltaFragments[0].ID)).Should(BeTrue())
+		})
+	})
+
+	Context("when orders expire", func() {
+
+		newOrderFragment := func(parity order.Parity, expiry time.Time) *order.Fragment {
+			orderFragments, err := order.NewOrder(order.TypeLimit, parity, expiry, order.CurrencyCodeBTC, order.CurrencyCodeETH, heapInt(10), heapInt(1000), heapInt(100), heapInt(0)).Split(n, k, prime)
+			Ω(err).ShouldNot(HaveOccurred())
+			signFragments(orderFragments)
+			return orderFragments[0]
+		}
+
+		It("should return an error when inserting expired order fragments", func() {
+			matrix := NewDeltaFragmentMatrix(prime)
+			_, err := matrix.InsertOrderFragment(newOrderFragment(order.ParityBuy, time.Now().Add(-time.Minute)))
+			Ω(err).Should(Equal(ErrOrderFragmentExpired))
+		})
+
+		It("should remove expired order fragments", func() {
+			now := time.Now()
+			buyOrderFragment := newOrderFragment(order.ParityBuy, now.Add(time.Minute))
+			sellOrderFragment := newOrderFragment(order.ParitySell, now.Add(time.Hour))
+
+			matrix := NewDeltaFragmentMatrix(prime)
+			_, err := matrix.InsertOrderFragment(buyOrderFragment)
+			Ω(err).ShouldNot(HaveOccurred())
+			_, err = matrix.InsertOrderFragment(sellOrderFragment)
+			Ω(err).ShouldNot(HaveOccurred())
+
+			Ω(matrix.ExpiredOrderFragments(now)).Should(BeEmpty())
+			Ω(matrix.RemoveExpiredOrderFragments(now)).Should(BeEmpty())
+			Ω(matrix.ExpiredOrderFragments(now.Add(2 * time.Minute))).Should(Equal([]*order.Fragment{buyOrderFragment}))
+			Ω(matrix.RemoveExpiredOrderFragments(now.Add(2 * time.Minute))).Should(Equal([]order.ID{buyOrderFragment.OrderID}))
+			Ω(matrix.ExpiredOrderFragments(now.Add(2 * time.Minute))).Should(BeEmpty())
+
+			// The expired order can be forgotten immediately, because it can
+			// no longer be inserted
+			Ω(matrix.HasCompleteOrderFragment(buyOrderFragment.OrderID)).Should(BeFalse())
+
+			// The sell order must not be compared with the expired order
+			differenceFragments, err := matrix.InsertOrderFragment(newOrderFragment(order.ParityBuy, now.Add(time.Hour)))
+			Ω(err).ShouldNot(HaveOccurred())
+			Ω(differenceFragments).Should(HaveLen(1))
+			Ω(differenceFragments[0].SellOrderID).Should(Equal(sellOrderFragment.OrderID))
+		})
+
+		It("should forget complete orders after they expire", func() {
+			now := time.Now()
+			buyOrderFragment := newOrderFragment(order.ParityBuy, now.Add(time.Minute))
+
+			matrix := NewDeltaFragmentMatrix(prime)
+			_, err := matrix.InsertOrderFragment(buyOrderFragment)
+			Ω(err).ShouldNot(HaveOccurred())
+			Ω(matrix.RemoveOrderFragment(buyOrderFragment.OrderID)).ShouldNot(HaveOccurred())
+
+			Ω(matrix.RemoveExpiredOrderFragments(now)).Should(BeEmpty())
+			Ω(matrix.HasCompleteOrderFragment(buyOrderFragment.OrderID)).Should(BeTrue())
+			Ω(matrix.RemoveExpiredOrderFragments(now.Add(2 * time.Minute))).Should(BeEmpty())
+			Ω(matrix.HasCompleteOrderFragment(buyOrderFragment.OrderID)).Should(BeFalse())
+		})
+
+		It("should remember complete orders with an unknown expiry for the retention period", func() {
+			now := time.Now()
+			orderID := order.ID("unknown")
+
+			matrix := NewDeltaFragmentMatrix(prime)
+			Ω(matrix.RemoveOrderFragment(orderID)).ShouldNot(HaveOccurred())
+			matrix.RemoveExpiredOrderFragments(now.Add(CompleteOrderRetention - time.Minute))
+			Ω(matrix.HasCompleteOrderFragment(orderID)).Should(BeTrue())
+			matrix.RemoveExpiredOrderFragments(now.Add(CompleteOrderRetention + time.Minute))
+			Ω(matrix.HasCompleteOrderFragment(orderID)).Should(BeFalse())
+		})
+	})
+
+	Context("when restoring matches", func() {
+
+		var buyOrderFragment, sellOrderFragment *order.Fragment
+		var deltaID DeltaID
+
+		newOrderFragment := func(parity order.Parity, nonce uint) *order.Fragment {
+			orderFragments, err := order.NewOrder(order.TypeLimit, parity, time.Now().Add(time.Hour), order.CurrencyCodeBTC, order.CurrencyCodeETH, heapInt(10), heapInt(1000), heapInt(100), heapInt(nonce)).Split(n, k, prime)
+			Ω(err).ShouldNot(HaveOccurred())
+			signFragments(orderFragments)
+			return orderFragments[0]
+		}
+
+		BeforeEach(func() {
+			buyOrderFragment = newOrderFragment(order.ParityBuy, 0)
+			sellOrderFragment = newOrderFragment(order.ParitySell, 0)
+			deltaID = DeltaID(crypto.Keccak256(buyOrderFragment.OrderID, sellOrderFragment.OrderID))
+		})
+
+		It("should reopen the orders of a match and remove the residual order", func() {
+			matrix := NewDeltaFragmentMatrix(prime)
+			_, err := matrix.InsertOrderFragment(buyOrderFragment)
+			Ω(err).ShouldNot(HaveOccurred())
+			_, err = matrix.InsertOrderFragment(sellOrderFragment)
+			Ω(err).ShouldNot(HaveOccurred())
+			Ω(matrix.RemoveMatch(deltaID, buyOrderFragment.OrderID, sellOrderFragment.OrderID)).ShouldNot(HaveOccurred())
+			Ω(matrix.HasCompleteOrderFragment(buyOrderFragment.OrderID)).Should(BeTrue())
+			Ω(matrix.HasCompleteOrderFragment(sellOrderFragment.OrderID)).Should(BeTrue())
+
+			residual := buyOrderFragment.Residual(sellOrderFragment.OrderID, sellOrderFragment.MaxVolumeShare, prime)
+			_, err = matrix.InsertResidualOrderFragment(buyOrderFragment, residual)
+			Ω(err).ShouldNot(HaveOccurred())
+
+			orderFragments, residualOrderIDs, differenceFragments := matrix.RestoreMatch(deltaID)
+			Ω(orderFragments).Should(Equal([]*order.Fragment{buyOrderFragment, sellOrderFragment}))
+			Ω(residualOrderIDs).Should(Equal([]order.ID{residual.OrderID}))
+			// The orders of the match are not compared again
+			Ω(differenceFragments).Should(BeEmpty())
+			Ω(matrix.HasCompleteOrderFragment(buyOrderFragment.OrderID)).Should(BeFalse())
+			Ω(matrix.HasCompleteOrderFragment(residual.OrderID)).Should(BeTrue())
+			Ω(matrix.ResidualOrderID(buyOrderFragment.OrderID).Equal(buyOrderFragment.OrderID)).Should(BeTrue())
+
+			// The reopened orders are compared with new orders
+			differenceFragments, err = matrix.InsertOrderFragment(newOrderFragment(order.ParitySell, 1))
+			Ω(err).ShouldNot(HaveOccurred())
+			Ω(differenceFragments).Should(HaveLen(1))
+			Ω(differenceFragments[0].BuyOrderID.Equal(buyOrderFragment.OrderID)).Should(BeTrue())
+
+			// A match is only restored once
+			orderFragments, _, _ = matrix.RestoreMatch(deltaID)
+			Ω(orderFragments).Should(BeEmpty())
+		})
+
+		It("should not reopen an order when its residual order has been matched again", func() {
+			matrix := NewDeltaFragmentMatrix(prime)
+			_, err := matrix.InsertOrderFragment(buyOrderFragment)
+			Ω(err).ShouldNot(HaveOccurred())
+			_, err = matrix.InsertOrderFragment(sellOrderFragment)
+			Ω(err).ShouldNot(HaveOccurred())
+			Ω(matrix.RemoveMatch(deltaID, buyOrderFragment.OrderID, sellOrderFragment.OrderID)).ShouldNot(HaveOccurred())
+
+			residual := buyOrderFragment.Residual(sellOrderFragment.OrderID, sellOrderFragment.MaxVolumeShare, prime)
+			_, err = matrix.InsertResidualOrderFragment(buyOrderFragment, residual)
+			Ω(err).ShouldNot(HaveOccurred())
+			Ω(matrix.RemoveOrderFragment(residual.OrderID)).ShouldNot(HaveOccurred())
+
+			orderFragments, residualOrderIDs, _ := matrix.RestoreMatch(deltaID)
+			Ω(orderFragments).Should(Equal([]*order.Fragment{sellOrderFragment}))
+			Ω(residualOrderIDs).Should(BeEmpty())
+			Ω(matrix.HasCompleteOrderFragment(buyOrderFragment.OrderID)).Should(BeTrue())
+		})
+	})
+
+	Context("when inserting immediate orders", func() {
+
+		newOrderFragments := func(parity order.Parity, timeInForce order.TimeInForce, openTime time.Time) []*order.Fragment {
+			orderFragments, err := order.NewOrder(order.TypeLimit, parity, time.Now().Add(time.Hour), order.CurrencyCodeBTC, order.CurrencyCodeETH, heapInt(10), heapInt(1000), heapInt(100), heapInt(0)).WithTimeInForce(timeInForce).WithOpenTime(openTime).Split(n, k, prime)
+			Ω(err).ShouldNot(HaveOccurred())
+			signFragments(orderFragments)
+			return orderFragments
+		}
+
+		It("should remove immediate orders when their window closes", func() {
+			now := time.Now()
+			gtcOrderFragment := newOrderFragments(order.ParitySell, order.TimeInForceGTC, time.Time{})[0]
+			iocOrderFragment := newOrderFragments(order.ParityBuy, order.TimeInForceIOC, now)[0]
+			fokOrderFragment := newOrderFragments(order.ParityBuy, order.TimeInForceFOK, now)[0]
+
+			matrix := NewDeltaFragmentMatrix(prime)
+			for _, orderFragment := range []*order.Fragment{gtcOrderFragment, iocOrderFragment, fokOrderFragment} {
+				_, err := matrix.InsertOrderFragment(orderFragment)
+				Ω(err).ShouldNot(HaveOccurred())
+			}
+
+			Ω(matrix.RemoveExpiredOrderFragments(now)).Should(BeEmpty())
+			Ω(matrix.RemoveExpiredOrderFragments(now.Add(ImmediateOrderWindow + time.Second))).Should(ConsistOf(iocOrderFragment.OrderID, fokOrderFragment.OrderID))
+			Ω(matrix.OrderFragment(gtcOrderFragment.OrderID)).ShouldNot(BeNil())
+		})
+
+		It("should close the window at the open time signed by the trader", func() {
+			now := time.Now()
+			closedOrderFragment := newOrderFragments(order.ParityBuy, order.TimeInForceIOC, now.Add(-ImmediateOrderWindow))[0]
+			missingOrderFragment := newOrderFragments(order.ParityBuy, order.TimeInForceIOC, time.Time{})[0]
+
+			// Every matrix closes the window at the same time, no matter when
+			// it receives the order fragment
+			matrix := NewDeltaFragmentMatrix(prime)
+			_, err := matrix.InsertOrderFragment(closedOrderFragment)
+			Ω(err).Should(Equal(ErrOrderFragmentExpired))
+			_, err = matrix.InsertOrderFragment(missingOrderFragment)
+			Ω(err).Should(Equal(ErrOrderFragmentExpired))
+		})
+
+		It("should close the window of residual orders with the window of their parent", func() {
+			buyOrderFragments := newOrderFragments(order.ParityBuy, order.TimeInForceIOC, time.Now())
+			sellOrderFragments := newOrderFragments(order.ParitySell, order.TimeInForceGTC, time.Time{})
+
+			matrix := NewDeltaFragmentMatrix(prime)
+			_, err := matrix.InsertOrderFragment(buyOrderFragments[0])
+			Ω(err).ShouldNot(HaveOccurred())
+			_, err = matrix.InsertOrderFragment(sellOrderFragments[0])
+			Ω(err).ShouldNot(HaveOccurred())
+			Ω(matrix.RemoveOrderFragment(buyOrderFragments[0].OrderID)).ShouldNot(HaveOccurred())
+			Ω(matrix.RemoveOrderFragment(sellOrderFragments[0].OrderID)).ShouldNot(HaveOccurred())
+
+			residual := buyOrderFragments[0].Residual(sellOrderFragments[0].OrderID, sellOrderFragments[0].MaxVolumeShare, prime)
+			Ω(residual.OrderTimeInForce).Should(Equal(order.TimeInForceIOC))
+			_, err = matrix.InsertResidualOrderFragment(buyOrderFragments[0], residual)
+			Ω(err).ShouldNot(HaveOccurred())
+			Ω(matrix.RemoveExpiredOrderFragments(time.Now().Add(ImmediateOrderWindow + time.Second))).Should(Equal([]order.ID{residual.OrderID}))
+
+			// The residual order of an immediate order whose window has closed
+			// cannot be inserted
+			buyOrderFragments = newOrderFragments(order.ParityBuy, order.TimeInForceIOC, time.Now().Add(-ImmediateOrderWindow))
+			residual = buyOrderFragments[1].Residual(sellOrderFragments[1].OrderID, sellOrderFragments[1].MaxVolumeShare, prime)
+			Ω(residual.OrderOpenTime).Should(Equal(buyOrderFragments[1].OrderOpenTime))
+			_, err = matrix.InsertResidualOrderFragment(buyOrderFragments[1], residual)
+			Ω(err).Should(Equal(ErrOrderFragmentExpired))
+		})
+	})
+})
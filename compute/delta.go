@@ -2,14 +2,21 @@ package compute
 
 import (
 	"bytes"
+	"errors"
 
 	"github.com/ethereum/go-ethereum/crypto"
 	base58 "github.com/jbenet/go-base58"
+	"github.com/republicprotocol/republic-go/canonical"
+	"github.com/republicprotocol/republic-go/identity"
 	"github.com/republicprotocol/republic-go/order"
 	"github.com/republicprotocol/republic-go/shamir"
 	"github.com/republicprotocol/republic-go/stackint"
 )
 
+// ErrIncompatibleDeltaFragments is returned when delta fragments that are not
+// fragments of the same Delta are used to reconstruct a Delta.
+var ErrIncompatibleDeltaFragments = errors.New("incompatible delta fragments")
+
 // A DeltaID is the Keccak256 hash of the order IDs that were used to compute
 // the associated Delta.
 type DeltaID []byte
@@ -25,16 +32,13 @@ func (id DeltaID) String() string {
 }
 
 // A Delta (reconstructed from Delta Fragments) contains the data required
-// to know if two orders can be matched
+// to know if two orders can be matched. It only reveals whether or not the
+// orders match, and nothing about the differences between them.
 type Delta struct {
 	ID          DeltaID
 	BuyOrderID  order.ID
 	SellOrderID order.ID
-	FstCode     *stackint.Int1024
-	SndCode     *stackint.Int1024
-	Price       *stackint.Int1024
-	MaxVolume   *stackint.Int1024
-	MinVolume   *stackint.Int1024
+	Match       *stackint.Int1024
 }
 
 // NewDelta reconstructs a delta from a series of fragments
@@ -46,17 +50,9 @@ func NewDelta(deltaFragments []*DeltaFragment, prime *stackint.Int1024) *Delta {
 
 	// Collect Shares across all DeltaFragments.
 	k := len(deltaFragments)
-	fstCodeShares := make(shamir.Shares, k)
-	sndCodeShares := make(shamir.Shares, k)
-	priceShares := make(shamir.Shares, k)
-	maxVolumeShares := make(shamir.Shares, k)
-	minVolumeShares := make(shamir.Shares, k)
+	matchShares := make(shamir.Shares, k)
 	for i, deltaFragment := range deltaFragments {
-		fstCodeShares[i] = deltaFragment.FstCodeShare
-		sndCodeShares[i] = deltaFragment.SndCodeShare
-		priceShares[i] = deltaFragment.PriceShare
-		maxVolumeShares[i] = deltaFragment.MaxVolumeShare
-		minVolumeShares[i] = deltaFragment.MinVolumeShare
+		matchShares[i] = deltaFragment.MatchShare
 	}
 
 	// Join the Shares into a Result.
@@ -64,39 +60,60 @@ func NewDelta(deltaFragments []*DeltaFragment, prime *stackint.Int1024) *Delta {
 		BuyOrderID:  deltaFragments[0].BuyOrderID,
 		SellOrderID: deltaFragments[0].SellOrderID,
 	}
-	delta.FstCode = shamir.Join(prime, fstCodeShares)
-	delta.SndCode = shamir.Join(prime, sndCodeShares)
-	delta.Price = shamir.Join(prime, priceShares)
-	delta.MaxVolume = shamir.Join(prime, maxVolumeShares)
-	delta.MinVolume = shamir.Join(prime, minVolumeShares)
+	delta.Match = shamir.Join(prime, matchShares)
 
 	// Compute the ResultID and return the Result.
 	delta.ID = DeltaID(crypto.Keccak256(delta.BuyOrderID[:], delta.SellOrderID[:]))
 	return delta
 }
 
-// IsMatch returns true if the Delta's two orders can fulfill one another
-func (delta *Delta) IsMatch(prime *stackint.Int1024) bool {
-
-	two := stackint.Two()
-	zeroThreshold := prime.Div(&two)
-
-	if !delta.FstCode.IsZero() {
-		return false
+// NewDeltaRobust reconstructs a delta from a series of fragments that are
+// still arriving, detecting and correcting faulty fragments when up to faults
+// of them are faulty. The delta is reconstructed once k + faults fragments
+// are consistent with it, using shamir.LagrangeCache.JoinOnline. The Lagrange
+// coefficients are taken from the LagrangeCache, so that they are not
+// recomputed for every delta. The delta, and the fragments that were faulty,
+// are returned. An error is returned if the fragments are not compatible, or
+// if too few of them are consistent, in which case more fragments are needed.
+func NewDeltaRobust(deltaFragments []*DeltaFragment, k, faults int64, lagranges *shamir.LagrangeCache) (*Delta, []*DeltaFragment, error) {
+	// Check that all DeltaFragments are compatible with each other.
+	if !IsCompatible(deltaFragments) {
+		return nil, nil, ErrIncompatibleDeltaFragments
 	}
-	if !delta.SndCode.IsZero() {
-		return false
+
+	// Collect Shares across all DeltaFragments.
+	matchShares := make(shamir.Shares, len(deltaFragments))
+	for i, deltaFragment := range deltaFragments {
+		matchShares[i] = deltaFragment.MatchShare
 	}
-	if delta.Price.Cmp(&zeroThreshold) == 1 {
-		return false
+
+	// Join the Shares into a Delta, and find the faulty DeltaFragments.
+	match, faultyKeys, err := lagranges.JoinOnline(k, faults, matchShares)
+	if err != nil {
+		return nil, nil, err
 	}
-	if delta.MaxVolume.Cmp(&zeroThreshold) == 1 {
-		return false
+	faultyDeltaFragments := make([]*DeltaFragment, 0, len(faultyKeys))
+	for _, deltaFragment := range deltaFragments {
+		for _, key := range faultyKeys {
+			if deltaFragment.MatchShare.Key == key {
+				faultyDeltaFragments = append(faultyDeltaFragments, deltaFragment)
+			}
+		}
 	}
-	if delta.MinVolume.Cmp(&zeroThreshold) == 1 {
-		return false
+
+	delta := &Delta{
+		BuyOrderID:  deltaFragments[0].BuyOrderID,
+		SellOrderID: deltaFragments[0].SellOrderID,
+		Match:       match,
 	}
-	return true
+	delta.ID = DeltaID(crypto.Keccak256(delta.BuyOrderID[:], delta.SellOrderID[:]))
+	return delta, faultyDeltaFragments, nil
+}
+
+// IsMatch returns true if the Delta's two orders can fulfill one another
+func (delta *Delta) IsMatch() bool {
+	one := stackint.One()
+	return delta.Match.Cmp(&one) == 0
 }
 
 // A DeltaFragmentID is the Keccak256 hash of the order IDs that were used to
@@ -113,79 +130,44 @@ func (id DeltaFragmentID) String() string {
 	return base58.Encode(id)
 }
 
-// A DeltaFragment is a secret share of a Final. Is is performing a
-// computation over two OrderFragments.
+// deltaFragmentPrefix is the tag of the canonical encoding of a DeltaFragment
+// so that a DeltaFragment signature cannot be mistaken for any other
+// signature.
+var deltaFragmentPrefix = []byte("Republic Protocol: delta fragment: ")
+
+// A DeltaFragment is a secret share of a Delta. It holds a share of the bit
+// that is 1 when two orders match, and 0 otherwise. It is produced by the
+// secure comparison of a DifferenceFragment with the rest of the dark pool,
+// and it is signed by the dark node that produced it. The CommitmentsHash is
+// the hash of the Commitments that the dark node verified its order fragments
+// against, and a Delta is only reconstructed from DeltaFragments with the same
+// CommitmentsHash, so that a trader cannot give different dark nodes shares
+// of different orders.
 type DeltaFragment struct {
+	Signature           identity.Signature
 	ID                  DeltaFragmentID
 	DeltaID             DeltaID
 	BuyOrderID          order.ID
 	SellOrderID         order.ID
 	BuyOrderFragmentID  order.FragmentID
 	SellOrderFragmentID order.FragmentID
+	CommitmentsHash     []byte
 
-	FstCodeShare   shamir.Share
-	SndCodeShare   shamir.Share
-	PriceShare     shamir.Share
-	MaxVolumeShare shamir.Share
-	MinVolumeShare shamir.Share
+	MatchShare shamir.Share
 }
 
-// NewDeltaFragment combines two order fragments, taking:
-// 1) the difference of the buy code, sell code and price
-// 2) the buy's max volume minus the sell's min volume
-// 3) the buy's min volume minus the sell's max volume
-func NewDeltaFragment(left *order.Fragment, right *order.Fragment, prime *stackint.Int1024) *DeltaFragment {
-	if !left.IsCompatible(right) {
-		return nil
-	}
-
-	var buyOrderFragment *order.Fragment
-	var sellOrderFragment *order.Fragment
-	if left.OrderParity == order.ParityBuy {
-		buyOrderFragment = left
-		sellOrderFragment = right
-	} else {
-		buyOrderFragment = right
-		sellOrderFragment = left
-	}
-
-	fstCodeShare := shamir.Share{
-		Key:   buyOrderFragment.FstCodeShare.Key,
-		Value: buyOrderFragment.FstCodeShare.Value.SubModulo(&sellOrderFragment.FstCodeShare.Value, prime),
-	}
-
-	sndCodeShare := shamir.Share{
-		Key:   buyOrderFragment.SndCodeShare.Key,
-		Value: buyOrderFragment.SndCodeShare.Value.SubModulo(&sellOrderFragment.SndCodeShare.Value, prime),
-	}
-
-	priceShare := shamir.Share{
-		Key:   buyOrderFragment.PriceShare.Key,
-		Value: buyOrderFragment.PriceShare.Value.SubModulo(&sellOrderFragment.PriceShare.Value, prime),
-	}
-
-	maxVolumeShare := shamir.Share{
-		Key:   buyOrderFragment.MaxVolumeShare.Key,
-		Value: buyOrderFragment.MaxVolumeShare.Value.SubModulo(&sellOrderFragment.MinVolumeShare.Value, prime),
-	}
-
-	minVolumeShare := shamir.Share{
-		Key:   buyOrderFragment.MinVolumeShare.Key,
-		Value: sellOrderFragment.MaxVolumeShare.Value.SubModulo(&buyOrderFragment.MinVolumeShare.Value, prime),
-	}
-
+// NewDeltaFragment returns a DeltaFragment for the same orders as the
+// DifferenceFragment, holding the given share of the match bit.
+func NewDeltaFragment(differenceFragment *DifferenceFragment, matchShare shamir.Share) *DeltaFragment {
 	return &DeltaFragment{
-		ID:                  DeltaFragmentID(crypto.Keccak256([]byte(buyOrderFragment.ID), []byte(sellOrderFragment.ID))),
-		DeltaID:             DeltaID(crypto.Keccak256([]byte(buyOrderFragment.OrderID), []byte(sellOrderFragment.OrderID))),
-		BuyOrderID:          buyOrderFragment.OrderID,
-		SellOrderID:         sellOrderFragment.OrderID,
-		BuyOrderFragmentID:  buyOrderFragment.ID,
-		SellOrderFragmentID: sellOrderFragment.ID,
-		FstCodeShare:        fstCodeShare,
-		SndCodeShare:        sndCodeShare,
-		PriceShare:          priceShare,
-		MaxVolumeShare:      maxVolumeShare,
-		MinVolumeShare:      minVolumeShare,
+		ID:                  differenceFragment.ID,
+		DeltaID:             differenceFragment.DeltaID,
+		BuyOrderID:          differenceFragment.BuyOrderID,
+		SellOrderID:         differenceFragment.SellOrderID,
+		BuyOrderFragmentID:  differenceFragment.BuyOrderFragmentID,
+		SellOrderFragmentID: differenceFragment.SellOrderFragmentID,
+		CommitmentsHash:     differenceFragment.CommitmentsHash,
+		MatchShare:          matchShare,
 	}
 }
 
@@ -197,20 +179,55 @@ func (deltaFragment *DeltaFragment) Equals(other *DeltaFragment) bool {
 		deltaFragment.SellOrderID.Equal(other.SellOrderID) &&
 		deltaFragment.BuyOrderFragmentID.Equal(other.BuyOrderFragmentID) &&
 		deltaFragment.SellOrderFragmentID.Equal(other.SellOrderFragmentID) &&
-		deltaFragment.FstCodeShare.Key == other.FstCodeShare.Key &&
-		deltaFragment.FstCodeShare.Value.Cmp(&other.FstCodeShare.Value) == 0 &&
-		deltaFragment.SndCodeShare.Key == other.SndCodeShare.Key &&
-		deltaFragment.SndCodeShare.Value.Cmp(&other.SndCodeShare.Value) == 0 &&
-		deltaFragment.PriceShare.Key == other.PriceShare.Key &&
-		deltaFragment.PriceShare.Value.Cmp(&other.PriceShare.Value) == 0 &&
-		deltaFragment.MaxVolumeShare.Key == other.MaxVolumeShare.Key &&
-		deltaFragment.MaxVolumeShare.Value.Cmp(&other.MaxVolumeShare.Value) == 0 &&
-		deltaFragment.MinVolumeShare.Key == other.MinVolumeShare.Key &&
-		deltaFragment.MinVolumeShare.Value.Cmp(&other.MinVolumeShare.Value) == 0
+		bytes.Equal(deltaFragment.CommitmentsHash, other.CommitmentsHash) &&
+		deltaFragment.MatchShare.Key == other.MatchShare.Key &&
+		deltaFragment.MatchShare.Value.Cmp(&other.MatchShare.Value) == 0
+}
+
+// Hash returns the Keccak256 hash of the canonical encoding of a
+// DeltaFragment. This hash is used to create the signature for a
+// DeltaFragment. It returns nil if the DeltaFragment cannot be encoded.
+func (deltaFragment *DeltaFragment) Hash() []byte {
+	data, err := deltaFragment.MarshalBinary()
+	if err != nil {
+		return nil
+	}
+	return crypto.Keccak256(data)
+}
+
+// Sign signs the DeltaFragment using the provided keypair, and assigns it the
+// DeltaFragment's Signature field.
+func (deltaFragment *DeltaFragment) Sign(keyPair identity.KeyPair) error {
+	var err error
+	deltaFragment.Signature, err = keyPair.Sign(deltaFragment)
+	return err
+}
+
+// VerifySignature verifies that the Signature field has been signed by the
+// provided ID's private key, returning an error if the signature is invalid
+func (deltaFragment *DeltaFragment) VerifySignature(ID identity.ID) error {
+	return identity.VerifySignature(deltaFragment, deltaFragment.Signature, ID)
+}
+
+// MarshalBinary implements the encoding.BinaryMarshaler interface. It returns
+// the canonical encoding of a DeltaFragment, which covers every field except
+// the Signature.
+func (deltaFragment *DeltaFragment) MarshalBinary() ([]byte, error) {
+	encoder := canonical.NewEncoder(deltaFragmentPrefix)
+	encoder.WriteBytes(deltaFragment.ID)
+	encoder.WriteBytes(deltaFragment.DeltaID)
+	encoder.WriteBytes(deltaFragment.BuyOrderID)
+	encoder.WriteBytes(deltaFragment.SellOrderID)
+	encoder.WriteBytes(deltaFragment.BuyOrderFragmentID)
+	encoder.WriteBytes(deltaFragment.SellOrderFragmentID)
+	encoder.WriteBytes(deltaFragment.CommitmentsHash)
+	encoder.WriteShare(deltaFragment.MatchShare)
+	return encoder.Bytes()
 }
 
 // IsCompatible returns true if all DeltaFragments are fragments of the same
-// Delta, otherwise it returns false.
+// Delta, computed from order fragments that were verified against the same
+// Commitments, otherwise it returns false.
 func IsCompatible(deltaFragments []*DeltaFragment) bool {
 	if len(deltaFragments) == 0 {
 		return false
@@ -219,6 +236,32 @@ func IsCompatible(deltaFragments []*DeltaFragment) bool {
 		if !deltaFragments[i].DeltaID.Equal(deltaFragments[0].DeltaID) {
 			return false
 		}
+		if !bytes.Equal(deltaFragments[i].CommitmentsHash, deltaFragments[0].CommitmentsHash) {
+			return false
+		}
 	}
 	return true
 }
+
+// withCommitmentsHash returns the DeltaFragments that have the given
+// CommitmentsHash.
+func withCommitmentsHash(deltaFragments []*DeltaFragment, commitmentsHash []byte) []*DeltaFragment {
+	consistent := make([]*DeltaFragment, 0, len(deltaFragments))
+	for _, deltaFragment := range deltaFragments {
+		if bytes.Equal(deltaFragment.CommitmentsHash, commitmentsHash) {
+			consistent = append(consistent, deltaFragment)
+		}
+	}
+	return consistent
+}
+
+// hasShareKey returns true if one of the DeltaFragments holds a share with the
+// given key, otherwise it returns false.
+func hasShareKey(deltaFragments []*DeltaFragment, key int64) bool {
+	for i := range deltaFragments {
+		if deltaFragments[i].MatchShare.Key == key {
+			return true
+		}
+	}
+	return false
+}
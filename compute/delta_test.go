@@ -2,11 +2,16 @@ package compute_test
 
 import (
 	"crypto/rand"
+	"encoding/binary"
+	"encoding/hex"
+	"testing"
 	"time"
 
+	"github.com/ethereum/go-ethereum/crypto"
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
 	. "github.com/republicprotocol/republic-go/compute"
+	"github.com/republicprotocol/republic-go/identity"
 	"github.com/republicprotocol/republic-go/order"
 	"github.com/republicprotocol/republic-go/shamir"
 	"github.com/republicprotocol/republic-go/stackint"
@@ -18,6 +23,14 @@ func heapInt(n uint) *stackint.Int1024 {
 	return &tmp
 }
 
+// signFragments signs order fragments as a new trader, so that two orders in a
+// test are never from the same trader
+func signFragments(fragments []*order.Fragment) {
+	trader, err := identity.NewKeyPair()
+	Ω(err).ShouldNot(HaveOccurred())
+	Ω(order.SignFragments(trader, fragments)).ShouldNot(HaveOccurred())
+}
+
 var _ = Describe("Delta and delta fragments", func() {
 
 	n := int64(8)
@@ -41,13 +54,11 @@ var _ = Describe("Delta and delta fragments", func() {
 		It("should only return a delta after receiving k delta fragments", func() {
 			lhs, err := order.NewOrder(order.TypeLimit, order.ParityBuy, time.Now().Add(time.Hour), order.CurrencyCodeBTC, order.CurrencyCodeETH, heapInt(10), heapInt(1000), heapInt(100), heapInt(0)).Split(n, k, prime)
 			Ω(err).ShouldNot(HaveOccurred())
+			signFragments(lhs)
 			rhs, err := order.NewOrder(order.TypeLimit, order.ParitySell, time.Now().Add(time.Hour), order.CurrencyCodeBTC, order.CurrencyCodeETH, heapInt(10), heapInt(1000), heapInt(100), heapInt(0)).Split(n, k, prime)
 			Ω(err).ShouldNot(HaveOccurred())
-			deltaFragments := make([]*DeltaFragment, n)
-			for i := range deltaFragments {
-				deltaFragment := NewDeltaFragment(lhs[i], rhs[i], prime)
-				deltaFragments[i] = deltaFragment
-			}
+			signFragments(rhs)
+			deltaFragments := computeDeltaFragments(lhs, rhs, n, k, prime, true)
 
 			builder := NewDeltaBuilder(k, prime)
 			for i := int64(0); i < k-1; i++ {
@@ -58,16 +69,32 @@ var _ = Describe("Delta and delta fragments", func() {
 			Ω(delta).ShouldNot(BeNil())
 		})
 
-		It("should not return a delta after the first k delta fragments", func() {
+		It("should return reconstructed deltas by ID", func() {
 			lhs, err := order.NewOrder(order.TypeLimit, order.ParityBuy, time.Now().Add(time.Hour), order.CurrencyCodeBTC, order.CurrencyCodeETH, heapInt(10), heapInt(1000), heapInt(100), heapInt(0)).Split(n, k, prime)
 			Ω(err).ShouldNot(HaveOccurred())
+			signFragments(lhs)
 			rhs, err := order.NewOrder(order.TypeLimit, order.ParitySell, time.Now().Add(time.Hour), order.CurrencyCodeBTC, order.CurrencyCodeETH, heapInt(10), heapInt(1000), heapInt(100), heapInt(0)).Split(n, k, prime)
 			Ω(err).ShouldNot(HaveOccurred())
-			deltaFragments := make([]*DeltaFragment, n)
-			for i := range deltaFragments {
-				deltaFragment := NewDeltaFragment(lhs[i], rhs[i], prime)
-				deltaFragments[i] = deltaFragment
+			signFragments(rhs)
+			deltaFragments := computeDeltaFragments(lhs, rhs, n, k, prime, true)
+
+			builder := NewDeltaBuilder(k, prime)
+			for i := int64(0); i < k-1; i++ {
+				builder.InsertDeltaFragment(deltaFragments[i])
 			}
+			Ω(builder.Delta(deltaFragments[0].DeltaID)).Should(BeNil())
+			delta := builder.InsertDeltaFragment(deltaFragments[k-1])
+			Ω(builder.Delta(deltaFragments[0].DeltaID)).Should(Equal(delta))
+		})
+
+		It("should not return a delta after the first k delta fragments", func() {
+			lhs, err := order.NewOrder(order.TypeLimit, order.ParityBuy, time.Now().Add(time.Hour), order.CurrencyCodeBTC, order.CurrencyCodeETH, heapInt(10), heapInt(1000), heapInt(100), heapInt(0)).Split(n, k, prime)
+			Ω(err).ShouldNot(HaveOccurred())
+			signFragments(lhs)
+			rhs, err := order.NewOrder(order.TypeLimit, order.ParitySell, time.Now().Add(time.Hour), order.CurrencyCodeBTC, order.CurrencyCodeETH, heapInt(10), heapInt(1000), heapInt(100), heapInt(0)).Split(n, k, prime)
+			Ω(err).ShouldNot(HaveOccurred())
+			signFragments(rhs)
+			deltaFragments := computeDeltaFragments(lhs, rhs, n, k, prime, true)
 
 			builder := NewDeltaBuilder(k, prime)
 			for i := int64(0); i < k-1; i++ {
@@ -86,13 +113,11 @@ var _ = Describe("Delta and delta fragments", func() {
 		It("should not return a delta using k non-unique fragments", func() {
 			lhs, err := order.NewOrder(order.TypeLimit, order.ParityBuy, time.Now().Add(time.Hour), order.CurrencyCodeBTC, order.CurrencyCodeETH, heapInt(10), heapInt(1000), heapInt(100), heapInt(0)).Split(n, k, prime)
 			Ω(err).ShouldNot(HaveOccurred())
+			signFragments(lhs)
 			rhs, err := order.NewOrder(order.TypeLimit, order.ParitySell, time.Now().Add(time.Hour), order.CurrencyCodeBTC, order.CurrencyCodeETH, heapInt(10), heapInt(1000), heapInt(100), heapInt(0)).Split(n, k, prime)
 			Ω(err).ShouldNot(HaveOccurred())
-			deltaFragments := make([]*DeltaFragment, n)
-			for i := range deltaFragments {
-				deltaFragment := NewDeltaFragment(lhs[i], rhs[i], prime)
-				deltaFragments[i] = deltaFragment
-			}
+			signFragments(rhs)
+			deltaFragments := computeDeltaFragments(lhs, rhs, n, k, prime, true)
 
 			builder := NewDeltaBuilder(k, prime)
 			for i := int64(0); i < k-1; i++ {
@@ -104,161 +129,177 @@ var _ = Describe("Delta and delta fragments", func() {
 				Ω(delta).Should(BeNil())
 			}
 		})
-	})
-
-	Context("when orders are an exact match", func() {
 
-		It("should find a match", func() {
+		It("should not accept delta fragments with a share index that has already been used", func() {
 			lhs, err := order.NewOrder(order.TypeLimit, order.ParityBuy, time.Now().Add(time.Hour), order.CurrencyCodeBTC, order.CurrencyCodeETH, heapInt(10), heapInt(1000), heapInt(100), heapInt(0)).Split(n, k, prime)
 			Ω(err).ShouldNot(HaveOccurred())
-
+			signFragments(lhs)
 			rhs, err := order.NewOrder(order.TypeLimit, order.ParitySell, time.Now().Add(time.Hour), order.CurrencyCodeBTC, order.CurrencyCodeETH, heapInt(10), heapInt(1000), heapInt(100), heapInt(0)).Split(n, k, prime)
 			Ω(err).ShouldNot(HaveOccurred())
+			signFragments(rhs)
+			deltaFragments := computeDeltaFragments(lhs, rhs, n, k, prime, true)
 
-			result := computeDelta(lhs, rhs, n, prime)
-			Ω(result.IsMatch(prime)).Should(Equal(true))
-		})
+			builder := NewDeltaBuilder(k, prime)
+			for i := int64(0); i < k-1; i++ {
+				Ω(builder.InsertDeltaFragment(deltaFragments[i])).Should(BeNil())
+			}
 
-	})
+			// Another dark node claims the share index of the first delta
+			// fragment
+			forged := *deltaFragments[0]
+			forged.ID = DeltaFragmentID("forged")
+			forged.MatchShare = shamir.Share{Key: deltaFragments[0].MatchShare.Key, Value: stackint.Zero()}
+			Ω(builder.InsertDeltaFragment(&forged)).Should(BeNil())
+			Ω(builder.HasDeltaFragment(forged.ID)).Should(BeFalse())
 
-	Context("when orders use different currencies", func() {
+			delta := builder.InsertDeltaFragment(deltaFragments[k-1])
+			Ω(delta).ShouldNot(BeNil())
+			Ω(delta.IsMatch()).Should(BeTrue())
+		})
 
-		It("should not find a match for the same currencies in reverse", func() {
+		It("should correct and report faulty delta fragments", func() {
 			lhs, err := order.NewOrder(order.TypeLimit, order.ParityBuy, time.Now().Add(time.Hour), order.CurrencyCodeBTC, order.CurrencyCodeETH, heapInt(10), heapInt(1000), heapInt(100), heapInt(0)).Split(n, k, prime)
 			Ω(err).ShouldNot(HaveOccurred())
-
-			rhs, err := order.NewOrder(order.TypeLimit, order.ParitySell, time.Now().Add(time.Hour), order.CurrencyCodeETH, order.CurrencyCodeBTC, heapInt(10), heapInt(1000), heapInt(100), heapInt(0)).Split(n, k, prime)
-			Ω(err).ShouldNot(HaveOccurred())
-
-			result := computeDelta(lhs, rhs, n, prime)
-			Ω(result.IsMatch(prime)).Should(Equal(false))
-		})
-
-		It("should not find a match when the first currencies differ", func() {
-			lhs, err := order.NewOrder(order.TypeLimit, order.ParityBuy, time.Now().Add(time.Hour), order.CurrencyCodeBTC, order.CurrencyCodeREN, heapInt(10), heapInt(1000), heapInt(100), heapInt(0)).Split(n, k, prime)
+			signFragments(lhs)
+			rhs, err := order.NewOrder(order.TypeLimit, order.ParitySell, time.Now().Add(time.Hour), order.CurrencyCodeBTC, order.CurrencyCodeETH, heapInt(10), heapInt(1000), heapInt(100), heapInt(0)).Split(n, k, prime)
 			Ω(err).ShouldNot(HaveOccurred())
+			signFragments(rhs)
+			deltaFragments := computeDeltaFragments(lhs, rhs, n, k, prime, true)
 
-			rhs, err := order.NewOrder(order.TypeLimit, order.ParitySell, time.Now().Add(time.Hour), order.CurrencyCodeETH, order.CurrencyCodeREN, heapInt(10), heapInt(1000), heapInt(100), heapInt(0)).Split(n, k, prime)
-			Ω(err).ShouldNot(HaveOccurred())
+			// One dark node sends a share that does not match
+			one := stackint.One()
+			deltaFragments[2].MatchShare.Value = deltaFragments[2].MatchShare.Value.AddModulo(&one, prime)
 
-			result := computeDelta(lhs, rhs, n, prime)
-			Ω(result.IsMatch(prime)).Should(Equal(false))
+			// The faulty delta fragment delays the delta by one delta fragment
+			faults := (n - k) / 2
+			builder := NewDeltaBuilder(k, prime)
+			builder.SetFaults(faults)
+			for i := int64(0); i < k+faults; i++ {
+				Ω(builder.InsertDeltaFragment(deltaFragments[i])).Should(BeNil())
+			}
+			delta := builder.InsertDeltaFragment(deltaFragments[k+faults])
+			Ω(delta).ShouldNot(BeNil())
+			Ω(delta.IsMatch()).Should(BeTrue())
+			Ω(builder.FaultyDeltaFragments(delta.ID)).Should(Equal([]*DeltaFragment{deltaFragments[2]}))
 		})
 
-		It("should not find a match when the second currencies differ", func() {
+		It("should reconstruct deltas from k + faults delta fragments without waiting for the rest of the dark pool", func() {
 			lhs, err := order.NewOrder(order.TypeLimit, order.ParityBuy, time.Now().Add(time.Hour), order.CurrencyCodeBTC, order.CurrencyCodeETH, heapInt(10), heapInt(1000), heapInt(100), heapInt(0)).Split(n, k, prime)
 			Ω(err).ShouldNot(HaveOccurred())
-
-			rhs, err := order.NewOrder(order.TypeLimit, order.ParitySell, time.Now().Add(time.Hour), order.CurrencyCodeBTC, order.CurrencyCodeREN, heapInt(10), heapInt(1000), heapInt(100), heapInt(0)).Split(n, k, prime)
+			signFragments(lhs)
+			rhs, err := order.NewOrder(order.TypeLimit, order.ParitySell, time.Now().Add(time.Hour), order.CurrencyCodeBTC, order.CurrencyCodeETH, heapInt(10), heapInt(1000), heapInt(100), heapInt(0)).Split(n, k, prime)
 			Ω(err).ShouldNot(HaveOccurred())
+			signFragments(rhs)
+			deltaFragments := computeDeltaFragments(lhs, rhs, n, k, prime, true)
 
-			result := computeDelta(lhs, rhs, n, prime)
-			Ω(result.IsMatch(prime)).Should(Equal(false))
+			faults := (n - k) / 2
+			builder := NewDeltaBuilder(k, prime)
+			builder.SetFaults(faults)
+			for i := int64(0); i < k+faults-1; i++ {
+				Ω(builder.InsertDeltaFragment(deltaFragments[i])).Should(BeNil())
+			}
+			delta := builder.InsertDeltaFragment(deltaFragments[k+faults-1])
+			Ω(delta).ShouldNot(BeNil())
+			Ω(delta.IsMatch()).Should(BeTrue())
+			Ω(builder.FaultyDeltaFragments(delta.ID)).Should(BeEmpty())
 		})
 
-		It("should not find a match when both currencies differ", func() {
+		It("should only join delta fragments that were computed from the same commitments", func() {
 			lhs, err := order.NewOrder(order.TypeLimit, order.ParityBuy, time.Now().Add(time.Hour), order.CurrencyCodeBTC, order.CurrencyCodeETH, heapInt(10), heapInt(1000), heapInt(100), heapInt(0)).Split(n, k, prime)
 			Ω(err).ShouldNot(HaveOccurred())
-
-			rhs, err := order.NewOrder(order.TypeLimit, order.ParitySell, time.Now().Add(time.Hour), order.CurrencyCodeREN, order.CurrencyCodeDGD, heapInt(10), heapInt(1000), heapInt(100), heapInt(0)).Split(n, k, prime)
+			signFragments(lhs)
+			rhs, err := order.NewOrder(order.TypeLimit, order.ParitySell, time.Now().Add(time.Hour), order.CurrencyCodeBTC, order.CurrencyCodeETH, heapInt(10), heapInt(1000), heapInt(100), heapInt(0)).Split(n, k, prime)
 			Ω(err).ShouldNot(HaveOccurred())
+			signFragments(rhs)
+			deltaFragments := computeDeltaFragments(lhs, rhs, n, k, prime, true)
+			for i := range deltaFragments {
+				deltaFragments[i].CommitmentsHash = []byte("commitments")
+			}
+			deltaFragments[0].CommitmentsHash = []byte("other commitments")
+			Ω(IsCompatible(deltaFragments[:k])).Should(BeFalse())
 
-			result := computeDelta(lhs, rhs, n, prime)
-			Ω(result.IsMatch(prime)).Should(Equal(false))
+			builder := NewDeltaBuilder(k, prime)
+			for i := int64(0); i < k; i++ {
+				Ω(builder.InsertDeltaFragment(deltaFragments[i])).Should(BeNil())
+			}
+			delta := builder.InsertDeltaFragment(deltaFragments[k])
+			Ω(delta).ShouldNot(BeNil())
+			Ω(delta.IsMatch()).Should(BeTrue())
 		})
 	})
 
-	Context("when prices vary", func() {
+	Context("when signing delta fragments", func() {
 
-		It("should find a match when the buy price is higher", func() {
-			lhs, err := order.NewOrder(order.TypeLimit, order.ParityBuy, time.Now().Add(time.Hour), order.CurrencyCodeBTC, order.CurrencyCodeETH, heapInt(12), heapInt(1000), heapInt(100), heapInt(0)).Split(n, k, prime)
+		It("should verify the signature of the signer", func() {
+			lhs, err := order.NewOrder(order.TypeLimit, order.ParityBuy, time.Now().Add(time.Hour), order.CurrencyCodeBTC, order.CurrencyCodeETH, heapInt(10), heapInt(1000), heapInt(100), heapInt(0)).Split(n, k, prime)
 			Ω(err).ShouldNot(HaveOccurred())
-
+			signFragments(lhs)
 			rhs, err := order.NewOrder(order.TypeLimit, order.ParitySell, time.Now().Add(time.Hour), order.CurrencyCodeBTC, order.CurrencyCodeETH, heapInt(10), heapInt(1000), heapInt(100), heapInt(0)).Split(n, k, prime)
 			Ω(err).ShouldNot(HaveOccurred())
+			signFragments(rhs)
+			deltaFragment := computeDeltaFragments(lhs, rhs, n, k, prime, true)[0]
 
-			result := computeDelta(lhs, rhs, n, prime)
-			Ω(result.IsMatch(prime)).Should(Equal(true))
-		})
-
-		It("should not find a match when the buy price is lower", func() {
-			lhs, err := order.NewOrder(order.TypeLimit, order.ParityBuy, time.Now().Add(time.Hour), order.CurrencyCodeBTC, order.CurrencyCodeETH, heapInt(10), heapInt(1000), heapInt(100), heapInt(0)).Split(n, k, prime)
+			keyPair, err := identity.NewKeyPair()
 			Ω(err).ShouldNot(HaveOccurred())
+			Ω(deltaFragment.Sign(keyPair)).ShouldNot(HaveOccurred())
+			Ω(deltaFragment.VerifySignature(keyPair.ID())).ShouldNot(HaveOccurred())
 
-			rhs, err := order.NewOrder(order.TypeLimit, order.ParitySell, time.Now().Add(time.Hour), order.CurrencyCodeBTC, order.CurrencyCodeETH, heapInt(12), heapInt(1000), heapInt(100), heapInt(0)).Split(n, k, prime)
+			other, err := identity.NewKeyPair()
 			Ω(err).ShouldNot(HaveOccurred())
+			Ω(deltaFragment.VerifySignature(other.ID())).Should(HaveOccurred())
 
-			result := computeDelta(lhs, rhs, n, prime)
-			Ω(result.IsMatch(prime)).Should(Equal(false))
+			// Changing the share invalidates the signature
+			deltaFragment.MatchShare = shamir.Share{Key: deltaFragment.MatchShare.Key, Value: stackint.Zero()}
+			Ω(deltaFragment.VerifySignature(keyPair.ID())).Should(HaveOccurred())
 		})
 	})
 
-	Context("when volumes vary", func() {
+	Context("when reconstructing the match bit", func() {
 
-		It("should find a match when the maximum buy volume is higher than the maximum sell volume", func() {
+		It("should find a match when the match bit is one", func() {
 			lhs, err := order.NewOrder(order.TypeLimit, order.ParityBuy, time.Now().Add(time.Hour), order.CurrencyCodeBTC, order.CurrencyCodeETH, heapInt(10), heapInt(1000), heapInt(100), heapInt(0)).Split(n, k, prime)
 			Ω(err).ShouldNot(HaveOccurred())
-
-			rhs, err := order.NewOrder(order.TypeLimit, order.ParitySell, time.Now().Add(time.Hour), order.CurrencyCodeBTC, order.CurrencyCodeETH, heapInt(10), heapInt(100), heapInt(100), heapInt(0)).Split(n, k, prime)
-			Ω(err).ShouldNot(HaveOccurred())
-
-			result := computeDelta(lhs, rhs, n, prime)
-			Ω(result.IsMatch(prime)).Should(Equal(true))
-		})
-
-		It("should find a match when the maximum sell volume is higher than the maximum buy volume", func() {
-			lhs, err := order.NewOrder(order.TypeLimit, order.ParityBuy, time.Now().Add(time.Hour), order.CurrencyCodeBTC, order.CurrencyCodeETH, heapInt(10), heapInt(100), heapInt(100), heapInt(0)).Split(n, k, prime)
-			Ω(err).ShouldNot(HaveOccurred())
-
-			rhs, err := order.NewOrder(order.TypeLimit, order.ParitySell, time.Now().Add(time.Hour), order.CurrencyCodeBTC, order.CurrencyCodeETH, heapInt(10), heapInt(100), heapInt(100), heapInt(0)).Split(n, k, prime)
-			Ω(err).ShouldNot(HaveOccurred())
-
-			result := computeDelta(lhs, rhs, n, prime)
-			Ω(result.IsMatch(prime)).Should(Equal(true))
-		})
-
-		It("should find a match when the minimum buy volume is higher than the minimum sell volume", func() {
-			lhs, err := order.NewOrder(order.TypeLimit, order.ParityBuy, time.Now().Add(time.Hour), order.CurrencyCodeBTC, order.CurrencyCodeETH, heapInt(10), heapInt(1000), heapInt(1000), heapInt(0)).Split(n, k, prime)
-			Ω(err).ShouldNot(HaveOccurred())
+			signFragments(lhs)
 
 			rhs, err := order.NewOrder(order.TypeLimit, order.ParitySell, time.Now().Add(time.Hour), order.CurrencyCodeBTC, order.CurrencyCodeETH, heapInt(10), heapInt(1000), heapInt(100), heapInt(0)).Split(n, k, prime)
 			Ω(err).ShouldNot(HaveOccurred())
+			signFragments(rhs)
 
-			result := computeDelta(lhs, rhs, n, prime)
-			Ω(result.IsMatch(prime)).Should(Equal(true))
+			result := NewDelta(computeDeltaFragments(lhs, rhs, n, k, prime, true)[:k], prime)
+			Ω(result.IsMatch()).Should(Equal(true))
 		})
 
-		It("should find a match when the minimum sell volume is higher than the maximum buy volume", func() {
+		It("should not find a match when the match bit is zero", func() {
 			lhs, err := order.NewOrder(order.TypeLimit, order.ParityBuy, time.Now().Add(time.Hour), order.CurrencyCodeBTC, order.CurrencyCodeETH, heapInt(10), heapInt(1000), heapInt(100), heapInt(0)).Split(n, k, prime)
 			Ω(err).ShouldNot(HaveOccurred())
+			signFragments(lhs)
 
-			rhs, err := order.NewOrder(order.TypeLimit, order.ParitySell, time.Now().Add(time.Hour), order.CurrencyCodeBTC, order.CurrencyCodeETH, heapInt(10), heapInt(1000), heapInt(1000), heapInt(0)).Split(n, k, prime)
+			rhs, err := order.NewOrder(order.TypeLimit, order.ParitySell, time.Now().Add(time.Hour), order.CurrencyCodeBTC, order.CurrencyCodeETH, heapInt(12), heapInt(1000), heapInt(100), heapInt(0)).Split(n, k, prime)
 			Ω(err).ShouldNot(HaveOccurred())
+			signFragments(rhs)
 
-			result := computeDelta(lhs, rhs, n, prime)
-			Ω(result.IsMatch(prime)).Should(Equal(true))
+			result := NewDelta(computeDeltaFragments(lhs, rhs, n, k, prime, false)[:k], prime)
+			Ω(result.IsMatch()).Should(Equal(false))
 		})
 
-		It("should not find a match when the maximum buy volume is lower than the minimum sell volume", func() {
-			lhs, err := order.NewOrder(order.TypeLimit, order.ParityBuy, time.Now().Add(time.Hour), order.CurrencyCodeBTC, order.CurrencyCodeETH, heapInt(10), heapInt(100), heapInt(100), heapInt(0)).Split(n, k, prime)
-			Ω(err).ShouldNot(HaveOccurred())
-
-			rhs, err := order.NewOrder(order.TypeLimit, order.ParitySell, time.Now().Add(time.Hour), order.CurrencyCodeBTC, order.CurrencyCodeETH, heapInt(10), heapInt(1000), heapInt(1000), heapInt(0)).Split(n, k, prime)
+		It("should not find a match when the match bit is not a bit", func() {
+			lhs, err := order.NewOrder(order.TypeLimit, order.ParityBuy, time.Now().Add(time.Hour), order.CurrencyCodeBTC, order.CurrencyCodeETH, heapInt(10), heapInt(1000), heapInt(100), heapInt(0)).Split(n, k, prime)
 			Ω(err).ShouldNot(HaveOccurred())
+			signFragments(lhs)
 
-			result := computeDelta(lhs, rhs, n, prime)
-			Ω(result.IsMatch(prime)).Should(Equal(false))
-		})
-
-		It("should not find a match when the maximum sell volume is lower than the minimum buy volume", func() {
-			lhs, err := order.NewOrder(order.TypeLimit, order.ParityBuy, time.Now().Add(time.Hour), order.CurrencyCodeBTC, order.CurrencyCodeETH, heapInt(10), heapInt(1000), heapInt(1000), heapInt(0)).Split(n, k, prime)
+			rhs, err := order.NewOrder(order.TypeLimit, order.ParitySell, time.Now().Add(time.Hour), order.CurrencyCodeBTC, order.CurrencyCodeETH, heapInt(10), heapInt(1000), heapInt(100), heapInt(0)).Split(n, k, prime)
 			Ω(err).ShouldNot(HaveOccurred())
+			signFragments(rhs)
 
-			rhs, err := order.NewOrder(order.TypeLimit, order.ParitySell, time.Now().Add(time.Hour), order.CurrencyCodeBTC, order.CurrencyCodeETH, heapInt(10), heapInt(100), heapInt(100), heapInt(0)).Split(n, k, prime)
+			two := stackint.Two()
+			matchShares, err := shamir.Split(n, k, prime, &two)
 			Ω(err).ShouldNot(HaveOccurred())
-
-			result := computeDelta(lhs, rhs, n, prime)
-			Ω(result.IsMatch(prime)).Should(Equal(false))
+			deltaFragments := make([]*DeltaFragment, k)
+			for i := range deltaFragments {
+				deltaFragments[i] = NewDeltaFragment(NewDifferenceFragment(lhs[i], rhs[i], prime), matchShares[i])
+			}
+			result := NewDelta(deltaFragments, prime)
+			Ω(result.IsMatch()).Should(Equal(false))
 		})
 	})
 
@@ -266,15 +307,18 @@ var _ = Describe("Delta and delta fragments", func() {
 		It("should return nil for incompatible orders", func() {
 			one, err := order.NewOrder(order.TypeLimit, order.ParityBuy, time.Now().Add(time.Hour), order.CurrencyCodeBTC, order.CurrencyCodeETH, heapInt(10), heapInt(1000), heapInt(100), heapInt(0)).Split(n, k, prime)
 			Ω(err).ShouldNot(HaveOccurred())
+			signFragments(one)
 
 			two, err := order.NewOrder(order.TypeLimit, order.ParitySell, time.Now().Add(time.Hour), order.CurrencyCodeETH, order.CurrencyCodeBTC, heapInt(10), heapInt(1000), heapInt(100), heapInt(0)).Split(n, k, prime)
 			Ω(err).ShouldNot(HaveOccurred())
+			signFragments(two)
 
 			three, err := order.NewOrder(order.TypeLimit, order.ParityBuy, time.Now().Add(time.Hour), order.CurrencyCodeETH, order.CurrencyCodeBTC, heapInt(10), heapInt(1000), heapInt(100), heapInt(1)).Split(n, k, prime)
 			Ω(err).ShouldNot(HaveOccurred())
+			signFragments(three)
 
-			deltaFragment1 := NewDeltaFragment(one[0], two[0], prime)
-			deltaFragment2 := NewDeltaFragment(two[0], three[0], prime)
+			deltaFragment1 := computeDeltaFragments(one, two, n, k, prime, true)[0]
+			deltaFragment2 := computeDeltaFragments(three, two, n, k, prime, true)[0]
 
 			nilDelta := NewDelta([]*DeltaFragment{deltaFragment1, deltaFragment2}, prime)
 			Ω(nilDelta).Should(BeNil())
@@ -290,15 +334,18 @@ var _ = Describe("Delta and delta fragments", func() {
 		It("comparing should return the right result", func() {
 			one, err := order.NewOrder(order.TypeLimit, order.ParityBuy, time.Now().Add(time.Hour), order.CurrencyCodeBTC, order.CurrencyCodeETH, heapInt(10), heapInt(1000), heapInt(100), heapInt(0)).Split(n, k, prime)
 			Ω(err).ShouldNot(HaveOccurred())
+			signFragments(one)
 
 			two, err := order.NewOrder(order.TypeLimit, order.ParitySell, time.Now().Add(time.Hour), order.CurrencyCodeETH, order.CurrencyCodeBTC, heapInt(10), heapInt(1000), heapInt(100), heapInt(0)).Split(n, k, prime)
 			Ω(err).ShouldNot(HaveOccurred())
+			signFragments(two)
 
 			oneClone, err := order.NewOrder(order.TypeLimit, order.ParityBuy, time.Now().Add(time.Hour), order.CurrencyCodeBTC, order.CurrencyCodeETH, heapInt(10), heapInt(1000), heapInt(100), heapInt(0)).Split(n, k, prime)
 			Ω(err).ShouldNot(HaveOccurred())
+			signFragments(oneClone)
 
-			deltaFragsOne := computeDeltaFromOrderFragments(one, two, n, prime)
-			deltaFragsClone := computeDeltaFromOrderFragments(oneClone, two, n, prime)
+			deltaFragsOne := computeDeltaFragments(one, two, n, k, prime, false)
+			deltaFragsClone := computeDeltaFragments(oneClone, two, n, k, prime, false)
 
 			for i := range deltaFragsOne {
 				Ω(deltaFragsOne[i].Equals(deltaFragsOne[i])).Should(BeTrue())
@@ -307,11 +354,32 @@ var _ = Describe("Delta and delta fragments", func() {
 		})
 	})
 
+	Context("when encoding delta fragments", func() {
+
+		It("should return the golden encoding and hash", func() {
+			deltaFragment := &DeltaFragment{
+				ID:                  DeltaFragmentID("deltaFragment"),
+				DeltaID:             DeltaID("delta"),
+				BuyOrderID:          order.ID("buy"),
+				SellOrderID:         order.ID("sell"),
+				BuyOrderFragmentID:  order.FragmentID("buyFragment"),
+				SellOrderFragmentID: order.FragmentID("sellFragment"),
+				CommitmentsHash:     []byte("commitments"),
+				MatchShare:          shamir.Share{Key: 1, Value: stackint.One()},
+			}
+			data, err := deltaFragment.MarshalBinary()
+			Ω(err).ShouldNot(HaveOccurred())
+			Ω(hex.EncodeToString(data)).Should(Equal(goldenDeltaFragmentEncoding))
+			Ω(hex.EncodeToString(deltaFragment.Hash())).Should(Equal(goldenDeltaFragmentHash))
+		})
+	})
+
 	Context("delta fragment IDs", func() {
 		It("can be converted to strings", func() {
 			lhs := order.NewFragment([]byte("lhs"),
 				order.TypeLimit,
 				order.ParityBuy,
+				time.Time{},
 				shamir.Share{Key: 0, Value: stackint.Zero()},
 				shamir.Share{Key: 0, Value: stackint.Zero()},
 				shamir.Share{Key: 0, Value: stackint.Zero()},
@@ -322,6 +390,7 @@ var _ = Describe("Delta and delta fragments", func() {
 			rhs := order.NewFragment([]byte("rhs"),
 				order.TypeLimit,
 				order.ParitySell,
+				time.Time{},
 				shamir.Share{Key: 0, Value: stackint.Zero()},
 				shamir.Share{Key: 0, Value: stackint.Zero()},
 				shamir.Share{Key: 0, Value: stackint.Zero()},
@@ -329,23 +398,107 @@ var _ = Describe("Delta and delta fragments", func() {
 				shamir.Share{Key: 0, Value: stackint.Zero()},
 			)
 
-			frag := NewDeltaFragment(lhs, rhs, prime)
+			lhs.Trader = identity.ID("lhs")
+			rhs.Trader = identity.ID("rhs")
+			frag := NewDifferenceFragment(lhs, rhs, prime)
 
-			Ω(frag.ID.String()).Should(Equal("B3HMD3WLjgMgPBEvPJJ3yBxuRHsXs7T1DaZyx1QZvnbg"))
+			Ω(frag.ID.String()).Should(Equal("A3AAMX1ieDEJXEGwaWBc4VmN396GHgJDEhxNvjFNuUU7"))
 		})
 	})
 
-	Context("constructing delta fragments", func() {
+	Context("constructing difference fragments", func() {
 		It("should return nil for incompatible fragments", func() {
 			lhs, err := order.NewOrder(order.TypeLimit, order.ParityBuy, time.Now().Add(time.Hour), order.CurrencyCodeBTC, order.CurrencyCodeETH, heapInt(10), heapInt(1000), heapInt(100), heapInt(0)).Split(n, k, prime)
 			Ω(err).ShouldNot(HaveOccurred())
+			signFragments(lhs)
 
 			rhs, err := order.NewOrder(order.TypeLimit, order.ParitySell, time.Now().Add(time.Hour), order.CurrencyCodeETH, order.CurrencyCodeBTC, heapInt(10), heapInt(1000), heapInt(100), heapInt(0)).Split(n, k, prime)
 			Ω(err).ShouldNot(HaveOccurred())
+			signFragments(rhs)
 
-			nilFrag := NewDeltaFragment(lhs[0], rhs[1], prime)
+			nilFrag := NewDifferenceFragment(lhs[0], rhs[1], prime)
 			Ω(nilFrag).Should(BeNil())
 		})
+
+		It("should hold shares of the differences between the orders", func() {
+			lhs, err := order.NewOrder(order.TypeLimit, order.ParityBuy, time.Now().Add(time.Hour), order.CurrencyCodeBTC, order.CurrencyCodeETH, heapInt(12), heapInt(1000), heapInt(100), heapInt(0)).Split(n, k, prime)
+			Ω(err).ShouldNot(HaveOccurred())
+			signFragments(lhs)
+
+			rhs, err := order.NewOrder(order.TypeLimit, order.ParitySell, time.Now().Add(time.Hour), order.CurrencyCodeBTC, order.CurrencyCodeETH, heapInt(10), heapInt(800), heapInt(200), heapInt(0)).Split(n, k, prime)
+			Ω(err).ShouldNot(HaveOccurred())
+			signFragments(rhs)
+
+			fstCodeShares := make(shamir.Shares, k)
+			priceShares := make(shamir.Shares, k)
+			maxVolumeShares := make(shamir.Shares, k)
+			minVolumeShares := make(shamir.Shares, k)
+			for i := int64(0); i < k; i++ {
+				differenceFragment := NewDifferenceFragment(lhs[i], rhs[i], prime)
+				Ω(differenceFragment).ShouldNot(BeNil())
+				fstCodeShares[i] = differenceFragment.FstCodeShare
+				priceShares[i] = differenceFragment.PriceShare
+				maxVolumeShares[i] = differenceFragment.MaxVolumeShare
+				minVolumeShares[i] = differenceFragment.MinVolumeShare
+			}
+
+			Ω(shamir.Join(prime, fstCodeShares).IsZero()).Should(BeTrue())
+			Ω(shamir.Join(prime, priceShares).Cmp(heapInt(2))).Should(Equal(0))
+			Ω(shamir.Join(prime, maxVolumeShares).Cmp(heapInt(800))).Should(Equal(0))
+			Ω(shamir.Join(prime, minVolumeShares).Cmp(heapInt(700))).Should(Equal(0))
+		})
+
+		It("should return nil for fragments of orders with different types", func() {
+			lhs, err := order.NewOrder(order.TypeIBBO, order.ParityBuy, time.Now().Add(time.Hour), order.CurrencyCodeBTC, order.CurrencyCodeETH, heapInt(10), heapInt(1000), heapInt(100), heapInt(0)).Split(n, k, prime)
+			Ω(err).ShouldNot(HaveOccurred())
+			signFragments(lhs)
+
+			rhs, err := order.NewOrder(order.TypeLimit, order.ParitySell, time.Now().Add(time.Hour), order.CurrencyCodeBTC, order.CurrencyCodeETH, heapInt(10), heapInt(1000), heapInt(100), heapInt(0)).Split(n, k, prime)
+			Ω(err).ShouldNot(HaveOccurred())
+			signFragments(rhs)
+
+			Ω(NewDifferenceFragment(lhs[0], rhs[0], prime)).Should(BeNil())
+		})
+
+		It("should not compare the prices of IBBO orders", func() {
+			lhs, err := order.NewOrder(order.TypeIBBO, order.ParityBuy, time.Now().Add(time.Hour), order.CurrencyCodeBTC, order.CurrencyCodeETH, heapInt(0), heapInt(1000), heapInt(100), heapInt(0)).Split(n, k, prime)
+			Ω(err).ShouldNot(HaveOccurred())
+			signFragments(lhs)
+
+			rhs, err := order.NewOrder(order.TypeIBBO, order.ParitySell, time.Now().Add(time.Hour), order.CurrencyCodeBTC, order.CurrencyCodeETH, heapInt(10), heapInt(1000), heapInt(100), heapInt(0)).Split(n, k, prime)
+			Ω(err).ShouldNot(HaveOccurred())
+			signFragments(rhs)
+
+			priceShares := make(shamir.Shares, k)
+			for i := int64(0); i < k; i++ {
+				differenceFragment := NewDifferenceFragment(lhs[i], rhs[i], prime)
+				Ω(differenceFragment).ShouldNot(BeNil())
+				priceShares[i] = differenceFragment.PriceShare
+			}
+			Ω(shamir.Join(prime, priceShares).IsZero()).Should(BeTrue())
+		})
+
+		It("should only match fill-or-kill orders with orders that can fill them completely", func() {
+			lhs, err := order.NewOrder(order.TypeLimit, order.ParityBuy, time.Now().Add(time.Hour), order.CurrencyCodeBTC, order.CurrencyCodeETH, heapInt(12), heapInt(1000), heapInt(100), heapInt(0)).WithTimeInForce(order.TimeInForceFOK).Split(n, k, prime)
+			Ω(err).ShouldNot(HaveOccurred())
+			signFragments(lhs)
+
+			rhs, err := order.NewOrder(order.TypeLimit, order.ParitySell, time.Now().Add(time.Hour), order.CurrencyCodeBTC, order.CurrencyCodeETH, heapInt(10), heapInt(800), heapInt(200), heapInt(0)).Split(n, k, prime)
+			Ω(err).ShouldNot(HaveOccurred())
+			signFragments(rhs)
+
+			minVolumeShares := make(shamir.Shares, k)
+			for i := int64(0); i < k; i++ {
+				differenceFragment := NewDifferenceFragment(lhs[i], rhs[i], prime)
+				Ω(differenceFragment).ShouldNot(BeNil())
+				minVolumeShares[i] = differenceFragment.MinVolumeShare
+			}
+
+			// The sell order can only fill 800 of the 1000 that the buy
+			// order must be filled with, so the difference is negative
+			expected := prime.Sub(heapInt(200))
+			Ω(shamir.Join(prime, minVolumeShares).Cmp(&expected)).Should(Equal(0))
+		})
 	})
 
 })
@@ -364,26 +517,105 @@ func computeRandomDelta(n, k int64, prime *stackint.Int1024) (*Delta, error) {
 	if err != nil {
 		return nil, err
 	}
+	signFragments(lhs)
 	rhs, err := order.NewOrder(order.TypeLimit, order.ParitySell, time.Now().Add(time.Hour), order.CurrencyCodeBTC, order.CurrencyCodeETH, &randomPrice, heapInt(1000), heapInt(100), &randomNonce).Split(n, k, prime)
 	if err != nil {
 		return nil, err
 	}
-	return computeDelta(lhs, rhs, n, prime), nil
+	signFragments(rhs)
+	return NewDelta(computeDeltaFragments(lhs, rhs, n, k, prime, true), prime), nil
 }
 
-func computeDeltaFromOrderFragments(lhs []*order.Fragment, rhs []*order.Fragment, n int64, prime *stackint.Int1024) []*DeltaFragment {
+// computeDeltaFragments returns the DeltaFragments for two orders, sharing the
+// given match bit in place of running the secure comparison.
+func computeDeltaFragments(lhs []*order.Fragment, rhs []*order.Fragment, n, k int64, prime *stackint.Int1024, match bool) []*DeltaFragment {
+	bit := stackint.Zero()
+	if match {
+		bit = stackint.One()
+	}
+	matchShares, err := shamir.Split(n, k, prime, &bit)
+	if err != nil {
+		return nil
+	}
 	deltaFragments := make([]*DeltaFragment, n)
 	for i := range deltaFragments {
-		deltaFragment := NewDeltaFragment(lhs[i], rhs[i], prime)
-		if deltaFragment == nil {
+		differenceFragment := NewDifferenceFragment(lhs[i], rhs[i], prime)
+		if differenceFragment == nil {
 			return nil
 		}
-		deltaFragments[i] = deltaFragment
+		deltaFragments[i] = NewDeltaFragment(differenceFragment, matchShares[i])
 	}
 	return deltaFragments
 }
 
-func computeDelta(lhs []*order.Fragment, rhs []*order.Fragment, n int64, prime *stackint.Int1024) *Delta {
-	deltaFragments := computeDeltaFromOrderFragments(lhs, rhs, n, prime)
-	return NewDelta(deltaFragments, prime)
+// BenchmarkDeltaBuilder measures the throughput of a DeltaBuilder that
+// reconstructs deltas from all of the delta fragments in a dark pool of 24
+// dark nodes.
+func BenchmarkDeltaBuilder(b *testing.B) {
+	n, k := int64(24), int64(16)
+	prime, _ := stackint.FromString("179769313486231590772930519078902473361797697894230657273430081157732675805500963132708477322407536021120113879871393357658789768814416622492847430639474124377767893424865485276302219601246094119453082952085005768838150682342462881473913110540827237163350510684586298239947245938479716304835356329624224137111")
+	deltaFragments := benchmarkDeltaFragments(b.N, n, k, &prime)
+
+	builder := NewDeltaBuilder(k, &prime)
+	builder.SetFaults((n - k) / 2)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		var delta *Delta
+		for _, deltaFragment := range deltaFragments[i] {
+			if d := builder.InsertDeltaFragment(deltaFragment); d != nil {
+				delta = d
+			}
+		}
+		if delta == nil || !delta.IsMatch() {
+			b.Fatal("expected a matching delta")
+		}
+	}
+}
+
+// BenchmarkNewDelta measures the reconstruction of deltas without reusing
+// Lagrange coefficients.
+func BenchmarkNewDelta(b *testing.B) {
+	n, k := int64(24), int64(16)
+	prime, _ := stackint.FromString("179769313486231590772930519078902473361797697894230657273430081157732675805500963132708477322407536021120113879871393357658789768814416622492847430639474124377767893424865485276302219601246094119453082952085005768838150682342462881473913110540827237163350510684586298239947245938479716304835356329624224137111")
+	deltaFragments := benchmarkDeltaFragments(b.N, n, k, &prime)
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if delta := NewDelta(deltaFragments[i][:k], &prime); delta == nil || !delta.IsMatch() {
+			b.Fatal("expected a matching delta")
+		}
+	}
 }
+
+// benchmarkDeltaFragments returns the delta fragments for m different deltas,
+// without computing any orders.
+func benchmarkDeltaFragments(m int, n, k int64, prime *stackint.Int1024) [][]*DeltaFragment {
+	one := stackint.One()
+	deltaFragments := make([][]*DeltaFragment, m)
+	for i := range deltaFragments {
+		matchShares, err := shamir.Split(n, k, prime, &one)
+		if err != nil {
+			panic(err)
+		}
+		buyOrderID := make(order.ID, 32)
+		binary.LittleEndian.PutUint64(buyOrderID, uint64(i))
+		sellOrderID := order.ID(crypto.Keccak256(buyOrderID))
+		deltaID := DeltaID(crypto.Keccak256(buyOrderID, sellOrderID))
+
+		deltaFragments[i] = make([]*DeltaFragment, n)
+		for j := range deltaFragments[i] {
+			deltaFragments[i][j] = &DeltaFragment{
+				ID:          DeltaFragmentID(crypto.Keccak256(deltaID, matchShares[j].Value.Bytes())),
+				DeltaID:     deltaID,
+				BuyOrderID:  buyOrderID,
+				SellOrderID: sellOrderID,
+				MatchShare:  matchShares[j],
+			}
+		}
+	}
+	return deltaFragments
+}
+
+const goldenDeltaFragmentEncoding = "032300000052657075626c69632050726f746f636f6c3a2064656c746120667261676d656e743a200d00000064656c7461467261676d656e740500000064656c7461030000006275790400000073656c6c0b000000627579467261676d656e740c00000073656c6c467261676d656e740b000000636f6d6d69746d656e747301000000000000000100000001"
+
+const goldenDeltaFragmentHash = "23dc8ced00eefb70588719d4aed52d2a7bdaaae30663b4f9fcec5fc7413ed271"
@@ -0,0 +1,111 @@
+package compute
+
+import (
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/republicprotocol/republic-go/order"
+	"github.com/republicprotocol/republic-go/shamir"
+	"github.com/republicprotocol/republic-go/stackint"
+)
+
+// A DifferenceFragment holds the shares of the differences between a buy order
+// and a sell order. It is computed locally from two order fragments, and is the
+// input to the secure comparison that produces a DeltaFragment. Any k
+// DifferenceFragments reveal the differences between the two orders, so they
+// must never be sent to other dark nodes. The CommitmentsHash is the hash of
+// the Commitments that the two order fragments were verified against.
+type DifferenceFragment struct {
+	ID                  DeltaFragmentID
+	DeltaID             DeltaID
+	BuyOrderID          order.ID
+	SellOrderID         order.ID
+	BuyOrderFragmentID  order.FragmentID
+	SellOrderFragmentID order.FragmentID
+	CommitmentsHash     []byte
+
+	FstCodeShare   shamir.Share
+	SndCodeShare   shamir.Share
+	PriceShare     shamir.Share
+	MaxVolumeShare shamir.Share
+	MinVolumeShare shamir.Share
+}
+
+// NewDifferenceFragment combines two order fragments, taking:
+// 1) the difference of the buy code, sell code and price
+// 2) the buy's max volume minus the sell's min volume
+// 3) the sell's max volume minus the buy's min volume
+// The orders match when the code differences are zero and all other
+// differences are not negative. The prices of IBBO orders are pegged to the
+// same midpoint, so their price difference is always zero. The min volume of a
+// fill-or-kill order is its max volume, so that it only matches orders that
+// can fill it completely.
+func NewDifferenceFragment(left *order.Fragment, right *order.Fragment, prime *stackint.Int1024) *DifferenceFragment {
+	if !left.IsCompatible(right) {
+		return nil
+	}
+
+	var buyOrderFragment *order.Fragment
+	var sellOrderFragment *order.Fragment
+	if left.OrderParity == order.ParityBuy {
+		buyOrderFragment = left
+		sellOrderFragment = right
+	} else {
+		buyOrderFragment = right
+		sellOrderFragment = left
+	}
+
+	fstCodeShare := shamir.Share{
+		Key:   buyOrderFragment.FstCodeShare.Key,
+		Value: buyOrderFragment.FstCodeShare.Value.CtSubModulo(&sellOrderFragment.FstCodeShare.Value, prime),
+	}
+
+	sndCodeShare := shamir.Share{
+		Key:   buyOrderFragment.SndCodeShare.Key,
+		Value: buyOrderFragment.SndCodeShare.Value.CtSubModulo(&sellOrderFragment.SndCodeShare.Value, prime),
+	}
+
+	priceShare := shamir.Share{
+		Key:   buyOrderFragment.PriceShare.Key,
+		Value: buyOrderFragment.PriceShare.Value.CtSubModulo(&sellOrderFragment.PriceShare.Value, prime),
+	}
+
+	if buyOrderFragment.OrderType == order.TypeIBBO {
+		priceShare.Value = stackint.Zero()
+	}
+
+	buyMinVolumeShare := requiredVolumeShare(buyOrderFragment)
+	sellMinVolumeShare := requiredVolumeShare(sellOrderFragment)
+
+	maxVolumeShare := shamir.Share{
+		Key:   buyOrderFragment.MaxVolumeShare.Key,
+		Value: buyOrderFragment.MaxVolumeShare.Value.CtSubModulo(&sellMinVolumeShare.Value, prime),
+	}
+
+	minVolumeShare := shamir.Share{
+		Key:   buyOrderFragment.MinVolumeShare.Key,
+		Value: sellOrderFragment.MaxVolumeShare.Value.CtSubModulo(&buyMinVolumeShare.Value, prime),
+	}
+
+	return &DifferenceFragment{
+		ID:                  DeltaFragmentID(crypto.Keccak256([]byte(buyOrderFragment.ID), []byte(sellOrderFragment.ID))),
+		DeltaID:             DeltaID(crypto.Keccak256([]byte(buyOrderFragment.OrderID), []byte(sellOrderFragment.OrderID))),
+		BuyOrderID:          buyOrderFragment.OrderID,
+		SellOrderID:         sellOrderFragment.OrderID,
+		BuyOrderFragmentID:  buyOrderFragment.ID,
+		SellOrderFragmentID: sellOrderFragment.ID,
+		FstCodeShare:        fstCodeShare,
+		SndCodeShare:        sndCodeShare,
+		PriceShare:          priceShare,
+		MaxVolumeShare:      maxVolumeShare,
+		MinVolumeShare:      minVolumeShare,
+	}
+}
+
+// requiredVolumeShare returns the share of the volume that an order must be
+// filled with. This is the max volume of fill-or-kill orders, and the min
+// volume of all other orders.
+func requiredVolumeShare(orderFragment *order.Fragment) shamir.Share {
+	if orderFragment.OrderTimeInForce == order.TimeInForceFOK {
+		return orderFragment.MaxVolumeShare
+	}
+	return orderFragment.MinVolumeShare
+}
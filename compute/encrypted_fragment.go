@@ -0,0 +1,76 @@
+package compute
+
+import (
+	"time"
+
+	"github.com/republicprotocol/go-do"
+	"github.com/republicprotocol/republic-go/identity"
+	"github.com/republicprotocol/republic-go/order"
+)
+
+// An EncryptedFragmentTable holds the order fragments that traders encrypted
+// for the other dark nodes in a dark pool. A dark node that was not running
+// when an order was opened can request its encrypted order fragment from the
+// rest of the dark pool. Encrypted order fragments are held until their order
+// expires.
+type EncryptedFragmentTable struct {
+	do.GuardedObject
+
+	encryptedFragments map[string]map[string]*order.EncryptedFragment
+}
+
+// NewEncryptedFragmentTable returns an empty EncryptedFragmentTable.
+func NewEncryptedFragmentTable() *EncryptedFragmentTable {
+	return &EncryptedFragmentTable{
+		GuardedObject:      do.NewGuardedObject(),
+		encryptedFragments: map[string]map[string]*order.EncryptedFragment{},
+	}
+}
+
+// InsertEncryptedFragment inserts an encrypted order fragment. Only one
+// encrypted order fragment is held for each dark node and order.
+func (table *EncryptedFragmentTable) InsertEncryptedFragment(encryptedFragment *order.EncryptedFragment) {
+	table.Enter(nil)
+	defer table.Exit()
+
+	to := string(encryptedFragment.To)
+	if _, ok := table.encryptedFragments[to]; !ok {
+		table.encryptedFragments[to] = map[string]*order.EncryptedFragment{}
+	}
+	if _, ok := table.encryptedFragments[to][string(encryptedFragment.OrderID)]; ok {
+		return
+	}
+	table.encryptedFragments[to][string(encryptedFragment.OrderID)] = encryptedFragment
+}
+
+// EncryptedFragments returns all encrypted order fragments for the dark node
+// with the given ID.
+func (table *EncryptedFragmentTable) EncryptedFragments(to identity.ID) []*order.EncryptedFragment {
+	table.EnterReadOnly(nil)
+	defer table.ExitReadOnly()
+
+	encryptedFragments := make([]*order.EncryptedFragment, 0, len(table.encryptedFragments[string(to)]))
+	for _, encryptedFragment := range table.encryptedFragments[string(to)] {
+		encryptedFragments = append(encryptedFragments, encryptedFragment)
+	}
+	return encryptedFragments
+}
+
+// RemoveExpiredEncryptedFragments removes all encrypted order fragments whose
+// orders have expired at the given time.
+func (table *EncryptedFragmentTable) RemoveExpiredEncryptedFragments(now time.Time) {
+	table.Enter(nil)
+	defer table.Exit()
+
+	for to, encryptedFragments := range table.encryptedFragments {
+		for orderID, encryptedFragment := range encryptedFragments {
+			if now.Before(encryptedFragment.OrderExpiry) {
+				continue
+			}
+			delete(encryptedFragments, orderID)
+		}
+		if len(encryptedFragments) == 0 {
+			delete(table.encryptedFragments, to)
+		}
+	}
+}
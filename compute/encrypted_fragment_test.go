@@ -0,0 +1,42 @@
+package compute_test
+
+import (
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	. "github.com/republicprotocol/republic-go/compute"
+	"github.com/republicprotocol/republic-go/identity"
+	"github.com/republicprotocol/republic-go/order"
+)
+
+var _ = Describe("Encrypted fragment tables", func() {
+
+	now := time.Now()
+
+	It("should return the encrypted order fragments of a dark node", func() {
+		table := NewEncryptedFragmentTable()
+		table.InsertEncryptedFragment(order.NewEncryptedFragment(identity.ID("node"), order.ID("buy"), now.Add(time.Hour), []byte("buy")))
+		table.InsertEncryptedFragment(order.NewEncryptedFragment(identity.ID("node"), order.ID("sell"), now.Add(time.Hour), []byte("sell")))
+		table.InsertEncryptedFragment(order.NewEncryptedFragment(identity.ID("node"), order.ID("sell"), now.Add(time.Hour), []byte("other")))
+		table.InsertEncryptedFragment(order.NewEncryptedFragment(identity.ID("other"), order.ID("buy"), now.Add(time.Hour), []byte("buy")))
+
+		encryptedFragments := table.EncryptedFragments(identity.ID("node"))
+		Ω(encryptedFragments).Should(HaveLen(2))
+		for _, encryptedFragment := range encryptedFragments {
+			Ω(encryptedFragment.Ciphertext).Should(Equal([]byte(encryptedFragment.OrderID)))
+		}
+		Ω(table.EncryptedFragments(identity.ID("unknown"))).Should(BeEmpty())
+	})
+
+	It("should remove expired encrypted order fragments", func() {
+		table := NewEncryptedFragmentTable()
+		table.InsertEncryptedFragment(order.NewEncryptedFragment(identity.ID("node"), order.ID("buy"), now.Add(time.Minute), []byte("buy")))
+		table.InsertEncryptedFragment(order.NewEncryptedFragment(identity.ID("node"), order.ID("sell"), now.Add(time.Hour), []byte("sell")))
+
+		table.RemoveExpiredEncryptedFragments(now.Add(time.Minute))
+		encryptedFragments := table.EncryptedFragments(identity.ID("node"))
+		Ω(encryptedFragments).Should(HaveLen(1))
+		Ω(encryptedFragments[0].OrderID).Should(Equal(order.ID("sell")))
+	})
+})
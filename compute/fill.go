@@ -0,0 +1,429 @@
+package compute
+
+import (
+	"time"
+
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/republicprotocol/go-do"
+	"github.com/republicprotocol/republic-go/order"
+	"github.com/republicprotocol/republic-go/shamir"
+	"github.com/republicprotocol/republic-go/stackint"
+)
+
+// Stages of a Fill. Each stage reconstructs one value from DeltaFragments
+// that are broadcast to the dark pool, in the same way that a Delta is
+// reconstructed.
+var (
+	// fillBuyVolumeStage reconstructs 1 when the maximum volume of the buy
+	// order is greater than, or equal to, the maximum volume of the sell
+	// order, and 0 otherwise.
+	fillBuyVolumeStage = []byte("Republic Protocol: fill: buy volume")
+
+	// fillSellVolumeStage reconstructs 1 when the maximum volume of the sell
+	// order is greater than, or equal to, the maximum volume of the buy order,
+	// and 0 otherwise.
+	fillSellVolumeStage = []byte("Republic Protocol: fill: sell volume")
+
+	// fillVolumeStage reconstructs the executed volume, which is the maximum
+	// volume of the smaller order.
+	fillVolumeStage = []byte("Republic Protocol: fill: volume")
+
+	// fillFstCodeStage and fillSndCodeStage reconstruct the currency codes of
+	// IBBO orders, so that the midpoint of their market can be found.
+	fillFstCodeStage = []byte("Republic Protocol: fill: fst code")
+	fillSndCodeStage = []byte("Republic Protocol: fill: snd code")
+)
+
+// A Fill settles a match between a buy order and a sell order. The executed
+// Volume is the smaller of the two maximum volumes. The remaining volume of
+// the larger order stays active as a residual order, unless both orders had
+// the same maximum volume.
+type Fill struct {
+	ID          DeltaID
+	BuyOrderID  order.ID
+	SellOrderID order.ID
+	Volume      *stackint.Int1024
+
+	// FstCode and SndCode are the currencies of a fill between IBBO orders,
+	// which are executed at the midpoint of their market. Both are zero for
+	// all other fills, or when this dark node does not hold fragments for both
+	// orders.
+	FstCode order.CurrencyCode
+	SndCode order.CurrencyCode
+
+	// OpenTime is the later of the open times of IBBO orders, at which the
+	// midpoint of their market is taken. The orders can only match once both
+	// of them are open, so this is when the match became possible. It is zero
+	// whenever FstCode and SndCode are zero.
+	OpenTime time.Time
+
+	// ResidualOrderID is the ID of the residual order, or nil when both
+	// orders were filled completely.
+	ResidualOrderID order.ID
+
+	// Residual is the order fragment of the residual order, computed from
+	// the Parent order fragment. Both are nil when there is no residual order,
+	// or when this dark node does not hold fragments for both orders.
+	Residual *order.Fragment
+	Parent   *order.Fragment
+}
+
+// IsFillDeltaFragment returns true if the DeltaFragment is part of a Fill,
+// instead of being part of a Delta.
+func IsFillDeltaFragment(deltaFragment *DeltaFragment) bool {
+	_, ok := fillStage(deltaFragment)
+	return ok
+}
+
+// fillStage returns the stage of a Fill that the DeltaFragment belongs to.
+func fillStage(deltaFragment *DeltaFragment) ([]byte, bool) {
+	deltaID := DeltaID(crypto.Keccak256(deltaFragment.BuyOrderID, deltaFragment.SellOrderID))
+	for _, stage := range [][]byte{fillBuyVolumeStage, fillSellVolumeStage, fillVolumeStage, fillFstCodeStage, fillSndCodeStage} {
+		if deltaFragment.DeltaID.Equal(fillDeltaID(deltaID, stage)) {
+			return stage, true
+		}
+	}
+	return nil, false
+}
+
+func fillDeltaID(deltaID DeltaID, stage []byte) DeltaID {
+	return DeltaID(crypto.Keccak256(stage, deltaID))
+}
+
+func fillDeltaFragmentID(buyOrderFragment, sellOrderFragment *order.Fragment, stage []byte) DeltaFragmentID {
+	return DeltaFragmentID(crypto.Keccak256(stage, buyOrderFragment.ID, sellOrderFragment.ID))
+}
+
+// fill is the state of a Fill that is in progress.
+type fill struct {
+	deltaID           DeltaID
+	buyOrderID        order.ID
+	sellOrderID       order.ID
+	buyOrderFragment  *order.Fragment
+	sellOrderFragment *order.Fragment
+	commitmentsHash   []byte
+	opened            bool
+	values            map[string]*stackint.Int1024
+	deltaFragments    map[string]map[string]*DeltaFragment
+}
+
+// A FillBuilder settles matches between buy orders and sell orders. For each
+// match, it uses the secure comparison to find the larger order, and then
+// reconstructs the executed volume from the maximum volume of the smaller
+// order. Nothing else about the volumes of the orders is revealed. For
+// matches between IBBO orders, it also reconstructs the currency codes.
+type FillBuilder struct {
+	do.GuardedObject
+
+	k                    int64
+	faults               int64
+	prime                *stackint.Int1024
+	lagranges            *shamir.LagrangeCache
+	fills                map[string]*fill
+	filled               map[string]bool
+	faultyDeltaFragments map[string][]*DeltaFragment
+}
+
+// NewFillBuilder returns a new FillBuilder which reconstructs each stage of a
+// Fill when it receives k DeltaFragments.
+func NewFillBuilder(k int64, prime *stackint.Int1024) *FillBuilder {
+	return &FillBuilder{
+		GuardedObject:        do.NewGuardedObject(),
+		k:                    k,
+		prime:                prime,
+		lagranges:            shamir.NewLagrangeCache(prime),
+		fills:                map[string]*fill{},
+		filled:               map[string]bool{},
+		faultyDeltaFragments: map[string][]*DeltaFragment{},
+	}
+}
+
+// Start a Fill for a match between the orders of two order fragments. It
+// returns the DifferenceFragments that must be securely compared to find the
+// larger order. The DeltaFragments produced by the comparisons must be
+// broadcast to the dark pool and inserted into the FillBuilder. If the larger
+// order is already known, no DifferenceFragments are returned and the
+// DeltaFragments that open the Fill are returned instead. The commitments hash
+// is the hash of the Commitments that the order fragments were verified
+// against, and it is bound to every DeltaFragment of the Fill.
+func (builder *FillBuilder) Start(buyOrderFragment, sellOrderFragment *order.Fragment, commitmentsHash []byte) ([]*DifferenceFragment, []*DeltaFragment) {
+	builder.Enter(nil)
+	defer builder.Exit()
+	return builder.start(buyOrderFragment, sellOrderFragment, commitmentsHash)
+}
+
+func (builder *FillBuilder) start(buyOrderFragment, sellOrderFragment *order.Fragment, commitmentsHash []byte) ([]*DifferenceFragment, []*DeltaFragment) {
+	deltaID := DeltaID(crypto.Keccak256(buyOrderFragment.OrderID, sellOrderFragment.OrderID))
+	if builder.filled[string(deltaID)] {
+		return []*DifferenceFragment{}, nil
+	}
+	f := builder.fill(deltaID, buyOrderFragment.OrderID, sellOrderFragment.OrderID)
+	if f.buyOrderFragment != nil {
+		return []*DifferenceFragment{}, nil
+	}
+	f.buyOrderFragment = buyOrderFragment
+	f.sellOrderFragment = sellOrderFragment
+	f.commitmentsHash = commitmentsHash
+	if f.hasValue(fillBuyVolumeStage) && f.hasValue(fillSellVolumeStage) {
+		return []*DifferenceFragment{}, builder.open(f)
+	}
+
+	key := buyOrderFragment.MaxVolumeShare.Key
+	return []*DifferenceFragment{
+		newFillDifferenceFragment(f, fillBuyVolumeStage, shamir.Share{
+			Key:   key,
+			Value: buyOrderFragment.MaxVolumeShare.Value.CtSubModulo(&sellOrderFragment.MaxVolumeShare.Value, builder.prime),
+		}),
+		newFillDifferenceFragment(f, fillSellVolumeStage, shamir.Share{
+			Key:   key,
+			Value: sellOrderFragment.MaxVolumeShare.Value.CtSubModulo(&buyOrderFragment.MaxVolumeShare.Value, builder.prime),
+		}),
+	}, nil
+}
+
+// newFillDifferenceFragment returns a DifferenceFragment that is not negative
+// when the given share is not negative. All other differences are zero, which
+// is a valid share of zero for every key.
+func newFillDifferenceFragment(f *fill, stage []byte, maxVolumeShare shamir.Share) *DifferenceFragment {
+	zero := shamir.Share{
+		Key:   maxVolumeShare.Key,
+		Value: stackint.Zero(),
+	}
+	return &DifferenceFragment{
+		ID:                  fillDeltaFragmentID(f.buyOrderFragment, f.sellOrderFragment, stage),
+		DeltaID:             fillDeltaID(f.deltaID, stage),
+		BuyOrderID:          f.buyOrderID,
+		SellOrderID:         f.sellOrderID,
+		BuyOrderFragmentID:  f.buyOrderFragment.ID,
+		SellOrderFragmentID: f.sellOrderFragment.ID,
+		CommitmentsHash:     f.commitmentsHash,
+		FstCodeShare:        zero,
+		SndCodeShare:        zero,
+		PriceShare:          zero,
+		MaxVolumeShare:      maxVolumeShare,
+		MinVolumeShare:      zero,
+	}
+}
+
+// InsertDeltaFragment inserts a DeltaFragment for a stage of a Fill. When the
+// larger order is known, it returns the DeltaFragments that open the executed
+// volume, and the currency codes of IBBO orders, which must be broadcast to
+// the dark pool and inserted into the FillBuilder. When all opened values are
+// known, it returns the Fill. Each Fill is returned exactly once. Each stage
+// is reconstructed once k + faults DeltaFragments are consistent, correcting
+// faulty DeltaFragments, in the same way as the DeltaBuilder.
+func (builder *FillBuilder) InsertDeltaFragment(deltaFragment *DeltaFragment) ([]*DeltaFragment, *Fill) {
+	builder.Enter(nil)
+	defer builder.Exit()
+	return builder.insertDeltaFragment(deltaFragment)
+}
+
+func (builder *FillBuilder) insertDeltaFragment(deltaFragment *DeltaFragment) ([]*DeltaFragment, *Fill) {
+	stage, ok := fillStage(deltaFragment)
+	if !ok {
+		return nil, nil
+	}
+	deltaID := DeltaID(crypto.Keccak256(deltaFragment.BuyOrderID, deltaFragment.SellOrderID))
+	if builder.filled[string(deltaID)] {
+		return nil, nil
+	}
+	f := builder.fill(deltaID, deltaFragment.BuyOrderID, deltaFragment.SellOrderID)
+	if f.hasValue(stage) {
+		return nil, nil
+	}
+	if _, ok := f.deltaFragments[string(stage)]; !ok {
+		f.deltaFragments[string(stage)] = map[string]*DeltaFragment{}
+	}
+	for _, other := range f.deltaFragments[string(stage)] {
+		if other.MatchShare.Key == deltaFragment.MatchShare.Key && !other.ID.Equal(deltaFragment.ID) {
+			return nil, nil // Do not accept a second share with the same index from another dark node
+		}
+	}
+	f.deltaFragments[string(stage)][string(deltaFragment.ID)] = deltaFragment
+
+	// Only delta fragments that were computed from the same commitments are
+	// joined
+	deltaFragments := make([]*DeltaFragment, 0, len(f.deltaFragments[string(stage)]))
+	for _, other := range f.deltaFragments[string(stage)] {
+		deltaFragments = append(deltaFragments, other)
+	}
+	deltaFragments = withCommitmentsHash(deltaFragments, deltaFragment.CommitmentsHash)
+	if int64(len(deltaFragments)) < builder.k+builder.faults {
+		return nil, nil
+	}
+
+	shares := make(shamir.Shares, 0, len(deltaFragments))
+	for _, deltaFragment := range deltaFragments {
+		shares = append(shares, deltaFragment.MatchShare)
+	}
+	value, faultyKeys, err := builder.lagranges.JoinOnline(builder.k, builder.faults, shares)
+	if err != nil {
+		return nil, nil // Wait for more delta fragments
+	}
+	for _, deltaFragment := range deltaFragments {
+		for _, key := range faultyKeys {
+			if deltaFragment.MatchShare.Key == key {
+				builder.faultyDeltaFragments[string(deltaID)] = append(builder.faultyDeltaFragments[string(deltaID)], deltaFragment)
+			}
+		}
+	}
+	f.values[string(stage)] = value
+	delete(f.deltaFragments, string(stage))
+
+	if f.isComplete() {
+		builder.filled[string(deltaID)] = true
+		delete(builder.fills, string(deltaID))
+		return nil, builder.newFill(f)
+	}
+	if f.hasValue(fillBuyVolumeStage) && f.hasValue(fillSellVolumeStage) {
+		return builder.open(f), nil
+	}
+	return nil, nil
+}
+
+// open returns the DeltaFragments that open the maximum volume of the smaller
+// order, and the currency codes of IBBO orders, or nil if this dark node
+// cannot open them.
+func (builder *FillBuilder) open(f *fill) []*DeltaFragment {
+	if f.opened || f.buyOrderFragment == nil {
+		return nil
+	}
+	f.opened = true
+
+	volumeShare := f.sellOrderFragment.MaxVolumeShare
+	if !f.isLarger(fillBuyVolumeStage) {
+		volumeShare = f.buyOrderFragment.MaxVolumeShare
+	}
+	openings := []*DeltaFragment{newFillDeltaFragment(f, fillVolumeStage, volumeShare)}
+	if f.isPegged() {
+		// The codes of both orders are equal, because the orders matched
+		openings = append(openings,
+			newFillDeltaFragment(f, fillFstCodeStage, f.buyOrderFragment.FstCodeShare),
+			newFillDeltaFragment(f, fillSndCodeStage, f.buyOrderFragment.SndCodeShare))
+	}
+	return openings
+}
+
+// newFillDeltaFragment returns a DeltaFragment that opens the given share for
+// a stage of a Fill.
+func newFillDeltaFragment(f *fill, stage []byte, share shamir.Share) *DeltaFragment {
+	return &DeltaFragment{
+		ID:                  fillDeltaFragmentID(f.buyOrderFragment, f.sellOrderFragment, stage),
+		DeltaID:             fillDeltaID(f.deltaID, stage),
+		BuyOrderID:          f.buyOrderID,
+		SellOrderID:         f.sellOrderID,
+		BuyOrderFragmentID:  f.buyOrderFragment.ID,
+		SellOrderFragmentID: f.sellOrderFragment.ID,
+		CommitmentsHash:     f.commitmentsHash,
+		MatchShare:          share,
+	}
+}
+
+func (builder *FillBuilder) newFill(f *fill) *Fill {
+	result := &Fill{
+		ID:          f.deltaID,
+		BuyOrderID:  f.buyOrderID,
+		SellOrderID: f.sellOrderID,
+		Volume:      f.values[string(fillVolumeStage)],
+	}
+	if f.isPegged() {
+		result.FstCode = f.code(fillFstCodeStage)
+		result.SndCode = f.code(fillSndCodeStage)
+		result.OpenTime = f.buyOrderFragment.OrderOpenTime
+		if f.sellOrderFragment.OrderOpenTime.After(result.OpenTime) {
+			result.OpenTime = f.sellOrderFragment.OrderOpenTime
+		}
+	}
+	buyIsLarger, sellIsLarger := f.isLarger(fillBuyVolumeStage), f.isLarger(fillSellVolumeStage)
+	if buyIsLarger && sellIsLarger {
+		return result
+	}
+	if buyIsLarger {
+		result.ResidualOrderID = order.ResidualID(f.buyOrderID, f.sellOrderID)
+		if f.buyOrderFragment != nil {
+			result.Parent = f.buyOrderFragment
+			result.Residual = f.buyOrderFragment.Residual(f.sellOrderID, f.sellOrderFragment.MaxVolumeShare, builder.prime)
+		}
+		return result
+	}
+	result.ResidualOrderID = order.ResidualID(f.sellOrderID, f.buyOrderID)
+	if f.sellOrderFragment != nil {
+		result.Parent = f.sellOrderFragment
+		result.Residual = f.sellOrderFragment.Residual(f.buyOrderID, f.buyOrderFragment.MaxVolumeShare, builder.prime)
+	}
+	return result
+}
+
+func (builder *FillBuilder) fill(deltaID DeltaID, buyOrderID, sellOrderID order.ID) *fill {
+	if f, ok := builder.fills[string(deltaID)]; ok {
+		return f
+	}
+	f := &fill{
+		deltaID:        deltaID,
+		buyOrderID:     buyOrderID,
+		sellOrderID:    sellOrderID,
+		values:         map[string]*stackint.Int1024{},
+		deltaFragments: map[string]map[string]*DeltaFragment{},
+	}
+	builder.fills[string(deltaID)] = f
+	return f
+}
+
+// FaultyDeltaFragments returns the DeltaFragments that were found to be
+// faulty when the stages of the Fill with the given ID were reconstructed.
+func (builder *FillBuilder) FaultyDeltaFragments(deltaID DeltaID) []*DeltaFragment {
+	builder.EnterReadOnly(nil)
+	defer builder.ExitReadOnly()
+	return builder.faultyDeltaFragments[string(deltaID)]
+}
+
+// SetK updates the required number of DeltaFragments to reconstruct each
+// stage of a Fill.
+func (builder *FillBuilder) SetK(k int64) {
+	builder.Enter(nil)
+	defer builder.Exit()
+	builder.k = k
+}
+
+// SetFaults updates the number of faulty DeltaFragments that must be
+// tolerated when each stage of a Fill is reconstructed. The FillBuilder waits
+// for k + faults consistent DeltaFragments before it reconstructs a stage.
+func (builder *FillBuilder) SetFaults(faults int64) {
+	builder.Enter(nil)
+	defer builder.Exit()
+	builder.faults = faults
+}
+
+func (f *fill) hasValue(stage []byte) bool {
+	_, ok := f.values[string(stage)]
+	return ok
+}
+
+// isPegged returns true if the orders are IBBO orders. This is only known when
+// this dark node holds fragments for both orders.
+func (f *fill) isPegged() bool {
+	return f.buyOrderFragment != nil && f.buyOrderFragment.OrderType == order.TypeIBBO
+}
+
+// isComplete returns true when all values that are opened by the Fill are
+// known.
+func (f *fill) isComplete() bool {
+	if !f.hasValue(fillVolumeStage) {
+		return false
+	}
+	return !f.isPegged() || (f.hasValue(fillFstCodeStage) && f.hasValue(fillSndCodeStage))
+}
+
+func (f *fill) code(stage []byte) order.CurrencyCode {
+	code, err := f.values[string(stage)].ToUint()
+	if err != nil {
+		return 0
+	}
+	return order.CurrencyCode(code)
+}
+
+func (f *fill) isLarger(stage []byte) bool {
+	one := stackint.One()
+	value, ok := f.values[string(stage)]
+	return ok && value.Cmp(&one) == 0
+}
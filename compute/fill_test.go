@@ -0,0 +1,231 @@
+package compute_test
+
+import (
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	. "github.com/republicprotocol/republic-go/compute"
+	"github.com/republicprotocol/republic-go/identity"
+	"github.com/republicprotocol/republic-go/order"
+	"github.com/republicprotocol/republic-go/shamir"
+	"github.com/republicprotocol/republic-go/stackint"
+)
+
+var _ = Describe("Fills", func() {
+
+	n := int64(8)
+	k := int64(6)
+	primeVal, _ := stackint.FromString("179769313486231590772930519078902473361797697894230657273430081157732675805500963132708477322407536021120113879871393357658789768814416622492847430639474124377767893424865485276302219601246094119453082952085005768838150682342462881473913110540827237163350510684586298239947245938479716304835356329624224137111")
+	prime := &primeVal
+
+	// compare replaces the secure comparison of the dark pool by reconstructing
+	// the difference in each DifferenceFragment and sharing the result
+	compare := func(differenceFragments []*DifferenceFragment) []*DeltaFragment {
+		maxVolumeShares := make(shamir.Shares, len(differenceFragments))
+		for i := range differenceFragments {
+			maxVolumeShares[i] = differenceFragments[i].MaxVolumeShare
+		}
+		bound := stackint.FromUint(1 << 62)
+		result := stackint.Zero()
+		if shamir.Join(prime, maxVolumeShares).Cmp(&bound) < 0 {
+			result = stackint.One()
+		}
+		resultShares, err := shamir.Split(n, k, prime, &result)
+		Ω(err).ShouldNot(HaveOccurred())
+
+		deltaFragments := make([]*DeltaFragment, len(differenceFragments))
+		for i := range differenceFragments {
+			deltaFragments[i] = NewDeltaFragment(differenceFragments[i], resultShares[i])
+		}
+		return deltaFragments
+	}
+
+	// fillAt runs a Fill between two orders, opened at the given times, with a
+	// FillBuilder for each dark node. When faulty is true, one of the delta
+	// fragments is corrupted and every FillBuilder must correct and report it.
+	fillAt := func(ty order.Type, buyVolume, sellVolume uint, faulty bool, buyOpenTime, sellOpenTime time.Time) []*Fill {
+		buyOrder := order.NewOrder(ty, order.ParityBuy, time.Now().Add(time.Hour), order.CurrencyCodeBTC, order.CurrencyCodeETH, heapInt(10), heapInt(buyVolume), heapInt(1), heapInt(0)).WithOpenTime(buyOpenTime)
+		buyOrderFragments, err := buyOrder.Split(n, k, prime)
+		Ω(err).ShouldNot(HaveOccurred())
+		signFragments(buyOrderFragments)
+		sellOrder := order.NewOrder(ty, order.ParitySell, time.Now().Add(time.Hour), order.CurrencyCodeBTC, order.CurrencyCodeETH, heapInt(10), heapInt(sellVolume), heapInt(1), heapInt(0)).WithOpenTime(sellOpenTime)
+		sellOrderFragments, err := sellOrder.Split(n, k, prime)
+		Ω(err).ShouldNot(HaveOccurred())
+		signFragments(sellOrderFragments)
+
+		builders := make([]*FillBuilder, n)
+		buyVolumeDifferenceFragments := make([]*DifferenceFragment, n)
+		sellVolumeDifferenceFragments := make([]*DifferenceFragment, n)
+		for i := range builders {
+			builders[i] = NewFillBuilder(k, prime)
+			if faulty {
+				builders[i].SetFaults((n - k) / 2)
+			}
+			differenceFragments, deltaFragments := builders[i].Start(buyOrderFragments[i], sellOrderFragments[i], nil)
+			Ω(differenceFragments).Should(HaveLen(2))
+			Ω(deltaFragments).Should(BeEmpty())
+			buyVolumeDifferenceFragments[i] = differenceFragments[0]
+			sellVolumeDifferenceFragments[i] = differenceFragments[1]
+		}
+		deltaFragments := append(compare(buyVolumeDifferenceFragments), compare(sellVolumeDifferenceFragments)...)
+		for _, deltaFragment := range deltaFragments {
+			Ω(IsFillDeltaFragment(deltaFragment)).Should(BeTrue())
+		}
+		if faulty {
+			one := stackint.One()
+			deltaFragments[2].MatchShare.Value = deltaFragments[2].MatchShare.Value.AddModulo(&one, prime)
+		}
+
+		openings := []*DeltaFragment{}
+		for i := range builders {
+			for _, deltaFragment := range deltaFragments {
+				opened, fill := builders[i].InsertDeltaFragment(deltaFragment)
+				Ω(fill).Should(BeNil())
+				for _, opening := range opened {
+					Ω(IsFillDeltaFragment(opening)).Should(BeTrue())
+					openings = append(openings, opening)
+				}
+			}
+		}
+		if ty == order.TypeIBBO {
+			Ω(openings).Should(HaveLen(3 * int(n)))
+		} else {
+			Ω(openings).Should(HaveLen(int(n)))
+		}
+
+		fills := []*Fill{}
+		for i := range builders {
+			for _, opening := range openings {
+				_, fill := builders[i].InsertDeltaFragment(opening)
+				if fill != nil {
+					fills = append(fills, fill)
+				}
+			}
+		}
+		Ω(fills).Should(HaveLen(int(n)))
+		for _, fill := range fills {
+			Ω(fill.BuyOrderID.Equal(buyOrder.ID)).Should(BeTrue())
+			Ω(fill.SellOrderID.Equal(sellOrder.ID)).Should(BeTrue())
+		}
+		for i := range builders {
+			if faulty {
+				Ω(builders[i].FaultyDeltaFragments(fills[0].ID)).Should(Equal([]*DeltaFragment{deltaFragments[2]}))
+			} else {
+				Ω(builders[i].FaultyDeltaFragments(fills[0].ID)).Should(BeEmpty())
+			}
+		}
+		return fills
+	}
+
+	fill := func(ty order.Type, buyVolume, sellVolume uint, faulty bool) []*Fill {
+		return fillAt(ty, buyVolume, sellVolume, faulty, time.Time{}, time.Time{})
+	}
+
+	residualVolume := func(fills []*Fill) *stackint.Int1024 {
+		maxVolumeShares := make(shamir.Shares, len(fills))
+		for i := range fills {
+			Ω(fills[i].Residual).ShouldNot(BeNil())
+			Ω(fills[i].Residual.OrderID.Equal(fills[i].ResidualOrderID)).Should(BeTrue())
+			Ω(fills[i].Residual.OrderParity).Should(Equal(fills[i].Parent.OrderParity))
+			maxVolumeShares[i] = fills[i].Residual.MaxVolumeShare
+		}
+		return shamir.Join(prime, maxVolumeShares)
+	}
+
+	Context("when filling matched orders", func() {
+
+		It("should leave a residual buy order when the buy order is larger", func() {
+			fills := fill(order.TypeLimit, 1000, 400, false)
+			for _, fill := range fills {
+				Ω(fill.Volume.Cmp(heapInt(400))).Should(Equal(0))
+				Ω(fill.ResidualOrderID.Equal(order.ResidualID(fill.BuyOrderID, fill.SellOrderID))).Should(BeTrue())
+			}
+			Ω(residualVolume(fills).Cmp(heapInt(600))).Should(Equal(0))
+		})
+
+		It("should leave a residual sell order when the sell order is larger", func() {
+			fills := fill(order.TypeLimit, 300, 1000, false)
+			for _, fill := range fills {
+				Ω(fill.Volume.Cmp(heapInt(300))).Should(Equal(0))
+				Ω(fill.ResidualOrderID.Equal(order.ResidualID(fill.SellOrderID, fill.BuyOrderID))).Should(BeTrue())
+			}
+			Ω(residualVolume(fills).Cmp(heapInt(700))).Should(Equal(0))
+		})
+
+		It("should not leave a residual order when both orders are filled completely", func() {
+			fills := fill(order.TypeLimit, 500, 500, false)
+			for _, fill := range fills {
+				Ω(fill.Volume.Cmp(heapInt(500))).Should(Equal(0))
+				Ω(fill.ResidualOrderID).Should(BeNil())
+				Ω(fill.Residual).Should(BeNil())
+			}
+		})
+
+		It("should open the currencies of IBBO orders", func() {
+			fills := fill(order.TypeIBBO, 1000, 400, false)
+			for _, fill := range fills {
+				Ω(fill.Volume.Cmp(heapInt(400))).Should(Equal(0))
+				Ω(fill.FstCode).Should(Equal(order.CurrencyCodeBTC))
+				Ω(fill.SndCode).Should(Equal(order.CurrencyCodeETH))
+			}
+			Ω(residualVolume(fills).Cmp(heapInt(600))).Should(Equal(0))
+		})
+
+		It("should execute IBBO orders at the midpoint in effect when the later order was opened", func() {
+			midpoints := NewMidpointTable()
+			midpoints.SetMidpoint(order.CurrencyCodeBTC, order.CurrencyCodeETH, heapInt(71234))
+			updateTime := time.Now().Add(-time.Minute)
+
+			// Orders opened before the update are executed at the old midpoint,
+			// even if the update arrives first
+			midpoints.InsertMidpointUpdate(NewMidpointUpdate(order.CurrencyCodeBTC, order.CurrencyCodeETH, heapInt(71300), updateTime))
+			for _, fill := range fillAt(order.TypeIBBO, 1000, 400, false, updateTime.Add(-2*time.Minute), updateTime.Add(-time.Second)) {
+				Ω(fill.OpenTime.Unix()).Should(Equal(updateTime.Add(-time.Second).Unix()))
+				midpoint, err := midpoints.MidpointAt(fill.FstCode, fill.SndCode, fill.OpenTime)
+				Ω(err).ShouldNot(HaveOccurred())
+				Ω(midpoint.Cmp(heapInt(71234))).Should(Equal(0))
+			}
+
+			// Orders opened after the update are executed at the new midpoint
+			for _, fill := range fillAt(order.TypeIBBO, 1000, 400, false, updateTime.Add(time.Second), updateTime.Add(-2*time.Minute)) {
+				Ω(fill.OpenTime.Unix()).Should(Equal(updateTime.Add(time.Second).Unix()))
+				midpoint, err := midpoints.MidpointAt(fill.FstCode, fill.SndCode, fill.OpenTime)
+				Ω(err).ShouldNot(HaveOccurred())
+				Ω(midpoint.Cmp(heapInt(71300))).Should(Equal(0))
+			}
+		})
+
+		It("should not open the currencies of limit orders", func() {
+			fills := fill(order.TypeLimit, 1000, 400, false)
+			for _, fill := range fills {
+				Ω(fill.FstCode).Should(BeZero())
+				Ω(fill.SndCode).Should(BeZero())
+			}
+		})
+
+		It("should correct and report faulty delta fragments", func() {
+			fills := fill(order.TypeLimit, 1000, 400, true)
+			for _, fill := range fills {
+				Ω(fill.Volume.Cmp(heapInt(400))).Should(Equal(0))
+			}
+			Ω(residualVolume(fills).Cmp(heapInt(600))).Should(Equal(0))
+		})
+
+		It("should ignore delta fragments that are not part of a fill", func() {
+			zero := shamir.Share{Key: 1, Value: stackint.Zero()}
+			buyOrderFragment := order.NewFragment(order.ID("buy"), order.TypeLimit, order.ParityBuy, time.Time{}, zero, zero, zero, zero, zero)
+			sellOrderFragment := order.NewFragment(order.ID("sell"), order.TypeLimit, order.ParitySell, time.Time{}, zero, zero, zero, zero, zero)
+			buyOrderFragment.Trader = identity.ID("buyer")
+			sellOrderFragment.Trader = identity.ID("seller")
+			differenceFragment := NewDifferenceFragment(buyOrderFragment, sellOrderFragment, prime)
+			deltaFragment := NewDeltaFragment(differenceFragment, zero)
+			Ω(IsFillDeltaFragment(deltaFragment)).Should(BeFalse())
+
+			builder := NewFillBuilder(1, prime)
+			openings, fill := builder.InsertDeltaFragment(deltaFragment)
+			Ω(openings).Should(BeEmpty())
+			Ω(fill).Should(BeNil())
+		})
+	})
+})
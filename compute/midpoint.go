@@ -0,0 +1,189 @@
+package compute
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"sort"
+	"time"
+
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/republicprotocol/go-do"
+	"github.com/republicprotocol/republic-go/identity"
+	"github.com/republicprotocol/republic-go/order"
+	"github.com/republicprotocol/republic-go/stackint"
+)
+
+// ErrMidpointNotFound is returned when the MidpointTable does not hold a
+// midpoint for a market.
+var ErrMidpointNotFound = errors.New("midpoint not found")
+
+// ErrInvalidOpenTime is returned when an IBBO order fragment does not have an
+// open time within the PeggedOrderWindow.
+var ErrInvalidOpenTime = errors.New("invalid open time")
+
+// PeggedOrderWindow is the duration, either side of the time at which a dark
+// node receives an IBBO order fragment, in which the open time of its order
+// must be. Fills between IBBO orders are executed at the midpoint in effect at
+// the open time of the later order, so outside of this window a trader could
+// choose an old midpoint by backdating their order.
+const PeggedOrderWindow = time.Minute
+
+// midpointPrefix is prepended to a MidpointUpdate before hashing so that a
+// MidpointUpdate signature cannot be mistaken for any other signature.
+var midpointPrefix = []byte("Republic Protocol: midpoint: ")
+
+// A MidpointUpdate is a midpoint of a market that is published by a price
+// feed, and signed by it. The midpoint is in effect from its Time until the
+// Time of the next MidpointUpdate for the same market.
+type MidpointUpdate struct {
+	Signature identity.Signature
+	FstCode   order.CurrencyCode
+	SndCode   order.CurrencyCode
+	Midpoint  *stackint.Int1024
+	Time      time.Time
+}
+
+// NewMidpointUpdate returns a new, unsigned, MidpointUpdate that sets the
+// midpoint of a market from the given time.
+func NewMidpointUpdate(fstCode, sndCode order.CurrencyCode, midpoint *stackint.Int1024, t time.Time) *MidpointUpdate {
+	return &MidpointUpdate{
+		FstCode:  fstCode,
+		SndCode:  sndCode,
+		Midpoint: midpoint,
+		Time:     t,
+	}
+}
+
+// Hash returns the Keccak256 hash of a MidpointUpdate. This hash is used to
+// create the signature for a MidpointUpdate.
+func (update *MidpointUpdate) Hash() []byte {
+	return crypto.Keccak256(update.Bytes())
+}
+
+// Sign signs the MidpointUpdate using the provided keypair, and assigns it the
+// MidpointUpdate's Signature field.
+func (update *MidpointUpdate) Sign(keyPair identity.KeyPair) error {
+	var err error
+	update.Signature, err = keyPair.Sign(update)
+	return err
+}
+
+// VerifySignature verifies that the Signature field has been signed by the
+// provided ID's private key, returning an error if the signature is invalid
+func (update *MidpointUpdate) VerifySignature(ID identity.ID) error {
+	return identity.VerifySignature(update, update.Signature, ID)
+}
+
+// Bytes returns a MidpointUpdate serialized into a bytes.
+func (update *MidpointUpdate) Bytes() []byte {
+	buf := new(bytes.Buffer)
+	buf.Write(midpointPrefix)
+	binary.Write(buf, binary.LittleEndian, update.FstCode)
+	binary.Write(buf, binary.LittleEndian, update.SndCode)
+	binary.Write(buf, binary.LittleEndian, update.Time.Unix())
+	buf.Write(update.Midpoint.Bytes())
+	return buf.Bytes()
+}
+
+// midpoint is a midpoint that is in effect from its time.
+type midpoint struct {
+	time  int64
+	value stackint.Int1024
+}
+
+// A MidpointTable holds the reference midpoints of markets, at which fills
+// between IBBO orders are executed. A midpoint is the price halfway between
+// the best bid and the best offer of a market, and is encoded in the same way
+// as the prices of orders. The MidpointTable remembers every MidpointUpdate,
+// so that a fill is executed at the midpoint in effect at the open time of
+// the later order, no matter when the MidpointUpdates arrived. All dark nodes
+// in a dark pool must be supplied with the same midpoints.
+type MidpointTable struct {
+	do.GuardedObject
+
+	midpoints map[[2]order.CurrencyCode][]midpoint
+}
+
+// NewMidpointTable returns an empty MidpointTable.
+func NewMidpointTable() *MidpointTable {
+	return &MidpointTable{
+		GuardedObject: do.NewGuardedObject(),
+		midpoints:     map[[2]order.CurrencyCode][]midpoint{},
+	}
+}
+
+// SetMidpoint sets the midpoint of a market for all time, replacing any
+// previous midpoints.
+func (table *MidpointTable) SetMidpoint(fstCode, sndCode order.CurrencyCode, value *stackint.Int1024) {
+	table.Enter(nil)
+	defer table.Exit()
+	table.midpoints[[2]order.CurrencyCode{fstCode, sndCode}] = []midpoint{{time: time.Time{}.Unix(), value: *value}}
+}
+
+// InsertMidpointUpdate inserts a MidpointUpdate, which sets the midpoint of a
+// market from the Time of the MidpointUpdate. It returns false, and ignores
+// the MidpointUpdate, if the MidpointTable already holds a midpoint for the
+// market at the same Time. Otherwise, it returns true. The caller is
+// responsible for verifying the signature of the MidpointUpdate.
+func (table *MidpointTable) InsertMidpointUpdate(update *MidpointUpdate) bool {
+	table.Enter(nil)
+	defer table.Exit()
+	market := [2]order.CurrencyCode{update.FstCode, update.SndCode}
+	midpoints := table.midpoints[market]
+	t := update.Time.Unix()
+	i := sort.Search(len(midpoints), func(i int) bool {
+		return midpoints[i].time >= t
+	})
+	if i < len(midpoints) && midpoints[i].time == t {
+		return false
+	}
+	midpoints = append(midpoints, midpoint{})
+	copy(midpoints[i+1:], midpoints[i:])
+	midpoints[i] = midpoint{time: t, value: *update.Midpoint}
+	table.midpoints[market] = midpoints
+	return true
+}
+
+// Midpoint returns the latest midpoint of a market. An ErrMidpointNotFound is
+// returned if the MidpointTable does not hold a midpoint for the market.
+func (table *MidpointTable) Midpoint(fstCode, sndCode order.CurrencyCode) (*stackint.Int1024, error) {
+	table.EnterReadOnly(nil)
+	defer table.ExitReadOnly()
+	midpoints := table.midpoints[[2]order.CurrencyCode{fstCode, sndCode}]
+	if len(midpoints) == 0 {
+		return nil, ErrMidpointNotFound
+	}
+	value := midpoints[len(midpoints)-1].value
+	return &value, nil
+}
+
+// MidpointAt returns the midpoint of a market that was in effect at the given
+// time. An ErrMidpointNotFound is returned if the MidpointTable does not hold
+// a midpoint for the market at that time.
+func (table *MidpointTable) MidpointAt(fstCode, sndCode order.CurrencyCode, t time.Time) (*stackint.Int1024, error) {
+	table.EnterReadOnly(nil)
+	defer table.ExitReadOnly()
+	midpoints := table.midpoints[[2]order.CurrencyCode{fstCode, sndCode}]
+	i := sort.Search(len(midpoints), func(i int) bool {
+		return midpoints[i].time > t.Unix()
+	})
+	if i == 0 {
+		return nil, ErrMidpointNotFound
+	}
+	value := midpoints[i-1].value
+	return &value, nil
+}
+
+// InPeggedOrderWindow returns true if the order fragment is not an IBBO order
+// fragment, or if the open time of its order is within the PeggedOrderWindow
+// of the given time. Otherwise, it returns false.
+func InPeggedOrderWindow(orderFragment *order.Fragment, now time.Time) bool {
+	if orderFragment.OrderType != order.TypeIBBO {
+		return true
+	}
+	if orderFragment.OrderOpenTime.IsZero() {
+		return false
+	}
+	return now.Sub(orderFragment.OrderOpenTime) <= PeggedOrderWindow && orderFragment.OrderOpenTime.Sub(now) <= PeggedOrderWindow
+}
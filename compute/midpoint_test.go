@@ -0,0 +1,112 @@
+package compute_test
+
+import (
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	. "github.com/republicprotocol/republic-go/compute"
+	"github.com/republicprotocol/republic-go/identity"
+	"github.com/republicprotocol/republic-go/order"
+)
+
+var _ = Describe("Midpoints", func() {
+
+	It("should return the midpoint of a market", func() {
+		table := NewMidpointTable()
+		table.SetMidpoint(order.CurrencyCodeETH, order.CurrencyCodeBTC, heapInt(71234))
+		table.SetMidpoint(order.CurrencyCodeETH, order.CurrencyCodeBTC, heapInt(71235))
+
+		midpoint, err := table.Midpoint(order.CurrencyCodeETH, order.CurrencyCodeBTC)
+		Ω(err).ShouldNot(HaveOccurred())
+		Ω(midpoint.Cmp(heapInt(71235))).Should(Equal(0))
+	})
+
+	It("should return an error for markets without a midpoint", func() {
+		table := NewMidpointTable()
+		table.SetMidpoint(order.CurrencyCodeETH, order.CurrencyCodeBTC, heapInt(71234))
+
+		_, err := table.Midpoint(order.CurrencyCodeBTC, order.CurrencyCodeETH)
+		Ω(err).Should(Equal(ErrMidpointNotFound))
+	})
+})
+
+var _ = Describe("Midpoint updates", func() {
+
+	It("should update the midpoint of a market from the time of the update", func() {
+		table := NewMidpointTable()
+		table.SetMidpoint(order.CurrencyCodeETH, order.CurrencyCodeBTC, heapInt(71234))
+		now := time.Now()
+		Ω(table.InsertMidpointUpdate(NewMidpointUpdate(order.CurrencyCodeETH, order.CurrencyCodeBTC, heapInt(71300), now))).Should(BeTrue())
+		Ω(table.InsertMidpointUpdate(NewMidpointUpdate(order.CurrencyCodeETH, order.CurrencyCodeBTC, heapInt(71250), now.Add(-time.Minute)))).Should(BeTrue())
+
+		midpoint, err := table.Midpoint(order.CurrencyCodeETH, order.CurrencyCodeBTC)
+		Ω(err).ShouldNot(HaveOccurred())
+		Ω(midpoint.Cmp(heapInt(71300))).Should(Equal(0))
+
+		// Updates that arrive out of order take effect at their own time
+		midpoint, err = table.MidpointAt(order.CurrencyCodeETH, order.CurrencyCodeBTC, now.Add(-2*time.Minute))
+		Ω(err).ShouldNot(HaveOccurred())
+		Ω(midpoint.Cmp(heapInt(71234))).Should(Equal(0))
+		midpoint, err = table.MidpointAt(order.CurrencyCodeETH, order.CurrencyCodeBTC, now.Add(-time.Second))
+		Ω(err).ShouldNot(HaveOccurred())
+		Ω(midpoint.Cmp(heapInt(71250))).Should(Equal(0))
+		midpoint, err = table.MidpointAt(order.CurrencyCodeETH, order.CurrencyCodeBTC, now)
+		Ω(err).ShouldNot(HaveOccurred())
+		Ω(midpoint.Cmp(heapInt(71300))).Should(Equal(0))
+	})
+
+	It("should ignore updates that it already holds", func() {
+		table := NewMidpointTable()
+		now := time.Now()
+		Ω(table.InsertMidpointUpdate(NewMidpointUpdate(order.CurrencyCodeETH, order.CurrencyCodeBTC, heapInt(71300), now))).Should(BeTrue())
+		Ω(table.InsertMidpointUpdate(NewMidpointUpdate(order.CurrencyCodeETH, order.CurrencyCodeBTC, heapInt(71250), now))).Should(BeFalse())
+
+		midpoint, err := table.MidpointAt(order.CurrencyCodeETH, order.CurrencyCodeBTC, now)
+		Ω(err).ShouldNot(HaveOccurred())
+		Ω(midpoint.Cmp(heapInt(71300))).Should(Equal(0))
+	})
+
+	It("should return an error before the first update", func() {
+		table := NewMidpointTable()
+		now := time.Now()
+		table.InsertMidpointUpdate(NewMidpointUpdate(order.CurrencyCodeETH, order.CurrencyCodeBTC, heapInt(71300), now))
+
+		_, err := table.MidpointAt(order.CurrencyCodeETH, order.CurrencyCodeBTC, now.Add(-time.Second))
+		Ω(err).Should(Equal(ErrMidpointNotFound))
+	})
+
+	It("should only verify updates signed by the price feed", func() {
+		priceFeed, err := identity.NewKeyPair()
+		Ω(err).ShouldNot(HaveOccurred())
+		other, err := identity.NewKeyPair()
+		Ω(err).ShouldNot(HaveOccurred())
+
+		update := NewMidpointUpdate(order.CurrencyCodeETH, order.CurrencyCodeBTC, heapInt(71300), time.Now())
+		Ω(update.Sign(priceFeed)).ShouldNot(HaveOccurred())
+		Ω(update.VerifySignature(priceFeed.ID())).ShouldNot(HaveOccurred())
+		Ω(update.VerifySignature(other.ID())).Should(HaveOccurred())
+
+		// Changing the midpoint invalidates the signature
+		update.Midpoint = heapInt(71250)
+		Ω(update.VerifySignature(priceFeed.ID())).Should(HaveOccurred())
+	})
+
+	It("should only accept IBBO order fragments opened within the window", func() {
+		now := time.Now()
+		for _, c := range []struct {
+			ty       order.Type
+			openTime time.Time
+			ok       bool
+		}{
+			{order.TypeLimit, time.Time{}, true},
+			{order.TypeIBBO, now.Add(-time.Second), true},
+			{order.TypeIBBO, time.Time{}, false},
+			{order.TypeIBBO, now.Add(-2 * PeggedOrderWindow), false},
+			{order.TypeIBBO, now.Add(2 * PeggedOrderWindow), false},
+		} {
+			orderFragment := &order.Fragment{OrderType: c.ty, OrderOpenTime: c.openTime}
+			Ω(InPeggedOrderWindow(orderFragment, now)).Should(Equal(c.ok))
+		}
+	})
+})
@@ -0,0 +1,120 @@
+package compute
+
+import (
+	"errors"
+	"time"
+
+	"github.com/republicprotocol/go-do"
+	"github.com/republicprotocol/republic-go/identity"
+	"github.com/republicprotocol/republic-go/order"
+	"github.com/republicprotocol/republic-go/stackint"
+)
+
+// ErrNonceMissing is returned when an order fragment does not hold the nonce
+// of its order.
+var ErrNonceMissing = errors.New("nonce missing")
+
+// ErrNonceUsed is returned when a trader uses a nonce that it has already
+// used for another order fragment whose order has not expired.
+var ErrNonceUsed = errors.New("nonce already used")
+
+// ErrNonceStale is returned when a trader uses a nonce that is not greater
+// than a nonce it used for an order that has expired.
+var ErrNonceStale = errors.New("nonce stale")
+
+// A Nonce is used by a Trader to open an order that expires at the Expiry.
+// The FragmentID identifies the order fragment that used the Nonce.
+type Nonce struct {
+	Trader     identity.ID
+	Value      stackint.Int1024
+	Expiry     time.Time
+	FragmentID order.FragmentID
+}
+
+// NewNonce returns the Nonce used to open the order of an order fragment. An
+// ErrNonceMissing is returned if the order fragment does not hold a nonce.
+func NewNonce(orderFragment *order.Fragment) (Nonce, error) {
+	if orderFragment.OrderNonce == nil {
+		return Nonce{}, ErrNonceMissing
+	}
+	return Nonce{
+		Trader:     orderFragment.Trader,
+		Value:      *orderFragment.OrderNonce,
+		Expiry:     orderFragment.OrderExpiry,
+		FragmentID: orderFragment.ID,
+	}, nil
+}
+
+// A NonceTable records the nonces used by traders, so that a signed order
+// fragment cannot be replayed to open an order again. A nonce is remembered
+// until its order expires, after which an order fragment that uses it is
+// rejected because it has expired. Traders must use increasing nonces,
+// because once a nonce is removed no trader can use a nonce that is lower
+// than it.
+type NonceTable struct {
+	do.GuardedObject
+
+	nonces  map[string]map[string]Nonce
+	removed map[string]stackint.Int1024
+}
+
+// NewNonceTable returns an empty NonceTable.
+func NewNonceTable() *NonceTable {
+	return &NonceTable{
+		GuardedObject: do.NewGuardedObject(),
+		nonces:        map[string]map[string]Nonce{},
+		removed:       map[string]stackint.Int1024{},
+	}
+}
+
+// InsertNonce records a Nonce for its trader. An ErrNonceUsed is returned if
+// the trader has already used the Nonce for a different order fragment, and
+// an ErrNonceStale is returned if the Nonce is not greater than the highest
+// Nonce of the trader that has been removed. Inserting a Nonce again for the
+// same order fragment succeeds, so that a trader can resend an order fragment
+// that was not received.
+func (table *NonceTable) InsertNonce(nonce Nonce) error {
+	table.Enter(nil)
+	defer table.Exit()
+
+	trader := string(nonce.Trader)
+	if removed, ok := table.removed[trader]; ok && nonce.Value.Cmp(&removed) <= 0 {
+		return ErrNonceStale
+	}
+	if _, ok := table.nonces[trader]; !ok {
+		table.nonces[trader] = map[string]Nonce{}
+	}
+	if used, ok := table.nonces[trader][nonce.Value.String()]; ok {
+		if used.FragmentID.Equal(nonce.FragmentID) {
+			return nil
+		}
+		return ErrNonceUsed
+	}
+	table.nonces[trader][nonce.Value.String()] = nonce
+	return nil
+}
+
+// RemoveExpiredNonces removes all Nonces whose orders have expired at the
+// given time, and returns them.
+func (table *NonceTable) RemoveExpiredNonces(now time.Time) []Nonce {
+	table.Enter(nil)
+	defer table.Exit()
+
+	expiredNonces := []Nonce{}
+	for trader, nonces := range table.nonces {
+		for key, nonce := range nonces {
+			if now.Before(nonce.Expiry) {
+				continue
+			}
+			if removed, ok := table.removed[trader]; !ok || nonce.Value.Cmp(&removed) > 0 {
+				table.removed[trader] = nonce.Value
+			}
+			delete(nonces, key)
+			expiredNonces = append(expiredNonces, nonce)
+		}
+		if len(nonces) == 0 {
+			delete(table.nonces, trader)
+		}
+	}
+	return expiredNonces
+}
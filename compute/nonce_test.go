@@ -0,0 +1,90 @@
+package compute_test
+
+import (
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	. "github.com/republicprotocol/republic-go/compute"
+	"github.com/republicprotocol/republic-go/identity"
+	"github.com/republicprotocol/republic-go/order"
+	"github.com/republicprotocol/republic-go/shamir"
+	"github.com/republicprotocol/republic-go/stackint"
+)
+
+var _ = Describe("Nonces", func() {
+
+	var trader identity.ID
+	var now time.Time
+
+	BeforeEach(func() {
+		keyPair, err := identity.NewKeyPair()
+		Ω(err).ShouldNot(HaveOccurred())
+		trader = keyPair.ID()
+		now = time.Now()
+	})
+
+	newNonce := func(trader identity.ID, value uint, expiry time.Time) Nonce {
+		return Nonce{Trader: trader, Value: *heapInt(value), Expiry: expiry, FragmentID: order.FragmentID(heapInt(value).Bytes())}
+	}
+
+	It("should return the nonce of an order fragment", func() {
+		share := shamir.Share{Key: 1, Value: stackint.Zero()}
+		fragment := order.NewFragment(order.ID("order"), order.TypeLimit, order.ParityBuy, now.Add(time.Hour), share, share, share, share, share)
+		fragment.Trader = trader
+		fragment.OrderNonce = heapInt(7)
+
+		nonce, err := NewNonce(fragment)
+		Ω(err).ShouldNot(HaveOccurred())
+		Ω(nonce.Trader).Should(Equal(trader))
+		Ω(nonce.Value.Cmp(heapInt(7))).Should(Equal(0))
+		Ω(nonce.Expiry).Should(Equal(fragment.OrderExpiry))
+		Ω(nonce.FragmentID).Should(Equal(fragment.ID))
+
+		fragment.OrderNonce = nil
+		_, err = NewNonce(fragment)
+		Ω(err).Should(Equal(ErrNonceMissing))
+	})
+
+	It("should reject duplicate nonces", func() {
+		table := NewNonceTable()
+		nonce := newNonce(trader, 1, now.Add(time.Hour))
+		Ω(table.InsertNonce(nonce)).ShouldNot(HaveOccurred())
+		nonce.FragmentID = order.FragmentID("other fragment")
+		Ω(table.InsertNonce(nonce)).Should(Equal(ErrNonceUsed))
+
+		// Other traders can use the same nonce
+		keyPair, err := identity.NewKeyPair()
+		Ω(err).ShouldNot(HaveOccurred())
+		Ω(table.InsertNonce(newNonce(keyPair.ID(), 1, now.Add(time.Hour)))).ShouldNot(HaveOccurred())
+	})
+
+	It("should accept the same nonce again for the same order fragment", func() {
+		table := NewNonceTable()
+		Ω(table.InsertNonce(newNonce(trader, 1, now.Add(time.Hour)))).ShouldNot(HaveOccurred())
+		Ω(table.InsertNonce(newNonce(trader, 1, now.Add(time.Hour)))).ShouldNot(HaveOccurred())
+	})
+
+	It("should accept nonces out of order until a nonce is removed", func() {
+		table := NewNonceTable()
+		Ω(table.InsertNonce(newNonce(trader, 3, now.Add(time.Hour)))).ShouldNot(HaveOccurred())
+		Ω(table.InsertNonce(newNonce(trader, 2, now.Add(time.Minute)))).ShouldNot(HaveOccurred())
+		Ω(table.InsertNonce(newNonce(trader, 1, now.Add(time.Hour)))).ShouldNot(HaveOccurred())
+	})
+
+	It("should remove expired nonces and reject stale nonces", func() {
+		table := NewNonceTable()
+		Ω(table.InsertNonce(newNonce(trader, 2, now.Add(time.Minute)))).ShouldNot(HaveOccurred())
+		Ω(table.InsertNonce(newNonce(trader, 3, now.Add(time.Hour)))).ShouldNot(HaveOccurred())
+
+		Ω(table.RemoveExpiredNonces(now)).Should(BeEmpty())
+		expiredNonces := table.RemoveExpiredNonces(now.Add(time.Minute))
+		Ω(expiredNonces).Should(HaveLen(1))
+		Ω(expiredNonces[0].Value.Cmp(heapInt(2))).Should(Equal(0))
+
+		Ω(table.InsertNonce(newNonce(trader, 1, now.Add(time.Hour)))).Should(Equal(ErrNonceStale))
+		Ω(table.InsertNonce(newNonce(trader, 2, now.Add(time.Hour)))).Should(Equal(ErrNonceStale))
+		Ω(table.InsertNonce(newNonce(trader, 3, now.Add(time.Hour)))).ShouldNot(HaveOccurred())
+		Ω(table.InsertNonce(newNonce(trader, 4, now.Add(time.Hour)))).ShouldNot(HaveOccurred())
+	})
+})
@@ -1,25 +1,199 @@
 package compute
 
-// type ResidueID []byte
-
-// type ResidueFragment struct {
-// 	Signature    []byte
-// 	ResidueID    ResidueID
-// 	ResidueShare shamir.Share
-// 	AShare       shamir.Share
-// 	BShare       shamir.Share
-// 	CShare       shamir.Share
-// }
-
-// func NewResidueFragment() (*ResidueFragment, error) {
-// 	return &ResidueFragment{}, nil
-// }
-
-// type ResidueFragments struct {
-// 	Signature        []byte
-// 	ResidueFragments []*ResidueFragment
-// }
-
-// func NewResidueFragments() (*ResidueFragments, error) {
-// 	return &ResidueFragments{}, nil
-// }
+import (
+	"bytes"
+
+	"github.com/ethereum/go-ethereum/crypto"
+	base58 "github.com/jbenet/go-base58"
+	"github.com/republicprotocol/republic-go/canonical"
+	"github.com/republicprotocol/republic-go/identity"
+	"github.com/republicprotocol/republic-go/shamir"
+	"github.com/republicprotocol/republic-go/stackint"
+)
+
+// randomFragmentPrefix, residueFragmentPrefix, and alphaBetaFragmentPrefix
+// are the tags of the canonical encodings of the fragments that are exchanged
+// while generating and consuming Beaver triples, so that their signatures
+// cannot be mistaken for any other signature.
+var (
+	randomFragmentPrefix    = []byte("Republic Protocol: random fragment: ")
+	residueFragmentPrefix   = []byte("Republic Protocol: residue fragment: ")
+	alphaBetaFragmentPrefix = []byte("Republic Protocol: alpha beta fragment: ")
+)
+
+// A ResidueID identifies a Beaver triple, and the fragments that are used to
+// generate and consume it. A Beaver triple must only be consumed once.
+type ResidueID []byte
+
+// Equal returns an equality check between two ResidueIDs.
+func (id ResidueID) Equal(other ResidueID) bool {
+	return bytes.Equal(id, other)
+}
+
+// String returns a ResidueID as a Base58 encoded string.
+func (id ResidueID) String() string {
+	return base58.Encode(id)
+}
+
+// A RandomFragment holds shares of the random values that one dark node
+// contributes to a Beaver triple. The random values contributed by all dark
+// nodes are summed to produce the A and B values of the Beaver triple. It is
+// signed by the dark node that contributes it.
+type RandomFragment struct {
+	Signature identity.Signature
+	ResidueID ResidueID
+	AShare    shamir.Share
+	BShare    shamir.Share
+}
+
+// Hash returns the Keccak256 hash of the canonical encoding of a
+// RandomFragment. This hash is used to create the signature for a
+// RandomFragment. It returns nil if the RandomFragment cannot be encoded.
+func (randomFragment *RandomFragment) Hash() []byte {
+	data, err := randomFragment.MarshalBinary()
+	if err != nil {
+		return nil
+	}
+	return crypto.Keccak256(data)
+}
+
+// Sign signs the RandomFragment using the provided keypair, and assigns it the
+// RandomFragment's Signature field.
+func (randomFragment *RandomFragment) Sign(keyPair identity.KeyPair) error {
+	var err error
+	randomFragment.Signature, err = keyPair.Sign(randomFragment)
+	return err
+}
+
+// VerifySignature verifies that the Signature field has been signed by the
+// provided ID's private key, returning an error if the signature is invalid
+func (randomFragment *RandomFragment) VerifySignature(ID identity.ID) error {
+	return identity.VerifySignature(randomFragment, randomFragment.Signature, ID)
+}
+
+// MarshalBinary implements the encoding.BinaryMarshaler interface. It returns
+// the canonical encoding of a RandomFragment, which covers every field except
+// the Signature.
+func (randomFragment *RandomFragment) MarshalBinary() ([]byte, error) {
+	encoder := canonical.NewEncoder(randomFragmentPrefix)
+	encoder.WriteBytes(randomFragment.ResidueID)
+	encoder.WriteShare(randomFragment.AShare)
+	encoder.WriteShare(randomFragment.BShare)
+	return encoder.Bytes()
+}
+
+// A ResidueFragment holds shares of a Beaver triple (A, B, C) where C is the
+// product of A and B. While the triple is being generated, the ResidueShare
+// holds a share of the product of the A and B shares held by another dark
+// node, and it is signed by that dark node.
+type ResidueFragment struct {
+	Signature    identity.Signature
+	ResidueID    ResidueID
+	AShare       shamir.Share
+	BShare       shamir.Share
+	CShare       shamir.Share
+	ResidueShare shamir.Share
+}
+
+// Hash returns the Keccak256 hash of the canonical encoding of a
+// ResidueFragment. This hash is used to create the signature for a
+// ResidueFragment. It returns nil if the ResidueFragment cannot be encoded.
+func (residueFragment *ResidueFragment) Hash() []byte {
+	data, err := residueFragment.MarshalBinary()
+	if err != nil {
+		return nil
+	}
+	return crypto.Keccak256(data)
+}
+
+// Sign signs the ResidueFragment using the provided keypair, and assigns it
+// the ResidueFragment's Signature field.
+func (residueFragment *ResidueFragment) Sign(keyPair identity.KeyPair) error {
+	var err error
+	residueFragment.Signature, err = keyPair.Sign(residueFragment)
+	return err
+}
+
+// VerifySignature verifies that the Signature field has been signed by the
+// provided ID's private key, returning an error if the signature is invalid
+func (residueFragment *ResidueFragment) VerifySignature(ID identity.ID) error {
+	return identity.VerifySignature(residueFragment, residueFragment.Signature, ID)
+}
+
+// MarshalBinary implements the encoding.BinaryMarshaler interface. It returns
+// the canonical encoding of a ResidueFragment, which covers every field except
+// the Signature.
+func (residueFragment *ResidueFragment) MarshalBinary() ([]byte, error) {
+	encoder := canonical.NewEncoder(residueFragmentPrefix)
+	encoder.WriteBytes(residueFragment.ResidueID)
+	encoder.WriteShare(residueFragment.AShare)
+	encoder.WriteShare(residueFragment.BShare)
+	encoder.WriteShare(residueFragment.CShare)
+	encoder.WriteShare(residueFragment.ResidueShare)
+	return encoder.Bytes()
+}
+
+// An AlphaBetaFragment holds shares of the values that are opened when a
+// ResidueFragment is used to multiply two shares. The Alpha value is the
+// first operand minus A, and the Beta value is the second operand minus B.
+// Revealing them does not reveal the operands because A and B are random. It
+// is signed by the dark node that holds its shares.
+type AlphaBetaFragment struct {
+	Signature  identity.Signature
+	ResidueID  ResidueID
+	AlphaShare shamir.Share
+	BetaShare  shamir.Share
+}
+
+// Hash returns the Keccak256 hash of the canonical encoding of an
+// AlphaBetaFragment. This hash is used to create the signature for an
+// AlphaBetaFragment. It returns nil if the AlphaBetaFragment cannot be
+// encoded.
+func (alphaBetaFragment *AlphaBetaFragment) Hash() []byte {
+	data, err := alphaBetaFragment.MarshalBinary()
+	if err != nil {
+		return nil
+	}
+	return crypto.Keccak256(data)
+}
+
+// Sign signs the AlphaBetaFragment using the provided keypair, and assigns it
+// the AlphaBetaFragment's Signature field.
+func (alphaBetaFragment *AlphaBetaFragment) Sign(keyPair identity.KeyPair) error {
+	var err error
+	alphaBetaFragment.Signature, err = keyPair.Sign(alphaBetaFragment)
+	return err
+}
+
+// VerifySignature verifies that the Signature field has been signed by the
+// provided ID's private key, returning an error if the signature is invalid
+func (alphaBetaFragment *AlphaBetaFragment) VerifySignature(ID identity.ID) error {
+	return identity.VerifySignature(alphaBetaFragment, alphaBetaFragment.Signature, ID)
+}
+
+// MarshalBinary implements the encoding.BinaryMarshaler interface. It returns
+// the canonical encoding of an AlphaBetaFragment, which covers every field
+// except the Signature.
+func (alphaBetaFragment *AlphaBetaFragment) MarshalBinary() ([]byte, error) {
+	encoder := canonical.NewEncoder(alphaBetaFragmentPrefix)
+	encoder.WriteBytes(alphaBetaFragment.ResidueID)
+	encoder.WriteShare(alphaBetaFragment.AlphaShare)
+	encoder.WriteShare(alphaBetaFragment.BetaShare)
+	return encoder.Bytes()
+}
+
+// NewAlphaBetaFragment masks two shares using the shares of a Beaver triple.
+// All shares must have the same key.
+func NewAlphaBetaFragment(x, y shamir.Share, residueFragment *ResidueFragment, prime *stackint.Int1024) *AlphaBetaFragment {
+	return &AlphaBetaFragment{
+		ResidueID: residueFragment.ResidueID,
+		AlphaShare: shamir.Share{
+			Key:   x.Key,
+			Value: x.Value.CtSubModulo(&residueFragment.AShare.Value, prime),
+		},
+		BetaShare: shamir.Share{
+			Key:   y.Key,
+			Value: y.Value.CtSubModulo(&residueFragment.BShare.Value, prime),
+		},
+	}
+}
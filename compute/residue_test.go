@@ -1 +1,89 @@
-package compute
+package compute_test
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	. "github.com/republicprotocol/republic-go/compute"
+
+	"github.com/republicprotocol/republic-go/identity"
+	"github.com/republicprotocol/republic-go/shamir"
+	"github.com/republicprotocol/republic-go/stackint"
+)
+
+var _ = Describe("Residue fragments", func() {
+
+	share := func(key int64, value uint) shamir.Share {
+		return shamir.Share{Key: key, Value: stackint.FromUint(value)}
+	}
+
+	Context("when signing random fragments", func() {
+
+		It("should verify the signature of the signer", func() {
+			randomFragment := &RandomFragment{
+				ResidueID: ResidueID("residue"),
+				AShare:    share(1, 2),
+				BShare:    share(1, 3),
+			}
+			keyPair, err := identity.NewKeyPair()
+			Ω(err).ShouldNot(HaveOccurred())
+			Ω(randomFragment.Sign(keyPair)).ShouldNot(HaveOccurred())
+			Ω(randomFragment.VerifySignature(keyPair.ID())).ShouldNot(HaveOccurred())
+
+			other, err := identity.NewKeyPair()
+			Ω(err).ShouldNot(HaveOccurred())
+			Ω(randomFragment.VerifySignature(other.ID())).Should(HaveOccurred())
+
+			// Changing the share invalidates the signature
+			randomFragment.BShare = share(1, 4)
+			Ω(randomFragment.VerifySignature(keyPair.ID())).Should(HaveOccurred())
+		})
+	})
+
+	Context("when signing residue fragments", func() {
+
+		It("should verify the signature of the signer", func() {
+			residueFragment := &ResidueFragment{
+				ResidueID:    ResidueID("residue"),
+				AShare:       share(1, 0),
+				BShare:       share(1, 0),
+				CShare:       share(1, 0),
+				ResidueShare: share(1, 5),
+			}
+			keyPair, err := identity.NewKeyPair()
+			Ω(err).ShouldNot(HaveOccurred())
+			Ω(residueFragment.Sign(keyPair)).ShouldNot(HaveOccurred())
+			Ω(residueFragment.VerifySignature(keyPair.ID())).ShouldNot(HaveOccurred())
+
+			other, err := identity.NewKeyPair()
+			Ω(err).ShouldNot(HaveOccurred())
+			Ω(residueFragment.VerifySignature(other.ID())).Should(HaveOccurred())
+
+			// Changing the share invalidates the signature
+			residueFragment.ResidueShare = share(1, 6)
+			Ω(residueFragment.VerifySignature(keyPair.ID())).Should(HaveOccurred())
+		})
+	})
+
+	Context("when signing alpha beta fragments", func() {
+
+		It("should verify the signature of the signer", func() {
+			alphaBetaFragment := &AlphaBetaFragment{
+				ResidueID:  ResidueID("residue"),
+				AlphaShare: share(1, 7),
+				BetaShare:  share(1, 8),
+			}
+			keyPair, err := identity.NewKeyPair()
+			Ω(err).ShouldNot(HaveOccurred())
+			Ω(alphaBetaFragment.Sign(keyPair)).ShouldNot(HaveOccurred())
+			Ω(alphaBetaFragment.VerifySignature(keyPair.ID())).ShouldNot(HaveOccurred())
+
+			other, err := identity.NewKeyPair()
+			Ω(err).ShouldNot(HaveOccurred())
+			Ω(alphaBetaFragment.VerifySignature(other.ID())).Should(HaveOccurred())
+
+			// Changing the residue invalidates the signature
+			alphaBetaFragment.ResidueID = ResidueID("other residue")
+			Ω(alphaBetaFragment.VerifySignature(keyPair.ID())).Should(HaveOccurred())
+		})
+	})
+})
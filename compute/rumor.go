@@ -0,0 +1,201 @@
+package compute
+
+import (
+	"bytes"
+	"errors"
+
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/republicprotocol/go-do"
+	"github.com/republicprotocol/republic-go/identity"
+	"github.com/republicprotocol/republic-go/order"
+)
+
+// ErrRumorMismatch is returned when the Rumors that agree on a match are for
+// different matches.
+var ErrRumorMismatch = errors.New("rumors are for different matches")
+
+// ErrInsufficientAgreement is returned when fewer than k distinct dark nodes
+// in the dark pool signed the Rumors that agree on a match.
+var ErrInsufficientAgreement = errors.New("insufficient agreement")
+
+// rumorPrefix is prepended to a Rumor before hashing so that a Rumor
+// signature cannot be mistaken for any other signature.
+var rumorPrefix = []byte("Republic Protocol: rumor: ")
+
+// A Rumor is gossiped by a dark node to the rest of its dark pool when it
+// finds a match between a buy order and a sell order. The Signature of the
+// dark node is its agreement that the orders match.
+type Rumor struct {
+	Signature   identity.Signature
+	BuyOrderID  order.ID
+	SellOrderID order.ID
+}
+
+// NewRumor returns a new, unsigned, Rumor for a match between a buy order and
+// a sell order.
+func NewRumor(buyOrderID, sellOrderID order.ID) *Rumor {
+	return &Rumor{
+		BuyOrderID:  buyOrderID,
+		SellOrderID: sellOrderID,
+	}
+}
+
+// DeltaID returns the ID of the Delta that matched the orders in the Rumor.
+func (rumor *Rumor) DeltaID() DeltaID {
+	return DeltaID(crypto.Keccak256(rumor.BuyOrderID, rumor.SellOrderID))
+}
+
+// Hash returns the Keccak256 hash of a Rumor. This hash is used to create the
+// signature for a Rumor.
+func (rumor *Rumor) Hash() []byte {
+	return crypto.Keccak256(rumor.Bytes())
+}
+
+// Sign signs the Rumor using the provided keypair, and assigns it the Rumor's
+// Signature field.
+func (rumor *Rumor) Sign(keyPair identity.KeyPair) error {
+	var err error
+	rumor.Signature, err = keyPair.Sign(rumor)
+	return err
+}
+
+// VerifySignature verifies that the Signature field has been signed by the
+// provided ID's private key, returning an error if the signature is invalid
+func (rumor *Rumor) VerifySignature(ID identity.ID) error {
+	return identity.VerifySignature(rumor, rumor.Signature, ID)
+}
+
+// Bytes returns a Rumor serialized into a bytes.
+func (rumor *Rumor) Bytes() []byte {
+	buf := new(bytes.Buffer)
+	buf.Write(rumorPrefix)
+	buf.Write(rumor.DeltaID())
+	return buf.Bytes()
+}
+
+// A RumorBuilder collects signed Rumors from the dark nodes in a dark pool
+// until k distinct dark nodes agree that two orders match.
+type RumorBuilder struct {
+	do.GuardedObject
+
+	k         int64
+	rumors    map[string]map[string]*Rumor
+	agreed    map[string]bool
+	finalized map[string][]*Rumor
+}
+
+// NewRumorBuilder returns a new RumorBuilder which requires k agreeing Rumors
+// before a match can be finalized.
+func NewRumorBuilder(k int64) *RumorBuilder {
+	return &RumorBuilder{
+		GuardedObject: do.NewGuardedObject(),
+		k:             k,
+		rumors:        map[string]map[string]*Rumor{},
+		agreed:        map[string]bool{},
+		finalized:     map[string][]*Rumor{},
+	}
+}
+
+// InsertRumor inserts a signed Rumor. Signatures from the same signer are
+// only counted once. When k distinct signers agree on a match, the agreeing
+// Rumors are returned. They are returned exactly once for each match, and
+// never for a match that has been finalized. The caller is responsible for
+// checking that the signers are in the dark pool.
+func (builder *RumorBuilder) InsertRumor(rumor *Rumor) ([]*Rumor, error) {
+	builder.Enter(nil)
+	defer builder.Exit()
+	return builder.insertRumor(rumor)
+}
+
+func (builder *RumorBuilder) insertRumor(rumor *Rumor) ([]*Rumor, error) {
+	signer, err := identity.RecoverSigner(rumor, rumor.Signature)
+	if err != nil {
+		return nil, err
+	}
+	deltaID := string(rumor.DeltaID())
+	if _, ok := builder.finalized[deltaID]; ok || builder.agreed[deltaID] {
+		return nil, nil
+	}
+	if _, ok := builder.rumors[deltaID]; !ok {
+		builder.rumors[deltaID] = map[string]*Rumor{}
+	}
+	builder.rumors[deltaID][string(signer)] = rumor
+	if int64(len(builder.rumors[deltaID])) < builder.k {
+		return nil, nil
+	}
+
+	rumors := make([]*Rumor, 0, len(builder.rumors[deltaID]))
+	for _, rumor := range builder.rumors[deltaID] {
+		rumors = append(rumors, rumor)
+	}
+	builder.agreed[deltaID] = true
+	return rumors, nil
+}
+
+// VerifyAgreement verifies that at least k distinct signers agree on the
+// match in a list of Rumors. Every Rumor must be for the same match, and only
+// signers for which inPool returns true are counted.
+func (builder *RumorBuilder) VerifyAgreement(rumors []*Rumor, inPool func(identity.ID) bool) error {
+	builder.EnterReadOnly(nil)
+	defer builder.ExitReadOnly()
+	return builder.verifyAgreement(rumors, inPool)
+}
+
+func (builder *RumorBuilder) verifyAgreement(rumors []*Rumor, inPool func(identity.ID) bool) error {
+	if len(rumors) == 0 {
+		return ErrInsufficientAgreement
+	}
+	deltaID := rumors[0].DeltaID()
+	signers := map[string]struct{}{}
+	for _, rumor := range rumors {
+		if !rumor.DeltaID().Equal(deltaID) {
+			return ErrRumorMismatch
+		}
+		signer, err := identity.RecoverSigner(rumor, rumor.Signature)
+		if err != nil {
+			return err
+		}
+		if inPool(signer) {
+			signers[string(signer)] = struct{}{}
+		}
+	}
+	if int64(len(signers)) < builder.k {
+		return ErrInsufficientAgreement
+	}
+	return nil
+}
+
+// Finalize marks the match agreed on by a list of Rumors as finalized, and
+// remembers the Rumors as proof of the agreement. It returns true the first
+// time that a match is finalized, and false afterwards. The caller is
+// responsible for verifying the agreement on the match.
+func (builder *RumorBuilder) Finalize(rumors []*Rumor) bool {
+	builder.Enter(nil)
+	defer builder.Exit()
+	deltaID := string(rumors[0].DeltaID())
+	if _, ok := builder.finalized[deltaID]; ok {
+		return false
+	}
+	builder.finalized[deltaID] = rumors
+	delete(builder.rumors, deltaID)
+	return true
+}
+
+// Finalizations returns the agreeing Rumors of every match that has been
+// finalized.
+func (builder *RumorBuilder) Finalizations() [][]*Rumor {
+	builder.EnterReadOnly(nil)
+	defer builder.ExitReadOnly()
+	finalizations := make([][]*Rumor, 0, len(builder.finalized))
+	for _, rumors := range builder.finalized {
+		finalizations = append(finalizations, rumors)
+	}
+	return finalizations
+}
+
+// SetK updates the required number of agreeing Rumors.
+func (builder *RumorBuilder) SetK(k int64) {
+	builder.Enter(nil)
+	defer builder.Exit()
+	builder.k = k
+}
@@ -0,0 +1,112 @@
+package compute_test
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	. "github.com/republicprotocol/republic-go/compute"
+
+	"github.com/republicprotocol/republic-go/identity"
+	"github.com/republicprotocol/republic-go/order"
+)
+
+var _ = Describe("Rumors", func() {
+
+	k := int64(3)
+
+	signedRumor := func(buyOrderID, sellOrderID order.ID) (*Rumor, identity.KeyPair) {
+		keyPair, err := identity.NewKeyPair()
+		Ω(err).ShouldNot(HaveOccurred())
+		rumor := NewRumor(buyOrderID, sellOrderID)
+		Ω(rumor.Sign(keyPair)).ShouldNot(HaveOccurred())
+		return rumor, keyPair
+	}
+
+	Context("when signing rumors", func() {
+
+		It("should verify the signature of the signer", func() {
+			rumor, keyPair := signedRumor(order.ID("buy"), order.ID("sell"))
+			Ω(rumor.VerifySignature(keyPair.ID())).ShouldNot(HaveOccurred())
+
+			other, err := identity.NewKeyPair()
+			Ω(err).ShouldNot(HaveOccurred())
+			Ω(rumor.VerifySignature(other.ID())).Should(HaveOccurred())
+		})
+
+		It("should not verify a signature for different orders", func() {
+			rumor, keyPair := signedRumor(order.ID("buy"), order.ID("sell"))
+			rumor.SellOrderID = order.ID("other")
+			Ω(rumor.VerifySignature(keyPair.ID())).Should(HaveOccurred())
+		})
+	})
+
+	Context("when using a rumor builder", func() {
+
+		It("should only return rumors after k distinct signers agree", func() {
+			builder := NewRumorBuilder(k)
+			for i := int64(0); i < k-1; i++ {
+				rumor, _ := signedRumor(order.ID("buy"), order.ID("sell"))
+				rumors, err := builder.InsertRumor(rumor)
+				Ω(err).ShouldNot(HaveOccurred())
+				Ω(rumors).Should(BeNil())
+
+				// Duplicates must not count towards k
+				rumors, err = builder.InsertRumor(rumor)
+				Ω(err).ShouldNot(HaveOccurred())
+				Ω(rumors).Should(BeNil())
+			}
+			rumor, _ := signedRumor(order.ID("buy"), order.ID("sell"))
+			rumors, err := builder.InsertRumor(rumor)
+			Ω(err).ShouldNot(HaveOccurred())
+			Ω(rumors).Should(HaveLen(int(k)))
+
+			// Agreement is only returned once
+			rumor, _ = signedRumor(order.ID("buy"), order.ID("sell"))
+			rumors, err = builder.InsertRumor(rumor)
+			Ω(err).ShouldNot(HaveOccurred())
+			Ω(rumors).Should(BeNil())
+		})
+
+		It("should not return rumors for finalized matches", func() {
+			builder := NewRumorBuilder(1)
+			rumor, _ := signedRumor(order.ID("buy"), order.ID("sell"))
+			Ω(builder.Finalize([]*Rumor{rumor})).Should(BeTrue())
+			Ω(builder.Finalize([]*Rumor{rumor})).Should(BeFalse())
+			rumors, err := builder.InsertRumor(rumor)
+			Ω(err).ShouldNot(HaveOccurred())
+			Ω(rumors).Should(BeNil())
+			Ω(builder.Finalizations()).Should(Equal([][]*Rumor{{rumor}}))
+		})
+
+		It("should verify agreement from k distinct signers in the dark pool", func() {
+			builder := NewRumorBuilder(k)
+			pool := map[string]bool{}
+			inPool := func(id identity.ID) bool { return pool[string(id)] }
+			rumors := make([]*Rumor, 0, k)
+			for i := int64(0); i < k; i++ {
+				rumor, keyPair := signedRumor(order.ID("buy"), order.ID("sell"))
+				pool[string(keyPair.ID())] = true
+				rumors = append(rumors, rumor)
+			}
+			Ω(builder.VerifyAgreement(rumors, inPool)).ShouldNot(HaveOccurred())
+
+			// Duplicates must not count towards k
+			duplicated := append(append([]*Rumor{}, rumors[:k-1]...), rumors[0])
+			Ω(builder.VerifyAgreement(duplicated, inPool)).Should(Equal(ErrInsufficientAgreement))
+
+			// Signers outside of the dark pool must not count towards k
+			outsider, _ := signedRumor(order.ID("buy"), order.ID("sell"))
+			Ω(builder.VerifyAgreement(append(rumors[:k-1:k-1], outsider), inPool)).Should(Equal(ErrInsufficientAgreement))
+
+			// Every rumor must be for the same match
+			other, keyPair := signedRumor(order.ID("buy"), order.ID("other"))
+			pool[string(keyPair.ID())] = true
+			Ω(builder.VerifyAgreement(append(rumors[:k-1:k-1], other), inPool)).Should(Equal(ErrRumorMismatch))
+		})
+
+		It("should return an error for unsigned rumors", func() {
+			builder := NewRumorBuilder(k)
+			_, err := builder.InsertRumor(NewRumor(order.ID("buy"), order.ID("sell")))
+			Ω(err).Should(HaveOccurred())
+		})
+	})
+})
@@ -0,0 +1,119 @@
+package compute
+
+import (
+	"bytes"
+	"encoding/binary"
+
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/republicprotocol/republic-go/identity"
+	"github.com/republicprotocol/republic-go/order"
+)
+
+// snapshotPrefix is prepended to a Snapshot before hashing so that a Snapshot
+// signature cannot be mistaken for any other signature.
+var snapshotPrefix = []byte("Republic Protocol: snapshot: ")
+
+// A Snapshot captures the delta fragments held by a DeltaBuilder, grouped by
+// the state of the delta that they belong to. Pending delta fragments belong
+// to deltas that have not been reconstructed, matched and mismatched delta
+// fragments belong to deltas that have been reconstructed. A matched delta is
+// not proof of a match. Only the Finalizations, which each hold the Rumors of
+// the dark nodes that agreed on a match, are proof that a match was finalized.
+// The OrderFragments are the order fragments that traders encrypted for the
+// dark node that requested the Snapshot.
+type Snapshot struct {
+	Signature      identity.Signature
+	Pending        []*DeltaFragment
+	Matched        []*DeltaFragment
+	Mismatched     []*DeltaFragment
+	OrderFragments []*order.EncryptedFragment
+	Finalizations  [][]*Rumor
+}
+
+// Len returns the total number of delta fragments, order fragments and
+// finalizations in the Snapshot.
+func (snapshot *Snapshot) Len() int {
+	return len(snapshot.Pending) + len(snapshot.Matched) + len(snapshot.Mismatched) + len(snapshot.OrderFragments) + len(snapshot.Finalizations)
+}
+
+// Split the Snapshot into unsigned Snapshots that each hold at most n delta
+// fragments, order fragments and finalizations. The state of each delta
+// fragment is preserved.
+func (snapshot *Snapshot) Split(n int) []*Snapshot {
+	snapshots := []*Snapshot{}
+	next := &Snapshot{}
+	reserve := func() {
+		if next.Len() >= n {
+			snapshots = append(snapshots, next)
+			next = &Snapshot{}
+		}
+	}
+	split := func(deltaFragments []*DeltaFragment, field func(*Snapshot) *[]*DeltaFragment) {
+		for _, deltaFragment := range deltaFragments {
+			reserve()
+			*field(next) = append(*field(next), deltaFragment)
+		}
+	}
+	split(snapshot.Pending, func(s *Snapshot) *[]*DeltaFragment { return &s.Pending })
+	split(snapshot.Matched, func(s *Snapshot) *[]*DeltaFragment { return &s.Matched })
+	split(snapshot.Mismatched, func(s *Snapshot) *[]*DeltaFragment { return &s.Mismatched })
+	for _, orderFragment := range snapshot.OrderFragments {
+		reserve()
+		next.OrderFragments = append(next.OrderFragments, orderFragment)
+	}
+	for _, rumors := range snapshot.Finalizations {
+		reserve()
+		next.Finalizations = append(next.Finalizations, rumors)
+	}
+	if next.Len() > 0 {
+		snapshots = append(snapshots, next)
+	}
+	return snapshots
+}
+
+// Hash returns the Keccak256 hash of a Snapshot. This hash is used to create
+// the signature for a Snapshot.
+func (snapshot *Snapshot) Hash() []byte {
+	return crypto.Keccak256(snapshot.Bytes())
+}
+
+// Sign signs the Snapshot using the provided keypair, and assigns it the
+// Snapshot's Signature field.
+func (snapshot *Snapshot) Sign(keyPair identity.KeyPair) error {
+	var err error
+	snapshot.Signature, err = keyPair.Sign(snapshot)
+	return err
+}
+
+// VerifySignature verifies that the Signature field has been signed by the
+// provided ID's private key, returning an error if the signature is invalid
+func (snapshot *Snapshot) VerifySignature(ID identity.ID) error {
+	return identity.VerifySignature(snapshot, snapshot.Signature, ID)
+}
+
+// Bytes returns a Snapshot serialized into a bytes. Each DeltaFragment is
+// written as the hash of its canonical encoding, each EncryptedFragment as its
+// hash, and each Rumor as its hash and Signature.
+func (snapshot *Snapshot) Bytes() []byte {
+	buf := new(bytes.Buffer)
+	buf.Write(snapshotPrefix)
+	for _, deltaFragments := range [][]*DeltaFragment{snapshot.Pending, snapshot.Matched, snapshot.Mismatched} {
+		binary.Write(buf, binary.LittleEndian, uint64(len(deltaFragments)))
+		for _, deltaFragment := range deltaFragments {
+			buf.Write(deltaFragment.Hash())
+		}
+	}
+	binary.Write(buf, binary.LittleEndian, uint64(len(snapshot.OrderFragments)))
+	for _, orderFragment := range snapshot.OrderFragments {
+		buf.Write(orderFragment.Hash())
+	}
+	binary.Write(buf, binary.LittleEndian, uint64(len(snapshot.Finalizations)))
+	for _, rumors := range snapshot.Finalizations {
+		binary.Write(buf, binary.LittleEndian, uint64(len(rumors)))
+		for _, rumor := range rumors {
+			buf.Write(rumor.Hash())
+			buf.Write(rumor.Signature)
+		}
+	}
+	return buf.Bytes()
+}
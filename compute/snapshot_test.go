@@ -0,0 +1,162 @@
+package compute_test
+
+import (
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	. "github.com/republicprotocol/republic-go/compute"
+	"github.com/republicprotocol/republic-go/identity"
+	"github.com/republicprotocol/republic-go/order"
+	"github.com/republicprotocol/republic-go/stackint"
+)
+
+var _ = Describe("Snapshots", func() {
+
+	n := int64(8)
+	k := int64(6)
+	primeVal, _ := stackint.FromString("179769313486231590772930519078902473361797697894230657273430081157732675805500963132708477322407536021120113879871393357658789768814416622492847430639474124377767893424865485276302219601246094119453082952085005768838150682342462881473913110540827237163350510684586298239947245938479716304835356329624224137111")
+	prime := &primeVal
+
+	deltaFragmentsForPrices := func(buyPrice, sellPrice uint) []*DeltaFragment {
+		buyOrder := order.NewOrder(order.TypeLimit, order.ParityBuy, time.Now().Add(time.Hour), order.CurrencyCodeBTC, order.CurrencyCodeETH, heapInt(buyPrice), heapInt(1000), heapInt(100), heapInt(0))
+		buyOrderFragments, err := buyOrder.Split(n, k, prime)
+		Ω(err).ShouldNot(HaveOccurred())
+		signFragments(buyOrderFragments)
+		sellOrder := order.NewOrder(order.TypeLimit, order.ParitySell, time.Now().Add(time.Hour), order.CurrencyCodeBTC, order.CurrencyCodeETH, heapInt(sellPrice), heapInt(1000), heapInt(100), heapInt(0))
+		sellOrderFragments, err := sellOrder.Split(n, k, prime)
+		Ω(err).ShouldNot(HaveOccurred())
+		signFragments(sellOrderFragments)
+		deltaFragments := computeDeltaFragments(buyOrderFragments, sellOrderFragments, n, k, prime, buyPrice >= sellPrice)
+		Ω(deltaFragments).ShouldNot(BeNil())
+		return deltaFragments
+	}
+
+	signedRumor := func(buyOrderID, sellOrderID order.ID) (*Rumor, identity.KeyPair) {
+		keyPair, err := identity.NewKeyPair()
+		Ω(err).ShouldNot(HaveOccurred())
+		rumor := NewRumor(buyOrderID, sellOrderID)
+		Ω(rumor.Sign(keyPair)).ShouldNot(HaveOccurred())
+		return rumor, keyPair
+	}
+
+	Context("when taking a snapshot of a delta builder", func() {
+
+		It("should group delta fragments by the state of their delta", func() {
+			builder := NewDeltaBuilder(k, prime)
+			for _, deltaFragment := range deltaFragmentsForPrices(10, 10)[:k] {
+				builder.InsertDeltaFragment(deltaFragment)
+			}
+			for _, deltaFragment := range deltaFragmentsForPrices(10, 20)[:k] {
+				builder.InsertDeltaFragment(deltaFragment)
+			}
+			for _, deltaFragment := range deltaFragmentsForPrices(20, 10)[:k-1] {
+				builder.InsertDeltaFragment(deltaFragment)
+			}
+
+			snapshot := builder.Snapshot()
+			Ω(snapshot.Matched).Should(HaveLen(int(k)))
+			Ω(snapshot.Mismatched).Should(HaveLen(int(k)))
+			Ω(snapshot.Pending).Should(HaveLen(int(k - 1)))
+		})
+
+		It("should rebuild deltas from a snapshot", func() {
+			builder := NewDeltaBuilder(k, prime)
+			for _, deltaFragment := range deltaFragmentsForPrices(10, 10)[:k-1] {
+				builder.InsertDeltaFragment(deltaFragment)
+			}
+
+			other := NewDeltaBuilder(k, prime)
+			var delta *Delta
+			for _, deltaFragment := range builder.Snapshot().Pending {
+				Ω(other.InsertDeltaFragment(deltaFragment)).Should(BeNil())
+			}
+			for _, deltaFragment := range deltaFragmentsForPrices(10, 10)[k-1:] {
+				if d := other.InsertDeltaFragment(deltaFragment); d != nil {
+					delta = d
+				}
+			}
+			Ω(delta).ShouldNot(BeNil())
+		})
+	})
+
+	Context("when splitting snapshots", func() {
+
+		It("should preserve the state of every delta fragment", func() {
+			snapshot := &Snapshot{
+				Pending:    deltaFragmentsForPrices(10, 10),
+				Matched:    deltaFragmentsForPrices(10, 10)[:3],
+				Mismatched: deltaFragmentsForPrices(10, 20)[:2],
+			}
+			snapshots := snapshot.Split(3)
+			Ω(snapshots).Should(HaveLen(5))
+
+			pending, matched, mismatched := 0, 0, 0
+			for _, s := range snapshots {
+				Ω(s.Len()).Should(BeNumerically("<=", 3))
+				pending += len(s.Pending)
+				matched += len(s.Matched)
+				mismatched += len(s.Mismatched)
+			}
+			Ω(pending).Should(Equal(len(snapshot.Pending)))
+			Ω(matched).Should(Equal(len(snapshot.Matched)))
+			Ω(mismatched).Should(Equal(len(snapshot.Mismatched)))
+		})
+
+		It("should preserve every order fragment and finalization", func() {
+			rumor, _ := signedRumor(order.ID("buy"), order.ID("sell"))
+			snapshot := &Snapshot{
+				Pending:        deltaFragmentsForPrices(10, 10)[:2],
+				OrderFragments: []*order.EncryptedFragment{order.NewEncryptedFragment(identity.ID("node"), order.ID("order"), time.Now(), []byte("ciphertext"))},
+				Finalizations:  [][]*Rumor{{rumor}, {rumor}},
+			}
+			snapshots := snapshot.Split(2)
+			Ω(snapshots).Should(HaveLen(3))
+
+			orderFragments, finalizations := 0, 0
+			for _, s := range snapshots {
+				Ω(s.Len()).Should(BeNumerically("<=", 2))
+				orderFragments += len(s.OrderFragments)
+				finalizations += len(s.Finalizations)
+			}
+			Ω(orderFragments).Should(Equal(1))
+			Ω(finalizations).Should(Equal(2))
+		})
+
+		It("should return no snapshots when there are no delta fragments", func() {
+			snapshot := &Snapshot{}
+			Ω(snapshot.Split(3)).Should(BeEmpty())
+		})
+	})
+
+	Context("when signing snapshots", func() {
+
+		It("should verify the signature of the signer", func() {
+			keyPair, err := identity.NewKeyPair()
+			Ω(err).ShouldNot(HaveOccurred())
+			snapshot := &Snapshot{Pending: deltaFragmentsForPrices(10, 10)}
+			Ω(snapshot.Sign(keyPair)).ShouldNot(HaveOccurred())
+			Ω(snapshot.VerifySignature(keyPair.ID())).ShouldNot(HaveOccurred())
+		})
+
+		It("should not verify a snapshot that has been modified", func() {
+			keyPair, err := identity.NewKeyPair()
+			Ω(err).ShouldNot(HaveOccurred())
+			snapshot := &Snapshot{Pending: deltaFragmentsForPrices(10, 10)}
+			Ω(snapshot.Sign(keyPair)).ShouldNot(HaveOccurred())
+			snapshot.Matched, snapshot.Pending = snapshot.Pending, nil
+			Ω(snapshot.VerifySignature(keyPair.ID())).Should(Equal(identity.ErrInvalidSignature))
+		})
+
+		It("should not verify a snapshot with modified finalizations", func() {
+			keyPair, err := identity.NewKeyPair()
+			Ω(err).ShouldNot(HaveOccurred())
+			rumor, _ := signedRumor(order.ID("buy"), order.ID("sell"))
+			snapshot := &Snapshot{Finalizations: [][]*Rumor{{rumor}}}
+			Ω(snapshot.Sign(keyPair)).ShouldNot(HaveOccurred())
+			other, _ := signedRumor(order.ID("buy"), order.ID("sell"))
+			snapshot.Finalizations[0] = append(snapshot.Finalizations[0], other)
+			Ω(snapshot.VerifySignature(keyPair.ID())).Should(Equal(identity.ErrInvalidSignature))
+		})
+	})
+})
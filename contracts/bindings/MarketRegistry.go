@@ -0,0 +1,642 @@
+// Code generated - DO NOT EDIT.
+// This file is a generated binding and any manual changes will be lost.
+
+package bindings
+
+import (
+	"math/big"
+	"strings"
+
+	ethereum "github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/event"
+)
+
+// MarketRegistryABI is the input ABI used to generate the binding from.
+const MarketRegistryABI = "[{\"constant\":true,\"inputs\":[],\"name\":\"owner\",\"outputs\":[{\"name\":\"\",\"type\":\"address\"}],\"payable\":false,\"stateMutability\":\"view\",\"type\":\"function\"},{\"constant\":false,\"inputs\":[{\"name\":\"_code\",\"type\":\"uint32\"},{\"name\":\"_symbol\",\"type\":\"string\"},{\"name\":\"_decimals\",\"type\":\"uint8\"}],\"name\":\"listCurrency\",\"outputs\":[],\"payable\":false,\"stateMutability\":\"nonpayable\",\"type\":\"function\"},{\"constant\":false,\"inputs\":[{\"name\":\"_fstCode\",\"type\":\"uint32\"},{\"name\":\"_sndCode\",\"type\":\"uint32\"},{\"name\":\"_tickSize\",\"type\":\"string\"},{\"name\":\"_minVolume\",\"type\":\"string\"}],\"name\":\"listMarket\",\"outputs\":[],\"payable\":false,\"stateMutability\":\"nonpayable\",\"type\":\"function\"},{\"constant\":true,\"inputs\":[],\"name\":\"numberOfCurrencies\",\"outputs\":[{\"name\":\"\",\"type\":\"uint256\"}],\"payable\":false,\"stateMutability\":\"view\",\"type\":\"function\"},{\"constant\":true,\"inputs\":[{\"name\":\"_index\",\"type\":\"uint256\"}],\"name\":\"getCurrency\",\"outputs\":[{\"name\":\"code\",\"type\":\"uint32\"},{\"name\":\"symbol\",\"type\":\"string\"},{\"name\":\"decimals\",\"type\":\"uint8\"}],\"payable\":false,\"stateMutability\":\"view\",\"type\":\"function\"},{\"constant\":true,\"inputs\":[],\"name\":\"numberOfMarkets\",\"outputs\":[{\"name\":\"\",\"type\":\"uint256\"}],\"payable\":false,\"stateMutability\":\"view\",\"type\":\"function\"},{\"constant\":true,\"inputs\":[{\"name\":\"_index\",\"type\":\"uint256\"}],\"name\":\"getMarket\",\"outputs\":[{\"name\":\"fstCode\",\"type\":\"uint32\"},{\"name\":\"sndCode\",\"type\":\"uint32\"},{\"name\":\"tickSize\",\"type\":\"string\"},{\"name\":\"minVolume\",\"type\":\"string\"}],\"payable\":false,\"stateMutability\":\"view\",\"type\":\"function\"},{\"inputs\":[],\"payable\":false,\"stateMutability\":\"nonpayable\",\"type\":\"constructor\"},{\"anonymous\":false,\"inputs\":[{\"indexed\":true,\"name\":\"code\",\"type\":\"uint32\"},{\"indexed\":false,\"name\":\"symbol\",\"type\":\"string\"},{\"indexed\":false,\"name\":\"decimals\",\"type\":\"uint8\"}],\"name\":\"CurrencyListed\",\"type\":\"event\"},{\"anonymous\":false,\"inputs\":[{\"indexed\":true,\"name\":\"fstCode\",\"type\":\"uint32\"},{\"indexed\":true,\"name\":\"sndCode\",\"type\":\"uint32\"},{\"indexed\":false,\"name\":\"tickSize\",\"type\":\"string\"},{\"indexed\":false,\"name\":\"minVolume\",\"type\":\"string\"}],\"name\":\"MarketListed\",\"type\":\"event\"}]"
+
+// MarketRegistry is an auto generated Go binding around an Ethereum contract.
+type MarketRegistry struct {
+	MarketRegistryCaller     // Read-only binding to the contract
+	MarketRegistryTransactor // Write-only binding to the contract
+	MarketRegistryFilterer   // Log filterer for contract events
+}
+
+// MarketRegistryCaller is an auto generated read-only Go binding around an Ethereum contract.
+type MarketRegistryCaller struct {
+	contract *bind.BoundContract // Generic contract wrapper for the low level calls
+}
+
+// MarketRegistryTransactor is an auto generated write-only Go binding around an Ethereum contract.
+type MarketRegistryTransactor struct {
+	contract *bind.BoundContract // Generic contract wrapper for the low level calls
+}
+
+// MarketRegistryFilterer is an auto generated log filtering Go binding around an Ethereum contract events.
+type MarketRegistryFilterer struct {
+	contract *bind.BoundContract // Generic contract wrapper for the low level calls
+}
+
+// MarketRegistrySession is an auto generated Go binding around an Ethereum contract,
+// with pre-set call and transact options.
+type MarketRegistrySession struct {
+	Contract     *MarketRegistry   // Generic contract binding to set the session for
+	CallOpts     bind.CallOpts     // Call options to use throughout this session
+	TransactOpts bind.TransactOpts // Transaction auth options to use throughout this session
+}
+
+// MarketRegistryCallerSession is an auto generated read-only Go binding around an Ethereum contract,
+// with pre-set call options.
+type MarketRegistryCallerSession struct {
+	Contract *MarketRegistryCaller // Generic contract caller binding to set the session for
+	CallOpts bind.CallOpts         // Call options to use throughout this session
+}
+
+// MarketRegistryTransactorSession is an auto generated write-only Go binding around an Ethereum contract,
+// with pre-set transact options.
+type MarketRegistryTransactorSession struct {
+	Contract     *MarketRegistryTransactor // Generic contract transactor binding to set the session for
+	TransactOpts bind.TransactOpts         // Transaction auth options to use throughout this session
+}
+
+// MarketRegistryRaw is an auto generated low-level Go binding around an Ethereum contract.
+type MarketRegistryRaw struct {
+	Contract *MarketRegistry // Generic contract binding to access the raw methods on
+}
+
+// MarketRegistryCallerRaw is an auto generated low-level read-only Go binding around an Ethereum contract.
+type MarketRegistryCallerRaw struct {
+	Contract *MarketRegistryCaller // Generic read-only contract binding to access the raw methods on
+}
+
+// MarketRegistryTransactorRaw is an auto generated low-level write-only Go binding around an Ethereum contract.
+type MarketRegistryTransactorRaw struct {
+	Contract *MarketRegistryTransactor // Generic write-only contract binding to access the raw methods on
+}
+
+// NewMarketRegistry creates a new instance of MarketRegistry, bound to a specific deployed contract.
+func NewMarketRegistry(address common.Address, backend bind.ContractBackend) (*MarketRegistry, error) {
+	contract, err := bindMarketRegistry(address, backend, backend, backend)
+	if err != nil {
+		return nil, err
+	}
+	return &MarketRegistry{MarketRegistryCaller: MarketRegistryCaller{contract: contract}, MarketRegistryTransactor: MarketRegistryTransactor{contract: contract}, MarketRegistryFilterer: MarketRegistryFilterer{contract: contract}}, nil
+}
+
+// NewMarketRegistryCaller creates a new read-only instance of MarketRegistry, bound to a specific deployed contract.
+func NewMarketRegistryCaller(address common.Address, caller bind.ContractCaller) (*MarketRegistryCaller, error) {
+	contract, err := bindMarketRegistry(address, caller, nil, nil)
+	if err != nil {
+		return nil, err
+	}
+	return &MarketRegistryCaller{contract: contract}, nil
+}
+
+// NewMarketRegistryTransactor creates a new write-only instance of MarketRegistry, bound to a specific deployed contract.
+func NewMarketRegistryTransactor(address common.Address, transactor bind.ContractTransactor) (*MarketRegistryTransactor, error) {
+	contract, err := bindMarketRegistry(address, nil, transactor, nil)
+	if err != nil {
+		return nil, err
+	}
+	return &MarketRegistryTransactor{contract: contract}, nil
+}
+
+// NewMarketRegistryFilterer creates a new log filterer instance of MarketRegistry, bound to a specific deployed contract.
+func NewMarketRegistryFilterer(address common.Address, filterer bind.ContractFilterer) (*MarketRegistryFilterer, error) {
+	contract, err := bindMarketRegistry(address, nil, nil, filterer)
+	if err != nil {
+		return nil, err
+	}
+	return &MarketRegistryFilterer{contract: contract}, nil
+}
+
+// bindMarketRegistry binds a generic wrapper to an already deployed contract.
+func bindMarketRegistry(address common.Address, caller bind.ContractCaller, transactor bind.ContractTransactor, filterer bind.ContractFilterer) (*bind.BoundContract, error) {
+	parsed, err := abi.JSON(strings.NewReader(MarketRegistryABI))
+	if err != nil {
+		return nil, err
+	}
+	return bind.NewBoundContract(address, parsed, caller, transactor, filterer), nil
+}
+
+// Call invokes the (constant) contract method with params as input values and
+// sets the output to result. The result type might be a single field for simple
+// returns, a slice of interfaces for anonymous returns and a struct for named
+// returns.
+func (_MarketRegistry *MarketRegistryRaw) Call(opts *bind.CallOpts, result interface{}, method string, params ...interface{}) error {
+	return _MarketRegistry.Contract.MarketRegistryCaller.contract.Call(opts, result, method, params...)
+}
+
+// Transfer initiates a plain transaction to move funds to the contract, calling
+// its default method if one is available.
+func (_MarketRegistry *MarketRegistryRaw) Transfer(opts *bind.TransactOpts) (*types.Transaction, error) {
+	return _MarketRegistry.Contract.MarketRegistryTransactor.contract.Transfer(opts)
+}
+
+// Transact invokes the (paid) contract method with params as input values.
+func (_MarketRegistry *MarketRegistryRaw) Transact(opts *bind.TransactOpts, method string, params ...interface{}) (*types.Transaction, error) {
+	return _MarketRegistry.Contract.MarketRegistryTransactor.contract.Transact(opts, method, params...)
+}
+
+// Call invokes the (constant) contract method with params as input values and
+// sets the output to result. The result type might be a single field for simple
+// returns, a slice of interfaces for anonymous returns and a struct for named
+// returns.
+func (_MarketRegistry *MarketRegistryCallerRaw) Call(opts *bind.CallOpts, result interface{}, method string, params ...interface{}) error {
+	return _MarketRegistry.Contract.contract.Call(opts, result, method, params...)
+}
+
+// Transfer initiates a plain transaction to move funds to the contract, calling
+// its default method if one is available.
+func (_MarketRegistry *MarketRegistryTransactorRaw) Transfer(opts *bind.TransactOpts) (*types.Transaction, error) {
+	return _MarketRegistry.Contract.contract.Transfer(opts)
+}
+
+// Transact invokes the (paid) contract method with params as input values.
+func (_MarketRegistry *MarketRegistryTransactorRaw) Transact(opts *bind.TransactOpts, method string, params ...interface{}) (*types.Transaction, error) {
+	return _MarketRegistry.Contract.contract.Transact(opts, method, params...)
+}
+
+// GetCurrency is a free data retrieval call binding the contract method 0xcdf9b77e.
+//
+// Solidity: function getCurrency(_index uint256) constant returns(code uint32, symbol string, decimals uint8)
+func (_MarketRegistry *MarketRegistryCaller) GetCurrency(opts *bind.CallOpts, _index *big.Int) (struct {
+	Code     uint32
+	Symbol   string
+	Decimals uint8
+}, error) {
+	ret := new(struct {
+		Code     uint32
+		Symbol   string
+		Decimals uint8
+	})
+	out := ret
+	err := _MarketRegistry.contract.Call(opts, out, "getCurrency", _index)
+	return *ret, err
+}
+
+// GetCurrency is a free data retrieval call binding the contract method 0xcdf9b77e.
+//
+// Solidity: function getCurrency(_index uint256) constant returns(code uint32, symbol string, decimals uint8)
+func (_MarketRegistry *MarketRegistrySession) GetCurrency(_index *big.Int) (struct {
+	Code     uint32
+	Symbol   string
+	Decimals uint8
+}, error) {
+	return _MarketRegistry.Contract.GetCurrency(&_MarketRegistry.CallOpts, _index)
+}
+
+// GetCurrency is a free data retrieval call binding the contract method 0xcdf9b77e.
+//
+// Solidity: function getCurrency(_index uint256) constant returns(code uint32, symbol string, decimals uint8)
+func (_MarketRegistry *MarketRegistryCallerSession) GetCurrency(_index *big.Int) (struct {
+	Code     uint32
+	Symbol   string
+	Decimals uint8
+}, error) {
+	return _MarketRegistry.Contract.GetCurrency(&_MarketRegistry.CallOpts, _index)
+}
+
+// GetMarket is a free data retrieval call binding the contract method 0xeb44fdd3.
+//
+// Solidity: function getMarket(_index uint256) constant returns(fstCode uint32, sndCode uint32, tickSize string, minVolume string)
+func (_MarketRegistry *MarketRegistryCaller) GetMarket(opts *bind.CallOpts, _index *big.Int) (struct {
+	FstCode   uint32
+	SndCode   uint32
+	TickSize  string
+	MinVolume string
+}, error) {
+	ret := new(struct {
+		FstCode   uint32
+		SndCode   uint32
+		TickSize  string
+		MinVolume string
+	})
+	out := ret
+	err := _MarketRegistry.contract.Call(opts, out, "getMarket", _index)
+	return *ret, err
+}
+
+// GetMarket is a free data retrieval call binding the contract method 0xeb44fdd3.
+//
+// Solidity: function getMarket(_index uint256) constant returns(fstCode uint32, sndCode uint32, tickSize string, minVolume string)
+func (_MarketRegistry *MarketRegistrySession) GetMarket(_index *big.Int) (struct {
+	FstCode   uint32
+	SndCode   uint32
+	TickSize  string
+	MinVolume string
+}, error) {
+	return _MarketRegistry.Contract.GetMarket(&_MarketRegistry.CallOpts, _index)
+}
+
+// GetMarket is a free data retrieval call binding the contract method 0xeb44fdd3.
+//
+// Solidity: function getMarket(_index uint256) constant returns(fstCode uint32, sndCode uint32, tickSize string, minVolume string)
+func (_MarketRegistry *MarketRegistryCallerSession) GetMarket(_index *big.Int) (struct {
+	FstCode   uint32
+	SndCode   uint32
+	TickSize  string
+	MinVolume string
+}, error) {
+	return _MarketRegistry.Contract.GetMarket(&_MarketRegistry.CallOpts, _index)
+}
+
+// NumberOfCurrencies is a free data retrieval call binding the contract method 0x73a873d5.
+//
+// Solidity: function numberOfCurrencies() constant returns(uint256)
+func (_MarketRegistry *MarketRegistryCaller) NumberOfCurrencies(opts *bind.CallOpts) (*big.Int, error) {
+	var (
+		ret0 = new(*big.Int)
+	)
+	out := ret0
+	err := _MarketRegistry.contract.Call(opts, out, "numberOfCurrencies")
+	return *ret0, err
+}
+
+// NumberOfCurrencies is a free data retrieval call binding the contract method 0x73a873d5.
+//
+// Solidity: function numberOfCurrencies() constant returns(uint256)
+func (_MarketRegistry *MarketRegistrySession) NumberOfCurrencies() (*big.Int, error) {
+	return _MarketRegistry.Contract.NumberOfCurrencies(&_MarketRegistry.CallOpts)
+}
+
+// NumberOfCurrencies is a free data retrieval call binding the contract method 0x73a873d5.
+//
+// Solidity: function numberOfCurrencies() constant returns(uint256)
+func (_MarketRegistry *MarketRegistryCallerSession) NumberOfCurrencies() (*big.Int, error) {
+	return _MarketRegistry.Contract.NumberOfCurrencies(&_MarketRegistry.CallOpts)
+}
+
+// NumberOfMarkets is a free data retrieval call binding the contract method 0x201b2fa9.
+//
+// Solidity: function numberOfMarkets() constant returns(uint256)
+func (_MarketRegistry *MarketRegistryCaller) NumberOfMarkets(opts *bind.CallOpts) (*big.Int, error) {
+	var (
+		ret0 = new(*big.Int)
+	)
+	out := ret0
+	err := _MarketRegistry.contract.Call(opts, out, "numberOfMarkets")
+	return *ret0, err
+}
+
+// NumberOfMarkets is a free data retrieval call binding the contract method 0x201b2fa9.
+//
+// Solidity: function numberOfMarkets() constant returns(uint256)
+func (_MarketRegistry *MarketRegistrySession) NumberOfMarkets() (*big.Int, error) {
+	return _MarketRegistry.Contract.NumberOfMarkets(&_MarketRegistry.CallOpts)
+}
+
+// NumberOfMarkets is a free data retrieval call binding the contract method 0x201b2fa9.
+//
+// Solidity: function numberOfMarkets() constant returns(uint256)
+func (_MarketRegistry *MarketRegistryCallerSession) NumberOfMarkets() (*big.Int, error) {
+	return _MarketRegistry.Contract.NumberOfMarkets(&_MarketRegistry.CallOpts)
+}
+
+// Owner is a free data retrieval call binding the contract method 0x8da5cb5b.
+//
+// Solidity: function owner() constant returns(address)
+func (_MarketRegistry *MarketRegistryCaller) Owner(opts *bind.CallOpts) (common.Address, error) {
+	var (
+		ret0 = new(common.Address)
+	)
+	out := ret0
+	err := _MarketRegistry.contract.Call(opts, out, "owner")
+	return *ret0, err
+}
+
+// Owner is a free data retrieval call binding the contract method 0x8da5cb5b.
+//
+// Solidity: function owner() constant returns(address)
+func (_MarketRegistry *MarketRegistrySession) Owner() (common.Address, error) {
+	return _MarketRegistry.Contract.Owner(&_MarketRegistry.CallOpts)
+}
+
+// Owner is a free data retrieval call binding the contract method 0x8da5cb5b.
+//
+// Solidity: function owner() constant returns(address)
+func (_MarketRegistry *MarketRegistryCallerSession) Owner() (common.Address, error) {
+	return _MarketRegistry.Contract.Owner(&_MarketRegistry.CallOpts)
+}
+
+// ListCurrency is a paid mutator transaction binding the contract method 0xa629fe6a.
+//
+// Solidity: function listCurrency(_code uint32, _symbol string, _decimals uint8) returns()
+func (_MarketRegistry *MarketRegistryTransactor) ListCurrency(opts *bind.TransactOpts, _code uint32, _symbol string, _decimals uint8) (*types.Transaction, error) {
+	return _MarketRegistry.contract.Transact(opts, "listCurrency", _code, _symbol, _decimals)
+}
+
+// ListCurrency is a paid mutator transaction binding the contract method 0xa629fe6a.
+//
+// Solidity: function listCurrency(_code uint32, _symbol string, _decimals uint8) returns()
+func (_MarketRegistry *MarketRegistrySession) ListCurrency(_code uint32, _symbol string, _decimals uint8) (*types.Transaction, error) {
+	return _MarketRegistry.Contract.ListCurrency(&_MarketRegistry.TransactOpts, _code, _symbol, _decimals)
+}
+
+// ListCurrency is a paid mutator transaction binding the contract method 0xa629fe6a.
+//
+// Solidity: function listCurrency(_code uint32, _symbol string, _decimals uint8) returns()
+func (_MarketRegistry *MarketRegistryTransactorSession) ListCurrency(_code uint32, _symbol string, _decimals uint8) (*types.Transaction, error) {
+	return _MarketRegistry.Contract.ListCurrency(&_MarketRegistry.TransactOpts, _code, _symbol, _decimals)
+}
+
+// ListMarket is a paid mutator transaction binding the contract method 0xdca0f500.
+//
+// Solidity: function listMarket(_fstCode uint32, _sndCode uint32, _tickSize string, _minVolume string) returns()
+func (_MarketRegistry *MarketRegistryTransactor) ListMarket(opts *bind.TransactOpts, _fstCode uint32, _sndCode uint32, _tickSize string, _minVolume string) (*types.Transaction, error) {
+	return _MarketRegistry.contract.Transact(opts, "listMarket", _fstCode, _sndCode, _tickSize, _minVolume)
+}
+
+// ListMarket is a paid mutator transaction binding the contract method 0xdca0f500.
+//
+// Solidity: function listMarket(_fstCode uint32, _sndCode uint32, _tickSize string, _minVolume string) returns()
+func (_MarketRegistry *MarketRegistrySession) ListMarket(_fstCode uint32, _sndCode uint32, _tickSize string, _minVolume string) (*types.Transaction, error) {
+	return _MarketRegistry.Contract.ListMarket(&_MarketRegistry.TransactOpts, _fstCode, _sndCode, _tickSize, _minVolume)
+}
+
+// ListMarket is a paid mutator transaction binding the contract method 0xdca0f500.
+//
+// Solidity: function listMarket(_fstCode uint32, _sndCode uint32, _tickSize string, _minVolume string) returns()
+func (_MarketRegistry *MarketRegistryTransactorSession) ListMarket(_fstCode uint32, _sndCode uint32, _tickSize string, _minVolume string) (*types.Transaction, error) {
+	return _MarketRegistry.Contract.ListMarket(&_MarketRegistry.TransactOpts, _fstCode, _sndCode, _tickSize, _minVolume)
+}
+
+// MarketRegistryCurrencyListedIterator is returned from FilterCurrencyListed and is used to iterate over the raw logs and unpacked data for CurrencyListed events raised by the MarketRegistry contract.
+type MarketRegistryCurrencyListedIterator struct {
+	Event *MarketRegistryCurrencyListed // Event containing the contract specifics and raw log
+
+	contract *bind.BoundContract // Generic contract to use for unpacking event data
+	event    string              // Event name to use for unpacking event data
+
+	logs chan types.Log        // Log channel receiving the found contract events
+	sub  ethereum.Subscription // Subscription for errors, completion and termination
+	done bool                  // Whether the subscription completed delivering logs
+	fail error                 // Occurred error to stop iteration
+}
+
+// Next advances the iterator to the subsequent event, returning whether there
+// are any more events found. In case of a retrieval or parsing error, false is
+// returned and Error() can be queried for the exact failure.
+func (it *MarketRegistryCurrencyListedIterator) Next() bool {
+	// If the iterator failed, stop iterating
+	if it.fail != nil {
+		return false
+	}
+	// If the iterator completed, deliver directly whatever's available
+	if it.done {
+		select {
+		case log := <-it.logs:
+			it.Event = new(MarketRegistryCurrencyListed)
+			if err := it.contract.UnpackLog(it.Event, it.event, log); err != nil {
+				it.fail = err
+				return false
+			}
+			it.Event.Raw = log
+			return true
+
+		default:
+			return false
+		}
+	}
+	// Iterator still in progress, wait for either a data or an error event
+	select {
+	case log := <-it.logs:
+		it.Event = new(MarketRegistryCurrencyListed)
+		if err := it.contract.UnpackLog(it.Event, it.event, log); err != nil {
+			it.fail = err
+			return false
+		}
+		it.Event.Raw = log
+		return true
+
+	case err := <-it.sub.Err():
+		it.done = true
+		it.fail = err
+		return it.Next()
+	}
+}
+
+// Error returns any retrieval or parsing error occurred during filtering.
+func (it *MarketRegistryCurrencyListedIterator) Error() error {
+	return it.fail
+}
+
+// Close terminates the iteration process, releasing any pending underlying
+// resources.
+func (it *MarketRegistryCurrencyListedIterator) Close() error {
+	it.sub.Unsubscribe()
+	return nil
+}
+
+// MarketRegistryCurrencyListed represents a CurrencyListed event raised by the MarketRegistry contract.
+type MarketRegistryCurrencyListed struct {
+	Code     uint32
+	Symbol   string
+	Decimals uint8
+	Raw      types.Log // Blockchain specific contextual infos
+}
+
+// FilterCurrencyListed is a free log retrieval operation binding the contract event 0xba5b9481a49fb7338254137715ba775338cb6acf5da7da2aaa215b31ec49a1a8.
+//
+// Solidity: event CurrencyListed(code indexed uint32, symbol string, decimals uint8)
+func (_MarketRegistry *MarketRegistryFilterer) FilterCurrencyListed(opts *bind.FilterOpts, code []uint32) (*MarketRegistryCurrencyListedIterator, error) {
+
+	var codeRule []interface{}
+	for _, codeItem := range code {
+		codeRule = append(codeRule, codeItem)
+	}
+
+	logs, sub, err := _MarketRegistry.contract.FilterLogs(opts, "CurrencyListed", codeRule)
+	if err != nil {
+		return nil, err
+	}
+	return &MarketRegistryCurrencyListedIterator{contract: _MarketRegistry.contract, event: "CurrencyListed", logs: logs, sub: sub}, nil
+}
+
+// WatchCurrencyListed is a free log subscription operation binding the contract event 0xba5b9481a49fb7338254137715ba775338cb6acf5da7da2aaa215b31ec49a1a8.
+//
+// Solidity: event CurrencyListed(code indexed uint32, symbol string, decimals uint8)
+func (_MarketRegistry *MarketRegistryFilterer) WatchCurrencyListed(opts *bind.WatchOpts, sink chan<- *MarketRegistryCurrencyListed, code []uint32) (event.Subscription, error) {
+
+	var codeRule []interface{}
+	for _, codeItem := range code {
+		codeRule = append(codeRule, codeItem)
+	}
+
+	logs, sub, err := _MarketRegistry.contract.WatchLogs(opts, "CurrencyListed", codeRule)
+	if err != nil {
+		return nil, err
+	}
+	return event.NewSubscription(func(quit <-chan struct{}) error {
+		defer sub.Unsubscribe()
+		for {
+			select {
+			case log := <-logs:
+				// New log arrived, parse the event and forward to the user
+				event := new(MarketRegistryCurrencyListed)
+				if err := _MarketRegistry.contract.UnpackLog(event, "CurrencyListed", log); err != nil {
+					return err
+				}
+				event.Raw = log
+
+				select {
+				case sink <- event:
+				case err := <-sub.Err():
+					return err
+				case <-quit:
+					return nil
+				}
+			case err := <-sub.Err():
+				return err
+			case <-quit:
+				return nil
+			}
+		}
+	}), nil
+}
+
+// MarketRegistryMarketListedIterator is returned from FilterMarketListed and is used to iterate over the raw logs and unpacked data for MarketListed events raised by the MarketRegistry contract.
+type MarketRegistryMarketListedIterator struct {
+	Event *MarketRegistryMarketListed // Event containing the contract specifics and raw log
+
+	contract *bind.BoundContract // Generic contract to use for unpacking event data
+	event    string              // Event name to use for unpacking event data
+
+	logs chan types.Log        // Log channel receiving the found contract events
+	sub  ethereum.Subscription // Subscription for errors, completion and termination
+	done bool                  // Whether the subscription completed delivering logs
+	fail error                 // Occurred error to stop iteration
+}
+
+// Next advances the iterator to the subsequent event, returning whether there
+// are any more events found. In case of a retrieval or parsing error, false is
+// returned and Error() can be queried for the exact failure.
+func (it *MarketRegistryMarketListedIterator) Next() bool {
+	// If the iterator failed, stop iterating
+	if it.fail != nil {
+		return false
+	}
+	// If the iterator completed, deliver directly whatever's available
+	if it.done {
+		select {
+		case log := <-it.logs:
+			it.Event = new(MarketRegistryMarketListed)
+			if err := it.contract.UnpackLog(it.Event, it.event, log); err != nil {
+				it.fail = err
+				return false
+			}
+			it.Event.Raw = log
+			return true
+
+		default:
+			return false
+		}
+	}
+	// Iterator still in progress, wait for either a data or an error event
+	select {
+	case log := <-it.logs:
+		it.Event = new(MarketRegistryMarketListed)
+		if err := it.contract.UnpackLog(it.Event, it.event, log); err != nil {
+			it.fail = err
+			return false
+		}
+		it.Event.Raw = log
+		return true
+
+	case err := <-it.sub.Err():
+		it.done = true
+		it.fail = err
+		return it.Next()
+	}
+}
+
+// Error returns any retrieval or parsing error occurred during filtering.
+func (it *MarketRegistryMarketListedIterator) Error() error {
+	return it.fail
+}
+
+// Close terminates the iteration process, releasing any pending underlying
+// resources.
+func (it *MarketRegistryMarketListedIterator) Close() error {
+	it.sub.Unsubscribe()
+	return nil
+}
+
+// MarketRegistryMarketListed represents a MarketListed event raised by the MarketRegistry contract.
+type MarketRegistryMarketListed struct {
+	FstCode   uint32
+	SndCode   uint32
+	TickSize  string
+	MinVolume string
+	Raw       types.Log // Blockchain specific contextual infos
+}
+
+// FilterMarketListed is a free log retrieval operation binding the contract event 0x9d0bff45c72c73e783bee3776ef46302887c6272eeef6fd9588ebbe36a0a797c.
+//
+// Solidity: event MarketListed(fstCode indexed uint32, sndCode indexed uint32, tickSize string, minVolume string)
+func (_MarketRegistry *MarketRegistryFilterer) FilterMarketListed(opts *bind.FilterOpts, fstCode []uint32, sndCode []uint32) (*MarketRegistryMarketListedIterator, error) {
+
+	var fstCodeRule []interface{}
+	for _, fstCodeItem := range fstCode {
+		fstCodeRule = append(fstCodeRule, fstCodeItem)
+	}
+	var sndCodeRule []interface{}
+	for _, sndCodeItem := range sndCode {
+		sndCodeRule = append(sndCodeRule, sndCodeItem)
+	}
+
+	logs, sub, err := _MarketRegistry.contract.FilterLogs(opts, "MarketListed", fstCodeRule, sndCodeRule)
+	if err != nil {
+		return nil, err
+	}
+	return &MarketRegistryMarketListedIterator{contract: _MarketRegistry.contract, event: "MarketListed", logs: logs, sub: sub}, nil
+}
+
+// WatchMarketListed is a free log subscription operation binding the contract event 0x9d0bff45c72c73e783bee3776ef46302887c6272eeef6fd9588ebbe36a0a797c.
+//
+// Solidity: event MarketListed(fstCode indexed uint32, sndCode indexed uint32, tickSize string, minVolume string)
+func (_MarketRegistry *MarketRegistryFilterer) WatchMarketListed(opts *bind.WatchOpts, sink chan<- *MarketRegistryMarketListed, fstCode []uint32, sndCode []uint32) (event.Subscription, error) {
+
+	var fstCodeRule []interface{}
+	for _, fstCodeItem := range fstCode {
+		fstCodeRule = append(fstCodeRule, fstCodeItem)
+	}
+	var sndCodeRule []interface{}
+	for _, sndCodeItem := range sndCode {
+		sndCodeRule = append(sndCodeRule, sndCodeItem)
+	}
+
+	logs, sub, err := _MarketRegistry.contract.WatchLogs(opts, "MarketListed", fstCodeRule, sndCodeRule)
+	if err != nil {
+		return nil, err
+	}
+	return event.NewSubscription(func(quit <-chan struct{}) error {
+		defer sub.Unsubscribe()
+		for {
+			select {
+			case log := <-logs:
+				// New log arrived, parse the event and forward to the user
+				event := new(MarketRegistryMarketListed)
+				if err := _MarketRegistry.contract.UnpackLog(event, "MarketListed", log); err != nil {
+					return err
+				}
+				event.Raw = log
+
+				select {
+				case sink <- event:
+				case err := <-sub.Err():
+					return err
+				case <-quit:
+					return nil
+				}
+			case err := <-sub.Err():
+				return err
+			case <-quit:
+				return nil
+			}
+		}
+	}), nil
+}
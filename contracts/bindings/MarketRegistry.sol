@@ -0,0 +1,72 @@
+pragma solidity ^0.4.18;
+
+/// @notice MarketRegistry lists the currencies and markets in which orders can
+/// be opened, so that traders and dark nodes can load them without being
+/// recompiled. Currencies and markets are listed in order, and a currency or
+/// market that is listed again replaces the earlier listing when they are
+/// loaded. Only the owner can list currencies and markets.
+contract MarketRegistry {
+
+    struct Currency {
+        uint32 code;
+        string symbol;
+        uint8 decimals;
+    }
+
+    struct Market {
+        uint32 fstCode;
+        uint32 sndCode;
+        string tickSize;
+        string minVolume;
+    }
+
+    address public owner;
+    Currency[] private currencies;
+    Market[] private markets;
+
+    event CurrencyListed(uint32 indexed code, string symbol, uint8 decimals);
+    event MarketListed(uint32 indexed fstCode, uint32 indexed sndCode, string tickSize, string minVolume);
+
+    /// @notice The MarketRegistry constructor. The sender becomes the owner.
+    function MarketRegistry() public {
+        owner = msg.sender;
+    }
+
+    /// @notice List a currency. Volumes of the currency are encoded with the
+    /// given number of decimals.
+    function listCurrency(uint32 _code, string _symbol, uint8 _decimals) public {
+        require(msg.sender == owner);
+        currencies.push(Currency(_code, _symbol, _decimals));
+        CurrencyListed(_code, _symbol, _decimals);
+    }
+
+    /// @notice List a market between two currencies. The tick size and the
+    /// minimum volume are decimal numbers.
+    function listMarket(uint32 _fstCode, uint32 _sndCode, string _tickSize, string _minVolume) public {
+        require(msg.sender == owner);
+        markets.push(Market(_fstCode, _sndCode, _tickSize, _minVolume));
+        MarketListed(_fstCode, _sndCode, _tickSize, _minVolume);
+    }
+
+    /// @notice Returns the number of currencies that have been listed.
+    function numberOfCurrencies() public view returns (uint256) {
+        return currencies.length;
+    }
+
+    /// @notice Returns the currency that was listed at the given index.
+    function getCurrency(uint256 _index) public view returns (uint32 code, string symbol, uint8 decimals) {
+        Currency storage currency = currencies[_index];
+        return (currency.code, currency.symbol, currency.decimals);
+    }
+
+    /// @notice Returns the number of markets that have been listed.
+    function numberOfMarkets() public view returns (uint256) {
+        return markets.length;
+    }
+
+    /// @notice Returns the market that was listed at the given index.
+    function getMarket(uint256 _index) public view returns (uint32 fstCode, uint32 sndCode, string tickSize, string minVolume) {
+        Market storage market = markets[_index];
+        return (market.fstCode, market.sndCode, market.tickSize, market.minVolume);
+    }
+}
@@ -0,0 +1,49 @@
+pragma solidity ^0.4.18;
+
+/// @notice The functions of the DarkNodeRegistry that are used by Settlement.
+contract DarkNodeRegistryInterface {
+    function isRegistered(bytes20 _darkNodeID) public view returns (bool);
+    function getOwner(bytes20 _darkNodeID) public view returns (address);
+}
+
+/// @notice Settlement records the matches that have been settled by the dark
+/// pools. Every dark node in a dark pool settles the same matches, so a match
+/// can only be settled once, and only by the owner of a registered dark node.
+/// The hash of the settlement parameters is stored so that dark nodes can check
+/// that a match was settled with the parameters that they agree on.
+contract Settlement {
+
+    DarkNodeRegistryInterface private darkNodeRegistry;
+    mapping (bytes32 => bytes32) private settlements;
+
+    event Settled(bytes32 indexed matchID, bytes32 indexed buyOrderID, bytes32 indexed sellOrderID, address buyer, address seller, uint256 volume, uint256 price);
+
+    /// @notice The Settlement constructor.
+    /// @param _darkNodeRegistry The address of the DarkNodeRegistry that dark
+    /// nodes are registered with.
+    function Settlement(address _darkNodeRegistry) public {
+        darkNodeRegistry = DarkNodeRegistryInterface(_darkNodeRegistry);
+    }
+
+    /// @notice Settle a match between a buy order and a sell order. The buyer
+    /// and seller are the accounts revealed by the traders of the orders. The
+    /// sender must own the registered dark node with the given ID.
+    function settle(bytes20 _darkNodeID, bytes32 _matchID, bytes32 _buyOrderID, bytes32 _sellOrderID, address _buyer, address _seller, uint256 _volume, uint256 _price) public {
+        require(darkNodeRegistry.isRegistered(_darkNodeID));
+        require(darkNodeRegistry.getOwner(_darkNodeID) == msg.sender);
+        require(settlements[_matchID] == 0x0);
+        settlements[_matchID] = keccak256(_buyOrderID, _sellOrderID, _buyer, _seller, _volume, _price);
+        Settled(_matchID, _buyOrderID, _sellOrderID, _buyer, _seller, _volume, _price);
+    }
+
+    /// @notice Returns true if the match has been settled.
+    function isSettled(bytes32 _matchID) public view returns (bool) {
+        return settlements[_matchID] != 0x0;
+    }
+
+    /// @notice Returns the hash of the parameters that the match was settled
+    /// with, or zero if the match has not been settled.
+    function settlementHash(bytes32 _matchID) public view returns (bytes32) {
+        return settlements[_matchID];
+    }
+}
@@ -1,6 +1,12 @@
 # Registrar
 abigen --sol ./republic-sol/contracts/DarkNodeRegistry.sol -pkg bindings --out dnr.go
 
+# Settlement
+abigen --sol ./Settlement.sol -pkg bindings --out Settlement.go
+
+# Market Registry
+abigen --sol ./MarketRegistry.sol -pkg bindings --out MarketRegistry.go
+
 # Atomic Swap
 # abigen --sol ./eth-atomic-swap/contracts/AtomicSwapEther.sol -pkg bindings --out AtomicSwapEth.go
 # abigen --sol ./eth-atomic-swap/contracts/AtomicSwapERC20.sol -pkg bindings --out AtomicSwapERC20.go
@@ -8,9 +8,31 @@ import (
 	"github.com/republicprotocol/republic-go/identity"
 	"github.com/republicprotocol/republic-go/logger"
 	"github.com/republicprotocol/republic-go/network"
+	"github.com/republicprotocol/republic-go/order"
+	"github.com/republicprotocol/republic-go/order/market"
 )
 
-// Config contains all configuration details for running a DarkNode.
+// Config contains all configuration details for running a DarkNode. The Field
+// is the name of the finite field used for secret sharing, which must be the
+// same for all DarkNodes in a network. The default finite field is used when
+// the Field is empty. The Midpoints are the reference midpoints at which fills
+// between IBBO orders are executed, until they are updated by midpoint updates
+// signed by the PriceFeed. Midpoints are never updated when the PriceFeed is
+// empty. The Markets list currencies and markets in addition to the default
+// ones, and more are loaded from the MarketRegistry contract at the
+// MarketRegistryAddress, when it is not empty. Order fragments are only opened
+// in listed markets. The SettlementAddress is the address of
+// the settlement contract, and matches are not settled when it is empty.
+// Matches are settled using the EthereumKey, which must be the account that
+// registered the DarkNode.
+//
+// The thresholds of a dark pool of n DarkNodes are not configured, they are
+// derived from n. Orders are split so that k = 2n/3 + 1 shares reconstruct
+// them, and deltas and fills are reconstructed once k + (n-k)/2 shares are
+// consistent. Beaver triples are the exception: they are shared with degree
+// (n-1)/2, and every one of the n DarkNodes must be online to generate them,
+// because two shares of degree k-1 cannot be multiplied by fewer than 2k-1 > n
+// DarkNodes. See smpc.ResidueGenerator.
 type Config struct {
 	NetworkOptions network.Options `json:"network"`
 	LoggerOptions  logger.Options  `json:"logger"`
@@ -22,6 +44,21 @@ type Config struct {
 	KeyPair     identity.KeyPair `json:"keyPair"`
 	EthereumKey keystore.Key     `json:"ethereumKey"`
 	EthereumRPC string           `json:"ethereumRPC"`
+	Field       string           `json:"field"`
+	Midpoints   []Midpoint       `json:"midpoints"`
+	Markets     *market.Config   `json:"markets"`
+	PriceFeed   identity.Address `json:"priceFeed"`
+
+	MarketRegistryAddress string `json:"marketRegistryAddress"`
+	SettlementAddress     string `json:"settlementAddress"`
+}
+
+// A Midpoint is the reference midpoint of a market. The Price is a decimal
+// number, which is encoded in the same way as the prices of orders.
+type Midpoint struct {
+	FstCode order.CurrencyCode `json:"fstCode"`
+	SndCode order.CurrencyCode `json:"sndCode"`
+	Price   string             `json:"price"`
 }
 
 // LoadConfig loads a Config object from the given filename. Returns the Config
@@ -2,9 +2,11 @@ package node
 
 import (
 	"bytes"
+	"errors"
 	"fmt"
 	"net"
 	"net/http"
+	"path/filepath"
 	"runtime"
 	"sync/atomic"
 	"time"
@@ -21,7 +23,13 @@ import (
 	"github.com/republicprotocol/republic-go/network/dht"
 	"github.com/republicprotocol/republic-go/network/rpc"
 	"github.com/republicprotocol/republic-go/order"
+	"github.com/republicprotocol/republic-go/order/fixed"
+	"github.com/republicprotocol/republic-go/order/market"
+	"github.com/republicprotocol/republic-go/settlement"
+	"github.com/republicprotocol/republic-go/shamir"
+	"github.com/republicprotocol/republic-go/smpc"
 	"github.com/republicprotocol/republic-go/stackint"
+	"github.com/republicprotocol/republic-go/store"
 	"github.com/rs/cors"
 	"github.com/shirou/gopsutil/cpu"
 	"github.com/shirou/gopsutil/mem"
@@ -29,9 +37,37 @@ import (
 	"google.golang.org/grpc"
 )
 
-// Prime is the default prime number used to define the finite field.
-var primeVal, _ = stackint.FromString("179769313486231590772930519078902473361797697894230657273430081157732675805500963132708477322407536021120113879871393357658789768814416622492847430639474124377767893424865485276302219601246094119453082952085005768838150682342462881473913110540827237163350510684586298239947245938479716304835356329624224137111")
-var prime = &primeVal
+// ErrNotInDarkPool is returned when a dark node that is not in the same dark
+// pool attempts to synchronize.
+var ErrNotInDarkPool = errors.New("not in dark pool")
+
+// ErrNoPriceFeed is returned when a dark node that has not been configured
+// with a price feed receives a midpoint update.
+var ErrNoPriceFeed = errors.New("no price feed")
+
+// syncBlockSize is the maximum number of delta fragments sent in a single
+// SyncBlock.
+const syncBlockSize = 1000
+
+// comparisonBits is the number of bits in the differences between the prices
+// and volumes of two orders. Prices and volumes must be less than
+// 2^comparisonBits, which is guaranteed when they are encoded using the fixed
+// package.
+const comparisonBits = fixed.Bits
+
+// comparisonSecurity is the statistical security, in bits, of the secure
+// comparison that decides whether or not two orders match.
+const comparisonSecurity = 40
+
+// expirySweepInterval is the interval at which expired orders are evicted. It
+// is shorter than the compute.ImmediateOrderWindow, so that the remaining
+// volume of immediate orders is cancelled soon after their window closes.
+const expirySweepInterval = 10 * time.Second
+
+// alphaBetaExpiry is how long the Multiplier keeps the values of a Beaver
+// triple. Values are removed when their comparison finishes, and this only
+// evicts AlphaBetaFragments that arrive after their comparison has finished.
+const alphaBetaExpiry = time.Hour
 
 // The DarkNode internal state
 type DarkNode struct {
@@ -46,21 +82,39 @@ type DarkNode struct {
 	Logger                 *logger.Logger
 	ClientPool             *rpc.ClientPool
 	DHT                    *dht.DHT
+	Store                  store.Store
+	FiniteField            *shamir.Field
+	VSS                    *shamir.VSS
 
 	DeltaBuilder                      *compute.DeltaBuilder
 	DeltaFragmentMatrix               *compute.DeltaFragmentMatrix
 	OrderFragmentWorkerQueue          chan *order.Fragment
 	OrderFragmentWorker               *OrderFragmentWorker
+	DifferenceFragmentWorkerQueue     chan *compute.DifferenceFragment
 	DeltaFragmentBroadcastWorkerQueue chan *compute.DeltaFragment
 	DeltaFragmentBroadcastWorker      *DeltaFragmentBroadcastWorker
 	DeltaFragmentWorkerQueue          chan *compute.DeltaFragment
 	DeltaFragmentWorker               *DeltaFragmentWorker
 	DeltaQueue                        chan *compute.Delta
 	DeltaMatchWorker                  *DeltaMatchWorker
+	GossipQueue                       chan *compute.Delta
+	RumorBuilder                      *compute.RumorBuilder
+	FillBuilder                       *compute.FillBuilder
+	Midpoints                         *compute.MidpointTable
+	Markets                           *market.Registry
+	Nonces                            *compute.NonceTable
+	EncryptedFragments                *compute.EncryptedFragmentTable
+	Notifier                          *Notifier
+	Settlements                       *settlement.Book
+	Settler                           settlement.Settler
+	ResidueGenerator                  *smpc.ResidueGenerator
+	Multiplier                        *smpc.Multiplier
+	Comparator                        *smpc.Comparator
 
 	Server *grpc.Server
 	Swarm  *network.SwarmService
 	Dark   *network.DarkService
+	Gossip *network.GossipService
 
 	DarkNodeRegistry dnr.DarkNodeRegistry
 	DarkOcean        *dark.Ocean
@@ -70,7 +124,10 @@ type DarkNode struct {
 
 // NewDarkNode return a DarkNode that adheres to the given Config. The DarkNode
 // will configure all of the components that it needs to operate but will not
-// start any of them.
+// start any of them. Matches are not settled until a Settler is set. The
+// Markets list the default currencies and markets, and the Markets of the
+// Config, and more can be loaded from other sources before the DarkNode is
+// started.
 func NewDarkNode(config Config, darkNodeRegistry dnr.DarkNodeRegistry) (*DarkNode, error) {
 	var err error
 	node := &DarkNode{
@@ -100,6 +157,13 @@ func NewDarkNode(config Config, darkNodeRegistry dnr.DarkNodeRegistry) (*DarkNod
 	}
 	k := int64(node.DarkPool.Size()*2/3 + 1)
 
+	// Select the finite field that is used by this node and its peers
+	node.FiniteField, err = shamir.FieldByName(config.Field)
+	if err != nil {
+		return nil, err
+	}
+	prime := node.FiniteField.Prime
+
 	multiAddressSignature, err := node.KeyPair.Sign(node.NetworkOptions.MultiAddress)
 	if err != nil {
 		return nil, err
@@ -110,27 +174,314 @@ func NewDarkNode(config Config, darkNodeRegistry dnr.DarkNodeRegistry) (*DarkNod
 		WithTimeout(node.NetworkOptions.Timeout).
 		WithTimeoutBackoff(node.NetworkOptions.TimeoutBackoff).
 		WithTimeoutRetries(node.NetworkOptions.TimeoutRetries).
-		WithCacheLimit(node.NetworkOptions.ClientPoolCacheLimit)
+		WithCacheLimit(node.NetworkOptions.ClientPoolCacheLimit).
+		WithField(node.FiniteField.ID)
 	node.DHT = dht.NewDHT(node.NetworkOptions.MultiAddress.Address(), node.NetworkOptions.MaxBucketLength)
 	node.Server = grpc.NewServer(grpc.ConnectionTimeout(time.Minute))
 	node.Swarm = network.NewSwarmService(node, node.NetworkOptions, node.Logger, node.ClientPool, node.DHT)
+	node.Swarm.Field = node.FiniteField.ID
 	node.Dark = network.NewDarkService(node, node.NetworkOptions, node.Logger)
+	node.Gossip = network.NewGossipService(node)
+
+	// Open the store that persists order fragments and delta fragments
+	// across restarts
+	if config.Path != "" {
+		node.Store, err = store.NewLevelDBStore(filepath.Join(config.Path, "db"))
+		if err != nil {
+			return nil, err
+		}
+	} else {
+		node.Store = store.NewMemoryStore()
+	}
+
+	// Create the parameters used to verify order fragments
+	node.VSS, err = shamir.NewVSS(prime)
+	if err != nil {
+		node.Store.Close()
+		return nil, err
+	}
 
 	// Create all background workers that will do all of the actual work
 	node.DeltaBuilder = compute.NewDeltaBuilder(k, prime)
+	node.DeltaBuilder.SetFaults((int64(node.DarkPool.Size()) - k) / 2)
 	node.DeltaFragmentMatrix = compute.NewDeltaFragmentMatrix(prime)
 	node.OrderFragmentWorkerQueue = make(chan *order.Fragment, 100)
-	node.OrderFragmentWorker = NewOrderFragmentWorker(node.Logger, node.DeltaFragmentMatrix, node.OrderFragmentWorkerQueue)
+	node.OrderFragmentWorker = NewOrderFragmentWorker(node.Logger, node.DeltaFragmentMatrix, node.Store, node.OrderFragmentWorkerQueue)
+	node.DifferenceFragmentWorkerQueue = make(chan *compute.DifferenceFragment, 100)
 	node.DeltaFragmentBroadcastWorkerQueue = make(chan *compute.DeltaFragment, 100)
 	node.DeltaFragmentBroadcastWorker = NewDeltaFragmentBroadcastWorker(node.Logger, node.ClientPool, node.DarkPool, node.DeltaFragmentBroadcastWorkerQueue)
 	node.DeltaFragmentWorkerQueue = make(chan *compute.DeltaFragment, 100)
-	node.DeltaFragmentWorker = NewDeltaFragmentWorker(node.Logger, node.DeltaBuilder, node.DeltaFragmentWorkerQueue)
+	node.DeltaFragmentWorker = NewDeltaFragmentWorker(node.Logger, node.DeltaBuilder, node.Store, node.DeltaFragmentWorkerQueue)
 	node.DeltaQueue = make(chan *compute.Delta, 100)
-	node.DeltaMatchWorker = NewDeltaMatchWorker(node.Logger, node.DeltaFragmentMatrix, node.DeltaQueue)
+	node.DeltaMatchWorker = NewDeltaMatchWorker(node.Logger, node.DeltaQueue)
+	node.GossipQueue = make(chan *compute.Delta, 100)
+	node.RumorBuilder = compute.NewRumorBuilder(k)
+	node.FillBuilder = compute.NewFillBuilder(k, prime)
+	node.FillBuilder.SetFaults((int64(node.DarkPool.Size()) - k) / 2)
+	node.Midpoints = compute.NewMidpointTable()
+	node.Nonces = compute.NewNonceTable()
+	node.EncryptedFragments = compute.NewEncryptedFragmentTable()
+	node.Notifier = NewNotifier(node.KeyPair)
+	node.Settlements = settlement.NewBook()
+	node.Markets = market.NewDefaultRegistry()
+	if config.Markets != nil {
+		if err := node.Markets.Load(*config.Markets); err != nil {
+			node.Store.Close()
+			return nil, err
+		}
+	}
+	for _, midpoint := range config.Midpoints {
+		price, err := fixed.EncodePrice(midpoint.Price)
+		if err != nil {
+			node.Store.Close()
+			return nil, err
+		}
+		node.Midpoints.SetMidpoint(midpoint.FstCode, midpoint.SndCode, &price)
+	}
+	node.ResidueGenerator = smpc.NewResidueGenerator(node.shareKey(node.ID), int64(node.DarkPool.Size()), k, prime)
+	node.Multiplier = smpc.NewMultiplier(k, prime)
+	node.Multiplier.SetFaults((int64(node.DarkPool.Size()) - k) / 2)
+	node.Comparator = smpc.NewComparator(comparisonBits, comparisonSecurity, node.Multiplier, prime)
+
+	if err := node.restoreStore(); err != nil {
+		node.Store.Close()
+		return nil, err
+	}
 
 	return node, nil
 }
 
+// restoreStore reloads the DeltaFragmentMatrix, the DeltaBuilder and the
+// NonceTable from the Store. Comparisons that were in progress when the
+// DarkNode stopped are restarted by re-queueing the difference fragments of
+// pairs of orders that the DarkNode has not stored its own delta fragment for.
+// Immediate orders are only restored while their window is open, which is the
+// same on every DarkNode because it starts at the open time of the order.
+func (node *DarkNode) restoreStore() error {
+	completeOrders, err := node.Store.CompleteOrders()
+	if err != nil {
+		return err
+	}
+	for _, orderID := range completeOrders {
+		if err := node.DeltaFragmentMatrix.RemoveOrderFragment(orderID); err != nil {
+			return err
+		}
+		node.DeltaBuilder.RemoveOrder(orderID, time.Time{})
+	}
+
+	differenceFragments := []*compute.DifferenceFragment{}
+	orderFragments, err := node.Store.OrderFragments()
+	if err != nil {
+		return err
+	}
+	for _, orderFragment := range orderFragments {
+		orderDifferenceFragments, err := node.DeltaFragmentMatrix.InsertOrderFragment(orderFragment)
+		if err != nil {
+			if err != compute.ErrOrderFragmentExpired {
+				return err
+			}
+			// The order expired, or the window of an immediate order closed,
+			// while the DarkNode was stopped
+			if err := node.Store.RemoveOrder(orderFragment.OrderID); err != nil {
+				return err
+			}
+			node.Logger.OrderExpired(logger.Info, orderFragment.OrderID.String())
+			continue
+		}
+		differenceFragments = append(differenceFragments, orderDifferenceFragments...)
+	}
+
+	residuals, err := node.Store.Residuals()
+	if err != nil {
+		return err
+	}
+	for _, residual := range residuals {
+		residualDifferenceFragments, err := node.DeltaFragmentMatrix.InsertResidualOrderFragment(residual.Root, residual.OrderFragment)
+		if err != nil {
+			if err != compute.ErrOrderFragmentExpired {
+				return err
+			}
+			if err := node.Store.RemoveOrder(residual.OrderFragment.OrderID); err != nil {
+				return err
+			}
+			node.Logger.OrderExpired(logger.Info, residual.OrderFragment.OrderID.String())
+			continue
+		}
+		differenceFragments = append(differenceFragments, residualDifferenceFragments...)
+	}
+
+	deltaFragments, err := node.Store.DeltaFragments()
+	if err != nil {
+		return err
+	}
+	key := node.shareKey(node.ID)
+	computed := map[string]bool{}
+	for _, deltaFragment := range deltaFragments {
+		node.DeltaBuilder.InsertDeltaFragment(deltaFragment)
+		if deltaFragment.MatchShare.Key == key {
+			computed[string(deltaFragment.DeltaID)] = true
+		}
+	}
+
+	// The comparisons of the remaining pairs are restarted once the background
+	// workers are reading from the queue
+	uncomputed := make([]*compute.DifferenceFragment, 0, len(differenceFragments))
+	for _, differenceFragment := range differenceFragments {
+		if !computed[string(differenceFragment.DeltaID)] {
+			uncomputed = append(uncomputed, differenceFragment)
+		}
+	}
+
+	// Nonces of orders that expired while the DarkNode was stopped are
+	// removed by the next sweep
+	nonces, err := node.Store.Nonces()
+	if err != nil {
+		return err
+	}
+	for _, nonce := range nonces {
+		if err := node.Nonces.InsertNonce(nonce); err != nil {
+			return err
+		}
+	}
+
+	go func() {
+		defer func() { recover() }()
+		for _, differenceFragment := range uncomputed {
+			node.DifferenceFragmentWorkerQueue <- differenceFragment
+		}
+	}()
+
+	node.Logger.Info(fmt.Sprintf("restored %d order fragments, %d residual orders, %d delta fragments, %d comparisons and %d nonces", len(orderFragments), len(residuals), len(deltaFragments), len(uncomputed), len(nonces)))
+	return nil
+}
+
+// SweepExpiredOrders evicts all orders that have expired at the given time
+// from the DeltaFragmentMatrix, the DeltaBuilder and the Store, and notifies
+// their traders. Removed orders that have expired are forgotten by the
+// DeltaBuilder. The nonces of these orders are evicted from the NonceTable
+// and the Store, their encrypted order fragments from the
+// EncryptedFragmentTable, and expired Beaver triple values from the
+// Multiplier.
+func (node *DarkNode) SweepExpiredOrders(now time.Time) {
+	node.Multiplier.RemoveExpiredAlphaBetas(now.Add(-alphaBetaExpiry))
+	node.EncryptedFragments.RemoveExpiredEncryptedFragments(now)
+	for _, nonce := range node.Nonces.RemoveExpiredNonces(now) {
+		if err := node.Store.RemoveNonce(nonce); err != nil {
+			node.Logger.Error(fmt.Sprintf("cannot remove expired nonce from store: %s", err.Error()))
+		}
+	}
+
+	// The traders are found before the orders are removed
+	notifications := map[string]*order.Notification{}
+	traders := map[string]identity.ID{}
+	expiries := map[string]time.Time{}
+	for _, orderFragment := range node.DeltaFragmentMatrix.ExpiredOrderFragments(now) {
+		trader, orderID := node.orderTrader(orderFragment.OrderID)
+		notifications[string(orderFragment.OrderID)] = order.NewNotification(order.NotificationExpired, orderID, nil)
+		traders[string(orderFragment.OrderID)] = trader
+		expiries[string(orderFragment.OrderID)] = orderFragment.OrderExpiry
+	}
+
+	node.DeltaBuilder.RemoveExpiredOrders(now)
+	for _, orderID := range node.DeltaFragmentMatrix.RemoveExpiredOrderFragments(now) {
+		node.DeltaBuilder.RemoveOrder(orderID, expiries[string(orderID)])
+		node.removeComparisons(orderID)
+		if err := node.Store.RemoveOrder(orderID); err != nil {
+			node.Logger.Error(fmt.Sprintf("cannot remove expired order from store: %s", err.Error()))
+		}
+		node.Logger.OrderExpired(logger.Info, orderID.String())
+		if notification, ok := notifications[string(orderID)]; ok {
+			node.notify(traders[string(orderID)], notification)
+		}
+	}
+}
+
+// orderTrader returns the trader of an open order, and the ID of the order
+// that the trader opened, which is not the same as the order ID for residual
+// orders. It must be called before the order is removed from the
+// DeltaFragmentMatrix. A nil trader is returned if the DarkNode does not hold
+// a fragment of the order.
+func (node *DarkNode) orderTrader(orderID order.ID) (identity.ID, order.ID) {
+	if rootOrderFragment := node.DeltaFragmentMatrix.RootOrderFragment(orderID); rootOrderFragment != nil {
+		return rootOrderFragment.Trader, rootOrderFragment.OrderID
+	}
+	if orderFragment := node.DeltaFragmentMatrix.OrderFragment(orderID); orderFragment != nil {
+		return orderFragment.Trader, orderFragment.OrderID
+	}
+	return nil, orderID
+}
+
+// notify sends a Notification to a trader, if the trader has subscribed to
+// this DarkNode.
+func (node *DarkNode) notify(trader identity.ID, notification *order.Notification) {
+	if trader == nil {
+		return
+	}
+	if err := node.Notifier.Notify(trader, notification); err != nil {
+		node.Logger.Error(fmt.Sprintf("cannot notify trader %v: %s", trader.Address(), err.Error()))
+	}
+}
+
+// removeMatch removes both orders of a match from the DeltaFragmentMatrix, the
+// Comparator and the Store, and records them as being complete. The match can
+// be undone by restoreMatch.
+func (node *DarkNode) removeMatch(rumor *compute.Rumor) error {
+	if err := node.DeltaFragmentMatrix.RemoveMatch(rumor.DeltaID(), rumor.BuyOrderID, rumor.SellOrderID); err != nil {
+		return err
+	}
+	for _, orderID := range []order.ID{rumor.BuyOrderID, rumor.SellOrderID} {
+		node.removeComparisons(orderID)
+		if err := node.Store.RemoveOrder(orderID); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// restoreMatch reopens the orders of a match that could not be settled, so
+// that they can be matched against other orders. The residual order that was
+// created when the match was filled is removed.
+func (node *DarkNode) restoreMatch(deltaID compute.DeltaID) {
+	orderFragments, residualOrderIDs, differenceFragments := node.DeltaFragmentMatrix.RestoreMatch(deltaID)
+	for _, residualOrderID := range residualOrderIDs {
+		node.removeComparisons(residualOrderID)
+		if err := node.Store.RemoveOrder(residualOrderID); err != nil {
+			node.Logger.Error(fmt.Sprintf("cannot remove residual order from store: %s", err.Error()))
+		}
+	}
+	for _, orderFragment := range orderFragments {
+		var err error
+		if rootOrderFragment := node.DeltaFragmentMatrix.RootOrderFragment(orderFragment.OrderID); rootOrderFragment != nil {
+			err = node.Store.PutResidual(store.Residual{Root: rootOrderFragment, OrderFragment: orderFragment})
+		} else {
+			err = node.Store.PutOrderFragment(orderFragment)
+		}
+		if err != nil {
+			node.Logger.Error(fmt.Sprintf("cannot store restored order fragment: %s", err.Error()))
+		}
+		node.Logger.Info(fmt.Sprintf("order %s restored after match %s was not settled", orderFragment.OrderID.String(), deltaID.String()))
+	}
+
+	// Write to a channel that might be closed
+	func() {
+		defer func() { recover() }()
+		for _, differenceFragment := range differenceFragments {
+			node.DifferenceFragmentWorkerQueue <- differenceFragment
+		}
+	}()
+}
+
+// removeComparisons removes all comparisons of an order from the Comparator,
+// and the Beaver triples of the comparisons that had not finished from the
+// ResidueGenerator.
+func (node *DarkNode) removeComparisons(orderID order.ID) {
+	for _, deltaID := range node.Comparator.RemoveOrder(orderID) {
+		for _, residueID := range node.Comparator.ResidueIDs(deltaID) {
+			node.ResidueGenerator.RemoveResidue(residueID)
+		}
+	}
+}
+
 // StartBackgroundWorkers starts the usage logger and order/delta workers
 func (node *DarkNode) StartBackgroundWorkers() {
 	// Usage logger
@@ -141,11 +492,21 @@ func (node *DarkNode) StartBackgroundWorkers() {
 		}
 	}()
 
+	// Expired order sweeper
+	go func() {
+		for {
+			time.Sleep(expirySweepInterval)
+			node.SweepExpiredOrders(time.Now())
+		}
+	}()
+
 	// Start background workers
-	go node.OrderFragmentWorker.Run(node.DeltaFragmentBroadcastWorkerQueue, node.DeltaFragmentWorkerQueue)
+	go node.OrderFragmentWorker.Run(node.DifferenceFragmentWorkerQueue)
+	go node.CompareDifferenceFragments()
 	go node.DeltaFragmentBroadcastWorker.Run()
 	go node.DeltaFragmentWorker.Run(node.DeltaQueue)
-	go node.DeltaMatchWorker.Run(node.DeltaNotifications)
+	go node.DeltaMatchWorker.Run(node.GossipQueue)
+	go node.GossipMatches()
 }
 
 // StartServices starts the gRPC listeners
@@ -154,6 +515,7 @@ func (node *DarkNode) StartServices() {
 
 	node.Swarm.Register(node.Server)
 	node.Dark.Register(node.Server)
+	node.Gossip.Register(node.Server)
 	listener, err := net.Listen("tcp", node.Host+":"+node.Port)
 	if err != nil {
 		node.Logger.Error(err.Error())
@@ -181,7 +543,7 @@ func (node *DarkNode) StartUI() {
 		w.Header().Set("Content-Type", "application/json")
 		json.NewEncoder(w).Encode(map[string]interface{}{
 			"nodeID":     "0x" + hex.EncodeToString(node.ID),
-			"publicKey":  "0x" + hex.EncodeToString(append(node.KeyPair.PublicKey.X.Bytes(), node.KeyPair.PublicKey.Y.Bytes()...)),
+			"publicKey":  "0x" + hex.EncodeToString(node.KeyPair.PublicKeyBytes()),
 			"address":    node.EthereumKey.Address.String(),
 			"republicID": node.ID.String(),
 			"ui": map[string]interface{}{
@@ -222,11 +584,18 @@ func (node *DarkNode) Stop() {
 
 	// Stop background workers by closing their job queues
 	close(node.OrderFragmentWorkerQueue)
+	close(node.DifferenceFragmentWorkerQueue)
 	close(node.DeltaFragmentBroadcastWorkerQueue)
 	close(node.DeltaFragmentWorkerQueue)
 	close(node.DeltaQueue)
+	close(node.GossipQueue)
 	close(node.DeltaNotifications)
 
+	// Close the store after the background workers have stopped writing to it
+	if err := node.Store.Close(); err != nil {
+		node.Logger.Error(fmt.Sprintf("cannot close store: %s", err.Error()))
+	}
+
 	// Stop the logger
 	node.Logger.Stop()
 
@@ -263,6 +632,13 @@ func (node *DarkNode) WatchDarkOcean() {
 			if darkPool != nil {
 				k := int64((darkPool.Size() * 2 / 3) + 1)
 				node.DeltaBuilder.SetK(k)
+				node.DeltaBuilder.SetFaults((int64(darkPool.Size()) - k) / 2)
+				node.ResidueGenerator.SetParticipants(node.shareKey(node.ID), int64(darkPool.Size()), k)
+				node.Multiplier.SetK(k)
+				node.Multiplier.SetFaults((int64(darkPool.Size()) - k) / 2)
+				node.RumorBuilder.SetK(k)
+				node.FillBuilder.SetK(k)
+				node.FillBuilder.SetFaults((int64(darkPool.Size()) - k) / 2)
 			}
 			node.ConnectToDarkPool(darkPool)
 		}
@@ -315,6 +691,12 @@ func (node *DarkNode) ConnectToDarkPool(darkPool *dark.Pool) {
 		// Update the MultiAddress in the node
 		n.SetMultiAddress(*multiAddress)
 		node.DarkPool.Append(*n)
+
+		// Synchronize with the dark node so that orders opened before this
+		// node connected are not missed
+		if err := node.SyncWithDarkNode(*multiAddress); err != nil {
+			node.Logger.Warn(fmt.Sprintf("cannot sync with dark node %v: %s", n.ID.Address(), err.Error()))
+		}
 	})
 
 	// In the background, continue to attempt connections to the disconnected
@@ -333,10 +715,197 @@ func (node *DarkNode) ConnectToDarkPool(darkPool *dark.Pool) {
 	}()
 }
 
+// SyncWithDarkNode requests a snapshot from another dark node and uses it to
+// rebuild the DeltaFragmentMatrix and DeltaBuilder, and to open the order
+// fragments that traders encrypted for this DarkNode. Snapshots that are not
+// signed by the dark node are ignored.
+func (node *DarkNode) SyncWithDarkNode(multiAddress identity.MultiAddress) error {
+	syncBlocks, err := node.ClientPool.Sync(multiAddress)
+	if err != nil {
+		return err
+	}
+	for syncBlock := range syncBlocks {
+		snapshot, err := rpc.DeserializeSyncBlock(syncBlock)
+		if err != nil {
+			node.Logger.Warn(fmt.Sprintf("cannot deserialize sync block from dark node %v: %s", multiAddress.Address(), err.Error()))
+			continue
+		}
+		if err := snapshot.VerifySignature(multiAddress.ID()); err != nil {
+			node.Logger.Warn(fmt.Sprintf("cannot verify sync block from dark node %v: %s", multiAddress.Address(), err.Error()))
+			continue
+		}
+		node.restoreSnapshot(snapshot)
+	}
+	return nil
+}
+
+// restoreSnapshot restores a Snapshot from another dark node in the dark
+// pool. The other dark node is trusted to sign the Snapshot, but nothing that
+// it holds is trusted on its own. Every delta fragment must be signed by the
+// dark node that holds its share key. Orders are only removed when a
+// finalization holds the agreement of k dark nodes in the dark pool, and not
+// because the other dark node reconstructed a match. Order fragments must be
+// decrypted by this DarkNode and signed by their trader, and are opened in
+// the same way as order fragments received from the trader.
+func (node *DarkNode) restoreSnapshot(snapshot *compute.Snapshot) {
+	for _, rumors := range snapshot.Finalizations {
+		if err := node.RumorBuilder.VerifyAgreement(rumors, node.inDarkPool); err != nil {
+			node.Logger.Warn(fmt.Sprintf("cannot verify synced finalization: %s", err.Error()))
+			continue
+		}
+		// Orders that have already been matched must never be matched again
+		if node.RumorBuilder.Finalize(rumors) {
+			if err := node.removeMatch(rumors[0]); err != nil {
+				node.Logger.Error(fmt.Sprintf("cannot remove matched order fragments: %s", err.Error()))
+			}
+		}
+	}
+
+	for _, deltaFragments := range [][]*compute.DeltaFragment{snapshot.Matched, snapshot.Mismatched} {
+		for _, deltaFragment := range deltaFragments {
+			if err := node.verifyDeltaFragment(deltaFragment); err != nil {
+				node.Logger.Warn(fmt.Sprintf("cannot verify synced delta fragment: %s", err.Error()))
+				continue
+			}
+			if !node.DeltaBuilder.HasDeltaFragment(deltaFragment.ID) {
+				if err := node.Store.PutDeltaFragment(deltaFragment); err != nil {
+					node.Logger.Error(fmt.Sprintf("cannot store delta fragment: %s", err.Error()))
+				}
+			}
+			node.DeltaBuilder.InsertDeltaFragment(deltaFragment)
+		}
+	}
+
+	// Pending delta fragments are written to the DeltaFragmentWorkerQueue so
+	// that any delta they complete is checked for a match
+	func() {
+		defer func() { recover() }()
+		for _, deltaFragment := range snapshot.Pending {
+			if err := node.verifyDeltaFragment(deltaFragment); err != nil {
+				node.Logger.Warn(fmt.Sprintf("cannot verify synced delta fragment: %s", err.Error()))
+				continue
+			}
+			node.DeltaFragmentWorkerQueue <- deltaFragment
+		}
+	}()
+
+	now := time.Now()
+	for _, encryptedFragment := range snapshot.OrderFragments {
+		if !bytes.Equal(encryptedFragment.To, node.ID) {
+			continue
+		}
+		orderFragment, err := rpc.DecryptOrderFragment(encryptedFragment, node.KeyPair)
+		if err != nil {
+			node.Logger.Warn(fmt.Sprintf("cannot decrypt synced order fragment: %s", err.Error()))
+			continue
+		}
+		if err := orderFragment.Verify(); err != nil {
+			node.Logger.Warn(fmt.Sprintf("cannot verify synced order fragment: %s", err.Error()))
+			continue
+		}
+		if orderFragment.IsExpired(now) {
+			continue
+		}
+		// The order fragment might have been received from the trader already
+		if err := node.openOrder(orderFragment); err != nil && err != compute.ErrNonceUsed {
+			node.Logger.Warn(fmt.Sprintf("cannot open synced order fragment: %s", err.Error()))
+		}
+	}
+}
+
+// verifyDeltaFragment returns an error if a delta fragment is not signed by
+// the dark node in the dark pool that holds the share key of its match bit.
+func (node *DarkNode) verifyDeltaFragment(deltaFragment *compute.DeltaFragment) error {
+	id := node.shareKeyID(deltaFragment.MatchShare.Key)
+	if id == nil {
+		return smpc.ErrUnexpectedShareKey
+	}
+	return deltaFragment.VerifySignature(id)
+}
+
+// OnSync returns signed snapshots of all delta fragments held by the
+// DeltaBuilder, all finalized matches, and the order fragments that traders
+// encrypted for the dark node that is synchronizing. Only dark nodes from the
+// same dark pool can synchronize.
+func (node *DarkNode) OnSync(from identity.MultiAddress) ([]*compute.Snapshot, error) {
+	darkPool := node.DarkOcean.FindPool(node.ID)
+	if darkPool == nil || darkPool.Has(from.ID()) == nil {
+		return nil, ErrNotInDarkPool
+	}
+	snapshot := node.DeltaBuilder.Snapshot()
+	snapshot.OrderFragments = node.EncryptedFragments.EncryptedFragments(from.ID())
+	snapshot.Finalizations = node.RumorBuilder.Finalizations()
+	snapshots := snapshot.Split(syncBlockSize)
+	for _, snapshot := range snapshots {
+		if err := snapshot.Sign(node.KeyPair); err != nil {
+			return nil, err
+		}
+	}
+	return snapshots, nil
+}
+
 // OnOpenOrder writes an order fragment that has been received to the
-// OrderFragmentWorkerQueue. This is a potentially blocking operation, however
-// this delegate method is called on a dedicated goroutine.
-func (node *DarkNode) OnOpenOrder(from identity.MultiAddress, orderFragment *order.Fragment) {
+// OrderFragmentWorkerQueue. The order fragment must have been split in the
+// finite field of the node, and must hold commitments that its shares can be
+// verified against, so that a trader cannot give the dark pool inconsistent
+// order fragments, and a proof that the order is in one of the Markets, which
+// is verified without learning which one. The price and volumes of the order
+// are not verified against its market, because the dark node only holds secret
+// shares of them. IBBO orders must have been opened within the
+// compute.PeggedOrderWindow, because their fills are executed at the midpoint
+// in effect at their open time. The nonce of the order must not have been used
+// by the trader for a different order fragment, so that an order fragment
+// cannot be replayed to open another order. An order fragment that is resent
+// is accepted again. The encrypted order fragments of the other dark nodes in
+// the dark pool are held until the order expires, so that they can be synced
+// by a dark node that missed the order. This is a potentially blocking
+// operation, however this delegate method is called on a dedicated goroutine.
+func (node *DarkNode) OnOpenOrder(from identity.MultiAddress, orderFragment *order.Fragment, encryptedFragments []*order.EncryptedFragment) error {
+	if err := node.openOrder(orderFragment); err != nil {
+		return err
+	}
+	for _, encryptedFragment := range encryptedFragments {
+		if bytes.Equal(encryptedFragment.To, node.ID) || !node.inDarkPool(encryptedFragment.To) {
+			continue
+		}
+		if !bytes.Equal(encryptedFragment.OrderID, orderFragment.OrderID) || !encryptedFragment.OrderExpiry.Equal(orderFragment.OrderExpiry) {
+			continue
+		}
+		node.EncryptedFragments.InsertEncryptedFragment(encryptedFragment)
+	}
+	return nil
+}
+
+// openOrder verifies an order fragment, records its nonce, and writes it to
+// the OrderFragmentWorkerQueue. The signature of the order fragment must have
+// been verified by the caller.
+func (node *DarkNode) openOrder(orderFragment *order.Fragment) error {
+	if err := node.FiniteField.Verify(orderFragment.Field); err != nil {
+		return err
+	}
+	if err := orderFragment.VerifyCommitments(node.VSS, int64(node.DarkPool.Size()*2/3+1)); err != nil {
+		return err
+	}
+	// The order fragment proves that its order is in a listed market, without
+	// revealing which one
+	if err := node.Markets.VerifyFragment(node.VSS, orderFragment); err != nil {
+		return err
+	}
+	if !compute.InPeggedOrderWindow(orderFragment, time.Now()) {
+		return compute.ErrInvalidOpenTime
+	}
+	// The nonce is recorded so that the signed order fragment cannot be
+	// replayed
+	nonce, err := compute.NewNonce(orderFragment)
+	if err != nil {
+		return err
+	}
+	if err := node.Nonces.InsertNonce(nonce); err != nil {
+		return err
+	}
+	if err := node.Store.PutNonce(nonce); err != nil {
+		node.Logger.Error(fmt.Sprintf("cannot store nonce: %s", err.Error()))
+	}
 	// Write to a channel that might be closed
 	func() {
 		defer func() { recover() }()
@@ -347,17 +916,778 @@ func (node *DarkNode) OnOpenOrder(from identity.MultiAddress, orderFragment *ord
 		}
 		node.OrderFragmentWorkerQueue <- orderFragment
 	}()
+	return nil
+}
+
+// OnCancelOrder removes a cancelled order from the DeltaFragmentMatrix and
+// drops all of its delta fragments from the DeltaBuilder. The cancellation is
+// verified against the trader that signed the order fragment, and then
+// forwarded to the rest of the dark pool so that every node removes the order.
+func (node *DarkNode) OnCancelOrder(from identity.MultiAddress, cancellation *order.Cancellation) error {
+	// A partially filled order is cancelled by cancelling its latest residual
+	// order
+	orderID := node.DeltaFragmentMatrix.ResidualOrderID(cancellation.OrderID)
+
+	// Cancellations are forwarded by every node in the dark pool so an order
+	// that is already complete is not an error
+	if node.DeltaFragmentMatrix.HasCompleteOrderFragment(orderID) {
+		return nil
+	}
+	trader, _ := node.orderTrader(orderID)
+	expiry := time.Time{}
+	if orderFragment := node.DeltaFragmentMatrix.OrderFragment(orderID); orderFragment != nil {
+		expiry = orderFragment.OrderExpiry
+	}
+	if err := node.DeltaFragmentMatrix.CancelOrderFragment(cancellation); err != nil {
+		return err
+	}
+	node.DeltaBuilder.RemoveOrder(orderID, expiry)
+	node.removeComparisons(orderID)
+	if err := node.Store.RemoveOrder(orderID); err != nil {
+		node.Logger.Error(fmt.Sprintf("cannot remove cancelled order from store: %s", err.Error()))
+	}
+	node.Logger.Compute(logger.Info, fmt.Sprintf("order %s cancelled", orderID.String()))
+	node.notify(trader, order.NewNotification(order.NotificationCancelled, cancellation.OrderID, nil))
+
+	go node.DarkPool.CoForAll(func(n *dark.Node) {
+		if bytes.Equal(node.ID, n.ID) {
+			return
+		}
+		multiAddress := n.MultiAddress()
+		if multiAddress == nil {
+			return
+		}
+		if err := node.ClientPool.CancelOrder(*multiAddress, cancellation.OrderID, cancellation.Signature); err != nil {
+			node.Logger.Warn(fmt.Sprintf("cannot forward cancellation to dark node %v: %s", n.ID.Address(), err.Error()))
+		}
+	})
+	return nil
+}
+
+// GenerateResidues starts generating Beaver triples with the rest of the dark
+// pool. Every dark node in the dark pool must generate the same ResidueIDs.
+// Once all dark nodes have taken part, the shares of each Beaver triple are
+// available from the ResidueGenerator.
+func (node *DarkNode) GenerateResidues(residueIDs []compute.ResidueID) {
+	node.DarkPool.CoForAll(func(n *dark.Node) {
+		if bytes.Equal(node.ID, n.ID) {
+			return
+		}
+		multiAddress := n.MultiAddress()
+		if multiAddress == nil {
+			return
+		}
+		key := node.shareKey(n.ID)
+
+		randomFragments := &rpc.RandomFragments{}
+		for _, residueID := range residueIDs {
+			randomFragment, err := node.ResidueGenerator.RandomFragment(residueID, key)
+			if err != nil {
+				node.Logger.Compute(logger.Error, fmt.Sprintf("cannot generate random fragment for dark node %v: %s", n.ID.Address(), err.Error()))
+				return
+			}
+			if randomFragment, err = node.signRandomFragment(randomFragment); err != nil {
+				node.Logger.Compute(logger.Error, fmt.Sprintf("cannot sign random fragment: %s", err.Error()))
+				return
+			}
+			randomFragments.RandomFragments = append(randomFragments.RandomFragments, rpc.SerializeRandomFragment(randomFragment))
+		}
+
+		// The dark node responds with the random fragments that it
+		// contributes to this dark node
+		response, err := node.ClientPool.RandomFragmentShares(*multiAddress, randomFragments)
+		if err != nil {
+			node.Logger.Warn(fmt.Sprintf("cannot exchange random fragments with dark node %v: %s", n.ID.Address(), err.Error()))
+			return
+		}
+		for _, serializedRandomFragment := range response.RandomFragments {
+			randomFragment, err := rpc.DeserializeRandomFragment(serializedRandomFragment)
+			if err != nil {
+				node.Logger.Warn(fmt.Sprintf("cannot deserialize random fragment from dark node %v: %s", n.ID.Address(), err.Error()))
+				continue
+			}
+			if err := node.insertRandomFragment(key, randomFragment); err != nil {
+				node.Logger.Warn(fmt.Sprintf("cannot insert random fragment from dark node %v: %s", n.ID.Address(), err.Error()))
+			}
+		}
+	})
+}
+
+// insertRandomFragment inserts a random fragment that must be signed by the
+// dark node that holds the given share key.
+func (node *DarkNode) insertRandomFragment(from int64, randomFragment *compute.RandomFragment) error {
+	signer, err := node.shareKeySigner(from)
+	if err != nil {
+		return err
+	}
+	if err := randomFragment.VerifySignature(signer); err != nil {
+		return err
+	}
+	residueFragments, err := node.ResidueGenerator.InsertRandomFragment(from, randomFragment)
+	if err != nil {
+		return err
+	}
+	if residueFragments != nil {
+		go node.broadcastResidueFragments(residueFragments)
+
+		// Residue fragments from the other dark nodes might have arrived
+		// before the random fragments
+		if residue := node.ResidueGenerator.Residue(randomFragment.ResidueID); residue != nil {
+			node.insertResidue(residue)
+		}
+	}
+	return nil
+}
+
+func (node *DarkNode) insertResidue(residue *compute.ResidueFragment) {
+	node.Logger.Compute(logger.Info, fmt.Sprintf("residue %s generated", residue.ResidueID.String()))
+	node.handleComparison(node.Comparator.InsertResidue(residue))
+}
+
+func (node *DarkNode) broadcastResidueFragments(residueFragments []*compute.ResidueFragment) {
+	node.DarkPool.CoForAll(func(n *dark.Node) {
+		multiAddress := n.MultiAddress()
+		if multiAddress == nil {
+			return
+		}
+		key := node.shareKey(n.ID)
+		if key == 0 || residueFragments[key-1] == nil {
+			return
+		}
+		residueFragment, err := node.signResidueFragment(residueFragments[key-1])
+		if err != nil {
+			node.Logger.Compute(logger.Error, fmt.Sprintf("cannot sign residue fragment: %s", err.Error()))
+			return
+		}
+		serializedResidueFragments := &rpc.ResidueFragments{
+			ResidueFragments: []*rpc.ResidueFragment{rpc.SerializeResidueFragment(residueFragment)},
+		}
+		if err := node.ClientPool.ComputeResidueFragment(*multiAddress, serializedResidueFragments); err != nil {
+			node.Logger.Warn(fmt.Sprintf("cannot send residue fragment to dark node %v: %s", n.ID.Address(), err.Error()))
+		}
+	})
+}
+
+// OnRandomFragmentShares inserts the random fragments that another dark node
+// in the dark pool contributes to this dark node, and returns the signed
+// random fragments that this dark node contributes to it. The random fragments
+// must be signed by the dark node that sent them.
+func (node *DarkNode) OnRandomFragmentShares(from identity.MultiAddress, randomFragments []*compute.RandomFragment) ([]*compute.RandomFragment, error) {
+	key := node.shareKey(from.ID())
+	if key == 0 {
+		return nil, ErrNotInDarkPool
+	}
+	contributions := make([]*compute.RandomFragment, 0, len(randomFragments))
+	for _, randomFragment := range randomFragments {
+		contribution, err := node.ResidueGenerator.RandomFragment(randomFragment.ResidueID, key)
+		if err != nil {
+			return nil, err
+		}
+		if contribution, err = node.signRandomFragment(contribution); err != nil {
+			return nil, err
+		}
+		contributions = append(contributions, contribution)
+		if err := node.insertRandomFragment(key, randomFragment); err != nil {
+			return nil, err
+		}
+	}
+	return contributions, nil
+}
+
+// OnResidueFragmentShares returns the signed residue fragments that this dark
+// node sends to another dark node in the dark pool. Residue fragments that are
+// not ready are omitted.
+func (node *DarkNode) OnResidueFragmentShares(from identity.MultiAddress, residueIDs []compute.ResidueID) ([]*compute.ResidueFragment, error) {
+	key := node.shareKey(from.ID())
+	if key == 0 {
+		return nil, ErrNotInDarkPool
+	}
+	residueFragments := make([]*compute.ResidueFragment, 0, len(residueIDs))
+	for _, residueID := range residueIDs {
+		if residueFragment := node.ResidueGenerator.ResidueFragment(residueID, key); residueFragment != nil {
+			residueFragment, err := node.signResidueFragment(residueFragment)
+			if err != nil {
+				return nil, err
+			}
+			residueFragments = append(residueFragments, residueFragment)
+		}
+	}
+	return residueFragments, nil
+}
+
+// OnComputeResidueFragment inserts the residue fragments that another dark
+// node in the dark pool sends to this dark node. The residue fragments must be
+// signed by the dark node that sent them.
+func (node *DarkNode) OnComputeResidueFragment(from identity.MultiAddress, residueFragments []*compute.ResidueFragment) error {
+	key := node.shareKey(from.ID())
+	if key == 0 {
+		return ErrNotInDarkPool
+	}
+	for _, residueFragment := range residueFragments {
+		if err := residueFragment.VerifySignature(from.ID()); err != nil {
+			return err
+		}
+		residue, err := node.ResidueGenerator.InsertResidueFragment(key, residueFragment)
+		if err != nil {
+			return err
+		}
+		if residue != nil {
+			node.insertResidue(residue)
+		}
+	}
+	return nil
+}
+
+// OnBroadcastAlphaBetaFragment inserts an alpha beta fragment that another
+// dark node in the dark pool has broadcast, and continues the comparisons that
+// were waiting for it. The alpha beta fragment must be signed by the dark node
+// that holds the share key of its shares.
+func (node *DarkNode) OnBroadcastAlphaBetaFragment(from identity.MultiAddress, alphaBetaFragment *compute.AlphaBetaFragment) (*compute.AlphaBetaFragment, error) {
+	key := node.shareKey(from.ID())
+	if key == 0 {
+		return nil, ErrNotInDarkPool
+	}
+	if alphaBetaFragment.AlphaShare.Key != key || alphaBetaFragment.BetaShare.Key != key {
+		return nil, smpc.ErrUnexpectedShareKey
+	}
+	signer, err := node.shareKeySigner(alphaBetaFragment.AlphaShare.Key)
+	if err != nil {
+		return nil, err
+	}
+	if err := alphaBetaFragment.VerifySignature(signer); err != nil {
+		return nil, err
+	}
+	node.handleComparison(node.Comparator.InsertAlphaBetaFragment(alphaBetaFragment))
+	return nil, nil
+}
+
+// CompareDifferenceFragments consumes the difference fragments computed by the
+// OrderFragmentWorker. For each difference fragment, it generates the Beaver
+// triples needed by the secure comparison and starts the comparison. The delta
+// fragments produced by the comparison only hold a share of whether or not the
+// orders match.
+func (node *DarkNode) CompareDifferenceFragments() {
+	for differenceFragment := range node.DifferenceFragmentWorkerQueue {
+		node.GenerateResidues(node.Comparator.ResidueIDs(differenceFragment.DeltaID))
+		node.handleComparison(node.Comparator.InsertDifferenceFragment(differenceFragment))
+	}
+}
+
+// handleComparison sends the alpha beta fragments of a comparison to the rest
+// of the dark pool, and writes a finished delta fragment to the delta fragment
+// queues.
+func (node *DarkNode) handleComparison(alphaBetaFragments []*compute.AlphaBetaFragment, deltaFragment *compute.DeltaFragment, err error) {
+	if err != nil {
+		node.Logger.Compute(logger.Error, fmt.Sprintf("cannot compare order fragments: %s", err.Error()))
+		return
+	}
+	if len(alphaBetaFragments) > 0 {
+		go node.broadcastAlphaBetaFragments(alphaBetaFragments)
+	}
+	if deltaFragment == nil {
+		return
+	}
+	for _, residueID := range node.Comparator.ResidueIDs(deltaFragment.DeltaID) {
+		node.ResidueGenerator.RemoveResidue(residueID)
+	}
+	go node.broadcastDeltaFragment(deltaFragment)
+}
+
+// broadcastDeltaFragment signs a delta fragment computed by this dark node,
+// writes it to the DeltaFragmentBroadcastWorkerQueue, and inserts it. The
+// queues might be closed.
+func (node *DarkNode) broadcastDeltaFragment(deltaFragment *compute.DeltaFragment) {
+	if err := deltaFragment.Sign(node.KeyPair); err != nil {
+		node.Logger.Compute(logger.Error, fmt.Sprintf("cannot sign delta fragment: %s", err.Error()))
+		return
+	}
+	// Write to channels that might be closed
+	defer func() { recover() }()
+	node.DeltaFragmentBroadcastWorkerQueue <- deltaFragment
+	node.insertDeltaFragment(deltaFragment)
+}
+
+// insertDeltaFragment writes a delta fragment to the DeltaFragmentWorkerQueue,
+// unless it is part of a fill in which case it is inserted into the
+// FillBuilder. The DeltaFragmentWorkerQueue might be closed.
+func (node *DarkNode) insertDeltaFragment(deltaFragment *compute.DeltaFragment) {
+	if compute.IsFillDeltaFragment(deltaFragment) {
+		node.insertFillDeltaFragment(deltaFragment)
+		return
+	}
+	node.DeltaFragmentWorkerQueue <- deltaFragment
+}
+
+func (node *DarkNode) broadcastAlphaBetaFragments(alphaBetaFragments []*compute.AlphaBetaFragment) {
+	signedAlphaBetaFragments := make([]*compute.AlphaBetaFragment, len(alphaBetaFragments))
+	for i, alphaBetaFragment := range alphaBetaFragments {
+		signedAlphaBetaFragment := *alphaBetaFragment
+		if err := signedAlphaBetaFragment.Sign(node.KeyPair); err != nil {
+			node.Logger.Compute(logger.Error, fmt.Sprintf("cannot sign alpha beta fragment: %s", err.Error()))
+			return
+		}
+		signedAlphaBetaFragments[i] = &signedAlphaBetaFragment
+	}
+	node.DarkPool.CoForAll(func(n *dark.Node) {
+		if bytes.Equal(node.ID, n.ID) {
+			return
+		}
+		multiAddress := n.MultiAddress()
+		if multiAddress == nil {
+			return
+		}
+		for _, alphaBetaFragment := range signedAlphaBetaFragments {
+			if _, err := node.ClientPool.BroadcastAlphaBetaFragment(*multiAddress, rpc.SerializeAlphaBetaFragment(alphaBetaFragment)); err != nil {
+				node.Logger.Warn(fmt.Sprintf("cannot send alpha beta fragment to dark node %v: %s", n.ID.Address(), err.Error()))
+				return
+			}
+		}
+	})
+}
+
+// signRandomFragment returns a copy of a random fragment signed by this dark
+// node. The ResidueGenerator returns the same random fragment to concurrent
+// callers, so it is never signed in place.
+func (node *DarkNode) signRandomFragment(randomFragment *compute.RandomFragment) (*compute.RandomFragment, error) {
+	signedRandomFragment := *randomFragment
+	if err := signedRandomFragment.Sign(node.KeyPair); err != nil {
+		return nil, err
+	}
+	return &signedRandomFragment, nil
+}
+
+// signResidueFragment returns a copy of a residue fragment signed by this dark
+// node.
+func (node *DarkNode) signResidueFragment(residueFragment *compute.ResidueFragment) (*compute.ResidueFragment, error) {
+	signedResidueFragment := *residueFragment
+	if err := signedResidueFragment.Sign(node.KeyPair); err != nil {
+		return nil, err
+	}
+	return &signedResidueFragment, nil
+}
+
+// shareKeySigner returns the ID of the dark node that must have signed a
+// fragment for the given share key. An smpc.ErrUnexpectedShareKey is returned
+// if no dark node in the dark pool holds the share key.
+func (node *DarkNode) shareKeySigner(key int64) (identity.ID, error) {
+	id := node.shareKeyID(key)
+	if id == nil {
+		return nil, smpc.ErrUnexpectedShareKey
+	}
+	return id, nil
+}
+
+// shareKey returns the key of the shares held by the dark node with the given
+// ID. The key is the position of the dark node in its dark pool, starting from
+// 1. Zero is returned if the dark node is not in the same dark pool.
+func (node *DarkNode) shareKey(id identity.ID) int64 {
+	darkPool := node.DarkOcean.FindPool(node.ID)
+	if darkPool == nil {
+		return 0
+	}
+	key, i := int64(0), int64(0)
+	darkPool.For(func(n *dark.Node) {
+		i++
+		if bytes.Equal(n.ID, id) {
+			key = i
+		}
+	})
+	return key
+}
+
+// shareKeyID returns the ID of the dark node in the same dark pool that holds
+// the given share key. Nil is returned if no dark node holds the share key.
+func (node *DarkNode) shareKeyID(key int64) identity.ID {
+	darkPool := node.DarkOcean.FindPool(node.ID)
+	if darkPool == nil {
+		return nil
+	}
+	var id identity.ID
+	i := int64(0)
+	darkPool.For(func(n *dark.Node) {
+		i++
+		if i == key {
+			id = n.ID
+		}
+	})
+	return id
+}
+
+// inDarkPool returns true if the dark node with the given ID is in the same
+// dark pool as this DarkNode.
+func (node *DarkNode) inDarkPool(id identity.ID) bool {
+	return node.shareKey(id) != 0
+}
+
+// GossipMatches consumes the matches found by the DeltaMatchWorker and gossips
+// a signed Rumor for each match to the rest of the dark pool. The responses
+// from dark nodes that agree with the match are collected, and the match is
+// finalized once a threshold of dark nodes agree.
+func (node *DarkNode) GossipMatches() {
+	for delta := range node.GossipQueue {
+		node.gossipMatch(delta)
+	}
+}
+
+func (node *DarkNode) gossipMatch(delta *compute.Delta) {
+	rumor := compute.NewRumor(delta.BuyOrderID, delta.SellOrderID)
+	if err := rumor.Sign(node.KeyPair); err != nil {
+		node.Logger.Error(fmt.Sprintf("cannot sign rumor: %s", err.Error()))
+		return
+	}
+	node.insertRumor(rumor)
+
+	serializedRumor := rpc.SerializeRumor(rumor)
+	node.DarkPool.CoForAll(func(n *dark.Node) {
+		if bytes.Equal(node.ID, n.ID) {
+			return
+		}
+		multiAddress := n.MultiAddress()
+		if multiAddress == nil {
+			return
+		}
+		response, err := node.ClientPool.Gossip(*multiAddress, serializedRumor)
+		if err != nil {
+			node.Logger.Warn(fmt.Sprintf("cannot gossip rumor to dark node %v: %s", n.ID.Address(), err.Error()))
+			return
+		}
+		// An empty response means that the dark node has not found the
+		// match, and it will gossip its own rumor if it finds it later
+		agreement := rpc.DeserializeRumor(response)
+		if len(agreement.Signature) == 0 {
+			return
+		}
+		if !agreement.BuyOrderID.Equal(rumor.BuyOrderID) || !agreement.SellOrderID.Equal(rumor.SellOrderID) {
+			node.Logger.Warn(fmt.Sprintf("cannot accept rumor from dark node %v: different orders", n.ID.Address()))
+			return
+		}
+		if err := agreement.VerifySignature(n.ID); err != nil {
+			node.Logger.Warn(fmt.Sprintf("cannot accept rumor from dark node %v: %s", n.ID.Address(), err.Error()))
+			return
+		}
+		node.insertRumor(agreement)
+	})
+}
+
+// insertRumor inserts the agreement of a dark node, and finalizes the match
+// when enough dark nodes agree.
+func (node *DarkNode) insertRumor(rumor *compute.Rumor) {
+	rumors, err := node.RumorBuilder.InsertRumor(rumor)
+	if err != nil {
+		node.Logger.Warn(fmt.Sprintf("cannot insert rumor: %s", err.Error()))
+		return
+	}
+	if rumors != nil {
+		go node.finalizeMatch(rumors)
+	}
+}
+
+// finalizeMatch tells the rest of the dark pool that a threshold of dark nodes
+// agree on a match, and finalizes the match locally. The agreeing rumors are
+// sent to the rest of the dark pool as proof of the agreement.
+func (node *DarkNode) finalizeMatch(rumors []*compute.Rumor) {
+	node.finalize(rumors)
+
+	serializedRumors := rpc.SerializeRumors(rumors)
+	node.DarkPool.CoForAll(func(n *dark.Node) {
+		if bytes.Equal(node.ID, n.ID) {
+			return
+		}
+		multiAddress := n.MultiAddress()
+		if multiAddress == nil {
+			return
+		}
+		if _, err := node.ClientPool.Finalize(*multiAddress, serializedRumors); err != nil {
+			node.Logger.Warn(fmt.Sprintf("cannot finalize rumor with dark node %v: %s", n.ID.Address(), err.Error()))
+		}
+	})
+}
+
+// finalize removes both orders of a match from the DeltaFragmentMatrix, and
+// notifies the DeltaNotifications channel and the traders of both orders. The
+// match is inserted into the Settlements, where it waits for the traders to
+// reveal their orders. Each match is only finalized once. If the DarkNode
+// holds fragments for both orders, it starts to fill the orders with the rest
+// of the dark pool.
+func (node *DarkNode) finalize(rumors []*compute.Rumor) {
+	if !node.RumorBuilder.Finalize(rumors) {
+		return
+	}
+	rumor := rumors[0]
+	buyOrderFragment := node.DeltaFragmentMatrix.OrderFragment(rumor.BuyOrderID)
+	sellOrderFragment := node.DeltaFragmentMatrix.OrderFragment(rumor.SellOrderID)
+	buyTrader, buyOrderID := node.orderTrader(rumor.BuyOrderID)
+	sellTrader, sellOrderID := node.orderTrader(rumor.SellOrderID)
+	if err := node.removeMatch(rumor); err != nil {
+		node.Logger.Compute(logger.Error, fmt.Sprintf("cannot remove matched order fragments: %s", err.Error()))
+	}
+	node.Logger.OrderMatch(logger.Info, rumor.DeltaID().String(), rumor.BuyOrderID.String(), rumor.SellOrderID.String())
+	node.notify(buyTrader, order.NewNotification(order.NotificationMatch, buyOrderID, sellOrderID))
+	node.notify(sellTrader, order.NewNotification(order.NotificationMatch, sellOrderID, buyOrderID))
+	if buyTrader != nil && sellTrader != nil {
+		node.Settlements.InsertMatch(rumor.DeltaID(), buyOrderID, sellOrderID, buyTrader, sellTrader)
+	}
+
+	delta := node.DeltaBuilder.Delta(rumor.DeltaID())
+	if delta == nil {
+		// The match was finalized by the rest of the dark pool before this
+		// dark node reconstructed the delta
+		match := stackint.One()
+		delta = &compute.Delta{
+			ID:          rumor.DeltaID(),
+			BuyOrderID:  rumor.BuyOrderID,
+			SellOrderID: rumor.SellOrderID,
+			Match:       &match,
+		}
+	}
+	// Write to a channel that might be closed
+	func() {
+		defer func() { recover() }()
+		node.DeltaNotifications <- delta
+	}()
+
+	if buyOrderFragment != nil && sellOrderFragment != nil {
+		node.startFill(buyOrderFragment, sellOrderFragment)
+	}
+}
+
+// startFill starts the secure comparisons that find the larger of two matched
+// orders. The comparisons are done by the CompareDifferenceFragments loop, in
+// the same way as the comparisons that find matches.
+func (node *DarkNode) startFill(buyOrderFragment, sellOrderFragment *order.Fragment) {
+	commitmentsHash := node.DeltaFragmentMatrix.CommitmentsHash(buyOrderFragment, sellOrderFragment)
+	differenceFragments, deltaFragments := node.FillBuilder.Start(buyOrderFragment, sellOrderFragment, commitmentsHash)
+	// Write to channels that might be closed
+	go func() {
+		defer func() { recover() }()
+		for _, differenceFragment := range differenceFragments {
+			node.DifferenceFragmentWorkerQueue <- differenceFragment
+		}
+		for _, deltaFragment := range deltaFragments {
+			node.broadcastDeltaFragment(deltaFragment)
+		}
+	}()
+}
+
+// insertFillDeltaFragment inserts a delta fragment into the FillBuilder. When
+// the larger order is known, the delta fragments that open the fill are
+// broadcast to the rest of the dark pool. When the opened values are known,
+// the fill is executed.
+func (node *DarkNode) insertFillDeltaFragment(deltaFragment *compute.DeltaFragment) {
+	openings, fill := node.FillBuilder.InsertDeltaFragment(deltaFragment)
+	for _, opening := range openings {
+		go node.broadcastDeltaFragment(opening)
+	}
+	if fill != nil {
+		logFaultyDeltaFragments(node.Logger, node.FillBuilder.FaultyDeltaFragments(fill.ID))
+		node.executeFill(fill)
+	}
+}
+
+// executeFill logs the executed volume of a fill, and inserts the residual
+// order of the larger order into the DeltaFragmentMatrix so that it can be
+// matched against other orders. Fills between IBBO orders are executed at the
+// midpoint of their market that was in effect at the open time of the later
+// order, which is the same on every DarkNode that holds the same midpoint
+// updates. The fill is inserted into the Settlements, and the match is settled
+// if both traders have already revealed their orders.
+func (node *DarkNode) executeFill(fill *compute.Fill) {
+	node.Logger.OrderFill(logger.Info, fill.ID.String(), fill.BuyOrderID.String(), fill.SellOrderID.String(), fill.Volume.String(), fill.ResidualOrderID.String())
+	var price *stackint.Int1024
+	if fill.FstCode != 0 || fill.SndCode != 0 {
+		midpoint, err := node.Midpoints.MidpointAt(fill.FstCode, fill.SndCode, fill.OpenTime)
+		if err != nil {
+			node.Logger.Compute(logger.Error, fmt.Sprintf("cannot execute fill %s at midpoint: %s", fill.ID.String(), err.Error()))
+		} else {
+			node.Logger.Compute(logger.Info, fmt.Sprintf("fill %s executed at midpoint %s", fill.ID.String(), fixed.DecodePrice(midpoint)))
+			price = midpoint
+		}
+	}
+	if match := node.Settlements.InsertFill(fill.ID, fill.Volume, price); match != nil {
+		go node.settle(match)
+	}
+
+	// The comparisons of the fill are started after the orders are removed,
+	// so they are removed once the fill is known
+	node.removeComparisons(fill.BuyOrderID)
+	node.removeComparisons(fill.SellOrderID)
+	if fill.Residual == nil {
+		return
+	}
+
+	differenceFragments, err := node.DeltaFragmentMatrix.InsertResidualOrderFragment(fill.Parent, fill.Residual)
+	if err == compute.ErrOrderFragmentExpired {
+		// The window of an immediate order closed before it was filled
+		// completely, so the remaining volume is cancelled
+		node.Logger.OrderExpired(logger.Info, fill.Residual.OrderID.String())
+		rootOrderFragment := node.DeltaFragmentMatrix.RootOrderFragment(fill.Parent.OrderID)
+		if rootOrderFragment == nil {
+			rootOrderFragment = fill.Parent
+		}
+		node.notify(rootOrderFragment.Trader, order.NewNotification(order.NotificationExpired, rootOrderFragment.OrderID, nil))
+		return
+	}
+	if err != nil {
+		node.Logger.Compute(logger.Error, fmt.Sprintf("cannot insert residual order fragment: %s", err.Error()))
+		return
+	}
+	residual := store.Residual{
+		Root:          node.DeltaFragmentMatrix.RootOrderFragment(fill.Residual.OrderID),
+		OrderFragment: fill.Residual,
+	}
+	if err := node.Store.PutResidual(residual); err != nil {
+		node.Logger.Error(fmt.Sprintf("cannot store residual order fragment: %s", err.Error()))
+	}
+
+	// Write to a channel that might be closed
+	func() {
+		defer func() { recover() }()
+		for _, differenceFragment := range differenceFragments {
+			node.DifferenceFragmentWorkerQueue <- differenceFragment
+		}
+	}()
+}
+
+// OnRevealOrder inserts the Reveal of an order into the Settlements, and
+// settles every match of the order that is ready to be settled. The Reveal is
+// verified by the Settlements. Unlike cancellations, reveals are not forwarded
+// to the rest of the dark pool, because the trader sends the Reveal to every
+// dark node that holds a fragment of the order.
+func (node *DarkNode) OnRevealOrder(from identity.MultiAddress, reveal *order.Reveal) error {
+	matches, err := node.Settlements.InsertReveal(reveal)
+	if err != nil {
+		return err
+	}
+	for _, match := range matches {
+		go node.settle(match)
+	}
+	return nil
+}
+
+// OnUpdateMidpoint inserts a midpoint update that was signed by the price feed
+// into the Midpoints. The first time that the DarkNode receives a midpoint
+// update, it forwards it to the rest of the dark pool, so that every node
+// executes fills at the same midpoints. Midpoint updates are not stored, so a
+// DarkNode that restarts only holds the Midpoints of its Config, and the
+// midpoint updates that it receives afterwards.
+func (node *DarkNode) OnUpdateMidpoint(from identity.MultiAddress, midpointUpdate *compute.MidpointUpdate) error {
+	if node.PriceFeed == "" {
+		return ErrNoPriceFeed
+	}
+	if err := midpointUpdate.VerifySignature(node.PriceFeed.ID()); err != nil {
+		return err
+	}
+	// Midpoint updates are forwarded by every node in the dark pool so an
+	// update that is already known is not an error
+	if !node.Midpoints.InsertMidpointUpdate(midpointUpdate) {
+		return nil
+	}
+	node.Logger.Compute(logger.Info, fmt.Sprintf("midpoint %s from %s", fixed.DecodePrice(midpointUpdate.Midpoint), midpointUpdate.Time.String()))
+
+	go node.DarkPool.CoForAll(func(n *dark.Node) {
+		if bytes.Equal(node.ID, n.ID) {
+			return
+		}
+		multiAddress := n.MultiAddress()
+		if multiAddress == nil {
+			return
+		}
+		if err := node.ClientPool.UpdateMidpoint(*multiAddress, rpc.SerializeMidpointUpdate(midpointUpdate)); err != nil {
+			node.Logger.Warn(fmt.Sprintf("cannot forward midpoint update to dark node %v: %s", n.ID.Address(), err.Error()))
+		}
+	})
+	return nil
+}
+
+// settle a Match using the Settler, and record the outcome in the Settlements.
+// The traders of both orders are notified of the outcome. The orders of a
+// Match that fails to settle are restored, so that they can be matched again.
+// Matches are left pending when the DarkNode has no Settler.
+func (node *DarkNode) settle(match *settlement.Match) {
+	if node.Settler == nil {
+		node.Logger.Warn(fmt.Sprintf("cannot settle match %s: no settler", compute.DeltaID(match.ID).String()))
+		return
+	}
+	err := node.Settler.Settle(match)
+	if node.Settlements.Settled(match.ID, err) == settlement.StatusSettled {
+		node.Logger.OrderSettled(logger.Info, compute.DeltaID(match.ID).String(), match.BuyOrderID.String(), match.SellOrderID.String(), match.Volume.String(), match.Price.String())
+		node.notify(match.Buy.Trader, order.NewNotification(order.NotificationSettled, match.BuyOrderID, match.SellOrderID))
+		node.notify(match.Sell.Trader, order.NewNotification(order.NotificationSettled, match.SellOrderID, match.BuyOrderID))
+		return
+	}
+	node.Logger.Error(fmt.Sprintf("cannot settle match %s: %s", compute.DeltaID(match.ID).String(), err.Error()))
+	node.notify(match.Buy.Trader, order.NewNotification(order.NotificationSettlementFailed, match.BuyOrderID, match.SellOrderID))
+	node.notify(match.Sell.Trader, order.NewNotification(order.NotificationSettlementFailed, match.SellOrderID, match.BuyOrderID))
+	node.restoreMatch(compute.DeltaID(match.ID))
+}
+
+// OnNotifications subscribes a trader to the Notifications for their orders.
+// The Subscription has already been verified by the DarkService.
+func (node *DarkNode) OnNotifications(from identity.MultiAddress, subscription *order.Subscription, done <-chan struct{}) (<-chan *order.Notification, error) {
+	return node.Notifier.Subscribe(subscription.Trader, done), nil
+}
+
+// OnGossip inserts the agreement of the dark node that gossiped the rumor.
+// If this dark node has also found the match, it responds with its own signed
+// rumor. Otherwise, it responds with nil.
+func (node *DarkNode) OnGossip(from identity.MultiAddress, rumor *compute.Rumor) (*compute.Rumor, error) {
+	if node.shareKey(from.ID()) == 0 {
+		return nil, ErrNotInDarkPool
+	}
+	if err := rumor.VerifySignature(from.ID()); err != nil {
+		return nil, err
+	}
+	node.insertRumor(rumor)
+
+	delta := node.DeltaBuilder.Delta(rumor.DeltaID())
+	if delta == nil || !delta.IsMatch() {
+		return nil, nil
+	}
+	agreement := compute.NewRumor(rumor.BuyOrderID, rumor.SellOrderID)
+	if err := agreement.Sign(node.KeyPair); err != nil {
+		return nil, err
+	}
+	node.insertRumor(agreement)
+	return agreement, nil
+}
+
+// OnFinalize finalizes a match that a threshold of the dark pool agreed on.
+// The rumors must be signed by at least k distinct dark nodes in the dark
+// pool, and must all be for the same match.
+func (node *DarkNode) OnFinalize(from identity.MultiAddress, rumors []*compute.Rumor) error {
+	if node.shareKey(from.ID()) == 0 {
+		return ErrNotInDarkPool
+	}
+	if err := node.RumorBuilder.VerifyAgreement(rumors, node.inDarkPool); err != nil {
+		return err
+	}
+	node.finalize(rumors)
+	return nil
 }
 
 // OnBroadcastDeltaFragment writes a delta fragment that has been received to
-// the DeltaFragmentWorkerQueue. This is a potentially blocking operation,
-// however this delegate method is called on a dedicated goroutine.
-func (node *DarkNode) OnBroadcastDeltaFragment(from identity.MultiAddress, deltaFragment *compute.DeltaFragment) {
+// the DeltaFragmentWorkerQueue. The delta fragment must be signed by the dark
+// node that sent it, that dark node must be in the same dark pool, and its
+// share of the match bit must use the share key of that dark node. This is a
+// potentially blocking operation, however this delegate method is called on a
+// dedicated goroutine.
+func (node *DarkNode) OnBroadcastDeltaFragment(from identity.MultiAddress, deltaFragment *compute.DeltaFragment) error {
+	key := node.shareKey(from.ID())
+	if key == 0 {
+		return ErrNotInDarkPool
+	}
+	if deltaFragment.MatchShare.Key != key {
+		return smpc.ErrUnexpectedShareKey
+	}
+	if err := deltaFragment.VerifySignature(from.ID()); err != nil {
+		return err
+	}
 	// Write to a channel that might be closed
 	func() {
 		defer func() { recover() }()
-		node.DeltaFragmentWorkerQueue <- deltaFragment
+		node.insertDeltaFragment(deltaFragment)
 	}()
+	return nil
 }
 
 // Usage logs memory and cpu usage
@@ -21,6 +21,8 @@ import (
 	"github.com/republicprotocol/republic-go/identity"
 	"github.com/republicprotocol/republic-go/network/rpc"
 	"github.com/republicprotocol/republic-go/order"
+	"github.com/republicprotocol/republic-go/order/market"
+	"github.com/republicprotocol/republic-go/shamir"
 	"github.com/republicprotocol/republic-go/stackint"
 )
 
@@ -31,12 +33,17 @@ const (
 
 var primeVal, _ = stackint.FromString("179769313486231590772930519078902473361797697894230657273430081157732675805500963132708477322407536021120113879871393357658789768814416622492847430639474124377767893424865485276302219601246094119453082952085005768838150682342462881473913110540827237163350510684586298239947245938479716304835356329624224137111")
 var Prime = &primeVal
+var VSS, _ = shamir.NewVSS(Prime)
 
 var traderKeypair, _ = identity.NewKeyPair()
 var traderAddress = traderKeypair.Address()
 var traderMulti, _ = identity.NewMultiAddressFromString("/ip4/127.0.0.1/tcp/80/republic/" + traderAddress.String())
 var traderMultiSignature, _ = traderKeypair.Sign(traderMulti)
 
+// Sell orders are opened by a different trader, because orders from the same
+// trader are never matched
+var sellerKeypair, _ = identity.NewKeyPair()
+
 var epochDNR dnr.DarkNodeRegistry
 
 var dnrOuterLock = new(sync.Mutex)
@@ -415,14 +422,16 @@ func sendOrders(nodes []*node.DarkNode) error {
 			return errors.New("fail to parse the amount into a float")
 		}
 		amount = amount * 1000000000000
+		// Dark nodes reject orders that reuse a nonce
+		nonce := uint(time.Now().UnixNano())
 		sellOrder := order.NewOrder(order.TypeLimit, order.ParitySell, time.Now().Add(time.Hour),
 			order.CurrencyCodeETH, order.CurrencyCodeBTC, heapInt(uint(price)), heapInt(uint(amount)),
-			heapInt(uint(amount)), heapInt(1))
+			heapInt(uint(amount)), heapInt(nonce))
 		sellOrders[i] = sellOrder
 
 		buyOrder := order.NewOrder(order.TypeLimit, order.ParityBuy, time.Now().Add(time.Hour),
 			order.CurrencyCodeETH, order.CurrencyCodeBTC, heapInt(uint(price)), heapInt(uint(amount)),
-			heapInt(uint(amount)), heapInt(1))
+			heapInt(uint(amount)), heapInt(nonce))
 		buyOrders[i] = buyOrder
 	}
 
@@ -434,11 +443,11 @@ func sendOrders(nodes []*node.DarkNode) error {
 	for i := range buyOrders {
 		buyOrder, sellOrder := buyOrders[i], sellOrders[i]
 		log.Printf("Sending matched order. [BUY] %s <---> [SELL] %s", buyOrder.ID, sellOrder.ID)
-		buyShares, err := buyOrder.Split(int64(totalNodes), int64(totalNodes*2/3+1), Prime)
+		buyShares, err := buyOrder.SplitVerifiable(int64(totalNodes), int64(totalNodes*2/3+1), VSS, market.NewDefaultRegistry().Pairs())
 		if err != nil {
 			return err
 		}
-		sellShares, err := sellOrder.Split(int64(totalNodes), int64(totalNodes*2/3+1), Prime)
+		sellShares, err := sellOrder.SplitVerifiable(int64(totalNodes), int64(totalNodes*2/3+1), VSS, market.NewDefaultRegistry().Pairs())
 		if err != nil {
 			return err
 		}
@@ -446,7 +455,7 @@ func sendOrders(nodes []*node.DarkNode) error {
 		do.CoForAll(buyShares, func(j int) {
 			// Sign order fragment with trader's keypair
 			buyShares[j].Sign(traderKeypair)
-			pool.OpenOrder(nodes[j].NetworkOptions.MultiAddress, rpc.SerializeOrderFragment(buyShares[j]))
+			pool.OpenOrder(nodes[j].NetworkOptions.MultiAddress, rpc.SerializeOrderFragment(buyShares[j]), nil)
 			if err != nil {
 				log.Printf("Coudln't send order fragment to %s\n", nodes[j].NetworkOptions.MultiAddress.ID())
 				log.Fatal(err)
@@ -454,9 +463,9 @@ func sendOrders(nodes []*node.DarkNode) error {
 		})
 
 		do.CoForAll(sellShares, func(j int) {
-			// Sign order fragment with trader's keypair
-			sellShares[j].Sign(traderKeypair)
-			pool.OpenOrder(nodes[j].NetworkOptions.MultiAddress, rpc.SerializeOrderFragment(sellShares[j]))
+			// Sign order fragment with seller's keypair
+			sellShares[j].Sign(sellerKeypair)
+			pool.OpenOrder(nodes[j].NetworkOptions.MultiAddress, rpc.SerializeOrderFragment(sellShares[j]), nil)
 			if err != nil {
 				log.Printf("Coudln't send order fragment to %s\n", nodes[j].NetworkOptions.MultiAddress.ID())
 				log.Fatal(err)
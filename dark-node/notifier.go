@@ -0,0 +1,80 @@
+package node
+
+import (
+	"github.com/republicprotocol/go-do"
+	"github.com/republicprotocol/republic-go/identity"
+	"github.com/republicprotocol/republic-go/order"
+)
+
+// notificationBufferLimit is the number of Notifications that are buffered for
+// a subscriber. Notifications are dropped for subscribers that do not keep up,
+// so that a slow trader cannot block the DarkNode.
+const notificationBufferLimit = 100
+
+// A Notifier signs Notifications and sends them to the traders that have
+// subscribed to them. A trader can hold many subscriptions at once, and each
+// one receives all Notifications for the orders of the trader.
+type Notifier struct {
+	do.GuardedObject
+
+	keyPair     identity.KeyPair
+	subscribers map[string]map[chan *order.Notification]struct{}
+}
+
+// NewNotifier returns a Notifier that signs Notifications using the given
+// KeyPair.
+func NewNotifier(keyPair identity.KeyPair) *Notifier {
+	return &Notifier{
+		GuardedObject: do.NewGuardedObject(),
+		keyPair:       keyPair,
+		subscribers:   map[string]map[chan *order.Notification]struct{}{},
+	}
+}
+
+// Subscribe returns a channel of the Notifications for the orders of a
+// trader. The subscription is removed, and the channel is closed, when the
+// done channel is closed.
+func (notifier *Notifier) Subscribe(trader identity.ID, done <-chan struct{}) <-chan *order.Notification {
+	notifications := make(chan *order.Notification, notificationBufferLimit)
+
+	notifier.Enter(nil)
+	if _, ok := notifier.subscribers[string(trader)]; !ok {
+		notifier.subscribers[string(trader)] = map[chan *order.Notification]struct{}{}
+	}
+	notifier.subscribers[string(trader)][notifications] = struct{}{}
+	notifier.Exit()
+
+	go func() {
+		<-done
+		notifier.Enter(nil)
+		defer notifier.Exit()
+		delete(notifier.subscribers[string(trader)], notifications)
+		if len(notifier.subscribers[string(trader)]) == 0 {
+			delete(notifier.subscribers, string(trader))
+		}
+		close(notifications)
+	}()
+	return notifications
+}
+
+// Notify signs a Notification and sends it to every subscription of a trader.
+// Nothing is signed when the trader has no subscriptions.
+func (notifier *Notifier) Notify(trader identity.ID, notification *order.Notification) error {
+	notifier.EnterReadOnly(nil)
+	defer notifier.ExitReadOnly()
+
+	subscribers := notifier.subscribers[string(trader)]
+	if len(subscribers) == 0 {
+		return nil
+	}
+	if err := notification.Sign(notifier.keyPair); err != nil {
+		return err
+	}
+	for subscriber := range subscribers {
+		select {
+		case subscriber <- notification:
+		default:
+		}
+	}
+	return nil
+}
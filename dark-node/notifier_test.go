@@ -0,0 +1,64 @@
+package node_test
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/republicprotocol/republic-go/dark-node"
+	"github.com/republicprotocol/republic-go/identity"
+	"github.com/republicprotocol/republic-go/order"
+)
+
+var _ = Describe("Notifier", func() {
+
+	var keyPair identity.KeyPair
+	var trader identity.KeyPair
+	var notifier *node.Notifier
+
+	BeforeEach(func() {
+		var err error
+		keyPair, err = identity.NewKeyPair()
+		Ω(err).ShouldNot(HaveOccurred())
+		trader, err = identity.NewKeyPair()
+		Ω(err).ShouldNot(HaveOccurred())
+		notifier = node.NewNotifier(keyPair)
+	})
+
+	It("should send signed notifications to every subscription of a trader", func() {
+		done := make(chan struct{})
+		defer close(done)
+		fst := notifier.Subscribe(trader.ID(), done)
+		snd := notifier.Subscribe(trader.ID(), done)
+
+		err := notifier.Notify(trader.ID(), order.NewNotification(order.NotificationMatch, order.ID("buy"), order.ID("sell")))
+		Ω(err).ShouldNot(HaveOccurred())
+		for _, notifications := range []<-chan *order.Notification{fst, snd} {
+			notification := <-notifications
+			Ω(notification.Type).Should(Equal(order.NotificationMatch))
+			Ω(notification.OrderID).Should(Equal(order.ID("buy")))
+			Ω(notification.CounterpartyOrderID).Should(Equal(order.ID("sell")))
+			Ω(notification.VerifySignature(keyPair.ID())).ShouldNot(HaveOccurred())
+		}
+	})
+
+	It("should not send notifications to other traders", func() {
+		other, err := identity.NewKeyPair()
+		Ω(err).ShouldNot(HaveOccurred())
+		done := make(chan struct{})
+		defer close(done)
+		notifications := notifier.Subscribe(other.ID(), done)
+
+		err = notifier.Notify(trader.ID(), order.NewNotification(order.NotificationExpired, order.ID("buy"), nil))
+		Ω(err).ShouldNot(HaveOccurred())
+		Consistently(notifications).ShouldNot(Receive())
+	})
+
+	It("should close the subscription when it is done", func() {
+		done := make(chan struct{})
+		notifications := notifier.Subscribe(trader.ID(), done)
+		close(done)
+		Eventually(notifications).Should(BeClosed())
+
+		err := notifier.Notify(trader.ID(), order.NewNotification(order.NotificationCancelled, order.ID("buy"), nil))
+		Ω(err).ShouldNot(HaveOccurred())
+	})
+})
@@ -5,44 +5,56 @@ import (
 
 	"github.com/republicprotocol/republic-go/compute"
 	"github.com/republicprotocol/republic-go/dark"
+	"github.com/republicprotocol/republic-go/identity"
 	"github.com/republicprotocol/republic-go/logger"
 	"github.com/republicprotocol/republic-go/network/rpc"
 	"github.com/republicprotocol/republic-go/order"
+	"github.com/republicprotocol/republic-go/store"
 )
 
 // An OrderFragmentWorker consumes order fragments and computes all
-// combinations of delta fragments.
+// combinations of difference fragments.
 type OrderFragmentWorker struct {
 	logger              *logger.Logger
 	deltaFragmentMatrix *compute.DeltaFragmentMatrix
+	store               store.Store
 	queue               chan *order.Fragment
 }
 
 // NewOrderFragmentWorker returns an OrderFragmentWorker that reads work from
-// a queue and uses a DeltaFragmentMatrix to do computations.
-func NewOrderFragmentWorker(logger *logger.Logger, deltaFragmentMatrix *compute.DeltaFragmentMatrix, queue chan *order.Fragment) *OrderFragmentWorker {
+// a queue and uses a DeltaFragmentMatrix to do computations. Order fragments
+// are written through to the Store.
+func NewOrderFragmentWorker(logger *logger.Logger, deltaFragmentMatrix *compute.DeltaFragmentMatrix, store store.Store, queue chan *order.Fragment) *OrderFragmentWorker {
 	return &OrderFragmentWorker{
 		logger:              logger,
 		deltaFragmentMatrix: deltaFragmentMatrix,
+		store:               store,
 		queue:               queue,
 	}
 }
 
-// Run the OrderFragmentWorker and write all delta fragments to an output
+// Run the OrderFragmentWorker and write all difference fragments to an output
 // queue.
-func (worker *OrderFragmentWorker) Run(queues ...chan *compute.DeltaFragment) error {
+func (worker *OrderFragmentWorker) Run(queues ...chan *compute.DifferenceFragment) error {
 	for orderFragment := range worker.queue {
-		deltaFragments, err := worker.deltaFragmentMatrix.InsertOrderFragment(orderFragment)
+		differenceFragments, err := worker.deltaFragmentMatrix.InsertOrderFragment(orderFragment)
 		if err != nil {
-			return err
+			worker.logger.Compute(logger.Warn, fmt.Sprintf("cannot insert order fragment %s: %s", orderFragment.ID.String(), err.Error()))
+			continue
 		}
-		if deltaFragments != nil {
+		// Order fragments for complete orders are ignored by the matrix
+		if !worker.deltaFragmentMatrix.HasCompleteOrderFragment(orderFragment.OrderID) {
+			if err := worker.store.PutOrderFragment(orderFragment); err != nil {
+				worker.logger.Error(fmt.Sprintf("cannot store order fragment: %s", err.Error()))
+			}
+		}
+		if differenceFragments != nil {
 			// Write to channels that might be closed
 			func() {
 				defer func() { recover() }()
-				for _, deltaFragment := range deltaFragments {
+				for _, differenceFragment := range differenceFragments {
 					for _, queue := range queues {
-						queue <- deltaFragment
+						queue <- differenceFragment
 					}
 				}
 			}()
@@ -91,15 +103,18 @@ func (worker *DeltaFragmentBroadcastWorker) Run() {
 type DeltaFragmentWorker struct {
 	logger       *logger.Logger
 	deltaBuilder *compute.DeltaBuilder
+	store        store.Store
 	queue        chan *compute.DeltaFragment
 }
 
 // NewDeltaFragmentWorker returns an DeltaFragmentWorker that reads work from
-// a queue and uses a DeltaBuilder to do reconstructions.
-func NewDeltaFragmentWorker(logger *logger.Logger, deltaBuilder *compute.DeltaBuilder, queue chan *compute.DeltaFragment) *DeltaFragmentWorker {
+// a queue and uses a DeltaBuilder to do reconstructions. Delta fragments are
+// written through to the Store.
+func NewDeltaFragmentWorker(logger *logger.Logger, deltaBuilder *compute.DeltaBuilder, store store.Store, queue chan *compute.DeltaFragment) *DeltaFragmentWorker {
 	return &DeltaFragmentWorker{
 		logger:       logger,
 		deltaBuilder: deltaBuilder,
+		store:        store,
 		queue:        queue,
 	}
 }
@@ -107,8 +122,15 @@ func NewDeltaFragmentWorker(logger *logger.Logger, deltaBuilder *compute.DeltaBu
 // Run the DeltaFragmentWorker and write all deltas to  an output queue.
 func (worker *DeltaFragmentWorker) Run(queues ...chan *compute.Delta) {
 	for deltaFragment := range worker.queue {
+		if !worker.deltaBuilder.HasDeltaFragment(deltaFragment.ID) {
+			if err := worker.store.PutDeltaFragment(deltaFragment); err != nil {
+				worker.logger.Error(fmt.Sprintf("cannot store delta fragment: %s", err.Error()))
+			}
+		}
 		delta := worker.deltaBuilder.InsertDeltaFragment(deltaFragment)
 		if delta != nil {
+			logFaultyDeltaFragments(worker.logger, worker.deltaBuilder.FaultyDeltaFragments(delta.ID))
+
 			// Write to channels that might be closed
 			func() {
 				defer func() { recover() }()
@@ -123,37 +145,46 @@ func (worker *DeltaFragmentWorker) Run(queues ...chan *compute.Delta) {
 // A DeltaMatchWorker consumes reconstructed deltas and calculates whether or not
 // the two orders can be matched
 type DeltaMatchWorker struct {
-	logger              *logger.Logger
-	deltaFragmentMatrix *compute.DeltaFragmentMatrix
-	queue               chan *compute.Delta
+	logger *logger.Logger
+	queue  chan *compute.Delta
 }
 
 // NewDeltaMatchWorker returns a new DeltaMatchWorker consumes deltas from the queue
-// and removes the fragments from the DeltaFragmentMatrix if the two orders match
-func NewDeltaMatchWorker(logger *logger.Logger, deltaFragmentMatrix *compute.DeltaFragmentMatrix, queue chan *compute.Delta) *DeltaMatchWorker {
+// and forwards the deltas for orders that match
+func NewDeltaMatchWorker(logger *logger.Logger, queue chan *compute.Delta) *DeltaMatchWorker {
 	return &DeltaMatchWorker{
-		logger:              logger,
-		deltaFragmentMatrix: deltaFragmentMatrix,
-		queue:               queue,
+		logger: logger,
+		queue:  queue,
 	}
 }
 
-// Run the DeltaMatchWorker. When two orders match:
-// 1) Remove the fragments for each order from the OrderFragmentMatrix and
-// 2) Write the match to the output queues
+// Run the DeltaMatchWorker. When two orders match, the match is written to the
+// output queues so that it can be gossiped to the rest of the dark pool. The
+// orders are not removed until the match is finalized.
 func (worker *DeltaMatchWorker) Run(queues ...chan *compute.Delta) {
 	for delta := range worker.queue {
-		if delta.IsMatch(prime) {
-			if err := worker.deltaFragmentMatrix.RemoveOrderFragment(delta.BuyOrderID); err != nil {
-				worker.logger.Compute(logger.Error, fmt.Sprintf("cannot remove buy order fragment: %s", err.Error()))
-			}
-			if err := worker.deltaFragmentMatrix.RemoveOrderFragment(delta.SellOrderID); err != nil {
-				worker.logger.Compute(logger.Error, fmt.Sprintf("cannot remove sell order fragment: %s", err.Error()))
-			}
-			worker.logger.OrderMatch(logger.Info, delta.ID.String(), delta.BuyOrderID.String(), delta.SellOrderID.String())
-			for _, queue := range queues {
-				queue <- delta
-			}
+		if delta.IsMatch() {
+			worker.logger.Compute(logger.Info, fmt.Sprintf("delta %s matched", delta.ID.String()))
+			// Write to channels that might be closed
+			func() {
+				defer func() { recover() }()
+				for _, queue := range queues {
+					queue <- delta
+				}
+			}()
+		}
+	}
+}
+
+// logFaultyDeltaFragments reports the dark nodes that sent faulty delta
+// fragments.
+func logFaultyDeltaFragments(logger *logger.Logger, faultyDeltaFragments []*compute.DeltaFragment) {
+	for _, faultyDeltaFragment := range faultyDeltaFragments {
+		signer, err := identity.RecoverSigner(faultyDeltaFragment, faultyDeltaFragment.Signature)
+		if err != nil {
+			logger.Warn(fmt.Sprintf("faulty delta fragment %v with share %v from an unknown dark node", faultyDeltaFragment.ID, faultyDeltaFragment.MatchShare.Key))
+			continue
 		}
+		logger.Warn(fmt.Sprintf("faulty delta fragment %v with share %v from %v", faultyDeltaFragment.ID, faultyDeltaFragment.MatchShare.Key, signer.Address()))
 	}
 }
@@ -49,6 +49,16 @@ func (ocean *Ocean) FindPool(id identity.ID) *Pool {
 	return nil
 }
 
+// Pools returns the Pools of the current epoch. The Pools are replaced, not
+// modified, when the Ocean is updated.
+func (ocean *Ocean) Pools() Pools {
+	ocean.EnterReadOnly(nil)
+	defer ocean.ExitReadOnly()
+	pools := make(Pools, len(ocean.pools))
+	copy(pools, ocean.pools)
+	return pools
+}
+
 // Update updates the dark ocean from the registrar contract
 func (ocean *Ocean) Update() error {
 	ocean.Enter(nil)
@@ -123,7 +133,12 @@ func (ocean *Ocean) update() error {
 			return err
 		}
 
-		pools[pool].Append(NewNode(nodeIDs[n]))
+		node := NewNode(nodeIDs[n])
+		node.PublicKey, err = ocean.darkNodeRegistry.GetPublicKey(nodeIDs[n])
+		if err != nil {
+			return err
+		}
+		pools[pool].Append(node)
 	}
 
 	ocean.pools = pools
@@ -8,9 +8,11 @@ import (
 	"github.com/republicprotocol/republic-go/identity"
 )
 
-// A Node represents a dark node in a dark ocean pool
+// A Node represents a dark node in a dark ocean pool. The PublicKey is the
+// public key that the dark node registered, and it is nil if it is not known.
 type Node struct {
 	identity.ID
+	PublicKey    []byte
 	mu           *sync.RWMutex
 	multiAddress *identity.MultiAddress
 }
@@ -1,6 +1,9 @@
 package hyper
 
 import (
+	"bytes"
+	"sort"
+
 	"github.com/republicprotocol/go-do"
 	"github.com/republicprotocol/republic-go/order"
 )
@@ -17,6 +20,14 @@ type Tuple struct {
 	rhs order.ID
 }
 
+// NewTuple returns a Tuple for a buy order and a sell order that match.
+func NewTuple(buyOrder order.ID, sellOrder order.ID) Tuple {
+	return Tuple{
+		lhs: buyOrder,
+		rhs: sellOrder,
+	}
+}
+
 // A ConflictSet contains a set of order matches that are in a direct or transitive conflict.
 type ConflictSet struct {
 	id     uint64
@@ -28,15 +39,29 @@ type ConflictSet struct {
 type Drive struct {
 	do.GuardedObject
 
+	delegate                  Delegate
 	conflictSetsNextID        uint64
 	conflictSets              map[uint64]*ConflictSet
 	tupleToConflictSetMapping map[string]*ConflictSet
 }
 
+// NewDrive returns a Drive that notifies the Delegate when it closes a
+// conflict set.
+func NewDrive(delegate Delegate) *Drive {
+	return &Drive{
+		GuardedObject:             do.NewGuardedObject(),
+		delegate:                  delegate,
+		conflictSetsNextID:        0,
+		conflictSets:              map[uint64]*ConflictSet{},
+		tupleToConflictSetMapping: map[string]*ConflictSet{},
+	}
+}
+
 // AddTuple inserts a tuple into the Hyperdrive.
 // If no conflict set is found, a new one is created.
 // If one conflict set is found, the tuple is added to it.
 // If two conflict sets are found, they are merged and the tuple added to the combined set.
+// Tuples with an order that has already been executed are ignored.
 func (hyperdrive *Drive) AddTuple(tuple Tuple) {
 	hyperdrive.Enter(nil)
 	defer hyperdrive.Exit()
@@ -59,6 +84,7 @@ func (hyperdrive *Drive) addTuple(tuple Tuple) {
 			return
 		}
 		lhsConflictSet.tuples = append(lhsConflictSet.tuples, tuple)
+		hyperdrive.tupleToConflictSetMapping[string(tuple.rhs)] = lhsConflictSet
 		return
 	}
 	if rhsConflictSet != nil {
@@ -66,25 +92,28 @@ func (hyperdrive *Drive) addTuple(tuple Tuple) {
 			return
 		}
 		rhsConflictSet.tuples = append(rhsConflictSet.tuples, tuple)
+		hyperdrive.tupleToConflictSetMapping[string(tuple.lhs)] = rhsConflictSet
 		return
 	}
 	hyperdrive.createConflictSet(tuple)
 }
 
 func (hyperdrive *Drive) mergeConflictSets(lhsConflictSet, rhsConflictSet *ConflictSet) {
-	if lhsConflictSet == nil || rhsConflictSet == nil {
+	if lhsConflictSet == nil || rhsConflictSet == nil || lhsConflictSet == rhsConflictSet {
 		return
 	}
 	for _, tuple := range rhsConflictSet.tuples {
 		hyperdrive.tupleToConflictSetMapping[string(tuple.lhs)] = lhsConflictSet
 		hyperdrive.tupleToConflictSetMapping[string(tuple.rhs)] = lhsConflictSet
 	}
+	lhsConflictSet.tuples = append(lhsConflictSet.tuples, rhsConflictSet.tuples...)
 	delete(hyperdrive.conflictSets, rhsConflictSet.id)
 }
 
 func (hyperdrive *Drive) createConflictSet(tuple Tuple) {
 	conflictSet := &ConflictSet{
 		id:     hyperdrive.conflictSetsNextID,
+		open:   true,
 		tuples: []Tuple{tuple},
 	}
 	hyperdrive.tupleToConflictSetMapping[string(tuple.lhs)] = conflictSet
@@ -92,3 +121,99 @@ func (hyperdrive *Drive) createConflictSet(tuple Tuple) {
 	hyperdrive.conflictSets[hyperdrive.conflictSetsNextID] = conflictSet
 	hyperdrive.conflictSetsNextID++
 }
+
+// CloseConflictSet closes the conflict set that contains an order. The
+// Delegate is notified about the orders that are executed, and the orders
+// that are released. Released orders can be added to new conflict sets, but
+// executed orders cannot. Nothing happens if the order is not in an open
+// conflict set.
+func (hyperdrive *Drive) CloseConflictSet(orderID order.ID) {
+	executed, released := func() ([]Tuple, []order.ID) {
+		hyperdrive.Enter(nil)
+		defer hyperdrive.Exit()
+		conflictSet := hyperdrive.tupleToConflictSetMapping[string(orderID)]
+		if conflictSet == nil || !conflictSet.open {
+			return nil, nil
+		}
+		return hyperdrive.closeConflictSet(conflictSet)
+	}()
+	hyperdrive.notify(executed, released)
+}
+
+// CloseConflictSets closes all open conflict sets, in the order that they
+// were created. See CloseConflictSet.
+func (hyperdrive *Drive) CloseConflictSets() {
+	executed, released := func() ([]Tuple, []order.ID) {
+		hyperdrive.Enter(nil)
+		defer hyperdrive.Exit()
+		ids := make([]uint64, 0, len(hyperdrive.conflictSets))
+		for id := range hyperdrive.conflictSets {
+			ids = append(ids, id)
+		}
+		sort.Slice(ids, func(i, j int) bool { return ids[i] < ids[j] })
+
+		executed, released := []Tuple{}, []order.ID{}
+		for _, id := range ids {
+			executedInSet, releasedInSet := hyperdrive.closeConflictSet(hyperdrive.conflictSets[id])
+			executed = append(executed, executedInSet...)
+			released = append(released, releasedInSet...)
+		}
+		return executed, released
+	}()
+	hyperdrive.notify(executed, released)
+}
+
+// closeConflictSet picks a maximal set of tuples that do not share an order.
+// Tuples are considered in order of their buy order ID, and then their sell
+// order ID, so that every Drive that has the same conflict set makes the same
+// choice, regardless of the order in which tuples were added.
+func (hyperdrive *Drive) closeConflictSet(conflictSet *ConflictSet) ([]Tuple, []order.ID) {
+	tuples := make([]Tuple, len(conflictSet.tuples))
+	copy(tuples, conflictSet.tuples)
+	sort.Slice(tuples, func(i, j int) bool {
+		if cmp := bytes.Compare(tuples[i].lhs, tuples[j].lhs); cmp != 0 {
+			return cmp < 0
+		}
+		return bytes.Compare(tuples[i].rhs, tuples[j].rhs) < 0
+	})
+
+	executed := []Tuple{}
+	executedOrders := map[string]bool{}
+	for _, tuple := range tuples {
+		if executedOrders[string(tuple.lhs)] || executedOrders[string(tuple.rhs)] {
+			continue
+		}
+		executed = append(executed, tuple)
+		executedOrders[string(tuple.lhs)] = true
+		executedOrders[string(tuple.rhs)] = true
+	}
+
+	released := []order.ID{}
+	for _, tuple := range tuples {
+		for _, orderID := range []order.ID{tuple.lhs, tuple.rhs} {
+			if executedOrders[string(orderID)] || hyperdrive.tupleToConflictSetMapping[string(orderID)] != conflictSet {
+				continue
+			}
+			released = append(released, orderID)
+			delete(hyperdrive.tupleToConflictSetMapping, string(orderID))
+		}
+	}
+
+	// The conflict set stays mapped to by its executed orders, so that they
+	// cannot be added to another conflict set
+	conflictSet.open = false
+	delete(hyperdrive.conflictSets, conflictSet.id)
+	return executed, released
+}
+
+func (hyperdrive *Drive) notify(executed []Tuple, released []order.ID) {
+	if hyperdrive.delegate == nil {
+		return
+	}
+	for _, tuple := range executed {
+		hyperdrive.delegate.OnOrderExecuted(tuple.lhs, tuple.rhs)
+	}
+	for _, orderID := range released {
+		hyperdrive.delegate.OnOrderReleased(orderID)
+	}
+}
@@ -2,10 +2,142 @@ package hyper_test
 
 import (
 	. "github.com/onsi/ginkgo"
-	// . "github.com/onsi/gomega"
-	// . "github.com/republicprotocol/republic-go/hyperdrive"
+	. "github.com/onsi/gomega"
+	. "github.com/republicprotocol/republic-go/hyperdrive"
+
+	"github.com/republicprotocol/republic-go/order"
 )
 
 var _ = Describe("Hyperdrive", func() {
 
+	buy1, buy2, buy3 := order.ID("buy1"), order.ID("buy2"), order.ID("buy3")
+	sell1, sell2, sell3 := order.ID("sell1"), order.ID("sell2"), order.ID("sell3")
+
+	Context("when closing conflict sets", func() {
+
+		It("should execute a tuple without conflicts", func() {
+			delegate := newMockDelegate()
+			drive := NewDrive(delegate)
+			drive.AddTuple(NewTuple(buy1, sell1))
+			drive.CloseConflictSet(buy1)
+
+			Ω(delegate.executed).Should(Equal([][2]string{{"buy1", "sell1"}}))
+			Ω(delegate.released).Should(BeEmpty())
+		})
+
+		It("should execute a maximal set of tuples and release the other orders", func() {
+			delegate := newMockDelegate()
+			drive := NewDrive(delegate)
+			drive.AddTuple(NewTuple(buy1, sell1))
+			drive.AddTuple(NewTuple(buy1, sell2))
+			drive.AddTuple(NewTuple(buy2, sell2))
+			drive.AddTuple(NewTuple(buy3, sell2))
+			drive.CloseConflictSet(sell2)
+
+			Ω(delegate.executed).Should(Equal([][2]string{{"buy1", "sell1"}, {"buy2", "sell2"}}))
+			Ω(delegate.released).Should(Equal([]string{"buy3"}))
+		})
+
+		It("should make the same choice regardless of the order of tuples", func() {
+			tuples := []Tuple{
+				NewTuple(buy1, sell1),
+				NewTuple(buy1, sell2),
+				NewTuple(buy2, sell1),
+				NewTuple(buy2, sell3),
+				NewTuple(buy3, sell3),
+			}
+			delegate := newMockDelegate()
+			drive := NewDrive(delegate)
+			for _, tuple := range tuples {
+				drive.AddTuple(tuple)
+			}
+			drive.CloseConflictSets()
+
+			reversedDelegate := newMockDelegate()
+			reversedDrive := NewDrive(reversedDelegate)
+			for i := len(tuples) - 1; i >= 0; i-- {
+				reversedDrive.AddTuple(tuples[i])
+			}
+			reversedDrive.CloseConflictSets()
+
+			Ω(delegate.executed).Should(Equal(reversedDelegate.executed))
+			Ω(delegate.released).Should(Equal(reversedDelegate.released))
+		})
+
+		It("should merge conflict sets", func() {
+			delegate := newMockDelegate()
+			drive := NewDrive(delegate)
+			drive.AddTuple(NewTuple(buy1, sell1))
+			drive.AddTuple(NewTuple(buy2, sell2))
+			drive.AddTuple(NewTuple(buy2, sell1))
+			drive.CloseConflictSet(buy1)
+
+			// All tuples are in one conflict set so closing it must settle
+			// every order
+			Ω(delegate.executed).Should(Equal([][2]string{{"buy1", "sell1"}, {"buy2", "sell2"}}))
+			drive.CloseConflictSets()
+			Ω(delegate.executed).Should(HaveLen(2))
+		})
+
+		It("should not close conflict sets for other orders", func() {
+			delegate := newMockDelegate()
+			drive := NewDrive(delegate)
+			drive.AddTuple(NewTuple(buy1, sell1))
+			drive.AddTuple(NewTuple(buy2, sell2))
+			drive.CloseConflictSet(buy1)
+			Ω(delegate.executed).Should(Equal([][2]string{{"buy1", "sell1"}}))
+			drive.CloseConflictSet(buy3)
+			Ω(delegate.executed).Should(HaveLen(1))
+		})
+	})
+
+	Context("when orders have been settled", func() {
+
+		It("should execute each order at most once", func() {
+			delegate := newMockDelegate()
+			drive := NewDrive(delegate)
+			drive.AddTuple(NewTuple(buy1, sell1))
+			drive.CloseConflictSet(buy1)
+			drive.CloseConflictSet(buy1)
+
+			drive.AddTuple(NewTuple(buy1, sell2))
+			drive.AddTuple(NewTuple(buy2, sell1))
+			drive.CloseConflictSets()
+
+			Ω(delegate.executed).Should(Equal([][2]string{{"buy1", "sell1"}}))
+		})
+
+		It("should allow released orders to be executed later", func() {
+			delegate := newMockDelegate()
+			drive := NewDrive(delegate)
+			drive.AddTuple(NewTuple(buy1, sell1))
+			drive.AddTuple(NewTuple(buy2, sell1))
+			drive.CloseConflictSet(sell1)
+			Ω(delegate.released).Should(Equal([]string{"buy2"}))
+
+			drive.AddTuple(NewTuple(buy2, sell2))
+			drive.CloseConflictSet(buy2)
+			Ω(delegate.executed).Should(Equal([][2]string{{"buy1", "sell1"}, {"buy2", "sell2"}}))
+		})
+	})
 })
+
+type mockDelegate struct {
+	executed [][2]string
+	released []string
+}
+
+func newMockDelegate() *mockDelegate {
+	return &mockDelegate{
+		executed: [][2]string{},
+		released: []string{},
+	}
+}
+
+func (delegate *mockDelegate) OnOrderReleased(orderID order.ID) {
+	delegate.released = append(delegate.released, string(orderID))
+}
+
+func (delegate *mockDelegate) OnOrderExecuted(buyOrderID order.ID, sellOrderID order.ID) {
+	delegate.executed = append(delegate.executed, [2]string{string(buyOrderID), string(sellOrderID)})
+}
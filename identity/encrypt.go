@@ -0,0 +1,43 @@
+package identity
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"errors"
+
+	"github.com/ethereum/go-ethereum/crypto/ecies"
+	"github.com/ethereum/go-ethereum/crypto/secp256k1"
+)
+
+// ErrInvalidPublicKey is returned when a public key is not a point on the
+// SECP256K1 S256 elliptic curve.
+var ErrInvalidPublicKey = errors.New("invalid public key")
+
+// PublicKeyLength is the length of a public key, encoded as its X and Y
+// coordinates.
+const PublicKeyLength = 64
+
+// PublicKeyBytes returns the public key of the KeyPair, encoded as its X and Y
+// coordinates. This is the public key that a dark node registers.
+func (keyPair KeyPair) PublicKeyBytes() []byte {
+	return elliptic.Marshal(secp256k1.S256(), keyPair.PublicKey.X, keyPair.PublicKey.Y)[1:]
+}
+
+// Encrypt a message using ECIES so that only the owner of the public key can
+// decrypt it. The public key must be encoded as its X and Y coordinates.
+func Encrypt(publicKey []byte, message []byte) ([]byte, error) {
+	if len(publicKey) != PublicKeyLength {
+		return nil, ErrInvalidPublicKey
+	}
+	x, y := elliptic.Unmarshal(secp256k1.S256(), append([]byte{4}, publicKey...))
+	if x == nil {
+		return nil, ErrInvalidPublicKey
+	}
+	return ecies.Encrypt(rand.Reader, ecies.ImportECDSAPublic(&ecdsa.PublicKey{Curve: secp256k1.S256(), X: x, Y: y}), message, nil, nil)
+}
+
+// Decrypt a message that was encrypted for the public key of the KeyPair.
+func (keyPair KeyPair) Decrypt(ciphertext []byte) ([]byte, error) {
+	return ecies.ImportECDSA(keyPair.PrivateKey).Decrypt(rand.Reader, ciphertext, nil, nil)
+}
@@ -0,0 +1,35 @@
+package identity_test
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/republicprotocol/republic-go/identity"
+)
+
+var _ = Describe("Encryption", func() {
+
+	It("should only be decrypted by the owner of the public key", func() {
+		keyPair, err := identity.NewKeyPair()
+		Ω(err).ShouldNot(HaveOccurred())
+		other, err := identity.NewKeyPair()
+		Ω(err).ShouldNot(HaveOccurred())
+
+		publicKey := keyPair.PublicKeyBytes()
+		Ω(publicKey).Should(HaveLen(identity.PublicKeyLength))
+		ciphertext, err := identity.Encrypt(publicKey, []byte("message"))
+		Ω(err).ShouldNot(HaveOccurred())
+
+		message, err := keyPair.Decrypt(ciphertext)
+		Ω(err).ShouldNot(HaveOccurred())
+		Ω(message).Should(Equal([]byte("message")))
+		_, err = other.Decrypt(ciphertext)
+		Ω(err).Should(HaveOccurred())
+	})
+
+	It("should reject invalid public keys", func() {
+		_, err := identity.Encrypt([]byte("public key"), []byte("message"))
+		Ω(err).Should(Equal(identity.ErrInvalidPublicKey))
+		_, err = identity.Encrypt(make([]byte, identity.PublicKeyLength), []byte("message"))
+		Ω(err).Should(Equal(identity.ErrInvalidPublicKey))
+	})
+})
@@ -147,6 +147,51 @@ func (logger *Logger) OrderMatch(ty Type, id, buyID, sellID string) {
 	})
 }
 
+// OrderFill logs an OrderFillEvent. The residual ID is empty when both
+// orders were filled completely.
+func (logger *Logger) OrderFill(ty Type, id, buyID, sellID, volume, residualID string) {
+	logger.Log(Log{
+		Timestamp: time.Now(),
+		Type:      ty,
+		EventType: OrderFill,
+		Event: OrderFillEvent{
+			ID:         id,
+			BuyID:      buyID,
+			SellID:     sellID,
+			Volume:     volume,
+			ResidualID: residualID,
+		},
+	})
+}
+
+// OrderSettled logs an OrderSettledEvent.
+func (logger *Logger) OrderSettled(ty Type, id, buyID, sellID, volume, price string) {
+	logger.Log(Log{
+		Timestamp: time.Now(),
+		Type:      ty,
+		EventType: OrderSettled,
+		Event: OrderSettledEvent{
+			ID:     id,
+			BuyID:  buyID,
+			SellID: sellID,
+			Volume: volume,
+			Price:  price,
+		},
+	})
+}
+
+// OrderExpired logs an OrderExpiredEvent.
+func (logger *Logger) OrderExpired(ty Type, id string) {
+	logger.Log(Log{
+		Timestamp: time.Now(),
+		Type:      ty,
+		EventType: OrderExpired,
+		Event: OrderExpiredEvent{
+			ID: id,
+		},
+	})
+}
+
 // BuyOrderReceived logs an OrderReceivedEvent.
 func (logger *Logger) BuyOrderReceived(ty Type, id, fragmentID string) {
 	logger.Log(Log{
@@ -219,6 +264,9 @@ const (
 	Ethereum      = EventType("ethereum")
 	OrderMatch    = EventType("orderMatch")
 	OrderReceived = EventType("orderReceived")
+	OrderFill     = EventType("orderFill")
+	OrderSettled  = EventType("orderSettled")
+	OrderExpired  = EventType("orderExpired")
 	Network       = EventType("network")
 	Compute       = EventType("compute")
 )
@@ -276,6 +324,46 @@ func (event OrderMatchEvent) String() string {
 	return fmt.Sprintf("buy = %s; sell = %s", event.BuyID, event.SellID)
 }
 
+// OrderFillEvent logs the volume executed between two matched orders, and the
+// residual order that remains active
+type OrderFillEvent struct {
+	ID         string `json:"id"`
+	BuyID      string `json:"buyId"`
+	SellID     string `json:"sellId"`
+	Volume     string `json:"volume"`
+	ResidualID string `json:"residualId,omitempty"`
+}
+
+func (event OrderFillEvent) String() string {
+	if event.ResidualID == "" {
+		return fmt.Sprintf("buy = %s; sell = %s; volume = %s", event.BuyID, event.SellID, event.Volume)
+	}
+	return fmt.Sprintf("buy = %s; sell = %s; volume = %s; residual = %s", event.BuyID, event.SellID, event.Volume, event.ResidualID)
+}
+
+// OrderSettledEvent logs a match that has been settled, and the volume and
+// price at which it was settled
+type OrderSettledEvent struct {
+	ID     string `json:"id"`
+	BuyID  string `json:"buyId"`
+	SellID string `json:"sellId"`
+	Volume string `json:"volume"`
+	Price  string `json:"price"`
+}
+
+func (event OrderSettledEvent) String() string {
+	return fmt.Sprintf("buy = %s; sell = %s; volume = %s; price = %s", event.BuyID, event.SellID, event.Volume, event.Price)
+}
+
+// OrderExpiredEvent logs an order that has been evicted after it expired
+type OrderExpiredEvent struct {
+	ID string `json:"id"`
+}
+
+func (event OrderExpiredEvent) String() string {
+	return "order = " + event.ID
+}
+
 // OrderReceivedEvent logs a newly received buy or sell fragment
 type OrderReceivedEvent struct {
 	BuyID      *string `json:"buyId,omitempty"`
@@ -2,6 +2,7 @@ package network
 
 import (
 	"context"
+	"time"
 
 	"github.com/republicprotocol/go-do"
 	"github.com/republicprotocol/republic-go/compute"
@@ -15,17 +16,20 @@ import (
 // A DarkDelegate is used as a callback interface to inject behavior into the
 // DarkService service.
 type DarkDelegate interface {
-	// OnSync(from identity.MultiAddress)
+	OnSync(from identity.MultiAddress) ([]*compute.Snapshot, error)
 
 	// OnSignOrderFragment(from identity.MultiAddress)
-	OnOpenOrder(from identity.MultiAddress, orderFragment *order.Fragment)
-	// OnCancelOrder(from identity.MultiAddress)
-
-	// OnRandomFragmentShares(from identity.MultiAddress)
-	// OnResidueFragmentShares(from identity.MultiAddress)
-	// OnComputeResidueFragment(from identity.MultiAddress)
-	// OnBroadcastAlphaBetaFragment(from identity.MultiAddress)
-	OnBroadcastDeltaFragment(from identity.MultiAddress, deltaFragment *compute.DeltaFragment)
+	OnOpenOrder(from identity.MultiAddress, orderFragment *order.Fragment, encryptedFragments []*order.EncryptedFragment) error
+	OnCancelOrder(from identity.MultiAddress, cancellation *order.Cancellation) error
+	OnNotifications(from identity.MultiAddress, subscription *order.Subscription, done <-chan struct{}) (<-chan *order.Notification, error)
+	OnRevealOrder(from identity.MultiAddress, reveal *order.Reveal) error
+	OnUpdateMidpoint(from identity.MultiAddress, midpointUpdate *compute.MidpointUpdate) error
+
+	OnRandomFragmentShares(from identity.MultiAddress, randomFragments []*compute.RandomFragment) ([]*compute.RandomFragment, error)
+	OnResidueFragmentShares(from identity.MultiAddress, residueIDs []compute.ResidueID) ([]*compute.ResidueFragment, error)
+	OnComputeResidueFragment(from identity.MultiAddress, residueFragments []*compute.ResidueFragment) error
+	OnBroadcastAlphaBetaFragment(from identity.MultiAddress, alphaBetaFragment *compute.AlphaBetaFragment) (*compute.AlphaBetaFragment, error)
+	OnBroadcastDeltaFragment(from identity.MultiAddress, deltaFragment *compute.DeltaFragment) error
 }
 
 // DarkService implements the gRPC Dark service.
@@ -65,7 +69,23 @@ func (service *DarkService) Sync(syncRequest *rpc.SyncRequest, stream rpc.Dark_S
 }
 
 func (service *DarkService) sync(syncRequest *rpc.SyncRequest, stream rpc.Dark_SyncServer) error {
-	// todo : unimplemented
+	from, sig, err := rpc.DeserializeMultiAddress(syncRequest.From)
+	if err != nil {
+		return err
+	}
+	err = from.VerifySignature(sig)
+	if err != nil {
+		return err
+	}
+	snapshots, err := service.OnSync(from)
+	if err != nil {
+		return err
+	}
+	for _, snapshot := range snapshots {
+		if err := stream.Send(rpc.SerializeSyncBlock(snapshot)); err != nil {
+			return err
+		}
+	}
 	return nil
 }
 
@@ -130,12 +150,18 @@ func (service *DarkService) openOrder(openOrderRequest *rpc.OpenOrderRequest) (*
 	if err != nil {
 		return &rpc.Nothing{}, err
 	}
-	// Verify fragment signature
-	err = orderFragment.VerifySignature(from.ID())
-	if err != nil {
+	// Verify that the fragment was signed by its trader, regardless of who
+	// sent it
+	if err := orderFragment.Verify(); err != nil {
+		return &rpc.Nothing{}, err
+	}
+	if orderFragment.IsExpired(time.Now()) {
+		return &rpc.Nothing{}, compute.ErrOrderFragmentExpired
+	}
+	encryptedFragments := rpc.DeserializeEncryptedOrderFragments(openOrderRequest.GetEncryptedOrderFragments())
+	if err := service.OnOpenOrder(from, orderFragment, encryptedFragments); err != nil {
 		return &rpc.Nothing{}, err
 	}
-	service.OnOpenOrder(from, orderFragment)
 	return &rpc.Nothing{}, nil
 }
 
@@ -162,7 +188,123 @@ func (service *DarkService) CancelOrder(ctx context.Context, cancelOrderRequest
 }
 
 func (service *DarkService) cancelOrder(cancelOrderRequest *rpc.CancelOrderRequest) (*rpc.Nothing, error) {
-	// todo : unimplemented
+	from, _, err := rpc.DeserializeMultiAddress(cancelOrderRequest.From)
+	if err != nil {
+		return &rpc.Nothing{}, err
+	}
+	// The cancellation is authenticated by the trader's signature, not by the
+	// sender, so that dark nodes can forward it on behalf of the trader
+	cancellation := &order.Cancellation{
+		Signature: cancelOrderRequest.CancellationSignature,
+		OrderID:   order.ID(cancelOrderRequest.OrderId),
+	}
+	if err := service.OnCancelOrder(from, cancellation); err != nil {
+		return &rpc.Nothing{}, err
+	}
+	return &rpc.Nothing{}, nil
+}
+
+// Notifications handles an rpc.NotificationsRequest. The Subscription must be
+// signed by the trader, and must be recent, otherwise an error is returned.
+// The stream stays open until the trader closes it.
+func (service *DarkService) Notifications(notificationsRequest *rpc.NotificationsRequest, stream rpc.Dark_NotificationsServer) error {
+	from, _, err := rpc.DeserializeMultiAddress(notificationsRequest.From)
+	if err != nil {
+		return err
+	}
+	// The subscription is authenticated by the trader's signature, so that
+	// only the trader receives the notifications for their orders
+	subscription := rpc.DeserializeSubscription(notificationsRequest.Subscription)
+	if err := subscription.Verify(time.Now()); err != nil {
+		return err
+	}
+	notifications, err := service.OnNotifications(from, subscription, stream.Context().Done())
+	if err != nil {
+		return err
+	}
+	for notification := range notifications {
+		if err := stream.Send(rpc.SerializeNotification(notification)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// RevealOrder handles an rpc.RevealOrderRequest
+func (service *DarkService) RevealOrder(ctx context.Context, revealOrderRequest *rpc.RevealOrderRequest) (*rpc.Nothing, error) {
+	wait := do.Process(func() do.Option {
+		nothing, err := service.revealOrder(revealOrderRequest)
+		if err != nil {
+			return do.Err(err)
+		}
+		return do.Ok(nothing)
+	})
+
+	select {
+	case val := <-wait:
+		if val, ok := val.Ok.(*rpc.Nothing); ok {
+			return val, nil
+		}
+		return &rpc.Nothing{}, val.Err
+
+	case <-ctx.Done():
+		return &rpc.Nothing{}, ctx.Err()
+	}
+}
+
+func (service *DarkService) revealOrder(revealOrderRequest *rpc.RevealOrderRequest) (*rpc.Nothing, error) {
+	from, _, err := rpc.DeserializeMultiAddress(revealOrderRequest.From)
+	if err != nil {
+		return &rpc.Nothing{}, err
+	}
+	// The reveal is authenticated by the trader's signature, not by the
+	// sender, in the same way as a cancellation
+	reveal, err := rpc.DeserializeReveal(revealOrderRequest.GetReveal())
+	if err != nil {
+		return &rpc.Nothing{}, err
+	}
+	if err := service.OnRevealOrder(from, reveal); err != nil {
+		return &rpc.Nothing{}, err
+	}
+	return &rpc.Nothing{}, nil
+}
+
+// UpdateMidpoint handles an rpc.UpdateMidpointRequest
+func (service *DarkService) UpdateMidpoint(ctx context.Context, updateMidpointRequest *rpc.UpdateMidpointRequest) (*rpc.Nothing, error) {
+	wait := do.Process(func() do.Option {
+		nothing, err := service.updateMidpoint(updateMidpointRequest)
+		if err != nil {
+			return do.Err(err)
+		}
+		return do.Ok(nothing)
+	})
+
+	select {
+	case val := <-wait:
+		if val, ok := val.Ok.(*rpc.Nothing); ok {
+			return val, nil
+		}
+		return &rpc.Nothing{}, val.Err
+
+	case <-ctx.Done():
+		return &rpc.Nothing{}, ctx.Err()
+	}
+}
+
+func (service *DarkService) updateMidpoint(updateMidpointRequest *rpc.UpdateMidpointRequest) (*rpc.Nothing, error) {
+	from, _, err := rpc.DeserializeMultiAddress(updateMidpointRequest.From)
+	if err != nil {
+		return &rpc.Nothing{}, err
+	}
+	// The midpoint update is authenticated by the signature of the price
+	// feed, not by the sender, so that dark nodes can forward it
+	midpointUpdate, err := rpc.DeserializeMidpointUpdate(updateMidpointRequest.GetMidpointUpdate())
+	if err != nil {
+		return &rpc.Nothing{}, err
+	}
+	if err := service.OnUpdateMidpoint(from, midpointUpdate); err != nil {
+		return &rpc.Nothing{}, err
+	}
 	return &rpc.Nothing{}, nil
 }
 
@@ -189,8 +331,31 @@ func (service *DarkService) RandomFragmentShares(ctx context.Context, randomFrag
 }
 
 func (service *DarkService) randomFragmentShares(randomFragmentSharesRequest *rpc.RandomFragmentSharesRequest) (*rpc.RandomFragments, error) {
-	// todo : unimplemented
-	return &rpc.RandomFragments{}, nil
+	from, sig, err := rpc.DeserializeMultiAddress(randomFragmentSharesRequest.From)
+	if err != nil {
+		return &rpc.RandomFragments{}, err
+	}
+	err = from.VerifySignature(sig)
+	if err != nil {
+		return &rpc.RandomFragments{}, err
+	}
+	randomFragments := make([]*compute.RandomFragment, 0, len(randomFragmentSharesRequest.GetRandomFragments().GetRandomFragments()))
+	for _, serializedRandomFragment := range randomFragmentSharesRequest.GetRandomFragments().GetRandomFragments() {
+		randomFragment, err := rpc.DeserializeRandomFragment(serializedRandomFragment)
+		if err != nil {
+			return &rpc.RandomFragments{}, err
+		}
+		randomFragments = append(randomFragments, randomFragment)
+	}
+	randomFragments, err = service.OnRandomFragmentShares(from, randomFragments)
+	if err != nil {
+		return &rpc.RandomFragments{}, err
+	}
+	serializedRandomFragments := &rpc.RandomFragments{}
+	for _, randomFragment := range randomFragments {
+		serializedRandomFragments.RandomFragments = append(serializedRandomFragments.RandomFragments, rpc.SerializeRandomFragment(randomFragment))
+	}
+	return serializedRandomFragments, nil
 }
 
 // ResidueFragmentShares handles an rpc.ResidueFragmentSharesRequest
@@ -216,8 +381,27 @@ func (service *DarkService) ResidueFragmentShares(ctx context.Context, residueFr
 }
 
 func (service *DarkService) residueFragmentShares(residueFragmentSharesRequest *rpc.ResidueFragmentSharesRequest) (*rpc.ResidueFragments, error) {
-	// todo : unimplemented
-	return &rpc.ResidueFragments{}, nil
+	from, sig, err := rpc.DeserializeMultiAddress(residueFragmentSharesRequest.From)
+	if err != nil {
+		return &rpc.ResidueFragments{}, err
+	}
+	err = from.VerifySignature(sig)
+	if err != nil {
+		return &rpc.ResidueFragments{}, err
+	}
+	residueIDs := make([]compute.ResidueID, len(residueFragmentSharesRequest.ResidueIds))
+	for i := range residueIDs {
+		residueIDs[i] = residueFragmentSharesRequest.ResidueIds[i]
+	}
+	residueFragments, err := service.OnResidueFragmentShares(from, residueIDs)
+	if err != nil {
+		return &rpc.ResidueFragments{}, err
+	}
+	serializedResidueFragments := &rpc.ResidueFragments{}
+	for _, residueFragment := range residueFragments {
+		serializedResidueFragments.ResidueFragments = append(serializedResidueFragments.ResidueFragments, rpc.SerializeResidueFragment(residueFragment))
+	}
+	return serializedResidueFragments, nil
 }
 
 // ComputeResidueFragment handles  an rpc.ComputeResidueFragmentRequest
@@ -243,7 +427,25 @@ func (service *DarkService) ComputeResidueFragment(ctx context.Context, computeR
 }
 
 func (service *DarkService) computeResidueFragment(computeResidueFragmentRequest *rpc.ComputeResidueFragmentRequest) (*rpc.Nothing, error) {
-	// todo : unimplemented
+	from, sig, err := rpc.DeserializeMultiAddress(computeResidueFragmentRequest.From)
+	if err != nil {
+		return &rpc.Nothing{}, err
+	}
+	err = from.VerifySignature(sig)
+	if err != nil {
+		return &rpc.Nothing{}, err
+	}
+	residueFragments := make([]*compute.ResidueFragment, 0, len(computeResidueFragmentRequest.GetResidueFragments().GetResidueFragments()))
+	for _, serializedResidueFragment := range computeResidueFragmentRequest.GetResidueFragments().GetResidueFragments() {
+		residueFragment, err := rpc.DeserializeResidueFragment(serializedResidueFragment)
+		if err != nil {
+			return &rpc.Nothing{}, err
+		}
+		residueFragments = append(residueFragments, residueFragment)
+	}
+	if err := service.OnComputeResidueFragment(from, residueFragments); err != nil {
+		return &rpc.Nothing{}, err
+	}
 	return &rpc.Nothing{}, nil
 }
 
@@ -270,8 +472,29 @@ func (service *DarkService) BroadcastAlphaBetaFragment(ctx context.Context, broa
 }
 
 func (service *DarkService) broadcastAlphaBetaFragment(broadcastAlphaBetaFragmentRequest *rpc.BroadcastAlphaBetaFragmentRequest) (*rpc.AlphaBetaFragment, error) {
-	// todo : unimplemented
-	return &rpc.AlphaBetaFragment{}, nil
+	from, sig, err := rpc.DeserializeMultiAddress(broadcastAlphaBetaFragmentRequest.From)
+	if err != nil {
+		return &rpc.AlphaBetaFragment{}, err
+	}
+	err = from.VerifySignature(sig)
+	if err != nil {
+		return &rpc.AlphaBetaFragment{}, err
+	}
+	if broadcastAlphaBetaFragmentRequest.AlphaBetaFragment == nil {
+		return &rpc.AlphaBetaFragment{}, nil
+	}
+	alphaBetaFragment, err := rpc.DeserializeAlphaBetaFragment(broadcastAlphaBetaFragmentRequest.AlphaBetaFragment)
+	if err != nil {
+		return &rpc.AlphaBetaFragment{}, err
+	}
+	alphaBetaFragment, err = service.OnBroadcastAlphaBetaFragment(from, alphaBetaFragment)
+	if err != nil {
+		return &rpc.AlphaBetaFragment{}, err
+	}
+	if alphaBetaFragment == nil {
+		return &rpc.AlphaBetaFragment{}, nil
+	}
+	return rpc.SerializeAlphaBetaFragment(alphaBetaFragment), nil
 }
 
 // BroadcastDeltaFragment handles an rpc.BroadcastDeltaFragmentRequest
@@ -289,7 +512,7 @@ func (service *DarkService) BroadcastDeltaFragment(ctx context.Context, broadcas
 		if val, ok := val.Ok.(*rpc.DeltaFragment); ok {
 			return val, nil
 		}
-		return &rpc.DeltaFragment{}, nil
+		return &rpc.DeltaFragment{}, val.Err
 
 	case <-ctx.Done():
 		return &rpc.DeltaFragment{}, ctx.Err()
@@ -305,7 +528,9 @@ func (service *DarkService) broadcastDeltaFragment(broadcastDeltaFragmentRequest
 	if err != nil {
 		return &rpc.DeltaFragment{}, err
 	}
-	service.OnBroadcastDeltaFragment(from, deltaFragment)
+	if err := service.OnBroadcastDeltaFragment(from, deltaFragment); err != nil {
+		return &rpc.DeltaFragment{}, err
+	}
 	// FIXME: Return the respective delta fragment.
 	return &rpc.DeltaFragment{}, nil
 }
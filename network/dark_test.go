@@ -6,14 +6,18 @@ import (
 	"sync"
 	"time"
 
+	"golang.org/x/net/context"
+
 	"github.com/republicprotocol/republic-go/stackint"
 
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
+	"github.com/republicprotocol/republic-go/compute"
 	"github.com/republicprotocol/republic-go/identity"
 	"github.com/republicprotocol/republic-go/network"
 	"github.com/republicprotocol/republic-go/network/rpc"
 	"github.com/republicprotocol/republic-go/order"
+	"github.com/republicprotocol/republic-go/shamir"
 	"google.golang.org/grpc"
 )
 
@@ -56,8 +60,15 @@ var _ = Describe("Dark service", func() {
 		})
 
 		It("should be able to handle Sync rpc", func() {
-			_, err = pool.Sync(darks[1].MultiAddress)
+			syncBlocks, err := pool.Sync(darks[1].MultiAddress)
 			Ω(err).ShouldNot(HaveOccurred())
+			numberOfSyncBlocks := 0
+			for syncBlock := range syncBlocks {
+				_, err := rpc.DeserializeSyncBlock(syncBlock)
+				Ω(err).ShouldNot(HaveOccurred())
+				numberOfSyncBlocks++
+			}
+			Ω(numberOfSyncBlocks).Should(Equal(1))
 		})
 
 		var fragment *order.Fragment
@@ -68,8 +79,40 @@ var _ = Describe("Dark service", func() {
 			Ω(err).ShouldNot(HaveOccurred())
 			err = fragment.Sign(*keypairs[0])
 			Ω(err).ShouldNot(HaveOccurred())
-			err = pool.OpenOrder(darks[1].MultiAddress, rpc.SerializeOrderFragment(fragment))
+			err = pool.OpenOrder(darks[1].MultiAddress, rpc.SerializeOrderFragment(fragment), nil)
+			Ω(err).ShouldNot(HaveOccurred())
+		})
+
+		It("should reject expired order fragments in the OpenOrder rpc", func() {
+			price := stackint.FromUint(10)
+			maxVolume := stackint.FromUint(1000)
+			minVolume := stackint.FromUint(100)
+			nonce := stackint.Zero()
+			fragments, err := order.NewOrder(order.TypeLimit, order.ParityBuy, time.Now().Add(-time.Hour), order.CurrencyCodeBTC, order.CurrencyCodeETH, &price, &maxVolume, &minVolume, &nonce).Split(n, k, &prime)
+			Ω(err).ShouldNot(HaveOccurred())
+			err = fragments[0].Sign(*keypairs[0])
+			Ω(err).ShouldNot(HaveOccurred())
+			err = pool.OpenOrder(darks[1].MultiAddress, rpc.SerializeOrderFragment(fragments[0]), nil)
+			Ω(err).Should(HaveOccurred())
+		})
+
+		It("should reject order fragments that were not signed by their trader in the OpenOrder rpc", func() {
+			trader, err := identity.NewKeyPair()
+			Ω(err).ShouldNot(HaveOccurred())
+			other, err := generateOrderFragment()
 			Ω(err).ShouldNot(HaveOccurred())
+			err = other.Sign(*keypairs[0])
+			Ω(err).ShouldNot(HaveOccurred())
+
+			// Claim that another trader opened the order fragment
+			other.Trader = trader.ID()
+			err = pool.OpenOrder(darks[1].MultiAddress, rpc.SerializeOrderFragment(other), nil)
+			Ω(err).Should(HaveOccurred())
+
+			// Unsigned order fragments are rejected
+			other.Signature = nil
+			err = pool.OpenOrder(darks[1].MultiAddress, rpc.SerializeOrderFragment(other), nil)
+			Ω(err).Should(HaveOccurred())
 		})
 
 		It("should be able to handle CancelOrder rpc", func() {
@@ -77,6 +120,65 @@ var _ = Describe("Dark service", func() {
 			Ω(err).ShouldNot(HaveOccurred())
 		})
 
+		It("should be able to handle Notifications rpc", func() {
+			subscription := order.NewSubscription(keypairs[0].ID(), time.Now())
+			err := subscription.Sign(*keypairs[0])
+			Ω(err).ShouldNot(HaveOccurred())
+
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+			notifications, err := pool.Notifications(ctx, darks[1].MultiAddress, rpc.SerializeSubscription(subscription))
+			Ω(err).ShouldNot(HaveOccurred())
+			notification := rpc.DeserializeNotification(<-notifications)
+			Ω(notification.Type).Should(Equal(order.NotificationMatch))
+			Ω(notification.OrderID).Should(Equal(order.ID("buy")))
+			Ω(notification.CounterpartyOrderID).Should(Equal(order.ID("sell")))
+		})
+
+		It("should reject unsigned subscriptions in the Notifications rpc", func() {
+			subscription := order.NewSubscription(keypairs[0].ID(), time.Now())
+
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+			notifications, err := pool.Notifications(ctx, darks[1].MultiAddress, rpc.SerializeSubscription(subscription))
+			Ω(err).ShouldNot(HaveOccurred())
+			_, ok := <-notifications
+			Ω(ok).Should(BeFalse())
+		})
+
+		It("should be able to handle RevealOrder rpc", func() {
+			price := stackint.FromUint(10)
+			maxVolume := stackint.FromUint(1000)
+			minVolume := stackint.FromUint(100)
+			nonce := stackint.FromUint(1)
+			ord := order.NewOrder(order.TypeLimit, order.ParityBuy, time.Now().Add(time.Hour), order.CurrencyCodeBTC, order.CurrencyCodeETH, &price, &maxVolume, &minVolume, &nonce)
+			reveal := order.NewReveal(keypairs[0].ID(), ord, []byte("account"))
+			err := reveal.Sign(*keypairs[0])
+			Ω(err).ShouldNot(HaveOccurred())
+
+			err = pool.RevealOrder(darks[1].MultiAddress, rpc.SerializeReveal(reveal))
+			Ω(err).ShouldNot(HaveOccurred())
+
+			// Reveals of orders that do not match their ID are rejected
+			ord.Price = &maxVolume
+			err = pool.RevealOrder(darks[1].MultiAddress, rpc.SerializeReveal(reveal))
+			Ω(err).Should(HaveOccurred())
+		})
+
+		It("should be able to handle UpdateMidpoint rpc", func() {
+			midpoint := stackint.FromUint(71234)
+			midpointUpdate := compute.NewMidpointUpdate(order.CurrencyCodeETH, order.CurrencyCodeBTC, &midpoint, time.Now())
+			err := midpointUpdate.Sign(*keypairs[0])
+			Ω(err).ShouldNot(HaveOccurred())
+
+			err = pool.UpdateMidpoint(darks[1].MultiAddress, rpc.SerializeMidpointUpdate(midpointUpdate))
+			Ω(err).ShouldNot(HaveOccurred())
+
+			// Midpoint updates without a midpoint are rejected
+			err = pool.UpdateMidpoint(darks[1].MultiAddress, &rpc.MidpointUpdate{Signature: midpointUpdate.Signature})
+			Ω(err).Should(HaveOccurred())
+		})
+
 		It("should be able to handle SignOrderFragment rpc", func() {
 			signature, err := pool.SignOrderFragment(darks[1].MultiAddress, &rpc.OrderFragmentSignature{})
 			Ω(err).ShouldNot(HaveOccurred())
@@ -84,13 +186,13 @@ var _ = Describe("Dark service", func() {
 		})
 
 		It("should be able to handle RandomFragmentShares rpc", func() {
-			fragments, err := pool.RandomFragmentShares(darks[1].MultiAddress)
+			fragments, err := pool.RandomFragmentShares(darks[1].MultiAddress, &rpc.RandomFragments{})
 			Ω(err).ShouldNot(HaveOccurred())
 			Ω(*fragments).Should(Equal(rpc.RandomFragments{}))
 		})
 
 		It("should be able to handle ResidueFragmentShares rpc", func() {
-			fragments, err := pool.ResidueFragmentShares(darks[1].MultiAddress, &rpc.RandomFragments{})
+			fragments, err := pool.ResidueFragmentShares(darks[1].MultiAddress, [][]byte{})
 			Ω(err).ShouldNot(HaveOccurred())
 			Ω(*fragments).Should(Equal(rpc.ResidueFragments{}))
 		})
@@ -101,10 +203,31 @@ var _ = Describe("Dark service", func() {
 		})
 
 		It("should be able to handle BroadcastAlphaBetaFragment rpc", func() {
-			fragment, err := pool.BroadcastAlphaBetaFragment(darks[1].MultiAddress, &rpc.AlphaBetaFragment{})
+			fragment, err := pool.BroadcastAlphaBetaFragment(darks[1].MultiAddress, rpc.SerializeAlphaBetaFragment(&compute.AlphaBetaFragment{
+				ResidueID:  compute.ResidueID("residue"),
+				AlphaShare: shamir.Share{Key: 1, Value: stackint.FromUint(1)},
+				BetaShare:  shamir.Share{Key: 1, Value: stackint.FromUint(2)},
+			}))
 			Ω(err).ShouldNot(HaveOccurred())
 			Ω(*fragment).Should(Equal(rpc.AlphaBetaFragment{}))
 		})
+
+		It("should be able to handle BroadcastDeltaFragment rpc", func() {
+			_, err := pool.BroadcastDeltaFragment(darks[1].MultiAddress, rpc.SerializeDeltaFragment(&compute.DeltaFragment{
+				DeltaID:    compute.DeltaID("delta"),
+				MatchShare: shamir.Share{Key: 1, Value: stackint.FromUint(1)},
+			}))
+			Ω(err).ShouldNot(HaveOccurred())
+		})
+
+		It("should return errors from the BroadcastDeltaFragment rpc", func() {
+			_, err := pool.BroadcastDeltaFragment(darks[1].MultiAddress, rpc.SerializeDeltaFragment(&compute.DeltaFragment{
+				DeltaID:    compute.DeltaID("delta"),
+				MatchShare: shamir.Share{Key: 0, Value: stackint.FromUint(1)},
+			}))
+			Ω(err).Should(HaveOccurred())
+			Ω(err.Error()).Should(ContainSubstring("unexpected share key"))
+		})
 	})
 })
 
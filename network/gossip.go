@@ -2,17 +2,20 @@ package network
 
 import (
 	"github.com/republicprotocol/go-do"
+	"github.com/republicprotocol/republic-go/compute"
+	"github.com/republicprotocol/republic-go/identity"
 	"github.com/republicprotocol/republic-go/network/rpc"
-	"github.com/republicprotocol/republic-go/order"
 	"golang.org/x/net/context"
 	"google.golang.org/grpc"
 )
 
 // A GossipDelegate is used as a callback interface to inject behavior into the
-// Gossip service.
+// Gossip service. OnGossip returns the Rumor signed by this node if it agrees
+// with the Rumor, or nil if it does not. OnFinalize receives the agreeing
+// Rumors that prove a match can be finalized.
 type GossipDelegate interface {
-	OnGossip(order.ID, order.ID)
-	OnFinalize(order.ID, order.ID)
+	OnGossip(from identity.MultiAddress, rumor *compute.Rumor) (*compute.Rumor, error)
+	OnFinalize(from identity.MultiAddress, rumors []*compute.Rumor) error
 }
 
 // GossipService implements the gRPC Gossip service.
@@ -55,8 +58,18 @@ func (service *GossipService) Gossip(ctx context.Context, gossipRequest *rpc.Gos
 }
 
 func (service *GossipService) gossip(gossipRequest *rpc.GossipRequest) (*rpc.Rumor, error) {
-	service.GossipDelegate.OnGossip(order.ID(gossipRequest.Rumor.BuyOrderId), order.ID(gossipRequest.Rumor.SellOrderId))
-	return nil, nil
+	from, _, err := rpc.DeserializeMultiAddress(gossipRequest.From)
+	if err != nil {
+		return &rpc.Rumor{}, err
+	}
+	rumor, err := service.GossipDelegate.OnGossip(from, rpc.DeserializeRumor(gossipRequest.GetRumor()))
+	if err != nil {
+		return &rpc.Rumor{}, err
+	}
+	if rumor == nil {
+		return &rpc.Rumor{}, nil
+	}
+	return rpc.SerializeRumor(rumor), nil
 }
 
 // Finalize handles an rpc.FinalizeRequest
@@ -82,6 +95,12 @@ func (service *GossipService) Finalize(ctx context.Context, finalizeRequest *rpc
 }
 
 func (service *GossipService) finalize(finalizeRequest *rpc.FinalizeRequest) (*rpc.Rumor, error) {
-	service.GossipDelegate.OnFinalize(order.ID(finalizeRequest.Rumor.BuyOrderId), order.ID(finalizeRequest.Rumor.SellOrderId))
-	return nil, nil
+	from, _, err := rpc.DeserializeMultiAddress(finalizeRequest.From)
+	if err != nil {
+		return &rpc.Rumor{}, err
+	}
+	if err := service.GossipDelegate.OnFinalize(from, rpc.DeserializeRumors(finalizeRequest.GetRumors())); err != nil {
+		return &rpc.Rumor{}, err
+	}
+	return &rpc.Rumor{}, nil
 }
@@ -1 +1,65 @@
 package network_test
+
+import (
+	"net"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/republicprotocol/republic-go/compute"
+	"github.com/republicprotocol/republic-go/identity"
+	"github.com/republicprotocol/republic-go/network"
+	"github.com/republicprotocol/republic-go/network/rpc"
+	"github.com/republicprotocol/republic-go/order"
+	"google.golang.org/grpc"
+)
+
+var _ = Describe("Gossip service", func() {
+
+	var server *grpc.Server
+	var multiAddress identity.MultiAddress
+	var keyPair identity.KeyPair
+	var pool *rpc.ClientPool
+
+	BeforeEach(func() {
+		var err error
+		keyPair, err = identity.NewKeyPair()
+		Ω(err).ShouldNot(HaveOccurred())
+		multiAddress, err = identity.NewMultiAddressFromString("/ip4/127.0.0.1/tcp/5000/republic/" + keyPair.Address().String())
+		Ω(err).ShouldNot(HaveOccurred())
+
+		server = grpc.NewServer(grpc.ConnectionTimeout(time.Minute))
+		network.NewGossipService(&MockDelegate{}).Register(server)
+		listener, err := net.Listen("tcp", "127.0.0.1:5000")
+		Ω(err).ShouldNot(HaveOccurred())
+		go server.Serve(listener)
+
+		multiAddressSignature, err := keyPair.Sign(multiAddress)
+		Ω(err).ShouldNot(HaveOccurred())
+		pool = rpc.NewClientPool(multiAddress, multiAddressSignature)
+	})
+
+	AfterEach(func() {
+		server.Stop()
+	})
+
+	It("should be able to handle Gossip rpc", func() {
+		rumor := compute.NewRumor(order.ID("buyOrderID"), order.ID("sellOrderID"))
+		Ω(rumor.Sign(keyPair)).ShouldNot(HaveOccurred())
+		response, err := pool.Gossip(multiAddress, rpc.SerializeRumor(rumor))
+		Ω(err).ShouldNot(HaveOccurred())
+		Ω(rpc.DeserializeRumor(response)).Should(Equal(rumor))
+	})
+
+	It("should be able to handle Finalize rpc", func() {
+		rumor := compute.NewRumor(order.ID("buyOrderID"), order.ID("sellOrderID"))
+		Ω(rumor.Sign(keyPair)).ShouldNot(HaveOccurred())
+		_, err := pool.Finalize(multiAddress, rpc.SerializeRumors([]*compute.Rumor{rumor}))
+		Ω(err).ShouldNot(HaveOccurred())
+	})
+
+	It("should return errors from the Finalize rpc", func() {
+		_, err := pool.Finalize(multiAddress, []*rpc.Rumor{})
+		Ω(err).Should(HaveOccurred())
+	})
+})
@@ -1,6 +1,7 @@
 package network_test
 
 import (
+	"errors"
 	"fmt"
 	"math/rand"
 	"time"
@@ -23,12 +24,69 @@ const (
 type MockDelegate struct {
 }
 
-func (mockDelegate *MockDelegate) OnOpenOrder(from identity.MultiAddress, orderFragment *order.Fragment) {
-	return
+func (mockDelegate *MockDelegate) OnSync(from identity.MultiAddress) ([]*compute.Snapshot, error) {
+	return []*compute.Snapshot{&compute.Snapshot{}}, nil
 }
 
-func (mockDelegate *MockDelegate) OnBroadcastDeltaFragment(from identity.MultiAddress, deltaFragment *compute.DeltaFragment) {
-	return
+func (mockDelegate *MockDelegate) OnOpenOrder(from identity.MultiAddress, orderFragment *order.Fragment, encryptedFragments []*order.EncryptedFragment) error {
+	return nil
+}
+
+func (mockDelegate *MockDelegate) OnCancelOrder(from identity.MultiAddress, cancellation *order.Cancellation) error {
+	return nil
+}
+
+func (mockDelegate *MockDelegate) OnNotifications(from identity.MultiAddress, subscription *order.Subscription, done <-chan struct{}) (<-chan *order.Notification, error) {
+	notifications := make(chan *order.Notification, 1)
+	notifications <- order.NewNotification(order.NotificationMatch, order.ID("buy"), order.ID("sell"))
+	go func() {
+		<-done
+		close(notifications)
+	}()
+	return notifications, nil
+}
+
+func (mockDelegate *MockDelegate) OnRevealOrder(from identity.MultiAddress, reveal *order.Reveal) error {
+	return reveal.Verify()
+}
+
+func (mockDelegate *MockDelegate) OnUpdateMidpoint(from identity.MultiAddress, midpointUpdate *compute.MidpointUpdate) error {
+	_, err := identity.RecoverSigner(midpointUpdate, midpointUpdate.Signature)
+	return err
+}
+
+func (mockDelegate *MockDelegate) OnRandomFragmentShares(from identity.MultiAddress, randomFragments []*compute.RandomFragment) ([]*compute.RandomFragment, error) {
+	return []*compute.RandomFragment{}, nil
+}
+
+func (mockDelegate *MockDelegate) OnResidueFragmentShares(from identity.MultiAddress, residueIDs []compute.ResidueID) ([]*compute.ResidueFragment, error) {
+	return []*compute.ResidueFragment{}, nil
+}
+
+func (mockDelegate *MockDelegate) OnComputeResidueFragment(from identity.MultiAddress, residueFragments []*compute.ResidueFragment) error {
+	return nil
+}
+
+func (mockDelegate *MockDelegate) OnBroadcastAlphaBetaFragment(from identity.MultiAddress, alphaBetaFragment *compute.AlphaBetaFragment) (*compute.AlphaBetaFragment, error) {
+	return nil, nil
+}
+
+func (mockDelegate *MockDelegate) OnBroadcastDeltaFragment(from identity.MultiAddress, deltaFragment *compute.DeltaFragment) error {
+	if deltaFragment.MatchShare.Key == 0 {
+		return errors.New("unexpected share key")
+	}
+	return nil
+}
+
+func (mockDelegate *MockDelegate) OnGossip(from identity.MultiAddress, rumor *compute.Rumor) (*compute.Rumor, error) {
+	return rumor, nil
+}
+
+func (mockDelegate *MockDelegate) OnFinalize(from identity.MultiAddress, rumors []*compute.Rumor) error {
+	if len(rumors) == 0 {
+		return errors.New("insufficient agreement")
+	}
+	return nil
 }
 
 func generateSwarmServices(numberOfSwarms int) ([]*network.SwarmService, []*grpc.Server, error) {
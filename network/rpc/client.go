@@ -101,11 +101,15 @@ func (client *Client) StreamTimeoutFunc(f func(ctx context.Context) error) error
 	return err
 }
 
-// Ping RPC.
+// Ping RPC. A shamir.FieldMismatchError is returned if the Client and the
+// peer use different finite fields.
 func (client *Client) Ping() error {
 	return client.TimeoutFunc(func(ctx context.Context) error {
-		_, err := client.SwarmClient.Ping(ctx, client.SignedFrom, grpc.FailFast(false))
-		return err
+		multiAddress, err := client.SwarmClient.Ping(ctx, client.SignedFrom, grpc.FailFast(false))
+		if err != nil {
+			return err
+		}
+		return VerifyField(client.Options.Field, multiAddress)
 	})
 }
 
@@ -167,9 +171,9 @@ func (client *Client) QueryPeersDeep(target *Address) (chan *MultiAddress, error
 // Sync RPC.
 func (client *Client) Sync() (chan *SyncBlock, error) {
 	ch := make(chan *SyncBlock)
-	err := client.TimeoutFunc(func(ctx context.Context) error {
+	err := client.StreamTimeoutFunc(func(ctx context.Context) error {
 		stream, err := client.DarkClient.Sync(ctx, &SyncRequest{
-			From: client.From,
+			From: client.SignedFrom,
 		}, grpc.FailFast(false))
 		if err != nil {
 			return err
@@ -208,12 +212,14 @@ func (client *Client) SignOrderFragment(orderFragmentSignature *OrderFragmentSig
 	return val, err
 }
 
-// OpenOrder RPC.
-func (client *Client) OpenOrder(orderFragment *OrderFragment) error {
+// OpenOrder RPC. The encrypted order fragments of the other dark nodes in the
+// dark pool are sent alongside the order fragment.
+func (client *Client) OpenOrder(orderFragment *OrderFragment, encryptedOrderFragments []*EncryptedOrderFragment) error {
 	return client.TimeoutFunc(func(ctx context.Context) error {
 		_, err := client.DarkClient.OpenOrder(ctx, &OpenOrderRequest{
-			From:          client.SignedFrom,
-			OrderFragment: orderFragment,
+			From:                    client.SignedFrom,
+			OrderFragment:           orderFragment,
+			EncryptedOrderFragments: encryptedOrderFragments,
 		}, grpc.FailFast(false))
 		return err
 	})
@@ -231,13 +237,64 @@ func (client *Client) CancelOrder(orderID order.ID, cancellationSignature identi
 	})
 }
 
+// Notifications RPC. The stream of notifications stays open until the context
+// is done, or until the dark node closes it, and then the channel is closed.
+func (client *Client) Notifications(ctx context.Context, subscription *Subscription) (chan *Notification, error) {
+	stream, err := client.DarkClient.Notifications(ctx, &NotificationsRequest{
+		From:         client.From,
+		Subscription: subscription,
+	}, grpc.FailFast(false))
+	if err != nil {
+		return nil, err
+	}
+	ch := make(chan *Notification)
+	go func() {
+		defer close(ch)
+		for {
+			notification, err := stream.Recv()
+			if err != nil {
+				return
+			}
+			select {
+			case ch <- notification:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+	return ch, nil
+}
+
+// RevealOrder RPC.
+func (client *Client) RevealOrder(reveal *Reveal) error {
+	return client.TimeoutFunc(func(ctx context.Context) error {
+		_, err := client.DarkClient.RevealOrder(ctx, &RevealOrderRequest{
+			From:   client.From,
+			Reveal: reveal,
+		}, grpc.FailFast(false))
+		return err
+	})
+}
+
+// UpdateMidpoint RPC.
+func (client *Client) UpdateMidpoint(midpointUpdate *MidpointUpdate) error {
+	return client.TimeoutFunc(func(ctx context.Context) error {
+		_, err := client.DarkClient.UpdateMidpoint(ctx, &UpdateMidpointRequest{
+			From:           client.From,
+			MidpointUpdate: midpointUpdate,
+		}, grpc.FailFast(false))
+		return err
+	})
+}
+
 // RandomFragmentShares RPC.
-func (client *Client) RandomFragmentShares() (*RandomFragments, error) {
+func (client *Client) RandomFragmentShares(randomFragments *RandomFragments) (*RandomFragments, error) {
 	var val *RandomFragments
 	var err error
 	err = client.TimeoutFunc(func(ctx context.Context) error {
 		val, err = client.DarkClient.RandomFragmentShares(ctx, &RandomFragmentSharesRequest{
-			From: client.From,
+			From:            client.SignedFrom,
+			RandomFragments: randomFragments,
 		}, grpc.FailFast(false))
 		return err
 	})
@@ -245,13 +302,13 @@ func (client *Client) RandomFragmentShares() (*RandomFragments, error) {
 }
 
 // ResidueFragmentShares RPC.
-func (client *Client) ResidueFragmentShares(randomFragments *RandomFragments) (*ResidueFragments, error) {
+func (client *Client) ResidueFragmentShares(residueIDs [][]byte) (*ResidueFragments, error) {
 	var val *ResidueFragments
 	var err error
 	err = client.TimeoutFunc(func(ctx context.Context) error {
 		val, err = client.DarkClient.ResidueFragmentShares(ctx, &ResidueFragmentSharesRequest{
-			From:            client.From,
-			RandomFragments: randomFragments,
+			From:       client.SignedFrom,
+			ResidueIds: residueIDs,
 		}, grpc.FailFast(false))
 		return err
 	})
@@ -262,7 +319,7 @@ func (client *Client) ResidueFragmentShares(randomFragments *RandomFragments) (*
 func (client *Client) ComputeResidueFragment(residueFragments *ResidueFragments) error {
 	return client.TimeoutFunc(func(ctx context.Context) error {
 		_, err := client.DarkClient.ComputeResidueFragment(ctx, &ComputeResidueFragmentRequest{
-			From:             client.From,
+			From:             client.SignedFrom,
 			ResidueFragments: residueFragments,
 		}, grpc.FailFast(false))
 		return err
@@ -275,7 +332,7 @@ func (client *Client) BroadcastAlphaBetaFragment(alphaBetaFragment *AlphaBetaFra
 	var err error
 	err = client.TimeoutFunc(func(ctx context.Context) error {
 		val, err = client.DarkClient.BroadcastAlphaBetaFragment(ctx, &BroadcastAlphaBetaFragmentRequest{
-			From:              client.From,
+			From:              client.SignedFrom,
 			AlphaBetaFragment: alphaBetaFragment,
 		}, grpc.FailFast(false))
 		return err
@@ -312,13 +369,13 @@ func (client *Client) Gossip(rumor *Rumor) (*Rumor, error) {
 }
 
 // Finalize RPC.
-func (client *Client) Finalize(rumor *Rumor) (*Rumor, error) {
+func (client *Client) Finalize(rumors []*Rumor) (*Rumor, error) {
 	var val *Rumor
 	var err error
 	err = client.TimeoutFunc(func(ctx context.Context) error {
 		val, err = client.GossipClient.Finalize(ctx, &FinalizeRequest{
-			From:  client.From,
-			Rumor: rumor,
+			From:   client.From,
+			Rumors: rumors,
 		}, grpc.FailFast(false))
 		return err
 	})
@@ -3,6 +3,8 @@ package rpc
 import (
 	"time"
 
+	"golang.org/x/net/context"
+
 	"github.com/republicprotocol/go-do"
 	"github.com/republicprotocol/republic-go/identity"
 	"github.com/republicprotocol/republic-go/order"
@@ -65,7 +67,8 @@ func (pool *ClientPool) findOrCreateClient(to identity.MultiAddress) (*Client, e
 	client = client.
 		WithTimeout(pool.options.Timeout).
 		WithTimeoutBackoff(pool.options.TimeoutBackoff).
-		WithTimeoutRetries(pool.options.TimeoutRetries)
+		WithTimeoutRetries(pool.options.TimeoutRetries).
+		WithField(pool.options.Field)
 
 	if len(pool.cache) >= pool.options.CacheLimit {
 		var k string
@@ -127,12 +130,12 @@ func (pool *ClientPool) SignOrderFragment(to identity.MultiAddress, orderFragmen
 }
 
 // OpenOrder RPC.
-func (pool *ClientPool) OpenOrder(to identity.MultiAddress, orderFragment *OrderFragment) error {
+func (pool *ClientPool) OpenOrder(to identity.MultiAddress, orderFragment *OrderFragment, encryptedOrderFragments []*EncryptedOrderFragment) error {
 	client, err := pool.FindOrCreateClient(to)
 	if err != nil {
 		return err
 	}
-	return client.OpenOrder(orderFragment)
+	return client.OpenOrder(orderFragment, encryptedOrderFragments)
 }
 
 // CancelOrder RPC.
@@ -144,22 +147,49 @@ func (pool *ClientPool) CancelOrder(to identity.MultiAddress, orderID order.ID,
 	return client.CancelOrder(orderID, cancellationSignature)
 }
 
+// Notifications RPC.
+func (pool *ClientPool) Notifications(ctx context.Context, to identity.MultiAddress, subscription *Subscription) (chan *Notification, error) {
+	client, err := pool.FindOrCreateClient(to)
+	if err != nil {
+		return nil, err
+	}
+	return client.Notifications(ctx, subscription)
+}
+
+// RevealOrder RPC.
+func (pool *ClientPool) RevealOrder(to identity.MultiAddress, reveal *Reveal) error {
+	client, err := pool.FindOrCreateClient(to)
+	if err != nil {
+		return err
+	}
+	return client.RevealOrder(reveal)
+}
+
+// UpdateMidpoint RPC.
+func (pool *ClientPool) UpdateMidpoint(to identity.MultiAddress, midpointUpdate *MidpointUpdate) error {
+	client, err := pool.FindOrCreateClient(to)
+	if err != nil {
+		return err
+	}
+	return client.UpdateMidpoint(midpointUpdate)
+}
+
 // RandomFragmentShares RPC.
-func (pool *ClientPool) RandomFragmentShares(to identity.MultiAddress) (*RandomFragments, error) {
+func (pool *ClientPool) RandomFragmentShares(to identity.MultiAddress, randomFragments *RandomFragments) (*RandomFragments, error) {
 	client, err := pool.FindOrCreateClient(to)
 	if err != nil {
 		return nil, err
 	}
-	return client.RandomFragmentShares()
+	return client.RandomFragmentShares(randomFragments)
 }
 
 // ResidueFragmentShares RPC.
-func (pool *ClientPool) ResidueFragmentShares(to identity.MultiAddress, randomFragments *RandomFragments) (*ResidueFragments, error) {
+func (pool *ClientPool) ResidueFragmentShares(to identity.MultiAddress, residueIDs [][]byte) (*ResidueFragments, error) {
 	client, err := pool.FindOrCreateClient(to)
 	if err != nil {
 		return nil, err
 	}
-	return client.ResidueFragmentShares(randomFragments)
+	return client.ResidueFragmentShares(residueIDs)
 }
 
 // ComputeResidueFragment RPC.
@@ -199,10 +229,10 @@ func (pool *ClientPool) Gossip(to identity.MultiAddress, rumor *Rumor) (*Rumor,
 }
 
 // Finalize RPC.
-func (pool *ClientPool) Finalize(to identity.MultiAddress, rumor *Rumor) (*Rumor, error) {
+func (pool *ClientPool) Finalize(to identity.MultiAddress, rumors []*Rumor) (*Rumor, error) {
 	client, err := pool.FindOrCreateClient(to)
 	if err != nil {
 		return nil, err
 	}
-	return client.Finalize(rumor)
+	return client.Finalize(rumors)
 }
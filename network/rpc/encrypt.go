@@ -0,0 +1,50 @@
+package rpc
+
+import (
+	"bytes"
+	"errors"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/republicprotocol/republic-go/identity"
+	"github.com/republicprotocol/republic-go/order"
+)
+
+// ErrEncryptedOrderFragmentMismatch is returned when a decrypted order
+// fragment does not match the public fields of its encrypted order fragment.
+var ErrEncryptedOrderFragmentMismatch = errors.New("encrypted order fragment mismatch")
+
+// EncryptOrderFragment encrypts a signed order.Fragment for the dark node with
+// the given ID, using the public key that the dark node registered.
+func EncryptOrderFragment(orderFragment *order.Fragment, to identity.ID, publicKey []byte) (*order.EncryptedFragment, error) {
+	data, err := proto.Marshal(SerializeOrderFragment(orderFragment))
+	if err != nil {
+		return nil, err
+	}
+	ciphertext, err := identity.Encrypt(publicKey, data)
+	if err != nil {
+		return nil, err
+	}
+	return order.NewEncryptedFragment(to, orderFragment.OrderID, orderFragment.OrderExpiry, ciphertext), nil
+}
+
+// DecryptOrderFragment decrypts an order.EncryptedFragment using the KeyPair
+// of the dark node that it was encrypted for. The signature of the decrypted
+// order.Fragment is not verified.
+func DecryptOrderFragment(encryptedFragment *order.EncryptedFragment, keyPair identity.KeyPair) (*order.Fragment, error) {
+	data, err := keyPair.Decrypt(encryptedFragment.Ciphertext)
+	if err != nil {
+		return nil, err
+	}
+	serialized := &OrderFragment{}
+	if err := proto.Unmarshal(data, serialized); err != nil {
+		return nil, err
+	}
+	orderFragment, err := DeserializeOrderFragment(serialized)
+	if err != nil {
+		return nil, err
+	}
+	if !bytes.Equal(orderFragment.OrderID, encryptedFragment.OrderID) || !orderFragment.OrderExpiry.Equal(encryptedFragment.OrderExpiry) {
+		return nil, ErrEncryptedOrderFragmentMismatch
+	}
+	return orderFragment, nil
+}
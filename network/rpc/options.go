@@ -1,15 +1,20 @@
 package rpc
 
-import "time"
+import (
+	"time"
+
+	"github.com/republicprotocol/republic-go/shamir"
+)
 
 // ClientPoolOptions change the behavior of the ClientPool. By default, the
 // ClientPool will use the DefaultClientPoolOptions. The ClientPool has methods
 // that will configure it to use other options in a chaining style.
 type ClientPoolOptions struct {
-	Timeout        time.Duration `json:"timeout"`
-	TimeoutBackoff time.Duration `json:"timeoutBackoff"`
-	TimeoutRetries int           `json:"timeoutRetries"`
-	CacheLimit     int           `json:"cacheLimit"`
+	Timeout        time.Duration  `json:"timeout"`
+	TimeoutBackoff time.Duration  `json:"timeoutBackoff"`
+	TimeoutRetries int            `json:"timeoutRetries"`
+	CacheLimit     int            `json:"cacheLimit"`
+	Field          shamir.FieldID `json:"field"`
 }
 
 // DefaultClientPoolOptions returns the ClientPoolOptions that the ClientPool
@@ -60,13 +65,22 @@ func (pool *ClientPool) WithCacheLimit(cacheLimit int) *ClientPool {
 	return pool
 }
 
+// WithField returns a ClientPool that uses the given FieldID for all Clients.
+func (pool *ClientPool) WithField(field shamir.FieldID) *ClientPool {
+	pool.Enter(nil)
+	defer pool.Exit()
+	pool.options.Field = field
+	return pool
+}
+
 // ClientOptions change the behavior of the Client. By default, the Client will
 // use the DefaultClientOptions. The Client has methods that will configure it
 // to use other options in a chaining style.
 type ClientOptions struct {
-	Timeout        time.Duration `json:"timeout"`
-	TimeoutBackoff time.Duration `json:"timeoutBackoff"`
-	TimeoutRetries int           `json:"timeoutRetries"`
+	Timeout        time.Duration  `json:"timeout"`
+	TimeoutBackoff time.Duration  `json:"timeoutBackoff"`
+	TimeoutRetries int            `json:"timeoutRetries"`
+	Field          shamir.FieldID `json:"field"`
 }
 
 // DefaultClientOptions returns the ClientOptions that the Client uses when it
@@ -98,3 +112,12 @@ func (client *Client) WithTimeoutRetries(retries int) *Client {
 	client.Options.TimeoutRetries = retries
 	return client
 }
+
+// WithField returns a Client that identifies itself as using the finite field
+// with the given FieldID, and expects the Clients it pings to do the same.
+func (client *Client) WithField(field shamir.FieldID) *Client {
+	client.Options.Field = field
+	client.From.Field = field
+	client.SignedFrom.Field = field
+	return client
+}
@@ -33,6 +33,16 @@ It has these top-level messages:
 	GossipRequest
 	FinalizeRequest
 	Rumor
+	Commitments
+	NotificationsRequest
+	Subscription
+	Notification
+	RevealOrderRequest
+	Reveal
+	Order
+	EncryptedOrderFragment
+	UpdateMidpointRequest
+	MidpointUpdate
 */
 package rpc
 
@@ -75,6 +85,7 @@ func (m *Address) GetAddress() string {
 type MultiAddress struct {
 	Signature    []byte `protobuf:"bytes,1,opt,name=signature,proto3" json:"signature,omitempty"`
 	MultiAddress string `protobuf:"bytes,2,opt,name=multiAddress" json:"multiAddress,omitempty"`
+	Field        []byte `protobuf:"bytes,3,opt,name=field,proto3" json:"field,omitempty"`
 }
 
 func (m *MultiAddress) Reset()                    { *m = MultiAddress{} }
@@ -96,6 +107,13 @@ func (m *MultiAddress) GetMultiAddress() string {
 	return ""
 }
 
+func (m *MultiAddress) GetField() []byte {
+	if m != nil {
+		return m.Field
+	}
+	return nil
+}
+
 type Nothing struct {
 }
 
@@ -169,8 +187,9 @@ func (m *SignOrderFragmentRequest) GetOrderFragmentSignature() *OrderFragmentSig
 }
 
 type OpenOrderRequest struct {
-	From          *MultiAddress  `protobuf:"bytes,1,opt,name=from" json:"from,omitempty"`
-	OrderFragment *OrderFragment `protobuf:"bytes,2,opt,name=orderFragment" json:"orderFragment,omitempty"`
+	From                    *MultiAddress             `protobuf:"bytes,1,opt,name=from" json:"from,omitempty"`
+	OrderFragment           *OrderFragment            `protobuf:"bytes,2,opt,name=orderFragment" json:"orderFragment,omitempty"`
+	EncryptedOrderFragments []*EncryptedOrderFragment `protobuf:"bytes,3,rep,name=encryptedOrderFragments" json:"encryptedOrderFragments,omitempty"`
 }
 
 func (m *OpenOrderRequest) Reset()                    { *m = OpenOrderRequest{} }
@@ -192,6 +211,13 @@ func (m *OpenOrderRequest) GetOrderFragment() *OrderFragment {
 	return nil
 }
 
+func (m *OpenOrderRequest) GetEncryptedOrderFragments() []*EncryptedOrderFragment {
+	if m != nil {
+		return m.EncryptedOrderFragments
+	}
+	return nil
+}
+
 type CancelOrderRequest struct {
 	From                  *MultiAddress `protobuf:"bytes,1,opt,name=from" json:"from,omitempty"`
 	CancellationSignature []byte        `protobuf:"bytes,2,opt,name=cancellationSignature,proto3" json:"cancellationSignature,omitempty"`
@@ -225,7 +251,8 @@ func (m *CancelOrderRequest) GetOrderId() []byte {
 }
 
 type RandomFragmentSharesRequest struct {
-	From *MultiAddress `protobuf:"bytes,1,opt,name=from" json:"from,omitempty"`
+	From            *MultiAddress    `protobuf:"bytes,1,opt,name=from" json:"from,omitempty"`
+	RandomFragments *RandomFragments `protobuf:"bytes,2,opt,name=randomFragments" json:"randomFragments,omitempty"`
 }
 
 func (m *RandomFragmentSharesRequest) Reset()                    { *m = RandomFragmentSharesRequest{} }
@@ -240,9 +267,16 @@ func (m *RandomFragmentSharesRequest) GetFrom() *MultiAddress {
 	return nil
 }
 
+func (m *RandomFragmentSharesRequest) GetRandomFragments() *RandomFragments {
+	if m != nil {
+		return m.RandomFragments
+	}
+	return nil
+}
+
 type ResidueFragmentSharesRequest struct {
-	From            *MultiAddress    `protobuf:"bytes,1,opt,name=from" json:"from,omitempty"`
-	RandomFragments *RandomFragments `protobuf:"bytes,2,opt,name=randomFragments" json:"randomFragments,omitempty"`
+	From       *MultiAddress `protobuf:"bytes,1,opt,name=from" json:"from,omitempty"`
+	ResidueIds [][]byte      `protobuf:"bytes,2,rep,name=residueIds,proto3" json:"residueIds,omitempty"`
 }
 
 func (m *ResidueFragmentSharesRequest) Reset()                    { *m = ResidueFragmentSharesRequest{} }
@@ -257,9 +291,9 @@ func (m *ResidueFragmentSharesRequest) GetFrom() *MultiAddress {
 	return nil
 }
 
-func (m *ResidueFragmentSharesRequest) GetRandomFragments() *RandomFragments {
+func (m *ResidueFragmentSharesRequest) GetResidueIds() [][]byte {
 	if m != nil {
-		return m.RandomFragments
+		return m.ResidueIds
 	}
 	return nil
 }
@@ -339,10 +373,10 @@ func (m *BroadcastDeltaFragmentRequest) GetDeltaFragment() *DeltaFragment {
 }
 
 type AlphaBetaFragment struct {
-	Signature     []byte         `protobuf:"bytes,1,opt,name=signature,proto3" json:"signature,omitempty"`
-	ResidueId     []byte         `protobuf:"bytes,2,opt,name=residueId,proto3" json:"residueId,omitempty"`
-	AlphaFragment *OrderFragment `protobuf:"bytes,3,opt,name=alphaFragment" json:"alphaFragment,omitempty"`
-	BetaFragment  *OrderFragment `protobuf:"bytes,4,opt,name=betaFragment" json:"betaFragment,omitempty"`
+	Signature  []byte `protobuf:"bytes,1,opt,name=signature,proto3" json:"signature,omitempty"`
+	ResidueId  []byte `protobuf:"bytes,2,opt,name=residueId,proto3" json:"residueId,omitempty"`
+	AlphaShare []byte `protobuf:"bytes,3,opt,name=alphaShare,proto3" json:"alphaShare,omitempty"`
+	BetaShare  []byte `protobuf:"bytes,4,opt,name=betaShare,proto3" json:"betaShare,omitempty"`
 }
 
 func (m *AlphaBetaFragment) Reset()                    { *m = AlphaBetaFragment{} }
@@ -364,16 +398,16 @@ func (m *AlphaBetaFragment) GetResidueId() []byte {
 	return nil
 }
 
-func (m *AlphaBetaFragment) GetAlphaFragment() *OrderFragment {
+func (m *AlphaBetaFragment) GetAlphaShare() []byte {
 	if m != nil {
-		return m.AlphaFragment
+		return m.AlphaShare
 	}
 	return nil
 }
 
-func (m *AlphaBetaFragment) GetBetaFragment() *OrderFragment {
+func (m *AlphaBetaFragment) GetBetaShare() []byte {
 	if m != nil {
-		return m.BetaFragment
+		return m.BetaShare
 	}
 	return nil
 }
@@ -386,11 +420,8 @@ type DeltaFragment struct {
 	SellOrderId         []byte `protobuf:"bytes,5,opt,name=sellOrderId,proto3" json:"sellOrderId,omitempty"`
 	BuyOrderFragmentId  []byte `protobuf:"bytes,6,opt,name=buyOrderFragmentId,proto3" json:"buyOrderFragmentId,omitempty"`
 	SellOrderFragmentId []byte `protobuf:"bytes,7,opt,name=sellOrderFragmentId,proto3" json:"sellOrderFragmentId,omitempty"`
-	FstCodeShare        []byte `protobuf:"bytes,8,opt,name=fstCodeShare,proto3" json:"fstCodeShare,omitempty"`
-	SndCodeShare        []byte `protobuf:"bytes,9,opt,name=sndCodeShare,proto3" json:"sndCodeShare,omitempty"`
-	PriceShare          []byte `protobuf:"bytes,10,opt,name=priceShare,proto3" json:"priceShare,omitempty"`
-	MaxVolumeShare      []byte `protobuf:"bytes,11,opt,name=maxVolumeShare,proto3" json:"maxVolumeShare,omitempty"`
-	MinVolumeShare      []byte `protobuf:"bytes,12,opt,name=minVolumeShare,proto3" json:"minVolumeShare,omitempty"`
+	MatchShare          []byte `protobuf:"bytes,8,opt,name=matchShare,proto3" json:"matchShare,omitempty"`
+	CommitmentsHash     []byte `protobuf:"bytes,9,opt,name=commitmentsHash,proto3" json:"commitmentsHash,omitempty"`
 }
 
 func (m *DeltaFragment) Reset()                    { *m = DeltaFragment{} }
@@ -447,52 +478,38 @@ func (m *DeltaFragment) GetSellOrderFragmentId() []byte {
 	return nil
 }
 
-func (m *DeltaFragment) GetFstCodeShare() []byte {
-	if m != nil {
-		return m.FstCodeShare
-	}
-	return nil
-}
-
-func (m *DeltaFragment) GetSndCodeShare() []byte {
-	if m != nil {
-		return m.SndCodeShare
-	}
-	return nil
-}
-
-func (m *DeltaFragment) GetPriceShare() []byte {
-	if m != nil {
-		return m.PriceShare
-	}
-	return nil
-}
-
-func (m *DeltaFragment) GetMaxVolumeShare() []byte {
+func (m *DeltaFragment) GetMatchShare() []byte {
 	if m != nil {
-		return m.MaxVolumeShare
+		return m.MatchShare
 	}
 	return nil
 }
 
-func (m *DeltaFragment) GetMinVolumeShare() []byte {
+func (m *DeltaFragment) GetCommitmentsHash() []byte {
 	if m != nil {
-		return m.MinVolumeShare
+		return m.CommitmentsHash
 	}
 	return nil
 }
 
 type OrderFragment struct {
-	Signature      []byte `protobuf:"bytes,1,opt,name=signature,proto3" json:"signature,omitempty"`
-	Id             []byte `protobuf:"bytes,2,opt,name=id,proto3" json:"id,omitempty"`
-	OrderId        []byte `protobuf:"bytes,3,opt,name=orderId,proto3" json:"orderId,omitempty"`
-	OrderType      int64  `protobuf:"varint,4,opt,name=orderType" json:"orderType,omitempty"`
-	OrderParity    int64  `protobuf:"varint,5,opt,name=orderParity" json:"orderParity,omitempty"`
-	FstCodeShare   []byte `protobuf:"bytes,6,opt,name=fstCodeShare,proto3" json:"fstCodeShare,omitempty"`
-	SndCodeShare   []byte `protobuf:"bytes,7,opt,name=sndCodeShare,proto3" json:"sndCodeShare,omitempty"`
-	PriceShare     []byte `protobuf:"bytes,8,opt,name=priceShare,proto3" json:"priceShare,omitempty"`
-	MaxVolumeShare []byte `protobuf:"bytes,9,opt,name=maxVolumeShare,proto3" json:"maxVolumeShare,omitempty"`
-	MinVolumeShare []byte `protobuf:"bytes,10,opt,name=minVolumeShare,proto3" json:"minVolumeShare,omitempty"`
+	Signature        []byte       `protobuf:"bytes,1,opt,name=signature,proto3" json:"signature,omitempty"`
+	Id               []byte       `protobuf:"bytes,2,opt,name=id,proto3" json:"id,omitempty"`
+	OrderId          []byte       `protobuf:"bytes,3,opt,name=orderId,proto3" json:"orderId,omitempty"`
+	OrderType        int64        `protobuf:"varint,4,opt,name=orderType" json:"orderType,omitempty"`
+	OrderParity      int64        `protobuf:"varint,5,opt,name=orderParity" json:"orderParity,omitempty"`
+	FstCodeShare     []byte       `protobuf:"bytes,6,opt,name=fstCodeShare,proto3" json:"fstCodeShare,omitempty"`
+	SndCodeShare     []byte       `protobuf:"bytes,7,opt,name=sndCodeShare,proto3" json:"sndCodeShare,omitempty"`
+	PriceShare       []byte       `protobuf:"bytes,8,opt,name=priceShare,proto3" json:"priceShare,omitempty"`
+	MaxVolumeShare   []byte       `protobuf:"bytes,9,opt,name=maxVolumeShare,proto3" json:"maxVolumeShare,omitempty"`
+	MinVolumeShare   []byte       `protobuf:"bytes,10,opt,name=minVolumeShare,proto3" json:"minVolumeShare,omitempty"`
+	OrderExpiry      int64        `protobuf:"varint,11,opt,name=orderExpiry" json:"orderExpiry,omitempty"`
+	Trader           []byte       `protobuf:"bytes,12,opt,name=trader,proto3" json:"trader,omitempty"`
+	Commitments      *Commitments `protobuf:"bytes,13,opt,name=commitments" json:"commitments,omitempty"`
+	Field            []byte       `protobuf:"bytes,14,opt,name=field,proto3" json:"field,omitempty"`
+	OrderTimeInForce int64        `protobuf:"varint,15,opt,name=orderTimeInForce" json:"orderTimeInForce,omitempty"`
+	OrderNonce       []byte       `protobuf:"bytes,16,opt,name=orderNonce,proto3" json:"orderNonce,omitempty"`
+	OrderOpenTime    int64        `protobuf:"varint,17,opt,name=orderOpenTime" json:"orderOpenTime,omitempty"`
 }
 
 func (m *OrderFragment) Reset()                    { *m = OrderFragment{} }
@@ -570,6 +587,55 @@ func (m *OrderFragment) GetMinVolumeShare() []byte {
 	return nil
 }
 
+func (m *OrderFragment) GetOrderExpiry() int64 {
+	if m != nil {
+		return m.OrderExpiry
+	}
+	return 0
+}
+
+func (m *OrderFragment) GetTrader() []byte {
+	if m != nil {
+		return m.Trader
+	}
+	return nil
+}
+
+func (m *OrderFragment) GetCommitments() *Commitments {
+	if m != nil {
+		return m.Commitments
+	}
+	return nil
+}
+
+func (m *OrderFragment) GetField() []byte {
+	if m != nil {
+		return m.Field
+	}
+	return nil
+}
+
+func (m *OrderFragment) GetOrderTimeInForce() int64 {
+	if m != nil {
+		return m.OrderTimeInForce
+	}
+	return 0
+}
+
+func (m *OrderFragment) GetOrderNonce() []byte {
+	if m != nil {
+		return m.OrderNonce
+	}
+	return nil
+}
+
+func (m *OrderFragment) GetOrderOpenTime() int64 {
+	if m != nil {
+		return m.OrderOpenTime
+	}
+	return 0
+}
+
 type OrderFragmentSignature struct {
 	Signature       []byte `protobuf:"bytes,1,opt,name=signature,proto3" json:"signature,omitempty"`
 	OrderFragmentId []byte `protobuf:"bytes,2,opt,name=orderFragmentId,proto3" json:"orderFragmentId,omitempty"`
@@ -676,7 +742,9 @@ func (m *ResidueFragments) GetResidueFragments() []*ResidueFragment {
 
 type RandomFragment struct {
 	Signature []byte `protobuf:"bytes,1,opt,name=signature,proto3" json:"signature,omitempty"`
-	Share     []byte `protobuf:"bytes,2,opt,name=share,proto3" json:"share,omitempty"`
+	ResidueId []byte `protobuf:"bytes,2,opt,name=residueId,proto3" json:"residueId,omitempty"`
+	AShare    []byte `protobuf:"bytes,3,opt,name=aShare,proto3" json:"aShare,omitempty"`
+	BShare    []byte `protobuf:"bytes,4,opt,name=bShare,proto3" json:"bShare,omitempty"`
 }
 
 func (m *RandomFragment) Reset()                    { *m = RandomFragment{} }
@@ -691,9 +759,23 @@ func (m *RandomFragment) GetSignature() []byte {
 	return nil
 }
 
-func (m *RandomFragment) GetShare() []byte {
+func (m *RandomFragment) GetResidueId() []byte {
+	if m != nil {
+		return m.ResidueId
+	}
+	return nil
+}
+
+func (m *RandomFragment) GetAShare() []byte {
+	if m != nil {
+		return m.AShare
+	}
+	return nil
+}
+
+func (m *RandomFragment) GetBShare() []byte {
 	if m != nil {
-		return m.Share
+		return m.BShare
 	}
 	return nil
 }
@@ -723,9 +805,11 @@ func (m *RandomFragments) GetRandomFragments() []*RandomFragment {
 }
 
 type SyncBlock struct {
-	Signature    []byte                  `protobuf:"bytes,1,opt,name=signature,proto3" json:"signature,omitempty"`
-	DeltaBlock   *SyncBlock_DeltaBlock   `protobuf:"bytes,2,opt,name=deltaBlock" json:"deltaBlock,omitempty"`
-	ResidueBlock *SyncBlock_ResidueBlock `protobuf:"bytes,3,opt,name=residueBlock" json:"residueBlock,omitempty"`
+	Signature      []byte                    `protobuf:"bytes,1,opt,name=signature,proto3" json:"signature,omitempty"`
+	DeltaBlock     *SyncBlock_DeltaBlock     `protobuf:"bytes,2,opt,name=deltaBlock" json:"deltaBlock,omitempty"`
+	ResidueBlock   *SyncBlock_ResidueBlock   `protobuf:"bytes,3,opt,name=residueBlock" json:"residueBlock,omitempty"`
+	OrderFragments []*EncryptedOrderFragment `protobuf:"bytes,4,rep,name=orderFragments" json:"orderFragments,omitempty"`
+	Finalizations  []*SyncBlock_Finalization `protobuf:"bytes,5,rep,name=finalizations" json:"finalizations,omitempty"`
 }
 
 func (m *SyncBlock) Reset()                    { *m = SyncBlock{} }
@@ -754,6 +838,20 @@ func (m *SyncBlock) GetResidueBlock() *SyncBlock_ResidueBlock {
 	return nil
 }
 
+func (m *SyncBlock) GetOrderFragments() []*EncryptedOrderFragment {
+	if m != nil {
+		return m.OrderFragments
+	}
+	return nil
+}
+
+func (m *SyncBlock) GetFinalizations() []*SyncBlock_Finalization {
+	if m != nil {
+		return m.Finalizations
+	}
+	return nil
+}
+
 type SyncBlock_DeltaBlock struct {
 	Pending    []*DeltaFragment `protobuf:"bytes,1,rep,name=pending" json:"pending,omitempty"`
 	Electing   []*DeltaFragment `protobuf:"bytes,2,rep,name=electing" json:"electing,omitempty"`
@@ -866,6 +964,22 @@ func (m *SyncBlock_ResidueBlock) GetMismatched() []*ResidueFragment {
 	return nil
 }
 
+type SyncBlock_Finalization struct {
+	Rumors []*Rumor `protobuf:"bytes,1,rep,name=rumors" json:"rumors,omitempty"`
+}
+
+func (m *SyncBlock_Finalization) Reset()                    { *m = SyncBlock_Finalization{} }
+func (m *SyncBlock_Finalization) String() string            { return proto.CompactTextString(m) }
+func (*SyncBlock_Finalization) ProtoMessage()               {}
+func (*SyncBlock_Finalization) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{21, 2} }
+
+func (m *SyncBlock_Finalization) GetRumors() []*Rumor {
+	if m != nil {
+		return m.Rumors
+	}
+	return nil
+}
+
 type GossipRequest struct {
 	From  *MultiAddress `protobuf:"bytes,1,opt,name=from" json:"from,omitempty"`
 	Rumor *Rumor        `protobuf:"bytes,2,opt,name=rumor" json:"rumor,omitempty"`
@@ -891,8 +1005,8 @@ func (m *GossipRequest) GetRumor() *Rumor {
 }
 
 type FinalizeRequest struct {
-	From  *MultiAddress `protobuf:"bytes,1,opt,name=from" json:"from,omitempty"`
-	Rumor *Rumor        `protobuf:"bytes,2,opt,name=rumor" json:"rumor,omitempty"`
+	From   *MultiAddress `protobuf:"bytes,1,opt,name=from" json:"from,omitempty"`
+	Rumors []*Rumor      `protobuf:"bytes,2,rep,name=rumors" json:"rumors,omitempty"`
 }
 
 func (m *FinalizeRequest) Reset()                    { *m = FinalizeRequest{} }
@@ -907,9 +1021,9 @@ func (m *FinalizeRequest) GetFrom() *MultiAddress {
 	return nil
 }
 
-func (m *FinalizeRequest) GetRumor() *Rumor {
+func (m *FinalizeRequest) GetRumors() []*Rumor {
 	if m != nil {
-		return m.Rumor
+		return m.Rumors
 	}
 	return nil
 }
@@ -946,6 +1060,518 @@ func (m *Rumor) GetSellOrderId() []byte {
 	return nil
 }
 
+type Commitments struct {
+	FstCode                [][]byte `protobuf:"bytes,1,rep,name=fstCode,proto3" json:"fstCode,omitempty"`
+	SndCode                [][]byte `protobuf:"bytes,2,rep,name=sndCode,proto3" json:"sndCode,omitempty"`
+	Price                  [][]byte `protobuf:"bytes,3,rep,name=price,proto3" json:"price,omitempty"`
+	MaxVolume              [][]byte `protobuf:"bytes,4,rep,name=maxVolume,proto3" json:"maxVolume,omitempty"`
+	MinVolume              [][]byte `protobuf:"bytes,5,rep,name=minVolume,proto3" json:"minVolume,omitempty"`
+	FstCodeBlinding        []byte   `protobuf:"bytes,6,opt,name=fstCodeBlinding,proto3" json:"fstCodeBlinding,omitempty"`
+	SndCodeBlinding        []byte   `protobuf:"bytes,7,opt,name=sndCodeBlinding,proto3" json:"sndCodeBlinding,omitempty"`
+	PriceBlinding          []byte   `protobuf:"bytes,8,opt,name=priceBlinding,proto3" json:"priceBlinding,omitempty"`
+	MaxVolumeBlinding      []byte   `protobuf:"bytes,9,opt,name=maxVolumeBlinding,proto3" json:"maxVolumeBlinding,omitempty"`
+	MinVolumeBlinding      []byte   `protobuf:"bytes,10,opt,name=minVolumeBlinding,proto3" json:"minVolumeBlinding,omitempty"`
+	MarketFstCodes         []int64  `protobuf:"varint,11,rep,packed,name=marketFstCodes,proto3" json:"marketFstCodes,omitempty"`
+	MarketSndCodes         []int64  `protobuf:"varint,12,rep,packed,name=marketSndCodes,proto3" json:"marketSndCodes,omitempty"`
+	MarketChallenges       [][]byte `protobuf:"bytes,13,rep,name=marketChallenges,proto3" json:"marketChallenges,omitempty"`
+	MarketFstCodeResponses [][]byte `protobuf:"bytes,14,rep,name=marketFstCodeResponses,proto3" json:"marketFstCodeResponses,omitempty"`
+	MarketSndCodeResponses [][]byte `protobuf:"bytes,15,rep,name=marketSndCodeResponses,proto3" json:"marketSndCodeResponses,omitempty"`
+}
+
+func (m *Commitments) Reset()                    { *m = Commitments{} }
+func (m *Commitments) String() string            { return proto.CompactTextString(m) }
+func (*Commitments) ProtoMessage()               {}
+func (*Commitments) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{25} }
+
+func (m *Commitments) GetFstCode() [][]byte {
+	if m != nil {
+		return m.FstCode
+	}
+	return nil
+}
+
+func (m *Commitments) GetSndCode() [][]byte {
+	if m != nil {
+		return m.SndCode
+	}
+	return nil
+}
+
+func (m *Commitments) GetPrice() [][]byte {
+	if m != nil {
+		return m.Price
+	}
+	return nil
+}
+
+func (m *Commitments) GetMaxVolume() [][]byte {
+	if m != nil {
+		return m.MaxVolume
+	}
+	return nil
+}
+
+func (m *Commitments) GetMinVolume() [][]byte {
+	if m != nil {
+		return m.MinVolume
+	}
+	return nil
+}
+
+func (m *Commitments) GetFstCodeBlinding() []byte {
+	if m != nil {
+		return m.FstCodeBlinding
+	}
+	return nil
+}
+
+func (m *Commitments) GetSndCodeBlinding() []byte {
+	if m != nil {
+		return m.SndCodeBlinding
+	}
+	return nil
+}
+
+func (m *Commitments) GetPriceBlinding() []byte {
+	if m != nil {
+		return m.PriceBlinding
+	}
+	return nil
+}
+
+func (m *Commitments) GetMaxVolumeBlinding() []byte {
+	if m != nil {
+		return m.MaxVolumeBlinding
+	}
+	return nil
+}
+
+func (m *Commitments) GetMinVolumeBlinding() []byte {
+	if m != nil {
+		return m.MinVolumeBlinding
+	}
+	return nil
+}
+
+func (m *Commitments) GetMarketFstCodes() []int64 {
+	if m != nil {
+		return m.MarketFstCodes
+	}
+	return nil
+}
+
+func (m *Commitments) GetMarketSndCodes() []int64 {
+	if m != nil {
+		return m.MarketSndCodes
+	}
+	return nil
+}
+
+func (m *Commitments) GetMarketChallenges() [][]byte {
+	if m != nil {
+		return m.MarketChallenges
+	}
+	return nil
+}
+
+func (m *Commitments) GetMarketFstCodeResponses() [][]byte {
+	if m != nil {
+		return m.MarketFstCodeResponses
+	}
+	return nil
+}
+
+func (m *Commitments) GetMarketSndCodeResponses() [][]byte {
+	if m != nil {
+		return m.MarketSndCodeResponses
+	}
+	return nil
+}
+
+type NotificationsRequest struct {
+	From         *MultiAddress `protobuf:"bytes,1,opt,name=from" json:"from,omitempty"`
+	Subscription *Subscription `protobuf:"bytes,2,opt,name=subscription" json:"subscription,omitempty"`
+}
+
+func (m *NotificationsRequest) Reset()                    { *m = NotificationsRequest{} }
+func (m *NotificationsRequest) String() string            { return proto.CompactTextString(m) }
+func (*NotificationsRequest) ProtoMessage()               {}
+func (*NotificationsRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{26} }
+
+func (m *NotificationsRequest) GetFrom() *MultiAddress {
+	if m != nil {
+		return m.From
+	}
+	return nil
+}
+
+func (m *NotificationsRequest) GetSubscription() *Subscription {
+	if m != nil {
+		return m.Subscription
+	}
+	return nil
+}
+
+type Subscription struct {
+	Signature []byte `protobuf:"bytes,1,opt,name=signature,proto3" json:"signature,omitempty"`
+	Trader    []byte `protobuf:"bytes,2,opt,name=trader,proto3" json:"trader,omitempty"`
+	Timestamp int64  `protobuf:"varint,3,opt,name=timestamp" json:"timestamp,omitempty"`
+}
+
+func (m *Subscription) Reset()                    { *m = Subscription{} }
+func (m *Subscription) String() string            { return proto.CompactTextString(m) }
+func (*Subscription) ProtoMessage()               {}
+func (*Subscription) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{27} }
+
+func (m *Subscription) GetSignature() []byte {
+	if m != nil {
+		return m.Signature
+	}
+	return nil
+}
+
+func (m *Subscription) GetTrader() []byte {
+	if m != nil {
+		return m.Trader
+	}
+	return nil
+}
+
+func (m *Subscription) GetTimestamp() int64 {
+	if m != nil {
+		return m.Timestamp
+	}
+	return 0
+}
+
+type Notification struct {
+	Signature           []byte `protobuf:"bytes,1,opt,name=signature,proto3" json:"signature,omitempty"`
+	Type                int64  `protobuf:"varint,2,opt,name=type" json:"type,omitempty"`
+	OrderId             []byte `protobuf:"bytes,3,opt,name=orderId,proto3" json:"orderId,omitempty"`
+	CounterpartyOrderId []byte `protobuf:"bytes,4,opt,name=counterpartyOrderId,proto3" json:"counterpartyOrderId,omitempty"`
+}
+
+func (m *Notification) Reset()                    { *m = Notification{} }
+func (m *Notification) String() string            { return proto.CompactTextString(m) }
+func (*Notification) ProtoMessage()               {}
+func (*Notification) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{28} }
+
+func (m *Notification) GetSignature() []byte {
+	if m != nil {
+		return m.Signature
+	}
+	return nil
+}
+
+func (m *Notification) GetType() int64 {
+	if m != nil {
+		return m.Type
+	}
+	return 0
+}
+
+func (m *Notification) GetOrderId() []byte {
+	if m != nil {
+		return m.OrderId
+	}
+	return nil
+}
+
+func (m *Notification) GetCounterpartyOrderId() []byte {
+	if m != nil {
+		return m.CounterpartyOrderId
+	}
+	return nil
+}
+
+type RevealOrderRequest struct {
+	From   *MultiAddress `protobuf:"bytes,1,opt,name=from" json:"from,omitempty"`
+	Reveal *Reveal       `protobuf:"bytes,2,opt,name=reveal" json:"reveal,omitempty"`
+}
+
+func (m *RevealOrderRequest) Reset()                    { *m = RevealOrderRequest{} }
+func (m *RevealOrderRequest) String() string            { return proto.CompactTextString(m) }
+func (*RevealOrderRequest) ProtoMessage()               {}
+func (*RevealOrderRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{29} }
+
+func (m *RevealOrderRequest) GetFrom() *MultiAddress {
+	if m != nil {
+		return m.From
+	}
+	return nil
+}
+
+func (m *RevealOrderRequest) GetReveal() *Reveal {
+	if m != nil {
+		return m.Reveal
+	}
+	return nil
+}
+
+type Reveal struct {
+	Signature []byte `protobuf:"bytes,1,opt,name=signature,proto3" json:"signature,omitempty"`
+	Trader    []byte `protobuf:"bytes,2,opt,name=trader,proto3" json:"trader,omitempty"`
+	Order     *Order `protobuf:"bytes,3,opt,name=order" json:"order,omitempty"`
+	Account   []byte `protobuf:"bytes,4,opt,name=account,proto3" json:"account,omitempty"`
+}
+
+func (m *Reveal) Reset()                    { *m = Reveal{} }
+func (m *Reveal) String() string            { return proto.CompactTextString(m) }
+func (*Reveal) ProtoMessage()               {}
+func (*Reveal) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{30} }
+
+func (m *Reveal) GetSignature() []byte {
+	if m != nil {
+		return m.Signature
+	}
+	return nil
+}
+
+func (m *Reveal) GetTrader() []byte {
+	if m != nil {
+		return m.Trader
+	}
+	return nil
+}
+
+func (m *Reveal) GetOrder() *Order {
+	if m != nil {
+		return m.Order
+	}
+	return nil
+}
+
+func (m *Reveal) GetAccount() []byte {
+	if m != nil {
+		return m.Account
+	}
+	return nil
+}
+
+type Order struct {
+	Signature   []byte `protobuf:"bytes,1,opt,name=signature,proto3" json:"signature,omitempty"`
+	Id          []byte `protobuf:"bytes,2,opt,name=id,proto3" json:"id,omitempty"`
+	Type        int64  `protobuf:"varint,3,opt,name=type" json:"type,omitempty"`
+	Parity      int64  `protobuf:"varint,4,opt,name=parity" json:"parity,omitempty"`
+	Expiry      int64  `protobuf:"varint,5,opt,name=expiry" json:"expiry,omitempty"`
+	TimeInForce int64  `protobuf:"varint,6,opt,name=timeInForce" json:"timeInForce,omitempty"`
+	FstCode     int64  `protobuf:"varint,7,opt,name=fstCode" json:"fstCode,omitempty"`
+	SndCode     int64  `protobuf:"varint,8,opt,name=sndCode" json:"sndCode,omitempty"`
+	Price       []byte `protobuf:"bytes,9,opt,name=price,proto3" json:"price,omitempty"`
+	MaxVolume   []byte `protobuf:"bytes,10,opt,name=maxVolume,proto3" json:"maxVolume,omitempty"`
+	MinVolume   []byte `protobuf:"bytes,11,opt,name=minVolume,proto3" json:"minVolume,omitempty"`
+	Nonce       []byte `protobuf:"bytes,12,opt,name=nonce,proto3" json:"nonce,omitempty"`
+	OpenTime    int64  `protobuf:"varint,13,opt,name=openTime" json:"openTime,omitempty"`
+}
+
+func (m *Order) Reset()                    { *m = Order{} }
+func (m *Order) String() string            { return proto.CompactTextString(m) }
+func (*Order) ProtoMessage()               {}
+func (*Order) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{31} }
+
+func (m *Order) GetSignature() []byte {
+	if m != nil {
+		return m.Signature
+	}
+	return nil
+}
+
+func (m *Order) GetId() []byte {
+	if m != nil {
+		return m.Id
+	}
+	return nil
+}
+
+func (m *Order) GetType() int64 {
+	if m != nil {
+		return m.Type
+	}
+	return 0
+}
+
+func (m *Order) GetParity() int64 {
+	if m != nil {
+		return m.Parity
+	}
+	return 0
+}
+
+func (m *Order) GetExpiry() int64 {
+	if m != nil {
+		return m.Expiry
+	}
+	return 0
+}
+
+func (m *Order) GetTimeInForce() int64 {
+	if m != nil {
+		return m.TimeInForce
+	}
+	return 0
+}
+
+func (m *Order) GetFstCode() int64 {
+	if m != nil {
+		return m.FstCode
+	}
+	return 0
+}
+
+func (m *Order) GetSndCode() int64 {
+	if m != nil {
+		return m.SndCode
+	}
+	return 0
+}
+
+func (m *Order) GetPrice() []byte {
+	if m != nil {
+		return m.Price
+	}
+	return nil
+}
+
+func (m *Order) GetMaxVolume() []byte {
+	if m != nil {
+		return m.MaxVolume
+	}
+	return nil
+}
+
+func (m *Order) GetMinVolume() []byte {
+	if m != nil {
+		return m.MinVolume
+	}
+	return nil
+}
+
+func (m *Order) GetNonce() []byte {
+	if m != nil {
+		return m.Nonce
+	}
+	return nil
+}
+
+func (m *Order) GetOpenTime() int64 {
+	if m != nil {
+		return m.OpenTime
+	}
+	return 0
+}
+
+type EncryptedOrderFragment struct {
+	To          []byte `protobuf:"bytes,1,opt,name=to,proto3" json:"to,omitempty"`
+	OrderId     []byte `protobuf:"bytes,2,opt,name=orderId,proto3" json:"orderId,omitempty"`
+	OrderExpiry int64  `protobuf:"varint,3,opt,name=orderExpiry" json:"orderExpiry,omitempty"`
+	Ciphertext  []byte `protobuf:"bytes,4,opt,name=ciphertext,proto3" json:"ciphertext,omitempty"`
+}
+
+func (m *EncryptedOrderFragment) Reset()                    { *m = EncryptedOrderFragment{} }
+func (m *EncryptedOrderFragment) String() string            { return proto.CompactTextString(m) }
+func (*EncryptedOrderFragment) ProtoMessage()               {}
+func (*EncryptedOrderFragment) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{32} }
+
+func (m *EncryptedOrderFragment) GetTo() []byte {
+	if m != nil {
+		return m.To
+	}
+	return nil
+}
+
+func (m *EncryptedOrderFragment) GetOrderId() []byte {
+	if m != nil {
+		return m.OrderId
+	}
+	return nil
+}
+
+func (m *EncryptedOrderFragment) GetOrderExpiry() int64 {
+	if m != nil {
+		return m.OrderExpiry
+	}
+	return 0
+}
+
+func (m *EncryptedOrderFragment) GetCiphertext() []byte {
+	if m != nil {
+		return m.Ciphertext
+	}
+	return nil
+}
+
+type UpdateMidpointRequest struct {
+	From           *MultiAddress   `protobuf:"bytes,1,opt,name=from" json:"from,omitempty"`
+	MidpointUpdate *MidpointUpdate `protobuf:"bytes,2,opt,name=midpointUpdate" json:"midpointUpdate,omitempty"`
+}
+
+func (m *UpdateMidpointRequest) Reset()                    { *m = UpdateMidpointRequest{} }
+func (m *UpdateMidpointRequest) String() string            { return proto.CompactTextString(m) }
+func (*UpdateMidpointRequest) ProtoMessage()               {}
+func (*UpdateMidpointRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{33} }
+
+func (m *UpdateMidpointRequest) GetFrom() *MultiAddress {
+	if m != nil {
+		return m.From
+	}
+	return nil
+}
+
+func (m *UpdateMidpointRequest) GetMidpointUpdate() *MidpointUpdate {
+	if m != nil {
+		return m.MidpointUpdate
+	}
+	return nil
+}
+
+type MidpointUpdate struct {
+	Signature []byte `protobuf:"bytes,1,opt,name=signature,proto3" json:"signature,omitempty"`
+	FstCode   int64  `protobuf:"varint,2,opt,name=fstCode" json:"fstCode,omitempty"`
+	SndCode   int64  `protobuf:"varint,3,opt,name=sndCode" json:"sndCode,omitempty"`
+	Midpoint  []byte `protobuf:"bytes,4,opt,name=midpoint,proto3" json:"midpoint,omitempty"`
+	Time      int64  `protobuf:"varint,5,opt,name=time" json:"time,omitempty"`
+}
+
+func (m *MidpointUpdate) Reset()                    { *m = MidpointUpdate{} }
+func (m *MidpointUpdate) String() string            { return proto.CompactTextString(m) }
+func (*MidpointUpdate) ProtoMessage()               {}
+func (*MidpointUpdate) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{34} }
+
+func (m *MidpointUpdate) GetSignature() []byte {
+	if m != nil {
+		return m.Signature
+	}
+	return nil
+}
+
+func (m *MidpointUpdate) GetFstCode() int64 {
+	if m != nil {
+		return m.FstCode
+	}
+	return 0
+}
+
+func (m *MidpointUpdate) GetSndCode() int64 {
+	if m != nil {
+		return m.SndCode
+	}
+	return 0
+}
+
+func (m *MidpointUpdate) GetMidpoint() []byte {
+	if m != nil {
+		return m.Midpoint
+	}
+	return nil
+}
+
+func (m *MidpointUpdate) GetTime() int64 {
+	if m != nil {
+		return m.Time
+	}
+	return 0
+}
+
 func init() {
 	proto.RegisterType((*Address)(nil), "rpc.Address")
 	proto.RegisterType((*MultiAddress)(nil), "rpc.MultiAddress")
@@ -971,9 +1597,20 @@ func init() {
 	proto.RegisterType((*SyncBlock)(nil), "rpc.SyncBlock")
 	proto.RegisterType((*SyncBlock_DeltaBlock)(nil), "rpc.SyncBlock.DeltaBlock")
 	proto.RegisterType((*SyncBlock_ResidueBlock)(nil), "rpc.SyncBlock.ResidueBlock")
+	proto.RegisterType((*SyncBlock_Finalization)(nil), "rpc.SyncBlock.Finalization")
 	proto.RegisterType((*GossipRequest)(nil), "rpc.GossipRequest")
 	proto.RegisterType((*FinalizeRequest)(nil), "rpc.FinalizeRequest")
 	proto.RegisterType((*Rumor)(nil), "rpc.Rumor")
+	proto.RegisterType((*Commitments)(nil), "rpc.Commitments")
+	proto.RegisterType((*NotificationsRequest)(nil), "rpc.NotificationsRequest")
+	proto.RegisterType((*Subscription)(nil), "rpc.Subscription")
+	proto.RegisterType((*Notification)(nil), "rpc.Notification")
+	proto.RegisterType((*RevealOrderRequest)(nil), "rpc.RevealOrderRequest")
+	proto.RegisterType((*Reveal)(nil), "rpc.Reveal")
+	proto.RegisterType((*Order)(nil), "rpc.Order")
+	proto.RegisterType((*EncryptedOrderFragment)(nil), "rpc.EncryptedOrderFragment")
+	proto.RegisterType((*UpdateMidpointRequest)(nil), "rpc.UpdateMidpointRequest")
+	proto.RegisterType((*MidpointUpdate)(nil), "rpc.MidpointUpdate")
 }
 
 // Reference imports to suppress errors if they are not otherwise used.
@@ -1176,6 +1813,9 @@ type DarkClient interface {
 	SignOrderFragment(ctx context.Context, in *SignOrderFragmentRequest, opts ...grpc.CallOption) (*OrderFragmentSignature, error)
 	OpenOrder(ctx context.Context, in *OpenOrderRequest, opts ...grpc.CallOption) (*Nothing, error)
 	CancelOrder(ctx context.Context, in *CancelOrderRequest, opts ...grpc.CallOption) (*Nothing, error)
+	Notifications(ctx context.Context, in *NotificationsRequest, opts ...grpc.CallOption) (Dark_NotificationsClient, error)
+	RevealOrder(ctx context.Context, in *RevealOrderRequest, opts ...grpc.CallOption) (*Nothing, error)
+	UpdateMidpoint(ctx context.Context, in *UpdateMidpointRequest, opts ...grpc.CallOption) (*Nothing, error)
 	RandomFragmentShares(ctx context.Context, in *RandomFragmentSharesRequest, opts ...grpc.CallOption) (*RandomFragments, error)
 	ResidueFragmentShares(ctx context.Context, in *ResidueFragmentSharesRequest, opts ...grpc.CallOption) (*ResidueFragments, error)
 	ComputeResidueFragment(ctx context.Context, in *ComputeResidueFragmentRequest, opts ...grpc.CallOption) (*Nothing, error)
@@ -1250,6 +1890,56 @@ func (c *darkClient) CancelOrder(ctx context.Context, in *CancelOrderRequest, op
 	return out, nil
 }
 
+func (c *darkClient) Notifications(ctx context.Context, in *NotificationsRequest, opts ...grpc.CallOption) (Dark_NotificationsClient, error) {
+	stream, err := grpc.NewClientStream(ctx, &_Dark_serviceDesc.Streams[1], c.cc, "/rpc.Dark/Notifications", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &darkNotificationsClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type Dark_NotificationsClient interface {
+	Recv() (*Notification, error)
+	grpc.ClientStream
+}
+
+type darkNotificationsClient struct {
+	grpc.ClientStream
+}
+
+func (x *darkNotificationsClient) Recv() (*Notification, error) {
+	m := new(Notification)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *darkClient) RevealOrder(ctx context.Context, in *RevealOrderRequest, opts ...grpc.CallOption) (*Nothing, error) {
+	out := new(Nothing)
+	err := grpc.Invoke(ctx, "/rpc.Dark/RevealOrder", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *darkClient) UpdateMidpoint(ctx context.Context, in *UpdateMidpointRequest, opts ...grpc.CallOption) (*Nothing, error) {
+	out := new(Nothing)
+	err := grpc.Invoke(ctx, "/rpc.Dark/UpdateMidpoint", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 func (c *darkClient) RandomFragmentShares(ctx context.Context, in *RandomFragmentSharesRequest, opts ...grpc.CallOption) (*RandomFragments, error) {
 	out := new(RandomFragments)
 	err := grpc.Invoke(ctx, "/rpc.Dark/RandomFragmentShares", in, out, c.cc, opts...)
@@ -1302,6 +1992,9 @@ type DarkServer interface {
 	SignOrderFragment(context.Context, *SignOrderFragmentRequest) (*OrderFragmentSignature, error)
 	OpenOrder(context.Context, *OpenOrderRequest) (*Nothing, error)
 	CancelOrder(context.Context, *CancelOrderRequest) (*Nothing, error)
+	Notifications(*NotificationsRequest, Dark_NotificationsServer) error
+	RevealOrder(context.Context, *RevealOrderRequest) (*Nothing, error)
+	UpdateMidpoint(context.Context, *UpdateMidpointRequest) (*Nothing, error)
 	RandomFragmentShares(context.Context, *RandomFragmentSharesRequest) (*RandomFragments, error)
 	ResidueFragmentShares(context.Context, *ResidueFragmentSharesRequest) (*ResidueFragments, error)
 	ComputeResidueFragment(context.Context, *ComputeResidueFragmentRequest) (*Nothing, error)
@@ -1388,6 +2081,63 @@ func _Dark_CancelOrder_Handler(srv interface{}, ctx context.Context, dec func(in
 	return interceptor(ctx, in, info, handler)
 }
 
+func _Dark_Notifications_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(NotificationsRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(DarkServer).Notifications(m, &darkNotificationsServer{stream})
+}
+
+type Dark_NotificationsServer interface {
+	Send(*Notification) error
+	grpc.ServerStream
+}
+
+type darkNotificationsServer struct {
+	grpc.ServerStream
+}
+
+func (x *darkNotificationsServer) Send(m *Notification) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func _Dark_RevealOrder_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RevealOrderRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(DarkServer).RevealOrder(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/rpc.Dark/RevealOrder",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(DarkServer).RevealOrder(ctx, req.(*RevealOrderRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Dark_UpdateMidpoint_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UpdateMidpointRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(DarkServer).UpdateMidpoint(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/rpc.Dark/UpdateMidpoint",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(DarkServer).UpdateMidpoint(ctx, req.(*UpdateMidpointRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 func _Dark_RandomFragmentShares_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
 	in := new(RandomFragmentSharesRequest)
 	if err := dec(in); err != nil {
@@ -1494,6 +2244,14 @@ var _Dark_serviceDesc = grpc.ServiceDesc{
 			MethodName: "CancelOrder",
 			Handler:    _Dark_CancelOrder_Handler,
 		},
+		{
+			MethodName: "RevealOrder",
+			Handler:    _Dark_RevealOrder_Handler,
+		},
+		{
+			MethodName: "UpdateMidpoint",
+			Handler:    _Dark_UpdateMidpoint_Handler,
+		},
 		{
 			MethodName: "RandomFragmentShares",
 			Handler:    _Dark_RandomFragmentShares_Handler,
@@ -1521,6 +2279,11 @@ var _Dark_serviceDesc = grpc.ServiceDesc{
 			Handler:       _Dark_Sync_Handler,
 			ServerStreams: true,
 		},
+		{
+			StreamName:    "Notifications",
+			Handler:       _Dark_Notifications_Handler,
+			ServerStreams: true,
+		},
 	},
 	Metadata: "rpc.proto",
 }
@@ -1625,85 +2388,134 @@ var _Gossip_serviceDesc = grpc.ServiceDesc{
 func init() { proto.RegisterFile("rpc.proto", fileDescriptor0) }
 
 var fileDescriptor0 = []byte{
-	// 1278 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xac, 0x58, 0xcd, 0x6f, 0x23, 0x35,
-	0x14, 0xd7, 0xe4, 0xb3, 0x79, 0x49, 0xbf, 0xbc, 0x6d, 0x08, 0xd9, 0x16, 0x75, 0x0d, 0xac, 0x2a,
-	0x54, 0xa2, 0x12, 0x2a, 0xb4, 0x1c, 0xf8, 0x68, 0x1b, 0x2d, 0x2a, 0x12, 0xdb, 0xee, 0x74, 0x85,
-	0x10, 0xe2, 0xb0, 0xd3, 0x19, 0x37, 0x1d, 0x6d, 0x32, 0x13, 0x3c, 0x13, 0x41, 0x39, 0x71, 0xe1,
-	0x00, 0x5c, 0x38, 0xc1, 0x89, 0x7f, 0x02, 0x4e, 0xdc, 0xe0, 0x2f, 0xe0, 0x5f, 0x42, 0xb6, 0xe7,
-	0xc3, 0x9e, 0xf1, 0x74, 0x9a, 0xb2, 0xb7, 0xf8, 0xf9, 0xf7, 0x9e, 0x7f, 0xfe, 0x79, 0xfc, 0x9e,
-	0x5f, 0xa0, 0x45, 0x67, 0xf6, 0x60, 0x46, 0xfd, 0xd0, 0x47, 0x55, 0x3a, 0xb3, 0xf1, 0xeb, 0xd0,
-	0x3c, 0x74, 0x1c, 0x4a, 0x82, 0x00, 0xf5, 0xa0, 0x69, 0x89, 0x9f, 0x3d, 0x63, 0xc7, 0xd8, 0x6d,
-	0x99, 0xf1, 0x10, 0x9f, 0x41, 0xe7, 0xb3, 0xf9, 0x24, 0x74, 0x63, 0xe4, 0x16, 0xb4, 0x02, 0x77,
-	0xec, 0x59, 0xe1, 0x9c, 0x12, 0x8e, 0xed, 0x98, 0xa9, 0x01, 0x61, 0xe8, 0x4c, 0x25, 0x74, 0xaf,
-	0xc2, 0x83, 0x29, 0x36, 0xdc, 0x82, 0xe6, 0x13, 0x3f, 0xbc, 0x72, 0xbd, 0x31, 0x7e, 0x06, 0xf5,
-	0xa7, 0x73, 0x42, 0xaf, 0xd1, 0x9b, 0x50, 0xbb, 0xa4, 0xfe, 0x94, 0x07, 0x6c, 0x0f, 0xd7, 0x07,
-	0x8c, 0xa9, 0xbc, 0xac, 0xc9, 0xa7, 0xd1, 0x1b, 0xd0, 0x08, 0x2d, 0x3a, 0x26, 0x21, 0x0f, 0xdc,
-	0x1e, 0x76, 0x38, 0x30, 0xc6, 0x44, 0x73, 0xf8, 0x00, 0xda, 0xe7, 0xd7, 0x9e, 0x6d, 0x92, 0xaf,
-	0xe7, 0x24, 0x08, 0x6f, 0x19, 0x1b, 0xff, 0x6a, 0x40, 0xef, 0xdc, 0x1d, 0x7b, 0xa7, 0xd4, 0x21,
-	0xf4, 0x31, 0xb5, 0xc6, 0x53, 0xe2, 0x85, 0x8b, 0xc5, 0x40, 0xe7, 0xd0, 0xf5, 0x65, 0xf7, 0xf3,
-	0x44, 0x29, 0xc1, 0xf7, 0x3e, 0x77, 0x3c, 0xd5, 0x42, 0xcc, 0x02, 0x57, 0x1c, 0xc0, 0xda, 0xe9,
-	0x8c, 0x08, 0x5e, 0x0b, 0xf2, 0x79, 0x04, 0xcb, 0x4a, 0xd0, 0x88, 0x06, 0xca, 0xd3, 0x30, 0x55,
-	0x20, 0xfe, 0xd9, 0x00, 0x74, 0x6c, 0x79, 0x36, 0x99, 0xdc, 0x65, 0xdd, 0x03, 0xd8, 0xb4, 0xb9,
-	0xf3, 0xc4, 0x0a, 0x5d, 0xdf, 0x53, 0x65, 0xe8, 0x98, 0xfa, 0x49, 0xf6, 0x11, 0x72, 0x12, 0x27,
-	0x4e, 0xaf, 0xca, 0x71, 0xf1, 0x10, 0x8f, 0xe0, 0xbe, 0x69, 0x79, 0x8e, 0x3f, 0x4d, 0xd4, 0xb9,
-	0xb2, 0x28, 0x09, 0x16, 0x3c, 0xe1, 0x1f, 0x0c, 0xd8, 0x32, 0x49, 0xe0, 0x3a, 0x73, 0xf2, 0x7f,
-	0xe2, 0xa0, 0x0f, 0x61, 0x95, 0x2a, 0x6c, 0x82, 0x48, 0xd7, 0x0d, 0xee, 0xa1, 0x32, 0x0d, 0xcc,
-	0x2c, 0x18, 0xff, 0x68, 0xc0, 0xf6, 0xb1, 0x3f, 0x9d, 0xcd, 0x43, 0x92, 0xa1, 0xb3, 0x20, 0x91,
-	0x43, 0x58, 0xa3, 0x6a, 0x80, 0x98, 0xc9, 0xa6, 0x60, 0x92, 0x99, 0x34, 0x73, 0x70, 0xfc, 0x8b,
-	0x01, 0x0f, 0x8e, 0xa8, 0x6f, 0x39, 0xb6, 0x15, 0x84, 0x87, 0x93, 0xd9, 0x95, 0x75, 0x44, 0x42,
-	0xeb, 0x8e, 0x7c, 0x46, 0xb0, 0x6e, 0x65, 0x43, 0x44, 0x84, 0xba, 0xe2, 0xa6, 0xe6, 0x16, 0xc8,
-	0x3b, 0xe0, 0xef, 0x0d, 0xd8, 0x4e, 0x28, 0x8d, 0xc8, 0xe4, 0xce, 0x74, 0x1e, 0xc1, 0xb2, 0x23,
-	0xbb, 0x2b, 0x5f, 0xbf, 0x1a, 0x58, 0x05, 0xe2, 0x7f, 0x0c, 0x58, 0xcf, 0x71, 0x2d, 0x49, 0x7d,
-	0x5b, 0xd0, 0x8a, 0xd4, 0x3d, 0x71, 0xa2, 0xef, 0x3c, 0x35, 0x30, 0x2e, 0x7c, 0xa7, 0x09, 0x97,
-	0x6a, 0xf1, 0x4d, 0x54, 0x80, 0xe8, 0x3d, 0xe8, 0x5c, 0xc8, 0x7a, 0xd6, 0x0a, 0x1d, 0x15, 0x1c,
-	0xfe, 0xb3, 0x0a, 0xcb, 0xca, 0x26, 0x4b, 0xf8, 0xaf, 0x40, 0xc5, 0x8d, 0x89, 0x57, 0x5c, 0x87,
-	0xdd, 0x46, 0x2e, 0x4a, 0x7a, 0x1b, 0xa3, 0x21, 0x7a, 0x0d, 0xe0, 0x62, 0x7e, 0x7d, 0x1a, 0x5d,
-	0xd5, 0x1a, 0x9f, 0x94, 0x2c, 0x68, 0x07, 0xda, 0x01, 0x99, 0x4c, 0x62, 0x40, 0x9d, 0x03, 0x64,
-	0x13, 0x1a, 0x00, 0x8a, 0xf1, 0x31, 0xbb, 0x13, 0xa7, 0xd7, 0xe0, 0x40, 0xcd, 0x0c, 0xda, 0x87,
-	0x7b, 0x89, 0xbb, 0xe4, 0xd0, 0xe4, 0x0e, 0xba, 0x29, 0x56, 0x88, 0x2e, 0x83, 0xf0, 0xd8, 0x77,
-	0x08, 0xbf, 0xe2, 0xbd, 0x25, 0x0e, 0x55, 0x6c, 0x0c, 0x13, 0x78, 0x4e, 0x8a, 0x69, 0x09, 0x8c,
-	0x6c, 0x63, 0x7b, 0x9d, 0x51, 0xd7, 0x8e, 0x10, 0x20, 0xf6, 0x9a, 0x5a, 0xd0, 0x43, 0x58, 0x99,
-	0x5a, 0xdf, 0x7e, 0xee, 0x4f, 0xe6, 0xd3, 0x08, 0xd3, 0xe6, 0x98, 0x8c, 0x95, 0xe3, 0x5c, 0x4f,
-	0xc6, 0x75, 0x22, 0x9c, 0x62, 0xc5, 0xff, 0x56, 0x60, 0x59, 0xd9, 0xcb, 0xe2, 0xa7, 0xa6, 0xcf,
-	0xa1, 0x2c, 0x0e, 0xff, 0xf9, 0xec, 0x7a, 0x46, 0xf8, 0xa1, 0x55, 0xcd, 0xd4, 0xc0, 0xce, 0x8c,
-	0x0f, 0xce, 0x2c, 0xea, 0x86, 0xd7, 0xfc, 0xcc, 0xaa, 0xa6, 0x6c, 0xca, 0x29, 0xda, 0xb8, 0x85,
-	0xa2, 0xcd, 0x52, 0x45, 0x97, 0x6e, 0xa1, 0x68, 0xeb, 0x96, 0x8a, 0x82, 0x56, 0xd1, 0xe7, 0xd0,
-	0xd5, 0x17, 0xdc, 0x12, 0x65, 0x77, 0x61, 0xd5, 0xcf, 0x7c, 0x6f, 0x42, 0xe6, 0xac, 0x19, 0xff,
-	0x65, 0xc0, 0x6a, 0x26, 0xd5, 0x96, 0xc4, 0xee, 0x42, 0xc3, 0x12, 0x9c, 0x45, 0xc8, 0x68, 0xc4,
-	0xec, 0x17, 0xc2, 0x2e, 0x0e, 0x2f, 0x1a, 0x31, 0xbb, 0x2d, 0xec, 0xe2, 0xb6, 0x45, 0x23, 0xa6,
-	0x77, 0x94, 0x62, 0xc4, 0xac, 0xb8, 0x6a, 0x8a, 0x4d, 0xcd, 0x4b, 0x8d, 0x4c, 0x5e, 0xc2, 0x14,
-	0xd6, 0xb2, 0x55, 0xa2, 0x84, 0xfb, 0xc7, 0xda, 0xa2, 0x53, 0x4d, 0xcb, 0x5f, 0xa6, 0xa4, 0xe5,
-	0x6b, 0xce, 0x08, 0x56, 0xd4, 0x1a, 0x59, 0xb2, 0xe2, 0x06, 0xd4, 0x03, 0x49, 0x2c, 0x31, 0xc0,
-	0x1e, 0xac, 0x66, 0x2a, 0x6d, 0x49, 0x98, 0x0f, 0x74, 0x65, 0x9b, 0xf1, 0xbe, 0xa7, 0x29, 0xdb,
-	0xf9, 0xaa, 0xfd, 0x7b, 0x03, 0x5a, 0xec, 0x59, 0x79, 0x34, 0xf1, 0xed, 0x17, 0x25, 0x4b, 0xbd,
-	0x0f, 0xc0, 0x93, 0x25, 0xc7, 0x46, 0x65, 0xe7, 0x55, 0xbe, 0x4a, 0x12, 0x41, 0x14, 0x20, 0xfe,
-	0xd3, 0x94, 0xc0, 0xe8, 0xa3, 0xe4, 0x48, 0x85, 0x73, 0x55, 0x7a, 0x38, 0xa6, 0xce, 0xa6, 0x04,
-	0x31, 0x15, 0x87, 0xfe, 0x1f, 0x15, 0x80, 0x34, 0x36, 0xda, 0x83, 0xe6, 0x8c, 0x78, 0x8e, 0xeb,
-	0x8d, 0x7b, 0x06, 0xdf, 0xad, 0xae, 0xfc, 0xc5, 0x10, 0x34, 0x80, 0x25, 0x32, 0x21, 0x76, 0xc8,
-	0xe0, 0x95, 0x42, 0x78, 0x82, 0x41, 0xfb, 0xd0, 0xb2, 0xf9, 0x4b, 0x86, 0x39, 0x54, 0x0b, 0x1d,
-	0x52, 0x10, 0x1a, 0x02, 0x5c, 0xba, 0x9e, 0x35, 0x71, 0xbf, 0x63, 0x2e, 0xb5, 0x42, 0x17, 0x09,
-	0xc5, 0xf6, 0x30, 0xb5, 0x42, 0xfb, 0x8a, 0xb0, 0x62, 0x52, 0xb8, 0x87, 0x08, 0xc2, 0x56, 0x98,
-	0xba, 0x41, 0xec, 0xd0, 0x28, 0x5e, 0x21, 0x45, 0xf5, 0xff, 0xae, 0x40, 0x47, 0xd6, 0x14, 0x0d,
-	0xb2, 0xb2, 0xe9, 0x3f, 0xee, 0x44, 0xb8, 0xfd, 0x9c, 0x70, 0x7a, 0x87, 0x54, 0xba, 0x61, 0x5e,
-	0x3a, 0xbd, 0x8b, 0x24, 0xde, 0x81, 0x46, 0x3c, 0xbd, 0x93, 0x2c, 0xdf, 0x20, 0x2b, 0x5f, 0xc1,
-	0x5e, 0x62, 0x01, 0x0f, 0x34, 0x02, 0x16, 0xac, 0x92, 0xe2, 0xf0, 0x17, 0xb0, 0xfc, 0x89, 0x1f,
-	0x04, 0xee, 0x6c, 0xc1, 0x57, 0xda, 0x0e, 0xd4, 0xe9, 0x7c, 0xea, 0xd3, 0xe8, 0x9a, 0x80, 0x58,
-	0x88, 0x59, 0x4c, 0x31, 0x81, 0xbf, 0x84, 0xd5, 0xc7, 0x62, 0x37, 0xe4, 0xa5, 0xc7, 0x1e, 0x43,
-	0x9d, 0x8f, 0x4b, 0x2e, 0xb4, 0xfa, 0xe4, 0xa9, 0x94, 0x3d, 0x79, 0xaa, 0xb9, 0x27, 0xcf, 0xf0,
-	0x37, 0x03, 0xea, 0xe7, 0xdf, 0x58, 0x74, 0x8a, 0xf6, 0xa0, 0x76, 0xc6, 0x8e, 0x25, 0xcf, 0xba,
-	0x9f, 0x37, 0xa1, 0xb7, 0x01, 0x78, 0x8b, 0x7c, 0x46, 0x08, 0x0d, 0x90, 0xd8, 0x01, 0x37, 0x68,
-	0xc0, 0xfb, 0x06, 0x7a, 0x07, 0x56, 0x52, 0xf8, 0x88, 0x90, 0x59, 0xa9, 0xcb, 0xf0, 0xa7, 0x3a,
-	0xd4, 0x46, 0x16, 0x7d, 0x81, 0xde, 0x82, 0x1a, 0xcb, 0x30, 0x68, 0x2d, 0x49, 0x36, 0x91, 0xdc,
-	0xfd, 0x15, 0x35, 0xfd, 0xec, 0x1b, 0xe8, 0x14, 0xd6, 0x73, 0xcd, 0x32, 0xda, 0x16, 0xb0, 0x82,
-	0x26, 0xba, 0x7f, 0x53, 0xf7, 0xcb, 0x32, 0x49, 0xd2, 0xe5, 0x22, 0xd1, 0xbe, 0x64, 0xbb, 0xde,
-	0xbe, 0x68, 0xf7, 0xa3, 0x3f, 0x0f, 0xd0, 0x01, 0xb4, 0xa5, 0x0e, 0x15, 0xbd, 0xc2, 0x27, 0xf3,
-	0x3d, 0x6b, 0xc6, 0xeb, 0x09, 0x6c, 0xe8, 0x5a, 0x49, 0xb4, 0xa3, 0x29, 0x02, 0x4a, 0x77, 0xd8,
-	0xd7, 0x76, 0x77, 0xe8, 0x29, 0x6c, 0x6a, 0x7b, 0x4a, 0xf4, 0x40, 0x77, 0x63, 0xd4, 0x88, 0xfa,
-	0x2e, 0x0d, 0x7d, 0x0a, 0x5d, 0x7d, 0x7b, 0x88, 0xb0, 0xd8, 0xe3, 0x4d, 0xbd, 0x63, 0x66, 0xbb,
-	0x5f, 0x41, 0xbf, 0xb8, 0xbd, 0x43, 0x0f, 0x39, 0xb6, 0xb4, 0xff, 0xeb, 0x17, 0x74, 0x6f, 0xe8,
-	0x0c, 0xba, 0xfa, 0x4e, 0x2d, 0x62, 0x7a, 0x63, 0x1b, 0xd7, 0xd7, 0x24, 0xe5, 0xe1, 0x73, 0x68,
-	0x88, 0x2c, 0x82, 0x76, 0x93, 0x5f, 0x02, 0xa7, 0x24, 0x97, 0xbe, 0x74, 0x95, 0xd1, 0x1e, 0x2c,
-	0xc5, 0xf9, 0x01, 0x89, 0x43, 0xca, 0xa4, 0x0b, 0x19, 0x7d, 0xd1, 0xe0, 0xff, 0x80, 0xbd, 0xfb,
-	0x5f, 0x00, 0x00, 0x00, 0xff, 0xff, 0xef, 0xe0, 0xc7, 0x99, 0x0e, 0x13, 0x00, 0x00,
+	// 2053 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xa4, 0x59, 0xcd, 0x8f, 0xe4, 0x46,
+	0x15, 0x97, 0xdb, 0xdd, 0x3d, 0xd3, 0xaf, 0x3f, 0x66, 0xa6, 0x76, 0xb7, 0xe3, 0x74, 0x76, 0xa3,
+	0x4e, 0x25, 0x44, 0xa3, 0x68, 0x19, 0x0d, 0xcd, 0x80, 0x02, 0x08, 0xc2, 0xee, 0x6c, 0x16, 0x16,
+	0x69, 0x77, 0x36, 0x9e, 0x04, 0x71, 0xc8, 0x61, 0x3d, 0x76, 0x4d, 0x8f, 0xb5, 0xed, 0x0f, 0xca,
+	0x6e, 0xd8, 0x09, 0x1c, 0x38, 0x70, 0x41, 0x80, 0x84, 0x38, 0xc0, 0xff, 0xc1, 0x8d, 0x03, 0x12,
+	0x97, 0x5c, 0xb8, 0x22, 0x0e, 0xfc, 0x37, 0xa8, 0x3e, 0x6c, 0x57, 0xd9, 0xe5, 0x6d, 0x7a, 0x72,
+	0x73, 0xfd, 0xde, 0xef, 0x55, 0xbd, 0x7a, 0x55, 0xf5, 0xea, 0xbd, 0x32, 0x0c, 0x68, 0xea, 0x1f,
+	0xa5, 0x34, 0xc9, 0x13, 0x64, 0xd3, 0xd4, 0xc7, 0xef, 0xc2, 0xce, 0x83, 0x20, 0xa0, 0x24, 0xcb,
+	0x90, 0x03, 0x3b, 0x9e, 0xf8, 0x74, 0xac, 0xb9, 0x75, 0x38, 0x70, 0x8b, 0x26, 0xbe, 0x84, 0xd1,
+	0xd3, 0xf5, 0x2a, 0x0f, 0x0b, 0xe6, 0x5d, 0x18, 0x64, 0xe1, 0x32, 0xf6, 0xf2, 0x35, 0x25, 0x9c,
+	0x3b, 0x72, 0x2b, 0x00, 0x61, 0x18, 0x45, 0x0a, 0xdb, 0xe9, 0xf0, 0xce, 0x34, 0x0c, 0xdd, 0x86,
+	0xde, 0x65, 0x48, 0x56, 0x81, 0x63, 0x73, 0x6d, 0xd1, 0xc0, 0x03, 0xd8, 0x79, 0x96, 0xe4, 0x57,
+	0x61, 0xbc, 0xc4, 0x9f, 0x42, 0xef, 0x93, 0x35, 0xa1, 0xd7, 0xe8, 0x6b, 0xd0, 0xbd, 0xa4, 0x49,
+	0xc4, 0x87, 0x19, 0x2e, 0x0e, 0x8e, 0x98, 0xfd, 0xaa, 0x31, 0x2e, 0x17, 0xa3, 0xf7, 0xa0, 0x9f,
+	0x7b, 0x74, 0x49, 0x72, 0x3e, 0xdc, 0x70, 0x31, 0xe2, 0xc4, 0x82, 0x23, 0x65, 0xf8, 0x04, 0x86,
+	0xe7, 0xd7, 0xb1, 0xef, 0x92, 0x9f, 0xaf, 0x49, 0x96, 0xff, 0x9f, 0x7d, 0xe3, 0xbf, 0x58, 0xe0,
+	0x9c, 0x87, 0xcb, 0xf8, 0x8c, 0x06, 0x84, 0x3e, 0xa6, 0xde, 0x32, 0x22, 0x71, 0xbe, 0x5d, 0x1f,
+	0xe8, 0x1c, 0xa6, 0x89, 0xaa, 0x7e, 0x5e, 0xfa, 0x4f, 0xd8, 0xfb, 0x16, 0x57, 0x3c, 0x33, 0x52,
+	0xdc, 0x16, 0x55, 0xfc, 0x2f, 0x0b, 0xf6, 0xcf, 0x52, 0x22, 0x0c, 0xdb, 0xd2, 0xa0, 0x0f, 0x61,
+	0xac, 0xf5, 0x2a, 0xed, 0x40, 0x4d, 0x3b, 0x5c, 0x9d, 0x88, 0x3e, 0x83, 0x37, 0x48, 0xec, 0xd3,
+	0xeb, 0x34, 0x27, 0x81, 0x46, 0xcc, 0x1c, 0x7b, 0x6e, 0x97, 0x73, 0xf9, 0xd8, 0xc8, 0x71, 0xdb,
+	0x74, 0xf1, 0xef, 0x2d, 0x40, 0xa7, 0x5e, 0xec, 0x93, 0xd5, 0x4d, 0xa6, 0x73, 0x02, 0x77, 0x7c,
+	0xae, 0xbc, 0xf2, 0xf2, 0x30, 0x89, 0x75, 0xf7, 0x8e, 0x5c, 0xb3, 0x90, 0x6d, 0x79, 0x3e, 0xb7,
+	0x27, 0xc5, 0x46, 0x2c, 0x9a, 0xf8, 0xb7, 0x16, 0xbc, 0xe5, 0x7a, 0x71, 0x90, 0x44, 0xa5, 0xdb,
+	0xaf, 0x3c, 0x4a, 0xb2, 0x2d, 0xcd, 0xfa, 0x01, 0xec, 0x51, 0xad, 0x97, 0x4c, 0xfa, 0xf9, 0x36,
+	0xd7, 0xd0, 0x47, 0xc8, 0xdc, 0x3a, 0x19, 0x13, 0xb8, 0xeb, 0x92, 0x2c, 0x0c, 0xd6, 0xe4, 0x2b,
+	0x99, 0xf1, 0x36, 0x00, 0x15, 0xdd, 0x3c, 0x09, 0x98, 0x05, 0xf6, 0xe1, 0xc8, 0x55, 0x10, 0xfc,
+	0x3b, 0x0b, 0xee, 0x9d, 0x26, 0x51, 0xba, 0xce, 0x49, 0x6d, 0xb8, 0x2d, 0x07, 0x7a, 0x00, 0xfb,
+	0x54, 0xef, 0xa0, 0x98, 0xf0, 0x1d, 0x31, 0xe1, 0x9a, 0xd0, 0x6d, 0xd0, 0xf1, 0x9f, 0x2c, 0x78,
+	0xe7, 0x21, 0x4d, 0xbc, 0xc0, 0xf7, 0xb2, 0xfc, 0xc1, 0x2a, 0xbd, 0xf2, 0x1e, 0x92, 0xdc, 0xbb,
+	0xa1, 0x3d, 0x8f, 0xe0, 0xc0, 0xab, 0x77, 0x21, 0x0d, 0x9a, 0x8a, 0x08, 0xd1, 0x18, 0xa0, 0xa9,
+	0x80, 0x7f, 0x63, 0xc1, 0xbd, 0xd2, 0xa4, 0x47, 0x64, 0x75, 0x63, 0x73, 0x3e, 0x84, 0x71, 0x40,
+	0x56, 0x0d, 0x53, 0xc4, 0xa1, 0xd3, 0x3b, 0xd6, 0x89, 0xf8, 0x8f, 0x16, 0x1c, 0x34, 0x6c, 0xdd,
+	0x10, 0x88, 0xef, 0xc2, 0xa0, 0x5c, 0x63, 0x79, 0x0e, 0x2a, 0x80, 0xed, 0x09, 0x3e, 0x53, 0xbe,
+	0xa1, 0xe4, 0xf6, 0x57, 0x10, 0xa6, 0x7d, 0x41, 0x72, 0x29, 0xee, 0x0a, 0xed, 0x12, 0xc0, 0x5f,
+	0x76, 0x60, 0xac, 0x19, 0xbc, 0xc1, 0x96, 0x09, 0x74, 0xc2, 0xc2, 0x88, 0x4e, 0x18, 0xb0, 0x93,
+	0xc7, 0x27, 0x58, 0x9d, 0x3c, 0xd9, 0x64, 0x76, 0x5d, 0xac, 0xaf, 0xcf, 0xe4, 0xb1, 0x14, 0x03,
+	0x2b, 0x08, 0x9a, 0xc3, 0x30, 0x23, 0xab, 0x55, 0x41, 0xe8, 0x71, 0x82, 0x0a, 0xa1, 0x23, 0x40,
+	0x05, 0xbf, 0xb0, 0xee, 0x49, 0xe0, 0xf4, 0x39, 0xd1, 0x20, 0x41, 0xc7, 0x70, 0xab, 0x54, 0x57,
+	0x14, 0x76, 0xb8, 0x82, 0x49, 0xc4, 0x6c, 0x8c, 0xbc, 0xdc, 0xbf, 0x12, 0xce, 0xd9, 0x15, 0x36,
+	0x56, 0x08, 0x3a, 0x84, 0x3d, 0x3f, 0x89, 0xa2, 0x30, 0xe7, 0x5b, 0xfa, 0xc7, 0x5e, 0x76, 0xe5,
+	0x0c, 0x38, 0xa9, 0x0e, 0xe3, 0x2f, 0xbb, 0x30, 0xd6, 0x7a, 0xdf, 0xde, 0x8f, 0xe6, 0x08, 0xc6,
+	0xfa, 0xe1, 0x9f, 0x9f, 0x5e, 0xa7, 0x62, 0xfd, 0x6c, 0xb7, 0x02, 0x98, 0x17, 0x79, 0xe3, 0xb9,
+	0x47, 0xc3, 0xfc, 0x9a, 0x7b, 0xd1, 0x76, 0x55, 0x88, 0x5d, 0xe3, 0x97, 0x59, 0x7e, 0x9a, 0x04,
+	0x44, 0xcc, 0x52, 0xf8, 0x4f, 0xc3, 0x18, 0x27, 0x8b, 0x83, 0x8a, 0x23, 0x5c, 0xa6, 0x61, 0xcc,
+	0x57, 0x29, 0x0d, 0x7d, 0xa2, 0xf9, 0xaa, 0x42, 0xd0, 0xfb, 0x30, 0x89, 0xbc, 0x57, 0x3f, 0x4d,
+	0x56, 0xeb, 0x48, 0x72, 0x84, 0xab, 0x6a, 0x28, 0xe7, 0x85, 0xb1, 0xca, 0x03, 0xc9, 0xd3, 0xd0,
+	0x72, 0x66, 0x1f, 0xbf, 0x4a, 0x43, 0x7a, 0xed, 0x0c, 0x95, 0x99, 0x09, 0x08, 0x4d, 0xa1, 0x9f,
+	0x53, 0x2f, 0x20, 0xd4, 0x19, 0xf1, 0x1e, 0x64, 0x0b, 0x2d, 0x60, 0xa8, 0x2c, 0x8f, 0x33, 0xe6,
+	0x67, 0x73, 0x9f, 0x9f, 0xcd, 0xd3, 0x0a, 0x77, 0x55, 0x52, 0x95, 0xc8, 0x4c, 0x94, 0x44, 0x06,
+	0x7d, 0x00, 0xfb, 0xc2, 0xd5, 0x61, 0x44, 0x9e, 0xc4, 0x8f, 0x13, 0xea, 0x13, 0x67, 0x8f, 0x1b,
+	0xd2, 0xc0, 0x99, 0x7f, 0x38, 0xf6, 0x2c, 0x89, 0x7d, 0xe2, 0xec, 0x0b, 0xff, 0x54, 0x08, 0x7a,
+	0x4f, 0x5e, 0xd4, 0xec, 0xa2, 0x67, 0x7a, 0xce, 0x01, 0xef, 0x48, 0x07, 0xf1, 0x0b, 0x98, 0x9a,
+	0x93, 0x87, 0x0d, 0xfb, 0xe9, 0x10, 0xf6, 0x92, 0xda, 0xbe, 0x17, 0x9b, 0xab, 0x0e, 0xe3, 0xbf,
+	0x5b, 0xb0, 0x57, 0x0b, 0xdf, 0x1b, 0xfa, 0x9e, 0x42, 0x5f, 0x86, 0x0f, 0xd1, 0xa5, 0x6c, 0x31,
+	0xfc, 0x42, 0x8d, 0x3a, 0xfd, 0x8b, 0x12, 0xf7, 0xd5, 0x70, 0xd3, 0xf7, 0xcb, 0x5d, 0x26, 0xc3,
+	0x96, 0x90, 0x8a, 0x23, 0xaf, 0x61, 0x7a, 0xac, 0xeb, 0xd7, 0x62, 0x1d, 0xa6, 0xb0, 0x5f, 0xbf,
+	0x79, 0x36, 0xd8, 0xfe, 0x43, 0xe3, 0x45, 0x66, 0x57, 0x37, 0xb7, 0x2e, 0x34, 0xdc, 0x63, 0xbf,
+	0x86, 0x89, 0x7e, 0xbd, 0x7f, 0xa5, 0x68, 0x5d, 0xf9, 0xd2, 0x6e, 0xf1, 0x65, 0x57, 0xf5, 0x25,
+	0x8e, 0x61, 0x4f, 0x1f, 0x7d, 0xd3, 0x84, 0xbf, 0x6f, 0xca, 0x54, 0xd8, 0x7c, 0x6f, 0x19, 0x32,
+	0x95, 0x66, 0xa2, 0xf2, 0xdf, 0x1d, 0x18, 0xb0, 0xd4, 0xfa, 0xe1, 0x2a, 0xf1, 0x5f, 0x6e, 0x18,
+	0xea, 0x3b, 0x00, 0x3c, 0xd8, 0x73, 0xae, 0xbc, 0x02, 0xdf, 0xe4, 0xa3, 0x94, 0x3d, 0x88, 0xcb,
+	0x90, 0x7f, 0xba, 0x0a, 0x19, 0x7d, 0x54, 0x6e, 0x05, 0xa1, 0x6c, 0x2b, 0xc9, 0x73, 0xa5, 0xec,
+	0x2a, 0x14, 0x57, 0x53, 0x40, 0xa7, 0x30, 0x49, 0xf4, 0x9c, 0xb5, 0xbb, 0x39, 0x67, 0xad, 0xa9,
+	0xa0, 0x07, 0x30, 0xbe, 0x0c, 0x63, 0x6f, 0x15, 0x7e, 0xc1, 0xf3, 0xc9, 0xcc, 0xe9, 0xcd, 0x6d,
+	0x83, 0x19, 0x8f, 0x15, 0x8e, 0xab, 0x6b, 0xcc, 0xfe, 0xd6, 0x01, 0xa8, 0xe6, 0x88, 0xee, 0xc3,
+	0x4e, 0x4a, 0xe2, 0x20, 0x8c, 0x97, 0x8e, 0x35, 0xb7, 0x5b, 0x52, 0x82, 0x82, 0x82, 0x8e, 0x60,
+	0x97, 0xac, 0x88, 0x9f, 0x33, 0x7a, 0xa7, 0x95, 0x5e, 0x72, 0xd0, 0x31, 0x0c, 0x7c, 0x9e, 0xdd,
+	0x31, 0x05, 0xbb, 0x55, 0xa1, 0x22, 0xa1, 0x05, 0x80, 0xb4, 0x97, 0xa9, 0x74, 0x5b, 0x55, 0x14,
+	0x16, 0x9b, 0x03, 0xbf, 0x02, 0x49, 0xe0, 0xf4, 0x5a, 0x15, 0x0a, 0x0a, 0x1b, 0x21, 0x0a, 0xb3,
+	0x42, 0xa1, 0xdf, 0x3e, 0x42, 0xc5, 0x9a, 0xfd, 0xb3, 0x03, 0x23, 0x75, 0x6d, 0xd1, 0x51, 0xdd,
+	0x6d, 0xe6, 0xc3, 0x59, 0x3a, 0xee, 0xb8, 0xe1, 0x38, 0xb3, 0x42, 0xe5, 0xba, 0x45, 0xd3, 0x75,
+	0x66, 0x15, 0xc5, 0x79, 0x27, 0x06, 0xe7, 0x99, 0x95, 0x54, 0xf7, 0x1d, 0xd5, 0xdd, 0xd7, 0x32,
+	0x97, 0xc2, 0x81, 0x27, 0x06, 0x07, 0xb6, 0x8c, 0xa2, 0xb8, 0x70, 0x01, 0x23, 0x75, 0x5b, 0x22,
+	0x0c, 0x7d, 0xba, 0x8e, 0x12, 0x9a, 0x49, 0x07, 0x82, 0xe8, 0x81, 0x41, 0xae, 0x94, 0xe0, 0x9f,
+	0xc1, 0xf8, 0x47, 0x49, 0x96, 0x85, 0xe9, 0x96, 0xd9, 0xee, 0x1c, 0x7a, 0xbc, 0x07, 0x79, 0xc4,
+	0xd5, 0xae, 0x85, 0x00, 0x7f, 0x0e, 0x7b, 0xd2, 0x1a, 0xb2, 0x65, 0xdf, 0x95, 0xdd, 0x9d, 0x56,
+	0xbb, 0x97, 0xd0, 0xe3, 0xc0, 0x86, 0x70, 0xa4, 0x27, 0x9c, 0x9d, 0x4d, 0x09, 0xa7, 0xdd, 0x48,
+	0x38, 0xf1, 0x7f, 0xba, 0x30, 0x54, 0x32, 0x04, 0x96, 0x94, 0xc9, 0x34, 0x89, 0x7b, 0x75, 0xe4,
+	0x16, 0x4d, 0x26, 0x91, 0xc9, 0x91, 0xac, 0xc2, 0x8a, 0x26, 0x4b, 0x24, 0x78, 0x52, 0xc4, 0x37,
+	0xd9, 0xc8, 0x15, 0x0d, 0x66, 0x79, 0x99, 0x06, 0xf1, 0x9d, 0x34, 0x72, 0x2b, 0x80, 0x4b, 0x8b,
+	0xe4, 0xc7, 0xe9, 0x49, 0x69, 0x01, 0xb0, 0xab, 0x5d, 0x0e, 0xfb, 0x70, 0x15, 0x8a, 0x43, 0x22,
+	0x2e, 0xc6, 0x3a, 0xcc, 0x98, 0xd2, 0x8c, 0x92, 0x29, 0x32, 0xb9, 0x3a, 0xcc, 0x92, 0x11, 0x6e,
+	0x58, 0xc9, 0x13, 0xf9, 0x9c, 0x0e, 0xa2, 0xfb, 0x70, 0x50, 0x1a, 0x59, 0x32, 0x45, 0x56, 0xd7,
+	0x14, 0x70, 0x76, 0x18, 0xeb, 0xa0, 0xcc, 0xed, 0x9a, 0x02, 0x91, 0x2e, 0xd2, 0x97, 0x24, 0x7f,
+	0x2c, 0x26, 0x91, 0x39, 0xc3, 0xb9, 0x7d, 0x68, 0xbb, 0x35, 0xb4, 0xe2, 0x9d, 0x8b, 0x29, 0x64,
+	0xce, 0x48, 0xe5, 0x15, 0x28, 0x4b, 0xd5, 0x04, 0x72, 0x7a, 0xe5, 0xad, 0x56, 0x24, 0x5e, 0x12,
+	0x96, 0xf9, 0x31, 0x57, 0x36, 0x70, 0xf4, 0x6d, 0x98, 0x6a, 0xa3, 0xb8, 0x24, 0x4b, 0x93, 0x38,
+	0x23, 0x99, 0x33, 0xe1, 0x1a, 0x2d, 0xd2, 0x4a, 0x4f, 0x8e, 0x5a, 0xe9, 0xed, 0xa9, 0x7a, 0x75,
+	0x29, 0xce, 0xe1, 0xf6, 0xb3, 0x24, 0x0f, 0x2f, 0x43, 0x5f, 0xdc, 0x1a, 0x5b, 0x9e, 0x91, 0x6f,
+	0xc1, 0x28, 0x5b, 0x5f, 0x64, 0x3e, 0x0d, 0x53, 0xa6, 0xee, 0x74, 0x14, 0xfa, 0xb9, 0x22, 0x70,
+	0x35, 0x1a, 0xbe, 0x80, 0x91, 0x2a, 0xdd, 0x9c, 0xe4, 0xc9, 0x64, 0xba, 0xa3, 0x25, 0xd3, 0x77,
+	0x61, 0x90, 0x87, 0x11, 0xc9, 0x72, 0x2f, 0x4a, 0xf9, 0x99, 0xb1, 0xdd, 0x0a, 0xc0, 0x7f, 0xb0,
+	0x60, 0xa4, 0x4e, 0x6d, 0xc3, 0x20, 0x08, 0xba, 0x39, 0x2b, 0x63, 0x3a, 0xbc, 0x1f, 0xfe, 0xfd,
+	0x9a, 0xca, 0xe7, 0x18, 0x6e, 0xf9, 0xc9, 0x3a, 0xce, 0x09, 0x4d, 0x3d, 0x9a, 0xd7, 0x4a, 0x49,
+	0x93, 0x08, 0xbf, 0x00, 0xe4, 0x92, 0x5f, 0x10, 0xef, 0x46, 0x4f, 0x4f, 0xef, 0x42, 0x9f, 0x72,
+	0x65, 0xe9, 0xe0, 0xa1, 0x0c, 0xc2, 0x0c, 0x72, 0xa5, 0x08, 0x7f, 0x01, 0x7d, 0x81, 0xdc, 0xd0,
+	0x9d, 0x73, 0xe8, 0xf1, 0xe9, 0xc9, 0x8c, 0x07, 0xaa, 0x67, 0x3a, 0x57, 0x08, 0xf8, 0xf3, 0xad,
+	0xcf, 0x27, 0x27, 0x67, 0x5a, 0x34, 0xf1, 0xbf, 0x3b, 0xd0, 0xe3, 0xd4, 0x2d, 0x6b, 0xcb, 0xc2,
+	0xeb, 0xb6, 0xe2, 0xf5, 0x29, 0xf4, 0x53, 0x51, 0x32, 0x8a, 0x92, 0x52, 0xb6, 0x18, 0x4e, 0x44,
+	0xc1, 0x25, 0x4a, 0x49, 0xd9, 0x62, 0xc1, 0x33, 0x57, 0x8a, 0xa0, 0x3e, 0x17, 0xaa, 0x90, 0x1a,
+	0x2c, 0x77, 0xb8, 0xd4, 0x14, 0x2c, 0x77, 0x85, 0xa4, 0x11, 0x2c, 0x45, 0x50, 0x31, 0x05, 0x4b,
+	0x11, 0x40, 0xda, 0x82, 0xe5, 0x50, 0x4a, 0x0b, 0x80, 0xf5, 0x18, 0xf3, 0x02, 0x4c, 0x94, 0x84,
+	0xa2, 0x81, 0x66, 0xb0, 0x9b, 0x14, 0x65, 0xd7, 0x98, 0x9b, 0x50, 0xb6, 0xd9, 0x0b, 0xe1, 0xd4,
+	0x9c, 0x2f, 0x32, 0x47, 0xe6, 0x89, 0xf4, 0x6f, 0x27, 0x4f, 0xd4, 0xad, 0xda, 0xd1, 0xb7, 0x6a,
+	0xad, 0x58, 0xb5, 0x9b, 0xc5, 0xea, 0xdb, 0x00, 0x7e, 0x98, 0x5e, 0x11, 0x9a, 0x93, 0x57, 0xc5,
+	0xca, 0x2a, 0x08, 0xfe, 0x15, 0xdc, 0xf9, 0x2c, 0x0d, 0xbc, 0x9c, 0x3c, 0x0d, 0x83, 0x34, 0x09,
+	0xb7, 0x7e, 0x92, 0xfa, 0x1e, 0x2b, 0xab, 0x85, 0xa6, 0xe8, 0x47, 0xee, 0x62, 0x91, 0xf6, 0x3f,
+	0xd5, 0x44, 0x6e, 0x8d, 0x8a, 0xff, 0x6c, 0xc1, 0x44, 0xa7, 0x6c, 0xd8, 0x62, 0xca, 0x62, 0x77,
+	0x5a, 0x17, 0xdb, 0xd6, 0x17, 0x7b, 0x06, 0xbb, 0xc5, 0xb0, 0x72, 0xfe, 0x65, 0x9b, 0x6f, 0xd1,
+	0x30, 0x22, 0x72, 0xd3, 0xf1, 0xef, 0xc5, 0x5f, 0x2d, 0xe8, 0x9d, 0xff, 0xd2, 0xa3, 0x11, 0xba,
+	0x0f, 0xdd, 0xe7, 0xec, 0xce, 0x68, 0x4e, 0x7e, 0xd6, 0x84, 0xd0, 0xd7, 0x01, 0xf8, 0x2f, 0x87,
+	0xe7, 0x84, 0xd0, 0x0c, 0x89, 0x13, 0xc6, 0x01, 0x03, 0xf9, 0xd8, 0x42, 0xdf, 0x80, 0x49, 0x45,
+	0x7f, 0x44, 0x48, 0xba, 0x51, 0x65, 0xf1, 0x8f, 0x3e, 0x74, 0x1f, 0x79, 0xf4, 0x25, 0xfa, 0x00,
+	0xba, 0xac, 0x4c, 0x40, 0xfb, 0x65, 0xc5, 0x20, 0x57, 0x6d, 0x36, 0xd1, 0x6b, 0x88, 0x63, 0x0b,
+	0x9d, 0xc1, 0x41, 0xe3, 0xe7, 0x03, 0xba, 0x27, 0x68, 0x2d, 0x3f, 0x25, 0x66, 0xaf, 0xfb, 0x9b,
+	0xc0, 0xaa, 0x81, 0xf2, 0xa7, 0x01, 0x12, 0xcf, 0xb2, 0xf5, 0x9f, 0x08, 0x33, 0xf1, 0xfb, 0x44,
+	0xfe, 0x8c, 0x41, 0x27, 0x30, 0x54, 0x5e, 0xe6, 0xd1, 0x1b, 0x5c, 0xd8, 0x7c, 0xab, 0xaf, 0x69,
+	0x7d, 0x04, 0x63, 0xed, 0xf6, 0x42, 0x6f, 0x16, 0xe2, 0xc6, 0x8d, 0x36, 0x3b, 0x68, 0x88, 0x8e,
+	0x2d, 0x36, 0xac, 0x12, 0x95, 0xe5, 0xb0, 0xcd, 0x38, 0x5d, 0x1b, 0xf6, 0xbb, 0x30, 0xd1, 0x0f,
+	0x04, 0x9a, 0x71, 0xb9, 0xf1, 0x94, 0xd4, 0x74, 0x9f, 0xc1, 0x6d, 0xd3, 0xa3, 0x3f, 0x9a, 0x1b,
+	0x6a, 0x60, 0xed, 0x21, 0x7e, 0x66, 0x7c, 0xcf, 0x47, 0x9f, 0xc0, 0x1d, 0xe3, 0xf3, 0x3d, 0x7a,
+	0xc7, 0x94, 0xa8, 0xeb, 0x3d, 0x9a, 0x1f, 0xcc, 0xd1, 0x4f, 0x60, 0x6a, 0x7e, 0xa9, 0x47, 0xb8,
+	0x78, 0xa9, 0x6a, 0x7f, 0xc6, 0xaf, 0x4d, 0xf7, 0x73, 0x98, 0xb5, 0xbf, 0xb4, 0xa3, 0xf7, 0x39,
+	0x77, 0xe3, 0x53, 0xfc, 0xac, 0xe5, 0x21, 0x1d, 0x3d, 0x87, 0xa9, 0xf9, 0xd1, 0x5c, 0x5a, 0xfa,
+	0xda, 0x17, 0xf5, 0x99, 0xa1, 0x16, 0x5c, 0xbc, 0x80, 0xbe, 0x28, 0x44, 0xd0, 0x61, 0xf9, 0x25,
+	0x78, 0x5a, 0x7d, 0x32, 0x53, 0x8a, 0x01, 0x74, 0x1f, 0x76, 0x8b, 0x12, 0x03, 0x89, 0x45, 0xaa,
+	0x55, 0x1c, 0x2a, 0xfb, 0xa2, 0xcf, 0x7f, 0x8d, 0x7e, 0xf3, 0x7f, 0x03, 0x00, 0xa2, 0x51, 0x4d,
+	0xaa, 0x27, 0x1d, 0x00, 0x00,
 }
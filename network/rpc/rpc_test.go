@@ -3,10 +3,13 @@ package rpc_test
 import (
 	"time"
 
+	"github.com/golang/protobuf/proto"
+
 	"github.com/republicprotocol/republic-go/order"
 
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
+	"github.com/republicprotocol/republic-go/compute"
 	"github.com/republicprotocol/republic-go/identity"
 	"github.com/republicprotocol/republic-go/network/rpc"
 	"github.com/republicprotocol/republic-go/shamir"
@@ -73,6 +76,19 @@ var _ = Describe("Data serialization and deserialization", func() {
 			newMultiAddress := rpc.SerializeMultiAddress(deserializedMulti, nil)
 			Ω(newMultiAddress).Should(Equal(rpcMultiAddress))
 		})
+
+		It("should verify the field of identity.MultiAddress", func() {
+			defaultField, err := shamir.FieldByName(shamir.FieldDefault)
+			Ω(err).ShouldNot(HaveOccurred())
+			testField, err := shamir.FieldByName(shamir.FieldTest)
+			Ω(err).ShouldNot(HaveOccurred())
+
+			rpcMultiAddress := &rpc.MultiAddress{MultiAddress: multiAddressString, Field: testField.ID}
+			Ω(rpc.VerifyField(testField.ID, rpcMultiAddress)).ShouldNot(HaveOccurred())
+			Ω(rpc.VerifyField(nil, rpcMultiAddress)).ShouldNot(HaveOccurred())
+			Ω(rpc.VerifyField(defaultField.ID, rpcMultiAddress)).Should(Equal(shamir.NewFieldMismatchError(defaultField.ID, testField.ID)))
+			Ω(rpc.VerifyField(defaultField.ID, &rpc.MultiAddress{MultiAddress: multiAddressString})).ShouldNot(HaveOccurred())
+		})
 	})
 
 	Context("identity.MultiAddresses", func() {
@@ -125,17 +141,86 @@ var _ = Describe("Data serialization and deserialization", func() {
 
 			prime, _ := stackint.FromString("179769313486231590772930519078902473361797697894230657273430081157732675805500963132708477322407536021120113879871393357658789768814416622492847430639474124377767893424865485276302219601246094119453082952085005768838150682342462881473913110540827237163350510684586298239947245938479716304835356329624224137111")
 
-			fragments, err := order.NewOrder(order.TypeLimit, order.ParityBuy, time.Now().Add(time.Hour), order.CurrencyCodeBTC, order.CurrencyCodeETH, &price, &maxVolume, &minVolume, &nonce).Split(2, 1, &prime)
+			// Expiries are serialized with a precision of one second
+			expiry := time.Now().Add(time.Hour).Truncate(time.Second)
+			fragments, err := order.NewOrder(order.TypeLimit, order.ParityBuy, expiry, order.CurrencyCodeBTC, order.CurrencyCodeETH, &price, &maxVolume, &minVolume, &nonce).WithTimeInForce(order.TimeInForceIOC).WithOpenTime(expiry.Add(-time.Hour)).Split(2, 1, &prime)
 			Ω(err).ShouldNot(HaveOccurred())
+			trader, err := identity.NewKeyPair()
+			Ω(err).ShouldNot(HaveOccurred())
+			Ω(order.SignFragments(trader, fragments)).ShouldNot(HaveOccurred())
 
 			for _, orderFragment := range fragments {
 				rpcOrderFragment := rpc.SerializeOrderFragment(orderFragment)
 				newOrderFragment, err := rpc.DeserializeOrderFragment(rpcOrderFragment)
 				Ω(err).ShouldNot(HaveOccurred())
 				Ω(*orderFragment).Should(Equal(*newOrderFragment))
+				Ω(newOrderFragment.Verify()).ShouldNot(HaveOccurred())
 			}
 		})
 
+		It("should be able to serialize and deserialize verifiable compute.OrderFragment", func() {
+			price := stackint.FromUint(10)
+			maxVolume := stackint.FromUint(1000)
+			minVolume := stackint.FromUint(100)
+			nonce := stackint.Zero()
+
+			prime, _ := stackint.FromString("179769313486231590772930519078902473361797697894230657273430081157732675805500963132708477322407536021120113879871393357658789768814416622492847430639474124377767893424865485276302219601246094119453082952085005768838150682342462881473913110540827237163350510684586298239947245938479716304835356329624224137111")
+			vss, err := shamir.NewVSS(&prime)
+			Ω(err).ShouldNot(HaveOccurred())
+
+			expiry := time.Now().Add(time.Hour).Truncate(time.Second)
+			fragments, err := order.NewOrder(order.TypeLimit, order.ParityBuy, expiry, order.CurrencyCodeBTC, order.CurrencyCodeETH, &price, &maxVolume, &minVolume, &nonce).SplitVerifiable(3, 2, vss, [][2]order.CurrencyCode{{order.CurrencyCodeETH, order.CurrencyCodeBTC}, {order.CurrencyCodeBTC, order.CurrencyCodeETH}})
+			Ω(err).ShouldNot(HaveOccurred())
+			trader, err := identity.NewKeyPair()
+			Ω(err).ShouldNot(HaveOccurred())
+			Ω(order.SignFragments(trader, fragments)).ShouldNot(HaveOccurred())
+
+			for _, orderFragment := range fragments {
+				newOrderFragment, err := rpc.DeserializeOrderFragment(rpc.SerializeOrderFragment(orderFragment))
+				Ω(err).ShouldNot(HaveOccurred())
+				Ω(newOrderFragment.Equal(orderFragment)).Should(BeTrue())
+				Ω(newOrderFragment.Verify()).ShouldNot(HaveOccurred())
+				Ω(newOrderFragment.VerifyCommitments(vss, 2)).ShouldNot(HaveOccurred())
+				Ω(newOrderFragment.VerifyMarket(vss)).ShouldNot(HaveOccurred())
+			}
+
+			// The proof that the order is in one of the markets survives the
+			// wire encoding
+			data, err := proto.Marshal(rpc.SerializeOrderFragment(fragments[0]))
+			Ω(err).ShouldNot(HaveOccurred())
+			serialized := &rpc.OrderFragment{}
+			Ω(proto.Unmarshal(data, serialized)).ShouldNot(HaveOccurred())
+			newOrderFragment, err := rpc.DeserializeOrderFragment(serialized)
+			Ω(err).ShouldNot(HaveOccurred())
+			Ω(newOrderFragment.Commitments.Markets).Should(Equal(fragments[0].Commitments.Markets))
+			Ω(newOrderFragment.Verify()).ShouldNot(HaveOccurred())
+			Ω(newOrderFragment.VerifyMarket(vss)).ShouldNot(HaveOccurred())
+		})
+
+		It("should return an error when deserializing an compute.OrderFragment with a malformed market proof", func() {
+			price := stackint.FromUint(10)
+			maxVolume := stackint.FromUint(1000)
+			minVolume := stackint.FromUint(100)
+			nonce := stackint.Zero()
+
+			prime, _ := stackint.FromString("179769313486231590772930519078902473361797697894230657273430081157732675805500963132708477322407536021120113879871393357658789768814416622492847430639474124377767893424865485276302219601246094119453082952085005768838150682342462881473913110540827237163350510684586298239947245938479716304835356329624224137111")
+			vss, err := shamir.NewVSS(&prime)
+			Ω(err).ShouldNot(HaveOccurred())
+
+			fragments, err := order.NewOrder(order.TypeLimit, order.ParityBuy, time.Now().Add(time.Hour), order.CurrencyCodeBTC, order.CurrencyCodeETH, &price, &maxVolume, &minVolume, &nonce).SplitVerifiable(3, 2, vss, [][2]order.CurrencyCode{{order.CurrencyCodeBTC, order.CurrencyCodeETH}})
+			Ω(err).ShouldNot(HaveOccurred())
+			serialized := rpc.SerializeOrderFragment(fragments[0])
+			serialized.Commitments.MarketSndCodeResponses = nil
+			_, err = rpc.DeserializeOrderFragment(serialized)
+			Ω(err).Should(Equal(rpc.ErrMalformedMarketProof))
+		})
+
+		It("should return an error when deserializing an compute.OrderFragment with malformed Commitments", func() {
+			wrongOrderFragment.Commitments = &rpc.Commitments{}
+			_, err := rpc.DeserializeOrderFragment(&wrongOrderFragment)
+			Ω(err).Should(HaveOccurred())
+		})
+
 		It("should return an error when deserializing an compute.OrderFragment with a malformed FstCodeShare", func() {
 			wrongOrderFragment.FstCodeShare = []byte("")
 			_, err := rpc.DeserializeOrderFragment(&wrongOrderFragment)
@@ -186,7 +271,146 @@ var _ = Describe("Data serialization and deserialization", func() {
 	//})
 	Context("delta fragment", func() {
 		It("should be able to serialize and deserialize compute.DeltaFragment", func() {
+			deltaFragment := &compute.DeltaFragment{
+				ID:                  compute.DeltaFragmentID("id"),
+				DeltaID:             compute.DeltaID("deltaID"),
+				BuyOrderID:          order.ID("buyOrderID"),
+				SellOrderID:         order.ID("sellOrderID"),
+				BuyOrderFragmentID:  order.FragmentID("buyOrderFragmentID"),
+				SellOrderFragmentID: order.FragmentID("sellOrderFragmentID"),
+				CommitmentsHash:     []byte("commitmentsHash"),
+				MatchShare:          shamir.Share{Key: 1, Value: stackint.One()},
+			}
+			keyPair, err := identity.NewKeyPair()
+			Ω(err).ShouldNot(HaveOccurred())
+			Ω(deltaFragment.Sign(keyPair)).ShouldNot(HaveOccurred())
+			newDeltaFragment, err := rpc.DeserializeDeltaFragment(rpc.SerializeDeltaFragment(deltaFragment))
+			Ω(err).ShouldNot(HaveOccurred())
+			Ω(newDeltaFragment.Equals(deltaFragment)).Should(BeTrue())
+			Ω(newDeltaFragment.VerifySignature(keyPair.ID())).ShouldNot(HaveOccurred())
+		})
+
+		It("should return an error when deserializing a malformed MatchShare", func() {
+			serializedDeltaFragment := &rpc.DeltaFragment{MatchShare: []byte("")}
+			_, err := rpc.DeserializeDeltaFragment(serializedDeltaFragment)
+			Ω(err).Should(HaveOccurred())
+		})
+	})
+
+	Context("rumor", func() {
+		It("should be able to serialize and deserialize compute.Rumor", func() {
+			keyPair, err := identity.NewKeyPair()
+			Ω(err).ShouldNot(HaveOccurred())
+			rumor := compute.NewRumor(order.ID("buyOrderID"), order.ID("sellOrderID"))
+			Ω(rumor.Sign(keyPair)).ShouldNot(HaveOccurred())
+			newRumor := rpc.DeserializeRumor(rpc.SerializeRumor(rumor))
+			Ω(newRumor).Should(Equal(rumor))
+			Ω(newRumor.VerifySignature(keyPair.ID())).ShouldNot(HaveOccurred())
+		})
+	})
+
+	Context("order.EncryptedFragment", func() {
+		It("should only be decrypted by the dark node it was encrypted for", func() {
+			price := stackint.FromUint(10)
+			maxVolume := stackint.FromUint(1000)
+			minVolume := stackint.FromUint(100)
+			nonce := stackint.FromUint(1)
+			prime := stackint.FromUint(1000000007)
+
+			// Expiries are serialized with a precision of one second
+			expiry := time.Now().Add(time.Hour).Truncate(time.Second)
+			fragments, err := order.NewOrder(order.TypeLimit, order.ParityBuy, expiry, order.CurrencyCodeBTC, order.CurrencyCodeETH, &price, &maxVolume, &minVolume, &nonce).Split(2, 1, &prime)
+			Ω(err).ShouldNot(HaveOccurred())
+			trader, err := identity.NewKeyPair()
+			Ω(err).ShouldNot(HaveOccurred())
+			Ω(order.SignFragments(trader, fragments)).ShouldNot(HaveOccurred())
+
+			encryptedFragment, err := rpc.EncryptOrderFragment(fragments[0], keyPair.ID(), keyPair.PublicKeyBytes())
+			Ω(err).ShouldNot(HaveOccurred())
+			encryptedFragment = rpc.DeserializeEncryptedOrderFragment(rpc.SerializeEncryptedOrderFragment(encryptedFragment))
+			Ω(encryptedFragment.To).Should(Equal(keyPair.ID()))
+			Ω(encryptedFragment.OrderID).Should(Equal(fragments[0].OrderID))
+
+			orderFragment, err := rpc.DecryptOrderFragment(encryptedFragment, keyPair)
+			Ω(err).ShouldNot(HaveOccurred())
+			Ω(*orderFragment).Should(Equal(*fragments[0]))
+			Ω(orderFragment.Verify()).ShouldNot(HaveOccurred())
+			_, err = rpc.DecryptOrderFragment(encryptedFragment, trader)
+			Ω(err).Should(HaveOccurred())
+
+			// The public fields must match the order fragment
+			encryptedFragment.OrderID = fragments[1].OrderID
+			encryptedFragment.OrderExpiry = time.Now()
+			_, err = rpc.DecryptOrderFragment(encryptedFragment, keyPair)
+			Ω(err).Should(Equal(rpc.ErrEncryptedOrderFragmentMismatch))
+		})
+	})
+
+	Context("compute.Snapshot", func() {
+		It("should be able to serialize and deserialize order fragments and finalizations", func() {
+			rumor := compute.NewRumor(order.ID("buyOrderID"), order.ID("sellOrderID"))
+			Ω(rumor.Sign(keyPair)).ShouldNot(HaveOccurred())
+			expiry := time.Now().Add(time.Hour).Truncate(time.Second)
+			snapshot := &compute.Snapshot{
+				OrderFragments: []*order.EncryptedFragment{order.NewEncryptedFragment(keyPair.ID(), order.ID("orderID"), expiry, []byte("ciphertext"))},
+				Finalizations:  [][]*compute.Rumor{{rumor}},
+			}
+			Ω(snapshot.Sign(keyPair)).ShouldNot(HaveOccurred())
+
+			newSnapshot, err := rpc.DeserializeSyncBlock(rpc.SerializeSyncBlock(snapshot))
+			Ω(err).ShouldNot(HaveOccurred())
+			Ω(newSnapshot.OrderFragments).Should(Equal(snapshot.OrderFragments))
+			Ω(newSnapshot.Finalizations).Should(Equal(snapshot.Finalizations))
+			Ω(newSnapshot.VerifySignature(keyPair.ID())).ShouldNot(HaveOccurred())
+		})
+	})
+
+	Context("order.Reveal", func() {
+		It("should be able to serialize and deserialize order.Reveal", func() {
+			price := stackint.FromUint(10)
+			maxVolume := stackint.FromUint(1000)
+			minVolume := stackint.FromUint(100)
+			nonce := stackint.FromUint(42)
+			ord := order.NewOrder(order.TypeLimit, order.ParityBuy, time.Now().Add(time.Hour), order.CurrencyCodeBTC, order.CurrencyCodeETH, &price, &maxVolume, &minVolume, &nonce).WithTimeInForce(order.TimeInForceIOC).WithOpenTime(time.Now())
+			reveal := order.NewReveal(keyPair.ID(), ord, []byte("account"))
+			Ω(reveal.Sign(keyPair)).ShouldNot(HaveOccurred())
+
+			newReveal, err := rpc.DeserializeReveal(rpc.SerializeReveal(reveal))
+			Ω(err).ShouldNot(HaveOccurred())
+			Ω(newReveal.Verify()).ShouldNot(HaveOccurred())
+			Ω(newReveal.Account).Should(Equal(reveal.Account))
+			Ω(newReveal.Order.ID).Should(Equal(ord.ID))
+			Ω(newReveal.Order.TimeInForce).Should(Equal(order.TimeInForceIOC))
+			Ω(newReveal.Order.OpenTime.Unix()).Should(Equal(ord.OpenTime.Unix()))
+			Ω(newReveal.Order.Price.Cmp(&price)).Should(Equal(0))
+			Ω(newReveal.Order.Nonce.Cmp(&nonce)).Should(Equal(0))
+		})
+
+		It("should return an error when deserializing an order.Reveal with a malformed price", func() {
+			serializedReveal := &rpc.Reveal{Order: &rpc.Order{Price: make([]byte, 1024)}}
+			_, err := rpc.DeserializeReveal(serializedReveal)
+			Ω(err).Should(HaveOccurred())
+		})
+	})
+
+	Context("compute.MidpointUpdate", func() {
+		It("should be able to serialize and deserialize compute.MidpointUpdate", func() {
+			midpoint := stackint.FromUint(71234)
+			midpointUpdate := compute.NewMidpointUpdate(order.CurrencyCodeETH, order.CurrencyCodeBTC, &midpoint, time.Now())
+			Ω(midpointUpdate.Sign(keyPair)).ShouldNot(HaveOccurred())
+
+			newMidpointUpdate, err := rpc.DeserializeMidpointUpdate(rpc.SerializeMidpointUpdate(midpointUpdate))
+			Ω(err).ShouldNot(HaveOccurred())
+			Ω(newMidpointUpdate.VerifySignature(keyPair.ID())).ShouldNot(HaveOccurred())
+			Ω(newMidpointUpdate.FstCode).Should(Equal(order.CurrencyCodeETH))
+			Ω(newMidpointUpdate.SndCode).Should(Equal(order.CurrencyCodeBTC))
+			Ω(newMidpointUpdate.Midpoint.Cmp(&midpoint)).Should(Equal(0))
+			Ω(newMidpointUpdate.Time.Unix()).Should(Equal(midpointUpdate.Time.Unix()))
+		})
 
+		It("should return an error when deserializing a compute.MidpointUpdate without a midpoint", func() {
+			_, err := rpc.DeserializeMidpointUpdate(&rpc.MidpointUpdate{})
+			Ω(err).Should(Equal(rpc.ErrMalformedMidpointUpdate))
 		})
 	})
 
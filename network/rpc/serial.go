@@ -1,12 +1,26 @@
 package rpc
 
 import (
+	"errors"
+	"math/big"
+	"time"
+
 	"github.com/republicprotocol/republic-go/compute"
 	"github.com/republicprotocol/republic-go/identity"
 	"github.com/republicprotocol/republic-go/order"
 	"github.com/republicprotocol/republic-go/shamir"
+	"github.com/republicprotocol/republic-go/stackint"
 )
 
+// ErrMalformedMarketProof is returned when the network representation of a
+// MarketProof does not have a currency code, a challenge, and two responses
+// for every Market.
+var ErrMalformedMarketProof = errors.New("malformed market proof")
+
+// ErrMalformedMidpointUpdate is returned when the network representation of a
+// MidpointUpdate does not have a midpoint.
+var ErrMalformedMidpointUpdate = errors.New("malformed midpoint update")
+
 // SerializeAddress converts an identity.MultiAddress into its network
 // representation.
 func SerializeAddress(address identity.Address) *Address {
@@ -34,21 +48,48 @@ func DeserializeMultiAddress(multiAddress *MultiAddress) (identity.MultiAddress,
 	return deserialized, multiAddress.Signature, err
 }
 
+// VerifyField checks that the network representation of a MultiAddress
+// identifies a peer that uses the finite field with the expected FieldID. The
+// check is skipped when either FieldID is empty, so that peers which do not
+// select a finite field can still connect. A shamir.FieldMismatchError is
+// returned if the FieldIDs are different.
+func VerifyField(expected shamir.FieldID, multiAddress *MultiAddress) error {
+	actual := shamir.FieldID(multiAddress.Field)
+	if len(expected) == 0 || len(actual) == 0 || expected.Equal(actual) {
+		return nil
+	}
+	return shamir.NewFieldMismatchError(expected, actual)
+}
+
 // SerializeOrderFragment converts an order.Fragment into its network
 // representation.
 func SerializeOrderFragment(orderFragment *order.Fragment) *OrderFragment {
 	val := &OrderFragment{
 		Signature:   []byte(orderFragment.Signature),
 		Id:          []byte(orderFragment.ID),
+		Trader:      []byte(orderFragment.Trader),
+		Field:       []byte(orderFragment.Field),
 		OrderId:     []byte(orderFragment.OrderID),
 		OrderType:   int64(orderFragment.OrderType),
 		OrderParity: int64(orderFragment.OrderParity),
+		OrderExpiry: orderFragment.OrderExpiry.Unix(),
+
+		OrderTimeInForce: int64(orderFragment.OrderTimeInForce),
 	}
 	val.FstCodeShare = shamir.ToBytes(orderFragment.FstCodeShare)
 	val.SndCodeShare = shamir.ToBytes(orderFragment.SndCodeShare)
 	val.PriceShare = shamir.ToBytes(orderFragment.PriceShare)
 	val.MaxVolumeShare = shamir.ToBytes(orderFragment.MaxVolumeShare)
 	val.MinVolumeShare = shamir.ToBytes(orderFragment.MinVolumeShare)
+	if orderFragment.OrderNonce != nil {
+		val.OrderNonce = orderFragment.OrderNonce.Bytes()
+	}
+	if !orderFragment.OrderOpenTime.IsZero() {
+		val.OrderOpenTime = orderFragment.OrderOpenTime.Unix()
+	}
+	if orderFragment.Commitments != nil {
+		val.Commitments = SerializeCommitments(orderFragment.Commitments)
+	}
 	return val
 }
 
@@ -59,9 +100,14 @@ func DeserializeOrderFragment(orderFragment *OrderFragment) (*order.Fragment, er
 	val := &order.Fragment{
 		Signature:   []byte(orderFragment.Signature),
 		ID:          order.FragmentID(orderFragment.Id),
+		Trader:      identity.ID(orderFragment.Trader),
+		Field:       shamir.FieldID(orderFragment.Field),
 		OrderID:     order.ID(orderFragment.OrderId),
 		OrderType:   order.Type(orderFragment.OrderType),
 		OrderParity: order.Parity(orderFragment.OrderParity),
+		OrderExpiry: time.Unix(orderFragment.OrderExpiry, 0),
+
+		OrderTimeInForce: order.TimeInForce(orderFragment.OrderTimeInForce),
 	}
 	var err error
 	val.FstCodeShare, err = shamir.FromBytes(orderFragment.FstCodeShare)
@@ -84,6 +130,160 @@ func DeserializeOrderFragment(orderFragment *OrderFragment) (*order.Fragment, er
 	if err != nil {
 		return nil, err
 	}
+	if len(orderFragment.OrderNonce) > 0 {
+		orderNonce, err := stackint.FromBytes(orderFragment.OrderNonce)
+		if err != nil {
+			return nil, err
+		}
+		val.OrderNonce = &orderNonce
+	}
+	if orderFragment.OrderOpenTime != 0 {
+		val.OrderOpenTime = time.Unix(orderFragment.OrderOpenTime, 0)
+	}
+	if orderFragment.Commitments != nil {
+		val.Commitments, err = DeserializeCommitments(orderFragment.Commitments)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return val, nil
+}
+
+// SerializeEncryptedOrderFragment converts an order.EncryptedFragment into its
+// network representation.
+func SerializeEncryptedOrderFragment(encryptedFragment *order.EncryptedFragment) *EncryptedOrderFragment {
+	return &EncryptedOrderFragment{
+		To:          []byte(encryptedFragment.To),
+		OrderId:     []byte(encryptedFragment.OrderID),
+		OrderExpiry: encryptedFragment.OrderExpiry.Unix(),
+		Ciphertext:  encryptedFragment.Ciphertext,
+	}
+}
+
+// DeserializeEncryptedOrderFragment converts a network representation of an
+// EncryptedOrderFragment into an order.EncryptedFragment.
+func DeserializeEncryptedOrderFragment(encryptedFragment *EncryptedOrderFragment) *order.EncryptedFragment {
+	return order.NewEncryptedFragment(identity.ID(encryptedFragment.To), order.ID(encryptedFragment.OrderId), time.Unix(encryptedFragment.OrderExpiry, 0), encryptedFragment.Ciphertext)
+}
+
+// SerializeEncryptedOrderFragments converts order.EncryptedFragments into
+// their network representation.
+func SerializeEncryptedOrderFragments(encryptedFragments []*order.EncryptedFragment) []*EncryptedOrderFragment {
+	serialized := make([]*EncryptedOrderFragment, len(encryptedFragments))
+	for i := range encryptedFragments {
+		serialized[i] = SerializeEncryptedOrderFragment(encryptedFragments[i])
+	}
+	return serialized
+}
+
+// DeserializeEncryptedOrderFragments converts network representations of
+// EncryptedOrderFragments into order.EncryptedFragments.
+func DeserializeEncryptedOrderFragments(encryptedFragments []*EncryptedOrderFragment) []*order.EncryptedFragment {
+	deserialized := make([]*order.EncryptedFragment, len(encryptedFragments))
+	for i := range encryptedFragments {
+		deserialized[i] = DeserializeEncryptedOrderFragment(encryptedFragments[i])
+	}
+	return deserialized
+}
+
+// SerializeCommitments converts order.Commitments into their network
+// representation.
+func SerializeCommitments(commitments *order.Commitments) *Commitments {
+	serialize := func(commitments shamir.Commitments) [][]byte {
+		serialized := make([][]byte, len(commitments))
+		for i := range commitments {
+			serialized[i] = commitments[i].Bytes()
+		}
+		return serialized
+	}
+	val := &Commitments{
+		FstCode:           serialize(commitments.FstCode),
+		SndCode:           serialize(commitments.SndCode),
+		Price:             serialize(commitments.Price),
+		MaxVolume:         serialize(commitments.MaxVolume),
+		MinVolume:         serialize(commitments.MinVolume),
+		FstCodeBlinding:   shamir.ToBytes(commitments.FstCodeBlinding),
+		SndCodeBlinding:   shamir.ToBytes(commitments.SndCodeBlinding),
+		PriceBlinding:     shamir.ToBytes(commitments.PriceBlinding),
+		MaxVolumeBlinding: shamir.ToBytes(commitments.MaxVolumeBlinding),
+		MinVolumeBlinding: shamir.ToBytes(commitments.MinVolumeBlinding),
+	}
+	if commitments.MarketProof != nil {
+		// Every Market has one response for the commitment to the FstCode,
+		// and one for the commitment to the SndCode
+		for _, market := range commitments.Markets {
+			val.MarketFstCodes = append(val.MarketFstCodes, int64(market[0]))
+			val.MarketSndCodes = append(val.MarketSndCodes, int64(market[1]))
+		}
+		val.MarketChallenges = serialize(commitments.MarketProof.Challenges)
+		for _, responses := range commitments.MarketProof.Responses {
+			if len(responses) != 2 {
+				continue
+			}
+			val.MarketFstCodeResponses = append(val.MarketFstCodeResponses, responses[0].Bytes())
+			val.MarketSndCodeResponses = append(val.MarketSndCodeResponses, responses[1].Bytes())
+		}
+	}
+	return val
+}
+
+// DeserializeCommitments converts a network representation of Commitments
+// into order.Commitments. An error is returned if the network representation
+// is malformed.
+func DeserializeCommitments(commitments *Commitments) (*order.Commitments, error) {
+	deserialize := func(commitments [][]byte) shamir.Commitments {
+		deserialized := make(shamir.Commitments, len(commitments))
+		for i := range commitments {
+			deserialized[i] = new(big.Int).SetBytes(commitments[i])
+		}
+		return deserialized
+	}
+	val := &order.Commitments{
+		FstCode:   deserialize(commitments.FstCode),
+		SndCode:   deserialize(commitments.SndCode),
+		Price:     deserialize(commitments.Price),
+		MaxVolume: deserialize(commitments.MaxVolume),
+		MinVolume: deserialize(commitments.MinVolume),
+	}
+	var err error
+	val.FstCodeBlinding, err = shamir.FromBytes(commitments.FstCodeBlinding)
+	if err != nil {
+		return nil, err
+	}
+	val.SndCodeBlinding, err = shamir.FromBytes(commitments.SndCodeBlinding)
+	if err != nil {
+		return nil, err
+	}
+	val.PriceBlinding, err = shamir.FromBytes(commitments.PriceBlinding)
+	if err != nil {
+		return nil, err
+	}
+	val.MaxVolumeBlinding, err = shamir.FromBytes(commitments.MaxVolumeBlinding)
+	if err != nil {
+		return nil, err
+	}
+	val.MinVolumeBlinding, err = shamir.FromBytes(commitments.MinVolumeBlinding)
+	if err != nil {
+		return nil, err
+	}
+	if len(commitments.MarketChallenges) > 0 {
+		n := len(commitments.MarketChallenges)
+		if len(commitments.MarketFstCodes) != n || len(commitments.MarketSndCodes) != n || len(commitments.MarketFstCodeResponses) != n || len(commitments.MarketSndCodeResponses) != n {
+			return nil, ErrMalformedMarketProof
+		}
+		val.Markets = make([][2]order.CurrencyCode, n)
+		val.MarketProof = &shamir.MembershipProof{
+			Challenges: deserialize(commitments.MarketChallenges),
+			Responses:  make([][]*big.Int, n),
+		}
+		for i := 0; i < n; i++ {
+			val.Markets[i] = [2]order.CurrencyCode{order.CurrencyCode(commitments.MarketFstCodes[i]), order.CurrencyCode(commitments.MarketSndCodes[i])}
+			val.MarketProof.Responses[i] = []*big.Int{
+				new(big.Int).SetBytes(commitments.MarketFstCodeResponses[i]),
+				new(big.Int).SetBytes(commitments.MarketSndCodeResponses[i]),
+			}
+		}
+	}
 	return val, nil
 }
 
@@ -91,17 +291,15 @@ func DeserializeOrderFragment(orderFragment *OrderFragment) (*order.Fragment, er
 // representation.
 func SerializeDeltaFragment(deltaFragment *compute.DeltaFragment) *DeltaFragment {
 	return &DeltaFragment{
+		Signature:           deltaFragment.Signature,
 		Id:                  deltaFragment.ID,
 		DeltaId:             deltaFragment.DeltaID,
 		BuyOrderId:          deltaFragment.BuyOrderID,
 		SellOrderId:         deltaFragment.SellOrderID,
 		BuyOrderFragmentId:  deltaFragment.BuyOrderFragmentID,
 		SellOrderFragmentId: deltaFragment.SellOrderFragmentID,
-		FstCodeShare:        shamir.ToBytes(deltaFragment.FstCodeShare),
-		SndCodeShare:        shamir.ToBytes(deltaFragment.SndCodeShare),
-		PriceShare:          shamir.ToBytes(deltaFragment.PriceShare),
-		MaxVolumeShare:      shamir.ToBytes(deltaFragment.MaxVolumeShare),
-		MinVolumeShare:      shamir.ToBytes(deltaFragment.MinVolumeShare),
+		MatchShare:          shamir.ToBytes(deltaFragment.MatchShare),
+		CommitmentsHash:     deltaFragment.CommitmentsHash,
 	}
 }
 
@@ -110,33 +308,414 @@ func SerializeDeltaFragment(deltaFragment *compute.DeltaFragment) *DeltaFragment
 // representation is malformed.
 func DeserializeDeltaFragment(deltaFragment *DeltaFragment) (*compute.DeltaFragment, error) {
 	val := &compute.DeltaFragment{
+		Signature:           deltaFragment.Signature,
 		ID:                  deltaFragment.Id,
 		DeltaID:             deltaFragment.DeltaId,
 		BuyOrderID:          deltaFragment.BuyOrderId,
 		SellOrderID:         deltaFragment.SellOrderId,
 		BuyOrderFragmentID:  deltaFragment.BuyOrderFragmentId,
 		SellOrderFragmentID: deltaFragment.SellOrderFragmentId,
+		CommitmentsHash:     deltaFragment.CommitmentsHash,
+	}
+	var err error
+	val.MatchShare, err = shamir.FromBytes(deltaFragment.MatchShare)
+	if err != nil {
+		return nil, err
+	}
+	return val, nil
+}
+
+// SerializeSyncBlock converts a compute.Snapshot into its network
+// representation.
+func SerializeSyncBlock(snapshot *compute.Snapshot) *SyncBlock {
+	serialize := func(deltaFragments []*compute.DeltaFragment) []*DeltaFragment {
+		serialized := make([]*DeltaFragment, len(deltaFragments))
+		for i := range deltaFragments {
+			serialized[i] = SerializeDeltaFragment(deltaFragments[i])
+		}
+		return serialized
+	}
+	finalizations := make([]*SyncBlock_Finalization, len(snapshot.Finalizations))
+	for i := range snapshot.Finalizations {
+		finalizations[i] = &SyncBlock_Finalization{
+			Rumors: SerializeRumors(snapshot.Finalizations[i]),
+		}
+	}
+	return &SyncBlock{
+		Signature: snapshot.Signature,
+		DeltaBlock: &SyncBlock_DeltaBlock{
+			Pending:    serialize(snapshot.Pending),
+			Matched:    serialize(snapshot.Matched),
+			Mismatched: serialize(snapshot.Mismatched),
+		},
+		OrderFragments: SerializeEncryptedOrderFragments(snapshot.OrderFragments),
+		Finalizations:  finalizations,
+	}
+}
+
+// DeserializeSyncBlock converts a network representation of a SyncBlock into
+// a compute.Snapshot. An error is returned if the network representation is
+// malformed.
+func DeserializeSyncBlock(syncBlock *SyncBlock) (*compute.Snapshot, error) {
+	deserialize := func(deltaFragments []*DeltaFragment) ([]*compute.DeltaFragment, error) {
+		deserialized := make([]*compute.DeltaFragment, len(deltaFragments))
+		for i := range deltaFragments {
+			deltaFragment, err := DeserializeDeltaFragment(deltaFragments[i])
+			if err != nil {
+				return nil, err
+			}
+			deserialized[i] = deltaFragment
+		}
+		return deserialized, nil
+	}
+	val := &compute.Snapshot{
+		Signature: syncBlock.Signature,
 	}
+	deltaBlock := syncBlock.GetDeltaBlock()
 	var err error
-	val.FstCodeShare, err = shamir.FromBytes(deltaFragment.FstCodeShare)
+	val.Pending, err = deserialize(deltaBlock.GetPending())
 	if err != nil {
 		return nil, err
 	}
-	val.SndCodeShare, err = shamir.FromBytes(deltaFragment.SndCodeShare)
+	val.Matched, err = deserialize(deltaBlock.GetMatched())
 	if err != nil {
 		return nil, err
 	}
-	val.PriceShare, err = shamir.FromBytes(deltaFragment.PriceShare)
+	val.Mismatched, err = deserialize(deltaBlock.GetMismatched())
 	if err != nil {
 		return nil, err
 	}
-	val.MaxVolumeShare, err = shamir.FromBytes(deltaFragment.MaxVolumeShare)
+	val.OrderFragments = DeserializeEncryptedOrderFragments(syncBlock.GetOrderFragments())
+	val.Finalizations = make([][]*compute.Rumor, len(syncBlock.GetFinalizations()))
+	for i, finalization := range syncBlock.GetFinalizations() {
+		val.Finalizations[i] = DeserializeRumors(finalization.GetRumors())
+	}
+	return val, nil
+}
+
+// SerializeRandomFragment converts a compute.RandomFragment into its network
+// representation.
+func SerializeRandomFragment(randomFragment *compute.RandomFragment) *RandomFragment {
+	return &RandomFragment{
+		Signature: randomFragment.Signature,
+		ResidueId: randomFragment.ResidueID,
+		AShare:    shamir.ToBytes(randomFragment.AShare),
+		BShare:    shamir.ToBytes(randomFragment.BShare),
+	}
+}
+
+// DeserializeRandomFragment converts a network representation of a
+// RandomFragment into a compute.RandomFragment. An error is returned if the
+// network representation is malformed.
+func DeserializeRandomFragment(randomFragment *RandomFragment) (*compute.RandomFragment, error) {
+	val := &compute.RandomFragment{
+		Signature: randomFragment.Signature,
+		ResidueID: randomFragment.ResidueId,
+	}
+	var err error
+	val.AShare, err = shamir.FromBytes(randomFragment.AShare)
 	if err != nil {
 		return nil, err
 	}
-	val.MinVolumeShare, err = shamir.FromBytes(deltaFragment.MinVolumeShare)
+	val.BShare, err = shamir.FromBytes(randomFragment.BShare)
 	if err != nil {
 		return nil, err
 	}
 	return val, nil
 }
+
+// SerializeResidueFragment converts a compute.ResidueFragment into its network
+// representation.
+func SerializeResidueFragment(residueFragment *compute.ResidueFragment) *ResidueFragment {
+	return &ResidueFragment{
+		Signature:    residueFragment.Signature,
+		ResidueId:    residueFragment.ResidueID,
+		AShare:       shamir.ToBytes(residueFragment.AShare),
+		BShare:       shamir.ToBytes(residueFragment.BShare),
+		CShare:       shamir.ToBytes(residueFragment.CShare),
+		ResidueShare: shamir.ToBytes(residueFragment.ResidueShare),
+	}
+}
+
+// DeserializeResidueFragment converts a network representation of a
+// ResidueFragment into a compute.ResidueFragment. An error is returned if the
+// network representation is malformed.
+func DeserializeResidueFragment(residueFragment *ResidueFragment) (*compute.ResidueFragment, error) {
+	val := &compute.ResidueFragment{
+		Signature: residueFragment.Signature,
+		ResidueID: residueFragment.ResidueId,
+	}
+	var err error
+	val.AShare, err = shamir.FromBytes(residueFragment.AShare)
+	if err != nil {
+		return nil, err
+	}
+	val.BShare, err = shamir.FromBytes(residueFragment.BShare)
+	if err != nil {
+		return nil, err
+	}
+	val.CShare, err = shamir.FromBytes(residueFragment.CShare)
+	if err != nil {
+		return nil, err
+	}
+	val.ResidueShare, err = shamir.FromBytes(residueFragment.ResidueShare)
+	if err != nil {
+		return nil, err
+	}
+	return val, nil
+}
+
+// SerializeAlphaBetaFragment converts a compute.AlphaBetaFragment into its
+// network representation.
+func SerializeAlphaBetaFragment(alphaBetaFragment *compute.AlphaBetaFragment) *AlphaBetaFragment {
+	return &AlphaBetaFragment{
+		Signature:  alphaBetaFragment.Signature,
+		ResidueId:  alphaBetaFragment.ResidueID,
+		AlphaShare: shamir.ToBytes(alphaBetaFragment.AlphaShare),
+		BetaShare:  shamir.ToBytes(alphaBetaFragment.BetaShare),
+	}
+}
+
+// DeserializeAlphaBetaFragment converts a network representation of an
+// AlphaBetaFragment into a compute.AlphaBetaFragment. An error is returned if
+// the network representation is malformed.
+func DeserializeAlphaBetaFragment(alphaBetaFragment *AlphaBetaFragment) (*compute.AlphaBetaFragment, error) {
+	val := &compute.AlphaBetaFragment{
+		Signature: alphaBetaFragment.Signature,
+		ResidueID: alphaBetaFragment.ResidueId,
+	}
+	var err error
+	val.AlphaShare, err = shamir.FromBytes(alphaBetaFragment.AlphaShare)
+	if err != nil {
+		return nil, err
+	}
+	val.BetaShare, err = shamir.FromBytes(alphaBetaFragment.BetaShare)
+	if err != nil {
+		return nil, err
+	}
+	return val, nil
+}
+
+// SerializeRumor converts a compute.Rumor into its network representation.
+func SerializeRumor(rumor *compute.Rumor) *Rumor {
+	return &Rumor{
+		Signature:   rumor.Signature,
+		BuyOrderId:  rumor.BuyOrderID,
+		SellOrderId: rumor.SellOrderID,
+	}
+}
+
+// DeserializeRumor converts a network representation of a Rumor into a
+// compute.Rumor.
+func DeserializeRumor(rumor *Rumor) *compute.Rumor {
+	return &compute.Rumor{
+		Signature:   rumor.Signature,
+		BuyOrderID:  rumor.BuyOrderId,
+		SellOrderID: rumor.SellOrderId,
+	}
+}
+
+// SerializeRumors converts compute.Rumors into their network representation.
+func SerializeRumors(rumors []*compute.Rumor) []*Rumor {
+	serialized := make([]*Rumor, len(rumors))
+	for i := range rumors {
+		serialized[i] = SerializeRumor(rumors[i])
+	}
+	return serialized
+}
+
+// DeserializeRumors converts network representations of Rumors into
+// compute.Rumors.
+func DeserializeRumors(rumors []*Rumor) []*compute.Rumor {
+	deserialized := make([]*compute.Rumor, len(rumors))
+	for i := range rumors {
+		deserialized[i] = DeserializeRumor(rumors[i])
+	}
+	return deserialized
+}
+
+// SerializeSubscription converts an order.Subscription into its network
+// representation.
+func SerializeSubscription(subscription *order.Subscription) *Subscription {
+	return &Subscription{
+		Signature: subscription.Signature,
+		Trader:    subscription.Trader,
+		Timestamp: subscription.Timestamp.Unix(),
+	}
+}
+
+// DeserializeSubscription converts a network representation of a
+// Subscription into an order.Subscription. A missing Subscription is
+// converted into an unsigned order.Subscription.
+func DeserializeSubscription(subscription *Subscription) *order.Subscription {
+	return &order.Subscription{
+		Signature: subscription.GetSignature(),
+		Trader:    subscription.GetTrader(),
+		Timestamp: time.Unix(subscription.GetTimestamp(), 0),
+	}
+}
+
+// SerializeNotification converts an order.Notification into its network
+// representation.
+func SerializeNotification(notification *order.Notification) *Notification {
+	return &Notification{
+		Signature:           notification.Signature,
+		Type:                int64(notification.Type),
+		OrderId:             notification.OrderID,
+		CounterpartyOrderId: notification.CounterpartyOrderID,
+	}
+}
+
+// DeserializeNotification converts a network representation of a
+// Notification into an order.Notification.
+func DeserializeNotification(notification *Notification) *order.Notification {
+	return &order.Notification{
+		Signature:           notification.Signature,
+		Type:                order.NotificationType(notification.Type),
+		OrderID:             notification.OrderId,
+		CounterpartyOrderID: notification.CounterpartyOrderId,
+	}
+}
+
+// SerializeOrder converts an order.Order into its network representation.
+func SerializeOrder(ord *order.Order) *Order {
+	val := &Order{
+		Signature:   ord.Signature,
+		Id:          ord.ID,
+		Type:        int64(ord.Type),
+		Parity:      int64(ord.Parity),
+		Expiry:      ord.Expiry.Unix(),
+		TimeInForce: int64(ord.TimeInForce),
+		FstCode:     int64(ord.FstCode),
+		SndCode:     int64(ord.SndCode),
+	}
+	if ord.Price != nil {
+		val.Price = ord.Price.Bytes()
+	}
+	if ord.MaxVolume != nil {
+		val.MaxVolume = ord.MaxVolume.Bytes()
+	}
+	if ord.MinVolume != nil {
+		val.MinVolume = ord.MinVolume.Bytes()
+	}
+	if ord.Nonce != nil {
+		val.Nonce = ord.Nonce.Bytes()
+	}
+	if !ord.OpenTime.IsZero() {
+		val.OpenTime = ord.OpenTime.Unix()
+	}
+	return val
+}
+
+// DeserializeOrder converts a network representation of an Order into an
+// order.Order. An error is returned if the network representation is
+// malformed.
+func DeserializeOrder(ord *Order) (*order.Order, error) {
+	val := &order.Order{
+		Signature:   ord.Signature,
+		ID:          order.ID(ord.Id),
+		Type:        order.Type(ord.Type),
+		Parity:      order.Parity(ord.Parity),
+		Expiry:      time.Unix(ord.Expiry, 0),
+		TimeInForce: order.TimeInForce(ord.TimeInForce),
+		FstCode:     order.CurrencyCode(ord.FstCode),
+		SndCode:     order.CurrencyCode(ord.SndCode),
+	}
+	var err error
+	if val.Price, err = deserializeInt1024(ord.Price); err != nil {
+		return nil, err
+	}
+	if val.MaxVolume, err = deserializeInt1024(ord.MaxVolume); err != nil {
+		return nil, err
+	}
+	if val.MinVolume, err = deserializeInt1024(ord.MinVolume); err != nil {
+		return nil, err
+	}
+	if val.Nonce, err = deserializeInt1024(ord.Nonce); err != nil {
+		return nil, err
+	}
+	if ord.OpenTime != 0 {
+		val.OpenTime = time.Unix(ord.OpenTime, 0)
+	}
+	return val, nil
+}
+
+// SerializeReveal converts an order.Reveal into its network representation.
+func SerializeReveal(reveal *order.Reveal) *Reveal {
+	val := &Reveal{
+		Signature: reveal.Signature,
+		Trader:    reveal.Trader,
+		Account:   reveal.Account,
+	}
+	if reveal.Order != nil {
+		val.Order = SerializeOrder(reveal.Order)
+	}
+	return val
+}
+
+// DeserializeReveal converts a network representation of a Reveal into an
+// order.Reveal. An error is returned if the network representation is
+// malformed. A Reveal without an Order is converted into an order.Reveal
+// without an Order, which cannot be verified.
+func DeserializeReveal(reveal *Reveal) (*order.Reveal, error) {
+	val := &order.Reveal{
+		Signature: reveal.GetSignature(),
+		Trader:    reveal.GetTrader(),
+		Account:   reveal.GetAccount(),
+	}
+	if reveal.GetOrder() != nil {
+		var err error
+		if val.Order, err = DeserializeOrder(reveal.GetOrder()); err != nil {
+			return nil, err
+		}
+	}
+	return val, nil
+}
+
+// SerializeMidpointUpdate converts a compute.MidpointUpdate into its network
+// representation.
+func SerializeMidpointUpdate(update *compute.MidpointUpdate) *MidpointUpdate {
+	val := &MidpointUpdate{
+		Signature: update.Signature,
+		FstCode:   int64(update.FstCode),
+		SndCode:   int64(update.SndCode),
+		Time:      update.Time.Unix(),
+	}
+	if update.Midpoint != nil {
+		val.Midpoint = update.Midpoint.Bytes()
+	}
+	return val
+}
+
+// DeserializeMidpointUpdate converts a network representation of a
+// MidpointUpdate into a compute.MidpointUpdate. An error is returned if the
+// network representation is malformed.
+func DeserializeMidpointUpdate(update *MidpointUpdate) (*compute.MidpointUpdate, error) {
+	midpoint, err := deserializeInt1024(update.GetMidpoint())
+	if err != nil {
+		return nil, err
+	}
+	if midpoint == nil {
+		return nil, ErrMalformedMidpointUpdate
+	}
+	return &compute.MidpointUpdate{
+		Signature: update.GetSignature(),
+		FstCode:   order.CurrencyCode(update.GetFstCode()),
+		SndCode:   order.CurrencyCode(update.GetSndCode()),
+		Midpoint:  midpoint,
+		Time:      time.Unix(update.GetTime(), 0),
+	}, nil
+}
+
+// deserializeInt1024 converts the bytes of an Int1024 into an Int1024. Empty
+// bytes are converted into nil.
+func deserializeInt1024(bytes []byte) (*stackint.Int1024, error) {
+	if len(bytes) == 0 {
+		return nil, nil
+	}
+	val, err := stackint.FromBytes(bytes)
+	if err != nil {
+		return nil, err
+	}
+	return &val, nil
+}
@@ -8,6 +8,7 @@ import (
 	"github.com/republicprotocol/republic-go/logger"
 	"github.com/republicprotocol/republic-go/network/dht"
 	"github.com/republicprotocol/republic-go/network/rpc"
+	"github.com/republicprotocol/republic-go/shamir"
 	"golang.org/x/net/context"
 	"google.golang.org/grpc"
 )
@@ -20,13 +21,16 @@ type SwarmDelegate interface {
 	// OnQueryDeep(from identity.MultiAddress)
 }
 
-// SwarmService implements the gRPC Swarm service.
+// SwarmService implements the gRPC Swarm service. When the Field is set,
+// peers that ping the SwarmService using a different finite field are
+// rejected.
 type SwarmService struct {
 	SwarmDelegate
 	Options
 	Logger     *logger.Logger
 	ClientPool *rpc.ClientPool
 	DHT        *dht.DHT
+	Field      shamir.FieldID
 }
 
 // NewSwarmService returns a SwarmService.
@@ -108,22 +112,30 @@ func (service *SwarmService) MultiAddress() identity.MultiAddress {
 
 // Ping is used to test the connection to the Node and exchange
 // identity.MultiAddresses. If the Node does not respond, or it responds with
-// an error, then the connection should be considered unhealthy.
+// an error, then the connection should be considered unhealthy. The Field of
+// the SwarmService is returned, so that the peer can check it, and a
+// shamir.FieldMismatchError is returned if the peer uses a different finite
+// field.
 func (service *SwarmService) Ping(ctx context.Context, from *rpc.MultiAddress) (*rpc.MultiAddress, error) {
 	wait := do.Process(func() do.Option {
 		return do.Err(service.ping(from))
 	})
 
+	multiAddress := rpc.SerializeMultiAddress(service.MultiAddress(), nil)
+	multiAddress.Field = service.Field
 	select {
 	case val := <-wait:
-		return rpc.SerializeMultiAddress(service.MultiAddress(), nil), val.Err
+		return multiAddress, val.Err
 
 	case <-ctx.Done():
-		return rpc.SerializeMultiAddress(service.MultiAddress(), nil), ctx.Err()
+		return multiAddress, ctx.Err()
 	}
 }
 
 func (service *SwarmService) ping(from *rpc.MultiAddress) error {
+	if err := rpc.VerifyField(service.Field, from); err != nil {
+		return err
+	}
 	return service.updatePeer(from)
 }
 
@@ -11,6 +11,7 @@ import (
 	"github.com/republicprotocol/go-do"
 	"github.com/republicprotocol/republic-go/identity"
 	"github.com/republicprotocol/republic-go/network"
+	"github.com/republicprotocol/republic-go/shamir"
 	"google.golang.org/grpc"
 )
 
@@ -141,6 +142,58 @@ var _ = Describe("Swarm service", func() {
 		})
 	})
 
+	Context("when pinging nodes", func() {
+		var defaultField, testField *shamir.Field
+
+		BeforeEach(func() {
+			mu.Lock()
+
+			defaultField, err = shamir.FieldByName(shamir.FieldDefault)
+			Ω(err).ShouldNot(HaveOccurred())
+			testField, err = shamir.FieldByName(shamir.FieldTest)
+			Ω(err).ShouldNot(HaveOccurred())
+
+			swarms, servers, err = generateSwarmServices(1)
+			Ω(err).ShouldNot(HaveOccurred())
+			for i := range swarms {
+				field := defaultField
+				if i == len(swarms)-1 {
+					field = testField
+				}
+				swarms[i].Field = field.ID
+				swarms[i].ClientPool.WithField(field.ID).WithTimeoutRetries(1)
+			}
+
+			err = startSwarmServices(servers, swarms)
+			Ω(err).ShouldNot(HaveOccurred())
+		})
+
+		AfterEach(func() {
+			stopSwarmServices(servers, swarms)
+			mu.Unlock()
+		})
+
+		It("should connect nodes that use the same field", func() {
+			err := swarms[0].ClientPool.Ping(swarms[1].MultiAddress())
+			Ω(err).ShouldNot(HaveOccurred())
+
+			multiAddress, err := swarms[1].DHT.FindMultiAddress(swarms[0].Address())
+			Ω(err).ShouldNot(HaveOccurred())
+			Ω(multiAddress).ShouldNot(BeNil())
+		})
+
+		It("should not connect nodes that use different fields", func() {
+			last := swarms[len(swarms)-1]
+			err := swarms[0].ClientPool.Ping(last.MultiAddress())
+			Ω(err).Should(HaveOccurred())
+			Ω(err.Error()).Should(ContainSubstring(shamir.NewFieldMismatchError(testField.ID, defaultField.ID).Error()))
+
+			multiAddress, err := last.DHT.FindMultiAddress(swarms[0].Address())
+			Ω(err).ShouldNot(HaveOccurred())
+			Ω(multiAddress).Should(BeNil())
+		})
+	})
+
 })
 
 func startSwarmServices(servers []*grpc.Server, swarms []*network.SwarmService) error {
@@ -0,0 +1,55 @@
+package order
+
+import (
+	"bytes"
+
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/republicprotocol/republic-go/identity"
+)
+
+// cancellationPrefix is prepended to the OrderID before hashing so that a
+// Cancellation signature cannot be mistaken for any other signature.
+var cancellationPrefix = []byte("Republic Protocol: cancel: ")
+
+// A Cancellation is a request to withdraw an Order from the dark pools. It
+// must be signed by the same trader that signed the Order's Fragments.
+type Cancellation struct {
+	Signature identity.Signature
+	OrderID   ID
+}
+
+// NewCancellation returns a new, unsigned, Cancellation for the Order with the
+// given ID.
+func NewCancellation(orderID ID) *Cancellation {
+	return &Cancellation{
+		OrderID: orderID,
+	}
+}
+
+// Hash returns the Keccak256 hash of a Cancellation. This hash is used to
+// create the signature for a Cancellation.
+func (cancellation *Cancellation) Hash() []byte {
+	return crypto.Keccak256(cancellation.Bytes())
+}
+
+// Sign signs the Cancellation using the provided keypair, and assigns it the
+// the Cancellation's Signature field.
+func (cancellation *Cancellation) Sign(keyPair identity.KeyPair) error {
+	var err error
+	cancellation.Signature, err = keyPair.Sign(cancellation)
+	return err
+}
+
+// VerifySignature verifies that the Signature field has been signed by the
+// provided ID's private key, returning an error if the signature is invalid
+func (cancellation *Cancellation) VerifySignature(ID identity.ID) error {
+	return identity.VerifySignature(cancellation, cancellation.Signature, ID)
+}
+
+// Bytes returns a Cancellation serialized into a bytes.
+func (cancellation *Cancellation) Bytes() []byte {
+	buf := new(bytes.Buffer)
+	buf.Write(cancellationPrefix)
+	buf.Write(cancellation.OrderID)
+	return buf.Bytes()
+}
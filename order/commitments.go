@@ -0,0 +1,167 @@
+package order
+
+import (
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/crypto"
+
+	"github.com/republicprotocol/republic-go/canonical"
+	"github.com/republicprotocol/republic-go/shamir"
+)
+
+// commitmentsTag identifies the canonical encoding of the commitments to the
+// polynomials of an Order.
+var commitmentsTag = []byte("Republic Protocol: commitments")
+
+// Commitments hold the Pedersen commitments to the polynomials that were used
+// to split the secure fields of an Order, and the shares of the blinding
+// polynomials that are needed to verify the shares of one Fragment. Every
+// Fragment of an Order holds the same commitments, and because they are
+// signed by the trader, a trader that sends different commitments to
+// different dark nodes can be proven to have done so. The MarketProof proves
+// that the FstCode and SndCode commit to one of the Markets, so that dark
+// nodes can check that an Order is in a listed market without learning which
+// one.
+type Commitments struct {
+	FstCode   shamir.Commitments
+	SndCode   shamir.Commitments
+	Price     shamir.Commitments
+	MaxVolume shamir.Commitments
+	MinVolume shamir.Commitments
+
+	FstCodeBlinding   shamir.Share
+	SndCodeBlinding   shamir.Share
+	PriceBlinding     shamir.Share
+	MaxVolumeBlinding shamir.Share
+	MinVolumeBlinding shamir.Share
+
+	Markets     [][2]CurrencyCode
+	MarketProof *shamir.MembershipProof
+}
+
+// Hash returns the Keccak256 hash of the canonical encoding of the
+// commitments to the polynomials, without the shares of the blinding
+// polynomials. The hash is the same for every Fragment of an Order, so dark
+// nodes that verified their Fragments against the same commitments compute the
+// same hash. An error is returned if any of the commitments is nil.
+func (commitments *Commitments) Hash() ([]byte, error) {
+	encoder := canonical.NewEncoder(commitmentsTag)
+	commitments.writePolynomials(encoder)
+	data, err := encoder.Bytes()
+	if err != nil {
+		return nil, err
+	}
+	return crypto.Keccak256(data), nil
+}
+
+// write the canonical encoding of the Commitments. Each polynomial is
+// encoded as a list of commitments, followed by the shares of the blinding
+// polynomials, and the optional MarketProof. The MarketProof is encoded as the
+// list of Markets, each as two currency codes, followed by the list of
+// challenges and the list of responses of each Market.
+func (commitments *Commitments) write(encoder *canonical.Encoder) {
+	commitments.writePolynomials(encoder)
+	for _, blinding := range []shamir.Share{commitments.FstCodeBlinding, commitments.SndCodeBlinding, commitments.PriceBlinding, commitments.MaxVolumeBlinding, commitments.MinVolumeBlinding} {
+		encoder.WriteShare(blinding)
+	}
+	encoder.WriteFlag(commitments.MarketProof != nil)
+	if commitments.MarketProof != nil {
+		encoder.WriteLength(len(commitments.Markets))
+		for _, market := range commitments.Markets {
+			encoder.WriteInt64(int64(market[0]))
+			encoder.WriteInt64(int64(market[1]))
+		}
+		encoder.WriteLength(len(commitments.MarketProof.Challenges))
+		for _, challenge := range commitments.MarketProof.Challenges {
+			encoder.WriteBigInt(challenge)
+		}
+		encoder.WriteLength(len(commitments.MarketProof.Responses))
+		for _, responses := range commitments.MarketProof.Responses {
+			encoder.WriteLength(len(responses))
+			for _, response := range responses {
+				encoder.WriteBigInt(response)
+			}
+		}
+	}
+}
+
+func (commitments *Commitments) writePolynomials(encoder *canonical.Encoder) {
+	for _, polynomial := range []shamir.Commitments{commitments.FstCode, commitments.SndCode, commitments.Price, commitments.MaxVolume, commitments.MinVolume} {
+		encoder.WriteLength(len(polynomial))
+		for _, commitment := range polynomial {
+			encoder.WriteBigInt(commitment)
+		}
+	}
+}
+
+// VerifyCommitments checks that the shares of the Fragment are consistent with
+// its Commitments, using the VSS parameters that were used to split the Order,
+// and the number of shares K that are required to reconstruct it. A
+// shamir.CommitmentError is returned if the Fragment has no Commitments, or
+// if any of its shares is not consistent with them.
+func (fragment *Fragment) VerifyCommitments(vss *shamir.VSS, k int64) error {
+	if fragment.Commitments == nil {
+		return shamir.NewCommitmentError(fragment.FstCodeShare)
+	}
+	if err := vss.VerifyShare(k, fragment.FstCodeShare, fragment.Commitments.FstCodeBlinding, fragment.Commitments.FstCode); err != nil {
+		return err
+	}
+	if err := vss.VerifyShare(k, fragment.SndCodeShare, fragment.Commitments.SndCodeBlinding, fragment.Commitments.SndCode); err != nil {
+		return err
+	}
+	if err := vss.VerifyShare(k, fragment.PriceShare, fragment.Commitments.PriceBlinding, fragment.Commitments.Price); err != nil {
+		return err
+	}
+	if err := vss.VerifyShare(k, fragment.MaxVolumeShare, fragment.Commitments.MaxVolumeBlinding, fragment.Commitments.MaxVolume); err != nil {
+		return err
+	}
+	return vss.VerifyShare(k, fragment.MinVolumeShare, fragment.Commitments.MinVolumeBlinding, fragment.Commitments.MinVolume)
+}
+
+// VerifyMarket checks that the FstCode and SndCode shares of the Fragment are
+// shares of one of the Markets in its Commitments, using the MarketProof. The
+// shares must have been verified against the Commitments using
+// VerifyCommitments. A shamir.ErrInvalidMembershipProof is returned if the
+// Fragment has no MarketProof, or if the MarketProof is not valid.
+func (fragment *Fragment) VerifyMarket(vss *shamir.VSS) error {
+	commitments := fragment.Commitments
+	if commitments == nil || len(commitments.FstCode) == 0 || len(commitments.SndCode) == 0 {
+		return shamir.ErrInvalidMembershipProof
+	}
+	return vss.VerifyMembership([]*big.Int{commitments.FstCode[0], commitments.SndCode[0]}, marketMembers(commitments.Markets), commitments.MarketProof)
+}
+
+// proveMarket returns a MarketProof that the commitments to the secret
+// FstCode and SndCode of the Order, which are the first commitments to their
+// polynomials, commit to its market. The blinding shares are joined to
+// recover the values that the commitments were blinded with. A
+// shamir.ErrInvalidMembershipProof is returned if the market of the Order is
+// not one of the Markets.
+func (order *Order) proveMarket(vss *shamir.VSS, markets [][2]CurrencyCode, fstCodeCommitments, sndCodeCommitments shamir.Commitments, fstCodeBlindings, sndCodeBlindings shamir.Shares) (*shamir.MembershipProof, error) {
+	index := -1
+	for i, market := range markets {
+		if market[0] == order.FstCode && market[1] == order.SndCode {
+			index = i
+			break
+		}
+	}
+	if index < 0 {
+		return nil, shamir.ErrInvalidMembershipProof
+	}
+	commitments := []*big.Int{fstCodeCommitments[0], sndCodeCommitments[0]}
+	blindings := []*big.Int{
+		shamir.Join(vss.Prime, fstCodeBlindings).ToBigInt(),
+		shamir.Join(vss.Prime, sndCodeBlindings).ToBigInt(),
+	}
+	return vss.ProveMembership(commitments, blindings, marketMembers(markets), index)
+}
+
+// marketMembers returns the currency codes of each Market as the values of a
+// member of a MembershipProof.
+func marketMembers(markets [][2]CurrencyCode) [][]*big.Int {
+	members := make([][]*big.Int, len(markets))
+	for i, market := range markets {
+		members[i] = []*big.Int{big.NewInt(int64(market[0])), big.NewInt(int64(market[1]))}
+	}
+	return members
+}
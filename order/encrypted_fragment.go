@@ -0,0 +1,39 @@
+package order
+
+import (
+	"time"
+
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/republicprotocol/republic-go/identity"
+)
+
+// An EncryptedFragment is a signed Fragment that a trader has encrypted for
+// the dark node that it was sent to. The trader gives the EncryptedFragments
+// of an Order to every dark node in the dark pool, so that a dark node that
+// was not running when the Order was opened can receive its Fragment from the
+// rest of the dark pool. Only the dark node with the ID To can decrypt the
+// Ciphertext. The OrderID and the OrderExpiry are public, so that dark nodes
+// can forget an EncryptedFragment when its Order expires.
+type EncryptedFragment struct {
+	To          identity.ID
+	OrderID     ID
+	OrderExpiry time.Time
+	Ciphertext  []byte
+}
+
+// NewEncryptedFragment returns an EncryptedFragment for the dark node with the
+// given ID. The Ciphertext must be the encryption of a signed Fragment of the
+// Order.
+func NewEncryptedFragment(to identity.ID, orderID ID, orderExpiry time.Time, ciphertext []byte) *EncryptedFragment {
+	return &EncryptedFragment{
+		To:          to,
+		OrderID:     orderID,
+		OrderExpiry: orderExpiry,
+		Ciphertext:  ciphertext,
+	}
+}
+
+// Hash returns the Keccak256 hash of an EncryptedFragment.
+func (encryptedFragment *EncryptedFragment) Hash() []byte {
+	return crypto.Keccak256(encryptedFragment.To, encryptedFragment.OrderID, encryptedFragment.Ciphertext)
+}
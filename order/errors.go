@@ -0,0 +1,20 @@
+package order
+
+import (
+	"fmt"
+)
+
+// An UnverifiedFragmentError is used when the signature of a Fragment was not
+// produced by the trader of the Fragment.
+type UnverifiedFragmentError string
+
+// NewUnverifiedFragmentError returns a new UnverifiedFragmentError for a
+// Fragment that could not be verified.
+func NewUnverifiedFragmentError(fragment *Fragment) UnverifiedFragmentError {
+	return UnverifiedFragmentError(fmt.Sprintf("expected fragment = %v to be signed by trader = %v", fragment.ID, fragment.Trader))
+}
+
+// Error implements the Error interface for UnverifiedFragmentError.
+func (err UnverifiedFragmentError) Error() string {
+	return string(err)
+}
@@ -0,0 +1,137 @@
+// Package fixed encodes the prices and volumes of orders as fixed-point
+// numbers, so that they can be secret shared. A fixed-point number with d
+// decimals represents the decimal number x as the integer x * 10^d. Every
+// client that opens orders must use the same encoding, otherwise orders from
+// different clients will not match correctly.
+package fixed
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+
+	"github.com/republicprotocol/republic-go/order"
+	"github.com/republicprotocol/republic-go/shamir"
+	"github.com/republicprotocol/republic-go/stackint"
+)
+
+// Bits is the number of bits in an encoded price or volume. The secure
+// comparison of two orders compares the differences of their prices and
+// volumes, which must be in the range (-2^Bits, 2^Bits).
+const Bits = 64
+
+// PriceDecimals is the number of decimals in an encoded price. Prices are the
+// cost of one unit of the first currency of an order in the second currency.
+// Volumes are encoded using the decimals of their currency.
+const PriceDecimals = 12
+
+// ErrMalformedNumber is returned when a number is not a non-negative decimal
+// number, such as "12" or "0.125".
+var ErrMalformedNumber = errors.New("malformed number")
+
+// ErrPrecision is returned when a number has more decimals than can be
+// encoded. Numbers are never rounded, so that no value is silently lost.
+var ErrPrecision = errors.New("number has too many decimals")
+
+// Encode a decimal number as a fixed-point number with the given decimals.
+// An ErrMalformedNumber is returned if the number cannot be parsed, an
+// ErrPrecision is returned if it has more decimals than can be encoded, and a
+// HeadroomError is returned if the encoded number does not fit in Bits.
+func Encode(number string, decimals int) (stackint.Int1024, error) {
+	integer, fraction := number, ""
+	if i := strings.IndexByte(number, '.'); i >= 0 {
+		integer, fraction = number[:i], number[i+1:]
+	}
+	if (integer == "" && fraction == "") || !isDigits(integer) || !isDigits(fraction) {
+		return stackint.Zero(), ErrMalformedNumber
+	}
+
+	// Trailing zeros do not add precision
+	fraction = strings.TrimRight(fraction, "0")
+	if len(fraction) > decimals {
+		return stackint.Zero(), ErrPrecision
+	}
+	digits := integer + fraction + strings.Repeat("0", decimals-len(fraction))
+
+	value, ok := new(big.Int).SetString(digits, 10)
+	if !ok {
+		return stackint.Zero(), ErrMalformedNumber
+	}
+	if value.BitLen() > Bits {
+		return stackint.Zero(), NewHeadroomError(number)
+	}
+	return stackint.FromBigInt(value)
+}
+
+// Decode a fixed-point number with the given decimals into a decimal number.
+// Trailing zeros are removed from the decimals.
+func Decode(value *stackint.Int1024, decimals int) string {
+	digits := value.ToBigInt().String()
+	if len(digits) <= decimals {
+		digits = strings.Repeat("0", decimals-len(digits)+1) + digits
+	}
+	integer := digits[:len(digits)-decimals]
+	fraction := strings.TrimRight(digits[len(digits)-decimals:], "0")
+	if fraction == "" {
+		return integer
+	}
+	return integer + "." + fraction
+}
+
+// EncodePrice encodes a decimal price using PriceDecimals.
+func EncodePrice(price string) (stackint.Int1024, error) {
+	return Encode(price, PriceDecimals)
+}
+
+// DecodePrice decodes a price that was encoded using EncodePrice.
+func DecodePrice(price *stackint.Int1024) string {
+	return Decode(price, PriceDecimals)
+}
+
+// VerifyHeadroom checks that an encoded value leaves enough headroom in the
+// field for the secure comparison of orders. The value must fit in Bits, and
+// must not be larger than half of the prime, so that the difference between
+// any two encoded values does not wrap around the field and is compared with
+// the correct sign. A HeadroomError is returned if it does not.
+func VerifyHeadroom(value *stackint.Int1024, field *shamir.Field) error {
+	if value.BitLength() > Bits || value.GreaterThan(field.HalfPrime) {
+		return NewHeadroomError(value.String())
+	}
+	return nil
+}
+
+// VerifyOrder checks that the price and volumes of an Order leave enough
+// headroom in the field, using VerifyHeadroom.
+func VerifyOrder(ord *order.Order, field *shamir.Field) error {
+	for _, value := range []*stackint.Int1024{ord.Price, ord.MaxVolume, ord.MinVolume} {
+		if err := VerifyHeadroom(value, field); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// A HeadroomError is used when an encoded value is too large for the secure
+// comparison of orders.
+type HeadroomError string
+
+// NewHeadroomError returns a new HeadroomError for a value that is too large.
+func NewHeadroomError(value string) HeadroomError {
+	return HeadroomError(fmt.Sprintf("expected value = %v to be less than 2^%v and half of the prime", value, Bits))
+}
+
+// Error implements the Error interface for HeadroomError.
+func (err HeadroomError) Error() string {
+	return string(err)
+}
+
+// isDigits returns true if s only contains the digits 0 to 9.
+func isDigits(s string) bool {
+	for _, c := range s {
+		if c < '0' || c > '9' {
+			return false
+		}
+	}
+	return true
+}
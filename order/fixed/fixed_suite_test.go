@@ -0,0 +1,13 @@
+package fixed_test
+
+import (
+	"testing"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+func TestFixed(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "Fixed Suite")
+}
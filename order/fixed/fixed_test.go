@@ -0,0 +1,120 @@
+package fixed_test
+
+import (
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	. "github.com/republicprotocol/republic-go/order/fixed"
+
+	"github.com/republicprotocol/republic-go/order"
+	"github.com/republicprotocol/republic-go/shamir"
+	"github.com/republicprotocol/republic-go/stackint"
+)
+
+var _ = Describe("Fixed-point numbers", func() {
+
+	Context("when encoding numbers", func() {
+
+		It("should encode numbers without losing precision", func() {
+			value, err := Encode("0.07123400", 8)
+			Ω(err).ShouldNot(HaveOccurred())
+			expected := stackint.FromUint(7123400)
+			Ω(value.Cmp(&expected)).Should(Equal(0))
+
+			value, err = Encode("12", 3)
+			Ω(err).ShouldNot(HaveOccurred())
+			expected = stackint.FromUint(12000)
+			Ω(value.Cmp(&expected)).Should(Equal(0))
+
+			value, err = Encode(".5", 1)
+			Ω(err).ShouldNot(HaveOccurred())
+			expected = stackint.FromUint(5)
+			Ω(value.Cmp(&expected)).Should(Equal(0))
+		})
+
+		It("should decode the numbers that were encoded", func() {
+			for _, number := range []string{"0", "1", "0.1", "0.000000001", "123.456", "18446744073.709551615"} {
+				value, err := Encode(number, 9)
+				Ω(err).ShouldNot(HaveOccurred())
+				Ω(Decode(&value, 9)).Should(Equal(number))
+			}
+		})
+
+		It("should return an error for malformed numbers", func() {
+			for _, number := range []string{"", ".", "-1", "1e9", "1.2.3", " 1", "0x10"} {
+				_, err := Encode(number, 9)
+				Ω(err).Should(Equal(ErrMalformedNumber))
+			}
+		})
+
+		It("should return an error for numbers with too many decimals", func() {
+			_, err := Encode("0.1234", 3)
+			Ω(err).Should(Equal(ErrPrecision))
+
+			_, err = Encode("0.1230000", 3)
+			Ω(err).ShouldNot(HaveOccurred())
+		})
+
+		It("should return an error for numbers that do not fit", func() {
+			_, err := Encode("18446744073709551615", 0)
+			Ω(err).ShouldNot(HaveOccurred())
+
+			_, err = Encode("18446744073709551616", 0)
+			Ω(err).Should(Equal(NewHeadroomError("18446744073709551616")))
+
+			_, err = Encode("18446744073.709551616", 9)
+			Ω(err).Should(HaveOccurred())
+		})
+	})
+
+	Context("when encoding prices", func() {
+
+		It("should use the price decimals for prices", func() {
+			price, err := EncodePrice("0.071234")
+			Ω(err).ShouldNot(HaveOccurred())
+			Ω(price.String()).Should(Equal("71234000000"))
+			Ω(DecodePrice(&price)).Should(Equal("0.071234"))
+		})
+	})
+
+	Context("when verifying headroom", func() {
+
+		It("should accept orders with encoded values", func() {
+			field, err := shamir.FieldByName(shamir.FieldTest)
+			Ω(err).ShouldNot(HaveOccurred())
+
+			price, err := EncodePrice("0.071234")
+			Ω(err).ShouldNot(HaveOccurred())
+			volume, err := Encode("10.5", 9)
+			Ω(err).ShouldNot(HaveOccurred())
+			nonce := stackint.Zero()
+			ord := order.NewOrder(order.TypeLimit, order.ParityBuy, time.Now().Add(time.Hour), order.CurrencyCodeETH, order.CurrencyCodeBTC, &price, &volume, &volume, &nonce)
+			Ω(VerifyOrder(ord, field)).ShouldNot(HaveOccurred())
+		})
+
+		It("should return an error for values that are too large", func() {
+			field, err := shamir.FieldByName(shamir.FieldDefault)
+			Ω(err).ShouldNot(HaveOccurred())
+
+			one := stackint.One()
+			large := one.ShiftLeft(Bits)
+			Ω(VerifyHeadroom(&large, field)).Should(Equal(NewHeadroomError(large.String())))
+
+			nonce := stackint.Zero()
+			ord := order.NewOrder(order.TypeLimit, order.ParityBuy, time.Now().Add(time.Hour), order.CurrencyCodeETH, order.CurrencyCodeBTC, &one, &large, &one, &nonce)
+			Ω(VerifyOrder(ord, field)).Should(HaveOccurred())
+		})
+
+		It("should return an error for values that are larger than half of the prime", func() {
+			prime := stackint.FromUint(1000003)
+			field, err := shamir.NewField(&prime)
+			Ω(err).ShouldNot(HaveOccurred())
+
+			half := stackint.FromUint(500001)
+			Ω(VerifyHeadroom(&half, field)).ShouldNot(HaveOccurred())
+			large := stackint.FromUint(500002)
+			Ω(VerifyHeadroom(&large, field)).Should(HaveOccurred())
+		})
+	})
+})
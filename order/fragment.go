@@ -2,15 +2,19 @@ package order
 
 import (
 	"bytes"
-	"encoding/binary"
 	"time"
 
 	"github.com/ethereum/go-ethereum/crypto"
 	"github.com/jbenet/go-base58"
+	"github.com/republicprotocol/republic-go/canonical"
 	"github.com/republicprotocol/republic-go/identity"
 	"github.com/republicprotocol/republic-go/shamir"
+	"github.com/republicprotocol/republic-go/stackint"
 )
 
+// fragmentTag identifies the canonical encoding of a Fragment.
+var fragmentTag = []byte("Republic Protocol: order fragment")
+
 // An FragmentID is the Keccak256 hash of a Fragment.
 type FragmentID []byte
 
@@ -25,50 +29,74 @@ func (id FragmentID) String() string {
 }
 
 // A Fragment is a secret share of an Order, created using Shamir's secret
-// sharing on the secure fields in an Order.
+// sharing on the secure fields in an Order. The Trader is the identity of the
+// trader that signed the Fragment, and the Field identifies the finite field
+// in which the Order was split. The type, parity, expiry, time in force,
+// nonce, and open time of the Order are public. Fragments created using
+// verifiable secret sharing also hold the Commitments that their shares can be
+// verified against.
 type Fragment struct {
 	Signature identity.Signature
 	ID        FragmentID
+	Trader    identity.ID
+	Field     shamir.FieldID
 
-	OrderID     ID
-	OrderType   Type
-	OrderParity Parity
-	OrderExpiry time.Time
+	OrderID          ID
+	OrderType        Type
+	OrderParity      Parity
+	OrderExpiry      time.Time
+	OrderTimeInForce TimeInForce
+	OrderNonce       *stackint.Int1024
+	OrderOpenTime    time.Time
 
 	FstCodeShare   shamir.Share
 	SndCodeShare   shamir.Share
 	PriceShare     shamir.Share
 	MaxVolumeShare shamir.Share
 	MinVolumeShare shamir.Share
+
+	Commitments *Commitments
 }
 
-// NewFragment returns a new Fragment and computes the FragmentID.
-func NewFragment(orderID ID, orderType Type, orderParity Parity, fstCodeShare, sndCodeShare, priceShare, maxVolumeShare, minVolumeShare shamir.Share) *Fragment {
+// NewFragment returns a new Fragment and computes the FragmentID. The Fragment
+// has no nonce and no Commitments, so its encoding cannot fail.
+func NewFragment(orderID ID, orderType Type, orderParity Parity, orderExpiry time.Time, fstCodeShare, sndCodeShare, priceShare, maxVolumeShare, minVolumeShare shamir.Share) *Fragment {
 	fragment := &Fragment{
 		OrderID:        orderID,
 		OrderType:      orderType,
 		OrderParity:    orderParity,
+		OrderExpiry:    orderExpiry,
 		FstCodeShare:   fstCodeShare,
 		SndCodeShare:   sndCodeShare,
 		PriceShare:     priceShare,
 		MaxVolumeShare: maxVolumeShare,
 		MinVolumeShare: minVolumeShare,
 	}
-	fragment.ID = FragmentID(fragment.Hash())
+	fragment.ID, _ = fragment.Hash()
 	return fragment
 }
 
-// Hash returns the Keccak256 hash of a Fragment. This hash is used to create
-// the FragmentID and signature for a Fragment.
-func (fragment *Fragment) Hash() []byte {
-	return crypto.Keccak256(fragment.Bytes())
+// Hash returns the Keccak256 hash of the canonical encoding of a Fragment.
+// This hash is used to create the FragmentID and signature for a Fragment. An
+// error is returned if the Fragment cannot be encoded.
+func (fragment *Fragment) Hash() (FragmentID, error) {
+	data, err := fragment.MarshalBinary()
+	if err != nil {
+		return nil, err
+	}
+	return FragmentID(crypto.Keccak256(data)), nil
 }
 
 // Sign signs the fragment using the provided keypair, and assigns it the the fragments's
-// Signature field.
+// Signature field. The Trader of the fragment is set to the ID of the keypair.
+// An error is returned if the Fragment cannot be encoded.
 func (fragment *Fragment) Sign(keyPair identity.KeyPair) error {
-	var err error
-	fragment.Signature, err = keyPair.Sign(fragment)
+	hash, err := fragment.Hash()
+	if err != nil {
+		return err
+	}
+	fragment.Trader = keyPair.ID()
+	fragment.Signature, err = keyPair.Sign(signableHash(hash))
 	return err
 }
 
@@ -85,7 +113,36 @@ func SignFragments(keyPair identity.KeyPair, fragments []*Fragment) error {
 // VerifySignature verifies that the Signature field has been signed by the provided
 // ID's private key, returning an error if the signature is invalid
 func (fragment *Fragment) VerifySignature(ID identity.ID) error {
-	return identity.VerifySignature(fragment, fragment.Signature, ID)
+	hash, err := fragment.Hash()
+	if err != nil {
+		return err
+	}
+	return identity.VerifySignature(signableHash(hash), fragment.Signature, ID)
+}
+
+// Verify recovers the signer of the Fragment and checks that it is the Trader
+// of the Fragment. An UnverifiedFragmentError is returned if the Fragment is
+// not signed, if it cannot be encoded, or if it was signed by anyone other
+// than the Trader.
+func (fragment *Fragment) Verify() error {
+	if len(fragment.Trader) == 0 {
+		return NewUnverifiedFragmentError(fragment)
+	}
+	signer, err := fragment.RecoverSigner()
+	if err != nil || !bytes.Equal(signer, fragment.Trader) {
+		return NewUnverifiedFragmentError(fragment)
+	}
+	return nil
+}
+
+// RecoverSigner returns the identity that signed the Fragment. An error is
+// returned if the Fragment cannot be encoded, or if the Signature is invalid.
+func (fragment *Fragment) RecoverSigner() (identity.ID, error) {
+	hash, err := fragment.Hash()
+	if err != nil {
+		return nil, err
+	}
+	return identity.RecoverSigner(signableHash(hash), fragment.Signature)
 }
 
 // VerifyFragmentSignatures maps over an array of fragments,
@@ -99,36 +156,50 @@ func VerifyFragmentSignatures(ID identity.ID, fragments []*Fragment) error {
 	return nil
 }
 
-// Bytes returns a Fragment serialized into a bytes.
-func (fragment *Fragment) Bytes() []byte {
-	buf := new(bytes.Buffer)
-
-	binary.Write(buf, binary.LittleEndian, fragment.OrderID)
-	binary.Write(buf, binary.LittleEndian, fragment.OrderType)
-	binary.Write(buf, binary.LittleEndian, fragment.OrderParity)
-	binary.Write(buf, binary.LittleEndian, fragment.OrderExpiry)
-
-	binary.Write(buf, binary.LittleEndian, fragment.FstCodeShare.Key)
-	binary.Write(buf, binary.LittleEndian, fragment.FstCodeShare.Value.Bytes())
-	binary.Write(buf, binary.LittleEndian, fragment.SndCodeShare.Key)
-	binary.Write(buf, binary.LittleEndian, fragment.SndCodeShare.Value.Bytes())
-	binary.Write(buf, binary.LittleEndian, fragment.PriceShare.Key)
-	binary.Write(buf, binary.LittleEndian, fragment.PriceShare.Value.Bytes())
-	binary.Write(buf, binary.LittleEndian, fragment.MaxVolumeShare.Key)
-	binary.Write(buf, binary.LittleEndian, fragment.MaxVolumeShare.Value.Bytes())
-	binary.Write(buf, binary.LittleEndian, fragment.MinVolumeShare.Key)
-	binary.Write(buf, binary.LittleEndian, fragment.MinVolumeShare.Value.Bytes())
-
-	return buf.Bytes()
+// MarshalBinary implements the encoding.BinaryMarshaler interface. It returns
+// the canonical encoding of a Fragment, which covers every field except the
+// Signature, the ID, and the Trader, which is recovered from the Signature. An
+// error is returned if any of the Commitments is nil.
+func (fragment *Fragment) MarshalBinary() ([]byte, error) {
+	encoder := canonical.NewEncoder(fragmentTag)
+	encoder.WriteBytes(fragment.Field)
+	encoder.WriteBytes(fragment.OrderID)
+	encoder.WriteInt64(int64(fragment.OrderType))
+	encoder.WriteInt64(int64(fragment.OrderParity))
+	encoder.WriteTime(fragment.OrderExpiry)
+	encoder.WriteInt64(int64(fragment.OrderTimeInForce))
+	encoder.WriteFlag(fragment.OrderNonce != nil)
+	if fragment.OrderNonce != nil {
+		encoder.WriteInt1024(fragment.OrderNonce)
+	}
+	encoder.WriteFlag(!fragment.OrderOpenTime.IsZero())
+	if !fragment.OrderOpenTime.IsZero() {
+		encoder.WriteTime(fragment.OrderOpenTime)
+	}
+	encoder.WriteShare(fragment.FstCodeShare)
+	encoder.WriteShare(fragment.SndCodeShare)
+	encoder.WriteShare(fragment.PriceShare)
+	encoder.WriteShare(fragment.MaxVolumeShare)
+	encoder.WriteShare(fragment.MinVolumeShare)
+	encoder.WriteFlag(fragment.Commitments != nil)
+	if fragment.Commitments != nil {
+		fragment.Commitments.write(encoder)
+	}
+	return encoder.Bytes()
 }
 
-// Equal returns an equality check between two Orders.
+// Equal returns an equality check between two Fragments.
 func (fragment *Fragment) Equal(other *Fragment) bool {
 	return fragment.ID.Equal(other.ID) &&
+		bytes.Equal(fragment.Trader, other.Trader) &&
+		fragment.Field.Equal(other.Field) &&
 		fragment.OrderID.Equal(other.OrderID) &&
 		fragment.OrderType == other.OrderType &&
 		fragment.OrderParity == other.OrderParity &&
-		fragment.OrderExpiry.Equal(other.OrderExpiry) &&
+		fragment.OrderExpiry.Unix() == other.OrderExpiry.Unix() &&
+		fragment.OrderTimeInForce == other.OrderTimeInForce &&
+		equalNonces(fragment.OrderNonce, other.OrderNonce) &&
+		fragment.OrderOpenTime.Unix() == other.OrderOpenTime.Unix() &&
 		fragment.FstCodeShare.Value.Cmp(&other.FstCodeShare.Value) == 0 &&
 		fragment.SndCodeShare.Value.Cmp(&other.SndCodeShare.Value) == 0 &&
 		fragment.PriceShare.Value.Cmp(&other.PriceShare.Value) == 0 &&
@@ -136,16 +207,73 @@ func (fragment *Fragment) Equal(other *Fragment) bool {
 		fragment.MinVolumeShare.Value.Cmp(&other.MinVolumeShare.Value) == 0
 }
 
+func equalNonces(nonce, other *stackint.Int1024) bool {
+	if nonce == nil || other == nil {
+		return nonce == other
+	}
+	return nonce.Cmp(other) == 0
+}
+
+// IsExpired returns true if the Order that the Fragment belongs to has expired
+// at the given time, otherwise it returns false.
+func (fragment *Fragment) IsExpired(now time.Time) bool {
+	return !now.Before(fragment.OrderExpiry)
+}
+
+// ResidualID returns the ID of the residual Order that remains after an Order
+// is partially filled by another Order.
+func ResidualID(orderID, filledOrderID ID) ID {
+	return ID(crypto.Keccak256([]byte("residual"), orderID, filledOrderID))
+}
+
+// Residual returns a Fragment of the residual Order that remains after the
+// Order of this Fragment has been partially filled by another Order. The
+// maximum volume of the residual Order is reduced by the given share of the
+// maximum volume of the other Order, and all other shares are unchanged. The
+// residual Order has a new ID, so that it is compared against all other orders
+// again. The Fragment is not signed, but it keeps the Trader, the Field, the
+// time in force, the nonce, and the open time of this Fragment. The residual
+// Order has no Commitments, because it is never opened by a trader, so its
+// encoding cannot fail.
+func (fragment *Fragment) Residual(filledOrderID ID, filledMaxVolumeShare shamir.Share, prime *stackint.Int1024) *Fragment {
+	maxVolumeShare := shamir.Share{
+		Key:   fragment.MaxVolumeShare.Key,
+		Value: fragment.MaxVolumeShare.Value.CtSubModulo(&filledMaxVolumeShare.Value, prime),
+	}
+	residual := NewFragment(ResidualID(fragment.OrderID, filledOrderID), fragment.OrderType, fragment.OrderParity, fragment.OrderExpiry, fragment.FstCodeShare, fragment.SndCodeShare, fragment.PriceShare, maxVolumeShare, fragment.MinVolumeShare)
+	residual.Trader = fragment.Trader
+	residual.Field = fragment.Field
+	residual.OrderTimeInForce = fragment.OrderTimeInForce
+	residual.OrderNonce = fragment.OrderNonce
+	residual.OrderOpenTime = fragment.OrderOpenTime
+	residual.ID, _ = residual.Hash()
+	return residual
+}
+
+// signableHash is a hash that has already been computed, so that it can be
+// signed by an identity.KeyPair.
+type signableHash []byte
+
+// Hash implements the identity.Signable interface.
+func (hash signableHash) Hash() []byte {
+	return hash
+}
+
 // IsCompatible returns true when two Fragments are compatible for a
 // computation, otherwise it returns false. For a Fragment to be compatible
 // with another Fragment it must have a diferrent ID, it must have a different
-// order ID, it must have a different parity, it must have a different owner,
-// and all secret sharing fields must have the same secret sharing index.
+// order ID, it must have a different parity, it must have the same type, it
+// must have a different trader, it must have been split in the same finite
+// field, and all secret sharing fields must have the same secret sharing
+// index. The Traders are not verified, so Fragments must be verified before
+// they are used.
 func (fragment *Fragment) IsCompatible(other *Fragment) bool {
-	// TODO: Check that signatories are different
 	return !fragment.ID.Equal(other.ID) &&
 		!fragment.OrderID.Equal(other.OrderID) &&
 		fragment.OrderParity != other.OrderParity &&
+		fragment.OrderType == other.OrderType &&
+		!bytes.Equal(fragment.Trader, other.Trader) &&
+		fragment.Field.Equal(other.Field) &&
 		fragment.FstCodeShare.Key == other.FstCodeShare.Key &&
 		fragment.SndCodeShare.Key == other.SndCodeShare.Key &&
 		fragment.PriceShare.Key == other.PriceShare.Key &&
@@ -1,9 +1,11 @@
 package order_test
 
 import (
+	"encoding/hex"
 	"time"
 
 	"github.com/republicprotocol/republic-go/identity"
+	"github.com/republicprotocol/republic-go/shamir"
 	"github.com/republicprotocol/republic-go/stackint"
 
 	. "github.com/onsi/ginkgo"
@@ -47,6 +49,65 @@ var _ = Describe("Order fragments", func() {
 		})
 	})
 
+	Context("when checking expiry", func() {
+
+		It("should copy the expiry of the order", func() {
+			nonce := stackint.FromUint(0)
+			expiry := time.Now().Add(time.Hour)
+			fragments, err := NewOrder(TypeLimit, ParityBuy, expiry, CurrencyCodeBTC, CurrencyCodeETH, &price, &maxVolume, &minVolume, &nonce).Split(n, k, prime)
+			Ω(err).ShouldNot(HaveOccurred())
+			for i := range fragments {
+				Ω(fragments[i].OrderExpiry.Equal(expiry)).Should(BeTrue())
+				Ω(fragments[i].IsExpired(time.Now())).Should(BeFalse())
+				Ω(fragments[i].IsExpired(expiry)).Should(BeTrue())
+			}
+		})
+
+		It("should return different IDs for different expiries", func() {
+			nonce := stackint.FromUint(0)
+			lhs, err := NewOrder(TypeLimit, ParityBuy, time.Now().Add(time.Hour), CurrencyCodeBTC, CurrencyCodeETH, &price, &maxVolume, &minVolume, &nonce).Split(n, k, prime)
+			Ω(err).ShouldNot(HaveOccurred())
+			rhs, err := NewOrder(TypeLimit, ParityBuy, time.Now().Add(2*time.Hour), CurrencyCodeBTC, CurrencyCodeETH, &price, &maxVolume, &minVolume, &nonce).Split(n, k, prime)
+			Ω(err).ShouldNot(HaveOccurred())
+			Ω(lhs[0].OrderID.Equal(rhs[0].OrderID)).Should(BeFalse())
+			Ω(lhs[0].ID.Equal(rhs[0].ID)).Should(BeFalse())
+		})
+	})
+
+	Context("when computing residual fragments", func() {
+
+		It("should reduce the maximum volume by the filled volume", func() {
+			nonce := stackint.FromUint(0)
+			filledVolume := stackint.FromUint(400)
+			fragments, err := NewOrder(TypeLimit, ParityBuy, time.Now().Add(time.Hour), CurrencyCodeBTC, CurrencyCodeETH, &price, &maxVolume, &minVolume, &nonce).Split(n, k, prime)
+			Ω(err).ShouldNot(HaveOccurred())
+			filledVolumeShares, err := shamir.Split(n, k, prime, &filledVolume)
+			Ω(err).ShouldNot(HaveOccurred())
+
+			filledOrderID := ID("filled")
+			residuals := make([]*Fragment, n)
+			maxVolumeShares := make(shamir.Shares, n)
+			for i := range fragments {
+				residuals[i] = fragments[i].Residual(filledOrderID, filledVolumeShares[i], prime)
+				maxVolumeShares[i] = residuals[i].MaxVolumeShare
+				Ω(residuals[i].OrderID.Equal(ResidualID(fragments[i].OrderID, filledOrderID))).Should(BeTrue())
+				Ω(residuals[i].OrderParity).Should(Equal(fragments[i].OrderParity))
+				Ω(residuals[i].PriceShare).Should(Equal(fragments[i].PriceShare))
+				Ω(residuals[i].MinVolumeShare).Should(Equal(fragments[i].MinVolumeShare))
+				Ω(residuals[i].Field.Equal(fragments[i].Field)).Should(BeTrue())
+			}
+			residualVolume := maxVolume.Sub(&filledVolume)
+			Ω(shamir.Join(prime, maxVolumeShares[:k]).Cmp(&residualVolume)).Should(Equal(0))
+		})
+
+		It("should return different order IDs for different fills", func() {
+			lhs := ResidualID(ID("order"), ID("filled"))
+			rhs := ResidualID(ID("order"), ID("other"))
+			Ω(lhs.Equal(rhs)).Should(BeFalse())
+			Ω(lhs.Equal(ResidualID(ID("order"), ID("filled")))).Should(BeTrue())
+		})
+	})
+
 	Context("when testing for equality", func() {
 
 		It("should return true for order fragments IDs that are equal", func() {
@@ -92,23 +153,108 @@ var _ = Describe("Order fragments", func() {
 				}
 			}
 		})
+
+		It("should return false for orders fragments with a different trader, nonce, or open time", func() {
+			nonce := stackint.FromUint(0)
+
+			fragments, err := NewOrder(TypeLimit, ParityBuy, time.Now().Add(time.Hour), CurrencyCodeBTC, CurrencyCodeETH, &price, &maxVolume, &minVolume, &nonce).Split(n, k, prime)
+			Ω(err).ShouldNot(HaveOccurred())
+
+			other := *fragments[0]
+			other.Trader = identity.ID("trader")
+			Ω(fragments[0].Equal(&other)).Should(Equal(false))
+
+			otherNonce := stackint.FromUint(1)
+			other = *fragments[0]
+			other.OrderNonce = &otherNonce
+			Ω(fragments[0].Equal(&other)).Should(Equal(false))
+
+			other = *fragments[0]
+			other.OrderNonce = nil
+			Ω(fragments[0].Equal(&other)).Should(Equal(false))
+
+			other = *fragments[0]
+			other.OrderOpenTime = time.Unix(1500000000, 0)
+			Ω(fragments[0].Equal(&other)).Should(Equal(false))
+		})
 	})
 	Context("when testing for compatibility", func() {
 
+		buyer, err := identity.NewKeyPair()
+		if err != nil {
+			panic(err)
+		}
+		seller, err := identity.NewKeyPair()
+		if err != nil {
+			panic(err)
+		}
+
 		It("should return true for pairwise order fragments from orders with different parity", func() {
 			nonce := stackint.FromUint(0)
 
 			lhs, err := NewOrder(TypeLimit, ParityBuy, time.Now().Add(time.Hour), CurrencyCodeBTC, CurrencyCodeETH, &price, &maxVolume, &minVolume, &nonce).Split(n, k, prime)
 			Ω(err).ShouldNot(HaveOccurred())
+			Ω(SignFragments(buyer, lhs)).ShouldNot(HaveOccurred())
 
 			nonce = stackint.FromUint(1)
 			rhs, err := NewOrder(TypeLimit, ParitySell, time.Now().Add(time.Hour), CurrencyCodeBTC, CurrencyCodeETH, &price, &maxVolume, &minVolume, &nonce).Split(n, k, prime)
 			Ω(err).ShouldNot(HaveOccurred())
+			Ω(SignFragments(seller, rhs)).ShouldNot(HaveOccurred())
 			for i := int64(0); i < n; i++ {
 				Ω(lhs[i].IsCompatible(rhs[i])).Should(Equal(true))
 			}
 		})
 
+		It("should return false for pairwise order fragments from orders with the same trader", func() {
+			nonce := stackint.FromUint(0)
+
+			lhs, err := NewOrder(TypeLimit, ParityBuy, time.Now().Add(time.Hour), CurrencyCodeBTC, CurrencyCodeETH, &price, &maxVolume, &minVolume, &nonce).Split(n, k, prime)
+			Ω(err).ShouldNot(HaveOccurred())
+			Ω(SignFragments(buyer, lhs)).ShouldNot(HaveOccurred())
+
+			nonce = stackint.FromUint(1)
+			rhs, err := NewOrder(TypeLimit, ParitySell, time.Now().Add(time.Hour), CurrencyCodeBTC, CurrencyCodeETH, &price, &maxVolume, &minVolume, &nonce).Split(n, k, prime)
+			Ω(err).ShouldNot(HaveOccurred())
+			Ω(SignFragments(buyer, rhs)).ShouldNot(HaveOccurred())
+			for i := int64(0); i < n; i++ {
+				Ω(lhs[i].IsCompatible(rhs[i])).Should(Equal(false))
+			}
+		})
+
+		It("should return false for pairwise order fragments from orders split in different fields", func() {
+			field, err := shamir.FieldByName(shamir.FieldTest)
+			Ω(err).ShouldNot(HaveOccurred())
+			nonce := stackint.FromUint(0)
+
+			lhs, err := NewOrder(TypeLimit, ParityBuy, time.Now().Add(time.Hour), CurrencyCodeBTC, CurrencyCodeETH, &price, &maxVolume, &minVolume, &nonce).Split(n, k, prime)
+			Ω(err).ShouldNot(HaveOccurred())
+			Ω(SignFragments(buyer, lhs)).ShouldNot(HaveOccurred())
+
+			nonce = stackint.FromUint(1)
+			rhs, err := NewOrder(TypeLimit, ParitySell, time.Now().Add(time.Hour), CurrencyCodeBTC, CurrencyCodeETH, &price, &maxVolume, &minVolume, &nonce).Split(n, k, field.Prime)
+			Ω(err).ShouldNot(HaveOccurred())
+			Ω(SignFragments(seller, rhs)).ShouldNot(HaveOccurred())
+			for i := int64(0); i < n; i++ {
+				Ω(lhs[i].IsCompatible(rhs[i])).Should(Equal(false))
+			}
+		})
+
+		It("should return false for pairwise order fragments from orders with different types", func() {
+			nonce := stackint.FromUint(0)
+
+			lhs, err := NewOrder(TypeIBBO, ParityBuy, time.Now().Add(time.Hour), CurrencyCodeBTC, CurrencyCodeETH, &price, &maxVolume, &minVolume, &nonce).Split(n, k, prime)
+			Ω(err).ShouldNot(HaveOccurred())
+			Ω(SignFragments(buyer, lhs)).ShouldNot(HaveOccurred())
+
+			nonce = stackint.FromUint(1)
+			rhs, err := NewOrder(TypeLimit, ParitySell, time.Now().Add(time.Hour), CurrencyCodeBTC, CurrencyCodeETH, &price, &maxVolume, &minVolume, &nonce).Split(n, k, prime)
+			Ω(err).ShouldNot(HaveOccurred())
+			Ω(SignFragments(seller, rhs)).ShouldNot(HaveOccurred())
+			for i := int64(0); i < n; i++ {
+				Ω(lhs[i].IsCompatible(rhs[i])).Should(Equal(false))
+			}
+		})
+
 		It("should return false for pairwise order fragments from orders with equal parity", func() {
 			nonce := stackint.FromUint(0)
 
@@ -188,6 +334,31 @@ var _ = Describe("Order fragments", func() {
 			Ω(err).Should(Equal(identity.ErrInvalidSignature))
 		})
 
+		It("should verify the trader that signed the fragments", func() {
+			err = SignFragments(keyPair, fragments)
+			Ω(err).ShouldNot(HaveOccurred())
+			for _, fragment := range fragments {
+				Ω(fragment.Trader).Should(Equal(keyPair.ID()))
+				Ω(fragment.Verify()).ShouldNot(HaveOccurred())
+			}
+		})
+
+		It("should return an UnverifiedFragmentError for fragments that were not signed by their trader", func() {
+			other, err := identity.NewKeyPair()
+			Ω(err).ShouldNot(HaveOccurred())
+			err = SignFragments(keyPair, fragments)
+			Ω(err).ShouldNot(HaveOccurred())
+
+			fragments[0].Trader = other.ID()
+			Ω(fragments[0].Verify()).Should(BeAssignableToTypeOf(UnverifiedFragmentError("")))
+
+			fragments[1].Signature = nil
+			Ω(fragments[1].Verify()).Should(BeAssignableToTypeOf(UnverifiedFragmentError("")))
+
+			fragments[2].Trader = nil
+			Ω(fragments[2].Verify()).Should(BeAssignableToTypeOf(UnverifiedFragmentError("")))
+		})
+
 		It("should error for invalid data", func() {
 
 			nonce2 := stackint.One()
@@ -206,4 +377,98 @@ var _ = Describe("Order fragments", func() {
 		})
 	})
 
+	Context("when encoding order fragments", func() {
+
+		share := func(value uint) shamir.Share {
+			return shamir.Share{Key: 1, Value: stackint.FromUint(value)}
+		}
+
+		It("should return the golden encoding and ID", func() {
+			fragment := NewFragment(ID("order"), TypeLimit, ParityBuy, time.Unix(1500000000, 0), share(1), share(2), share(10), share(1000), share(100))
+			fragment.Field = shamir.FieldID("field")
+			fragment.OrderTimeInForce = TimeInForceIOC
+			data, err := fragment.MarshalBinary()
+			Ω(err).ShouldNot(HaveOccurred())
+			Ω(hex.EncodeToString(data)).Should(Equal(goldenFragmentEncoding))
+			id, err := fragment.Hash()
+			Ω(err).ShouldNot(HaveOccurred())
+			Ω(hex.EncodeToString(id)).Should(Equal(goldenFragmentID))
+		})
+
+		It("should write the nonce after its flag", func() {
+			nonce := stackint.FromUint(42)
+			fragment := NewFragment(ID("order"), TypeLimit, ParityBuy, time.Unix(1500000000, 0), share(1), share(2), share(10), share(1000), share(100))
+			fragment.Field = shamir.FieldID("field")
+			fragment.OrderTimeInForce = TimeInForceIOC
+			fragment.OrderNonce = &nonce
+			data, err := fragment.MarshalBinary()
+			Ω(err).ShouldNot(HaveOccurred())
+			Ω(hex.EncodeToString(data)).Should(Equal(goldenFragmentPublicFields + "01" + "010000002a" + "00" + goldenFragmentShares))
+		})
+
+		It("should write the open time after its flag", func() {
+			nonce := stackint.FromUint(42)
+			fragment := NewFragment(ID("order"), TypeLimit, ParityBuy, time.Unix(1500000000, 0), share(1), share(2), share(10), share(1000), share(100))
+			fragment.Field = shamir.FieldID("field")
+			fragment.OrderTimeInForce = TimeInForceIOC
+			fragment.OrderNonce = &nonce
+			fragment.OrderOpenTime = time.Unix(1500000000, 0)
+			data, err := fragment.MarshalBinary()
+			Ω(err).ShouldNot(HaveOccurred())
+			Ω(hex.EncodeToString(data)).Should(Equal(goldenFragmentPublicFields + "01" + "010000002a" + "01" + "002f685900000000" + goldenFragmentShares))
+		})
+
+		It("should return an error for fragments that cannot be encoded", func() {
+			fragment := NewFragment(ID("order"), TypeLimit, ParityBuy, time.Unix(1500000000, 0), share(1), share(2), share(10), share(1000), share(100))
+			fragment.Commitments = &Commitments{
+				FstCode:           shamir.Commitments{nil},
+				FstCodeBlinding:   share(0),
+				SndCodeBlinding:   share(0),
+				PriceBlinding:     share(0),
+				MaxVolumeBlinding: share(0),
+				MinVolumeBlinding: share(0),
+			}
+			_, err := fragment.Hash()
+			Ω(err).Should(HaveOccurred())
+
+			keyPair, err := identity.NewKeyPair()
+			Ω(err).ShouldNot(HaveOccurred())
+			Ω(fragment.Sign(keyPair)).Should(HaveOccurred())
+		})
+
+		It("should cover the expiry and the commitments", func() {
+			nonce := stackint.Zero()
+			expiry := time.Unix(1500000000, 0)
+			vss, err := shamir.NewVSS(prime)
+			Ω(err).ShouldNot(HaveOccurred())
+			fragments, err := NewOrder(TypeLimit, ParityBuy, expiry, CurrencyCodeBTC, CurrencyCodeETH, &price, &maxVolume, &minVolume, &nonce).SplitVerifiable(n, k, vss, nil)
+			Ω(err).ShouldNot(HaveOccurred())
+
+			other := *fragments[0]
+			other.OrderExpiry = expiry.Add(time.Second)
+			Ω(other.Hash()).ShouldNot(Equal(fragments[0].ID))
+
+			other = *fragments[0]
+			other.Commitments = nil
+			Ω(other.Hash()).ShouldNot(Equal(fragments[0].ID))
+
+			other = *fragments[0]
+			commitments := *fragments[0].Commitments
+			commitments.PriceBlinding = commitments.MinVolumeBlinding
+			other.Commitments = &commitments
+			Ω(other.Hash()).ShouldNot(Equal(fragments[0].ID))
+		})
+	})
+
 })
+
+// goldenFragmentPublicFields is the encoding of the public fields of a
+// Fragment that are written before its optional nonce and open time, and
+// goldenFragmentShares is the encoding of its shares and absent Commitments.
+const goldenFragmentPublicFields = "032100000052657075626c69632050726f746f636f6c3a206f7264657220667261676d656e74050000006669656c64050000006f7264657202000000000000000100000000000000002f6859000000000100000000000000"
+
+const goldenFragmentShares = "01000000000000000100000001010000000000000001000000020100000000000000010000000a01000000000000000200000003e80100000000000000010000006400"
+
+const goldenFragmentEncoding = goldenFragmentPublicFields + "00" + "00" + goldenFragmentShares
+
+const goldenFragmentID = "2d2387b6549d8b046cdc22cce3f9eb13e8016af5ea603f3f5ba9abb6323a48ea"
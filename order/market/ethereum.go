@@ -0,0 +1,78 @@
+package market
+
+import (
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/republicprotocol/republic-go/contracts/bindings"
+	"github.com/republicprotocol/republic-go/contracts/connection"
+	"github.com/republicprotocol/republic-go/order"
+)
+
+// An EthereumSource is a Source that lists the Currencies and Markets of the
+// MarketRegistry contract on Ethereum. New Currencies and Markets can be
+// listed by the owner of the contract, and are loaded the next time that the
+// EthereumSource is loaded into a Registry. Currencies and Markets are listed
+// in the order in which they were added to the contract, so a Currency or
+// Market that was added again replaces the earlier one.
+type EthereumSource struct {
+	callOpts *bind.CallOpts
+	binding  *bindings.MarketRegistryCaller
+}
+
+// NewEthereumSource returns an EthereumSource that uses the MarketRegistry
+// contract deployed at the given address.
+func NewEthereumSource(clientDetails *connection.ClientDetails, callOpts *bind.CallOpts, address common.Address) (*EthereumSource, error) {
+	contract, err := bindings.NewMarketRegistryCaller(address, bind.ContractCaller(clientDetails.Client))
+	if err != nil {
+		return nil, err
+	}
+	return &EthereumSource{
+		callOpts: callOpts,
+		binding:  contract,
+	}, nil
+}
+
+// ListCurrencies implements the Source interface.
+func (source *EthereumSource) ListCurrencies() ([]Currency, error) {
+	n, err := source.binding.NumberOfCurrencies(source.callOpts)
+	if err != nil {
+		return nil, err
+	}
+	currencies := []Currency{}
+	for i := int64(0); i < n.Int64(); i++ {
+		currency, err := source.binding.GetCurrency(source.callOpts, big.NewInt(i))
+		if err != nil {
+			return nil, err
+		}
+		currencies = append(currencies, Currency{
+			Code:     order.CurrencyCode(currency.Code),
+			Symbol:   currency.Symbol,
+			Decimals: int(currency.Decimals),
+		})
+	}
+	return currencies, nil
+}
+
+// ListMarkets implements the Source interface.
+func (source *EthereumSource) ListMarkets() ([]Market, error) {
+	n, err := source.binding.NumberOfMarkets(source.callOpts)
+	if err != nil {
+		return nil, err
+	}
+	markets := []Market{}
+	for i := int64(0); i < n.Int64(); i++ {
+		market, err := source.binding.GetMarket(source.callOpts, big.NewInt(i))
+		if err != nil {
+			return nil, err
+		}
+		markets = append(markets, Market{
+			FstCode:   order.CurrencyCode(market.FstCode),
+			SndCode:   order.CurrencyCode(market.SndCode),
+			TickSize:  market.TickSize,
+			MinVolume: market.MinVolume,
+		})
+	}
+	return markets, nil
+}
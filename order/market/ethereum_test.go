@@ -0,0 +1,106 @@
+package market_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"math/big"
+	"strings"
+
+	ethereum "github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	. "github.com/republicprotocol/republic-go/order/market"
+
+	"github.com/republicprotocol/republic-go/contracts/bindings"
+	"github.com/republicprotocol/republic-go/contracts/connection"
+	"github.com/republicprotocol/republic-go/order"
+)
+
+// mockMarketRegistry is a connection.Client that answers calls to the
+// MarketRegistry contract from the Currencies and Markets that it holds. All
+// other methods of the connection.Client are not implemented.
+type mockMarketRegistry struct {
+	connection.Client
+
+	abi        abi.ABI
+	currencies []Currency
+	markets    []Market
+}
+
+func newMockMarketRegistry(currencies []Currency, markets []Market) *mockMarketRegistry {
+	parsed, err := abi.JSON(strings.NewReader(bindings.MarketRegistryABI))
+	if err != nil {
+		panic(err)
+	}
+	return &mockMarketRegistry{
+		abi:        parsed,
+		currencies: currencies,
+		markets:    markets,
+	}
+}
+
+func (registry *mockMarketRegistry) CodeAt(ctx context.Context, account common.Address, blockNumber *big.Int) ([]byte, error) {
+	return []byte{0x1}, nil
+}
+
+func (registry *mockMarketRegistry) CallContract(ctx context.Context, call ethereum.CallMsg, blockNumber *big.Int) ([]byte, error) {
+	for _, method := range registry.abi.Methods {
+		if !bytes.Equal(method.Id(), call.Data[:4]) {
+			continue
+		}
+		switch method.Name {
+		case "numberOfCurrencies":
+			return method.Outputs.Pack(big.NewInt(int64(len(registry.currencies))))
+		case "numberOfMarkets":
+			return method.Outputs.Pack(big.NewInt(int64(len(registry.markets))))
+		case "getCurrency":
+			currency := registry.currencies[new(big.Int).SetBytes(call.Data[4:]).Int64()]
+			return method.Outputs.Pack(uint32(currency.Code), currency.Symbol, uint8(currency.Decimals))
+		case "getMarket":
+			market := registry.markets[new(big.Int).SetBytes(call.Data[4:]).Int64()]
+			return method.Outputs.Pack(uint32(market.FstCode), uint32(market.SndCode), market.TickSize, market.MinVolume)
+		}
+	}
+	return nil, errors.New("unknown method")
+}
+
+var _ = Describe("Ethereum sources", func() {
+
+	Context("when loading a registry", func() {
+
+		It("should list the currencies and markets of the contract", func() {
+			currencies := []Currency{
+				{Code: 5, Symbol: "ZRX", Decimals: 9},
+				{Code: 6, Symbol: "OMG", Decimals: 9},
+			}
+			markets := []Market{
+				{FstCode: 5, SndCode: order.CurrencyCodeETH, TickSize: "0.000001", MinVolume: "1"},
+				{FstCode: 6, SndCode: order.CurrencyCodeETH, TickSize: "0.00001", MinVolume: "0.1"},
+			}
+			source, err := NewEthereumSource(&connection.ClientDetails{Client: newMockMarketRegistry(currencies, markets)}, &bind.CallOpts{}, common.Address{})
+			Ω(err).ShouldNot(HaveOccurred())
+			Ω(source.ListCurrencies()).Should(Equal(currencies))
+			Ω(source.ListMarkets()).Should(Equal(markets))
+
+			// The markets of the contract are listed alongside the defaults
+			registry := NewDefaultRegistry()
+			Ω(registry.Load(source)).ShouldNot(HaveOccurred())
+			Ω(registry.CurrencyBySymbol("ZRX")).Should(Equal(currencies[0]))
+			Ω(registry.Market(6, order.CurrencyCodeETH)).Should(Equal(markets[1]))
+			Ω(registry.Market(order.CurrencyCodeETH, order.CurrencyCodeBTC)).ShouldNot(BeZero())
+		})
+
+		It("should return an error for markets of currencies that are not listed", func() {
+			markets := []Market{
+				{FstCode: 5, SndCode: order.CurrencyCodeETH, TickSize: "0.000001", MinVolume: "1"},
+			}
+			source, err := NewEthereumSource(&connection.ClientDetails{Client: newMockMarketRegistry(nil, markets)}, &bind.CallOpts{}, common.Address{})
+			Ω(err).ShouldNot(HaveOccurred())
+			Ω(NewDefaultRegistry().Load(source)).Should(Equal(ErrUnknownCurrency))
+		})
+	})
+})
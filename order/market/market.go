@@ -0,0 +1,418 @@
+// Package market lists the currencies and markets in which orders can be
+// opened. Currencies and markets are loaded into a Registry from Sources, so
+// that new markets can be listed without recompiling clients. A Config loads
+// them from a configuration file, and an EthereumSource loads them from the
+// MarketRegistry contract, to which the owner of the contract can list new
+// currencies and markets at any time.
+//
+// Orders are verified against the Registry when they are built, and by traders
+// before they are split. Dark nodes only ever see secret shares of an order,
+// so traders attach a zero-knowledge proof to the commitments of each order
+// fragment, proving that the committed pair of currencies is one of the
+// listed markets, and dark nodes verify it against their own Registry when
+// they receive an OpenOrder request. Prices and volumes are not proven, so an
+// order with a price that is not a multiple of the tick size, or a volume
+// that is less than the minimum volume, can still be opened.
+package market
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"sort"
+	"time"
+
+	"github.com/republicprotocol/go-do"
+	"github.com/republicprotocol/republic-go/order"
+	"github.com/republicprotocol/republic-go/order/fixed"
+	"github.com/republicprotocol/republic-go/shamir"
+	"github.com/republicprotocol/republic-go/stackint"
+)
+
+// ErrUnknownCurrency is returned when a Currency is not listed in a Registry.
+var ErrUnknownCurrency = errors.New("unknown currency")
+
+// ErrUnknownMarket is returned when a Market is not listed in a Registry.
+var ErrUnknownMarket = errors.New("unknown market")
+
+// ErrInvalidCurrency is returned when a Currency cannot be listed, because it
+// does not have a positive code, a unique symbol, and a number of decimals
+// that can be encoded.
+var ErrInvalidCurrency = errors.New("invalid currency")
+
+// ErrInvalidMarket is returned when a Market cannot be listed, because it does
+// not trade two different currencies, or because its tick size is zero.
+var ErrInvalidMarket = errors.New("invalid market")
+
+// ErrInvalidPrice is returned when the price of an order is not a positive
+// multiple of the tick size of its Market.
+var ErrInvalidPrice = errors.New("invalid price")
+
+// ErrInvalidVolume is returned when the minimum volume of an order is less
+// than the minimum volume of its Market, or larger than its maximum volume.
+var ErrInvalidVolume = errors.New("invalid volume")
+
+// maxDecimals is the largest number of decimals that a Currency can use. With
+// more decimals, one unit of the Currency could not be encoded in fixed.Bits.
+const maxDecimals = 18
+
+// A Currency can be traded in Markets. Volumes of the Currency are encoded as
+// fixed-point numbers with its Decimals, which is the precision at which it is
+// traded, and not necessarily the precision of its smallest unit.
+type Currency struct {
+	Code     order.CurrencyCode `json:"code"`
+	Symbol   string             `json:"symbol"`
+	Decimals int                `json:"decimals"`
+}
+
+// EncodeVolume encodes a decimal volume of the Currency.
+func (currency Currency) EncodeVolume(volume string) (stackint.Int1024, error) {
+	return fixed.Encode(volume, currency.Decimals)
+}
+
+// DecodeVolume decodes a volume of the Currency into a decimal volume.
+func (currency Currency) DecodeVolume(volume *stackint.Int1024) string {
+	return fixed.Decode(volume, currency.Decimals)
+}
+
+// A Market is a pair of Currencies that can be traded. Prices are the cost of
+// one unit of the first Currency in the second Currency, and must be a
+// multiple of the TickSize. Volumes are measured in the first Currency, and
+// must be at least the MinVolume. The TickSize and MinVolume are decimal
+// numbers, so that they can be written in configuration files.
+type Market struct {
+	FstCode   order.CurrencyCode `json:"fstCode"`
+	SndCode   order.CurrencyCode `json:"sndCode"`
+	TickSize  string             `json:"tickSize"`
+	MinVolume string             `json:"minVolume"`
+}
+
+// A Source lists Currencies and Markets that can be loaded into a Registry.
+type Source interface {
+	ListCurrencies() ([]Currency, error)
+	ListMarkets() ([]Market, error)
+}
+
+// Config is a Source that lists Currencies and Markets in configuration
+// files.
+type Config struct {
+	Currencies []Currency `json:"currencies"`
+	Markets    []Market   `json:"markets"`
+}
+
+// ListCurrencies implements the Source interface.
+func (config Config) ListCurrencies() ([]Currency, error) {
+	return config.Currencies, nil
+}
+
+// ListMarkets implements the Source interface.
+func (config Config) ListMarkets() ([]Market, error) {
+	return config.Markets, nil
+}
+
+// DefaultConfig returns the Config of the Currencies and Markets that are
+// listed by default.
+func DefaultConfig() Config {
+	return Config{
+		Currencies: []Currency{
+			{Code: order.CurrencyCodeBTC, Symbol: "BTC", Decimals: 8},
+			{Code: order.CurrencyCodeETH, Symbol: "ETH", Decimals: 9},
+			{Code: order.CurrencyCodeREN, Symbol: "REN", Decimals: 9},
+			{Code: order.CurrencyCodeDGD, Symbol: "DGD", Decimals: 9},
+		},
+		Markets: []Market{
+			{FstCode: order.CurrencyCodeETH, SndCode: order.CurrencyCodeBTC, TickSize: "0.000001", MinVolume: "0.001"},
+			{FstCode: order.CurrencyCodeREN, SndCode: order.CurrencyCodeETH, TickSize: "0.00000001", MinVolume: "1"},
+			{FstCode: order.CurrencyCodeDGD, SndCode: order.CurrencyCodeETH, TickSize: "0.000001", MinVolume: "0.01"},
+		},
+	}
+}
+
+// LoadConfig loads a Config object from the given filename. Returns the Config
+// object, or an error.
+func LoadConfig(filename string) (Config, error) {
+	file, err := os.Open(filename)
+	if err != nil {
+		return Config{}, err
+	}
+	defer file.Close()
+	config := Config{}
+	if err := json.NewDecoder(file).Decode(&config); err != nil {
+		return Config{}, err
+	}
+	return config, nil
+}
+
+// A Registry holds the Currencies and Markets in which orders can be opened.
+// It is safe for concurrent use.
+type Registry struct {
+	do.GuardedObject
+
+	currencies map[order.CurrencyCode]Currency
+	symbols    map[string]order.CurrencyCode
+	markets    map[[2]order.CurrencyCode]listing
+}
+
+// A listing is a Market with its TickSize and MinVolume encoded.
+type listing struct {
+	Market
+	tickSize  stackint.Int1024
+	minVolume stackint.Int1024
+}
+
+// NewRegistry returns an empty Registry.
+func NewRegistry() *Registry {
+	return &Registry{
+		GuardedObject: do.NewGuardedObject(),
+		currencies:    map[order.CurrencyCode]Currency{},
+		symbols:       map[string]order.CurrencyCode{},
+		markets:       map[[2]order.CurrencyCode]listing{},
+	}
+}
+
+// NewDefaultRegistry returns a Registry that lists the Currencies and Markets
+// of the DefaultConfig.
+func NewDefaultRegistry() *Registry {
+	registry := NewRegistry()
+	if err := registry.Load(DefaultConfig()); err != nil {
+		panic(err)
+	}
+	return registry
+}
+
+// Load the Currencies and Markets of a Source into the Registry. Currencies
+// and Markets that are already listed are replaced. Currencies are loaded
+// before Markets, so a Source can list Markets of the Currencies that it
+// lists.
+func (registry *Registry) Load(source Source) error {
+	currencies, err := source.ListCurrencies()
+	if err != nil {
+		return err
+	}
+	for _, currency := range currencies {
+		if err := registry.ListCurrency(currency); err != nil {
+			return err
+		}
+	}
+	markets, err := source.ListMarkets()
+	if err != nil {
+		return err
+	}
+	for _, market := range markets {
+		if err := registry.ListMarket(market); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ListCurrency lists a Currency in the Registry, replacing any Currency with
+// the same code. An ErrInvalidCurrency is returned if the Currency is not
+// valid.
+func (registry *Registry) ListCurrency(currency Currency) error {
+	registry.Enter(nil)
+	defer registry.Exit()
+	return registry.listCurrency(currency)
+}
+
+func (registry *Registry) listCurrency(currency Currency) error {
+	if currency.Code <= 0 || currency.Symbol == "" || currency.Decimals < 0 || currency.Decimals > maxDecimals {
+		return ErrInvalidCurrency
+	}
+	if code, ok := registry.symbols[currency.Symbol]; ok && code != currency.Code {
+		return ErrInvalidCurrency
+	}
+	if previous, ok := registry.currencies[currency.Code]; ok {
+		delete(registry.symbols, previous.Symbol)
+	}
+	registry.currencies[currency.Code] = currency
+	registry.symbols[currency.Symbol] = currency.Code
+	return nil
+}
+
+// ListMarket lists a Market in the Registry, replacing any Market with the
+// same Currencies. An ErrUnknownCurrency is returned if the Currencies of the
+// Market are not listed, and an ErrInvalidMarket is returned if the Market is
+// not valid.
+func (registry *Registry) ListMarket(market Market) error {
+	registry.Enter(nil)
+	defer registry.Exit()
+	return registry.listMarket(market)
+}
+
+func (registry *Registry) listMarket(market Market) error {
+	if market.FstCode == market.SndCode {
+		return ErrInvalidMarket
+	}
+	fst, ok := registry.currencies[market.FstCode]
+	if !ok {
+		return ErrUnknownCurrency
+	}
+	if _, ok := registry.currencies[market.SndCode]; !ok {
+		return ErrUnknownCurrency
+	}
+	tickSize, err := fixed.EncodePrice(market.TickSize)
+	if err != nil {
+		return err
+	}
+	if tickSize.IsZero() {
+		return ErrInvalidMarket
+	}
+	minVolume, err := fst.EncodeVolume(market.MinVolume)
+	if err != nil {
+		return err
+	}
+	registry.markets[[2]order.CurrencyCode{market.FstCode, market.SndCode}] = listing{
+		Market:    market,
+		tickSize:  tickSize,
+		minVolume: minVolume,
+	}
+	return nil
+}
+
+// Currency returns the listed Currency with the given code. An
+// ErrUnknownCurrency is returned if it is not listed.
+func (registry *Registry) Currency(code order.CurrencyCode) (Currency, error) {
+	registry.EnterReadOnly(nil)
+	defer registry.ExitReadOnly()
+	currency, ok := registry.currencies[code]
+	if !ok {
+		return Currency{}, ErrUnknownCurrency
+	}
+	return currency, nil
+}
+
+// CurrencyBySymbol returns the listed Currency with the given symbol. An
+// ErrUnknownCurrency is returned if it is not listed.
+func (registry *Registry) CurrencyBySymbol(symbol string) (Currency, error) {
+	registry.EnterReadOnly(nil)
+	defer registry.ExitReadOnly()
+	code, ok := registry.symbols[symbol]
+	if !ok {
+		return Currency{}, ErrUnknownCurrency
+	}
+	return registry.currencies[code], nil
+}
+
+// Market returns the listed Market of the given Currencies. An
+// ErrUnknownMarket is returned if it is not listed.
+func (registry *Registry) Market(fstCode, sndCode order.CurrencyCode) (Market, error) {
+	registry.EnterReadOnly(nil)
+	defer registry.ExitReadOnly()
+	listing, ok := registry.markets[[2]order.CurrencyCode{fstCode, sndCode}]
+	if !ok {
+		return Market{}, ErrUnknownMarket
+	}
+	return listing.Market, nil
+}
+
+// Currencies returns all listed Currencies, ordered by code.
+func (registry *Registry) Currencies() []Currency {
+	registry.EnterReadOnly(nil)
+	defer registry.ExitReadOnly()
+	currencies := make([]Currency, 0, len(registry.currencies))
+	for _, currency := range registry.currencies {
+		currencies = append(currencies, currency)
+	}
+	sort.Slice(currencies, func(i, j int) bool { return currencies[i].Code < currencies[j].Code })
+	return currencies
+}
+
+// Markets returns all listed Markets, ordered by their Currency codes.
+func (registry *Registry) Markets() []Market {
+	registry.EnterReadOnly(nil)
+	defer registry.ExitReadOnly()
+	markets := make([]Market, 0, len(registry.markets))
+	for _, listing := range registry.markets {
+		markets = append(markets, listing.Market)
+	}
+	sort.Slice(markets, func(i, j int) bool {
+		if markets[i].FstCode != markets[j].FstCode {
+			return markets[i].FstCode < markets[j].FstCode
+		}
+		return markets[i].SndCode < markets[j].SndCode
+	})
+	return markets
+}
+
+// Pairs returns the currency codes of all listed Markets, ordered in the same
+// way as Markets. Traders prove that an Order is in one of the Pairs when it
+// is split.
+func (registry *Registry) Pairs() [][2]order.CurrencyCode {
+	markets := registry.Markets()
+	pairs := make([][2]order.CurrencyCode, len(markets))
+	for i, market := range markets {
+		pairs[i] = [2]order.CurrencyCode{market.FstCode, market.SndCode}
+	}
+	return pairs
+}
+
+// VerifyFragment checks that an order Fragment is in a listed Market, without
+// learning which one. Every Market in the Commitments of the Fragment must be
+// listed, and its MarketProof must prove that the Fragment is in one of them.
+// The shares of the Fragment must have been verified against its Commitments,
+// using the same VSS parameters. An ErrUnknownMarket is returned if the
+// Fragment has no Markets, or if one of them is not listed, and a
+// shamir.ErrInvalidMembershipProof is returned if the MarketProof is not
+// valid. The price and volumes of the Fragment are not verified.
+func (registry *Registry) VerifyFragment(vss *shamir.VSS, fragment *order.Fragment) error {
+	if fragment.Commitments == nil || len(fragment.Commitments.Markets) == 0 {
+		return ErrUnknownMarket
+	}
+	registry.EnterReadOnly(nil)
+	for _, pair := range fragment.Commitments.Markets {
+		if _, ok := registry.markets[pair]; !ok {
+			registry.ExitReadOnly()
+			return ErrUnknownMarket
+		}
+	}
+	registry.ExitReadOnly()
+	return fragment.VerifyMarket(vss)
+}
+
+// VerifyOrder checks that an Order can be opened in a listed Market. An
+// ErrUnknownMarket is returned if its Market is not listed, an
+// ErrInvalidPrice is returned if its price is not a positive multiple of the
+// tick size, unless it is an IBBO order, and an ErrInvalidVolume is returned
+// if its minimum volume is less than the minimum volume of the Market or
+// larger than its maximum volume.
+func (registry *Registry) VerifyOrder(ord *order.Order) error {
+	registry.EnterReadOnly(nil)
+	defer registry.ExitReadOnly()
+	return registry.verifyOrder(ord)
+}
+
+func (registry *Registry) verifyOrder(ord *order.Order) error {
+	listing, ok := registry.markets[[2]order.CurrencyCode{ord.FstCode, ord.SndCode}]
+	if !ok {
+		return ErrUnknownMarket
+	}
+	if ord.Type != order.TypeIBBO {
+		// IBBO orders are executed at the midpoint of the Market, so their
+		// price is ignored
+		if ord.Price == nil {
+			return ErrInvalidPrice
+		}
+		remainder := ord.Price.Mod(&listing.tickSize)
+		if ord.Price.IsZero() || !remainder.IsZero() {
+			return ErrInvalidPrice
+		}
+	}
+	if ord.MinVolume == nil || ord.MaxVolume == nil {
+		return ErrInvalidVolume
+	}
+	if ord.MinVolume.LessThan(&listing.minVolume) || ord.MinVolume.GreaterThan(ord.MaxVolume) {
+		return ErrInvalidVolume
+	}
+	return nil
+}
+
+// NewOrder returns a new Order in the same way as order.NewOrder, after
+// checking that it can be opened in a listed Market using VerifyOrder.
+func (registry *Registry) NewOrder(ty order.Type, parity order.Parity, expiry time.Time, fstCode, sndCode order.CurrencyCode, price, maxVolume, minVolume, nonce *stackint.Int1024) (*order.Order, error) {
+	ord := order.NewOrder(ty, parity, expiry, fstCode, sndCode, price, maxVolume, minVolume, nonce)
+	if err := registry.VerifyOrder(ord); err != nil {
+		return nil, err
+	}
+	return ord, nil
+}
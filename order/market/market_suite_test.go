@@ -0,0 +1,13 @@
+package market_test
+
+import (
+	"testing"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+func TestMarket(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "Market Suite")
+}
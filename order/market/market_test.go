@@ -0,0 +1,211 @@
+package market_test
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	. "github.com/republicprotocol/republic-go/order/market"
+
+	"github.com/republicprotocol/republic-go/order"
+	"github.com/republicprotocol/republic-go/order/fixed"
+	"github.com/republicprotocol/republic-go/shamir"
+	"github.com/republicprotocol/republic-go/stackint"
+)
+
+var _ = Describe("Markets", func() {
+
+	var registry *Registry
+
+	BeforeEach(func() {
+		registry = NewDefaultRegistry()
+	})
+
+	newOrderOfType := func(ty order.Type, fstCode, sndCode order.CurrencyCode, price, maxVolume, minVolume string) *order.Order {
+		encodedPrice, err := fixed.EncodePrice(price)
+		Ω(err).ShouldNot(HaveOccurred())
+		currency, err := registry.Currency(fstCode)
+		Ω(err).ShouldNot(HaveOccurred())
+		encodedMaxVolume, err := currency.EncodeVolume(maxVolume)
+		Ω(err).ShouldNot(HaveOccurred())
+		encodedMinVolume, err := currency.EncodeVolume(minVolume)
+		Ω(err).ShouldNot(HaveOccurred())
+		nonce := stackint.Zero()
+		return order.NewOrder(ty, order.ParityBuy, time.Now().Add(time.Hour), fstCode, sndCode, &encodedPrice, &encodedMaxVolume, &encodedMinVolume, &nonce)
+	}
+
+	newOrder := func(fstCode, sndCode order.CurrencyCode, price, maxVolume, minVolume string) *order.Order {
+		return newOrderOfType(order.TypeLimit, fstCode, sndCode, price, maxVolume, minVolume)
+	}
+
+	Context("when listing currencies", func() {
+
+		It("should find currencies by code and by symbol", func() {
+			currency, err := registry.Currency(order.CurrencyCodeETH)
+			Ω(err).ShouldNot(HaveOccurred())
+			Ω(currency.Symbol).Should(Equal("ETH"))
+
+			currency, err = registry.CurrencyBySymbol("BTC")
+			Ω(err).ShouldNot(HaveOccurred())
+			Ω(currency.Code).Should(Equal(order.CurrencyCodeBTC))
+			Ω(registry.Currencies()).Should(HaveLen(4))
+		})
+
+		It("should encode volumes using the decimals of the currency", func() {
+			currency, err := registry.Currency(order.CurrencyCodeBTC)
+			Ω(err).ShouldNot(HaveOccurred())
+			volume, err := currency.EncodeVolume("1.5")
+			Ω(err).ShouldNot(HaveOccurred())
+			Ω(volume.String()).Should(Equal("150000000"))
+			Ω(currency.DecodeVolume(&volume)).Should(Equal("1.5"))
+		})
+
+		It("should list new currencies without replacing the symbols of others", func() {
+			Ω(registry.ListCurrency(Currency{Code: 5, Symbol: "OMG", Decimals: 9})).ShouldNot(HaveOccurred())
+			Ω(registry.ListCurrency(Currency{Code: 6, Symbol: "OMG", Decimals: 9})).Should(Equal(ErrInvalidCurrency))
+			Ω(registry.ListCurrency(Currency{Code: 0, Symbol: "ZRX", Decimals: 9})).Should(Equal(ErrInvalidCurrency))
+			Ω(registry.ListCurrency(Currency{Code: 7, Symbol: "ZRX", Decimals: 19})).Should(Equal(ErrInvalidCurrency))
+
+			_, err := registry.Currency(5)
+			Ω(err).ShouldNot(HaveOccurred())
+			_, err = registry.Currency(6)
+			Ω(err).Should(Equal(ErrUnknownCurrency))
+		})
+	})
+
+	Context("when listing markets", func() {
+
+		It("should only list markets of listed currencies", func() {
+			Ω(registry.ListMarket(Market{FstCode: 5, SndCode: order.CurrencyCodeETH, TickSize: "0.0001", MinVolume: "1"})).Should(Equal(ErrUnknownCurrency))
+			Ω(registry.ListMarket(Market{FstCode: order.CurrencyCodeETH, SndCode: order.CurrencyCodeETH, TickSize: "0.0001", MinVolume: "1"})).Should(Equal(ErrInvalidMarket))
+			Ω(registry.ListMarket(Market{FstCode: order.CurrencyCodeREN, SndCode: order.CurrencyCodeBTC, TickSize: "0", MinVolume: "1"})).Should(Equal(ErrInvalidMarket))
+			Ω(registry.ListMarket(Market{FstCode: order.CurrencyCodeREN, SndCode: order.CurrencyCodeBTC, TickSize: "0.0001", MinVolume: "1"})).ShouldNot(HaveOccurred())
+
+			market, err := registry.Market(order.CurrencyCodeREN, order.CurrencyCodeBTC)
+			Ω(err).ShouldNot(HaveOccurred())
+			Ω(market.TickSize).Should(Equal("0.0001"))
+			_, err = registry.Market(order.CurrencyCodeBTC, order.CurrencyCodeREN)
+			Ω(err).Should(Equal(ErrUnknownMarket))
+			Ω(registry.Markets()).Should(HaveLen(4))
+		})
+
+		It("should load currencies and markets from configuration files", func() {
+			config := Config{
+				Currencies: []Currency{{Code: 5, Symbol: "OMG", Decimals: 9}},
+				Markets:    []Market{{FstCode: 5, SndCode: order.CurrencyCodeETH, TickSize: "0.000001", MinVolume: "10"}},
+			}
+			data, err := json.Marshal(config)
+			Ω(err).ShouldNot(HaveOccurred())
+			dir, err := ioutil.TempDir("", "market")
+			Ω(err).ShouldNot(HaveOccurred())
+			defer os.RemoveAll(dir)
+			filename := filepath.Join(dir, "markets.json")
+			Ω(ioutil.WriteFile(filename, data, 0644)).ShouldNot(HaveOccurred())
+
+			loaded, err := LoadConfig(filename)
+			Ω(err).ShouldNot(HaveOccurred())
+			Ω(loaded).Should(Equal(config))
+			Ω(registry.Load(loaded)).ShouldNot(HaveOccurred())
+
+			Ω(registry.VerifyOrder(newOrder(5, order.CurrencyCodeETH, "0.015", "100", "10"))).ShouldNot(HaveOccurred())
+		})
+
+		It("should return an error when loading a missing configuration file", func() {
+			_, err := LoadConfig("non-existent.json")
+			Ω(err).Should(HaveOccurred())
+		})
+	})
+
+	Context("when verifying orders", func() {
+
+		It("should accept orders in listed markets", func() {
+			Ω(registry.VerifyOrder(newOrder(order.CurrencyCodeETH, order.CurrencyCodeBTC, "0.071234", "10", "1"))).ShouldNot(HaveOccurred())
+		})
+
+		It("should return an error for orders in markets that are not listed", func() {
+			Ω(registry.VerifyOrder(newOrder(order.CurrencyCodeBTC, order.CurrencyCodeETH, "14", "10", "1"))).Should(Equal(ErrUnknownMarket))
+		})
+
+		It("should return an error for prices that are not a multiple of the tick size", func() {
+			Ω(registry.VerifyOrder(newOrder(order.CurrencyCodeETH, order.CurrencyCodeBTC, "0.0712345", "10", "1"))).Should(Equal(ErrInvalidPrice))
+			Ω(registry.VerifyOrder(newOrder(order.CurrencyCodeETH, order.CurrencyCodeBTC, "0", "10", "1"))).Should(Equal(ErrInvalidPrice))
+		})
+
+		It("should ignore the price of IBBO orders", func() {
+			Ω(registry.VerifyOrder(newOrderOfType(order.TypeIBBO, order.CurrencyCodeETH, order.CurrencyCodeBTC, "0", "10", "1"))).ShouldNot(HaveOccurred())
+			Ω(registry.VerifyOrder(newOrderOfType(order.TypeIBBO, order.CurrencyCodeBTC, order.CurrencyCodeETH, "0", "10", "1"))).Should(Equal(ErrUnknownMarket))
+		})
+
+		It("should return an error for volumes that are not valid", func() {
+			Ω(registry.VerifyOrder(newOrder(order.CurrencyCodeETH, order.CurrencyCodeBTC, "0.071234", "10", "0.0001"))).Should(Equal(ErrInvalidVolume))
+			Ω(registry.VerifyOrder(newOrder(order.CurrencyCodeETH, order.CurrencyCodeBTC, "0.071234", "1", "10"))).Should(Equal(ErrInvalidVolume))
+		})
+
+		It("should only construct orders that can be verified", func() {
+			price, err := fixed.EncodePrice("0.071234")
+			Ω(err).ShouldNot(HaveOccurred())
+			volume := stackint.FromUint(1000000000)
+			nonce := stackint.Zero()
+
+			ord, err := registry.NewOrder(order.TypeLimit, order.ParityBuy, time.Now().Add(time.Hour), order.CurrencyCodeETH, order.CurrencyCodeBTC, &price, &volume, &volume, &nonce)
+			Ω(err).ShouldNot(HaveOccurred())
+			Ω(ord.ID.Equal(order.ID(ord.Hash()))).Should(BeTrue())
+
+			_, err = registry.NewOrder(order.TypeLimit, order.ParityBuy, time.Now().Add(time.Hour), order.CurrencyCodeBTC, order.CurrencyCodeDGD, &price, &volume, &volume, &nonce)
+			Ω(err).Should(Equal(ErrUnknownMarket))
+		})
+	})
+
+	Context("when verifying order fragments", func() {
+
+		prime, _ := stackint.FromString("179769313486231590772930519078902473361797697894230657273430081157732675805500963132708477322407536021120113879871393357658789768814416622492847430639474124377767893424865485276302219601246094119453082952085005768838150682342462881473913110540827237163350510684586298239947245938479716304835356329624224137111")
+		vss, _ := shamir.NewVSS(&prime)
+
+		It("should accept order fragments that prove they are in a listed market", func() {
+			ord := newOrder(order.CurrencyCodeREN, order.CurrencyCodeETH, "0.0001", "1000", "10")
+			fragments, err := ord.SplitVerifiable(5, 4, vss, registry.Pairs())
+			Ω(err).ShouldNot(HaveOccurred())
+			for _, fragment := range fragments {
+				Ω(fragment.VerifyCommitments(vss, 4)).ShouldNot(HaveOccurred())
+				Ω(registry.VerifyFragment(vss, fragment)).ShouldNot(HaveOccurred())
+			}
+		})
+
+		It("should return an error for order fragments that prove they are in a market that is not listed", func() {
+			Ω(registry.ListCurrency(Currency{Code: 5, Symbol: "ZRX", Decimals: 9})).ShouldNot(HaveOccurred())
+			Ω(registry.ListMarket(Market{FstCode: 5, SndCode: order.CurrencyCodeETH, TickSize: "0.000001", MinVolume: "1"})).ShouldNot(HaveOccurred())
+			ord := newOrder(5, order.CurrencyCodeETH, "0.001", "1000", "10")
+			fragments, err := ord.SplitVerifiable(5, 4, vss, registry.Pairs())
+			Ω(err).ShouldNot(HaveOccurred())
+
+			// Dark nodes that have not listed the market reject the order
+			Ω(NewDefaultRegistry().VerifyFragment(vss, fragments[0])).Should(Equal(ErrUnknownMarket))
+			Ω(registry.VerifyFragment(vss, fragments[0])).ShouldNot(HaveOccurred())
+		})
+
+		It("should return an error for order fragments that do not prove their market", func() {
+			ord := newOrder(order.CurrencyCodeETH, order.CurrencyCodeBTC, "0.071234", "10", "1")
+			fragments, err := ord.SplitVerifiable(5, 4, vss, nil)
+			Ω(err).ShouldNot(HaveOccurred())
+			Ω(registry.VerifyFragment(vss, fragments[0])).Should(Equal(ErrUnknownMarket))
+
+			// A proof for an order in an unlisted market cannot be made using
+			// the listed markets
+			ord = newOrder(order.CurrencyCodeBTC, order.CurrencyCodeETH, "14", "10", "1")
+			_, err = ord.SplitVerifiable(5, 4, vss, registry.Pairs())
+			Ω(err).Should(Equal(shamir.ErrInvalidMembershipProof))
+
+			// The proof of one order does not hold for another
+			fragments, err = newOrder(order.CurrencyCodeETH, order.CurrencyCodeBTC, "0.071234", "10", "1").SplitVerifiable(5, 4, vss, registry.Pairs())
+			Ω(err).ShouldNot(HaveOccurred())
+			others, err := newOrder(order.CurrencyCodeETH, order.CurrencyCodeBTC, "0.071234", "10", "1").SplitVerifiable(5, 4, vss, registry.Pairs())
+			Ω(err).ShouldNot(HaveOccurred())
+			fragments[0].Commitments.MarketProof = others[0].Commitments.MarketProof
+			Ω(registry.VerifyFragment(vss, fragments[0])).Should(Equal(shamir.ErrInvalidMembershipProof))
+		})
+	})
+})
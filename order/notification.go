@@ -0,0 +1,150 @@
+package order
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"time"
+
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/republicprotocol/republic-go/identity"
+)
+
+// SubscriptionWindow is the duration, either side of its timestamp, in which a
+// Subscription is accepted. Outside of this window, a captured Subscription
+// cannot be replayed to receive the Notifications of a trader.
+const SubscriptionWindow = time.Minute
+
+// ErrSubscriptionExpired is returned when a Subscription is used outside of
+// the SubscriptionWindow.
+var ErrSubscriptionExpired = errors.New("subscription expired")
+
+// subscriptionPrefix is prepended to the trader before hashing so that a
+// Subscription signature cannot be mistaken for any other signature.
+var subscriptionPrefix = []byte("Republic Protocol: subscribe: ")
+
+// notificationPrefix is prepended to a Notification before hashing so that a
+// Notification signature cannot be mistaken for any other signature.
+var notificationPrefix = []byte("Republic Protocol: notification: ")
+
+// A Subscription is a request from a trader to receive the Notifications for
+// their orders. It must be signed by the trader.
+type Subscription struct {
+	Signature identity.Signature
+	Trader    identity.ID
+	Timestamp time.Time
+}
+
+// NewSubscription returns a new, unsigned, Subscription for the trader with
+// the given ID.
+func NewSubscription(trader identity.ID, timestamp time.Time) *Subscription {
+	return &Subscription{
+		Trader:    trader,
+		Timestamp: timestamp,
+	}
+}
+
+// Hash returns the Keccak256 hash of a Subscription. This hash is used to
+// create the signature for a Subscription.
+func (subscription *Subscription) Hash() []byte {
+	return crypto.Keccak256(subscription.Bytes())
+}
+
+// Sign signs the Subscription using the provided keypair, and assigns it the
+// the Subscription's Signature field.
+func (subscription *Subscription) Sign(keyPair identity.KeyPair) error {
+	var err error
+	subscription.Signature, err = keyPair.Sign(subscription)
+	return err
+}
+
+// Verify checks that the Subscription was signed by its trader, and that it
+// is being used within the SubscriptionWindow of its timestamp.
+func (subscription *Subscription) Verify(now time.Time) error {
+	if err := identity.VerifySignature(subscription, subscription.Signature, subscription.Trader); err != nil {
+		return err
+	}
+	if now.Sub(subscription.Timestamp) > SubscriptionWindow || subscription.Timestamp.Sub(now) > SubscriptionWindow {
+		return ErrSubscriptionExpired
+	}
+	return nil
+}
+
+// Bytes returns a Subscription serialized into a bytes.
+func (subscription *Subscription) Bytes() []byte {
+	buf := new(bytes.Buffer)
+	buf.Write(subscriptionPrefix)
+	buf.Write(subscription.Trader)
+	binary.Write(buf, binary.LittleEndian, subscription.Timestamp.Unix())
+	return buf.Bytes()
+}
+
+// A NotificationType is the event that caused a Notification.
+type NotificationType int64
+
+// NotificationTypes for the events of an Order. A match notifies both traders,
+// an expiry notifies the trader when an Order, or the residual of a partially
+// filled Order, expires, and a cancellation notifies the trader when the dark
+// node removes a cancelled Order. A settlement, or a failed settlement,
+// notifies both traders once a match has been submitted to the settlement
+// layer.
+const (
+	NotificationMatch            NotificationType = 1
+	NotificationExpired          NotificationType = 2
+	NotificationCancelled        NotificationType = 3
+	NotificationSettled          NotificationType = 4
+	NotificationSettlementFailed NotificationType = 5
+)
+
+// A Notification is an event for an Order that is sent to the trader that
+// opened it. The OrderID is the ID of the Order opened by the trader, even if
+// the event happened to one of its residual orders. The CounterpartyOrderID is
+// only set for matches and settlements. A Notification is signed by the dark
+// node that sends it.
+type Notification struct {
+	Signature           identity.Signature
+	Type                NotificationType
+	OrderID             ID
+	CounterpartyOrderID ID
+}
+
+// NewNotification returns a new, unsigned, Notification.
+func NewNotification(ty NotificationType, orderID, counterpartyOrderID ID) *Notification {
+	return &Notification{
+		Type:                ty,
+		OrderID:             orderID,
+		CounterpartyOrderID: counterpartyOrderID,
+	}
+}
+
+// Hash returns the Keccak256 hash of a Notification. This hash is used to
+// create the signature for a Notification.
+func (notification *Notification) Hash() []byte {
+	return crypto.Keccak256(notification.Bytes())
+}
+
+// Sign signs the Notification using the provided keypair, and assigns it the
+// the Notification's Signature field.
+func (notification *Notification) Sign(keyPair identity.KeyPair) error {
+	var err error
+	notification.Signature, err = keyPair.Sign(notification)
+	return err
+}
+
+// VerifySignature verifies that the Signature field has been signed by the
+// provided ID's private key, returning an error if the signature is invalid
+func (notification *Notification) VerifySignature(ID identity.ID) error {
+	return identity.VerifySignature(notification, notification.Signature, ID)
+}
+
+// Bytes returns a Notification serialized into a bytes.
+func (notification *Notification) Bytes() []byte {
+	buf := new(bytes.Buffer)
+	buf.Write(notificationPrefix)
+	binary.Write(buf, binary.LittleEndian, notification.Type)
+	binary.Write(buf, binary.LittleEndian, uint32(len(notification.OrderID)))
+	buf.Write(notification.OrderID)
+	binary.Write(buf, binary.LittleEndian, uint32(len(notification.CounterpartyOrderID)))
+	buf.Write(notification.CounterpartyOrderID)
+	return buf.Bytes()
+}
@@ -2,13 +2,13 @@ package order
 
 import (
 	"bytes"
-	"encoding/binary"
 	"time"
 
 	"github.com/republicprotocol/republic-go/identity"
 
 	"github.com/ethereum/go-ethereum/crypto"
 	base58 "github.com/jbenet/go-base58"
+	"github.com/republicprotocol/republic-go/canonical"
 	"github.com/republicprotocol/republic-go/shamir"
 	"github.com/republicprotocol/republic-go/stackint"
 )
@@ -29,12 +29,40 @@ const (
 // trade that an Order is representing.
 type Type int64
 
-// Type values.
+// Type values. A TypeLimit Order is executed at a price that is no worse
+// than its price. A TypeIBBO Order is pegged to the midpoint of the best bid
+// and best offer of its market, so its price is ignored and it is executed at
+// a reference midpoint that is supplied to the dark nodes. TypeIBBO Orders can
+// only match other TypeIBBO Orders.
 const (
 	TypeIBBO  Type = 1
 	TypeLimit Type = 2
 )
 
+// A TimeInForce is a public bit of information that determines how long an
+// Order remains open.
+type TimeInForce int64
+
+// TimeInForce values. A TimeInForceGTC Order remains open until it is
+// filled, cancelled, or it expires, and it is the zero value. A
+// TimeInForceIOC Order is immediate-or-cancel, and only remains open for a
+// short window in which it can be filled, after which any remaining volume is
+// cancelled. The window starts at the OpenTime of the Order, which is set by
+// the trader, so that every dark node closes it at the same time. A
+// TimeInForceFOK Order is fill-or-kill, and is immediate in the same way, but
+// can only be filled completely by a single match.
+const (
+	TimeInForceGTC TimeInForce = 0
+	TimeInForceIOC TimeInForce = 1
+	TimeInForceFOK TimeInForce = 2
+)
+
+// IsImmediate returns true if the TimeInForce only keeps an Order open for a
+// short window, otherwise it returns false.
+func (timeInForce TimeInForce) IsImmediate() bool {
+	return timeInForce == TimeInForceIOC || timeInForce == TimeInForceFOK
+}
+
 // The Parity of an Order determines whether it is buy or a sell.
 type Parity int64
 
@@ -44,6 +72,9 @@ const (
 	ParitySell Parity = 2
 )
 
+// orderTag identifies the canonical encoding of an Order.
+var orderTag = []byte("Republic Protocol: order")
+
 // An ID is the Keccak256 hash of an Order.
 type ID []byte
 
@@ -62,9 +93,10 @@ type Order struct {
 	Signature identity.Signature `json:"signature"`
 	ID        ID                 `json:"id"`
 
-	Type   Type      `json:"type"`
-	Parity Parity    `json:"parity"`
-	Expiry time.Time `json:"expiry"`
+	Type        Type        `json:"type"`
+	Parity      Parity      `json:"parity"`
+	Expiry      time.Time   `json:"expiry"`
+	TimeInForce TimeInForce `json:"timeInForce"`
 
 	FstCode   CurrencyCode      `json:"fstCode"`
 	SndCode   CurrencyCode      `json:"sndCode"`
@@ -73,6 +105,11 @@ type Order struct {
 	MinVolume *stackint.Int1024 `json:"minVolume"`
 
 	Nonce *stackint.Int1024 `json:"nonce"`
+
+	// OpenTime is the time at which the trader opened the Order. It is only
+	// needed by immediate Orders and IBBO Orders, and is zero for all other
+	// Orders.
+	OpenTime time.Time `json:"openTime"`
 }
 
 // NewOrder returns a new Order and computes the ID.
@@ -92,8 +129,25 @@ func NewOrder(ty Type, parity Parity, expiry time.Time, fstCode, sndCode Currenc
 	return order
 }
 
+// WithTimeInForce sets the TimeInForce of the Order, and recomputes the ID.
+// Orders returned by NewOrder are TimeInForceGTC. Returns the Order.
+func (order *Order) WithTimeInForce(timeInForce TimeInForce) *Order {
+	order.TimeInForce = timeInForce
+	order.ID = ID(order.Hash())
+	return order
+}
+
+// WithOpenTime sets the OpenTime of the Order, and recomputes the ID. The
+// window of an immediate Order starts at its OpenTime. Returns the Order.
+func (order *Order) WithOpenTime(openTime time.Time) *Order {
+	order.OpenTime = openTime
+	order.ID = ID(order.Hash())
+	return order
+}
+
 // Split the Order into n OrderFragments, where k OrderFragments are needed to
-// reconstruct the Order. Returns a slice of all n OrderFragments, or an error.
+// reconstruct the Order. The OrderFragments hold the FieldID of the prime.
+// Returns a slice of all n OrderFragments, or an error.
 func (order *Order) Split(n, k int64, prime *stackint.Int1024) ([]*Fragment, error) {
 	fstCode := stackint.FromUint(uint(order.FstCode))
 	fstCodeShares, err := shamir.Split(n, k, prime, &fstCode)
@@ -117,26 +171,114 @@ func (order *Order) Split(n, k int64, prime *stackint.Int1024) ([]*Fragment, err
 	if err != nil {
 		return nil, err
 	}
+	field := shamir.NewFieldID(prime)
 	fragments := make([]*Fragment, n)
 	for i := range fragments {
 		fragments[i] = NewFragment(
 			order.ID,
 			order.Type,
 			order.Parity,
+			order.Expiry,
 			fstCodeShares[i],
 			sndCodeShares[i],
 			priceShares[i],
 			maxVolumeShares[i],
 			minVolumeShares[i],
 		)
+		fragments[i].OrderTimeInForce = order.TimeInForce
+		fragments[i].OrderNonce = order.Nonce
+		fragments[i].OrderOpenTime = order.OpenTime
+		fragments[i].Field = field
+		if fragments[i].ID, err = fragments[i].Hash(); err != nil {
+			return nil, err
+		}
 	}
 	return fragments, nil
 }
 
-// Hash returns the Keccak256 hash of an Order. This hash is used to create the
-// ID and signature for an Order.
+// SplitVerifiable splits the Order into Fragments in the same way as Split,
+// but uses Pedersen verifiable secret sharing so that each Fragment holds the
+// Commitments that its shares can be verified against. The finite field is
+// defined by the prime of the VSS parameters. When markets are given, the
+// Commitments also hold a MarketProof that the Order is in one of them, and a
+// shamir.ErrInvalidMembershipProof is returned if it is not.
+func (order *Order) SplitVerifiable(n, k int64, vss *shamir.VSS, markets [][2]CurrencyCode) ([]*Fragment, error) {
+	fstCode := stackint.FromUint(uint(order.FstCode))
+	fstCodeShares, fstCodeBlindings, fstCodeCommitments, err := vss.Split(n, k, &fstCode)
+	if err != nil {
+		return nil, err
+	}
+	sndCode := stackint.FromUint(uint(order.SndCode))
+	sndCodeShares, sndCodeBlindings, sndCodeCommitments, err := vss.Split(n, k, &sndCode)
+	if err != nil {
+		return nil, err
+	}
+	priceShares, priceBlindings, priceCommitments, err := vss.Split(n, k, order.Price)
+	if err != nil {
+		return nil, err
+	}
+	maxVolumeShares, maxVolumeBlindings, maxVolumeCommitments, err := vss.Split(n, k, order.MaxVolume)
+	if err != nil {
+		return nil, err
+	}
+	minVolumeShares, minVolumeBlindings, minVolumeCommitments, err := vss.Split(n, k, order.MinVolume)
+	if err != nil {
+		return nil, err
+	}
+	var marketProof *shamir.MembershipProof
+	if markets != nil {
+		if marketProof, err = order.proveMarket(vss, markets, fstCodeCommitments, sndCodeCommitments, fstCodeBlindings, sndCodeBlindings); err != nil {
+			return nil, err
+		}
+	}
+	field := shamir.NewFieldID(vss.Prime)
+	fragments := make([]*Fragment, n)
+	for i := range fragments {
+		fragments[i] = NewFragment(
+			order.ID,
+			order.Type,
+			order.Parity,
+			order.Expiry,
+			fstCodeShares[i],
+			sndCodeShares[i],
+			priceShares[i],
+			maxVolumeShares[i],
+			minVolumeShares[i],
+		)
+		fragments[i].OrderTimeInForce = order.TimeInForce
+		fragments[i].OrderNonce = order.Nonce
+		fragments[i].OrderOpenTime = order.OpenTime
+		fragments[i].Field = field
+		fragments[i].Commitments = &Commitments{
+			FstCode:           fstCodeCommitments,
+			SndCode:           sndCodeCommitments,
+			Price:             priceCommitments,
+			MaxVolume:         maxVolumeCommitments,
+			MinVolume:         minVolumeCommitments,
+			FstCodeBlinding:   fstCodeBlindings[i],
+			SndCodeBlinding:   sndCodeBlindings[i],
+			PriceBlinding:     priceBlindings[i],
+			MaxVolumeBlinding: maxVolumeBlindings[i],
+			MinVolumeBlinding: minVolumeBlindings[i],
+			Markets:           markets,
+			MarketProof:       marketProof,
+		}
+		if fragments[i].ID, err = fragments[i].Hash(); err != nil {
+			return nil, err
+		}
+	}
+	return fragments, nil
+}
+
+// Hash returns the Keccak256 hash of the canonical encoding of an Order. This
+// hash is used to create the ID and signature for an Order. It returns nil if
+// the Order cannot be encoded.
 func (order *Order) Hash() []byte {
-	return crypto.Keccak256(order.Bytes())
+	data, err := order.MarshalBinary()
+	if err != nil {
+		return nil
+	}
+	return crypto.Keccak256(data)
 }
 
 // Sign signs the order using the provided keypair, and assigns it the the order's
@@ -153,19 +295,28 @@ func (order *Order) VerifySignature(ID identity.ID) error {
 	return identity.VerifySignature(order, order.Signature, ID)
 }
 
-// Bytes returns an Order serialized into a bytes.
-func (order *Order) Bytes() []byte {
-	buf := new(bytes.Buffer)
-	binary.Write(buf, binary.LittleEndian, order.Type)
-	binary.Write(buf, binary.LittleEndian, order.Parity)
-	binary.Write(buf, binary.LittleEndian, order.Expiry)
-	binary.Write(buf, binary.LittleEndian, order.FstCode)
-	binary.Write(buf, binary.LittleEndian, order.SndCode)
-	binary.Write(buf, binary.LittleEndian, order.Price.Bytes())
-	binary.Write(buf, binary.LittleEndian, order.MaxVolume.Bytes())
-	binary.Write(buf, binary.LittleEndian, order.MinVolume.Bytes())
-	binary.Write(buf, binary.LittleEndian, order.Nonce.Bytes())
-	return buf.Bytes()
+// MarshalBinary implements the encoding.BinaryMarshaler interface. It returns
+// the canonical encoding of an Order, which covers every field except the
+// Signature and the ID. An error is returned if the Price, the MaxVolume, the
+// MinVolume, or the Nonce is nil. The OpenTime is optional, and is only
+// encoded when it is set.
+func (order *Order) MarshalBinary() ([]byte, error) {
+	encoder := canonical.NewEncoder(orderTag)
+	encoder.WriteInt64(int64(order.Type))
+	encoder.WriteInt64(int64(order.Parity))
+	encoder.WriteTime(order.Expiry)
+	encoder.WriteInt64(int64(order.TimeInForce))
+	encoder.WriteInt64(int64(order.FstCode))
+	encoder.WriteInt64(int64(order.SndCode))
+	encoder.WriteInt1024(order.Price)
+	encoder.WriteInt1024(order.MaxVolume)
+	encoder.WriteInt1024(order.MinVolume)
+	encoder.WriteInt1024(order.Nonce)
+	encoder.WriteFlag(!order.OpenTime.IsZero())
+	if !order.OpenTime.IsZero() {
+		encoder.WriteTime(order.OpenTime)
+	}
+	return encoder.Bytes()
 }
 
 // Equal returns an equality check between two Orders.
@@ -174,10 +325,12 @@ func (order *Order) Equal(other *Order) bool {
 		order.Type == other.Type &&
 		order.Parity == other.Parity &&
 		order.Expiry.Equal(other.Expiry) &&
+		order.TimeInForce == other.TimeInForce &&
 		order.FstCode == other.FstCode &&
 		order.SndCode == other.SndCode &&
 		order.Price.Cmp(other.Price) == 0 &&
 		order.MaxVolume.Cmp(other.MaxVolume) == 0 &&
 		order.MinVolume.Cmp(other.MinVolume) == 0 &&
-		order.Nonce.Cmp(other.Nonce) == 0
+		order.Nonce.Cmp(other.Nonce) == 0 &&
+		order.OpenTime.Equal(other.OpenTime)
 }
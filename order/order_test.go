@@ -1,12 +1,14 @@
 package order_test
 
 import (
+	"encoding/hex"
 	"time"
 
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
 	"github.com/republicprotocol/republic-go/identity"
 	. "github.com/republicprotocol/republic-go/order"
+	"github.com/republicprotocol/republic-go/shamir"
 	"github.com/republicprotocol/republic-go/stackint"
 )
 
@@ -73,6 +75,61 @@ var _ = Describe("Orders", func() {
 		})
 	})
 
+	Context("when encoding orders", func() {
+
+		expiry := time.Unix(1500000000, 0)
+
+		It("should return the golden encoding and ID", func() {
+			nonce := stackint.Zero()
+			ord := NewOrder(TypeLimit, ParityBuy, expiry, CurrencyCodeBTC, CurrencyCodeETH, &price, &maxVolume, &minVolume, &nonce)
+			data, err := ord.MarshalBinary()
+			Ω(err).ShouldNot(HaveOccurred())
+			Ω(hex.EncodeToString(data)).Should(Equal(goldenOrderEncoding))
+			Ω(hex.EncodeToString(ord.ID)).Should(Equal(goldenOrderID))
+		})
+
+		It("should write the open time after its flag", func() {
+			nonce := stackint.Zero()
+			ord := NewOrder(TypeLimit, ParityBuy, expiry, CurrencyCodeBTC, CurrencyCodeETH, &price, &maxVolume, &minVolume, &nonce).WithOpenTime(expiry)
+			data, err := ord.MarshalBinary()
+			Ω(err).ShouldNot(HaveOccurred())
+			Ω(hex.EncodeToString(data)).Should(Equal(goldenOrderFields + "01" + "002f685900000000"))
+		})
+
+		It("should return different IDs when any field is different", func() {
+			nonce := stackint.Zero()
+			one := stackint.One()
+			ord := NewOrder(TypeLimit, ParityBuy, expiry, CurrencyCodeBTC, CurrencyCodeETH, &price, &maxVolume, &minVolume, &nonce)
+			others := []*Order{
+				NewOrder(TypeIBBO, ParityBuy, expiry, CurrencyCodeBTC, CurrencyCodeETH, &price, &maxVolume, &minVolume, &nonce),
+				NewOrder(TypeLimit, ParitySell, expiry, CurrencyCodeBTC, CurrencyCodeETH, &price, &maxVolume, &minVolume, &nonce),
+				NewOrder(TypeLimit, ParityBuy, expiry.Add(time.Second), CurrencyCodeBTC, CurrencyCodeETH, &price, &maxVolume, &minVolume, &nonce),
+				NewOrder(TypeLimit, ParityBuy, expiry, CurrencyCodeBTC, CurrencyCodeETH, &price, &maxVolume, &minVolume, &nonce).WithTimeInForce(TimeInForceFOK),
+				NewOrder(TypeLimit, ParityBuy, expiry, CurrencyCodeBTC, CurrencyCodeETH, &price, &maxVolume, &minVolume, &nonce).WithOpenTime(expiry.Add(-time.Hour)),
+				NewOrder(TypeLimit, ParityBuy, expiry, CurrencyCodeREN, CurrencyCodeETH, &price, &maxVolume, &minVolume, &nonce),
+				NewOrder(TypeLimit, ParityBuy, expiry, CurrencyCodeBTC, CurrencyCodeREN, &price, &maxVolume, &minVolume, &nonce),
+				NewOrder(TypeLimit, ParityBuy, expiry, CurrencyCodeBTC, CurrencyCodeETH, &one, &maxVolume, &minVolume, &nonce),
+				NewOrder(TypeLimit, ParityBuy, expiry, CurrencyCodeBTC, CurrencyCodeETH, &price, &one, &minVolume, &nonce),
+				NewOrder(TypeLimit, ParityBuy, expiry, CurrencyCodeBTC, CurrencyCodeETH, &price, &maxVolume, &one, &nonce),
+				NewOrder(TypeLimit, ParityBuy, expiry, CurrencyCodeBTC, CurrencyCodeETH, &price, &maxVolume, &minVolume, &one),
+			}
+			for _, other := range others {
+				Ω(other.ID.Equal(ord.ID)).Should(BeFalse())
+			}
+		})
+
+		It("should return an error for orders with missing fields", func() {
+			ord := NewOrder(TypeLimit, ParityBuy, expiry, CurrencyCodeBTC, CurrencyCodeETH, &price, &maxVolume, &minVolume, nil)
+			_, err := ord.MarshalBinary()
+			Ω(err).Should(HaveOccurred())
+			Ω(ord.Hash()).Should(BeNil())
+
+			keyPair, err := identity.NewKeyPair()
+			Ω(err).ShouldNot(HaveOccurred())
+			Ω(ord.Sign(keyPair)).Should(HaveOccurred())
+		})
+	})
+
 	Context("when splitting orders", func() {
 
 		It("should return the correct number of order fragments", func() {
@@ -102,6 +159,181 @@ var _ = Describe("Orders", func() {
 				}
 			}
 		})
+
+		It("should record the field of the prime in order fragments", func() {
+			field, err := shamir.FieldByName(shamir.FieldTest)
+			Ω(err).ShouldNot(HaveOccurred())
+
+			nonce := stackint.Zero()
+			order := NewOrder(TypeLimit, ParityBuy, time.Now().Add(time.Hour), CurrencyCodeBTC, CurrencyCodeETH, &price, &maxVolume, &minVolume, &nonce)
+			fragments, err := order.Split(n, k, field.Prime)
+			Ω(err).ShouldNot(HaveOccurred())
+			for i := range fragments {
+				Ω(field.Verify(fragments[i].Field)).ShouldNot(HaveOccurred())
+				Ω(fragments[i].Hash()).Should(Equal(fragments[i].ID))
+			}
+		})
+
+		It("should record the time in force in order fragments", func() {
+			nonce := stackint.Zero()
+			expiry := time.Now().Add(time.Hour)
+			gtc := NewOrder(TypeLimit, ParityBuy, expiry, CurrencyCodeBTC, CurrencyCodeETH, &price, &maxVolume, &minVolume, &nonce)
+			ioc := NewOrder(TypeLimit, ParityBuy, expiry, CurrencyCodeBTC, CurrencyCodeETH, &price, &maxVolume, &minVolume, &nonce).WithTimeInForce(TimeInForceIOC)
+			Ω(gtc.TimeInForce).Should(Equal(TimeInForceGTC))
+			Ω(ioc.ID.Equal(ID(ioc.Hash()))).Should(BeTrue())
+			Ω(ioc.ID.Equal(gtc.ID)).Should(BeFalse())
+			Ω(ioc.Equal(gtc)).Should(BeFalse())
+
+			field, err := shamir.FieldByName(shamir.FieldTest)
+			Ω(err).ShouldNot(HaveOccurred())
+			fragments, err := ioc.Split(n, k, field.Prime)
+			Ω(err).ShouldNot(HaveOccurred())
+			for i := range fragments {
+				Ω(fragments[i].OrderTimeInForce).Should(Equal(TimeInForceIOC))
+			}
+		})
+
+		It("should record the nonce in order fragments", func() {
+			nonce := stackint.FromUint(42)
+			field, err := shamir.FieldByName(shamir.FieldTest)
+			Ω(err).ShouldNot(HaveOccurred())
+			fragments, err := NewOrder(TypeLimit, ParityBuy, time.Now().Add(time.Hour), CurrencyCodeBTC, CurrencyCodeETH, &price, &maxVolume, &minVolume, &nonce).Split(n, k, field.Prime)
+			Ω(err).ShouldNot(HaveOccurred())
+			for i := range fragments {
+				Ω(fragments[i].OrderNonce.Cmp(&nonce)).Should(Equal(0))
+			}
+
+			// The nonce is covered by the ID
+			other := *fragments[0]
+			other.OrderNonce = nil
+			Ω(other.Hash()).ShouldNot(Equal(fragments[0].ID))
+		})
+
+		It("should record the open time in order fragments", func() {
+			nonce := stackint.Zero()
+			openTime := time.Unix(time.Now().Unix(), 0)
+			field, err := shamir.FieldByName(shamir.FieldTest)
+			Ω(err).ShouldNot(HaveOccurred())
+			fragments, err := NewOrder(TypeLimit, ParityBuy, openTime.Add(time.Hour), CurrencyCodeBTC, CurrencyCodeETH, &price, &maxVolume, &minVolume, &nonce).WithTimeInForce(TimeInForceIOC).WithOpenTime(openTime).Split(n, k, field.Prime)
+			Ω(err).ShouldNot(HaveOccurred())
+			for i := range fragments {
+				Ω(fragments[i].OrderOpenTime.Equal(openTime)).Should(BeTrue())
+			}
+
+			// The open time is covered by the ID
+			other := *fragments[0]
+			other.OrderOpenTime = openTime.Add(time.Second)
+			Ω(other.Hash()).ShouldNot(Equal(fragments[0].ID))
+		})
+	})
+
+	Context("when splitting orders verifiably", func() {
+
+		prime, _ := stackint.FromString("179769313486231590772930519078902473361797697894230657273430081157732675805500963132708477322407536021120113879871393357658789768814416622492847430639474124377767893424865485276302219601246094119453082952085005768838150682342462881473913110540827237163350510684586298239947245938479716304835356329624224137111")
+		vss, _ := shamir.NewVSS(&prime)
+
+		It("should return order fragments that can be verified against their commitments", func() {
+			nonce := stackint.Zero()
+			order := NewOrder(TypeLimit, ParityBuy, time.Now().Add(time.Hour), CurrencyCodeBTC, CurrencyCodeETH, &price, &maxVolume, &minVolume, &nonce)
+			fragments, err := order.SplitVerifiable(5, 4, vss, nil)
+			Ω(err).ShouldNot(HaveOccurred())
+			Ω(fragments).Should(HaveLen(5))
+			for _, fragment := range fragments {
+				Ω(fragment.VerifyCommitments(vss, 4)).ShouldNot(HaveOccurred())
+			}
+			Ω(shamir.Join(&prime, shamir.Shares{fragments[0].PriceShare, fragments[2].PriceShare, fragments[3].PriceShare, fragments[4].PriceShare}).Cmp(&price)).Should(Equal(0))
+		})
+
+		It("should return a CommitmentError for inconsistent order fragments", func() {
+			nonce := stackint.Zero()
+			order := NewOrder(TypeLimit, ParityBuy, time.Now().Add(time.Hour), CurrencyCodeBTC, CurrencyCodeETH, &price, &maxVolume, &minVolume, &nonce)
+			fragments, err := order.SplitVerifiable(5, 4, vss, nil)
+			Ω(err).ShouldNot(HaveOccurred())
+
+			// The trader gives a dark node a share of a different volume
+			otherVolume := stackint.FromUint(1)
+			others, err := NewOrder(TypeLimit, ParityBuy, time.Now().Add(time.Hour), CurrencyCodeBTC, CurrencyCodeETH, &price, &otherVolume, &minVolume, &nonce).SplitVerifiable(5, 4, vss, nil)
+			Ω(err).ShouldNot(HaveOccurred())
+			fragments[0].MaxVolumeShare = others[0].MaxVolumeShare
+			Ω(fragments[0].VerifyCommitments(vss, 4)).Should(BeAssignableToTypeOf(shamir.CommitmentError("")))
+
+			// Order fragments without commitments cannot be verified
+			fragments, err = order.Split(5, 4, &prime)
+			Ω(err).ShouldNot(HaveOccurred())
+			Ω(fragments[0].VerifyCommitments(vss, 4)).Should(HaveOccurred())
+		})
+
+		It("should sign the commitments of order fragments", func() {
+			nonce := stackint.Zero()
+			order := NewOrder(TypeLimit, ParityBuy, time.Now().Add(time.Hour), CurrencyCodeBTC, CurrencyCodeETH, &price, &maxVolume, &minVolume, &nonce)
+			fragments, err := order.SplitVerifiable(5, 4, vss, nil)
+			Ω(err).ShouldNot(HaveOccurred())
+			keyPair, err := identity.NewKeyPair()
+			Ω(err).ShouldNot(HaveOccurred())
+			Ω(SignFragments(keyPair, fragments)).ShouldNot(HaveOccurred())
+			Ω(fragments[0].Verify()).ShouldNot(HaveOccurred())
+
+			fragments[0].Commitments = fragments[1].Commitments
+			Ω(fragments[0].Verify()).Should(HaveOccurred())
+		})
+
+		It("should return a CommitmentError for commitments to polynomials of a different degree", func() {
+			nonce := stackint.Zero()
+			order := NewOrder(TypeLimit, ParityBuy, time.Now().Add(time.Hour), CurrencyCodeBTC, CurrencyCodeETH, &price, &maxVolume, &minVolume, &nonce)
+			fragments, err := order.SplitVerifiable(5, 4, vss, nil)
+			Ω(err).ShouldNot(HaveOccurred())
+			Ω(fragments[0].VerifyCommitments(vss, 3)).Should(BeAssignableToTypeOf(shamir.CommitmentError("")))
+			Ω(fragments[0].VerifyCommitments(vss, 5)).Should(BeAssignableToTypeOf(shamir.CommitmentError("")))
+		})
+
+		It("should hash the same commitments for every order fragment", func() {
+			nonce := stackint.Zero()
+			order := NewOrder(TypeLimit, ParityBuy, time.Now().Add(time.Hour), CurrencyCodeBTC, CurrencyCodeETH, &price, &maxVolume, &minVolume, &nonce)
+			fragments, err := order.SplitVerifiable(5, 4, vss, nil)
+			Ω(err).ShouldNot(HaveOccurred())
+			hash, err := fragments[0].Commitments.Hash()
+			Ω(err).ShouldNot(HaveOccurred())
+			for _, fragment := range fragments[1:] {
+				Ω(fragment.Commitments.Hash()).Should(Equal(hash))
+			}
+
+			// The commitments of another split are different
+			others, err := order.SplitVerifiable(5, 4, vss, nil)
+			Ω(err).ShouldNot(HaveOccurred())
+			Ω(others[0].Commitments.Hash()).ShouldNot(Equal(hash))
+		})
+
+		It("should prove that order fragments are in one of the given markets", func() {
+			markets := [][2]CurrencyCode{{CurrencyCodeETH, CurrencyCodeBTC}, {CurrencyCodeBTC, CurrencyCodeETH}}
+			nonce := stackint.Zero()
+			order := NewOrder(TypeLimit, ParityBuy, time.Now().Add(time.Hour), CurrencyCodeBTC, CurrencyCodeETH, &price, &maxVolume, &minVolume, &nonce)
+			fragments, err := order.SplitVerifiable(5, 4, vss, markets)
+			Ω(err).ShouldNot(HaveOccurred())
+			for _, fragment := range fragments {
+				Ω(fragment.VerifyCommitments(vss, 4)).ShouldNot(HaveOccurred())
+				Ω(fragment.VerifyMarket(vss)).ShouldNot(HaveOccurred())
+				Ω(fragment.Commitments.Markets).Should(Equal(markets))
+			}
+
+			// The proof does not hold for other markets
+			fragments[0].Commitments.Markets = [][2]CurrencyCode{{CurrencyCodeETH, CurrencyCodeBTC}, {CurrencyCodeREN, CurrencyCodeETH}}
+			Ω(fragments[0].VerifyMarket(vss)).Should(Equal(shamir.ErrInvalidMembershipProof))
+
+			// The market is covered by the ID
+			Ω(fragments[0].Hash()).ShouldNot(Equal(fragments[0].ID))
+		})
+
+		It("should not prove that order fragments are in markets that are not given", func() {
+			nonce := stackint.Zero()
+			order := NewOrder(TypeLimit, ParityBuy, time.Now().Add(time.Hour), CurrencyCodeREN, CurrencyCodeBTC, &price, &maxVolume, &minVolume, &nonce)
+			_, err := order.SplitVerifiable(5, 4, vss, [][2]CurrencyCode{{CurrencyCodeREN, CurrencyCodeETH}, {CurrencyCodeETH, CurrencyCodeBTC}})
+			Ω(err).Should(Equal(shamir.ErrInvalidMembershipProof))
+
+			// Order fragments without a proof cannot be verified
+			fragments, err := order.SplitVerifiable(5, 4, vss, nil)
+			Ω(err).ShouldNot(HaveOccurred())
+			Ω(fragments[0].VerifyMarket(vss)).Should(Equal(shamir.ErrInvalidMembershipProof))
+		})
 	})
 
 	Context("when being signed", func() {
@@ -148,3 +380,119 @@ var _ = Describe("Orders", func() {
 		})
 	})
 })
+
+var _ = Describe("Cancellations", func() {
+
+	price := stackint.FromUint(10)
+	minVolume := stackint.FromUint(100)
+	maxVolume := stackint.FromUint(1000)
+	nonce := stackint.Zero()
+	order := NewOrder(TypeLimit, ParityBuy, time.Now().Add(time.Hour), CurrencyCodeBTC, CurrencyCodeETH, &price, &maxVolume, &minVolume, &nonce)
+
+	It("can be signed and verified", func() {
+		keyPair, err := identity.NewKeyPair()
+		Ω(err).ShouldNot(HaveOccurred())
+
+		cancellation := NewCancellation(order.ID)
+		Ω(cancellation.Sign(keyPair)).ShouldNot(HaveOccurred())
+		Ω(cancellation.VerifySignature(keyPair.ID())).ShouldNot(HaveOccurred())
+	})
+
+	It("should not be interchangeable with an order signature", func() {
+		keyPair, err := identity.NewKeyPair()
+		Ω(err).ShouldNot(HaveOccurred())
+
+		Ω(order.Sign(keyPair)).ShouldNot(HaveOccurred())
+		cancellation := NewCancellation(order.ID)
+		cancellation.Signature = order.Signature
+		Ω(cancellation.VerifySignature(keyPair.ID())).Should(Equal(identity.ErrInvalidSignature))
+	})
+})
+
+var _ = Describe("Subscriptions", func() {
+
+	It("can be signed and verified within the subscription window", func() {
+		keyPair, err := identity.NewKeyPair()
+		Ω(err).ShouldNot(HaveOccurred())
+
+		now := time.Now()
+		subscription := NewSubscription(keyPair.ID(), now)
+		Ω(subscription.Sign(keyPair)).ShouldNot(HaveOccurred())
+		Ω(subscription.Verify(now)).ShouldNot(HaveOccurred())
+		Ω(subscription.Verify(now.Add(SubscriptionWindow + time.Second))).Should(Equal(ErrSubscriptionExpired))
+		Ω(subscription.Verify(now.Add(-SubscriptionWindow - time.Second))).Should(Equal(ErrSubscriptionExpired))
+	})
+
+	It("should not be verified for another trader", func() {
+		keyPair, err := identity.NewKeyPair()
+		Ω(err).ShouldNot(HaveOccurred())
+		otherKeyPair, err := identity.NewKeyPair()
+		Ω(err).ShouldNot(HaveOccurred())
+
+		subscription := NewSubscription(otherKeyPair.ID(), time.Now())
+		Ω(subscription.Sign(keyPair)).ShouldNot(HaveOccurred())
+		Ω(subscription.Verify(time.Now())).Should(Equal(identity.ErrInvalidSignature))
+	})
+})
+
+var _ = Describe("Notifications", func() {
+
+	It("can be signed and verified", func() {
+		keyPair, err := identity.NewKeyPair()
+		Ω(err).ShouldNot(HaveOccurred())
+
+		notification := NewNotification(NotificationMatch, ID("buy"), ID("sell"))
+		Ω(notification.Sign(keyPair)).ShouldNot(HaveOccurred())
+		Ω(notification.VerifySignature(keyPair.ID())).ShouldNot(HaveOccurred())
+
+		notification.Type = NotificationCancelled
+		Ω(notification.VerifySignature(keyPair.ID())).Should(Equal(identity.ErrInvalidSignature))
+	})
+
+	It("should not confuse the order ID with the counterparty order ID", func() {
+		notification := NewNotification(NotificationMatch, ID("buy"), ID("sell"))
+		other := NewNotification(NotificationMatch, ID("buys"), ID("ell"))
+		Ω(notification.Hash()).ShouldNot(Equal(other.Hash()))
+	})
+})
+
+var _ = Describe("Reveals", func() {
+
+	price := stackint.FromUint(10)
+	minVolume := stackint.FromUint(100)
+	maxVolume := stackint.FromUint(1000)
+	nonce := stackint.Zero()
+	order := NewOrder(TypeLimit, ParityBuy, time.Now().Add(time.Hour), CurrencyCodeBTC, CurrencyCodeETH, &price, &maxVolume, &minVolume, &nonce)
+
+	It("can be signed and verified", func() {
+		keyPair, err := identity.NewKeyPair()
+		Ω(err).ShouldNot(HaveOccurred())
+
+		reveal := NewReveal(keyPair.ID(), order, []byte("account"))
+		Ω(reveal.Sign(keyPair)).ShouldNot(HaveOccurred())
+		Ω(reveal.Verify()).ShouldNot(HaveOccurred())
+
+		reveal.Account = []byte("other account")
+		Ω(reveal.Verify()).Should(Equal(identity.ErrInvalidSignature))
+	})
+
+	It("should not be verified for orders that do not match their ID", func() {
+		keyPair, err := identity.NewKeyPair()
+		Ω(err).ShouldNot(HaveOccurred())
+
+		otherPrice := stackint.FromUint(11)
+		changed := *order
+		changed.Price = &otherPrice
+		reveal := NewReveal(keyPair.ID(), &changed, []byte("account"))
+		Ω(reveal.Sign(keyPair)).ShouldNot(HaveOccurred())
+		Ω(reveal.Verify()).Should(Equal(ErrRevealMismatch))
+	})
+})
+
+// goldenOrderFields is the encoding of the fields of an Order that are
+// written before its optional open time.
+const goldenOrderFields = "031800000052657075626c69632050726f746f636f6c3a206f7264657202000000000000000100000000000000002f685900000000000000000000000001000000000000000200000000000000010000000a0200000003e8010000006400000000"
+
+const goldenOrderEncoding = goldenOrderFields + "00"
+
+const goldenOrderID = "dc3c4ecfa541bbb551f836cf1b93a11399d25575359ceb76161a394c8beead2b"
@@ -0,0 +1,85 @@
+package order
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/republicprotocol/republic-go/identity"
+)
+
+// ErrRevealMismatch is returned when the Order of a Reveal does not hash to
+// its ID.
+var ErrRevealMismatch = errors.New("revealed order does not match its id")
+
+// revealPrefix is prepended to a Reveal before hashing so that a Reveal
+// signature cannot be mistaken for any other signature.
+var revealPrefix = []byte("Republic Protocol: reveal: ")
+
+// A Reveal discloses an Order to the dark nodes after it has been matched, so
+// that the match can be settled. The Account is the account of the trader on
+// the settlement layer. It must be signed by the trader that opened the Order.
+type Reveal struct {
+	Signature identity.Signature
+	Trader    identity.ID
+	Order     *Order
+	Account   []byte
+}
+
+// NewReveal returns a new, unsigned, Reveal of an Order opened by the trader
+// with the given ID.
+func NewReveal(trader identity.ID, order *Order, account []byte) *Reveal {
+	return &Reveal{
+		Trader:  trader,
+		Order:   order,
+		Account: account,
+	}
+}
+
+// Hash returns the Keccak256 hash of a Reveal. This hash is used to create the
+// signature for a Reveal.
+func (reveal *Reveal) Hash() []byte {
+	return crypto.Keccak256(reveal.Bytes())
+}
+
+// Sign signs the Reveal using the provided keypair, and assigns it the the
+// Reveal's Signature field.
+func (reveal *Reveal) Sign(keyPair identity.KeyPair) error {
+	var err error
+	reveal.Signature, err = keyPair.Sign(reveal)
+	return err
+}
+
+// Verify checks that the Reveal was signed by its trader, and that its Order
+// hashes to the ID of the Order, so that the Order cannot have been changed
+// after it was opened.
+func (reveal *Reveal) Verify() error {
+	if reveal.Order == nil {
+		return ErrRevealMismatch
+	}
+	if err := identity.VerifySignature(reveal, reveal.Signature, reveal.Trader); err != nil {
+		return err
+	}
+	if !bytes.Equal(reveal.Order.Hash(), reveal.Order.ID) {
+		return ErrRevealMismatch
+	}
+	return nil
+}
+
+// Bytes returns a Reveal serialized into a bytes. The Order is identified by
+// its ID, which is the hash of the Order.
+func (reveal *Reveal) Bytes() []byte {
+	buf := new(bytes.Buffer)
+	buf.Write(revealPrefix)
+	buf.Write(reveal.Trader)
+	if reveal.Order != nil {
+		binary.Write(buf, binary.LittleEndian, uint32(len(reveal.Order.ID)))
+		buf.Write(reveal.Order.ID)
+	} else {
+		binary.Write(buf, binary.LittleEndian, uint32(0))
+	}
+	binary.Write(buf, binary.LittleEndian, uint32(len(reveal.Account)))
+	buf.Write(reveal.Account)
+	return buf.Bytes()
+}
@@ -0,0 +1,183 @@
+package settlement
+
+import (
+	"bytes"
+	"errors"
+
+	"github.com/republicprotocol/go-do"
+	"github.com/republicprotocol/republic-go/identity"
+	"github.com/republicprotocol/republic-go/order"
+	"github.com/republicprotocol/republic-go/stackint"
+)
+
+// ErrUnmatchedOrder is returned when an order is revealed before it has been
+// matched, or after all of its matches are ready to be settled.
+var ErrUnmatchedOrder = errors.New("order has not been matched")
+
+// ErrUnexpectedTrader is returned when an order is revealed by a trader that
+// did not open it.
+var ErrUnexpectedTrader = errors.New("order revealed by unexpected trader")
+
+// bookOrder is an order that is waiting to be settled. It is referenced by
+// every pending Match that it is part of.
+type bookOrder struct {
+	trader  identity.ID
+	reveal  *order.Reveal
+	matches int
+}
+
+// A Book pairs finalized matches with their fills and with the Reveals of
+// their traders. A Match is ready to be settled once its fill has been
+// executed and both of its orders have been revealed. Reveals are only
+// accepted for orders that are part of a pending Match, and are forgotten once
+// all of these Matches are ready, so traders reveal their order again for
+// every match of a partially filled order.
+type Book struct {
+	do.GuardedObject
+
+	pending  map[string]*Match
+	orders   map[string]*bookOrder
+	statuses map[string]Status
+}
+
+// NewBook returns an empty Book.
+func NewBook() *Book {
+	return &Book{
+		GuardedObject: do.NewGuardedObject(),
+		pending:       map[string]*Match{},
+		orders:        map[string]*bookOrder{},
+		statuses:      map[string]Status{},
+	}
+}
+
+// InsertMatch inserts a finalized match between two orders, and the traders
+// that opened them. Each match is only inserted once.
+func (book *Book) InsertMatch(id []byte, buyOrderID, sellOrderID order.ID, buyTrader, sellTrader identity.ID) {
+	book.Enter(nil)
+	defer book.Exit()
+
+	if _, ok := book.statuses[string(id)]; ok {
+		return
+	}
+	book.pending[string(id)] = &Match{
+		ID:          id,
+		BuyOrderID:  buyOrderID,
+		SellOrderID: sellOrderID,
+	}
+	book.statuses[string(id)] = StatusPending
+	book.insertOrder(buyOrderID, buyTrader)
+	book.insertOrder(sellOrderID, sellTrader)
+}
+
+// InsertFill sets the executed volume of a match, and the price at which it
+// is executed. A nil price is used for matches between limit orders, which are
+// executed at the midpoint of their prices once they are revealed. The Match
+// is returned if it is ready to be settled, otherwise nil is returned.
+func (book *Book) InsertFill(id []byte, volume, price *stackint.Int1024) *Match {
+	book.Enter(nil)
+	defer book.Exit()
+
+	match, ok := book.pending[string(id)]
+	if !ok {
+		return nil
+	}
+	match.Volume = volume
+	match.Price = price
+	return book.complete(match)
+}
+
+// InsertReveal verifies a Reveal and inserts it for every pending Match of its
+// order. The Matches that are ready to be settled are returned. An
+// ErrUnmatchedOrder is returned if the order is not part of a pending Match,
+// and an ErrUnexpectedTrader is returned if the Reveal was signed by a trader
+// that did not open the order.
+func (book *Book) InsertReveal(reveal *order.Reveal) ([]*Match, error) {
+	if err := reveal.Verify(); err != nil {
+		return nil, err
+	}
+
+	book.Enter(nil)
+	defer book.Exit()
+
+	bookOrder, ok := book.orders[string(reveal.Order.ID)]
+	if !ok {
+		return nil, ErrUnmatchedOrder
+	}
+	if !bytes.Equal(bookOrder.trader, reveal.Trader) {
+		return nil, ErrUnexpectedTrader
+	}
+	bookOrder.reveal = reveal
+
+	matches := []*Match{}
+	for _, match := range book.pending {
+		if !match.BuyOrderID.Equal(reveal.Order.ID) && !match.SellOrderID.Equal(reveal.Order.ID) {
+			continue
+		}
+		if match := book.complete(match); match != nil {
+			matches = append(matches, match)
+		}
+	}
+	return matches, nil
+}
+
+// Settled records the outcome of the settlement of a Match. A nil error
+// records the Match as settled, otherwise it is recorded as failed. The Status
+// of the Match is returned.
+func (book *Book) Settled(id []byte, err error) Status {
+	book.Enter(nil)
+	defer book.Exit()
+
+	status := StatusSettled
+	if err != nil {
+		status = StatusFailed
+	}
+	book.statuses[string(id)] = status
+	return status
+}
+
+// Status returns the Status of a Match.
+func (book *Book) Status(id []byte) Status {
+	book.EnterReadOnly(nil)
+	defer book.ExitReadOnly()
+	return book.statuses[string(id)]
+}
+
+func (book *Book) insertOrder(orderID order.ID, trader identity.ID) {
+	if bookOrder, ok := book.orders[string(orderID)]; ok {
+		bookOrder.matches++
+		return
+	}
+	book.orders[string(orderID)] = &bookOrder{
+		trader:  trader,
+		matches: 1,
+	}
+}
+
+func (book *Book) removeOrder(orderID order.ID) {
+	bookOrder, ok := book.orders[string(orderID)]
+	if !ok {
+		return
+	}
+	if bookOrder.matches--; bookOrder.matches == 0 {
+		delete(book.orders, string(orderID))
+	}
+}
+
+// complete removes a pending Match from the Book, and returns it, if it has
+// been filled and both of its orders have been revealed. Otherwise, it
+// returns nil.
+func (book *Book) complete(match *Match) *Match {
+	buy, sell := book.orders[string(match.BuyOrderID)], book.orders[string(match.SellOrderID)]
+	if match.Volume == nil || buy == nil || buy.reveal == nil || sell == nil || sell.reveal == nil {
+		return nil
+	}
+	match.Buy = buy.reveal
+	match.Sell = sell.reveal
+	if match.Price == nil {
+		match.Price = midpoint(match.Buy.Order, match.Sell.Order)
+	}
+	delete(book.pending, string(match.ID))
+	book.removeOrder(match.BuyOrderID)
+	book.removeOrder(match.SellOrderID)
+	return match
+}
@@ -0,0 +1,129 @@
+package settlement_test
+
+import (
+	"errors"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/republicprotocol/republic-go/identity"
+	"github.com/republicprotocol/republic-go/order"
+	. "github.com/republicprotocol/republic-go/settlement"
+	"github.com/republicprotocol/republic-go/stackint"
+)
+
+var _ = Describe("Books", func() {
+
+	var buyer, seller identity.KeyPair
+	var buy, sell *order.Order
+	var book *Book
+	matchID := []byte("match")
+
+	newOrder := func(parity order.Parity, price uint) *order.Order {
+		p := stackint.FromUint(price)
+		maxVolume := stackint.FromUint(1000)
+		minVolume := stackint.FromUint(100)
+		nonce := stackint.FromUint(uint(time.Now().UnixNano()))
+		return order.NewOrder(order.TypeLimit, parity, time.Now().Add(time.Hour), order.CurrencyCodeBTC, order.CurrencyCodeETH, &p, &maxVolume, &minVolume, &nonce)
+	}
+
+	newReveal := func(keyPair identity.KeyPair, ord *order.Order) *order.Reveal {
+		reveal := order.NewReveal(keyPair.ID(), ord, []byte(keyPair.Address()))
+		Ω(reveal.Sign(keyPair)).ShouldNot(HaveOccurred())
+		return reveal
+	}
+
+	BeforeEach(func() {
+		var err error
+		buyer, err = identity.NewKeyPair()
+		Ω(err).ShouldNot(HaveOccurred())
+		seller, err = identity.NewKeyPair()
+		Ω(err).ShouldNot(HaveOccurred())
+		buy = newOrder(order.ParityBuy, 12)
+		sell = newOrder(order.ParitySell, 10)
+		book = NewBook()
+		book.InsertMatch(matchID, buy.ID, sell.ID, buyer.ID(), seller.ID())
+	})
+
+	It("should return the match once it is filled and both orders are revealed", func() {
+		volume := stackint.FromUint(500)
+		Ω(book.InsertFill(matchID, &volume, nil)).Should(BeNil())
+
+		matches, err := book.InsertReveal(newReveal(buyer, buy))
+		Ω(err).ShouldNot(HaveOccurred())
+		Ω(matches).Should(BeEmpty())
+		matches, err = book.InsertReveal(newReveal(seller, sell))
+		Ω(err).ShouldNot(HaveOccurred())
+		Ω(matches).Should(HaveLen(1))
+
+		match := matches[0]
+		Ω(match.ID).Should(Equal(matchID))
+		Ω(match.Buy.Order).Should(Equal(buy))
+		Ω(match.Sell.Order).Should(Equal(sell))
+		Ω(match.Volume.Cmp(&volume)).Should(Equal(0))
+		Ω(book.Status(matchID)).Should(Equal(StatusPending))
+	})
+
+	It("should execute limit orders at the midpoint of their prices", func() {
+		_, err := book.InsertReveal(newReveal(buyer, buy))
+		Ω(err).ShouldNot(HaveOccurred())
+		_, err = book.InsertReveal(newReveal(seller, sell))
+		Ω(err).ShouldNot(HaveOccurred())
+
+		volume := stackint.FromUint(500)
+		match := book.InsertFill(matchID, &volume, nil)
+		Ω(match).ShouldNot(BeNil())
+		price := stackint.FromUint(11)
+		Ω(match.Price.Cmp(&price)).Should(Equal(0))
+	})
+
+	It("should execute orders at the price of the fill when there is one", func() {
+		_, err := book.InsertReveal(newReveal(buyer, buy))
+		Ω(err).ShouldNot(HaveOccurred())
+		_, err = book.InsertReveal(newReveal(seller, sell))
+		Ω(err).ShouldNot(HaveOccurred())
+
+		volume := stackint.FromUint(500)
+		price := stackint.FromUint(7)
+		match := book.InsertFill(matchID, &volume, &price)
+		Ω(match).ShouldNot(BeNil())
+		Ω(match.Price.Cmp(&price)).Should(Equal(0))
+	})
+
+	It("should reject reveals of orders that have not been matched", func() {
+		_, err := book.InsertReveal(newReveal(buyer, newOrder(order.ParityBuy, 12)))
+		Ω(err).Should(Equal(ErrUnmatchedOrder))
+	})
+
+	It("should reject reveals from traders that did not open the order", func() {
+		_, err := book.InsertReveal(newReveal(seller, buy))
+		Ω(err).Should(Equal(ErrUnexpectedTrader))
+	})
+
+	It("should reject reveals that cannot be verified", func() {
+		reveal := newReveal(buyer, buy)
+		reveal.Account = []byte(seller.Address())
+		_, err := book.InsertReveal(reveal)
+		Ω(err).Should(Equal(identity.ErrInvalidSignature))
+	})
+
+	It("should forget reveals once the match is ready", func() {
+		volume := stackint.FromUint(500)
+		book.InsertFill(matchID, &volume, nil)
+		_, err := book.InsertReveal(newReveal(buyer, buy))
+		Ω(err).ShouldNot(HaveOccurred())
+		_, err = book.InsertReveal(newReveal(seller, sell))
+		Ω(err).ShouldNot(HaveOccurred())
+
+		_, err = book.InsertReveal(newReveal(buyer, buy))
+		Ω(err).Should(Equal(ErrUnmatchedOrder))
+	})
+
+	It("should record the outcome of settlements", func() {
+		Ω(book.Status([]byte("unknown"))).Should(Equal(StatusUnknown))
+		Ω(book.Settled(matchID, nil)).Should(Equal(StatusSettled))
+		Ω(book.Status(matchID)).Should(Equal(StatusSettled))
+		Ω(book.Settled(matchID, errors.New("rejected"))).Should(Equal(StatusFailed))
+		Ω(book.Status(matchID)).Should(Equal(StatusFailed))
+	})
+})
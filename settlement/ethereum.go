@@ -0,0 +1,165 @@
+package settlement
+
+import (
+	"bytes"
+	"context"
+	"errors"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/republicprotocol/republic-go/contracts/bindings"
+	"github.com/republicprotocol/republic-go/contracts/connection"
+	"github.com/republicprotocol/republic-go/identity"
+)
+
+// ErrInvalidAccount is returned when a trader reveals an account that is not
+// an Ethereum address.
+var ErrInvalidAccount = errors.New("invalid ethereum account")
+
+// ErrInvalidID is returned when the ID of a Match, or of one of its orders, is
+// not 32 bytes long.
+var ErrInvalidID = errors.New("invalid id length")
+
+// ErrSettlementFailed is returned when the settlement contract rejects a Match
+// that has not been settled.
+var ErrSettlementFailed = errors.New("settlement failed")
+
+// ErrSettlementMismatch is returned when a Match has already been settled with
+// parameters that are different from the parameters of the Match.
+var ErrSettlementMismatch = errors.New("settlement mismatch")
+
+// An EthereumSettler is a Settler that settles Matches using the settlement
+// contract on Ethereum. The accounts revealed by the traders are the Ethereum
+// addresses of the buyer and the seller. The settlement contract only accepts
+// Matches from the owner of a registered dark node, so the transactions must
+// be signed by the account that registered the dark node.
+type EthereumSettler struct {
+	context      context.Context
+	client       *connection.ClientDetails
+	transactOpts *bind.TransactOpts
+	callOpts     *bind.CallOpts
+	binding      *bindings.Settlement
+	darkNodeID   [20]byte
+}
+
+// NewEthereumSettler returns an EthereumSettler that uses the settlement
+// contract deployed at the given address, on behalf of the dark node with the
+// given ID.
+func NewEthereumSettler(context context.Context, clientDetails *connection.ClientDetails, transactOpts *bind.TransactOpts, callOpts *bind.CallOpts, address common.Address, darkNodeID identity.ID) (*EthereumSettler, error) {
+	contract, err := bindings.NewSettlement(address, bind.ContractBackend(clientDetails.Client))
+	if err != nil {
+		return nil, err
+	}
+	return &EthereumSettler{
+		context:      context,
+		client:       clientDetails,
+		transactOpts: transactOpts,
+		callOpts:     callOpts,
+		binding:      contract,
+		darkNodeID:   toBytes20(darkNodeID),
+	}, nil
+}
+
+// Settle implements the Settler interface. It waits for the settlement
+// transaction to be mined. A Match that was already settled, by this or by
+// another dark node, is not settled again. An ErrSettlementMismatch is
+// returned if it was settled with different parameters.
+func (settler *EthereumSettler) Settle(match *Match) error {
+	matchID, err := toBytes32(match.ID)
+	if err != nil {
+		return err
+	}
+	buyOrderID, err := toBytes32(match.BuyOrderID)
+	if err != nil {
+		return err
+	}
+	sellOrderID, err := toBytes32(match.SellOrderID)
+	if err != nil {
+		return err
+	}
+	if len(match.Buy.Account) != common.AddressLength || len(match.Sell.Account) != common.AddressLength {
+		return ErrInvalidAccount
+	}
+	buyer := common.BytesToAddress(match.Buy.Account)
+	seller := common.BytesToAddress(match.Sell.Account)
+	volume := match.Volume.ToBigInt()
+	price := match.Price.ToBigInt()
+
+	// The settlement contract stores the hash of the tightly packed
+	// parameters of each Match
+	hash := crypto.Keccak256(buyOrderID[:], sellOrderID[:], buyer.Bytes(), seller.Bytes(), common.BigToHash(volume).Bytes(), common.BigToHash(price).Bytes())
+	if settled, err := settler.isSettledWith(matchID, hash); settled || err != nil {
+		return err
+	}
+
+	tx, err := settler.binding.Settle(settler.transactOpts, settler.darkNodeID, matchID, buyOrderID, sellOrderID, buyer, seller, volume, price)
+	if err != nil {
+		// The transaction cannot be sent when another dark node settles the
+		// Match first
+		if settled, settledErr := settler.isSettledWith(matchID, hash); settled || settledErr != nil {
+			return settledErr
+		}
+		return err
+	}
+	receipt, err := settler.client.PatchedWaitMined(settler.context, tx)
+	if err != nil {
+		return err
+	}
+	if receipt != nil && receipt.Status != types.ReceiptStatusFailed {
+		return nil
+	}
+
+	// The transaction fails when another dark node settles the Match first
+	settled, err := settler.isSettledWith(matchID, hash)
+	if err != nil {
+		return err
+	}
+	if !settled {
+		return ErrSettlementFailed
+	}
+	return nil
+}
+
+// isSettledWith returns true if the Match with the given ID has been settled
+// with parameters that have the given hash. An ErrSettlementMismatch is
+// returned if it has been settled with different parameters.
+func (settler *EthereumSettler) isSettledWith(matchID [32]byte, hash []byte) (bool, error) {
+	settlementHash, err := settler.binding.SettlementHash(settler.callOpts, matchID)
+	if err != nil {
+		return false, err
+	}
+	if settlementHash == [32]byte{} {
+		return false, nil
+	}
+	if !bytes.Equal(settlementHash[:], hash) {
+		return false, ErrSettlementMismatch
+	}
+	return true, nil
+}
+
+// IsSettled returns true if the Match with the given ID has been settled by
+// the settlement contract, otherwise it returns false.
+func (settler *EthereumSettler) IsSettled(id []byte) (bool, error) {
+	matchID, err := toBytes32(id)
+	if err != nil {
+		return false, err
+	}
+	return settler.binding.IsSettled(settler.callOpts, matchID)
+}
+
+func toBytes20(id []byte) [20]byte {
+	bytes20 := [20]byte{}
+	copy(bytes20[:], id)
+	return bytes20
+}
+
+func toBytes32(id []byte) ([32]byte, error) {
+	bytes32 := [32]byte{}
+	if len(id) != 32 {
+		return bytes32, ErrInvalidID
+	}
+	copy(bytes32[:], id)
+	return bytes32, nil
+}
@@ -0,0 +1,164 @@
+package settlement_test
+
+import (
+	"context"
+	"math/big"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	. "github.com/republicprotocol/republic-go/settlement"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind/backends"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/republicprotocol/republic-go/contracts/bindings"
+	"github.com/republicprotocol/republic-go/contracts/connection"
+	"github.com/republicprotocol/republic-go/identity"
+	"github.com/republicprotocol/republic-go/order"
+	"github.com/republicprotocol/republic-go/stackint"
+)
+
+var _ = Describe("Ethereum settlers", func() {
+
+	var backend *backends.SimulatedBackend
+	var auth *bind.TransactOpts
+	var darkNodeRegistryAddress, settlementAddress common.Address
+	var darkNode identity.KeyPair
+	var settler *EthereumSettler
+	var done chan struct{}
+
+	// deployDarkNodeRegistry deploys a DarkNodeRegistry, and registers a dark
+	// node that is owned by the transactor
+	deployDarkNodeRegistry := func() {
+		tokenAddress, _, token, err := bindings.DeployRepublicToken(auth, backend)
+		Ω(err).ShouldNot(HaveOccurred())
+		backend.Commit()
+		bond := big.NewInt(1)
+		address, _, darkNodeRegistry, err := bindings.DeployDarkNodeRegistry(auth, backend, tokenAddress, bond, big.NewInt(1), big.NewInt(0))
+		Ω(err).ShouldNot(HaveOccurred())
+		backend.Commit()
+		darkNodeRegistryAddress = address
+
+		_, err = token.Approve(auth, darkNodeRegistryAddress, bond)
+		Ω(err).ShouldNot(HaveOccurred())
+		backend.Commit()
+		darkNodeID := [20]byte{}
+		copy(darkNodeID[:], darkNode.ID())
+		_, err = darkNodeRegistry.Register(auth, darkNodeID, darkNode.PublicKeyBytes(), bond)
+		Ω(err).ShouldNot(HaveOccurred())
+		backend.Commit()
+		_, err = darkNodeRegistry.Epoch(auth)
+		Ω(err).ShouldNot(HaveOccurred())
+		backend.Commit()
+		registered, err := darkNodeRegistry.IsRegistered(&bind.CallOpts{}, darkNodeID)
+		Ω(err).ShouldNot(HaveOccurred())
+		Ω(registered).Should(BeTrue())
+	}
+
+	newMatch := func() *Match {
+		buyer, err := identity.NewKeyPair()
+		Ω(err).ShouldNot(HaveOccurred())
+		seller, err := identity.NewKeyPair()
+		Ω(err).ShouldNot(HaveOccurred())
+		price := stackint.FromUint(10)
+		maxVolume := stackint.FromUint(1000)
+		minVolume := stackint.FromUint(100)
+		nonce := stackint.FromUint(uint(time.Now().UnixNano()))
+		buy := order.NewOrder(order.TypeLimit, order.ParityBuy, time.Now().Add(time.Hour), order.CurrencyCodeBTC, order.CurrencyCodeETH, &price, &maxVolume, &minVolume, &nonce)
+		sell := order.NewOrder(order.TypeLimit, order.ParitySell, time.Now().Add(time.Hour), order.CurrencyCodeBTC, order.CurrencyCodeETH, &price, &maxVolume, &minVolume, &nonce)
+		volume := stackint.FromUint(500)
+		return &Match{
+			ID:          crypto.Keccak256(buy.ID, sell.ID),
+			BuyOrderID:  buy.ID,
+			SellOrderID: sell.ID,
+			Buy:         order.NewReveal(buyer.ID(), buy, buyer.ID()),
+			Sell:        order.NewReveal(seller.ID(), sell, seller.ID()),
+			Volume:      &volume,
+			Price:       &price,
+		}
+	}
+
+	BeforeEach(func() {
+		key, err := crypto.GenerateKey()
+		Ω(err).ShouldNot(HaveOccurred())
+		auth = bind.NewKeyedTransactor(key)
+		backend = backends.NewSimulatedBackend(core.GenesisAlloc{
+			auth.From: {Balance: big.NewInt(1000000000000000000)},
+		})
+		darkNode, err = identity.NewKeyPair()
+		Ω(err).ShouldNot(HaveOccurred())
+		deployDarkNodeRegistry()
+		settlementAddress, _, _, err = bindings.DeploySettlement(auth, backend, darkNodeRegistryAddress)
+		Ω(err).ShouldNot(HaveOccurred())
+		backend.Commit()
+
+		settler, err = NewEthereumSettler(context.Background(), &connection.ClientDetails{Client: backend}, auth, &bind.CallOpts{}, settlementAddress, darkNode.ID())
+		Ω(err).ShouldNot(HaveOccurred())
+
+		// Mine blocks in the background while the settler waits for its
+		// transactions
+		done = make(chan struct{})
+		go func() {
+			ticker := time.NewTicker(100 * time.Millisecond)
+			defer ticker.Stop()
+			for {
+				select {
+				case <-done:
+					return
+				case <-ticker.C:
+					backend.Commit()
+				}
+			}
+		}()
+	})
+
+	AfterEach(func() {
+		close(done)
+	})
+
+	It("should settle matches once", func() {
+		match := newMatch()
+		settled, err := settler.IsSettled(match.ID)
+		Ω(err).ShouldNot(HaveOccurred())
+		Ω(settled).Should(BeFalse())
+
+		Ω(settler.Settle(match)).ShouldNot(HaveOccurred())
+		settled, err = settler.IsSettled(match.ID)
+		Ω(err).ShouldNot(HaveOccurred())
+		Ω(settled).Should(BeTrue())
+
+		// Settling the match again succeeds without settling it twice
+		Ω(settler.Settle(match)).ShouldNot(HaveOccurred())
+	})
+
+	It("should not accept a match that was settled with different parameters", func() {
+		match := newMatch()
+		Ω(settler.Settle(match)).ShouldNot(HaveOccurred())
+
+		volume := stackint.FromUint(400)
+		match.Volume = &volume
+		Ω(settler.Settle(match)).Should(Equal(ErrSettlementMismatch))
+	})
+
+	It("should not settle matches for dark nodes that are not registered", func() {
+		other, err := identity.NewKeyPair()
+		Ω(err).ShouldNot(HaveOccurred())
+		settler, err := NewEthereumSettler(context.Background(), &connection.ClientDetails{Client: backend}, auth, &bind.CallOpts{}, settlementAddress, other.ID())
+		Ω(err).ShouldNot(HaveOccurred())
+
+		match := newMatch()
+		Ω(settler.Settle(match)).Should(HaveOccurred())
+		settled, err := settler.IsSettled(match.ID)
+		Ω(err).ShouldNot(HaveOccurred())
+		Ω(settled).Should(BeFalse())
+	})
+
+	It("should reject matches with invalid accounts", func() {
+		match := newMatch()
+		match.Buy.Account = []byte("account")
+		Ω(settler.Settle(match)).Should(Equal(ErrInvalidAccount))
+	})
+})
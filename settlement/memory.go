@@ -0,0 +1,63 @@
+package settlement
+
+import (
+	"github.com/republicprotocol/go-do"
+)
+
+// A MemorySettler is a Settler that records Matches in memory, instead of
+// settling them on a settlement layer. It is used for testing.
+type MemorySettler struct {
+	do.GuardedObject
+
+	matches map[string]*Match
+	err     error
+}
+
+// NewMemorySettler returns a MemorySettler that has not settled any Matches.
+func NewMemorySettler() *MemorySettler {
+	return &MemorySettler{
+		GuardedObject: do.NewGuardedObject(),
+		matches:       map[string]*Match{},
+	}
+}
+
+// Settle implements the Settler interface. It returns the error set by Fail,
+// without recording the Match, if there is one.
+func (settler *MemorySettler) Settle(match *Match) error {
+	settler.Enter(nil)
+	defer settler.Exit()
+
+	if settler.err != nil {
+		return settler.err
+	}
+	settler.matches[string(match.ID)] = match
+	return nil
+}
+
+// Fail sets the error returned by all future calls to Settle. A nil error
+// allows Matches to be settled again.
+func (settler *MemorySettler) Fail(err error) {
+	settler.Enter(nil)
+	defer settler.Exit()
+	settler.err = err
+}
+
+// Match returns the settled Match with the given ID, or nil if it has not
+// been settled.
+func (settler *MemorySettler) Match(id []byte) *Match {
+	settler.EnterReadOnly(nil)
+	defer settler.ExitReadOnly()
+	return settler.matches[string(id)]
+}
+
+// Matches returns all settled Matches.
+func (settler *MemorySettler) Matches() []*Match {
+	settler.EnterReadOnly(nil)
+	defer settler.ExitReadOnly()
+
+	matches := make([]*Match, 0, len(settler.matches))
+	for _, match := range settler.matches {
+		matches = append(matches, match)
+	}
+	return matches
+}
@@ -0,0 +1,33 @@
+package settlement_test
+
+import (
+	"errors"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	. "github.com/republicprotocol/republic-go/settlement"
+)
+
+var _ = Describe("Memory settlers", func() {
+
+	It("should record settled matches", func() {
+		settler := NewMemorySettler()
+		match := &Match{ID: []byte("match")}
+		Ω(settler.Settle(match)).ShouldNot(HaveOccurred())
+		Ω(settler.Settle(match)).ShouldNot(HaveOccurred())
+		Ω(settler.Match(match.ID)).Should(Equal(match))
+		Ω(settler.Matches()).Should(HaveLen(1))
+	})
+
+	It("should not record matches when it fails", func() {
+		settler := NewMemorySettler()
+		err := errors.New("rejected")
+		settler.Fail(err)
+		Ω(settler.Settle(&Match{ID: []byte("match")})).Should(Equal(err))
+		Ω(settler.Matches()).Should(BeEmpty())
+
+		settler.Fail(nil)
+		Ω(settler.Settle(&Match{ID: []byte("match")})).ShouldNot(HaveOccurred())
+		Ω(settler.Matches()).Should(HaveLen(1))
+	})
+})
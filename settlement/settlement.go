@@ -0,0 +1,59 @@
+// Package settlement settles the matches that are found by the dark pools.
+// Dark nodes only hold fragments of orders, so a match cannot be settled until
+// the traders of both orders reveal them, together with the accounts in which
+// they settle. A Book pairs each finalized match with these reveals, and a
+// Settler submits the completed Match to a settlement layer.
+package settlement
+
+import (
+	"github.com/republicprotocol/republic-go/order"
+	"github.com/republicprotocol/republic-go/stackint"
+)
+
+// A Status is the state of the settlement of a Match.
+type Status int64
+
+// Status values. A StatusPending Match is waiting for its fill, for the
+// reveals of its traders, or for the settlement layer. A StatusSettled Match
+// was settled by the settlement layer, and a StatusFailed Match was rejected
+// by the settlement layer. The zero value is used for unknown Matches.
+const (
+	StatusUnknown Status = 0
+	StatusPending Status = 1
+	StatusSettled Status = 2
+	StatusFailed  Status = 3
+)
+
+// A Match is a finalized match between a buy order and a sell order. The ID is
+// the ID of the delta that matched the orders, which is different for every
+// fill of a partially filled order. The order IDs are the IDs of the orders
+// opened by the traders, and the Reveals disclose these orders. The Volume is
+// the executed volume of the fill, and the Price is the price at which it is
+// executed.
+type Match struct {
+	ID          []byte
+	BuyOrderID  order.ID
+	SellOrderID order.ID
+	Buy         *order.Reveal
+	Sell        *order.Reveal
+	Volume      *stackint.Int1024
+	Price       *stackint.Int1024
+}
+
+// A Settler settles Matches on a settlement layer. Every dark node in a dark
+// pool settles the same Matches, so Settle must succeed without settling the
+// Match again when the Match has already been settled with the same
+// parameters. Settle blocks until the Match has been settled, or it returns an
+// error.
+type Settler interface {
+	Settle(match *Match) error
+}
+
+// midpoint returns the price half way between the prices of two limit orders,
+// which is the price at which a match between limit orders is executed.
+func midpoint(buy, sell *order.Order) *stackint.Int1024 {
+	two := stackint.Two()
+	sum := buy.Price.Add(sell.Price)
+	price := sum.Div(&two)
+	return &price
+}
@@ -0,0 +1,13 @@
+package settlement_test
+
+import (
+	"testing"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+func TestSettlement(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "Settlement Suite")
+}
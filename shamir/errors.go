@@ -35,3 +35,49 @@ func NewFiniteFieldError(secret *stackint.Int1024) FiniteFieldError {
 func (err FiniteFieldError) Error() string {
 	return string(err)
 }
+
+// A CommitmentError is used when a share is not consistent with the
+// commitments to the polynomial that was used to create it.
+type CommitmentError string
+
+// NewCommitmentError returns a new CommitmentError for the share that could
+// not be verified.
+func NewCommitmentError(share Share) CommitmentError {
+	return CommitmentError(fmt.Sprintf("expected share = %v to be consistent with the commitments", share.Key))
+}
+
+// Error implements the Error interface for CommitmentError.
+func (err CommitmentError) Error() string {
+	return string(err)
+}
+
+// A ReconstructionError is used when a secret cannot be reconstructed because
+// too many of the shares are faulty.
+type ReconstructionError string
+
+// NewReconstructionError returns a new ReconstructionError. The number of
+// shares given for reconstruction is N, and the number of shares required for
+// reconstruction is K.
+func NewReconstructionError(n, k int64) ReconstructionError {
+	return ReconstructionError(fmt.Sprintf("expected at most %v faulty shares in n = %v shares with k = %v", (n-k)/2, n, k))
+}
+
+// Error implements the Error interface for ReconstructionError.
+func (err ReconstructionError) Error() string {
+	return string(err)
+}
+
+// A FieldMismatchError is used when a value, or a peer, uses a different
+// finite field than the one that is expected.
+type FieldMismatchError string
+
+// NewFieldMismatchError returns a new FieldMismatchError for the FieldID that
+// was expected and the FieldID that was found.
+func NewFieldMismatchError(expected, actual FieldID) FieldMismatchError {
+	return FieldMismatchError(fmt.Sprintf("expected field = %v to be equal to field = %v", actual, expected))
+}
+
+// Error implements the Error interface for FieldMismatchError.
+func (err FieldMismatchError) Error() string {
+	return string(err)
+}
@@ -0,0 +1,109 @@
+package shamir
+
+import (
+	"bytes"
+	"errors"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/jbenet/go-base58"
+	"github.com/republicprotocol/republic-go/stackint"
+)
+
+// ErrUnknownField is returned when a Field is selected by a name that does
+// not belong to any of the known Fields.
+var ErrUnknownField = errors.New("unknown field")
+
+// Names of the Fields that can be selected using FieldByName.
+const (
+	// FieldDefault is the field defined by the prime 2^1024 - 105. It is used
+	// by production networks, and by networks that do not select a Field.
+	FieldDefault = "default"
+
+	// FieldTest is the field defined by the prime 2^127 - 1. It is large
+	// enough for the secure comparison of 64 bit values, and is much faster
+	// than the default Field, so it can be used by test networks.
+	FieldTest = "test"
+)
+
+// fieldPrimes are the primes of the Fields that can be selected by name.
+var fieldPrimes = map[string]string{
+	FieldDefault: "179769313486231590772930519078902473361797697894230657273430081157732675805500963132708477322407536021120113879871393357658789768814416622492847430639474124377767893424865485276302219601246094119453082952085005768838150682342462881473913110540827237163350510684586298239947245938479716304835356329624224137111",
+	FieldTest:    "170141183460469231731687303715884105727",
+}
+
+// fieldPrefix is prepended to a prime before hashing it into a FieldID.
+var fieldPrefix = []byte("Republic Protocol: field: ")
+
+// A FieldID is the Keccak256 hash of the prime that defines a Field. Peers
+// exchange FieldIDs to check that they are using the same Field, and order
+// fragments hold the FieldID of the Field in which they were split.
+type FieldID []byte
+
+// NewFieldID returns the FieldID of the Field defined by prime.
+func NewFieldID(prime *stackint.Int1024) FieldID {
+	return FieldID(crypto.Keccak256(fieldPrefix, prime.Bytes()))
+}
+
+// Equal returns an equality check between two FieldIDs.
+func (id FieldID) Equal(other FieldID) bool {
+	return bytes.Equal(id, other)
+}
+
+// String returns a FieldID as a Base58 encoded string.
+func (id FieldID) String() string {
+	return base58.Encode(id)
+}
+
+// A Field is the finite field, defined by a prime, from which secrets are
+// selected. It holds the constants derived from the prime, so that they are
+// only computed once. Values larger than HalfPrime are the negative values of
+// the Field.
+type Field struct {
+	ID        FieldID
+	Prime     *stackint.Int1024
+	HalfPrime *stackint.Int1024
+}
+
+// NewField returns the Field defined by prime. An ErrInvalidPrime is returned
+// if the prime is not an odd prime.
+func NewField(prime *stackint.Int1024) (*Field, error) {
+	p := prime.ToBigInt()
+	if p.Cmp(big.NewInt(2)) <= 0 || !p.ProbablyPrime(20) {
+		return nil, ErrInvalidPrime
+	}
+	clone := prime.Clone()
+	halfPrime := prime.ShiftRight(1)
+	return &Field{
+		ID:        NewFieldID(&clone),
+		Prime:     &clone,
+		HalfPrime: &halfPrime,
+	}, nil
+}
+
+// FieldByName returns one of the known Fields. The default Field is returned
+// for an empty name, and an ErrUnknownField is returned for a name that does
+// not belong to any of the known Fields.
+func FieldByName(name string) (*Field, error) {
+	if name == "" {
+		name = FieldDefault
+	}
+	number, ok := fieldPrimes[name]
+	if !ok {
+		return nil, ErrUnknownField
+	}
+	prime, err := stackint.FromString(number)
+	if err != nil {
+		return nil, err
+	}
+	return NewField(&prime)
+}
+
+// Verify that id identifies the Field. A FieldMismatchError is returned if it
+// does not.
+func (field *Field) Verify(id FieldID) error {
+	if !field.ID.Equal(id) {
+		return NewFieldMismatchError(field.ID, id)
+	}
+	return nil
+}
@@ -0,0 +1,72 @@
+package shamir_test
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	. "github.com/republicprotocol/republic-go/shamir"
+	"github.com/republicprotocol/republic-go/stackint"
+)
+
+var _ = Describe("Fields", func() {
+
+	Context("when selecting fields by name", func() {
+
+		It("should return the default field for an empty name", func() {
+			field, err := FieldByName("")
+			Ω(err).ShouldNot(HaveOccurred())
+			defaultField, err := FieldByName(FieldDefault)
+			Ω(err).ShouldNot(HaveOccurred())
+			Ω(field.ID.Equal(defaultField.ID)).Should(BeTrue())
+			Ω(field.Prime.BitLength()).Should(Equal(1024))
+		})
+
+		It("should return different fields for different names", func() {
+			defaultField, err := FieldByName(FieldDefault)
+			Ω(err).ShouldNot(HaveOccurred())
+			testField, err := FieldByName(FieldTest)
+			Ω(err).ShouldNot(HaveOccurred())
+			Ω(testField.Prime.BitLength()).Should(Equal(127))
+			Ω(testField.ID.Equal(defaultField.ID)).Should(BeFalse())
+			Ω(testField.ID.String()).ShouldNot(Equal(defaultField.ID.String()))
+		})
+
+		It("should return an error for unknown names", func() {
+			_, err := FieldByName("unknown")
+			Ω(err).Should(Equal(ErrUnknownField))
+		})
+	})
+
+	Context("when creating fields", func() {
+
+		It("should derive half of the prime", func() {
+			prime := stackint.FromUint(23)
+			field, err := NewField(&prime)
+			Ω(err).ShouldNot(HaveOccurred())
+			halfPrime := stackint.FromUint(11)
+			Ω(field.HalfPrime.Cmp(&halfPrime)).Should(Equal(0))
+			Ω(field.ID.Equal(NewFieldID(&prime))).Should(BeTrue())
+		})
+
+		It("should return an error for numbers that are not odd primes", func() {
+			for _, n := range []uint{0, 1, 2, 21} {
+				prime := stackint.FromUint(n)
+				_, err := NewField(&prime)
+				Ω(err).Should(Equal(ErrInvalidPrime))
+			}
+		})
+	})
+
+	Context("when verifying field IDs", func() {
+
+		It("should only accept the ID of the field", func() {
+			defaultField, err := FieldByName(FieldDefault)
+			Ω(err).ShouldNot(HaveOccurred())
+			testField, err := FieldByName(FieldTest)
+			Ω(err).ShouldNot(HaveOccurred())
+
+			Ω(defaultField.Verify(defaultField.ID)).ShouldNot(HaveOccurred())
+			Ω(defaultField.Verify(testField.ID)).Should(Equal(NewFieldMismatchError(defaultField.ID, testField.ID)))
+			Ω(defaultField.Verify(FieldID{})).Should(HaveOccurred())
+		})
+	})
+})
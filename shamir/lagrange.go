@@ -0,0 +1,264 @@
+package shamir
+
+import (
+	"encoding/binary"
+	"errors"
+	"sort"
+
+	"github.com/republicprotocol/go-do"
+	"github.com/republicprotocol/republic-go/stackint"
+)
+
+// ErrInvalidShareKeys is returned when Shares do not have the distinct keys
+// that are needed to interpolate them.
+var ErrInvalidShareKeys = errors.New("invalid share keys")
+
+// maxLagrangeCacheSize is the number of Lagranges that a LagrangeCache holds
+// before it is cleared.
+const maxLagrangeCacheSize = 1024
+
+// A Lagrange holds the Lagrange coefficients needed to interpolate the value
+// at a point, from Shares with a fixed set of keys. Computing the coefficients
+// is much more expensive than applying them, so a Lagrange should be reused
+// for all Shares with the same keys. Coefficients are held in the Montgomery
+// domain, so that applying them only needs a Montgomery multiplication.
+type Lagrange struct {
+	prime        *stackint.Int1024
+	mont         *stackint.Montgomery
+	keys         []int64
+	coefficients []stackint.Int1024
+}
+
+// NewLagrange returns the Lagrange coefficients for interpolating the value at
+// x from Shares with the given keys. Prime is used to define the finite field
+// from which the secret was selected. An ErrInvalidShareKeys is returned if
+// the keys are not distinct.
+func NewLagrange(prime *stackint.Int1024, keys []int64, x int64) (*Lagrange, error) {
+	mont, err := stackint.NewMontgomery(prime)
+	if err != nil {
+		return nil, err
+	}
+	return newLagrange(mont, keys, x)
+}
+
+func newLagrange(mont *stackint.Montgomery, keys []int64, x int64) (*Lagrange, error) {
+	prime := mont.Modulus()
+
+	sorted := make([]int64, len(keys))
+	copy(sorted, keys)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i] < sorted[j] })
+	for i := 1; i < len(sorted); i++ {
+		if sorted[i] == sorted[i-1] {
+			return nil, ErrInvalidShareKeys
+		}
+	}
+
+	k := len(sorted)
+	lagrange := &Lagrange{
+		prime:        &prime,
+		mont:         mont,
+		keys:         sorted,
+		coefficients: make([]stackint.Int1024, k),
+	}
+	if k == 0 {
+		return lagrange, nil
+	}
+	xs := make([]stackint.Int1024, k)
+	for i, key := range sorted {
+		xs[i] = fieldElement(&prime, key)
+		xs[i] = mont.ToMont(&xs[i])
+	}
+	at := fieldElement(&prime, x)
+	at = mont.ToMont(&at)
+
+	// The numerator of the ith coefficient is the product of (x - x_j) for
+	// all j != i, which is computed using prefix and suffix products.
+	suffixes := make([]stackint.Int1024, k+1)
+	suffixes[k] = mont.OneMont()
+	for i := k - 1; i >= 0; i-- {
+		diff := at.SubModulo(&xs[i], &prime)
+		suffixes[i] = mont.MulMont(&suffixes[i+1], &diff)
+	}
+	prefix := mont.OneMont()
+	for i := 0; i < k; i++ {
+		lagrange.coefficients[i] = mont.MulMont(&prefix, &suffixes[i+1])
+		diff := at.SubModulo(&xs[i], &prime)
+		prefix = mont.MulMont(&prefix, &diff)
+	}
+
+	// The denominator of the ith coefficient is the product of (x_i - x_j)
+	// for all j != i. All denominators are inverted using a single modular
+	// inversion.
+	denominators := make([]stackint.Int1024, k)
+	products := make([]stackint.Int1024, k)
+	for i := 0; i < k; i++ {
+		denominators[i] = mont.OneMont()
+		for j := 0; j < k; j++ {
+			if i == j {
+				continue
+			}
+			diff := xs[i].SubModulo(&xs[j], &prime)
+			denominators[i] = mont.MulMont(&denominators[i], &diff)
+		}
+		if i == 0 {
+			products[i] = denominators[i]
+		} else {
+			products[i] = mont.MulMont(&products[i-1], &denominators[i])
+		}
+	}
+	product := mont.FromMont(&products[k-1])
+	inverse := product.ModInverse(&prime)
+	inverse = mont.ToMont(&inverse)
+	for i := k - 1; i >= 0; i-- {
+		denominatorInverse := inverse
+		if i > 0 {
+			denominatorInverse = mont.MulMont(&inverse, &products[i-1])
+		}
+		inverse = mont.MulMont(&inverse, &denominators[i])
+		lagrange.coefficients[i] = mont.MulMont(&lagrange.coefficients[i], &denominatorInverse)
+	}
+
+	return lagrange, nil
+}
+
+// Interpolate the value at the point of the Lagrange from Shares. The Shares
+// can be in any order, but they must have exactly the keys of the Lagrange,
+// otherwise an ErrInvalidShareKeys is returned.
+func (lagrange *Lagrange) Interpolate(shares Shares) (*stackint.Int1024, error) {
+	if len(shares) != len(lagrange.keys) {
+		return nil, ErrInvalidShareKeys
+	}
+
+	// Shares are checked for duplicate keys using a bit mask, so that small
+	// numbers of Shares can be interpolated without allocating
+	var mask uint64
+	var seen []bool
+	if len(shares) > 64 {
+		seen = make([]bool, len(shares))
+	}
+	value := stackint.Zero()
+	for _, share := range shares {
+		i := sort.Search(len(lagrange.keys), func(i int) bool { return lagrange.keys[i] >= share.Key })
+		if i == len(lagrange.keys) || lagrange.keys[i] != share.Key {
+			return nil, ErrInvalidShareKeys
+		}
+		if seen != nil {
+			if seen[i] {
+				return nil, ErrInvalidShareKeys
+			}
+			seen[i] = true
+		} else {
+			if mask&(1<<uint(i)) != 0 {
+				return nil, ErrInvalidShareKeys
+			}
+			mask |= 1 << uint(i)
+		}
+
+		// The share is not in the Montgomery domain, and the coefficient is,
+		// so their Montgomery product is not in the Montgomery domain. Shares
+		// are secret, so only constant-time arithmetic is used on them.
+		term := lagrange.mont.CtMulMont(&share.Value, &lagrange.coefficients[i])
+		value = value.CtAddModulo(&term, lagrange.prime)
+	}
+	return &value, nil
+}
+
+// A LagrangeCache holds Lagranges keyed by the sorted keys of the Shares that
+// they interpolate, and the point at which they interpolate. It is safe for
+// concurrent use.
+type LagrangeCache struct {
+	do.GuardedObject
+
+	prime     *stackint.Int1024
+	mont      *stackint.Montgomery
+	lagranges map[string]*Lagrange
+}
+
+// NewLagrangeCache returns an empty LagrangeCache for the finite field defined
+// by prime.
+func NewLagrangeCache(prime *stackint.Int1024) *LagrangeCache {
+	// Without a Montgomery context, because the prime is even, every call to
+	// the cache will return the error from NewMontgomery
+	mont, _ := stackint.NewMontgomery(prime)
+	return &LagrangeCache{
+		GuardedObject: do.NewGuardedObject(),
+		prime:         prime,
+		mont:          mont,
+		lagranges:     map[string]*Lagrange{},
+	}
+}
+
+// Lagrange returns the Lagrange for interpolating the value at x from Shares
+// with the given keys, computing it if it is not already in the cache.
+func (cache *LagrangeCache) Lagrange(keys []int64, x int64) (*Lagrange, error) {
+	id := lagrangeID(keys, x)
+
+	cache.EnterReadOnly(nil)
+	lagrange, ok := cache.lagranges[id]
+	cache.ExitReadOnly()
+	if ok {
+		return lagrange, nil
+	}
+
+	if cache.mont == nil {
+		return nil, stackint.ErrEvenModulus
+	}
+	lagrange, err := newLagrange(cache.mont, keys, x)
+	if err != nil {
+		return nil, err
+	}
+
+	cache.Enter(nil)
+	defer cache.Exit()
+	if len(cache.lagranges) >= maxLagrangeCacheSize {
+		cache.lagranges = map[string]*Lagrange{}
+	}
+	cache.lagranges[id] = lagrange
+	return lagrange, nil
+}
+
+// Join Shares into a secret using the cached Lagrange for their keys. An
+// ErrInvalidShareKeys is returned if the keys of the Shares are not distinct.
+func (cache *LagrangeCache) Join(shares Shares) (*stackint.Int1024, error) {
+	lagrange, err := cache.Lagrange(ShareKeys(shares), 0)
+	if err != nil {
+		return nil, err
+	}
+	return lagrange.Interpolate(shares)
+}
+
+// lagrangeID returns the cache key of a Lagrange. The keys are sorted so that
+// the same Lagrange is used for Shares in any order.
+func lagrangeID(keys []int64, x int64) string {
+	sorted := make([]int64, len(keys))
+	copy(sorted, keys)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i] < sorted[j] })
+
+	id := make([]byte, 8*(len(sorted)+1))
+	binary.LittleEndian.PutUint64(id, uint64(x))
+	for i, key := range sorted {
+		binary.LittleEndian.PutUint64(id[8*(i+1):], uint64(key))
+	}
+	return string(id)
+}
+
+// ShareKeys returns the keys of Shares.
+func ShareKeys(shares Shares) []int64 {
+	keys := make([]int64, len(shares))
+	for i, share := range shares {
+		keys[i] = share.Key
+	}
+	return keys
+}
+
+// fieldElement returns n as an element of the finite field defined by prime.
+func fieldElement(prime *stackint.Int1024, n int64) stackint.Int1024 {
+	if n < 0 {
+		abs := stackint.FromUint(uint(-n))
+		abs = abs.Mod(prime)
+		zero := stackint.Zero()
+		return zero.SubModulo(&abs, prime)
+	}
+	value := stackint.FromUint(uint(n))
+	return value.Mod(prime)
+}
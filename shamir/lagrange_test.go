@@ -0,0 +1,171 @@
+package shamir_test
+
+import (
+	"testing"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	. "github.com/republicprotocol/republic-go/shamir"
+	"github.com/republicprotocol/republic-go/stackint"
+)
+
+var lagrangePrime, _ = stackint.FromString("179769313486231590772930519078902473361797697894230657273430081157732675805500963132708477322407536021120113879871393357658789768814416622492847430639474124377767893424865485276302219601246094119453082952085005768838150682342462881473913110540827237163350510684586298239947245938479716304835356329624224137111")
+
+var _ = Describe("Lagrange interpolation", func() {
+
+	n := int64(24)
+	k := int64(16)
+
+	It("should interpolate the same secret as Join", func() {
+		secret := stackint.FromUint(1234)
+		shares, err := Split(n, k, &lagrangePrime, &secret)
+		Ω(err).ShouldNot(HaveOccurred())
+
+		lagrange, err := NewLagrange(&lagrangePrime, ShareKeys(shares[n-k:]), 0)
+		Ω(err).ShouldNot(HaveOccurred())
+		joined, err := lagrange.Interpolate(shares[n-k:])
+		Ω(err).ShouldNot(HaveOccurred())
+		Ω(joined.Cmp(&secret)).Should(Equal(0))
+		Ω(joined.Cmp(Join(&lagrangePrime, shares[n-k:]))).Should(Equal(0))
+	})
+
+	It("should interpolate many secrets using the same coefficients", func() {
+		lagrange, err := NewLagrange(&lagrangePrime, []int64{1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15, 16}, 0)
+		Ω(err).ShouldNot(HaveOccurred())
+		for i := uint(0); i < 10; i++ {
+			secret := stackint.FromUint(i)
+			shares, err := Split(n, k, &lagrangePrime, &secret)
+			Ω(err).ShouldNot(HaveOccurred())
+
+			// Shares can be given in any order
+			shares[0], shares[k-1] = shares[k-1], shares[0]
+			joined, err := lagrange.Interpolate(shares[:k])
+			Ω(err).ShouldNot(HaveOccurred())
+			Ω(joined.Cmp(&secret)).Should(Equal(0))
+		}
+	})
+
+	It("should interpolate shares at other points", func() {
+		secret := stackint.FromUint(1234)
+		shares, err := Split(n, k, &lagrangePrime, &secret)
+		Ω(err).ShouldNot(HaveOccurred())
+
+		lagrange, err := NewLagrange(&lagrangePrime, ShareKeys(shares[:k]), shares[n-1].Key)
+		Ω(err).ShouldNot(HaveOccurred())
+		value, err := lagrange.Interpolate(shares[:k])
+		Ω(err).ShouldNot(HaveOccurred())
+		Ω(value.Cmp(&shares[n-1].Value)).Should(Equal(0))
+	})
+
+	It("should return an error for shares with different keys", func() {
+		secret := stackint.FromUint(1234)
+		shares, err := Split(n, k, &lagrangePrime, &secret)
+		Ω(err).ShouldNot(HaveOccurred())
+
+		_, err = NewLagrange(&lagrangePrime, []int64{1, 2, 2}, 0)
+		Ω(err).Should(Equal(ErrInvalidShareKeys))
+
+		lagrange, err := NewLagrange(&lagrangePrime, ShareKeys(shares[:k]), 0)
+		Ω(err).ShouldNot(HaveOccurred())
+		_, err = lagrange.Interpolate(shares[1 : k+1])
+		Ω(err).Should(Equal(ErrInvalidShareKeys))
+		_, err = lagrange.Interpolate(shares[:k-1])
+		Ω(err).Should(Equal(ErrInvalidShareKeys))
+		_, err = lagrange.Interpolate(append(shares[:k-1:k-1], shares[0]))
+		Ω(err).Should(Equal(ErrInvalidShareKeys))
+	})
+
+	Context("when caching coefficients", func() {
+
+		It("should join shares in any order using the same coefficients", func() {
+			cache := NewLagrangeCache(&lagrangePrime)
+			secret := stackint.FromUint(1234)
+			shares, err := Split(n, k, &lagrangePrime, &secret)
+			Ω(err).ShouldNot(HaveOccurred())
+
+			joined, err := cache.Join(shares[:k])
+			Ω(err).ShouldNot(HaveOccurred())
+			Ω(joined.Cmp(&secret)).Should(Equal(0))
+
+			reversed := make(Shares, k)
+			for i := range reversed {
+				reversed[i] = shares[k-1-int64(i)]
+			}
+			lagrange, err := cache.Lagrange(ShareKeys(shares[:k]), 0)
+			Ω(err).ShouldNot(HaveOccurred())
+			other, err := cache.Lagrange(ShareKeys(reversed), 0)
+			Ω(err).ShouldNot(HaveOccurred())
+			Ω(other).Should(BeIdenticalTo(lagrange))
+
+			joined, err = cache.Join(reversed)
+			Ω(err).ShouldNot(HaveOccurred())
+			Ω(joined.Cmp(&secret)).Should(Equal(0))
+		})
+
+		It("should join robustly using the cached coefficients", func() {
+			cache := NewLagrangeCache(&lagrangePrime)
+			secret := stackint.FromUint(1234)
+			shares, err := Split(n, k, &lagrangePrime, &secret)
+			Ω(err).ShouldNot(HaveOccurred())
+
+			joined, faults, err := cache.JoinRobust(k, shares)
+			Ω(err).ShouldNot(HaveOccurred())
+			Ω(joined.Cmp(&secret)).Should(Equal(0))
+			Ω(faults).Should(BeEmpty())
+		})
+	})
+})
+
+func BenchmarkJoin(b *testing.B) {
+	shares := benchmarkShares(24, 16)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		Join(&lagrangePrime, shares[:16])
+	}
+}
+
+func BenchmarkLagrangeCacheJoin(b *testing.B) {
+	shares := benchmarkShares(24, 16)
+	cache := NewLagrangeCache(&lagrangePrime)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, err := cache.Join(shares[:16]); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkLagrangeInterpolate(b *testing.B) {
+	shares := benchmarkShares(24, 16)
+	lagrange, err := NewLagrange(&lagrangePrime, ShareKeys(shares[:16]), 0)
+	if err != nil {
+		b.Fatal(err)
+	}
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, err := lagrange.Interpolate(shares[:16]); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkJoinRobust(b *testing.B) {
+	shares := benchmarkShares(24, 16)
+	cache := NewLagrangeCache(&lagrangePrime)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, _, err := cache.JoinRobust(16, shares); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func benchmarkShares(n, k int64) Shares {
+	secret := stackint.FromUint(1234)
+	shares, err := Split(n, k, &lagrangePrime, &secret)
+	if err != nil {
+		panic(err)
+	}
+	return shares
+}
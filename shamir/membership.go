@@ -0,0 +1,186 @@
+package shamir
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/binary"
+	"errors"
+	"math/big"
+)
+
+// membershipPrefix is used to derive the challenge of a MembershipProof from
+// the statement that it proves.
+var membershipPrefix = []byte("Republic Protocol: membership: ")
+
+// ErrInvalidMembershipProof is returned when a MembershipProof does not prove
+// that a list of commitments commits to one of a list of members, or when a
+// MembershipProof cannot be created for them.
+var ErrInvalidMembershipProof = errors.New("invalid membership proof")
+
+// A MembershipProof is a non-interactive zero-knowledge proof that a list of
+// Pedersen commitments, G^v_j * H^r_j, commits to the values of one member in
+// a list of members, without revealing which member. Each member is a list of
+// values, one for each commitment. The proof is an OR composition of Schnorr
+// proofs of knowledge of the blindings r_j, one for each member. Only the
+// proof for the true member is real, the others are simulated, and the
+// challenges of all members must sum to a hash of the statement, so that the
+// prover can only choose the challenges of the simulated proofs.
+type MembershipProof struct {
+	// Challenges holds the challenge of each member.
+	Challenges []*big.Int
+	// Responses holds the responses of each member, one for each commitment.
+	Responses [][]*big.Int
+}
+
+// ProveMembership returns a MembershipProof that the commitments commit to
+// the values of the member at the given index. The blindings are the values
+// that the commitments were blinded with, one for each commitment. An
+// ErrInvalidMembershipProof is returned if the index is not a member, or if
+// the members do not have one value for each commitment.
+func (vss *VSS) ProveMembership(commitments, blindings []*big.Int, members [][]*big.Int, index int) (*MembershipProof, error) {
+	if len(blindings) != len(commitments) || index < 0 || index >= len(members) {
+		return nil, ErrInvalidMembershipProof
+	}
+	for _, member := range members {
+		if len(member) != len(commitments) {
+			return nil, ErrInvalidMembershipProof
+		}
+	}
+
+	proof := &MembershipProof{
+		Challenges: make([]*big.Int, len(members)),
+		Responses:  make([][]*big.Int, len(members)),
+	}
+	announcements := make([][]*big.Int, len(members))
+	nonces := make([]*big.Int, len(commitments))
+	sum := big.NewInt(0)
+	for i, member := range members {
+		announcements[i] = make([]*big.Int, len(commitments))
+		proof.Responses[i] = make([]*big.Int, len(commitments))
+		if i == index {
+			// The real proof commits to random nonces, and its challenge is
+			// fixed once all of the announcements have been hashed
+			for j := range commitments {
+				nonce, err := rand.Int(rand.Reader, vss.p)
+				if err != nil {
+					return nil, err
+				}
+				nonces[j] = nonce
+				announcements[i][j] = new(big.Int).Exp(vss.H, nonce, vss.Q)
+			}
+			continue
+		}
+		// The proofs of the other members are simulated by choosing their
+		// challenges and responses first
+		challenge, err := rand.Int(rand.Reader, vss.p)
+		if err != nil {
+			return nil, err
+		}
+		proof.Challenges[i] = challenge
+		sum.Add(sum, challenge)
+		for j := range commitments {
+			response, err := rand.Int(rand.Reader, vss.p)
+			if err != nil {
+				return nil, err
+			}
+			proof.Responses[i][j] = response
+			announcements[i][j] = vss.announcement(commitments[j], member[j], challenge, response)
+		}
+	}
+
+	challenge := vss.membershipChallenge(commitments, members, announcements)
+	proof.Challenges[index] = challenge.Sub(challenge, sum)
+	proof.Challenges[index].Mod(proof.Challenges[index], vss.p)
+	for j := range commitments {
+		response := new(big.Int).Mul(proof.Challenges[index], blindings[j])
+		response.Add(response, nonces[j])
+		proof.Responses[index][j] = response.Mod(response, vss.p)
+	}
+	return proof, nil
+}
+
+// VerifyMembership checks that the MembershipProof proves that the
+// commitments commit to the values of one of the members. An
+// ErrInvalidMembershipProof is returned if it does not.
+func (vss *VSS) VerifyMembership(commitments []*big.Int, members [][]*big.Int, proof *MembershipProof) error {
+	if proof == nil || len(members) == 0 || len(proof.Challenges) != len(members) || len(proof.Responses) != len(members) {
+		return ErrInvalidMembershipProof
+	}
+	for _, commitment := range commitments {
+		if !vss.inSubgroup(commitment) {
+			return ErrInvalidMembershipProof
+		}
+	}
+	announcements := make([][]*big.Int, len(members))
+	sum := big.NewInt(0)
+	for i, member := range members {
+		if len(member) != len(commitments) || len(proof.Responses[i]) != len(commitments) {
+			return ErrInvalidMembershipProof
+		}
+		if !vss.inField(proof.Challenges[i]) {
+			return ErrInvalidMembershipProof
+		}
+		sum.Add(sum, proof.Challenges[i])
+		announcements[i] = make([]*big.Int, len(commitments))
+		for j := range commitments {
+			if member[j] == nil || !vss.inField(proof.Responses[i][j]) {
+				return ErrInvalidMembershipProof
+			}
+			announcements[i][j] = vss.announcement(commitments[j], member[j], proof.Challenges[i], proof.Responses[i][j])
+		}
+	}
+	sum.Mod(sum, vss.p)
+	if sum.Cmp(vss.membershipChallenge(commitments, members, announcements)) != 0 {
+		return ErrInvalidMembershipProof
+	}
+	return nil
+}
+
+// announcement returns H^response * (commitment / G^value)^-challenge, which
+// is the announcement of a Schnorr proof that commitment / G^value is a power
+// of H.
+func (vss *VSS) announcement(commitment, value, challenge, response *big.Int) *big.Int {
+	// Elements of the subgroup are inverted by raising them to Prime - 1, and
+	// Prime - x is used as the exponent of -x
+	negValue := new(big.Int).Mod(value, vss.p)
+	negValue.Sub(vss.p, negValue)
+	blinded := new(big.Int).Exp(vss.G, negValue, vss.Q)
+	blinded.Mul(blinded, commitment)
+	blinded.Mod(blinded, vss.Q)
+
+	negChallenge := new(big.Int).Sub(vss.p, challenge)
+	announcement := new(big.Int).Exp(blinded, negChallenge, vss.Q)
+	announcement.Mul(announcement, new(big.Int).Exp(vss.H, response, vss.Q))
+	return announcement.Mod(announcement, vss.Q)
+}
+
+// membershipChallenge hashes the statement of a MembershipProof, and its
+// announcements, into the challenge that the challenges of its members must
+// sum to.
+func (vss *VSS) membershipChallenge(commitments []*big.Int, members [][]*big.Int, announcements [][]*big.Int) *big.Int {
+	hash := sha256.New()
+	hash.Write(membershipPrefix)
+	write := func(values ...*big.Int) {
+		for _, value := range values {
+			data := value.Bytes()
+			length := make([]byte, 4)
+			binary.LittleEndian.PutUint32(length, uint32(len(data)))
+			hash.Write(length)
+			hash.Write(data)
+		}
+	}
+	write(vss.Q, vss.G, vss.H)
+	write(commitments...)
+	for i := range members {
+		write(members[i]...)
+		write(announcements[i]...)
+	}
+	challenge := new(big.Int).SetBytes(hash.Sum(nil))
+	return challenge.Mod(challenge, vss.p)
+}
+
+// inField returns true if the value is an element of the finite field defined
+// by Prime, otherwise it returns false.
+func (vss *VSS) inField(value *big.Int) bool {
+	return value != nil && value.Sign() >= 0 && value.Cmp(vss.p) < 0
+}
@@ -0,0 +1,249 @@
+package shamir
+
+import (
+	"math/big"
+
+	"github.com/republicprotocol/republic-go/stackint"
+)
+
+// JoinRobust Shares into a secret, detecting and correcting faulty Shares. K
+// is the number of Shares required to reconstruct the secret, and up to
+// (len(shares) - k) / 2 faulty Shares are corrected using the Berlekamp-Welch
+// algorithm. Prime is used to define the finite field from which the secret
+// was selected. The reconstructed secret and the keys of the faulty Shares,
+// or an error, are returned. A ReconstructionError is returned if there are
+// too many faulty Shares to correct.
+func JoinRobust(prime *stackint.Int1024, k int64, shares Shares) (*stackint.Int1024, []int64, error) {
+	return NewLagrangeCache(prime).JoinRobust(k, shares)
+}
+
+// JoinRobust Shares into a secret, detecting and correcting faulty Shares, in
+// the same way as the JoinRobust function. When none of the Shares are faulty,
+// the secret is interpolated using cached Lagranges, and the more expensive
+// Berlekamp-Welch algorithm is not needed.
+func (cache *LagrangeCache) JoinRobust(k int64, shares Shares) (*stackint.Int1024, []int64, error) {
+	m := int64(len(shares))
+	if m < k || k <= 0 {
+		return nil, nil, NewNKError(m, k)
+	}
+	keys := map[int64]bool{}
+	for _, share := range shares {
+		if share.Key <= 0 || keys[share.Key] {
+			return nil, nil, ErrInvalidShareKeys
+		}
+		keys[share.Key] = true
+	}
+
+	// If the remaining Shares lie on the polynomial interpolated from the
+	// first k Shares then there are no faulty Shares
+	secret, ok, err := cache.joinConsistent(k, shares)
+	if err != nil {
+		return nil, nil, err
+	}
+	if ok {
+		return secret, []int64{}, nil
+	}
+
+	// Find the error locator polynomial E, of degree e, and the polynomial
+	// Q = P * E, of degree e + k - 1, such that Q(x) = y * E(x) for all
+	// shares. The coefficients of Q come first, followed by the coefficients
+	// of E except for the leading coefficient which is always 1.
+	p := cache.prime.ToBigInt()
+	e := (m - k) / 2
+	rows := make([][]*big.Int, m)
+	for i, share := range shares {
+		x := big.NewInt(share.Key)
+		y := share.Value.ToBigInt()
+		row := make([]*big.Int, 2*e+k+1)
+		exp := big.NewInt(1)
+		for j := int64(0); j < e+k; j++ {
+			row[j] = new(big.Int).Set(exp)
+			if j < e {
+				row[e+k+j] = new(big.Int).Mul(y, exp)
+				row[e+k+j].Neg(row[e+k+j])
+				row[e+k+j].Mod(row[e+k+j], p)
+			}
+			if j == e {
+				row[2*e+k] = new(big.Int).Mul(y, exp)
+				row[2*e+k].Mod(row[2*e+k], p)
+			}
+			exp.Mul(exp, x)
+			exp.Mod(exp, p)
+		}
+		rows[i] = row
+	}
+	solution, ok := solve(rows, 2*e+k, p)
+	if !ok {
+		return nil, nil, NewReconstructionError(m, k)
+	}
+	q := solution[:e+k]
+	locator := append(solution[e+k:], big.NewInt(1))
+
+	// P = Q / E, and the shares that do not lie on P are faulty
+	polynomial, ok := divide(q, locator, p)
+	if !ok {
+		return nil, nil, NewReconstructionError(m, k)
+	}
+	faults := []int64{}
+	for _, share := range shares {
+		if evaluatePolynomial(polynomial, big.NewInt(share.Key), p).Cmp(share.Value.ToBigInt()) != 0 {
+			faults = append(faults, share.Key)
+		}
+	}
+	if int64(len(faults)) > e {
+		return nil, nil, NewReconstructionError(m, k)
+	}
+
+	corrected, err := stackint.FromBigInt(polynomial[0])
+	if err != nil {
+		return nil, nil, err
+	}
+	return &corrected, faults, nil
+}
+
+// JoinOnline Shares into a secret while they are still arriving, assuming
+// that up to faults of all Shares that will arrive are faulty. The Shares are
+// joined using JoinRobust, and the secret is only accepted when at least k +
+// faults Shares are consistent with it, because at least k of those Shares
+// are not faulty and they determine the secret. Without faulty Shares, the
+// secret is reconstructed from k + faults Shares, and every faulty Share
+// delays the reconstruction by one Share, rather than waiting for k +
+// 2*faults Shares. A ReconstructionError is returned if too few Shares are
+// consistent with the secret, and more Shares must be given.
+func (cache *LagrangeCache) JoinOnline(k, faults int64, shares Shares) (*stackint.Int1024, []int64, error) {
+	m := int64(len(shares))
+	if m < k+faults {
+		return nil, nil, NewNKError(m, k+faults)
+	}
+	secret, faultyKeys, err := cache.JoinRobust(k, shares)
+	if err != nil {
+		return nil, nil, err
+	}
+	if m-int64(len(faultyKeys)) < k+faults {
+		return nil, nil, NewReconstructionError(m, k)
+	}
+	return secret, faultyKeys, nil
+}
+
+// joinConsistent interpolates the secret from the first k Shares, and returns
+// true if all of the remaining Shares are consistent with it.
+func (cache *LagrangeCache) joinConsistent(k int64, shares Shares) (*stackint.Int1024, bool, error) {
+	keys := ShareKeys(shares[:k])
+	lagrange, err := cache.Lagrange(keys, 0)
+	if err != nil {
+		return nil, false, err
+	}
+	secret, err := lagrange.Interpolate(shares[:k])
+	if err != nil {
+		return nil, false, err
+	}
+	for _, share := range shares[k:] {
+		lagrange, err := cache.Lagrange(keys, share.Key)
+		if err != nil {
+			return nil, false, err
+		}
+		value, err := lagrange.Interpolate(shares[:k])
+		if err != nil {
+			return nil, false, err
+		}
+		if value.Cmp(&share.Value) != 0 {
+			return nil, false, nil
+		}
+	}
+	return secret, true, nil
+}
+
+// solve a system of linear equations modulo p using Gaussian elimination.
+// Each row holds the coefficients of n unknowns followed by the constant.
+// Unknowns that are not determined by the system are set to zero. False is
+// returned if the system has no solution.
+func solve(rows [][]*big.Int, n int64, p *big.Int) ([]*big.Int, bool) {
+	pivots := make([]int, 0, n)
+	r := 0
+	for c := int64(0); c < n && r < len(rows); c++ {
+		pivot := -1
+		for i := r; i < len(rows); i++ {
+			if rows[i][c].Sign() != 0 {
+				pivot = i
+				break
+			}
+		}
+		if pivot < 0 {
+			continue
+		}
+		rows[r], rows[pivot] = rows[pivot], rows[r]
+
+		inverse := new(big.Int).ModInverse(rows[r][c], p)
+		for j := c; j <= n; j++ {
+			rows[r][j].Mul(rows[r][j], inverse)
+			rows[r][j].Mod(rows[r][j], p)
+		}
+		for i := range rows {
+			if i == r || rows[i][c].Sign() == 0 {
+				continue
+			}
+			factor := new(big.Int).Set(rows[i][c])
+			for j := c; j <= n; j++ {
+				rows[i][j].Sub(rows[i][j], new(big.Int).Mul(factor, rows[r][j]))
+				rows[i][j].Mod(rows[i][j], p)
+			}
+		}
+		pivots = append(pivots, int(c))
+		r++
+	}
+
+	// Rows without a pivot must have a zero constant
+	for i := r; i < len(rows); i++ {
+		if rows[i][n].Sign() != 0 {
+			return nil, false
+		}
+	}
+	solution := make([]*big.Int, n)
+	for c := range solution {
+		solution[c] = big.NewInt(0)
+	}
+	for i, c := range pivots {
+		solution[c].Set(rows[i][n])
+	}
+	return solution, true
+}
+
+// divide the numerator polynomial by the monic denominator polynomial modulo
+// p. Coefficients are ordered from the lowest degree. False is returned if
+// there is a remainder.
+func divide(numerator, denominator []*big.Int, p *big.Int) ([]*big.Int, bool) {
+	remainder := make([]*big.Int, len(numerator))
+	for i := range numerator {
+		remainder[i] = new(big.Int).Set(numerator[i])
+	}
+	d := len(denominator) - 1
+	if len(numerator) <= d {
+		return nil, false
+	}
+	quotient := make([]*big.Int, len(numerator)-d)
+	for i := len(quotient) - 1; i >= 0; i-- {
+		quotient[i] = new(big.Int).Set(remainder[i+d])
+		for j := 0; j <= d; j++ {
+			remainder[i+j].Sub(remainder[i+j], new(big.Int).Mul(quotient[i], denominator[j]))
+			remainder[i+j].Mod(remainder[i+j], p)
+		}
+	}
+	for i := 0; i < d; i++ {
+		if remainder[i].Sign() != 0 {
+			return nil, false
+		}
+	}
+	return quotient, true
+}
+
+// evaluatePolynomial at x modulo p. Coefficients are ordered from the lowest
+// degree.
+func evaluatePolynomial(coefficients []*big.Int, x, p *big.Int) *big.Int {
+	value := new(big.Int)
+	for i := len(coefficients) - 1; i >= 0; i-- {
+		value.Mul(value, x)
+		value.Add(value, coefficients[i])
+		value.Mod(value, p)
+	}
+	return value
+}
@@ -0,0 +1,119 @@
+package shamir_test
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	. "github.com/republicprotocol/republic-go/shamir"
+	"github.com/republicprotocol/republic-go/stackint"
+)
+
+var _ = Describe("Robust reconstruction", func() {
+
+	n := int64(10)
+	k := int64(4)
+	prime, _ := stackint.FromString("179769313486231590772930519078902473361797697894230657273430081157732675805500963132708477322407536021120113879871393357658789768814416622492847430639474124377767893424865485276302219601246094119453082952085005768838150682342462881473913110540827237163350510684586298239947245938479716304835356329624224137111")
+	one := stackint.One()
+
+	corrupt := func(shares Shares, indices ...int) Shares {
+		corrupted := make(Shares, len(shares))
+		copy(corrupted, shares)
+		for _, i := range indices {
+			corrupted[i] = Share{Key: shares[i].Key, Value: shares[i].Value.AddModulo(&one, &prime)}
+		}
+		return corrupted
+	}
+
+	It("should join shares that are not faulty", func() {
+		secret := stackint.FromUint(1234)
+		shares, err := Split(n, k, &prime, &secret)
+		Ω(err).ShouldNot(HaveOccurred())
+
+		joined, faults, err := JoinRobust(&prime, k, shares)
+		Ω(err).ShouldNot(HaveOccurred())
+		Ω(joined.Cmp(&secret)).Should(Equal(0))
+		Ω(faults).Should(BeEmpty())
+
+		joined, faults, err = JoinRobust(&prime, k, shares[:k])
+		Ω(err).ShouldNot(HaveOccurred())
+		Ω(joined.Cmp(&secret)).Should(Equal(0))
+		Ω(faults).Should(BeEmpty())
+	})
+
+	It("should correct up to (n - k) / 2 faulty shares and report them", func() {
+		secret := stackint.FromUint(1234)
+		shares, err := Split(n, k, &prime, &secret)
+		Ω(err).ShouldNot(HaveOccurred())
+
+		joined, faults, err := JoinRobust(&prime, k, corrupt(shares, 0, 5, 9))
+		Ω(err).ShouldNot(HaveOccurred())
+		Ω(joined.Cmp(&secret)).Should(Equal(0))
+		Ω(faults).Should(Equal([]int64{shares[0].Key, shares[5].Key, shares[9].Key}))
+
+		joined, faults, err = JoinRobust(&prime, k, corrupt(shares[:k+2], 1))
+		Ω(err).ShouldNot(HaveOccurred())
+		Ω(joined.Cmp(&secret)).Should(Equal(0))
+		Ω(faults).Should(Equal([]int64{shares[1].Key}))
+	})
+
+	It("should return an error when there are too many faulty shares", func() {
+		secret := stackint.FromUint(1234)
+		shares, err := Split(n, k, &prime, &secret)
+		Ω(err).ShouldNot(HaveOccurred())
+
+		_, _, err = JoinRobust(&prime, k, corrupt(shares[:k+1], 2))
+		Ω(err).Should(Equal(NewReconstructionError(k+1, k)))
+	})
+
+	It("should return an error when there are less than k shares", func() {
+		secret := stackint.FromUint(1234)
+		shares, err := Split(n, k, &prime, &secret)
+		Ω(err).ShouldNot(HaveOccurred())
+
+		_, _, err = JoinRobust(&prime, k, shares[:k-1])
+		Ω(err).Should(Equal(NewNKError(k-1, k)))
+	})
+
+	It("should return an error when share keys are not unique", func() {
+		secret := stackint.FromUint(1234)
+		shares, err := Split(n, k, &prime, &secret)
+		Ω(err).ShouldNot(HaveOccurred())
+
+		_, _, err = JoinRobust(&prime, k, append(shares[:k:k], shares[0]))
+		Ω(err).Should(HaveOccurred())
+	})
+
+	Context("when joining shares as they arrive", func() {
+
+		faults := (n - k) / 2
+
+		It("should join k + faults shares that are not faulty", func() {
+			secret := stackint.FromUint(1234)
+			shares, err := Split(n, k, &prime, &secret)
+			Ω(err).ShouldNot(HaveOccurred())
+
+			_, _, err = NewLagrangeCache(&prime).JoinOnline(k, faults, shares[:k+faults-1])
+			Ω(err).Should(HaveOccurred())
+
+			joined, faultyKeys, err := NewLagrangeCache(&prime).JoinOnline(k, faults, shares[:k+faults])
+			Ω(err).ShouldNot(HaveOccurred())
+			Ω(joined.Cmp(&secret)).Should(Equal(0))
+			Ω(faultyKeys).Should(BeEmpty())
+		})
+
+		It("should wait for one more share for each faulty share", func() {
+			secret := stackint.FromUint(1234)
+			shares, err := Split(n, k, &prime, &secret)
+			Ω(err).ShouldNot(HaveOccurred())
+			corrupted := corrupt(shares, 0, 1)
+
+			for m := k + faults; m < k+faults+2; m++ {
+				_, _, err = NewLagrangeCache(&prime).JoinOnline(k, faults, corrupted[:m])
+				Ω(err).Should(HaveOccurred())
+			}
+			joined, faultyKeys, err := NewLagrangeCache(&prime).JoinOnline(k, faults, corrupted[:k+faults+2])
+			Ω(err).ShouldNot(HaveOccurred())
+			Ω(joined.Cmp(&secret)).Should(Equal(0))
+			Ω(faultyKeys).Should(Equal([]int64{shares[0].Key, shares[1].Key}))
+		})
+	})
+})
@@ -25,57 +25,48 @@ type Shares []Share
 func Split(n int64, k int64, prime, secret *stackint.Int1024) (Shares, error) {
 	// Validate the encoding by checking that N is greater than K, and that the
 	// secret is within the finite field.
-	if n < k {
+	if n < k || k < 1 {
 		return nil, NewNKError(n, k)
 	}
 	if prime.Cmp(secret) <= 0 {
 		return nil, NewFiniteFieldError(secret)
 	}
+	mont, err := stackint.NewMontgomery(prime)
+	if err != nil {
+		return nil, err
+	}
 
 	// Generate K polynomial coefficients, where the first coefficient is the
-	// secret.
+	// secret. Coefficients are kept in the Montgomery domain so that the
+	// polynomial can be evaluated without any division, and only constant-time
+	// arithmetic is used on them.
 	one := stackint.One()
 	max := prime.Sub(&one)
-	coefficients := make([]*stackint.Int1024, k)
-	coefficients[0] = secret
+	coefficients := make([]stackint.Int1024, k)
+	coefficients[0] = mont.CtToMont(secret)
 
 	for i := int64(1); i < k; i++ {
 		coefficient, err := stackint.Random(rand.Reader, &max)
 		if err != nil {
 			return nil, err
 		}
-		coefficients[i] = &coefficient
+		coefficients[i] = mont.CtToMont(&coefficient)
 	}
 
-	// Create N shares.
+	// Create N shares by evaluating the polynomial at x using Horner's method.
 	shares := make(Shares, n)
 	for x := int64(1); x <= n; x++ {
-
-		// accum := coefficients[0]
-		accum := (*coefficients[0]).Clone()
-		// base := x
 		base := stackint.FromUint(uint(x))
-		// expMod := base % prime
-		exp := base.Clone()
-		expMod := exp.Mod(prime)
-
-		// Evaluate the polyomial at x.
-		for j := range coefficients[1:] {
-
-			// co := (coefficients * expoMod) % prime
-			coefficient := coefficients[j].Clone()
-			co := coefficient.MulModulo(&expMod, prime)
+		base = mont.ToMont(&base)
 
-			// accum = (accum + co) % prime
-			accum = accum.AddModulo(&co, prime)
-
-			// expMod = (expMod * base ) % prime
-			exp = exp.Mul(&base)
-			expMod = exp.Mod(prime)
+		accum := coefficients[k-1]
+		for j := k - 2; j >= 0; j-- {
+			accum = mont.CtMulMont(&accum, &base)
+			accum = accum.CtAddModulo(&coefficients[j], prime)
 		}
 		shares[x-1] = Share{
 			Key:   x,
-			Value: accum,
+			Value: mont.CtFromMont(&accum),
 		}
 	}
 	return shares, nil
@@ -87,6 +78,10 @@ func Split(n int64, k int64, prime, secret *stackint.Int1024) (Shares, error) {
 func Join(prime *stackint.Int1024, shares Shares) *stackint.Int1024 {
 	secret := stackint.Zero()
 
+	// Shares are secret, so they are only used in constant-time arithmetic
+	// when a Montgomery context is available
+	mont, err := stackint.NewMontgomery(prime)
+
 	// Compute the Lagrange basic polynomial interpolation.
 	for i := 0; i < len(shares); i++ {
 		num := stackint.One()
@@ -112,9 +107,14 @@ func Join(prime *stackint.Int1024, shares Shares) *stackint.Int1024 {
 		}
 
 		den = den.ModInverse(prime)
-		value := shares[i].Value.MulModulo(&num, prime)
-		value = value.MulModulo(&den, prime)
-		secret = secret.AddModulo(&value, prime)
+		coefficient := num.MulModulo(&den, prime)
+		if err != nil {
+			value := shares[i].Value.MulModulo(&coefficient, prime)
+			secret = secret.AddModulo(&value, prime)
+			continue
+		}
+		value := mont.CtMulModulo(&shares[i].Value, &coefficient)
+		secret = secret.CtAddModulo(&value, prime)
 	}
 
 	return &secret
@@ -59,6 +59,18 @@ var _ = Describe("Shamir's secret sharing", func() {
 			Ω(err).Should(BeNil())
 			Ω(int64(len(shares))).Should(Equal(n))
 		})
+
+		It("should not use the secret as any other coefficient", func() {
+			// With K = 2 the polynomial is a line, and its slope must be
+			// random
+			secret := stackint.FromUint(1234)
+			prime, err := stackint.FromString(primeStr)
+			Ω(err).Should(BeNil())
+			shares, err := Split(2, 2, &prime, &secret)
+			Ω(err).Should(BeNil())
+			slope := shares[1].Value.SubModulo(&shares[0].Value, &prime)
+			Ω(slope.Cmp(&secret)).ShouldNot(Equal(0))
+		})
 	})
 
 	Context("joining", func() {
@@ -0,0 +1,184 @@
+package shamir
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/binary"
+	"errors"
+	"math/big"
+
+	"github.com/republicprotocol/republic-go/stackint"
+)
+
+// vssPrefix is used to derive the generators of a VSS from a prime, so that
+// nobody knows the discrete logarithm of one generator with respect to the
+// other.
+var vssPrefix = []byte("Republic Protocol: vss: ")
+
+// ErrInvalidPrime is returned when VSS parameters cannot be created for a
+// prime.
+var ErrInvalidPrime = errors.New("invalid prime")
+
+// Commitments are Pedersen commitments to the coefficients of the polynomials
+// that were used to split a secret. The commitment to the ith coefficient is
+// G^a_i * H^b_i, where a_i is the coefficient of the polynomial that hides the
+// secret and b_i is the coefficient of the blinding polynomial.
+type Commitments []*big.Int
+
+// A VSS holds the public parameters for Pedersen verifiable secret sharing
+// over the finite field defined by Prime. Commitments are elements of the
+// subgroup of order Prime in the multiplicative group of integers modulo Q.
+// Pedersen commitments are used, instead of Feldman commitments, because they
+// do not reveal G^secret, which would allow small secrets to be found by
+// brute force.
+type VSS struct {
+	Prime *stackint.Int1024
+	Q     *big.Int
+	G     *big.Int
+	H     *big.Int
+
+	p *big.Int
+	r *big.Int
+}
+
+// NewVSS returns the VSS parameters for a prime. The parameters are derived
+// deterministically, so all parties that use the same prime agree on them.
+// The modulus Q is the smallest prime of the form r*Prime + 1, and the
+// generators are derived by hashing.
+func NewVSS(prime *stackint.Int1024) (*VSS, error) {
+	p := prime.ToBigInt()
+	if p.Cmp(big.NewInt(2)) <= 0 || !p.ProbablyPrime(20) {
+		return nil, ErrInvalidPrime
+	}
+	vss := &VSS{
+		Prime: prime,
+		Q:     new(big.Int),
+		p:     p,
+	}
+	for r := int64(2); ; r += 2 {
+		vss.Q.Mul(p, big.NewInt(r))
+		vss.Q.Add(vss.Q, big.NewInt(1))
+		if vss.Q.ProbablyPrime(20) {
+			vss.r = big.NewInt(r)
+			break
+		}
+	}
+	vss.G = vss.generator([]byte("G"))
+	vss.H = vss.generator([]byte("H"))
+	return vss, nil
+}
+
+// generator hashes a name into an element of the subgroup of order Prime.
+func (vss *VSS) generator(name []byte) *big.Int {
+	one := big.NewInt(1)
+	for i := uint64(0); ; i++ {
+		counter := make([]byte, 8)
+		binary.LittleEndian.PutUint64(counter, i)
+		hash := sha256.Sum256(append(append(append([]byte{}, vssPrefix...), name...), counter...))
+
+		g := new(big.Int).SetBytes(hash[:])
+		g.Exp(g, vss.r, vss.Q)
+		if g.Sign() != 0 && g.Cmp(one) != 0 {
+			return g
+		}
+	}
+}
+
+// Split a secret into Shares, and Shares of a random blinding secret, using
+// Pedersen verifiable secret sharing. N represents the number of Shares that
+// the secret will be split into, and K represents the number of Share required
+// to reconstruct the secret. The Shares, the blinding Shares, and the
+// Commitments that can be used to verify them, or an error, are returned.
+func (vss *VSS) Split(n, k int64, secret *stackint.Int1024) (Shares, Shares, Commitments, error) {
+	if n < k {
+		return nil, nil, nil, NewNKError(n, k)
+	}
+	if vss.Prime.Cmp(secret) <= 0 {
+		return nil, nil, nil, NewFiniteFieldError(secret)
+	}
+
+	// Generate K coefficients for the polynomial that hides the secret, and K
+	// coefficients for the blinding polynomial, and commit to them.
+	coefficients := make([]*big.Int, k)
+	blindingCoefficients := make([]*big.Int, k)
+	commitments := make(Commitments, k)
+	for i := int64(0); i < k; i++ {
+		var err error
+		if i == 0 {
+			coefficients[i] = secret.ToBigInt()
+		} else if coefficients[i], err = rand.Int(rand.Reader, vss.p); err != nil {
+			return nil, nil, nil, err
+		}
+		if blindingCoefficients[i], err = rand.Int(rand.Reader, vss.p); err != nil {
+			return nil, nil, nil, err
+		}
+		commitments[i] = vss.commit(coefficients[i], blindingCoefficients[i])
+	}
+
+	// Create N shares of each polynomial.
+	shares := make(Shares, n)
+	blindings := make(Shares, n)
+	for x := int64(1); x <= n; x++ {
+		value, err := stackint.FromBigInt(vss.evaluate(coefficients, x))
+		if err != nil {
+			return nil, nil, nil, err
+		}
+		blinding, err := stackint.FromBigInt(vss.evaluate(blindingCoefficients, x))
+		if err != nil {
+			return nil, nil, nil, err
+		}
+		shares[x-1] = Share{Key: x, Value: value}
+		blindings[x-1] = Share{Key: x, Value: blinding}
+	}
+	return shares, blindings, commitments, nil
+}
+
+// VerifyShare checks that a Share, and its blinding Share, are consistent with
+// the Commitments to the polynomials that were used to create them. K is the
+// number of Shares required to reconstruct the secret, and there must be
+// exactly one commitment for each of the K coefficients, so that the secret
+// cannot be hidden in a polynomial of a higher degree. A CommitmentError is
+// returned if they are not consistent.
+func (vss *VSS) VerifyShare(k int64, share, blinding Share, commitments Commitments) error {
+	if share.Key <= 0 || share.Key != blinding.Key || k <= 0 || int64(len(commitments)) != k {
+		return NewCommitmentError(share)
+	}
+	expected := big.NewInt(1)
+	x := big.NewInt(share.Key)
+	exp := big.NewInt(1)
+	for _, commitment := range commitments {
+		// Every commitment must be in the subgroup of order Prime
+		if !vss.inSubgroup(commitment) {
+			return NewCommitmentError(share)
+		}
+		term := new(big.Int).Exp(commitment, exp, vss.Q)
+		expected.Mul(expected, term)
+		expected.Mod(expected, vss.Q)
+		exp.Mul(exp, x)
+	}
+	if vss.commit(share.Value.ToBigInt(), blinding.Value.ToBigInt()).Cmp(expected) != 0 {
+		return NewCommitmentError(share)
+	}
+	return nil
+}
+
+// inSubgroup returns true if the commitment is an element of the subgroup of
+// order Prime, otherwise it returns false.
+func (vss *VSS) inSubgroup(commitment *big.Int) bool {
+	if commitment == nil || commitment.Sign() <= 0 || commitment.Cmp(vss.Q) >= 0 {
+		return false
+	}
+	return new(big.Int).Exp(commitment, vss.p, vss.Q).Cmp(big.NewInt(1)) == 0
+}
+
+// commit returns the Pedersen commitment G^value * H^blinding.
+func (vss *VSS) commit(value, blinding *big.Int) *big.Int {
+	commitment := new(big.Int).Exp(vss.G, value, vss.Q)
+	commitment.Mul(commitment, new(big.Int).Exp(vss.H, blinding, vss.Q))
+	return commitment.Mod(commitment, vss.Q)
+}
+
+// evaluate the polynomial with the given coefficients at x.
+func (vss *VSS) evaluate(coefficients []*big.Int, x int64) *big.Int {
+	return evaluatePolynomial(coefficients, big.NewInt(x), vss.p)
+}
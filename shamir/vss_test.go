@@ -0,0 +1,188 @@
+package shamir_test
+
+import (
+	"math/big"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	. "github.com/republicprotocol/republic-go/shamir"
+	"github.com/republicprotocol/republic-go/stackint"
+)
+
+var _ = Describe("Verifiable secret sharing", func() {
+
+	n := int64(8)
+	k := int64(6)
+	prime, _ := stackint.FromString("179769313486231590772930519078902473361797697894230657273430081157732675805500963132708477322407536021120113879871393357658789768814416622492847430639474124377767893424865485276302219601246094119453082952085005768838150682342462881473913110540827237163350510684586298239947245938479716304835356329624224137111")
+	vss, _ := NewVSS(&prime)
+
+	Context("when creating parameters", func() {
+
+		It("should derive the same parameters from the same prime", func() {
+			other, err := NewVSS(&prime)
+			Ω(err).ShouldNot(HaveOccurred())
+			Ω(other.Q.Cmp(vss.Q)).Should(Equal(0))
+			Ω(other.G.Cmp(vss.G)).Should(Equal(0))
+			Ω(other.H.Cmp(vss.H)).Should(Equal(0))
+			Ω(vss.G.Cmp(vss.H)).ShouldNot(Equal(0))
+		})
+
+		It("should return an error for numbers that are not prime", func() {
+			notPrime := stackint.FromUint(1000)
+			_, err := NewVSS(&notPrime)
+			Ω(err).Should(Equal(ErrInvalidPrime))
+		})
+	})
+
+	Context("when splitting secrets", func() {
+
+		It("should return shares that can be joined into the secret", func() {
+			secret := stackint.FromUint(1234)
+			shares, blindings, commitments, err := vss.Split(n, k, &secret)
+			Ω(err).ShouldNot(HaveOccurred())
+			Ω(shares).Should(HaveLen(int(n)))
+			Ω(blindings).Should(HaveLen(int(n)))
+			Ω(commitments).Should(HaveLen(int(k)))
+			Ω(Join(&prime, shares[:k]).Cmp(&secret)).Should(Equal(0))
+			Ω(Join(&prime, shares[n-k:]).Cmp(&secret)).Should(Equal(0))
+		})
+
+		It("should return an error when n is less than k", func() {
+			secret := stackint.FromUint(1234)
+			_, _, _, err := vss.Split(k, n, &secret)
+			Ω(err).Should(Equal(NewNKError(k, n)))
+		})
+
+		It("should return an error when the secret is outside the finite field", func() {
+			_, _, _, err := vss.Split(n, k, &prime)
+			Ω(err).Should(Equal(NewFiniteFieldError(&prime)))
+		})
+	})
+
+	Context("when verifying shares", func() {
+
+		It("should verify shares that are consistent with the commitments", func() {
+			secret := stackint.FromUint(1234)
+			shares, blindings, commitments, err := vss.Split(n, k, &secret)
+			Ω(err).ShouldNot(HaveOccurred())
+			for i := range shares {
+				Ω(vss.VerifyShare(k, shares[i], blindings[i], commitments)).ShouldNot(HaveOccurred())
+			}
+		})
+
+		It("should return a CommitmentError for modified shares", func() {
+			secret := stackint.FromUint(1234)
+			shares, blindings, commitments, err := vss.Split(n, k, &secret)
+			Ω(err).ShouldNot(HaveOccurred())
+
+			one := stackint.One()
+			share := Share{Key: shares[0].Key, Value: shares[0].Value.AddModulo(&one, &prime)}
+			Ω(vss.VerifyShare(k, share, blindings[0], commitments)).Should(Equal(NewCommitmentError(share)))
+
+			blinding := Share{Key: blindings[0].Key, Value: blindings[0].Value.AddModulo(&one, &prime)}
+			Ω(vss.VerifyShare(k, shares[0], blinding, commitments)).Should(HaveOccurred())
+
+			// Shares must be verified at their own index
+			Ω(vss.VerifyShare(k, Share{Key: 2, Value: shares[0].Value}, Share{Key: 2, Value: blindings[0].Value}, commitments)).Should(HaveOccurred())
+		})
+
+		It("should return a CommitmentError for shares of a different polynomial", func() {
+			secret := stackint.FromUint(1234)
+			shares, blindings, _, err := vss.Split(n, k, &secret)
+			Ω(err).ShouldNot(HaveOccurred())
+			_, _, commitments, err := vss.Split(n, k, &secret)
+			Ω(err).ShouldNot(HaveOccurred())
+			Ω(vss.VerifyShare(k, shares[0], blindings[0], commitments)).Should(HaveOccurred())
+		})
+
+		It("should return a CommitmentError for malformed commitments", func() {
+			secret := stackint.FromUint(1234)
+			shares, blindings, commitments, err := vss.Split(n, k, &secret)
+			Ω(err).ShouldNot(HaveOccurred())
+
+			Ω(vss.VerifyShare(k, shares[0], blindings[0], Commitments{})).Should(HaveOccurred())
+			Ω(vss.VerifyShare(k-1, shares[0], blindings[0], commitments)).Should(HaveOccurred())
+			Ω(vss.VerifyShare(k, shares[0], blindings[0], commitments[:k-1])).Should(HaveOccurred())
+			commitments[1] = new(big.Int).Sub(vss.Q, big.NewInt(1))
+			Ω(vss.VerifyShare(k, shares[0], blindings[0], commitments)).Should(HaveOccurred())
+			commitments[1] = nil
+			Ω(vss.VerifyShare(k, shares[0], blindings[0], commitments)).Should(HaveOccurred())
+		})
+	})
+
+	Context("when proving membership", func() {
+
+		// commitPair splits two secrets and returns the commitments to them,
+		// and their blindings
+		commitPair := func(fst, snd uint) ([]*big.Int, []*big.Int) {
+			commitments := []*big.Int{}
+			blindings := []*big.Int{}
+			for _, value := range []uint{fst, snd} {
+				secret := stackint.FromUint(value)
+				_, blindingShares, polynomial, err := vss.Split(n, k, &secret)
+				Ω(err).ShouldNot(HaveOccurred())
+				blinding := Join(&prime, blindingShares)
+				commitments = append(commitments, polynomial[0])
+				blindings = append(blindings, blinding.ToBigInt())
+			}
+			return commitments, blindings
+		}
+		members := [][]*big.Int{
+			{big.NewInt(2), big.NewInt(1)},
+			{big.NewInt(3), big.NewInt(2)},
+			{big.NewInt(4), big.NewInt(2)},
+		}
+
+		It("should verify proofs for every member", func() {
+			for i, member := range members {
+				commitments, blindings := commitPair(uint(member[0].Int64()), uint(member[1].Int64()))
+				proof, err := vss.ProveMembership(commitments, blindings, members, i)
+				Ω(err).ShouldNot(HaveOccurred())
+				Ω(vss.VerifyMembership(commitments, members, proof)).ShouldNot(HaveOccurred())
+			}
+		})
+
+		It("should not verify proofs for values that are not a member", func() {
+			// The values are members when taken separately, but not as a pair
+			commitments, blindings := commitPair(2, 2)
+			for i := range members {
+				proof, err := vss.ProveMembership(commitments, blindings, members, i)
+				Ω(err).ShouldNot(HaveOccurred())
+				Ω(vss.VerifyMembership(commitments, members, proof)).Should(Equal(ErrInvalidMembershipProof))
+			}
+		})
+
+		It("should not verify proofs against different members or commitments", func() {
+			commitments, blindings := commitPair(3, 2)
+			proof, err := vss.ProveMembership(commitments, blindings, members, 1)
+			Ω(err).ShouldNot(HaveOccurred())
+
+			others := [][]*big.Int{members[0], members[1], {big.NewInt(1), big.NewInt(4)}}
+			Ω(vss.VerifyMembership(commitments, others, proof)).Should(Equal(ErrInvalidMembershipProof))
+			Ω(vss.VerifyMembership(commitments, members[1:], proof)).Should(Equal(ErrInvalidMembershipProof))
+
+			otherCommitments, _ := commitPair(3, 2)
+			Ω(vss.VerifyMembership(otherCommitments, members, proof)).Should(Equal(ErrInvalidMembershipProof))
+		})
+
+		It("should not verify modified proofs", func() {
+			commitments, blindings := commitPair(4, 2)
+			proof, err := vss.ProveMembership(commitments, blindings, members, 2)
+			Ω(err).ShouldNot(HaveOccurred())
+
+			proof.Responses[0][1] = new(big.Int).Add(proof.Responses[0][1], big.NewInt(1))
+			Ω(vss.VerifyMembership(commitments, members, proof)).Should(Equal(ErrInvalidMembershipProof))
+			proof.Responses[0][1] = nil
+			Ω(vss.VerifyMembership(commitments, members, proof)).Should(Equal(ErrInvalidMembershipProof))
+			Ω(vss.VerifyMembership(commitments, members, nil)).Should(Equal(ErrInvalidMembershipProof))
+		})
+
+		It("should return an error for members that cannot be proven", func() {
+			commitments, blindings := commitPair(2, 1)
+			_, err := vss.ProveMembership(commitments, blindings, members, len(members))
+			Ω(err).Should(Equal(ErrInvalidMembershipProof))
+			_, err = vss.ProveMembership(commitments, blindings[:1], members, 0)
+			Ω(err).Should(Equal(ErrInvalidMembershipProof))
+		})
+	})
+})
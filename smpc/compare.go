@@ -0,0 +1,591 @@
+package smpc
+
+import (
+	"encoding/binary"
+
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/republicprotocol/go-do"
+	"github.com/republicprotocol/republic-go/compute"
+	"github.com/republicprotocol/republic-go/order"
+	"github.com/republicprotocol/republic-go/shamir"
+	"github.com/republicprotocol/republic-go/stackint"
+)
+
+// numberOfComparisons is the number of differences that are compared with
+// zero to decide whether or not two orders match. The currency codes are equal
+// when their difference, and the negation of their difference, are not
+// negative. The prices and volumes match when their differences are not
+// negative.
+const numberOfComparisons = 7
+
+// The stages of a comparison. Each stage runs for at least one round, and a
+// round ends when all values opened during the round are known.
+type stage int
+
+const (
+	stageSquareRandomValues = stage(iota)
+	stageOpenSquares
+	stageOpenMaskedValues
+	stageCompareBits
+	stageMultiplyResults
+)
+
+// A Comparator computes shares of a bit that is 1 when two orders match, and
+// 0 otherwise. The input is a DifferenceFragment and the output is a
+// DeltaFragment. Nothing else about the orders is revealed to the
+// participants.
+//
+// Every difference x must be in the range (-2^bits, 2^bits), so that the bit
+// of x + 2^bits at position bits is 1 when x is not negative. To extract this
+// bit, x + 2^bits is masked with a random value that has bits + security
+// shared random bits, and the masked value is opened. The bits of the masked
+// value are compared with the shared random bits to compute x + 2^bits modulo
+// 2^bits, without revealing x. The prime must be congruent to 3 modulo 4, and
+// must be larger than 2^(bits + security + 2).
+//
+// The random bits are generated from Beaver triples, and all multiplications
+// use Beaver triples, so the Beaver triples returned by ResidueIDs must be
+// generated before the comparison can start. In every round each participant
+// sends AlphaBetaFragments to all other participants, and the next round
+// starts once k AlphaBetaFragments have been received for each of them.
+//
+// Finished comparisons are remembered, so that they are not started again,
+// until one of their orders is removed.
+type Comparator struct {
+	do.GuardedObject
+
+	bits       int
+	security   int
+	prime      *stackint.Int1024
+	montgomery *stackint.Montgomery
+	multiplier *Multiplier
+
+	residues    map[string]*compute.ResidueFragment
+	comparisons map[string]*comparison
+	orders      map[string][]compute.DeltaID
+	waiting     map[string]*comparison
+	openings    map[string]*comparison
+}
+
+// NewComparator returns a Comparator for differences in the range (-2^bits,
+// 2^bits). Values are opened by inserting AlphaBetaFragments into the
+// Multiplier.
+func NewComparator(bits, security int, multiplier *Multiplier, prime *stackint.Int1024) *Comparator {
+	// Square roots are found by exponentiation, which is much faster using
+	// Montgomery multiplication. Without a Montgomery context, because the
+	// prime is even, the Comparator falls back to ExpModulo.
+	montgomery, _ := stackint.NewMontgomery(prime)
+	return &Comparator{
+		GuardedObject: do.NewGuardedObject(),
+		bits:          bits,
+		security:      security,
+		prime:         prime,
+		montgomery:    montgomery,
+		multiplier:    multiplier,
+		residues:      map[string]*compute.ResidueFragment{},
+		comparisons:   map[string]*comparison{},
+		orders:        map[string][]compute.DeltaID{},
+		waiting:       map[string]*comparison{},
+		openings:      map[string]*comparison{},
+	}
+}
+
+// ResidueIDs returns the ResidueIDs of the Beaver triples that are needed by
+// the comparison for a Delta. All participants derive the same ResidueIDs.
+func (comparator *Comparator) ResidueIDs(deltaID compute.DeltaID) []compute.ResidueID {
+	// One Beaver triple for each random bit, one for each bit that is
+	// compared after the first, and one for each product of the results
+	n := numberOfComparisons*(comparator.bits+comparator.security) +
+		numberOfComparisons*(comparator.bits-1) +
+		numberOfComparisons - 1
+	residueIDs := make([]compute.ResidueID, n)
+	for i := range residueIDs {
+		residueIDs[i] = compute.ResidueID(crypto.Keccak256([]byte(deltaID), []byte("residue"), uint32Bytes(i)))
+	}
+	return residueIDs
+}
+
+// InsertDifferenceFragment starts the comparison of a DifferenceFragment. If
+// all Beaver triples for the comparison have been inserted, the
+// AlphaBetaFragments for the first round are returned and must be sent to all
+// other participants. Otherwise, the comparison starts when the last Beaver
+// triple is inserted. If the other participants have already finished the
+// comparison, the DeltaFragment is also returned.
+func (comparator *Comparator) InsertDifferenceFragment(differenceFragment *compute.DifferenceFragment) ([]*compute.AlphaBetaFragment, *compute.DeltaFragment, error) {
+	comparator.Enter(nil)
+	defer comparator.Exit()
+	return comparator.insertDifferenceFragment(differenceFragment)
+}
+
+func (comparator *Comparator) insertDifferenceFragment(differenceFragment *compute.DifferenceFragment) ([]*compute.AlphaBetaFragment, *compute.DeltaFragment, error) {
+	if _, ok := comparator.comparisons[string(differenceFragment.DeltaID)]; ok {
+		return nil, nil, nil
+	}
+	comparison := &comparison{
+		deltaID:            differenceFragment.DeltaID,
+		differenceFragment: differenceFragment,
+		key:                differenceFragment.PriceShare.Key,
+		residueIDs:         comparator.ResidueIDs(differenceFragment.DeltaID),
+	}
+	comparator.comparisons[string(differenceFragment.DeltaID)] = comparison
+	comparator.orders[string(differenceFragment.BuyOrderID)] = append(comparator.orders[string(differenceFragment.BuyOrderID)], differenceFragment.DeltaID)
+	comparator.orders[string(differenceFragment.SellOrderID)] = append(comparator.orders[string(differenceFragment.SellOrderID)], differenceFragment.DeltaID)
+	for _, residueID := range comparison.residueIDs {
+		if _, ok := comparator.residues[string(residueID)]; !ok {
+			comparator.waiting[string(residueID)] = comparison
+			comparison.missingResidues++
+		}
+	}
+	return comparator.start(comparison)
+}
+
+// RemoveOrder removes all comparisons of an order, whether or not they have
+// finished. It returns the DeltaIDs of the comparisons that had not finished,
+// because their Beaver triples are no longer needed.
+func (comparator *Comparator) RemoveOrder(orderID order.ID) []compute.DeltaID {
+	comparator.Enter(nil)
+	defer comparator.Exit()
+	return comparator.removeOrder(orderID)
+}
+
+func (comparator *Comparator) removeOrder(orderID order.ID) []compute.DeltaID {
+	deltaIDs := []compute.DeltaID{}
+	for _, deltaID := range comparator.orders[string(orderID)] {
+		comparison, ok := comparator.comparisons[string(deltaID)]
+		if !ok {
+			continue
+		}
+		if comparison.differenceFragment != nil {
+			deltaIDs = append(deltaIDs, deltaID)
+		}
+		// Values opened after the comparison finished are also removed
+		comparator.remove(comparison)
+		delete(comparator.comparisons, string(deltaID))
+	}
+	delete(comparator.orders, string(orderID))
+	return deltaIDs
+}
+
+// InsertResidue inserts a Beaver triple. If it is the last Beaver triple
+// needed by a comparison, the comparison starts and the AlphaBetaFragments for
+// its first round are returned. They must be sent to all other participants.
+func (comparator *Comparator) InsertResidue(residue *compute.ResidueFragment) ([]*compute.AlphaBetaFragment, *compute.DeltaFragment, error) {
+	comparator.Enter(nil)
+	defer comparator.Exit()
+	return comparator.insertResidue(residue)
+}
+
+func (comparator *Comparator) insertResidue(residue *compute.ResidueFragment) ([]*compute.AlphaBetaFragment, *compute.DeltaFragment, error) {
+	if _, ok := comparator.residues[string(residue.ResidueID)]; ok {
+		return nil, nil, nil
+	}
+	comparator.residues[string(residue.ResidueID)] = residue
+	comparison, ok := comparator.waiting[string(residue.ResidueID)]
+	if !ok {
+		return nil, nil, nil
+	}
+	delete(comparator.waiting, string(residue.ResidueID))
+	comparison.missingResidues--
+	return comparator.start(comparison)
+}
+
+// InsertAlphaBetaFragment inserts an AlphaBetaFragment received from another
+// participant. When it ends a round of a comparison, the AlphaBetaFragments
+// for the next round are returned and must be sent to all other participants.
+// When it ends the last round, the DeltaFragment is returned.
+func (comparator *Comparator) InsertAlphaBetaFragment(alphaBetaFragment *compute.AlphaBetaFragment) ([]*compute.AlphaBetaFragment, *compute.DeltaFragment, error) {
+	comparator.Enter(nil)
+	defer comparator.Exit()
+	return comparator.insertAlphaBetaFragment(alphaBetaFragment)
+}
+
+func (comparator *Comparator) insertAlphaBetaFragment(alphaBetaFragment *compute.AlphaBetaFragment) ([]*compute.AlphaBetaFragment, *compute.DeltaFragment, error) {
+	if alphaBeta := comparator.multiplier.InsertAlphaBetaFragment(alphaBetaFragment); alphaBeta == nil {
+		return nil, nil, nil
+	}
+	comparison, ok := comparator.openings[string(alphaBetaFragment.ResidueID)]
+	if !ok {
+		// The value belongs to a round that has not been reached, and will
+		// be used when it is
+		return nil, nil, nil
+	}
+	return comparator.advance(comparison)
+}
+
+func (comparator *Comparator) start(comparison *comparison) ([]*compute.AlphaBetaFragment, *compute.DeltaFragment, error) {
+	if comparison.missingResidues > 0 || comparison.residues != nil || comparison.differenceFragment == nil {
+		return nil, nil, nil
+	}
+	residues := make([]*compute.ResidueFragment, len(comparison.residueIDs))
+	for i, residueID := range comparison.residueIDs {
+		residues[i] = comparator.residues[string(residueID)]
+		if residues[i].CShare.Key != comparison.key {
+			comparator.remove(comparison)
+			return nil, nil, ErrUnexpectedShareKey
+		}
+	}
+	comparison.residues = residues
+
+	alphaBetaFragments := comparator.squareRandomValues(comparison)
+	nextAlphaBetaFragments, deltaFragment, err := comparator.advance(comparison)
+	if err != nil {
+		return nil, nil, err
+	}
+	return append(alphaBetaFragments, nextAlphaBetaFragments...), deltaFragment, nil
+}
+
+// advance the comparison through all rounds for which the opened values are
+// known.
+func (comparator *Comparator) advance(comparison *comparison) ([]*compute.AlphaBetaFragment, *compute.DeltaFragment, error) {
+	alphaBetaFragments := []*compute.AlphaBetaFragment{}
+	for comparison.residues != nil {
+		alphaBetas := make([]*AlphaBeta, len(comparison.openingIDs))
+		for i, openingID := range comparison.openingIDs {
+			if alphaBetas[i] = comparator.multiplier.AlphaBeta(openingID); alphaBetas[i] == nil {
+				return alphaBetaFragments, nil, nil
+			}
+		}
+		for _, openingID := range comparison.openingIDs {
+			delete(comparator.openings, string(openingID))
+		}
+
+		var nextAlphaBetaFragments []*compute.AlphaBetaFragment
+		var err error
+		switch comparison.stage {
+		case stageSquareRandomValues:
+			nextAlphaBetaFragments = comparator.openSquares(comparison, alphaBetas)
+		case stageOpenSquares:
+			nextAlphaBetaFragments, err = comparator.openMaskedValues(comparison, alphaBetas)
+		case stageOpenMaskedValues:
+			nextAlphaBetaFragments = comparator.compareFirstBits(comparison, alphaBetas)
+		case stageCompareBits:
+			nextAlphaBetaFragments = comparator.compareBits(comparison, alphaBetas)
+		case stageMultiplyResults:
+			nextAlphaBetaFragments = comparator.multiplyResults(comparison, alphaBetas)
+		}
+		if err != nil {
+			comparator.remove(comparison)
+			return nil, nil, err
+		}
+		if len(comparison.results) == 1 {
+			deltaFragment := compute.NewDeltaFragment(comparison.differenceFragment, shamir.Share{
+				Key:   comparison.key,
+				Value: comparison.results[0],
+			})
+			comparator.remove(comparison)
+			return alphaBetaFragments, deltaFragment, nil
+		}
+		alphaBetaFragments = append(alphaBetaFragments, nextAlphaBetaFragments...)
+	}
+	return alphaBetaFragments, nil, nil
+}
+
+// squareRandomValues multiplies the B value of each Beaver triple reserved for
+// random bits with itself.
+func (comparator *Comparator) squareRandomValues(comparison *comparison) []*compute.AlphaBetaFragment {
+	residues := comparison.residues[:numberOfComparisons*(comparator.bits+comparator.security)]
+	alphaBetaFragments := make([]*compute.AlphaBetaFragment, len(residues))
+	for i, residue := range residues {
+		alphaBetaFragments[i] = compute.NewAlphaBetaFragment(residue.BShare, residue.BShare, residue, comparator.prime)
+	}
+	comparison.stage = stageSquareRandomValues
+	return comparator.multiply(comparison, alphaBetaFragments)
+}
+
+// openSquares opens the squares of the B values.
+func (comparator *Comparator) openSquares(comparison *comparison, alphaBetas []*AlphaBeta) []*compute.AlphaBetaFragment {
+	squares := make([]stackint.Int1024, len(alphaBetas))
+	for i, alphaBeta := range alphaBetas {
+		squares[i] = Multiply(comparison.residues[i], alphaBeta, comparator.prime).Value
+	}
+	comparison.stage = stageOpenSquares
+	return comparator.open(comparison, squares)
+}
+
+// openMaskedValues uses the opened squares to compute the random bits, and
+// opens every difference plus 2^bits, masked with the random bits.
+func (comparator *Comparator) openMaskedValues(comparison *comparison, alphaBetas []*AlphaBeta) ([]*compute.AlphaBetaFragment, error) {
+	// If s is a square root of B^2 then B/s is 1 or -1 with equal
+	// probability, so (B/s + 1)/2 is a random bit
+	one := stackint.One()
+	two := stackint.Two()
+	twoInv := two.ModInverse(comparator.prime)
+	exp := comparator.prime.Add(&one)
+	exp = exp.ShiftRight(2)
+
+	randomBits := make([]stackint.Int1024, len(alphaBetas))
+	for i, alphaBeta := range alphaBetas {
+		square := alphaBeta.Alpha
+		if square.IsZero() {
+			return nil, ErrRandomBitGeneration
+		}
+		root := comparator.expModulo(square, &exp)
+		if rootSquared := root.MulModulo(&root, comparator.prime); rootSquared.Cmp(square) != 0 {
+			return nil, ErrRandomBitGeneration
+		}
+		rootInv := root.ModInverse(comparator.prime)
+		bit := comparison.residues[i].BShare.Value.MulModulo(&rootInv, comparator.prime)
+		bit = bit.AddModulo(&one, comparator.prime)
+		randomBits[i] = bit.MulModulo(&twoInv, comparator.prime)
+	}
+
+	offset := one.ShiftLeft(uint(comparator.bits))
+	differenceFragment := comparison.differenceFragment
+	zero := stackint.Zero()
+	comparison.inputs = []stackint.Int1024{
+		differenceFragment.FstCodeShare.Value,
+		zero.SubModulo(&differenceFragment.FstCodeShare.Value, comparator.prime),
+		differenceFragment.SndCodeShare.Value,
+		zero.SubModulo(&differenceFragment.SndCodeShare.Value, comparator.prime),
+		differenceFragment.PriceShare.Value,
+		differenceFragment.MaxVolumeShare.Value,
+		differenceFragment.MinVolumeShare.Value,
+	}
+	comparison.randomBits = make([][]stackint.Int1024, numberOfComparisons)
+	maskedValues := make([]stackint.Int1024, numberOfComparisons)
+	for i := range comparison.inputs {
+		comparison.inputs[i] = comparison.inputs[i].AddModulo(&offset, comparator.prime)
+		comparison.randomBits[i] = randomBits[i*(comparator.bits+comparator.security) : (i+1)*(comparator.bits+comparator.security)]
+		mask := comparator.compose(comparison.randomBits[i])
+		maskedValues[i] = comparison.inputs[i].AddModulo(&mask, comparator.prime)
+	}
+	comparison.stage = stageOpenMaskedValues
+	return comparator.open(comparison, maskedValues), nil
+}
+
+// compareFirstBits starts the comparison of the opened masked values, a, with
+// the random masks, b, to compute a < b modulo 2^bits. The comparison starts
+// from the lowest bit, where a < b when a is 0 and b is 1.
+func (comparator *Comparator) compareFirstBits(comparison *comparison, alphaBetas []*AlphaBeta) []*compute.AlphaBetaFragment {
+	comparison.maskedValues = make([]stackint.Int1024, numberOfComparisons)
+	comparison.lessThans = make([]stackint.Int1024, numberOfComparisons)
+	for i, alphaBeta := range alphaBetas {
+		comparison.maskedValues[i] = *alphaBeta.Alpha
+		comparison.lessThans[i] = comparator.bitLessThan(comparison, i, 0)
+	}
+	comparison.bit = 0
+	return comparator.compareNextBits(comparison)
+}
+
+// compareBits computes a < b up to the current bit, from the product of a < b
+// up to the previous bit and a = b at the current bit.
+func (comparator *Comparator) compareBits(comparison *comparison, alphaBetas []*AlphaBeta) []*compute.AlphaBetaFragment {
+	for i, alphaBeta := range alphaBetas {
+		product := Multiply(comparator.compareBitsResidue(comparison, i, comparison.bit), alphaBeta, comparator.prime)
+		lessThan := comparator.bitLessThan(comparison, i, comparison.bit)
+		comparison.lessThans[i] = product.Value.AddModulo(&lessThan, comparator.prime)
+	}
+	return comparator.compareNextBits(comparison)
+}
+
+func (comparator *Comparator) compareNextBits(comparison *comparison) []*compute.AlphaBetaFragment {
+	comparison.bit++
+	if comparison.bit >= comparator.bits {
+		return comparator.startMultiplyResults(comparison)
+	}
+
+	one := stackint.One()
+	alphaBetaFragments := make([]*compute.AlphaBetaFragment, numberOfComparisons)
+	for i := range alphaBetaFragments {
+		equal := comparison.randomBits[i][comparison.bit]
+		if !comparison.maskedValues[i].IsBitSet(comparison.bit) {
+			equal = one.SubModulo(&equal, comparator.prime)
+		}
+		alphaBetaFragments[i] = compute.NewAlphaBetaFragment(
+			shamir.Share{Key: comparison.key, Value: equal},
+			shamir.Share{Key: comparison.key, Value: comparison.lessThans[i]},
+			comparator.compareBitsResidue(comparison, i, comparison.bit),
+			comparator.prime,
+		)
+	}
+	comparison.stage = stageCompareBits
+	return comparator.multiply(comparison, alphaBetaFragments)
+}
+
+// startMultiplyResults computes the result of each comparison, and starts
+// multiplying the results together. The result of a comparison is the bit of
+// the input at position bits.
+func (comparator *Comparator) startMultiplyResults(comparison *comparison) []*compute.AlphaBetaFragment {
+	one := stackint.One()
+	offset := one.ShiftLeft(uint(comparator.bits))
+	offsetInv := offset.ModInverse(comparator.prime)
+	mask := offset.Sub(&one)
+
+	comparison.results = make([]stackint.Int1024, numberOfComparisons)
+	for i := range comparison.results {
+		// The input modulo 2^bits is a - b + 2^bits(a < b), where a and b
+		// are the masked value and the random mask modulo 2^bits
+		a := comparison.maskedValues[i].AND(&mask)
+		b := comparator.compose(comparison.randomBits[i][:comparator.bits])
+		carry := comparison.lessThans[i].MulModulo(&offset, comparator.prime)
+		remainder := a.SubModulo(&b, comparator.prime)
+		remainder = remainder.AddModulo(&carry, comparator.prime)
+
+		result := comparison.inputs[i].SubModulo(&remainder, comparator.prime)
+		comparison.results[i] = result.MulModulo(&offsetInv, comparator.prime)
+	}
+	comparison.nextResultResidue = numberOfComparisons*(comparator.bits+comparator.security) + numberOfComparisons*(comparator.bits-1)
+	return comparator.multiplyNextResults(comparison)
+}
+
+// multiplyResults replaces each pair of results with their product.
+func (comparator *Comparator) multiplyResults(comparison *comparison, alphaBetas []*AlphaBeta) []*compute.AlphaBetaFragment {
+	results := make([]stackint.Int1024, 0, len(alphaBetas)+1)
+	for i, alphaBeta := range alphaBetas {
+		residue := comparison.residues[comparison.nextResultResidue-len(alphaBetas)+i]
+		results = append(results, Multiply(residue, alphaBeta, comparator.prime).Value)
+	}
+	if len(comparison.results)%2 == 1 {
+		results = append(results, comparison.results[len(comparison.results)-1])
+	}
+	comparison.results = results
+	return comparator.multiplyNextResults(comparison)
+}
+
+func (comparator *Comparator) multiplyNextResults(comparison *comparison) []*compute.AlphaBetaFragment {
+	if len(comparison.results) == 1 {
+		return nil
+	}
+	alphaBetaFragments := make([]*compute.AlphaBetaFragment, len(comparison.results)/2)
+	for i := range alphaBetaFragments {
+		alphaBetaFragments[i] = compute.NewAlphaBetaFragment(
+			shamir.Share{Key: comparison.key, Value: comparison.results[2*i]},
+			shamir.Share{Key: comparison.key, Value: comparison.results[2*i+1]},
+			comparison.residues[comparison.nextResultResidue],
+			comparator.prime,
+		)
+		comparison.nextResultResidue++
+	}
+	comparison.stage = stageMultiplyResults
+	return comparator.multiply(comparison, alphaBetaFragments)
+}
+
+// bitLessThan returns a share of 1 when the bit of the masked value is 0 and
+// the random bit is 1, and a share of 0 otherwise.
+func (comparator *Comparator) bitLessThan(comparison *comparison, i, bit int) stackint.Int1024 {
+	if comparison.maskedValues[i].IsBitSet(bit) {
+		return stackint.Zero()
+	}
+	return comparison.randomBits[i][bit]
+}
+
+// compareBitsResidue returns the Beaver triple used to compare a bit, other
+// than the first, of the ith comparison.
+func (comparator *Comparator) compareBitsResidue(comparison *comparison, i, bit int) *compute.ResidueFragment {
+	offset := numberOfComparisons * (comparator.bits + comparator.security)
+	return comparison.residues[offset+i*(comparator.bits-1)+bit-1]
+}
+
+// compose returns a share of the value that has the shared bits, from the
+// lowest bit to the highest bit.
+func (comparator *Comparator) compose(bits []stackint.Int1024) stackint.Int1024 {
+	value := stackint.Zero()
+	for i := len(bits) - 1; i >= 0; i-- {
+		value = value.AddModulo(&value, comparator.prime)
+		value = value.AddModulo(&bits[i], comparator.prime)
+	}
+	return value
+}
+
+// multiply inserts the AlphaBetaFragments of this participant, and waits for
+// the Alpha and Beta values of all of them to be opened.
+func (comparator *Comparator) multiply(comparison *comparison, alphaBetaFragments []*compute.AlphaBetaFragment) []*compute.AlphaBetaFragment {
+	comparison.openingIDs = make([]compute.ResidueID, len(alphaBetaFragments))
+	for i, alphaBetaFragment := range alphaBetaFragments {
+		comparison.openingIDs[i] = alphaBetaFragment.ResidueID
+		comparator.openings[string(alphaBetaFragment.ResidueID)] = comparison
+		comparator.multiplier.InsertAlphaBetaFragment(alphaBetaFragment)
+	}
+	return alphaBetaFragments
+}
+
+// open reveals values to all participants, by sending the shares of the
+// values as the Alpha shares of AlphaBetaFragments.
+func (comparator *Comparator) open(comparison *comparison, values []stackint.Int1024) []*compute.AlphaBetaFragment {
+	alphaBetaFragments := make([]*compute.AlphaBetaFragment, len(values))
+	for i := range values {
+		alphaBetaFragments[i] = &compute.AlphaBetaFragment{
+			ResidueID:  openingID(comparison.differenceFragment.DeltaID, comparison.stage, i),
+			AlphaShare: shamir.Share{Key: comparison.key, Value: values[i]},
+			BetaShare:  shamir.Share{Key: comparison.key, Value: stackint.Zero()},
+		}
+	}
+	return comparator.multiply(comparison, alphaBetaFragments)
+}
+
+// multiplicationIDs returns the IDs of all values that are opened by the
+// Multiplier during the comparison for a Delta. Values are opened for each
+// Beaver triple, and for the squares and masked values that are revealed.
+func (comparator *Comparator) multiplicationIDs(deltaID compute.DeltaID) []compute.ResidueID {
+	ids := comparator.ResidueIDs(deltaID)
+	for i := 0; i < numberOfComparisons*(comparator.bits+comparator.security); i++ {
+		ids = append(ids, openingID(deltaID, stageOpenSquares, i))
+	}
+	for i := 0; i < numberOfComparisons; i++ {
+		ids = append(ids, openingID(deltaID, stageOpenMaskedValues, i))
+	}
+	return ids
+}
+
+// remove the state of a comparison that has finished, or failed, including
+// the values opened by the Multiplier. The comparison itself is remembered
+// until one of its orders is removed, so that it is not started again.
+func (comparator *Comparator) remove(comparison *comparison) {
+	for _, residueID := range comparison.residueIDs {
+		delete(comparator.residues, string(residueID))
+		delete(comparator.waiting, string(residueID))
+	}
+	for _, openingID := range comparison.openingIDs {
+		delete(comparator.openings, string(openingID))
+	}
+	comparator.multiplier.RemoveAlphaBetas(comparator.multiplicationIDs(comparison.deltaID))
+	comparison.differenceFragment = nil
+	comparison.residueIDs = nil
+	comparison.residues = nil
+	comparison.openingIDs = nil
+	comparison.inputs = nil
+	comparison.randomBits = nil
+	comparison.maskedValues = nil
+	comparison.lessThans = nil
+	comparison.results = nil
+}
+
+type comparison struct {
+	deltaID            compute.DeltaID
+	differenceFragment *compute.DifferenceFragment
+	key                int64
+	residueIDs         []compute.ResidueID
+	residues           []*compute.ResidueFragment
+	missingResidues    int
+
+	stage      stage
+	openingIDs []compute.ResidueID
+
+	inputs            []stackint.Int1024
+	randomBits        [][]stackint.Int1024
+	maskedValues      []stackint.Int1024
+	lessThans         []stackint.Int1024
+	bit               int
+	results           []stackint.Int1024
+	nextResultResidue int
+}
+
+// openingID returns the ID of the ith value that is revealed during a stage of
+// the comparison for a Delta.
+func openingID(deltaID compute.DeltaID, stage stage, i int) compute.ResidueID {
+	return compute.ResidueID(crypto.Keccak256([]byte(deltaID), []byte("opening"), uint32Bytes(int(stage)), uint32Bytes(i)))
+}
+
+func uint32Bytes(n int) []byte {
+	bytes := make([]byte, 4)
+	binary.BigEndian.PutUint32(bytes, uint32(n))
+	return bytes
+}
+
+// expModulo returns x^e modulo the prime of the Comparator.
+func (comparator *Comparator) expModulo(x, e *stackint.Int1024) stackint.Int1024 {
+	if comparator.montgomery == nil {
+		return x.ExpModulo(e, comparator.prime)
+	}
+	return comparator.montgomery.ExpModulo(x, e)
+}
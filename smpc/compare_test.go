@@ -0,0 +1,248 @@
+package smpc_test
+
+import (
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	. "github.com/republicprotocol/republic-go/smpc"
+
+	"github.com/republicprotocol/republic-go/compute"
+	"github.com/republicprotocol/republic-go/identity"
+	"github.com/republicprotocol/republic-go/order"
+	"github.com/republicprotocol/republic-go/stackint"
+)
+
+var _ = Describe("Comparators", func() {
+
+	n := int64(5)
+	k := int64(4)
+	bits := 16
+	security := 8
+
+	Context("when comparing orders", func() {
+
+		It("should match orders with the same price and volume", func() {
+			buy := newOrder(order.ParityBuy, order.CurrencyCodeBTC, 10, 1000, 100)
+			sell := newOrder(order.ParitySell, order.CurrencyCodeBTC, 10, 1000, 100)
+			Ω(compareOrders(n, k, n, bits, security, buy, sell).IsMatch()).Should(BeTrue())
+		})
+
+		It("should match orders when the buy price is higher", func() {
+			buy := newOrder(order.ParityBuy, order.CurrencyCodeBTC, 12, 1000, 100)
+			sell := newOrder(order.ParitySell, order.CurrencyCodeBTC, 10, 800, 200)
+			Ω(compareOrders(n, k, n, bits, security, buy, sell).IsMatch()).Should(BeTrue())
+		})
+
+		It("should not match orders when the buy price is lower", func() {
+			buy := newOrder(order.ParityBuy, order.CurrencyCodeBTC, 9, 1000, 100)
+			sell := newOrder(order.ParitySell, order.CurrencyCodeBTC, 10, 1000, 100)
+			Ω(compareOrders(n, k, n, bits, security, buy, sell).IsMatch()).Should(BeFalse())
+		})
+
+		It("should not match orders for different currencies", func() {
+			buy := newOrder(order.ParityBuy, order.CurrencyCodeBTC, 10, 1000, 100)
+			sell := newOrder(order.ParitySell, order.CurrencyCodeDGD, 10, 1000, 100)
+			Ω(compareOrders(n, k, n, bits, security, buy, sell).IsMatch()).Should(BeFalse())
+		})
+
+		It("should not match orders when the buy volume is too low", func() {
+			buy := newOrder(order.ParityBuy, order.CurrencyCodeBTC, 10, 100, 50)
+			sell := newOrder(order.ParitySell, order.CurrencyCodeBTC, 10, 1000, 200)
+			Ω(compareOrders(n, k, n, bits, security, buy, sell).IsMatch()).Should(BeFalse())
+		})
+
+		It("should not match orders when the sell volume is too low", func() {
+			buy := newOrder(order.ParityBuy, order.CurrencyCodeBTC, 10, 1000, 200)
+			sell := newOrder(order.ParitySell, order.CurrencyCodeBTC, 10, 100, 50)
+			Ω(compareOrders(n, k, n, bits, security, buy, sell).IsMatch()).Should(BeFalse())
+		})
+
+		It("should finish with only k participants", func() {
+			buy := newOrder(order.ParityBuy, order.CurrencyCodeBTC, 12, 1000, 100)
+			sell := newOrder(order.ParitySell, order.CurrencyCodeBTC, 10, 800, 200)
+			Ω(compareOrders(n, k, k, bits, security, buy, sell).IsMatch()).Should(BeTrue())
+		})
+	})
+
+	Context("when inserting residues", func() {
+
+		It("should require a residue for every residue ID", func() {
+			multiplier := NewMultiplier(k, prime)
+			comparator := NewComparator(bits, security, multiplier, prime)
+			deltaID := compute.DeltaID("delta")
+			residueIDs := comparator.ResidueIDs(deltaID)
+			Ω(residueIDs).Should(HaveLen(7*(bits+security) + 7*(bits-1) + 6))
+			Ω(comparator.ResidueIDs(deltaID)).Should(Equal(residueIDs))
+			Ω(comparator.ResidueIDs(compute.DeltaID("other"))).ShouldNot(Equal(residueIDs))
+		})
+
+		It("should return an error for residues with the wrong share key", func() {
+			buy := newOrder(order.ParityBuy, order.CurrencyCodeBTC, 10, 1000, 100)
+			sell := newOrder(order.ParitySell, order.CurrencyCodeBTC, 10, 1000, 100)
+			differenceFragments := computeDifferenceFragments(n, k, buy, sell)
+
+			multiplier := NewMultiplier(k, prime)
+			comparator := NewComparator(bits, security, multiplier, prime)
+			_, _, err := comparator.InsertDifferenceFragment(differenceFragments[0])
+			Ω(err).ShouldNot(HaveOccurred())
+			for _, residueID := range comparator.ResidueIDs(differenceFragments[0].DeltaID) {
+				residues := generateResidues(n, k, residueID)
+				_, _, err = comparator.InsertResidue(residues[1])
+			}
+			Ω(err).Should(Equal(ErrUnexpectedShareKey))
+		})
+	})
+
+	Context("when removing orders", func() {
+
+		var differenceFragment *compute.DifferenceFragment
+		var comparator *Comparator
+
+		insertWrongResidues := func() error {
+			var err error
+			for _, residueID := range comparator.ResidueIDs(differenceFragment.DeltaID) {
+				residues := generateResidues(n, k, residueID)
+				if _, _, insertErr := comparator.InsertResidue(residues[1]); insertErr != nil {
+					err = insertErr
+				}
+			}
+			return err
+		}
+
+		BeforeEach(func() {
+			buy := newOrder(order.ParityBuy, order.CurrencyCodeBTC, 10, 1000, 100)
+			sell := newOrder(order.ParitySell, order.CurrencyCodeBTC, 10, 1000, 100)
+			differenceFragment = computeDifferenceFragments(n, k, buy, sell)[0]
+			comparator = NewComparator(bits, security, NewMultiplier(k, prime), prime)
+		})
+
+		It("should return the comparisons that have not finished", func() {
+			_, _, err := comparator.InsertDifferenceFragment(differenceFragment)
+			Ω(err).ShouldNot(HaveOccurred())
+			Ω(comparator.RemoveOrder(differenceFragment.BuyOrderID)).Should(Equal([]compute.DeltaID{differenceFragment.DeltaID}))
+			Ω(comparator.RemoveOrder(differenceFragment.SellOrderID)).Should(BeEmpty())
+		})
+
+		It("should remember finished comparisons until an order is removed", func() {
+			_, _, err := comparator.InsertDifferenceFragment(differenceFragment)
+			Ω(err).ShouldNot(HaveOccurred())
+			Ω(insertWrongResidues()).Should(Equal(ErrUnexpectedShareKey))
+
+			// The failed comparison is not started again
+			_, _, err = comparator.InsertDifferenceFragment(differenceFragment)
+			Ω(err).ShouldNot(HaveOccurred())
+			Ω(insertWrongResidues()).ShouldNot(HaveOccurred())
+
+			// The residues inserted above are used when it is started again
+			Ω(comparator.RemoveOrder(differenceFragment.SellOrderID)).Should(BeEmpty())
+			_, _, err = comparator.InsertDifferenceFragment(differenceFragment)
+			Ω(err).Should(Equal(ErrUnexpectedShareKey))
+		})
+	})
+})
+
+func newOrder(parity order.Parity, currency order.CurrencyCode, price, maxVolume, minVolume uint) *order.Order {
+	priceVal := stackint.FromUint(price)
+	maxVolumeVal := stackint.FromUint(maxVolume)
+	minVolumeVal := stackint.FromUint(minVolume)
+	nonce := stackint.Zero()
+	return order.NewOrder(order.TypeLimit, parity, time.Now().Add(time.Hour), currency, order.CurrencyCodeETH, &priceVal, &maxVolumeVal, &minVolumeVal, &nonce)
+}
+
+func computeDifferenceFragments(n, k int64, buy, sell *order.Order) []*compute.DifferenceFragment {
+	buyFragments, err := buy.Split(n, k, prime)
+	Ω(err).ShouldNot(HaveOccurred())
+	sellFragments, err := sell.Split(n, k, prime)
+	Ω(err).ShouldNot(HaveOccurred())
+
+	// The buy and sell orders must be opened by different traders
+	buyer, err := identity.NewKeyPair()
+	Ω(err).ShouldNot(HaveOccurred())
+	Ω(order.SignFragments(buyer, buyFragments)).ShouldNot(HaveOccurred())
+	seller, err := identity.NewKeyPair()
+	Ω(err).ShouldNot(HaveOccurred())
+	Ω(order.SignFragments(seller, sellFragments)).ShouldNot(HaveOccurred())
+	differenceFragments := make([]*compute.DifferenceFragment, n)
+	for i := range differenceFragments {
+		differenceFragments[i] = compute.NewDifferenceFragment(buyFragments[i], sellFragments[i], prime)
+		Ω(differenceFragments[i]).ShouldNot(BeNil())
+	}
+	return differenceFragments
+}
+
+// compareOrders runs a comparison between the first m of n participants, and
+// reconstructs the Delta from the DeltaFragments that they produce.
+// AlphaBetaFragments are delivered to the other participants in the order that
+// they are produced.
+func compareOrders(n, k, m int64, bits, security int, buy, sell *order.Order) *compute.Delta {
+	differenceFragments := computeDifferenceFragments(n, k, buy, sell)
+
+	multipliers := make([]*Multiplier, m)
+	comparators := make([]*Comparator, m)
+	for i := range comparators {
+		multipliers[i] = NewMultiplier(k, prime)
+		comparators[i] = NewComparator(bits, security, multipliers[i], prime)
+	}
+
+	type message struct {
+		from              int
+		alphaBetaFragment *compute.AlphaBetaFragment
+	}
+	messages := []message{}
+	deltaFragments := make([]*compute.DeltaFragment, m)
+	handle := func(i int) func([]*compute.AlphaBetaFragment, *compute.DeltaFragment, error) {
+		return func(alphaBetaFragments []*compute.AlphaBetaFragment, deltaFragment *compute.DeltaFragment, err error) {
+			Ω(err).ShouldNot(HaveOccurred())
+			for _, alphaBetaFragment := range alphaBetaFragments {
+				messages = append(messages, message{i, alphaBetaFragment})
+			}
+			if deltaFragment != nil {
+				Ω(deltaFragments[i]).Should(BeNil())
+				deltaFragments[i] = deltaFragment
+			}
+		}
+	}
+
+	// Half of the participants receive the DifferenceFragment before the
+	// residues, and the other half after
+	for i := range comparators {
+		if i%2 == 0 {
+			handle(i)(comparators[i].InsertDifferenceFragment(differenceFragments[i]))
+		}
+	}
+	for _, residueID := range comparators[0].ResidueIDs(differenceFragments[0].DeltaID) {
+		residues := generateResidues(n, k, residueID)
+		for i := range comparators {
+			handle(i)(comparators[i].InsertResidue(residues[i]))
+		}
+	}
+	for i := range comparators {
+		if i%2 == 1 {
+			handle(i)(comparators[i].InsertDifferenceFragment(differenceFragments[i]))
+		}
+	}
+
+	for len(messages) > 0 {
+		msg := messages[0]
+		messages = messages[1:]
+		for i := range comparators {
+			if i != msg.from {
+				handle(i)(comparators[i].InsertAlphaBetaFragment(msg.alphaBetaFragment))
+			}
+		}
+	}
+
+	// The values opened in the first round are removed when the comparison
+	// finishes
+	residueID := comparators[0].ResidueIDs(differenceFragments[0].DeltaID)[0]
+	for i := range deltaFragments {
+		Ω(deltaFragments[i]).ShouldNot(BeNil())
+		Ω(deltaFragments[i].DeltaID).Should(Equal(differenceFragments[i].DeltaID))
+		Ω(multipliers[i].AlphaBeta(residueID)).Should(BeNil())
+	}
+	delta := compute.NewDelta(deltaFragments[:k], prime)
+	Ω(delta).ShouldNot(BeNil())
+	Ω(compute.NewDelta(deltaFragments[m-k:], prime).Match.Cmp(delta.Match)).Should(Equal(0))
+	return delta
+}
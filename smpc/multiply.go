@@ -0,0 +1,181 @@
+package smpc
+
+import (
+	"time"
+
+	"github.com/republicprotocol/go-do"
+	"github.com/republicprotocol/republic-go/compute"
+	"github.com/republicprotocol/republic-go/shamir"
+	"github.com/republicprotocol/republic-go/stackint"
+)
+
+// An AlphaBeta holds the opened Alpha and Beta values that are needed to use
+// a Beaver triple for multiplication.
+type AlphaBeta struct {
+	ResidueID compute.ResidueID
+	Alpha     *stackint.Int1024
+	Beta      *stackint.Int1024
+}
+
+// A Multiplier collects AlphaBetaFragments and opens the Alpha and Beta values
+// once k + faults AlphaBetaFragments for the same Beaver triple are
+// consistent, so that faulty AlphaBetaFragments are corrected instead of
+// opening the wrong values. The opened values are kept until they are
+// removed, and AlphaBetaFragments that arrive after their values have been
+// removed are kept until they expire.
+type Multiplier struct {
+	do.GuardedObject
+
+	k                  int64
+	faults             int64
+	prime              *stackint.Int1024
+	lagranges          *shamir.LagrangeCache
+	alphaBetas         map[string]*AlphaBeta
+	alphaBetaFragments map[string][]*compute.AlphaBetaFragment
+	inserted           map[string]time.Time
+}
+
+// NewMultiplier returns a Multiplier which opens Alpha and Beta values when it
+// receives k consistent AlphaBetaFragments. Use SetFaults to tolerate faulty
+// AlphaBetaFragments.
+func NewMultiplier(k int64, prime *stackint.Int1024) *Multiplier {
+	return &Multiplier{
+		GuardedObject:      do.NewGuardedObject(),
+		k:                  k,
+		prime:              prime,
+		lagranges:          shamir.NewLagrangeCache(prime),
+		alphaBetas:         map[string]*AlphaBeta{},
+		alphaBetaFragments: map[string][]*compute.AlphaBetaFragment{},
+		inserted:           map[string]time.Time{},
+	}
+}
+
+// InsertAlphaBetaFragment inserts an AlphaBetaFragment. If at least k + faults
+// of the AlphaBetaFragments for the same Beaver triple are consistent, it will
+// open the Alpha and Beta values and return them. The values are only returned
+// once.
+func (multiplier *Multiplier) InsertAlphaBetaFragment(alphaBetaFragment *compute.AlphaBetaFragment) *AlphaBeta {
+	multiplier.Enter(nil)
+	defer multiplier.Exit()
+	return multiplier.insertAlphaBetaFragment(alphaBetaFragment)
+}
+
+func (multiplier *Multiplier) insertAlphaBetaFragment(alphaBetaFragment *compute.AlphaBetaFragment) *AlphaBeta {
+	residueID := string(alphaBetaFragment.ResidueID)
+	if _, ok := multiplier.alphaBetas[residueID]; ok {
+		return nil // Only return new AlphaBetas
+	}
+	for _, other := range multiplier.alphaBetaFragments[residueID] {
+		if other.AlphaShare.Key == alphaBetaFragment.AlphaShare.Key {
+			return nil // Only return new AlphaBetas
+		}
+	}
+	if _, ok := multiplier.inserted[residueID]; !ok {
+		multiplier.inserted[residueID] = time.Now()
+	}
+	multiplier.alphaBetaFragments[residueID] = append(multiplier.alphaBetaFragments[residueID], alphaBetaFragment)
+
+	alphaBetaFragments := multiplier.alphaBetaFragments[residueID]
+	if int64(len(alphaBetaFragments)) < multiplier.k+multiplier.faults {
+		return nil
+	}
+	alphaShares := make(shamir.Shares, len(alphaBetaFragments))
+	betaShares := make(shamir.Shares, len(alphaBetaFragments))
+	for i, alphaBetaFragment := range alphaBetaFragments {
+		alphaShares[i] = alphaBetaFragment.AlphaShare
+		betaShares[i] = alphaBetaFragment.BetaShare
+	}
+
+	// Wait for more AlphaBetaFragments if too many of them are faulty
+	alpha, _, err := multiplier.lagranges.JoinOnline(multiplier.k, multiplier.faults, alphaShares)
+	if err != nil {
+		return nil
+	}
+	beta, _, err := multiplier.lagranges.JoinOnline(multiplier.k, multiplier.faults, betaShares)
+	if err != nil {
+		return nil
+	}
+	alphaBeta := &AlphaBeta{
+		ResidueID: alphaBetaFragment.ResidueID,
+		Alpha:     alpha,
+		Beta:      beta,
+	}
+	multiplier.alphaBetas[residueID] = alphaBeta
+	delete(multiplier.alphaBetaFragments, residueID)
+	return alphaBeta
+}
+
+// AlphaBeta returns the opened Alpha and Beta values for the Beaver triple
+// with the given ResidueID, or nil if they have not been opened.
+func (multiplier *Multiplier) AlphaBeta(residueID compute.ResidueID) *AlphaBeta {
+	multiplier.EnterReadOnly(nil)
+	defer multiplier.ExitReadOnly()
+	return multiplier.alphaBetas[string(residueID)]
+}
+
+// RemoveAlphaBetas removes the opened Alpha and Beta values, and any
+// AlphaBetaFragments, for the Beaver triples with the given ResidueIDs.
+func (multiplier *Multiplier) RemoveAlphaBetas(residueIDs []compute.ResidueID) {
+	multiplier.Enter(nil)
+	defer multiplier.Exit()
+	for _, residueID := range residueIDs {
+		multiplier.removeAlphaBeta(string(residueID))
+	}
+}
+
+// RemoveExpiredAlphaBetas removes the opened Alpha and Beta values, and any
+// AlphaBetaFragments, for all Beaver triples that received their first
+// AlphaBetaFragment before the given time. It returns the number of Beaver
+// triples that were removed.
+func (multiplier *Multiplier) RemoveExpiredAlphaBetas(before time.Time) int {
+	multiplier.Enter(nil)
+	defer multiplier.Exit()
+	n := 0
+	for residueID, inserted := range multiplier.inserted {
+		if inserted.Before(before) {
+			multiplier.removeAlphaBeta(residueID)
+			n++
+		}
+	}
+	return n
+}
+
+func (multiplier *Multiplier) removeAlphaBeta(residueID string) {
+	delete(multiplier.alphaBetas, residueID)
+	delete(multiplier.alphaBetaFragments, residueID)
+	delete(multiplier.inserted, residueID)
+}
+
+// SetK updates the required number of AlphaBetaFragments to open the Alpha and
+// Beta values.
+func (multiplier *Multiplier) SetK(k int64) {
+	multiplier.Enter(nil)
+	defer multiplier.Exit()
+	multiplier.k = k
+}
+
+// SetFaults updates the number of faulty AlphaBetaFragments that must be
+// tolerated when the Alpha and Beta values are opened. The Multiplier waits
+// for k + faults consistent AlphaBetaFragments before it opens them.
+func (multiplier *Multiplier) SetFaults(faults int64) {
+	multiplier.Enter(nil)
+	defer multiplier.Exit()
+	multiplier.faults = faults
+}
+
+// Multiply returns a share of the product of the two shares that were used to
+// create the AlphaBeta. Given the shares of a Beaver triple (A, B, C), the
+// product XY is equal to C + Alpha*B + Beta*A + Alpha*Beta.
+func Multiply(residueFragment *compute.ResidueFragment, alphaBeta *AlphaBeta, prime *stackint.Int1024) shamir.Share {
+	alphaB := alphaBeta.Alpha.MulModulo(&residueFragment.BShare.Value, prime)
+	betaA := alphaBeta.Beta.MulModulo(&residueFragment.AShare.Value, prime)
+	alphaBetaProduct := alphaBeta.Alpha.MulModulo(alphaBeta.Beta, prime)
+
+	value := residueFragment.CShare.Value.AddModulo(&alphaB, prime)
+	value = value.AddModulo(&betaA, prime)
+	value = value.AddModulo(&alphaBetaProduct, prime)
+	return shamir.Share{
+		Key:   residueFragment.CShare.Key,
+		Value: value,
+	}
+}
@@ -0,0 +1,152 @@
+package smpc_test
+
+import (
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	. "github.com/republicprotocol/republic-go/smpc"
+
+	"github.com/republicprotocol/republic-go/compute"
+	"github.com/republicprotocol/republic-go/shamir"
+	"github.com/republicprotocol/republic-go/stackint"
+)
+
+var _ = Describe("Multipliers", func() {
+
+	n := int64(5)
+	k := int64(4)
+
+	Context("when multiplying shares", func() {
+
+		It("should produce shares of the product", func() {
+			residueID := compute.ResidueID("multiply")
+			residues := generateResidues(n, k, residueID)
+
+			x := stackint.FromUint(1234567)
+			y := stackint.FromUint(7654321)
+			xShares, err := shamir.Split(n, k, prime, &x)
+			Ω(err).ShouldNot(HaveOccurred())
+			yShares, err := shamir.Split(n, k, prime, &y)
+			Ω(err).ShouldNot(HaveOccurred())
+
+			multipliers := make([]*Multiplier, n)
+			alphaBetaFragments := make([]*compute.AlphaBetaFragment, n)
+			for i := range multipliers {
+				multipliers[i] = NewMultiplier(k, prime)
+				alphaBetaFragments[i] = compute.NewAlphaBetaFragment(xShares[i], yShares[i], residues[i], prime)
+			}
+
+			productShares := make(shamir.Shares, n)
+			for i := range multipliers {
+				var alphaBeta *AlphaBeta
+				for j := range alphaBetaFragments {
+					if opened := multipliers[i].InsertAlphaBetaFragment(alphaBetaFragments[j]); opened != nil {
+						Ω(alphaBeta).Should(BeNil())
+						alphaBeta = opened
+					}
+				}
+				Ω(alphaBeta).ShouldNot(BeNil())
+				Ω(multipliers[i].AlphaBeta(residueID)).Should(Equal(alphaBeta))
+				productShares[i] = Multiply(residues[i], alphaBeta, prime)
+			}
+
+			xy := x.MulModulo(&y, prime)
+			Ω(shamir.Join(prime, productShares[:k]).Cmp(&xy)).Should(Equal(0))
+			Ω(shamir.Join(prime, productShares[n-k:]).Cmp(&xy)).Should(Equal(0))
+		})
+
+		It("should not open alpha and beta with fewer than k fragments", func() {
+			residueID := compute.ResidueID("multiply")
+			residues := generateResidues(n, k, residueID)
+
+			x := stackint.FromUint(42)
+			xShares, err := shamir.Split(n, k, prime, &x)
+			Ω(err).ShouldNot(HaveOccurred())
+
+			multiplier := NewMultiplier(k, prime)
+			for i := int64(0); i < k-1; i++ {
+				alphaBetaFragment := compute.NewAlphaBetaFragment(xShares[i], xShares[i], residues[i], prime)
+				Ω(multiplier.InsertAlphaBetaFragment(alphaBetaFragment)).Should(BeNil())
+				// Duplicates must not count towards k
+				Ω(multiplier.InsertAlphaBetaFragment(alphaBetaFragment)).Should(BeNil())
+			}
+			Ω(multiplier.AlphaBeta(residueID)).Should(BeNil())
+		})
+
+		It("should correct faulty fragments when opening alpha and beta", func() {
+			n, k, faults := int64(7), int64(5), int64(1)
+			residueID := compute.ResidueID("faulty")
+			residues := generateResidues(n, k, residueID)
+
+			x := stackint.FromUint(1234567)
+			xShares, err := shamir.Split(n, k, prime, &x)
+			Ω(err).ShouldNot(HaveOccurred())
+			alphaBetaFragments := make([]*compute.AlphaBetaFragment, n)
+			for i := range alphaBetaFragments {
+				alphaBetaFragments[i] = compute.NewAlphaBetaFragment(xShares[i], xShares[i], residues[i], prime)
+			}
+
+			honest := NewMultiplier(k, prime)
+			var expected *AlphaBeta
+			for i := int64(0); i < k; i++ {
+				expected = honest.InsertAlphaBetaFragment(alphaBetaFragments[i])
+			}
+			Ω(expected).ShouldNot(BeNil())
+
+			one := stackint.One()
+			alphaBetaFragments[0].AlphaShare.Value = alphaBetaFragments[0].AlphaShare.Value.AddModulo(&one, prime)
+			multiplier := NewMultiplier(k, prime)
+			multiplier.SetFaults(faults)
+			for i := int64(0); i < k+faults; i++ {
+				Ω(multiplier.InsertAlphaBetaFragment(alphaBetaFragments[i])).Should(BeNil())
+			}
+			alphaBeta := multiplier.InsertAlphaBetaFragment(alphaBetaFragments[k+faults])
+			Ω(alphaBeta).ShouldNot(BeNil())
+			Ω(alphaBeta.Alpha.Cmp(expected.Alpha)).Should(Equal(0))
+			Ω(alphaBeta.Beta.Cmp(expected.Beta)).Should(Equal(0))
+		})
+	})
+
+	Context("when removing alpha and beta values", func() {
+
+		// openAlphaBeta inserts the AlphaBetaFragments of the participants in
+		// the range [from, to)
+		openAlphaBeta := func(multiplier *Multiplier, residueID compute.ResidueID, from, to int64) {
+			residues := generateResidues(n, k, residueID)
+			x := stackint.FromUint(42)
+			xShares, err := shamir.Split(n, k, prime, &x)
+			Ω(err).ShouldNot(HaveOccurred())
+			for i := from; i < to; i++ {
+				multiplier.InsertAlphaBetaFragment(compute.NewAlphaBetaFragment(xShares[i], xShares[i], residues[i], prime))
+			}
+		}
+
+		It("should remove opened values and fragments", func() {
+			multiplier := NewMultiplier(k, prime)
+			openAlphaBeta(multiplier, compute.ResidueID("opened"), 0, k)
+			openAlphaBeta(multiplier, compute.ResidueID("partial"), 0, k-1)
+			openAlphaBeta(multiplier, compute.ResidueID("kept"), 0, k)
+			Ω(multiplier.AlphaBeta(compute.ResidueID("opened"))).ShouldNot(BeNil())
+
+			multiplier.RemoveAlphaBetas([]compute.ResidueID{compute.ResidueID("opened"), compute.ResidueID("partial")})
+			Ω(multiplier.AlphaBeta(compute.ResidueID("opened"))).Should(BeNil())
+			Ω(multiplier.AlphaBeta(compute.ResidueID("kept"))).ShouldNot(BeNil())
+
+			// Fragments inserted before the removal do not count towards k
+			openAlphaBeta(multiplier, compute.ResidueID("partial"), k-1, k)
+			Ω(multiplier.AlphaBeta(compute.ResidueID("partial"))).Should(BeNil())
+		})
+
+		It("should remove expired values and fragments", func() {
+			multiplier := NewMultiplier(k, prime)
+			openAlphaBeta(multiplier, compute.ResidueID("opened"), 0, k)
+			openAlphaBeta(multiplier, compute.ResidueID("partial"), 0, k-1)
+			Ω(multiplier.RemoveExpiredAlphaBetas(time.Now().Add(-time.Minute))).Should(Equal(0))
+			Ω(multiplier.AlphaBeta(compute.ResidueID("opened"))).ShouldNot(BeNil())
+
+			Ω(multiplier.RemoveExpiredAlphaBetas(time.Now().Add(time.Minute))).Should(Equal(2))
+			Ω(multiplier.AlphaBeta(compute.ResidueID("opened"))).Should(BeNil())
+		})
+	})
+})
@@ -0,0 +1,314 @@
+package smpc
+
+import (
+	"crypto/rand"
+
+	"github.com/republicprotocol/go-do"
+	"github.com/republicprotocol/republic-go/compute"
+	"github.com/republicprotocol/republic-go/shamir"
+	"github.com/republicprotocol/republic-go/stackint"
+)
+
+// A ResidueGenerator generates Beaver triples together with the other
+// participants in a dark pool, without any participant learning the values of
+// the triple. Generation happens in two rounds.
+//
+// In the first round every participant contributes random values for A and B
+// by sending a RandomFragment to every other participant. The shares of A and
+// B are split with degree (n-1)/2, so that the product of a share of A and a
+// share of B is a share of a polynomial that can be reconstructed from n
+// shares.
+//
+// In the second round every participant splits the product of its shares of A
+// and B with degree k-1, and sends a ResidueFragment to every other
+// participant. The shares of C are interpolated from these ResidueFragments.
+// All n participants must take part in the second round.
+//
+// The shares of A and B have a lower degree than the k-1 used for orders, and
+// generation stops while any participant is offline. Both are deliberate. The
+// product of two shares of degree t is a share of degree 2t, which can only be
+// reduced by 2t+1 participants. Sharing A and B with degree k-1 would need
+// 2k-1 participants, which is more than n for k = 2n/3 + 1, so no
+// participant could ever multiply. Degree (n-1)/2 is the highest degree that
+// the n participants can multiply, and it is what bounds the privacy of the
+// secure comparisons. Tolerating n-k offline participants would need degree
+// (k-1)/2, which is roughly n/3. It would also need the participants to agree
+// on which of them contributed to each Beaver triple. Otherwise their shares
+// of A, B, and C lie on different polynomials.
+type ResidueGenerator struct {
+	do.GuardedObject
+
+	key   int64
+	n     int64
+	k     int64
+	prime *stackint.Int1024
+
+	randomFragments          map[string][]*compute.RandomFragment
+	randomFragmentsReceived  map[string]map[int64]*compute.RandomFragment
+	residueFragments         map[string][]*compute.ResidueFragment
+	residueFragmentsReceived map[string]map[int64]*compute.ResidueFragment
+	residues                 map[string]*compute.ResidueFragment
+}
+
+// NewResidueGenerator returns a ResidueGenerator for the participant with the
+// given key, in a computation with n participants where k shares are needed
+// to reconstruct a secret.
+func NewResidueGenerator(key, n, k int64, prime *stackint.Int1024) *ResidueGenerator {
+	return &ResidueGenerator{
+		GuardedObject:            do.NewGuardedObject(),
+		key:                      key,
+		n:                        n,
+		k:                        k,
+		prime:                    prime,
+		randomFragments:          map[string][]*compute.RandomFragment{},
+		randomFragmentsReceived:  map[string]map[int64]*compute.RandomFragment{},
+		residueFragments:         map[string][]*compute.ResidueFragment{},
+		residueFragmentsReceived: map[string]map[int64]*compute.ResidueFragment{},
+		residues:                 map[string]*compute.ResidueFragment{},
+	}
+}
+
+// RandomFragment returns the RandomFragment that this participant contributes
+// to the participant with the given key. Random values are generated the first
+// time that a ResidueID is seen.
+func (generator *ResidueGenerator) RandomFragment(residueID compute.ResidueID, to int64) (*compute.RandomFragment, error) {
+	generator.Enter(nil)
+	defer generator.Exit()
+	return generator.randomFragment(residueID, to)
+}
+
+func (generator *ResidueGenerator) randomFragment(residueID compute.ResidueID, to int64) (*compute.RandomFragment, error) {
+	if to < 1 || to > generator.n {
+		return nil, ErrUnknownParticipant
+	}
+	if randomFragments, ok := generator.randomFragments[string(residueID)]; ok {
+		return randomFragments[to-1], nil
+	}
+
+	one := stackint.One()
+	max := generator.prime.Sub(&one)
+	a, err := stackint.Random(rand.Reader, &max)
+	if err != nil {
+		return nil, err
+	}
+	b, err := stackint.Random(rand.Reader, &max)
+	if err != nil {
+		return nil, err
+	}
+	aShares, err := shamir.Split(generator.n, (generator.n-1)/2+1, generator.prime, &a)
+	if err != nil {
+		return nil, err
+	}
+	bShares, err := shamir.Split(generator.n, (generator.n-1)/2+1, generator.prime, &b)
+	if err != nil {
+		return nil, err
+	}
+
+	randomFragments := make([]*compute.RandomFragment, generator.n)
+	for i := range randomFragments {
+		randomFragments[i] = &compute.RandomFragment{
+			ResidueID: residueID,
+			AShare:    aShares[i],
+			BShare:    bShares[i],
+		}
+	}
+	generator.randomFragments[string(residueID)] = randomFragments
+	return randomFragments[to-1], nil
+}
+
+// InsertRandomFragment inserts a RandomFragment received from the participant
+// with the given key. When RandomFragments have been received from all other
+// participants, the ResidueFragments that must be sent to each participant
+// are returned, indexed by key minus one. The ResidueFragment for this
+// participant is nil. Otherwise, nil is returned.
+func (generator *ResidueGenerator) InsertRandomFragment(from int64, randomFragment *compute.RandomFragment) ([]*compute.ResidueFragment, error) {
+	generator.Enter(nil)
+	defer generator.Exit()
+	return generator.insertRandomFragment(from, randomFragment)
+}
+
+func (generator *ResidueGenerator) insertRandomFragment(from int64, randomFragment *compute.RandomFragment) ([]*compute.ResidueFragment, error) {
+	if from < 1 || from > generator.n || from == generator.key {
+		return nil, ErrUnknownParticipant
+	}
+	if randomFragment.AShare.Key != generator.key || randomFragment.BShare.Key != generator.key {
+		return nil, ErrUnexpectedShareKey
+	}
+	residueID := string(randomFragment.ResidueID)
+	if _, ok := generator.residueFragments[residueID]; ok {
+		return nil, nil
+	}
+	if _, ok := generator.randomFragmentsReceived[residueID]; !ok {
+		generator.randomFragmentsReceived[residueID] = map[int64]*compute.RandomFragment{}
+	}
+	generator.randomFragmentsReceived[residueID][from] = randomFragment
+	if int64(len(generator.randomFragmentsReceived[residueID])) < generator.n-1 {
+		return nil, nil
+	}
+
+	// Sum the random values from all participants, including this one, to
+	// get shares of A and B
+	own, err := generator.randomFragment(randomFragment.ResidueID, generator.key)
+	if err != nil {
+		return nil, err
+	}
+	a := own.AShare.Value.Clone()
+	b := own.BShare.Value.Clone()
+	for _, received := range generator.randomFragmentsReceived[residueID] {
+		a = a.AddModulo(&received.AShare.Value, generator.prime)
+		b = b.AddModulo(&received.BShare.Value, generator.prime)
+	}
+	delete(generator.randomFragmentsReceived, residueID)
+
+	// Split the product of the shares so that it can be reduced to a share of
+	// degree k-1
+	product := a.MulModulo(&b, generator.prime)
+	productShares, err := shamir.Split(generator.n, generator.k, generator.prime, &product)
+	if err != nil {
+		return nil, err
+	}
+	residueFragments := make([]*compute.ResidueFragment, generator.n)
+	for i := range residueFragments {
+		residueFragments[i] = &compute.ResidueFragment{
+			ResidueID:    randomFragment.ResidueID,
+			AShare:       shamir.Share{Key: int64(i + 1), Value: stackint.Zero()},
+			BShare:       shamir.Share{Key: int64(i + 1), Value: stackint.Zero()},
+			CShare:       shamir.Share{Key: int64(i + 1), Value: stackint.Zero()},
+			ResidueShare: productShares[i],
+		}
+	}
+	generator.residueFragments[residueID] = residueFragments
+
+	// Shares of A and B are only kept by this participant
+	residueFragments[generator.key-1].AShare = shamir.Share{Key: generator.key, Value: a}
+	residueFragments[generator.key-1].BShare = shamir.Share{Key: generator.key, Value: b}
+
+	// ResidueFragments from other participants might have arrived before this
+	// participant finished the first round
+	generator.buildResidue(randomFragment.ResidueID)
+
+	residueFragmentsToSend := make([]*compute.ResidueFragment, generator.n)
+	for i := int64(1); i <= generator.n; i++ {
+		residueFragmentsToSend[i-1] = generator.residueFragment(randomFragment.ResidueID, i)
+	}
+	return residueFragmentsToSend, nil
+}
+
+// ResidueFragment returns the ResidueFragment that this participant sends to
+// the participant with the given key. It returns nil if this participant has
+// not received all RandomFragments for the ResidueID.
+func (generator *ResidueGenerator) ResidueFragment(residueID compute.ResidueID, to int64) *compute.ResidueFragment {
+	generator.EnterReadOnly(nil)
+	defer generator.ExitReadOnly()
+	return generator.residueFragment(residueID, to)
+}
+
+func (generator *ResidueGenerator) residueFragment(residueID compute.ResidueID, to int64) *compute.ResidueFragment {
+	// The ResidueFragment kept by this participant holds its shares of A and
+	// B, and must never be sent
+	if to < 1 || to > generator.n || to == generator.key {
+		return nil
+	}
+	residueFragments, ok := generator.residueFragments[string(residueID)]
+	if !ok {
+		return nil
+	}
+	return residueFragments[to-1]
+}
+
+// InsertResidueFragment inserts a ResidueFragment received from the
+// participant with the given key. When ResidueFragments have been received
+// from all participants, the shares of the Beaver triple are returned.
+// Otherwise, nil is returned.
+func (generator *ResidueGenerator) InsertResidueFragment(from int64, residueFragment *compute.ResidueFragment) (*compute.ResidueFragment, error) {
+	generator.Enter(nil)
+	defer generator.Exit()
+	return generator.insertResidueFragment(from, residueFragment)
+}
+
+func (generator *ResidueGenerator) insertResidueFragment(from int64, residueFragment *compute.ResidueFragment) (*compute.ResidueFragment, error) {
+	if from < 1 || from > generator.n || from == generator.key {
+		return nil, ErrUnknownParticipant
+	}
+	if residueFragment.ResidueShare.Key != generator.key {
+		return nil, ErrUnexpectedShareKey
+	}
+	residueID := string(residueFragment.ResidueID)
+	if _, ok := generator.residues[residueID]; ok {
+		return nil, nil
+	}
+	if _, ok := generator.residueFragmentsReceived[residueID]; !ok {
+		generator.residueFragmentsReceived[residueID] = map[int64]*compute.ResidueFragment{}
+	}
+	generator.residueFragmentsReceived[residueID][from] = residueFragment
+	return generator.buildResidue(residueFragment.ResidueID), nil
+}
+
+func (generator *ResidueGenerator) buildResidue(residueID compute.ResidueID) *compute.ResidueFragment {
+	residueFragments, ok := generator.residueFragments[string(residueID)]
+	if !ok {
+		return nil
+	}
+	own := residueFragments[generator.key-1]
+	received := generator.residueFragmentsReceived[string(residueID)]
+	if int64(len(received)) < generator.n-1 {
+		return nil
+	}
+
+	// The shares of the product from each participant are interpolated as if
+	// they were the shares of a secret, reducing the product to a share of
+	// degree k-1
+	productShares := make(shamir.Shares, 0, generator.n)
+	productShares = append(productShares, shamir.Share{Key: generator.key, Value: own.ResidueShare.Value})
+	for from, residueFragment := range received {
+		productShares = append(productShares, shamir.Share{Key: from, Value: residueFragment.ResidueShare.Value})
+	}
+	c := shamir.Join(generator.prime, productShares)
+
+	residue := &compute.ResidueFragment{
+		ResidueID: residueID,
+		AShare:    own.AShare,
+		BShare:    own.BShare,
+		CShare:    shamir.Share{Key: generator.key, Value: *c},
+	}
+	generator.residues[string(residueID)] = residue
+	delete(generator.residueFragmentsReceived, string(residueID))
+	return residue
+}
+
+// Residue returns the shares of the Beaver triple with the given ResidueID, or
+// nil if the Beaver triple has not been generated.
+func (generator *ResidueGenerator) Residue(residueID compute.ResidueID) *compute.ResidueFragment {
+	generator.EnterReadOnly(nil)
+	defer generator.ExitReadOnly()
+	return generator.residues[string(residueID)]
+}
+
+// RemoveResidue removes all state for the given ResidueID. Beaver triples
+// must be removed after they are consumed.
+func (generator *ResidueGenerator) RemoveResidue(residueID compute.ResidueID) {
+	generator.Enter(nil)
+	defer generator.Exit()
+	delete(generator.randomFragments, string(residueID))
+	delete(generator.randomFragmentsReceived, string(residueID))
+	delete(generator.residueFragments, string(residueID))
+	delete(generator.residueFragmentsReceived, string(residueID))
+	delete(generator.residues, string(residueID))
+}
+
+// SetParticipants updates the key of this participant, the number of
+// participants, and the number of shares needed to reconstruct a secret. All
+// Beaver triples are discarded because they were generated with a different
+// set of participants.
+func (generator *ResidueGenerator) SetParticipants(key, n, k int64) {
+	generator.Enter(nil)
+	defer generator.Exit()
+	generator.key = key
+	generator.n = n
+	generator.k = k
+	generator.randomFragments = map[string][]*compute.RandomFragment{}
+	generator.randomFragmentsReceived = map[string]map[int64]*compute.RandomFragment{}
+	generator.residueFragments = map[string][]*compute.ResidueFragment{}
+	generator.residueFragmentsReceived = map[string]map[int64]*compute.ResidueFragment{}
+	generator.residues = map[string]*compute.ResidueFragment{}
+}
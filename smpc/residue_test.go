@@ -0,0 +1,127 @@
+package smpc_test
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	. "github.com/republicprotocol/republic-go/smpc"
+
+	"github.com/republicprotocol/republic-go/compute"
+	"github.com/republicprotocol/republic-go/shamir"
+	"github.com/republicprotocol/republic-go/stackint"
+)
+
+var primeVal, _ = stackint.FromString("179769313486231590772930519078902473361797697894230657273430081157732675805500963132708477322407536021120113879871393357658789768814416622492847430639474124377767893424865485276302219601246094119453082952085005768838150682342462881473913110540827237163350510684586298239947245938479716304835356329624224137111")
+var prime = &primeVal
+
+var _ = Describe("Residue generators", func() {
+
+	n := int64(5)
+	k := int64(4)
+
+	Context("when generating Beaver triples", func() {
+
+		It("should generate shares of C that are the product of A and B", func() {
+			residueID := compute.ResidueID("residue")
+			residues := generateResidues(n, k, residueID)
+
+			aShares := make(shamir.Shares, n)
+			bShares := make(shamir.Shares, n)
+			cShares := make(shamir.Shares, n)
+			for i := range residues {
+				Ω(residues[i]).ShouldNot(BeNil())
+				aShares[i] = residues[i].AShare
+				bShares[i] = residues[i].BShare
+				cShares[i] = residues[i].CShare
+			}
+			a := shamir.Join(prime, aShares)
+			b := shamir.Join(prime, bShares)
+			ab := a.MulModulo(b, prime)
+			Ω(shamir.Join(prime, cShares[:k]).Cmp(&ab)).Should(Equal(0))
+			Ω(shamir.Join(prime, cShares[1:]).Cmp(&ab)).Should(Equal(0))
+		})
+
+		It("should not send the shares of A and B to other participants", func() {
+			generators := make([]*ResidueGenerator, n)
+			for i := range generators {
+				generators[i] = NewResidueGenerator(int64(i+1), n, k, prime)
+			}
+			residueID := compute.ResidueID("residue")
+			var residueFragments []*compute.ResidueFragment
+			for j := int64(2); j <= n; j++ {
+				randomFragment, err := generators[j-1].RandomFragment(residueID, 1)
+				Ω(err).ShouldNot(HaveOccurred())
+				residueFragments, err = generators[0].InsertRandomFragment(j, randomFragment)
+				Ω(err).ShouldNot(HaveOccurred())
+			}
+			Ω(residueFragments).Should(HaveLen(int(n)))
+			Ω(residueFragments[0]).Should(BeNil())
+			for _, residueFragment := range residueFragments[1:] {
+				Ω(residueFragment.AShare.Value.IsZero()).Should(BeTrue())
+				Ω(residueFragment.BShare.Value.IsZero()).Should(BeTrue())
+			}
+			Ω(generators[0].ResidueFragment(residueID, 1)).Should(BeNil())
+		})
+
+		It("should return an error for unknown participants", func() {
+			generator := NewResidueGenerator(1, n, k, prime)
+			_, err := generator.RandomFragment(compute.ResidueID("residue"), n+1)
+			Ω(err).Should(Equal(ErrUnknownParticipant))
+
+			other := NewResidueGenerator(2, n, k, prime)
+			randomFragment, err := other.RandomFragment(compute.ResidueID("residue"), 1)
+			Ω(err).ShouldNot(HaveOccurred())
+			_, err = generator.InsertRandomFragment(1, randomFragment)
+			Ω(err).Should(Equal(ErrUnknownParticipant))
+		})
+
+		It("should return an error for shares meant for another participant", func() {
+			generator := NewResidueGenerator(1, n, k, prime)
+			other := NewResidueGenerator(2, n, k, prime)
+			randomFragment, err := other.RandomFragment(compute.ResidueID("residue"), 3)
+			Ω(err).ShouldNot(HaveOccurred())
+			_, err = generator.InsertRandomFragment(2, randomFragment)
+			Ω(err).Should(Equal(ErrUnexpectedShareKey))
+		})
+	})
+})
+
+// generateResidues runs the generation of a Beaver triple between n
+// participants and returns the shares held by each participant.
+func generateResidues(n, k int64, residueID compute.ResidueID) []*compute.ResidueFragment {
+	generators := make([]*ResidueGenerator, n)
+	for i := range generators {
+		generators[i] = NewResidueGenerator(int64(i+1), n, k, prime)
+	}
+
+	residueFragments := make([][]*compute.ResidueFragment, n)
+	for i := int64(1); i <= n; i++ {
+		for j := int64(1); j <= n; j++ {
+			if i == j {
+				continue
+			}
+			randomFragment, err := generators[j-1].RandomFragment(residueID, i)
+			Ω(err).ShouldNot(HaveOccurred())
+			toSend, err := generators[i-1].InsertRandomFragment(j, randomFragment)
+			Ω(err).ShouldNot(HaveOccurred())
+			if toSend != nil {
+				residueFragments[i-1] = toSend
+			}
+		}
+	}
+
+	residues := make([]*compute.ResidueFragment, n)
+	for i := int64(1); i <= n; i++ {
+		for j := int64(1); j <= n; j++ {
+			if i == j {
+				continue
+			}
+			residue, err := generators[i-1].InsertResidueFragment(j, residueFragments[j-1][i-1])
+			Ω(err).ShouldNot(HaveOccurred())
+			if residue != nil {
+				residues[i-1] = residue
+			}
+		}
+		Ω(generators[i-1].Residue(residueID)).Should(Equal(residues[i-1]))
+	}
+	return residues
+}
@@ -1,4 +1,20 @@
+// Package smpc implements the secure multi-party computations that are run by
+// the dark nodes in a dark pool. Participants are identified by the key of
+// the shamir.Shares that they hold, starting from 1.
 package smpc
 
-// type Participants struct {
-// }
+import (
+	"errors"
+)
+
+// ErrUnknownParticipant is returned when a fragment is received from a
+// participant that is not part of the computation.
+var ErrUnknownParticipant = errors.New("unknown participant")
+
+// ErrUnexpectedShareKey is returned when a fragment holds shares that are not
+// meant for this participant.
+var ErrUnexpectedShareKey = errors.New("unexpected share key")
+
+// ErrRandomBitGeneration is returned when a shared random bit cannot be
+// generated because the random value that was squared is zero.
+var ErrRandomBitGeneration = errors.New("cannot generate random bit")
@@ -0,0 +1,13 @@
+package smpc_test
+
+import (
+	"testing"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+func TestSmpc(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "Smpc Suite")
+}
@@ -354,3 +354,16 @@ func (x *Int1024) Exp(y *Int1024) Int1024 {
 	power := square.Exp(&half)
 	return x.Mul(&power)
 }
+
+// ExpModulo returns x**y mod n
+func (x *Int1024) ExpModulo(y, n *Int1024) Int1024 {
+	base := x.Mod(n)
+	result := One()
+	for i := y.BitLength() - 1; i >= 0; i-- {
+		result = result.MulModulo(&result, n)
+		if y.IsBitSet(i) {
+			result = result.MulModulo(&base, n)
+		}
+	}
+	return result.Mod(n)
+}
@@ -19,6 +19,7 @@ var modFn = func(inputs ...Int1024) Int1024 { return inputs[0].Mod(&inputs[1]) }
 var submodFn = func(inputs ...Int1024) Int1024 { return inputs[0].SubModulo(&inputs[1], &inputs[2]) }
 var addmodFn = func(inputs ...Int1024) Int1024 { return inputs[0].AddModulo(&inputs[1], &inputs[2]) }
 var mulmodFn = func(inputs ...Int1024) Int1024 { return inputs[0].MulModulo(&inputs[1], &inputs[2]) }
+var expmodFn = func(inputs ...Int1024) Int1024 { return inputs[0].ExpModulo(&inputs[1], &inputs[2]) }
 
 var _ = Describe("Int1024 arithmetic", func() {
 
@@ -258,6 +259,33 @@ var _ = Describe("Int1024 arithmetic", func() {
 			//
 		})
 	})
+
+	Context("when raising powers with modulo", func() {
+		It("should return the right result for 1024 bit numbers", func() {
+			prime := "179769313486231590772930519078902473361797697894230657273430081157732675805500963132708477322407536021120113879871393357658789768814416622492847430639474124377767893424865485276302219601246094119453082952085005768838150682342462881473913110540827237163350510684586298239947245938479716304835356329624224137111"
+			primeSubOne := "179769313486231590772930519078902473361797697894230657273430081157732675805500963132708477322407536021120113879871393357658789768814416622492847430639474124377767893424865485276302219601246094119453082952085005768838150682342462881473913110540827237163350510684586298239947245938479716304835356329624224137110"
+
+			RunAllCases(expmodFn, []TestCase{
+				TestCase{inputsStr: []string{"2", "0", "7"}, expectedStr: "1"},
+				TestCase{inputsStr: []string{"0", "0", "7"}, expectedStr: "1"},
+				TestCase{inputsStr: []string{"3", "3", "7"}, expectedStr: "6"},
+				TestCase{inputsStr: []string{"10", "3", "7"}, expectedStr: "6"},
+				TestCase{inputsStr: []string{"2", "100", "1000000007"}, expectedStr: "976371285"},
+				// Fermat's little theorem
+				TestCase{inputsStr: []string{"123456789", primeSubOne, prime}, expectedStr: "1"},
+				TestCase{inputsStr: []string{primeSubOne, "2", prime}, expectedStr: "1"},
+			})
+		})
+
+		It("should match math/big", func() {
+			prime, _ := big.NewInt(0).SetString("179769313486231590772930519078902473361797697894230657273430081157732675805500963132708477322407536021120113879871393357658789768814416622492847430639474124377767893424865485276302219601246094119453082952085005768838150682342462881473913110540827237163350510684586298239947245938479716304835356329624224137111", 10)
+			base, _ := big.NewInt(0).SetString("98765432109876543210987654321098765432109876543210", 10)
+			exp, _ := big.NewInt(0).SetString("44942328371557897693232629769725618340449424473557664318357520289433168951375240783177119330601884005280028469967848339414697442203604155623211857659868531094441973356216371319075554900311523529863270738021251442209537670585615720368478277635206809290837627671146574559986811484619929076208839082406056034278", 10)
+			expected := big.NewInt(0).Exp(base, exp, prime)
+
+			RunCase(expmodFn, TestCase{inputsStr: []string{base.String(), exp.String(), prime.String()}, expectedStr: expected.String()})
+		})
+	})
 })
 
 type BinaryFn func(inputs ...Int1024) Int1024
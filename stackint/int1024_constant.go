@@ -0,0 +1,194 @@
+package stackint
+
+import "github.com/republicprotocol/republic-go/stackint/asm"
+
+// The functions in this file run in constant time with respect to the values
+// of their inputs, so that they can be used on secret values such as the
+// shares of an order. They always operate on every word of an Int1024, do not
+// branch on values, and only use the assembly routines of the asm package,
+// which do not branch on values either. The lengths of the results are
+// computed without branching, but the lengths of the inputs are not used.
+
+// CtSelect returns x if c is 1, and y if c is 0, in constant time.
+func CtSelect(c uint, x, y *Int1024) Int1024 {
+	xWords := x.ctWords()
+	yWords := y.ctWords()
+	return ctFromWords(ctSelectWords(asm.Word(c&1), &xWords, &yWords))
+}
+
+// CtEquals returns 1 if x is equal to y, and 0 otherwise, in constant time.
+func (x *Int1024) CtEquals(y *Int1024) uint {
+	xWords := x.ctWords()
+	yWords := y.ctWords()
+	var diff asm.Word
+	for i := 0; i < INT1024WORDS; i++ {
+		diff |= xWords[i] ^ yWords[i]
+	}
+	return uint(1 ^ ctIsNonZero(diff))
+}
+
+// CtLessThan returns 1 if x is less than y, and 0 otherwise, in constant time.
+func (x *Int1024) CtLessThan(y *Int1024) uint {
+	xWords := x.ctWords()
+	yWords := y.ctWords()
+	var diff [INT1024WORDS]asm.Word
+	return uint(asm.SubVV(diff[:], xWords[:], yWords[:]))
+}
+
+// CtAddModulo returns (x+y) % n in constant time. The inputs must be less
+// than 2n, which is true for all Int1024s when n is larger than 2^1023.
+func (x *Int1024) CtAddModulo(y, n *Int1024) Int1024 {
+	nWords := n.ctWords()
+	a := ctReduceOnce(x.ctWords(), &nWords)
+	b := ctReduceOnce(y.ctWords(), &nWords)
+
+	var sum, diff [INT1024WORDS]asm.Word
+	carry := asm.AddVV(sum[:], a[:], b[:])
+	borrow := asm.SubVV(diff[:], sum[:], nWords[:])
+
+	// The sum is reduced if it overflowed, or if it is not less than n
+	return ctFromWords(ctSelectWords(carry|(1^borrow), &diff, &sum))
+}
+
+// CtSubModulo returns (x-y) % n in constant time. The inputs must be less
+// than 2n, which is true for all Int1024s when n is larger than 2^1023.
+func (x *Int1024) CtSubModulo(y, n *Int1024) Int1024 {
+	nWords := n.ctWords()
+	a := ctReduceOnce(x.ctWords(), &nWords)
+	b := ctReduceOnce(y.ctWords(), &nWords)
+
+	var diff, correction [INT1024WORDS]asm.Word
+	borrow := asm.SubVV(diff[:], a[:], b[:])
+
+	// Add n back if the difference was negative
+	mask := -borrow
+	for i := 0; i < INT1024WORDS; i++ {
+		correction[i] = nWords[i] & mask
+	}
+	asm.AddVV(diff[:], diff[:], correction[:])
+	return ctFromWords(diff)
+}
+
+// CtToMont converts x into the Montgomery domain in constant time. X must be
+// less than 2^1024.
+func (mont *Montgomery) CtToMont(x *Int1024) Int1024 {
+	return mont.CtMulMont(x, &mont.r2)
+}
+
+// CtFromMont converts x out of the Montgomery domain in constant time.
+func (mont *Montgomery) CtFromMont(x *Int1024) Int1024 {
+	one := One()
+	return mont.CtMulMont(x, &one)
+}
+
+// CtMulMont returns x*y*R^-1 mod m in constant time. The product of x and y
+// must be less than m*R, which is true when one of them is less than m.
+func (mont *Montgomery) CtMulMont(x, y *Int1024) Int1024 {
+	xWords := x.ctWords()
+	yWords := y.ctWords()
+
+	// Compute the full product of x and y
+	var t [2*INT1024WORDS + 1]asm.Word
+	for i := 0; i < INT1024WORDS; i++ {
+		t[i+INT1024WORDS] = asm.AddMulVVW(t[i:i+INT1024WORDS], xWords[:], yWords[i])
+	}
+
+	// Reduce the product by adding multiples of m until the lowest words are
+	// zero, and then drop the lowest words
+	for i := 0; i < INT1024WORDS; i++ {
+		u := t[i] * mont.mInv
+		c := asm.AddMulVVW(t[i:i+INT1024WORDS], mont.m.words[:], u)
+		t[2*INT1024WORDS] += asm.AddVW(t[i+INT1024WORDS:2*INT1024WORDS], t[i+INT1024WORDS:2*INT1024WORDS], c)
+	}
+
+	// The result is less than 2m, and may overflow 1024 bits
+	var z, diff [INT1024WORDS]asm.Word
+	copy(z[:], t[INT1024WORDS:2*INT1024WORDS])
+	borrow := asm.SubVV(diff[:], z[:], mont.m.words[:])
+	return ctFromWords(ctSelectWords(t[2*INT1024WORDS]|(1^borrow), &diff, &z))
+}
+
+// CtMulModulo returns x*y mod m in constant time, where x and y are not in the
+// Montgomery domain. The inputs must be less than 2m.
+func (mont *Montgomery) CtMulModulo(x, y *Int1024) Int1024 {
+	a := ctFromWords(ctReduceOnce(x.ctWords(), &mont.m.words))
+	xy := mont.CtMulMont(&a, y)
+	return mont.CtMulMont(&xy, &mont.r2)
+}
+
+// CtExpModulo returns x^e mod m, where x is not in the Montgomery domain. The
+// running time does not depend on x, but it does depend on e, which must not
+// be secret.
+func (mont *Montgomery) CtExpModulo(x, e *Int1024) Int1024 {
+	X := mont.CtToMont(x)
+	result := mont.r.Clone()
+	for i := e.BitLength() - 1; i >= 0; i-- {
+		result = mont.CtMulMont(&result, &result)
+		if e.IsBitSet(i) {
+			result = mont.CtMulMont(&result, &X)
+		}
+	}
+	return mont.CtFromMont(&result)
+}
+
+// CtModInverse returns the inverse of x modulo m in constant time, using
+// Fermat's little theorem. The modulus must be prime. The inverse of zero is
+// zero.
+func (mont *Montgomery) CtModInverse(x *Int1024) Int1024 {
+	two := Two()
+	e := mont.m.Sub(&two)
+	return mont.CtExpModulo(x, &e)
+}
+
+// ctWords returns the words of x, with the words above its length set to zero
+// without branching.
+func (x *Int1024) ctWords() [INT1024WORDS]asm.Word {
+	var words [INT1024WORDS]asm.Word
+	length := asm.Word(x.length)
+	for i := 0; i < INT1024WORDS; i++ {
+		// The mask is all ones when i < length
+		mask := -((asm.Word(i) - length) >> (WORDSIZE - 1))
+		words[i] = x.words[i] & mask
+	}
+	return words
+}
+
+// ctFromWords returns the Int1024 with the given words, computing its length
+// without branching.
+func ctFromWords(words [INT1024WORDS]asm.Word) Int1024 {
+	length := asm.Word(1)
+	for i := 0; i < INT1024WORDS; i++ {
+		length = ctSelectWord(ctIsNonZero(words[i]), asm.Word(i+1), length)
+	}
+	return Int1024{
+		words:  words,
+		length: uint16(length),
+	}
+}
+
+// ctReduceOnce returns x-n if x is not less than n, and x otherwise.
+func ctReduceOnce(x [INT1024WORDS]asm.Word, n *[INT1024WORDS]asm.Word) [INT1024WORDS]asm.Word {
+	var diff [INT1024WORDS]asm.Word
+	borrow := asm.SubVV(diff[:], x[:], n[:])
+	return ctSelectWords(1^borrow, &diff, &x)
+}
+
+// ctSelectWords returns x if c is 1, and y if c is 0.
+func ctSelectWords(c asm.Word, x, y *[INT1024WORDS]asm.Word) [INT1024WORDS]asm.Word {
+	var z [INT1024WORDS]asm.Word
+	mask := -c
+	for i := 0; i < INT1024WORDS; i++ {
+		z[i] = y[i] ^ (mask & (x[i] ^ y[i]))
+	}
+	return z
+}
+
+// ctSelectWord returns x if c is 1, and y if c is 0.
+func ctSelectWord(c, x, y asm.Word) asm.Word {
+	return y ^ (-c & (x ^ y))
+}
+
+// ctIsNonZero returns 1 if w is not zero, and 0 otherwise.
+func ctIsNonZero(w asm.Word) asm.Word {
+	return (w | -w) >> (WORDSIZE - 1)
+}
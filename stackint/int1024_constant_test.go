@@ -0,0 +1,188 @@
+package stackint_test
+
+import (
+	"crypto/rand"
+	"math"
+	"math/big"
+	mathRand "math/rand"
+	"runtime"
+	"sort"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	. "github.com/republicprotocol/republic-go/stackint"
+)
+
+var _ = Describe("Constant-time arithmetic", func() {
+
+	prime, _ := FromString(montgomeryModuli[0])
+	bigPrime := prime.ToBigInt()
+	mont, _ := NewMontgomery(&prime)
+
+	randomInt := func(max *big.Int) Int1024 {
+		n, err := rand.Int(rand.Reader, max)
+		Ω(err).ShouldNot(HaveOccurred())
+		x, err := FromBigInt(n)
+		Ω(err).ShouldNot(HaveOccurred())
+		return x
+	}
+
+	Context("when selecting and comparing", func() {
+
+		It("should select the right value", func() {
+			x := FromUint(1)
+			y, _ := FromString("18446744073709551617")
+			xSelected := CtSelect(1, &x, &y)
+			ySelected := CtSelect(0, &x, &y)
+			Ω(xSelected.Equals(&x)).Should(BeTrue())
+			Ω(ySelected.Equals(&y)).Should(BeTrue())
+		})
+
+		It("should compare values", func() {
+			for i := 0; i < 100; i++ {
+				x := randomInt(bigPrime)
+				y := randomInt(bigPrime)
+				Ω(x.CtEquals(&x)).Should(Equal(uint(1)))
+				Ω(x.CtEquals(&y) == 1).Should(Equal(x.Equals(&y)))
+				Ω(x.CtLessThan(&y) == 1).Should(Equal(x.LessThan(&y)))
+				Ω(x.CtLessThan(&x)).Should(Equal(uint(0)))
+			}
+		})
+	})
+
+	Context("when doing modular arithmetic", func() {
+
+		It("should add and subtract values", func() {
+			for i := 0; i < 100; i++ {
+				x := randomInt(bigPrime)
+				y := randomInt(bigPrime)
+				sum := x.CtAddModulo(&y, &prime)
+				diff := x.CtSubModulo(&y, &prime)
+				Ω(sum.Equals(&x)).Should(Equal(y.IsZero()))
+				expectedSum := x.AddModulo(&y, &prime)
+				expectedDiff := x.SubModulo(&y, &prime)
+				Ω(sum.Cmp(&expectedSum)).Should(Equal(0))
+				Ω(diff.Cmp(&expectedDiff)).Should(Equal(0))
+			}
+		})
+
+		It("should reduce values that are not in the field", func() {
+			max, _ := FromString(montgomeryModuli[1])
+			one := One()
+			sum := max.CtAddModulo(&one, &prime)
+			expected := new(big.Int).Add(max.ToBigInt(), big.NewInt(1))
+			expected.Mod(expected, bigPrime)
+			Ω(sum.ToBigInt().Cmp(expected)).Should(Equal(0))
+		})
+
+		It("should multiply values", func() {
+			for i := 0; i < 100; i++ {
+				x := randomInt(bigPrime)
+				y := randomInt(bigPrime)
+				product := mont.CtMulModulo(&x, &y)
+				expected := new(big.Int).Mul(x.ToBigInt(), y.ToBigInt())
+				expected.Mod(expected, bigPrime)
+				Ω(product.ToBigInt().Cmp(expected)).Should(Equal(0))
+			}
+		})
+
+		It("should invert values", func() {
+			for i := 0; i < 5; i++ {
+				x := randomInt(bigPrime)
+				inverse := mont.CtModInverse(&x)
+				expected := new(big.Int).ModInverse(x.ToBigInt(), bigPrime)
+				Ω(inverse.ToBigInt().Cmp(expected)).Should(Equal(0))
+			}
+			zero := Zero()
+			inverse := mont.CtModInverse(&zero)
+			Ω(inverse.IsZero()).Should(BeTrue())
+		})
+	})
+
+	Context("when measuring the running time", func() {
+
+		// The running time for a fixed input of zero, which is the fastest
+		// input for arithmetic that skips zero words, is compared with the
+		// running time for random inputs. A t-statistic above 10 is strong
+		// evidence of a timing leak.
+		threshold := 10.0
+		one := One()
+
+		It("should not leak the value of an addition", func() {
+			y := randomInt(bigPrime)
+			t := dudect(func() Int1024 { return Zero() }, func() Int1024 { return randomInt(bigPrime) }, func(x *Int1024) {
+				x.CtAddModulo(&y, &prime)
+			})
+			Ω(math.Abs(t)).Should(BeNumerically("<", threshold))
+		})
+
+		It("should not leak the value of a subtraction", func() {
+			y := randomInt(bigPrime)
+			t := dudect(func() Int1024 { return one }, func() Int1024 { return randomInt(bigPrime) }, func(x *Int1024) {
+				x.CtSubModulo(&y, &prime)
+			})
+			Ω(math.Abs(t)).Should(BeNumerically("<", threshold))
+		})
+
+		It("should not leak the value of a multiplication", func() {
+			y := randomInt(bigPrime)
+			t := dudect(func() Int1024 { return Zero() }, func() Int1024 { return randomInt(bigPrime) }, func(x *Int1024) {
+				mont.CtMulModulo(x, &y)
+			})
+			Ω(math.Abs(t)).Should(BeNumerically("<", threshold))
+		})
+	})
+})
+
+// dudect measures the running time of fn for inputs from a fixed class and a
+// random class, interleaved in a random order, and returns Welch's
+// t-statistic for the difference between the two classes. Measurements above
+// the 90th percentile are discarded to remove noise from the scheduler and
+// the garbage collector.
+func dudect(fixed, random func() Int1024, fn func(x *Int1024)) float64 {
+	samples := 20000
+	batch := 8
+
+	classes := make([]int, samples)
+	inputs := make([]Int1024, samples)
+	for i := range inputs {
+		classes[i] = mathRand.Intn(2)
+		if classes[i] == 0 {
+			inputs[i] = fixed()
+		} else {
+			inputs[i] = random()
+		}
+	}
+
+	runtime.GC()
+	durations := make([]float64, samples)
+	for i := range inputs {
+		start := time.Now()
+		for j := 0; j < batch; j++ {
+			fn(&inputs[i])
+		}
+		durations[i] = float64(time.Since(start))
+	}
+
+	sorted := make([]float64, samples)
+	copy(sorted, durations)
+	sort.Float64s(sorted)
+	cutoff := sorted[samples*9/10]
+
+	var n, mean, m2 [2]float64
+	for i, duration := range durations {
+		if duration > cutoff {
+			continue
+		}
+		c := classes[i]
+		n[c]++
+		delta := duration - mean[c]
+		mean[c] += delta / n[c]
+		m2[c] += delta * (duration - mean[c])
+	}
+	variance0 := m2[0] / (n[0] - 1)
+	variance1 := m2[1] / (n[1] - 1)
+	return (mean[0] - mean[1]) / math.Sqrt(variance0/n[0]+variance1/n[1])
+}
@@ -0,0 +1,151 @@
+package stackint
+
+import (
+	"errors"
+	"math/big"
+
+	"github.com/republicprotocol/republic-go/stackint/asm"
+)
+
+// ErrEvenModulus is returned when a Montgomery context is created for a
+// modulus that is not odd.
+var ErrEvenModulus = errors.New("montgomery modulus must be odd")
+
+// A Montgomery context holds the values needed to do modular multiplication
+// against a fixed odd modulus, using Montgomery reduction instead of division.
+// Numbers in the Montgomery domain are represented as x*R mod m, where R is
+// 2^1024. The context is precomputed once and can be shared by all callers
+// that use the same modulus.
+type Montgomery struct {
+	m    Int1024
+	mInv asm.Word
+	r    Int1024
+	r2   Int1024
+}
+
+// NewMontgomery returns a Montgomery context for an odd modulus. An
+// ErrEvenModulus is returned if the modulus is even.
+func NewMontgomery(m *Int1024) (*Montgomery, error) {
+	if m.IsEven() {
+		return nil, ErrEvenModulus
+	}
+
+	// -m^-1 mod 2^64 is found using Newton's method, starting from m which is
+	// its own inverse modulo 8 and doubling the number of correct bits in
+	// each iteration.
+	inv := m.words[0]
+	for i := 0; i < 5; i++ {
+		inv *= 2 - m.words[0]*inv
+	}
+
+	// R mod m and R^2 mod m are only computed once, so math/big is used
+	// because R does not fit in an Int1024.
+	bigM := m.ToBigInt()
+	bigR := new(big.Int).Lsh(big.NewInt(1), SIZE)
+	r, err := FromBigInt(new(big.Int).Mod(bigR, bigM))
+	if err != nil {
+		return nil, err
+	}
+	r2, err := FromBigInt(new(big.Int).Mod(new(big.Int).Mul(bigR, bigR), bigM))
+	if err != nil {
+		return nil, err
+	}
+
+	return &Montgomery{
+		m:    m.Clone(),
+		mInv: -inv,
+		r:    r,
+		r2:   r2,
+	}, nil
+}
+
+// Modulus returns the modulus of the Montgomery context.
+func (mont *Montgomery) Modulus() Int1024 {
+	return mont.m.Clone()
+}
+
+// ToMont converts x into the Montgomery domain.
+func (mont *Montgomery) ToMont(x *Int1024) Int1024 {
+	reduced := mont.reduce(x)
+	return mont.MulMont(&reduced, &mont.r2)
+}
+
+// FromMont converts x out of the Montgomery domain.
+func (mont *Montgomery) FromMont(x *Int1024) Int1024 {
+	one := One()
+	return mont.MulMont(x, &one)
+}
+
+// OneMont returns 1 in the Montgomery domain.
+func (mont *Montgomery) OneMont() Int1024 {
+	return mont.r.Clone()
+}
+
+// MulMont returns x*y*R^-1 mod m. When x and y are in the Montgomery domain
+// the result is their product in the Montgomery domain. Both x and y must be
+// less than the modulus.
+func (mont *Montgomery) MulMont(x, y *Int1024) Int1024 {
+	// Compute the full product of x and y
+	var t [2*INT1024WORDS + 1]asm.Word
+	for i := uint16(0); i < y.length; i++ {
+		if y.words[i] == 0 {
+			continue
+		}
+		t[int(i)+int(x.length)] = asm.AddMulVVW(t[i:int(i)+int(x.length)], x.words[:x.length], y.words[i])
+	}
+
+	// Reduce the product by adding multiples of m until the lowest words are
+	// zero, and then drop the lowest words
+	for i := 0; i < INT1024WORDS; i++ {
+		u := t[i] * mont.mInv
+		c := asm.AddMulVVW(t[i:i+INT1024WORDS], mont.m.words[:], u)
+		t[2*INT1024WORDS] += asm.AddVW(t[i+INT1024WORDS:2*INT1024WORDS], t[i+INT1024WORDS:2*INT1024WORDS], c)
+	}
+
+	z := Int1024{}
+	copy(z.words[:], t[INT1024WORDS:2*INT1024WORDS])
+	z.setLength()
+
+	// The result is less than 2m, and may overflow 1024 bits
+	if t[2*INT1024WORDS] != 0 || z.GreaterThanOrEqual(&mont.m) {
+		asm.SubVV(z.words[:], z.words[:], mont.m.words[:])
+		z.setLength()
+	}
+	return z
+}
+
+// ExpMont returns x^e in the Montgomery domain, where x is in the Montgomery
+// domain and e is not.
+func (mont *Montgomery) ExpMont(x, e *Int1024) Int1024 {
+	result := mont.r.Clone()
+	for i := e.BitLength() - 1; i >= 0; i-- {
+		result = mont.MulMont(&result, &result)
+		if e.IsBitSet(i) {
+			result = mont.MulMont(&result, x)
+		}
+	}
+	return result
+}
+
+// MulModulo returns x*y mod m, where x and y are not in the Montgomery domain.
+func (mont *Montgomery) MulModulo(x, y *Int1024) Int1024 {
+	X := mont.reduce(x)
+	Y := mont.reduce(y)
+	XY := mont.MulMont(&X, &Y)
+	return mont.MulMont(&XY, &mont.r2)
+}
+
+// ExpModulo returns x^e mod m, where x is not in the Montgomery domain.
+func (mont *Montgomery) ExpModulo(x, e *Int1024) Int1024 {
+	X := mont.ToMont(x)
+	XE := mont.ExpMont(&X, e)
+	return mont.FromMont(&XE)
+}
+
+// reduce returns x mod m.
+func (mont *Montgomery) reduce(x *Int1024) Int1024 {
+	if x.LessThan(&mont.m) {
+		return x.Clone()
+	}
+	return x.Mod(&mont.m)
+}
@@ -0,0 +1,156 @@
+package stackint_test
+
+import (
+	"crypto/rand"
+	"fmt"
+	"math/big"
+	"testing"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	. "github.com/republicprotocol/republic-go/stackint"
+)
+
+var montgomeryModuli = []string{
+	// The prime used by the dark pool, 2^1024 - 105
+	"179769313486231590772930519078902473361797697894230657273430081157732675805500963132708477322407536021120113879871393357658789768814416622492847430639474124377767893424865485276302219601246094119453082952085005768838150682342462881473913110540827237163350510684586298239947245938479716304835356329624224137111",
+	// 2^1024 - 1
+	"179769313486231590772930519078902473361797697894230657273430081157732675805500963132708477322407536021120113879871393357658789768814416622492847430639474124377767893424865485276302219601246094119453082952085005768838150682342462881473913110540827237163350510684586298239947245938479716304835356329624224137215",
+	// 2^521 - 1
+	"6864797660130609714981900799081393217269435300143305409394463459185543183397656052122559640661454554977296311391480858037121987999716643812574028291115057151",
+	"18446744073709551629",
+	"7",
+}
+
+var _ = Describe("Montgomery arithmetic", func() {
+
+	for _, modulusStr := range montgomeryModuli {
+		modulusStr := modulusStr
+
+		Context(fmt.Sprintf("when the modulus has %v digits", len(modulusStr)), func() {
+
+			modulus, _ := FromString(modulusStr)
+			mont, _ := NewMontgomery(&modulus)
+			bigModulus := modulus.ToBigInt()
+
+			randomInt := func() (Int1024, *big.Int) {
+				n, err := rand.Int(rand.Reader, bigModulus)
+				Ω(err).ShouldNot(HaveOccurred())
+				x, err := FromBigInt(n)
+				Ω(err).ShouldNot(HaveOccurred())
+				return x, n
+			}
+
+			It("should convert to and from the Montgomery domain", func() {
+				for i := 0; i < 100; i++ {
+					x, _ := randomInt()
+					xMont := mont.ToMont(&x)
+					xFromMont := mont.FromMont(&xMont)
+					Ω(xFromMont.Cmp(&x)).Should(Equal(0))
+				}
+			})
+
+			It("should multiply numbers in the Montgomery domain", func() {
+				for i := 0; i < 100; i++ {
+					x, bigX := randomInt()
+					y, bigY := randomInt()
+					xMont := mont.ToMont(&x)
+					yMont := mont.ToMont(&y)
+					xyMont := mont.MulMont(&xMont, &yMont)
+					xy := mont.FromMont(&xyMont)
+
+					expected := new(big.Int).Mul(bigX, bigY)
+					expected.Mod(expected, bigModulus)
+					Ω(xy.ToBigInt().Cmp(expected)).Should(Equal(0))
+					xy = mont.MulModulo(&x, &y)
+					Ω(xy.ToBigInt().Cmp(expected)).Should(Equal(0))
+				}
+			})
+
+			It("should multiply the largest numbers in the field", func() {
+				one := One()
+				max := modulus.Sub(&one)
+				bigMax := max.ToBigInt()
+				expected := new(big.Int).Mul(bigMax, bigMax)
+				expected.Mod(expected, bigModulus)
+				maxSquared := mont.MulModulo(&max, &max)
+				Ω(maxSquared.ToBigInt().Cmp(expected)).Should(Equal(0))
+			})
+
+			It("should exponentiate numbers in the Montgomery domain", func() {
+				for i := 0; i < 10; i++ {
+					x, bigX := randomInt()
+					e, bigE := randomInt()
+					xMont := mont.ToMont(&x)
+					xeMont := mont.ExpMont(&xMont, &e)
+					xe := mont.FromMont(&xeMont)
+
+					expected := new(big.Int).Exp(bigX, bigE, bigModulus)
+					Ω(xe.ToBigInt().Cmp(expected)).Should(Equal(0))
+					xe = mont.ExpModulo(&x, &e)
+					Ω(xe.ToBigInt().Cmp(expected)).Should(Equal(0))
+				}
+			})
+
+			It("should reduce numbers that are not in the field", func() {
+				max, err := FromString("179769313486231590772930519078902473361797697894230657273430081157732675805500963132708477322407536021120113879871393357658789768814416622492847430639474124377767893424865485276302219601246094119453082952085005768838150682342462881473913110540827237163350510684586298239947245938479716304835356329624224137215")
+				Ω(err).ShouldNot(HaveOccurred())
+				two := Two()
+				expected := new(big.Int).Mul(max.ToBigInt(), big.NewInt(2))
+				expected.Mod(expected, bigModulus)
+				maxTimesTwo := mont.MulModulo(&max, &two)
+				Ω(maxTimesTwo.ToBigInt().Cmp(expected)).Should(Equal(0))
+			})
+		})
+	}
+
+	It("should return an error for even moduli", func() {
+		modulus := FromUint(1024)
+		_, err := NewMontgomery(&modulus)
+		Ω(err).Should(Equal(ErrEvenModulus))
+	})
+})
+
+func BenchmarkMulModulo(b *testing.B) {
+	x, y, prime := benchmarkOperands()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		x.MulModulo(&y, &prime)
+	}
+}
+
+func BenchmarkMulMont(b *testing.B) {
+	x, y, prime := benchmarkOperands()
+	mont, _ := NewMontgomery(&prime)
+	x, y = mont.ToMont(&x), mont.ToMont(&y)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		mont.MulMont(&x, &y)
+	}
+}
+
+func BenchmarkExpModulo(b *testing.B) {
+	x, y, prime := benchmarkOperands()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		x.ExpModulo(&y, &prime)
+	}
+}
+
+func BenchmarkExpMont(b *testing.B) {
+	x, y, prime := benchmarkOperands()
+	mont, _ := NewMontgomery(&prime)
+	x = mont.ToMont(&x)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		mont.ExpMont(&x, &y)
+	}
+}
+
+func benchmarkOperands() (Int1024, Int1024, Int1024) {
+	prime, _ := FromString(montgomeryModuli[0])
+	x, _ := FromString("123456789012345678901234567890123456789012345678901234567890123456789012345678901234567890123456789012345678901234567890123456789012345678901234567890123456789012345678901234567890123456789012345678901234567890123456789012345678901234567890123456789012345678901234567890123456789012345678901234567890")
+	y := prime.Sub(&x)
+	return x, y, prime
+}
@@ -0,0 +1,313 @@
+package store
+
+import (
+	"encoding/binary"
+	"encoding/hex"
+	"strings"
+	"time"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/republicprotocol/republic-go/compute"
+	"github.com/republicprotocol/republic-go/identity"
+	"github.com/republicprotocol/republic-go/network/rpc"
+	"github.com/republicprotocol/republic-go/order"
+	"github.com/republicprotocol/republic-go/stackint"
+	"github.com/syndtr/goleveldb/leveldb"
+	"github.com/syndtr/goleveldb/leveldb/util"
+)
+
+// Key prefixes used to separate the different kinds of values in a LevelDB
+// database. Order IDs and delta fragment IDs are hex encoded so that a prefix
+// can never match part of an ID.
+const (
+	orderFragmentPrefix      = "orderFragment/"
+	residualPrefix           = "residual/"
+	deltaFragmentPrefix      = "deltaFragment/"
+	orderDeltaFragmentPrefix = "orderDeltaFragment/"
+	completeOrderPrefix      = "completeOrder/"
+	noncePrefix              = "nonce/"
+)
+
+// LevelDBStore is a Store that persists everything to a LevelDB database on
+// disk. Values are stored using their network representation.
+type LevelDBStore struct {
+	db *leveldb.DB
+}
+
+// NewLevelDBStore opens the LevelDB database at the given path, creating it
+// if it does not exist.
+func NewLevelDBStore(path string) (*LevelDBStore, error) {
+	db, err := leveldb.OpenFile(path, nil)
+	if err != nil {
+		return nil, err
+	}
+	return &LevelDBStore{
+		db: db,
+	}, nil
+}
+
+// PutOrderFragment implements the Store interface.
+func (store *LevelDBStore) PutOrderFragment(orderFragment *order.Fragment) error {
+	value, err := proto.Marshal(rpc.SerializeOrderFragment(orderFragment))
+	if err != nil {
+		return err
+	}
+	batch := new(leveldb.Batch)
+	batch.Put(orderFragmentKey(orderFragment.OrderID), value)
+	batch.Delete(completeOrderKey(orderFragment.OrderID))
+	return store.db.Write(batch, nil)
+}
+
+// OrderFragments implements the Store interface.
+func (store *LevelDBStore) OrderFragments() ([]*order.Fragment, error) {
+	orderFragments := []*order.Fragment{}
+	iter := store.db.NewIterator(util.BytesPrefix([]byte(orderFragmentPrefix)), nil)
+	defer iter.Release()
+	for iter.Next() {
+		orderFragment, err := unmarshalOrderFragment(iter.Value())
+		if err != nil {
+			return nil, err
+		}
+		orderFragments = append(orderFragments, orderFragment)
+	}
+	return orderFragments, iter.Error()
+}
+
+// PutResidual implements the Store interface. The root order fragment and the
+// residual order fragment are stored as a single value, with the length of
+// the root order fragment as a prefix.
+func (store *LevelDBStore) PutResidual(residual Residual) error {
+	root, err := proto.Marshal(rpc.SerializeOrderFragment(residual.Root))
+	if err != nil {
+		return err
+	}
+	orderFragment, err := proto.Marshal(rpc.SerializeOrderFragment(residual.OrderFragment))
+	if err != nil {
+		return err
+	}
+	value := make([]byte, 4, 4+len(root)+len(orderFragment))
+	binary.BigEndian.PutUint32(value, uint32(len(root)))
+	value = append(value, root...)
+	value = append(value, orderFragment...)
+	batch := new(leveldb.Batch)
+	batch.Put(residualKey(residual.OrderFragment.OrderID), value)
+	batch.Delete(completeOrderKey(residual.OrderFragment.OrderID))
+	return store.db.Write(batch, nil)
+}
+
+// Residuals implements the Store interface.
+func (store *LevelDBStore) Residuals() ([]Residual, error) {
+	residuals := []Residual{}
+	iter := store.db.NewIterator(util.BytesPrefix([]byte(residualPrefix)), nil)
+	defer iter.Release()
+	for iter.Next() {
+		value := iter.Value()
+		if len(value) < 4 || uint64(len(value)-4) < uint64(binary.BigEndian.Uint32(value)) {
+			return nil, ErrCorruptValue
+		}
+		n := 4 + int(binary.BigEndian.Uint32(value))
+		root, err := unmarshalOrderFragment(value[4:n])
+		if err != nil {
+			return nil, err
+		}
+		orderFragment, err := unmarshalOrderFragment(value[n:])
+		if err != nil {
+			return nil, err
+		}
+		residuals = append(residuals, Residual{
+			Root:          root,
+			OrderFragment: orderFragment,
+		})
+	}
+	return residuals, iter.Error()
+}
+
+// PutDeltaFragment implements the Store interface. The delta fragment is
+// indexed by its buy order and its sell order, so that it can be removed with
+// either of them.
+func (store *LevelDBStore) PutDeltaFragment(deltaFragment *compute.DeltaFragment) error {
+	value, err := proto.Marshal(rpc.SerializeDeltaFragment(deltaFragment))
+	if err != nil {
+		return err
+	}
+	batch := new(leveldb.Batch)
+	batch.Put(deltaFragmentKey(deltaFragment.ID), value)
+	batch.Put(orderDeltaFragmentKey(deltaFragment.BuyOrderID, deltaFragment.ID), deltaFragment.ID)
+	batch.Put(orderDeltaFragmentKey(deltaFragment.SellOrderID, deltaFragment.ID), deltaFragment.ID)
+	return store.db.Write(batch, nil)
+}
+
+// DeltaFragments implements the Store interface.
+func (store *LevelDBStore) DeltaFragments() ([]*compute.DeltaFragment, error) {
+	deltaFragments := []*compute.DeltaFragment{}
+	iter := store.db.NewIterator(util.BytesPrefix([]byte(deltaFragmentPrefix)), nil)
+	defer iter.Release()
+	for iter.Next() {
+		serializedDeltaFragment := &rpc.DeltaFragment{}
+		if err := proto.Unmarshal(iter.Value(), serializedDeltaFragment); err != nil {
+			return nil, err
+		}
+		deltaFragment, err := rpc.DeserializeDeltaFragment(serializedDeltaFragment)
+		if err != nil {
+			return nil, err
+		}
+		deltaFragments = append(deltaFragments, deltaFragment)
+	}
+	return deltaFragments, iter.Error()
+}
+
+// RemoveOrder implements the Store interface. All changes are written in a
+// single batch.
+func (store *LevelDBStore) RemoveOrder(orderID order.ID) error {
+	batch := new(leveldb.Batch)
+	batch.Delete(orderFragmentKey(orderID))
+	batch.Delete(residualKey(orderID))
+
+	iter := store.db.NewIterator(util.BytesPrefix(orderDeltaFragmentKey(orderID, nil)), nil)
+	for iter.Next() {
+		deltaFragmentID := compute.DeltaFragmentID(iter.Value())
+		value, err := store.db.Get(deltaFragmentKey(deltaFragmentID), nil)
+		if err == leveldb.ErrNotFound {
+			batch.Delete(copyBytes(iter.Key()))
+			continue
+		}
+		if err != nil {
+			iter.Release()
+			return err
+		}
+		serializedDeltaFragment := &rpc.DeltaFragment{}
+		if err := proto.Unmarshal(value, serializedDeltaFragment); err != nil {
+			iter.Release()
+			return err
+		}
+		batch.Delete(deltaFragmentKey(deltaFragmentID))
+		batch.Delete(orderDeltaFragmentKey(serializedDeltaFragment.BuyOrderId, deltaFragmentID))
+		batch.Delete(orderDeltaFragmentKey(serializedDeltaFragment.SellOrderId, deltaFragmentID))
+	}
+	iter.Release()
+	if err := iter.Error(); err != nil {
+		return err
+	}
+
+	batch.Put(completeOrderKey(orderID), orderID)
+	return store.db.Write(batch, nil)
+}
+
+// CompleteOrders implements the Store interface.
+func (store *LevelDBStore) CompleteOrders() ([]order.ID, error) {
+	orderIDs := []order.ID{}
+	iter := store.db.NewIterator(util.BytesPrefix([]byte(completeOrderPrefix)), nil)
+	defer iter.Release()
+	for iter.Next() {
+		orderIDs = append(orderIDs, order.ID(copyBytes(iter.Value())))
+	}
+	return orderIDs, iter.Error()
+}
+
+// PutNonce implements the Store interface. The nonce is stored in its key,
+// and the expiry of its order, followed by the ID of the order fragment that
+// used it, is stored as the value.
+func (store *LevelDBStore) PutNonce(nonce compute.Nonce) error {
+	value := make([]byte, 8, 8+len(nonce.FragmentID))
+	binary.BigEndian.PutUint64(value, uint64(nonce.Expiry.Unix()))
+	value = append(value, nonce.FragmentID...)
+	return store.db.Put([]byte(nonceKey(nonce)), value, nil)
+}
+
+// Nonces implements the Store interface.
+func (store *LevelDBStore) Nonces() ([]compute.Nonce, error) {
+	nonces := []compute.Nonce{}
+	iter := store.db.NewIterator(util.BytesPrefix([]byte(noncePrefix)), nil)
+	defer iter.Release()
+	for iter.Next() {
+		nonce, err := unmarshalNonce(iter.Key(), iter.Value())
+		if err != nil {
+			return nil, err
+		}
+		nonces = append(nonces, nonce)
+	}
+	return nonces, iter.Error()
+}
+
+// RemoveNonce implements the Store interface.
+func (store *LevelDBStore) RemoveNonce(nonce compute.Nonce) error {
+	return store.db.Delete([]byte(nonceKey(nonce)), nil)
+}
+
+// Close implements the Store interface.
+func (store *LevelDBStore) Close() error {
+	if err := store.db.Close(); err != nil {
+		if err == leveldb.ErrClosed {
+			return ErrClosed
+		}
+		return err
+	}
+	return nil
+}
+
+func orderFragmentKey(orderID order.ID) []byte {
+	return []byte(orderFragmentPrefix + hex.EncodeToString(orderID))
+}
+
+func residualKey(orderID order.ID) []byte {
+	return []byte(residualPrefix + hex.EncodeToString(orderID))
+}
+
+func deltaFragmentKey(deltaFragmentID compute.DeltaFragmentID) []byte {
+	return []byte(deltaFragmentPrefix + hex.EncodeToString(deltaFragmentID))
+}
+
+func orderDeltaFragmentKey(orderID order.ID, deltaFragmentID compute.DeltaFragmentID) []byte {
+	return []byte(orderDeltaFragmentPrefix + hex.EncodeToString(orderID) + "/" + hex.EncodeToString(deltaFragmentID))
+}
+
+func completeOrderKey(orderID order.ID) []byte {
+	return []byte(completeOrderPrefix + hex.EncodeToString(orderID))
+}
+
+// nonceKey returns the key of a nonce, which is unique for each trader and
+// nonce. It is also used to index nonces in a MemoryStore.
+func nonceKey(nonce compute.Nonce) string {
+	return noncePrefix + hex.EncodeToString(nonce.Trader) + "/" + hex.EncodeToString(nonce.Value.Bytes())
+}
+
+func unmarshalNonce(key, value []byte) (compute.Nonce, error) {
+	parts := strings.Split(strings.TrimPrefix(string(key), noncePrefix), "/")
+	if len(parts) != 2 || len(value) < 8 {
+		return compute.Nonce{}, ErrCorruptValue
+	}
+	trader, err := hex.DecodeString(parts[0])
+	if err != nil {
+		return compute.Nonce{}, ErrCorruptValue
+	}
+	nonceBytes, err := hex.DecodeString(parts[1])
+	if err != nil {
+		return compute.Nonce{}, ErrCorruptValue
+	}
+	nonce, err := stackint.FromBytes(nonceBytes)
+	if err != nil {
+		return compute.Nonce{}, ErrCorruptValue
+	}
+	return compute.Nonce{
+		Trader:     identity.ID(trader),
+		Value:      nonce,
+		Expiry:     time.Unix(int64(binary.BigEndian.Uint64(value[:8])), 0),
+		FragmentID: order.FragmentID(append([]byte{}, value[8:]...)),
+	}, nil
+}
+
+func unmarshalOrderFragment(value []byte) (*order.Fragment, error) {
+	serializedOrderFragment := &rpc.OrderFragment{}
+	if err := proto.Unmarshal(value, serializedOrderFragment); err != nil {
+		return nil, err
+	}
+	return rpc.DeserializeOrderFragment(serializedOrderFragment)
+}
+
+// copyBytes copies bytes returned by an iterator, because the iterator reuses
+// its buffers.
+func copyBytes(bs []byte) []byte {
+	copied := make([]byte, len(bs))
+	copy(copied, bs)
+	return copied
+}
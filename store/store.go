@@ -0,0 +1,246 @@
+package store
+
+import (
+	"errors"
+
+	"github.com/republicprotocol/go-do"
+	"github.com/republicprotocol/republic-go/compute"
+	"github.com/republicprotocol/republic-go/order"
+)
+
+// ErrClosed is returned when a Store is used after it has been closed.
+var ErrClosed = errors.New("store closed")
+
+// ErrCorruptValue is returned when a value read from a Store cannot be
+// decoded.
+var ErrCorruptValue = errors.New("corrupt value")
+
+// A Residual is the order fragment of a residual order that remains after an
+// order is partially filled, and the Root order fragment that was opened by
+// the trader.
+type Residual struct {
+	Root          *order.Fragment
+	OrderFragment *order.Fragment
+}
+
+// A Store persists the order fragments and delta fragments held by a dark
+// node, so that the dark node can be restarted without losing its state.
+type Store interface {
+	// PutOrderFragment stores an order fragment that is open. An order that
+	// was recorded as being complete is reopened.
+	PutOrderFragment(orderFragment *order.Fragment) error
+
+	// OrderFragments returns all order fragments that are open.
+	OrderFragments() ([]*order.Fragment, error)
+
+	// PutResidual stores the order fragment of a residual order that is
+	// open. A residual order that was recorded as being complete is reopened.
+	PutResidual(residual Residual) error
+
+	// Residuals returns all residual orders that are open.
+	Residuals() ([]Residual, error)
+
+	// PutDeltaFragment stores a delta fragment.
+	PutDeltaFragment(deltaFragment *compute.DeltaFragment) error
+
+	// DeltaFragments returns all delta fragments for orders that are open.
+	DeltaFragments() ([]*compute.DeltaFragment, error)
+
+	// RemoveOrder removes the order fragment, or the residual order, and all
+	// delta fragments, for an order and records the order as being complete.
+	RemoveOrder(orderID order.ID) error
+
+	// CompleteOrders returns the IDs of all orders that have been removed.
+	CompleteOrders() ([]order.ID, error)
+
+	// PutNonce stores a nonce that has been used by a trader.
+	PutNonce(nonce compute.Nonce) error
+
+	// Nonces returns all nonces that have been stored and not removed.
+	Nonces() ([]compute.Nonce, error)
+
+	// RemoveNonce removes a nonce after its order has expired.
+	RemoveNonce(nonce compute.Nonce) error
+
+	// Close the Store and release its resources.
+	Close() error
+}
+
+// MemoryStore is a Store that holds everything in memory. It does not persist
+// anything across restarts.
+type MemoryStore struct {
+	do.GuardedObject
+
+	closed         bool
+	orderFragments map[string]*order.Fragment
+	residuals      map[string]Residual
+	deltaFragments map[string]*compute.DeltaFragment
+	completeOrders map[string]bool
+	nonces         map[string]compute.Nonce
+}
+
+// NewMemoryStore returns a new MemoryStore.
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{
+		GuardedObject:  do.NewGuardedObject(),
+		closed:         false,
+		orderFragments: map[string]*order.Fragment{},
+		residuals:      map[string]Residual{},
+		deltaFragments: map[string]*compute.DeltaFragment{},
+		completeOrders: map[string]bool{},
+		nonces:         map[string]compute.Nonce{},
+	}
+}
+
+// PutOrderFragment implements the Store interface.
+func (store *MemoryStore) PutOrderFragment(orderFragment *order.Fragment) error {
+	store.Enter(nil)
+	defer store.Exit()
+	if store.closed {
+		return ErrClosed
+	}
+	store.orderFragments[string(orderFragment.OrderID)] = orderFragment
+	delete(store.completeOrders, string(orderFragment.OrderID))
+	return nil
+}
+
+// OrderFragments implements the Store interface.
+func (store *MemoryStore) OrderFragments() ([]*order.Fragment, error) {
+	store.EnterReadOnly(nil)
+	defer store.ExitReadOnly()
+	if store.closed {
+		return nil, ErrClosed
+	}
+	orderFragments := make([]*order.Fragment, 0, len(store.orderFragments))
+	for _, orderFragment := range store.orderFragments {
+		orderFragments = append(orderFragments, orderFragment)
+	}
+	return orderFragments, nil
+}
+
+// PutResidual implements the Store interface.
+func (store *MemoryStore) PutResidual(residual Residual) error {
+	store.Enter(nil)
+	defer store.Exit()
+	if store.closed {
+		return ErrClosed
+	}
+	store.residuals[string(residual.OrderFragment.OrderID)] = residual
+	delete(store.completeOrders, string(residual.OrderFragment.OrderID))
+	return nil
+}
+
+// Residuals implements the Store interface.
+func (store *MemoryStore) Residuals() ([]Residual, error) {
+	store.EnterReadOnly(nil)
+	defer store.ExitReadOnly()
+	if store.closed {
+		return nil, ErrClosed
+	}
+	residuals := make([]Residual, 0, len(store.residuals))
+	for _, residual := range store.residuals {
+		residuals = append(residuals, residual)
+	}
+	return residuals, nil
+}
+
+// PutDeltaFragment implements the Store interface.
+func (store *MemoryStore) PutDeltaFragment(deltaFragment *compute.DeltaFragment) error {
+	store.Enter(nil)
+	defer store.Exit()
+	if store.closed {
+		return ErrClosed
+	}
+	store.deltaFragments[string(deltaFragment.ID)] = deltaFragment
+	return nil
+}
+
+// DeltaFragments implements the Store interface.
+func (store *MemoryStore) DeltaFragments() ([]*compute.DeltaFragment, error) {
+	store.EnterReadOnly(nil)
+	defer store.ExitReadOnly()
+	if store.closed {
+		return nil, ErrClosed
+	}
+	deltaFragments := make([]*compute.DeltaFragment, 0, len(store.deltaFragments))
+	for _, deltaFragment := range store.deltaFragments {
+		deltaFragments = append(deltaFragments, deltaFragment)
+	}
+	return deltaFragments, nil
+}
+
+// RemoveOrder implements the Store interface.
+func (store *MemoryStore) RemoveOrder(orderID order.ID) error {
+	store.Enter(nil)
+	defer store.Exit()
+	if store.closed {
+		return ErrClosed
+	}
+	delete(store.orderFragments, string(orderID))
+	delete(store.residuals, string(orderID))
+	for id, deltaFragment := range store.deltaFragments {
+		if deltaFragment.BuyOrderID.Equal(orderID) || deltaFragment.SellOrderID.Equal(orderID) {
+			delete(store.deltaFragments, id)
+		}
+	}
+	store.completeOrders[string(orderID)] = true
+	return nil
+}
+
+// CompleteOrders implements the Store interface.
+func (store *MemoryStore) CompleteOrders() ([]order.ID, error) {
+	store.EnterReadOnly(nil)
+	defer store.ExitReadOnly()
+	if store.closed {
+		return nil, ErrClosed
+	}
+	orderIDs := make([]order.ID, 0, len(store.completeOrders))
+	for orderID := range store.completeOrders {
+		orderIDs = append(orderIDs, order.ID(orderID))
+	}
+	return orderIDs, nil
+}
+
+// PutNonce implements the Store interface.
+func (store *MemoryStore) PutNonce(nonce compute.Nonce) error {
+	store.Enter(nil)
+	defer store.Exit()
+	if store.closed {
+		return ErrClosed
+	}
+	store.nonces[nonceKey(nonce)] = nonce
+	return nil
+}
+
+// Nonces implements the Store interface.
+func (store *MemoryStore) Nonces() ([]compute.Nonce, error) {
+	store.EnterReadOnly(nil)
+	defer store.ExitReadOnly()
+	if store.closed {
+		return nil, ErrClosed
+	}
+	nonces := make([]compute.Nonce, 0, len(store.nonces))
+	for _, nonce := range store.nonces {
+		nonces = append(nonces, nonce)
+	}
+	return nonces, nil
+}
+
+// RemoveNonce implements the Store interface.
+func (store *MemoryStore) RemoveNonce(nonce compute.Nonce) error {
+	store.Enter(nil)
+	defer store.Exit()
+	if store.closed {
+		return ErrClosed
+	}
+	delete(store.nonces, nonceKey(nonce))
+	return nil
+}
+
+// Close implements the Store interface.
+func (store *MemoryStore) Close() error {
+	store.Enter(nil)
+	defer store.Exit()
+	store.closed = true
+	return nil
+}
@@ -0,0 +1,260 @@
+package store_test
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	. "github.com/republicprotocol/republic-go/store"
+
+	"github.com/republicprotocol/republic-go/compute"
+	"github.com/republicprotocol/republic-go/identity"
+	"github.com/republicprotocol/republic-go/order"
+	"github.com/republicprotocol/republic-go/shamir"
+	"github.com/republicprotocol/republic-go/stackint"
+)
+
+var primeVal, _ = stackint.FromString("179769313486231590772930519078902473361797697894230657273430081157732675805500963132708477322407536021120113879871393357658789768814416622492847430639474124377767893424865485276302219601246094119453082952085005768838150682342462881473913110540827237163350510684586298239947245938479716304835356329624224137111")
+var prime = &primeVal
+
+var _ = Describe("Stores", func() {
+
+	var dir string
+
+	BeforeEach(func() {
+		var err error
+		dir, err = ioutil.TempDir("", "store")
+		Ω(err).ShouldNot(HaveOccurred())
+	})
+
+	AfterEach(func() {
+		os.RemoveAll(dir)
+	})
+
+	stores := map[string]func() Store{
+		"memory": func() Store {
+			return NewMemoryStore()
+		},
+		"leveldb": func() Store {
+			store, err := NewLevelDBStore(filepath.Join(dir, "db"))
+			Ω(err).ShouldNot(HaveOccurred())
+			return store
+		},
+	}
+
+	for name, newStore := range stores {
+		name, newStore := name, newStore
+
+		Context("when using a "+name+" store", func() {
+
+			It("should return stored order fragments", func() {
+				store := newStore()
+				defer store.Close()
+
+				buyFragment, sellFragment := newOrderFragments()
+				Ω(store.PutOrderFragment(buyFragment)).ShouldNot(HaveOccurred())
+				Ω(store.PutOrderFragment(sellFragment)).ShouldNot(HaveOccurred())
+
+				orderFragments, err := store.OrderFragments()
+				Ω(err).ShouldNot(HaveOccurred())
+				Ω(orderFragments).Should(HaveLen(2))
+				for _, orderFragment := range orderFragments {
+					if orderFragment.OrderID.Equal(buyFragment.OrderID) {
+						Ω(orderFragment.Equal(buyFragment)).Should(BeTrue())
+					} else {
+						Ω(orderFragment.Equal(sellFragment)).Should(BeTrue())
+					}
+				}
+			})
+
+			It("should return stored delta fragments", func() {
+				store := newStore()
+				defer store.Close()
+
+				deltaFragment := newDeltaFragment(newOrderFragments())
+				Ω(store.PutDeltaFragment(deltaFragment)).ShouldNot(HaveOccurred())
+				Ω(store.PutDeltaFragment(deltaFragment)).ShouldNot(HaveOccurred())
+
+				deltaFragments, err := store.DeltaFragments()
+				Ω(err).ShouldNot(HaveOccurred())
+				Ω(deltaFragments).Should(HaveLen(1))
+				Ω(deltaFragments[0].Equals(deltaFragment)).Should(BeTrue())
+			})
+
+			It("should remove everything for an order and record it as complete", func() {
+				store := newStore()
+				defer store.Close()
+
+				buyFragment, sellFragment := newOrderFragments()
+				Ω(store.PutOrderFragment(buyFragment)).ShouldNot(HaveOccurred())
+				Ω(store.PutOrderFragment(sellFragment)).ShouldNot(HaveOccurred())
+				Ω(store.PutDeltaFragment(newDeltaFragment(buyFragment, sellFragment))).ShouldNot(HaveOccurred())
+				Ω(store.RemoveOrder(sellFragment.OrderID)).ShouldNot(HaveOccurred())
+
+				orderFragments, err := store.OrderFragments()
+				Ω(err).ShouldNot(HaveOccurred())
+				Ω(orderFragments).Should(HaveLen(1))
+				Ω(orderFragments[0].Equal(buyFragment)).Should(BeTrue())
+
+				deltaFragments, err := store.DeltaFragments()
+				Ω(err).ShouldNot(HaveOccurred())
+				Ω(deltaFragments).Should(BeEmpty())
+
+				completeOrders, err := store.CompleteOrders()
+				Ω(err).ShouldNot(HaveOccurred())
+				Ω(completeOrders).Should(Equal([]order.ID{sellFragment.OrderID}))
+			})
+
+			It("should reopen a complete order when it is stored again", func() {
+				store := newStore()
+				defer store.Close()
+
+				buyFragment, _ := newOrderFragments()
+				Ω(store.PutOrderFragment(buyFragment)).ShouldNot(HaveOccurred())
+				Ω(store.RemoveOrder(buyFragment.OrderID)).ShouldNot(HaveOccurred())
+				Ω(store.PutOrderFragment(buyFragment)).ShouldNot(HaveOccurred())
+
+				orderFragments, err := store.OrderFragments()
+				Ω(err).ShouldNot(HaveOccurred())
+				Ω(orderFragments).Should(HaveLen(1))
+				completeOrders, err := store.CompleteOrders()
+				Ω(err).ShouldNot(HaveOccurred())
+				Ω(completeOrders).Should(BeEmpty())
+			})
+
+			It("should return stored residual orders until they are removed", func() {
+				store := newStore()
+				defer store.Close()
+
+				buyFragment, sellFragment := newOrderFragments()
+				residual := Residual{
+					Root:          buyFragment,
+					OrderFragment: buyFragment.Residual(sellFragment.OrderID, sellFragment.MaxVolumeShare, prime),
+				}
+				Ω(store.PutResidual(residual)).ShouldNot(HaveOccurred())
+
+				residuals, err := store.Residuals()
+				Ω(err).ShouldNot(HaveOccurred())
+				Ω(residuals).Should(HaveLen(1))
+				Ω(residuals[0].Root.Equal(residual.Root)).Should(BeTrue())
+				Ω(residuals[0].OrderFragment.Equal(residual.OrderFragment)).Should(BeTrue())
+
+				Ω(store.RemoveOrder(residual.OrderFragment.OrderID)).ShouldNot(HaveOccurred())
+				residuals, err = store.Residuals()
+				Ω(err).ShouldNot(HaveOccurred())
+				Ω(residuals).Should(BeEmpty())
+			})
+
+			It("should return stored nonces until they are removed", func() {
+				store := newStore()
+				defer store.Close()
+
+				buyFragment, sellFragment := newOrderFragments()
+				buyNonce, err := compute.NewNonce(buyFragment)
+				Ω(err).ShouldNot(HaveOccurred())
+				sellNonce, err := compute.NewNonce(sellFragment)
+				Ω(err).ShouldNot(HaveOccurred())
+				Ω(store.PutNonce(buyNonce)).ShouldNot(HaveOccurred())
+				Ω(store.PutNonce(sellNonce)).ShouldNot(HaveOccurred())
+
+				nonces, err := store.Nonces()
+				Ω(err).ShouldNot(HaveOccurred())
+				Ω(nonces).Should(HaveLen(2))
+
+				Ω(store.RemoveNonce(buyNonce)).ShouldNot(HaveOccurred())
+				nonces, err = store.Nonces()
+				Ω(err).ShouldNot(HaveOccurred())
+				Ω(nonces).Should(HaveLen(1))
+				Ω(nonces[0].Trader).Should(Equal(sellNonce.Trader))
+				Ω(nonces[0].Value.Cmp(&sellNonce.Value)).Should(Equal(0))
+				Ω(nonces[0].Expiry.Unix()).Should(Equal(sellNonce.Expiry.Unix()))
+				Ω(nonces[0].FragmentID).Should(Equal(sellNonce.FragmentID))
+			})
+
+			It("should return an error after being closed", func() {
+				store := newStore()
+				Ω(store.Close()).ShouldNot(HaveOccurred())
+				_, err := store.OrderFragments()
+				Ω(err).Should(HaveOccurred())
+			})
+		})
+	}
+
+	Context("when reopening a leveldb store", func() {
+
+		It("should restore everything that was stored", func() {
+			path := filepath.Join(dir, "db")
+			store, err := NewLevelDBStore(path)
+			Ω(err).ShouldNot(HaveOccurred())
+
+			buyFragment, sellFragment := newOrderFragments()
+			otherBuyFragment, _ := newOrderFragments()
+			deltaFragment := newDeltaFragment(buyFragment, sellFragment)
+			Ω(store.PutOrderFragment(buyFragment)).ShouldNot(HaveOccurred())
+			Ω(store.PutOrderFragment(sellFragment)).ShouldNot(HaveOccurred())
+			Ω(store.PutOrderFragment(otherBuyFragment)).ShouldNot(HaveOccurred())
+			Ω(store.PutDeltaFragment(deltaFragment)).ShouldNot(HaveOccurred())
+			Ω(store.RemoveOrder(otherBuyFragment.OrderID)).ShouldNot(HaveOccurred())
+			nonce, err := compute.NewNonce(buyFragment)
+			Ω(err).ShouldNot(HaveOccurred())
+			Ω(store.PutNonce(nonce)).ShouldNot(HaveOccurred())
+			Ω(store.Close()).ShouldNot(HaveOccurred())
+
+			store, err = NewLevelDBStore(path)
+			Ω(err).ShouldNot(HaveOccurred())
+			defer store.Close()
+
+			orderFragments, err := store.OrderFragments()
+			Ω(err).ShouldNot(HaveOccurred())
+			Ω(orderFragments).Should(HaveLen(2))
+
+			deltaFragments, err := store.DeltaFragments()
+			Ω(err).ShouldNot(HaveOccurred())
+			Ω(deltaFragments).Should(HaveLen(1))
+			Ω(deltaFragments[0].Equals(deltaFragment)).Should(BeTrue())
+
+			completeOrders, err := store.CompleteOrders()
+			Ω(err).ShouldNot(HaveOccurred())
+			Ω(completeOrders).Should(Equal([]order.ID{otherBuyFragment.OrderID}))
+
+			nonces, err := store.Nonces()
+			Ω(err).ShouldNot(HaveOccurred())
+			Ω(nonces).Should(HaveLen(1))
+			Ω(nonces[0].Trader).Should(Equal(nonce.Trader))
+			Ω(nonces[0].Value.Cmp(&nonce.Value)).Should(Equal(0))
+			Ω(nonces[0].FragmentID).Should(Equal(nonce.FragmentID))
+		})
+	})
+})
+
+func newOrderFragments() (*order.Fragment, *order.Fragment) {
+	price := stackint.FromUint(10)
+	maxVolume := stackint.FromUint(1000)
+	minVolume := stackint.FromUint(100)
+	nonce := stackint.FromUint(uint(time.Now().UnixNano()))
+
+	buy := order.NewOrder(order.TypeLimit, order.ParityBuy, time.Now().Add(time.Hour), order.CurrencyCodeBTC, order.CurrencyCodeETH, &price, &maxVolume, &minVolume, &nonce)
+	buyFragments, err := buy.Split(3, 2, prime)
+	Ω(err).ShouldNot(HaveOccurred())
+	Ω(order.SignFragments(newKeyPair(), buyFragments)).ShouldNot(HaveOccurred())
+	sell := order.NewOrder(order.TypeLimit, order.ParitySell, time.Now().Add(time.Hour), order.CurrencyCodeBTC, order.CurrencyCodeETH, &price, &maxVolume, &minVolume, &nonce)
+	sellFragments, err := sell.Split(3, 2, prime)
+	Ω(err).ShouldNot(HaveOccurred())
+	Ω(order.SignFragments(newKeyPair(), sellFragments)).ShouldNot(HaveOccurred())
+	return buyFragments[0], sellFragments[0]
+}
+
+func newKeyPair() identity.KeyPair {
+	keyPair, err := identity.NewKeyPair()
+	Ω(err).ShouldNot(HaveOccurred())
+	return keyPair
+}
+
+func newDeltaFragment(buyFragment, sellFragment *order.Fragment) *compute.DeltaFragment {
+	differenceFragment := compute.NewDifferenceFragment(buyFragment, sellFragment, prime)
+	Ω(differenceFragment).ShouldNot(BeNil())
+	return compute.NewDeltaFragment(differenceFragment, shamir.Share{Key: buyFragment.PriceShare.Key, Value: stackint.One()})
+}
@@ -0,0 +1,124 @@
+package trader
+
+import (
+	"github.com/republicprotocol/republic-go/identity"
+	"github.com/republicprotocol/republic-go/order"
+)
+
+// A Delivery is the result of sending an order fragment, a cancellation, or a
+// reveal, to a dark node. The Err is nil if the dark node accepted it.
+type Delivery struct {
+	NodeID identity.ID
+	Err    error
+}
+
+// A PoolStatus records the deliveries of an Order to the dark nodes of one
+// dark pool. The Order was split into N order fragments, K of which are
+// needed to reconstruct it.
+type PoolStatus struct {
+	N         int64
+	K         int64
+	Opened    []Delivery
+	Cancelled []Delivery
+	Revealed  []Delivery
+}
+
+// IsOpen returns true if at least K dark nodes in the dark pool received an
+// order fragment, otherwise it returns false.
+func (status *PoolStatus) IsOpen() bool {
+	return int64(accepted(status.Opened)) >= status.K
+}
+
+// IsCancelled returns true if at least one dark node in the dark pool
+// accepted the cancellation, otherwise it returns false. The dark node
+// forwards the cancellation to the rest of the dark pool.
+func (status *PoolStatus) IsCancelled() bool {
+	return accepted(status.Cancelled) > 0
+}
+
+// IsRevealed returns true if at least one dark node in the dark pool accepted
+// the reveal, otherwise it returns false.
+func (status *PoolStatus) IsRevealed() bool {
+	return accepted(status.Revealed) > 0
+}
+
+// A Status records the deliveries of an Order to the dark pools in which it
+// was opened. A Trader only opens an Order in one dark pool, selected by the
+// market of the Order.
+type Status struct {
+	OrderID order.ID
+	Pools   []PoolStatus
+}
+
+// IsOpen returns true if the Order is open in at least one dark pool,
+// otherwise it returns false.
+func (status *Status) IsOpen() bool {
+	for i := range status.Pools {
+		if status.Pools[i].IsOpen() {
+			return true
+		}
+	}
+	return false
+}
+
+// IsCancelled returns true if the Order has been cancelled in every dark pool
+// in which it is open, otherwise it returns false.
+func (status *Status) IsCancelled() bool {
+	cancelled := false
+	for i := range status.Pools {
+		if !status.Pools[i].IsOpen() {
+			continue
+		}
+		if !status.Pools[i].IsCancelled() {
+			return false
+		}
+		cancelled = true
+	}
+	return cancelled
+}
+
+// IsRevealed returns true if the Order has been revealed to at least one
+// dark node, otherwise it returns false. Every dark node that accepts the
+// reveal can settle the match of the Order.
+func (status *Status) IsRevealed() bool {
+	for i := range status.Pools {
+		if status.Pools[i].IsRevealed() {
+			return true
+		}
+	}
+	return false
+}
+
+// Clone returns a deep copy of the Status.
+func (status *Status) Clone() *Status {
+	clone := &Status{
+		OrderID: status.OrderID,
+		Pools:   make([]PoolStatus, len(status.Pools)),
+	}
+	for i, poolStatus := range status.Pools {
+		clone.Pools[i] = PoolStatus{
+			N: poolStatus.N,
+			K: poolStatus.K,
+		}
+		if poolStatus.Opened != nil {
+			clone.Pools[i].Opened = append([]Delivery{}, poolStatus.Opened...)
+		}
+		if poolStatus.Cancelled != nil {
+			clone.Pools[i].Cancelled = append([]Delivery{}, poolStatus.Cancelled...)
+		}
+		if poolStatus.Revealed != nil {
+			clone.Pools[i].Revealed = append([]Delivery{}, poolStatus.Revealed...)
+		}
+	}
+	return clone
+}
+
+func accepted(deliveries []Delivery) int {
+	n := 0
+	for _, delivery := range deliveries {
+		if delivery.Err == nil {
+			n++
+		}
+	}
+	return n
+}
@@ -0,0 +1,446 @@
+package trader
+
+import (
+	"encoding/binary"
+	"errors"
+	"time"
+
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/republicprotocol/go-do"
+	"github.com/republicprotocol/republic-go/dark"
+	"github.com/republicprotocol/republic-go/identity"
+	"github.com/republicprotocol/republic-go/network/rpc"
+	"github.com/republicprotocol/republic-go/order"
+	"github.com/republicprotocol/republic-go/order/market"
+	"github.com/republicprotocol/republic-go/shamir"
+	"github.com/republicprotocol/republic-go/stackint"
+)
+
+// ErrNoDarkPools is returned when an Order is opened while there are no dark
+// pools.
+var ErrNoDarkPools = errors.New("no dark pools")
+
+// ErrOpenTimeMissing is returned when an immediate Order is opened without an
+// OpenTime, because dark nodes close its window at the OpenTime.
+var ErrOpenTimeMissing = errors.New("open time missing")
+
+// ErrOrderNotOpened is returned when an Order could not be opened in its dark
+// pool, because too few dark nodes received its order fragments.
+var ErrOrderNotOpened = errors.New("order not opened")
+
+// ErrOrderNotCancelled is returned when the cancellation of an Order was not
+// accepted in the dark pool in which the Order is open.
+var ErrOrderNotCancelled = errors.New("order not cancelled")
+
+// ErrOrderNotRevealed is returned when the reveal of an Order was not accepted
+// by any dark node.
+var ErrOrderNotRevealed = errors.New("order not revealed")
+
+// ErrUnknownOrder is returned when the Trader has not opened an Order.
+var ErrUnknownOrder = errors.New("unknown order")
+
+// ErrUnknownDarkNode is returned when the MultiAddress of a dark node cannot
+// be found.
+var ErrUnknownDarkNode = errors.New("unknown dark node")
+
+// marketTag identifies the hash of a market that selects the dark pool in
+// which its orders are opened.
+var marketTag = []byte("Republic Protocol: market")
+
+// A DarkOcean returns the dark pools in which a Trader opens orders. It is
+// implemented by the dark.Ocean, which reads the dark pools of the current
+// epoch from a DarkNodeRegistry.
+type DarkOcean interface {
+	Pools() dark.Pools
+}
+
+// Options that parameterize the behavior of a Trader. The Field is the name
+// of the finite field used by the dark nodes. The Trader finds the
+// MultiAddresses of dark nodes by querying the BootstrapMultiAddresses, and
+// every RPC is attempted TimeoutRetries times before the delivery fails.
+type Options struct {
+	MultiAddress            identity.MultiAddress   `json:"multiAddress"`
+	BootstrapMultiAddresses identity.MultiAddresses `json:"bootstrapMultiAddresses"`
+	Field                   string                  `json:"field"`
+	Timeout                 time.Duration           `json:"timeout"`
+	TimeoutBackoff          time.Duration           `json:"timeoutBackoff"`
+	TimeoutRetries          int                     `json:"timeoutRetries"`
+}
+
+// DefaultOptions returns the Options for a Trader at the given MultiAddress
+// that opens orders using the default finite field.
+func DefaultOptions(multiAddress identity.MultiAddress) Options {
+	return Options{
+		MultiAddress:            multiAddress,
+		BootstrapMultiAddresses: identity.MultiAddresses{},
+		Field:                   shamir.FieldDefault,
+		Timeout:                 30 * time.Second,
+		TimeoutBackoff:          0 * time.Second,
+		TimeoutRetries:          3,
+	}
+}
+
+// A Trader opens orders in the dark pools. Every Order is verified against a
+// market.Registry and split into order fragments for one dark pool, one for
+// each dark node, and the Trader signs every order fragment so that it can
+// later cancel the Order. The Trader
+// records the delivery of every order fragment and every cancellation. Dark
+// nodes reject an Order that reuses a nonce, so every Order must use a nonce
+// returned by NextNonce.
+type Trader struct {
+	do.GuardedObject
+
+	keyPair    identity.KeyPair
+	darkOcean  DarkOcean
+	registry   *market.Registry
+	options    Options
+	vss        *shamir.VSS
+	clientPool *rpc.ClientPool
+
+	multiAddresses map[identity.Address]identity.MultiAddress
+	statuses       map[string]*Status
+	nonce          uint64
+}
+
+// NewTrader returns a Trader that signs orders using the given KeyPair, and
+// opens them in the dark pools of the DarkOcean. Only orders in the Markets
+// listed by the Registry are opened.
+func NewTrader(keyPair identity.KeyPair, darkOcean DarkOcean, registry *market.Registry, options Options) (*Trader, error) {
+	field, err := shamir.FieldByName(options.Field)
+	if err != nil {
+		return nil, err
+	}
+	vss, err := shamir.NewVSS(field.Prime)
+	if err != nil {
+		return nil, err
+	}
+	multiAddressSignature, err := keyPair.Sign(options.MultiAddress)
+	if err != nil {
+		return nil, err
+	}
+	clientPool := rpc.NewClientPool(options.MultiAddress, multiAddressSignature).
+		WithTimeout(options.Timeout).
+		WithTimeoutBackoff(options.TimeoutBackoff).
+		WithTimeoutRetries(options.TimeoutRetries).
+		WithField(field.ID)
+
+	return &Trader{
+		GuardedObject:  do.NewGuardedObject(),
+		keyPair:        keyPair,
+		darkOcean:      darkOcean,
+		registry:       registry,
+		options:        options,
+		vss:            vss,
+		clientPool:     clientPool,
+		multiAddresses: map[identity.Address]identity.MultiAddress{},
+		statuses:       map[string]*Status{},
+	}, nil
+}
+
+// NextNonce returns the nonce for the next Order opened by the Trader. Nonces
+// are monotonically increasing, and are never less than the current time in
+// nanoseconds, so that they keep increasing when the Trader is restarted.
+func (trader *Trader) NextNonce() *stackint.Int1024 {
+	trader.Enter(nil)
+	defer trader.Exit()
+	nonce := uint64(time.Now().UnixNano())
+	if nonce <= trader.nonce {
+		nonce = trader.nonce + 1
+	}
+	trader.nonce = nonce
+	value := stackint.FromUint(uint(nonce))
+	return &value
+}
+
+// Open an Order in one dark pool of the current epoch. The Order is verified
+// against the Registry before it is split, because dark nodes cannot verify
+// the secret shares of its price and volumes, and its order fragments prove
+// that it is in one of the Markets listed by the Registry, which dark nodes
+// verify against their own registry. The dark pool is selected by the market
+// of the Order, so that all orders in a market are opened in the same dark
+// pool and can be matched against each other, and so that an Order is never
+// matched by more than one dark pool. The Order is split so that any 2/3 of
+// the dark nodes, plus one, can reconstruct it. Every dark node also receives
+// the order fragments of the rest of its dark pool, encrypted for the dark
+// nodes that they are sent to, so that a dark node that was not running can
+// sync its order fragment later. An ErrOrderNotOpened is returned, alongside
+// the Status, if too few dark nodes received their order fragment. Immediate
+// Orders must be opened soon after their OpenTime, because their window starts
+// at the OpenTime.
+func (trader *Trader) Open(ord *order.Order) (*Status, error) {
+	if err := trader.registry.VerifyOrder(ord); err != nil {
+		return nil, err
+	}
+	if ord.TimeInForce.IsImmediate() && ord.OpenTime.IsZero() {
+		return nil, ErrOpenTimeMissing
+	}
+	pool := selectPool(trader.darkOcean.Pools(), ord)
+	if pool == nil {
+		return nil, ErrNoDarkPools
+	}
+
+	nodes := poolNodes(pool)
+	n := int64(len(nodes))
+	k := int64(len(nodes)*2/3 + 1)
+	fragments, err := ord.SplitVerifiable(n, k, trader.vss, trader.registry.Pairs())
+	if err != nil {
+		return nil, err
+	}
+	if err := order.SignFragments(trader.keyPair, fragments); err != nil {
+		return nil, err
+	}
+	encryptedFragments, err := encryptFragments(nodes, fragments)
+	if err != nil {
+		return nil, err
+	}
+
+	poolStatus := PoolStatus{
+		N:      n,
+		K:      k,
+		Opened: make([]Delivery, len(nodes)),
+	}
+	do.CoForAll(nodes, func(i int) {
+		poolStatus.Opened[i] = Delivery{NodeID: nodes[i].ID}
+		multiAddress, err := trader.findMultiAddress(nodes[i].ID, nodes[i].MultiAddress())
+		if err != nil {
+			poolStatus.Opened[i].Err = err
+			return
+		}
+		poolStatus.Opened[i].Err = trader.clientPool.OpenOrder(multiAddress, rpc.SerializeOrderFragment(fragments[i]), encryptedFragments)
+	})
+	status := &Status{
+		OrderID: ord.ID,
+		Pools:   []PoolStatus{poolStatus},
+	}
+
+	trader.Enter(nil)
+	trader.statuses[string(ord.ID)] = status
+	trader.Exit()
+
+	if !status.IsOpen() {
+		return status.Clone(), ErrOrderNotOpened
+	}
+	return status.Clone(), nil
+}
+
+// Cancel an Order that was opened by the Trader. The signed Cancellation is
+// sent to every dark node that received an order fragment of the Order, and
+// the dark nodes forward it to the rest of their dark pool. An
+// ErrOrderNotCancelled is returned, alongside the Status, if no dark node in
+// one of these dark pools accepted the Cancellation.
+func (trader *Trader) Cancel(orderID order.ID) (*Status, error) {
+	trader.EnterReadOnly(nil)
+	status, ok := trader.statuses[string(orderID)]
+	if ok {
+		status = status.Clone()
+	}
+	trader.ExitReadOnly()
+	if !ok {
+		return nil, ErrUnknownOrder
+	}
+
+	cancellation := order.NewCancellation(orderID)
+	if err := cancellation.Sign(trader.keyPair); err != nil {
+		return nil, err
+	}
+
+	for i := range status.Pools {
+		poolStatus := &status.Pools[i]
+		poolStatus.Cancelled = make([]Delivery, 0, len(poolStatus.Opened))
+		for _, delivery := range poolStatus.Opened {
+			if delivery.Err == nil {
+				poolStatus.Cancelled = append(poolStatus.Cancelled, Delivery{NodeID: delivery.NodeID})
+			}
+		}
+		do.CoForAll(poolStatus.Cancelled, func(j int) {
+			multiAddress, err := trader.findMultiAddress(poolStatus.Cancelled[j].NodeID, nil)
+			if err != nil {
+				poolStatus.Cancelled[j].Err = err
+				return
+			}
+			poolStatus.Cancelled[j].Err = trader.clientPool.CancelOrder(multiAddress, cancellation.OrderID, cancellation.Signature)
+		})
+	}
+
+	trader.Enter(nil)
+	trader.statuses[string(orderID)] = status
+	trader.Exit()
+
+	if !status.IsCancelled() {
+		return status.Clone(), ErrOrderNotCancelled
+	}
+	return status.Clone(), nil
+}
+
+// Reveal an Order that was opened by the Trader, after it has been matched, so
+// that the dark nodes can settle the match. The Account is the account of the
+// Trader on the settlement layer. The signed Reveal is sent to every dark node
+// that received an order fragment of the Order. Dark nodes only accept the
+// Reveal while they have a match of the Order that has not been settled, so
+// the Order is revealed again for every match of a partially filled Order. An
+// ErrOrderNotRevealed is returned, alongside the Status, if no dark node
+// accepted the Reveal.
+func (trader *Trader) Reveal(ord *order.Order, account []byte) (*Status, error) {
+	trader.EnterReadOnly(nil)
+	status, ok := trader.statuses[string(ord.ID)]
+	if ok {
+		status = status.Clone()
+	}
+	trader.ExitReadOnly()
+	if !ok {
+		return nil, ErrUnknownOrder
+	}
+
+	reveal := order.NewReveal(trader.keyPair.ID(), ord, account)
+	if err := reveal.Sign(trader.keyPair); err != nil {
+		return nil, err
+	}
+	serializedReveal := rpc.SerializeReveal(reveal)
+
+	for i := range status.Pools {
+		poolStatus := &status.Pools[i]
+		poolStatus.Revealed = make([]Delivery, 0, len(poolStatus.Opened))
+		for _, delivery := range poolStatus.Opened {
+			if delivery.Err == nil {
+				poolStatus.Revealed = append(poolStatus.Revealed, Delivery{NodeID: delivery.NodeID})
+			}
+		}
+		do.CoForAll(poolStatus.Revealed, func(j int) {
+			multiAddress, err := trader.findMultiAddress(poolStatus.Revealed[j].NodeID, nil)
+			if err != nil {
+				poolStatus.Revealed[j].Err = err
+				return
+			}
+			poolStatus.Revealed[j].Err = trader.clientPool.RevealOrder(multiAddress, serializedReveal)
+		})
+	}
+
+	trader.Enter(nil)
+	trader.statuses[string(ord.ID)] = status
+	trader.Exit()
+
+	if !status.IsRevealed() {
+		return status.Clone(), ErrOrderNotRevealed
+	}
+	return status.Clone(), nil
+}
+
+// Status returns the delivery Status of an Order that was opened by the
+// Trader. An ErrUnknownOrder is returned if the Trader has not opened the
+// Order.
+func (trader *Trader) Status(orderID order.ID) (*Status, error) {
+	trader.EnterReadOnly(nil)
+	defer trader.ExitReadOnly()
+	status, ok := trader.statuses[string(orderID)]
+	if !ok {
+		return nil, ErrUnknownOrder
+	}
+	return status.Clone(), nil
+}
+
+// findMultiAddress returns the MultiAddress of a dark node. The known
+// MultiAddress is used when it is not nil, otherwise the Trader queries its
+// bootstrap nodes. The MultiAddress is cached so that the dark node can be
+// found again when the Order is cancelled.
+func (trader *Trader) findMultiAddress(id identity.ID, known *identity.MultiAddress) (identity.MultiAddress, error) {
+	target := id.Address()
+	if known != nil {
+		trader.Enter(nil)
+		trader.multiAddresses[target] = *known
+		trader.Exit()
+		return *known, nil
+	}
+
+	trader.EnterReadOnly(nil)
+	multiAddress, ok := trader.multiAddresses[target]
+	trader.ExitReadOnly()
+	if ok {
+		return multiAddress, nil
+	}
+
+	var err error
+	serializedTarget := rpc.SerializeAddress(target)
+	for _, bootstrapMultiAddress := range trader.options.BootstrapMultiAddresses {
+		if bootstrapMultiAddress.Address() == target {
+			multiAddress, ok = bootstrapMultiAddress, true
+			break
+		}
+		candidates, queryErr := trader.clientPool.QueryPeersDeep(bootstrapMultiAddress, serializedTarget)
+		if queryErr != nil {
+			err = queryErr
+			continue
+		}
+		for candidate := range candidates {
+			deserializedCandidate, _, deserializeErr := rpc.DeserializeMultiAddress(candidate)
+			if deserializeErr != nil {
+				continue
+			}
+			if !ok && deserializedCandidate.Address() == target {
+				multiAddress, ok = deserializedCandidate, true
+			}
+		}
+		if ok {
+			break
+		}
+	}
+	if !ok {
+		if err != nil {
+			return multiAddress, err
+		}
+		return multiAddress, ErrUnknownDarkNode
+	}
+
+	trader.Enter(nil)
+	trader.multiAddresses[target] = multiAddress
+	trader.Exit()
+	return multiAddress, nil
+}
+
+// selectPool returns the dark pool in which an Order is opened, or nil if all
+// dark pools are empty. The dark pool is selected by the hash of the market of
+// the Order, so every trader opens the orders of a market in the same dark
+// pool for as long as the dark pools of the epoch do not change.
+func selectPool(pools dark.Pools, ord *order.Order) *dark.Pool {
+	nonEmptyPools := make(dark.Pools, 0, len(pools))
+	for _, pool := range pools {
+		if pool.Size() > 0 {
+			nonEmptyPools = append(nonEmptyPools, pool)
+		}
+	}
+	if len(nonEmptyPools) == 0 {
+		return nil
+	}
+	codes := make([]byte, 16)
+	binary.BigEndian.PutUint64(codes[:8], uint64(ord.FstCode))
+	binary.BigEndian.PutUint64(codes[8:], uint64(ord.SndCode))
+	hash := crypto.Keccak256(marketTag, codes)
+	return nonEmptyPools[binary.BigEndian.Uint64(hash[:8])%uint64(len(nonEmptyPools))]
+}
+
+// encryptFragments encrypts each order fragment for the dark node that it is
+// sent to, so that the rest of the dark pool can hold it for a dark node that
+// is not running. Dark nodes without a known public key are skipped.
+func encryptFragments(nodes dark.Nodes, fragments []*order.Fragment) ([]*rpc.EncryptedOrderFragment, error) {
+	encryptedFragments := make([]*rpc.EncryptedOrderFragment, 0, len(nodes))
+	for i := range nodes {
+		if nodes[i].PublicKey == nil {
+			continue
+		}
+		encryptedFragment, err := rpc.EncryptOrderFragment(fragments[i], nodes[i].ID, nodes[i].PublicKey)
+		if err != nil {
+			return nil, err
+		}
+		encryptedFragments = append(encryptedFragments, rpc.SerializeEncryptedOrderFragment(encryptedFragment))
+	}
+	return encryptedFragments, nil
+}
+
+// poolNodes returns a copy of the Nodes in a Pool, in a stable order so that
+// the i-th order fragment is sent to the i-th Node.
+func poolNodes(pool *dark.Pool) dark.Nodes {
+	nodes := dark.Nodes{}
+	pool.For(func(node *dark.Node) {
+		nodes = append(nodes, *node)
+	})
+	return nodes
+}
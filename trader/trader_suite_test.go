@@ -0,0 +1,13 @@
+package trader_test
+
+import (
+	"testing"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+func TestTrader(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "Trader Suite")
+}
@@ -0,0 +1,418 @@
+package trader_test
+
+import (
+	"bytes"
+	"fmt"
+	"net"
+	"sync"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	. "github.com/republicprotocol/republic-go/trader"
+
+	"github.com/republicprotocol/republic-go/compute"
+	"github.com/republicprotocol/republic-go/dark"
+	"github.com/republicprotocol/republic-go/identity"
+	"github.com/republicprotocol/republic-go/logger"
+	"github.com/republicprotocol/republic-go/network"
+	"github.com/republicprotocol/republic-go/network/rpc"
+	"github.com/republicprotocol/republic-go/order"
+	"github.com/republicprotocol/republic-go/order/fixed"
+	"github.com/republicprotocol/republic-go/order/market"
+	"github.com/republicprotocol/republic-go/stackint"
+	"google.golang.org/grpc"
+)
+
+var _ = Describe("Traders", func() {
+
+	var keyPair identity.KeyPair
+	var darkNodes []*mockDarkNode
+	var servers []*grpc.Server
+	var pool *dark.Pool
+	var trader *Trader
+
+	BeforeEach(func() {
+		var err error
+		keyPair, err = identity.NewKeyPair()
+		Ω(err).ShouldNot(HaveOccurred())
+		multiAddress, err := identity.NewMultiAddressFromString(fmt.Sprintf("/ip4/127.0.0.1/tcp/80/republic/%s", keyPair.Address()))
+		Ω(err).ShouldNot(HaveOccurred())
+
+		// Three dark nodes are reachable, and the fourth dark node cannot be
+		// found
+		darkNodes = make([]*mockDarkNode, 3)
+		servers = make([]*grpc.Server, 3)
+		pool = dark.NewPool()
+		for i := range darkNodes {
+			darkNodes[i], servers[i], err = startMockDarkNode(6000 + i)
+			Ω(err).ShouldNot(HaveOccurred())
+			node := dark.NewNode(darkNodes[i].multiAddress.ID())
+			node.PublicKey = darkNodes[i].keyPair.PublicKeyBytes()
+			node.SetMultiAddress(darkNodes[i].multiAddress)
+			pool.Append(node)
+		}
+		_, unknownKeyPair, err := identity.NewAddress()
+		Ω(err).ShouldNot(HaveOccurred())
+		pool.Append(dark.NewNode(unknownKeyPair.ID()))
+
+		options := DefaultOptions(multiAddress)
+		options.Timeout = time.Second
+		options.TimeoutRetries = 1
+		trader, err = NewTrader(keyPair, &mockDarkOcean{pools: dark.Pools{pool}}, market.NewDefaultRegistry(), options)
+		Ω(err).ShouldNot(HaveOccurred())
+	})
+
+	AfterEach(func() {
+		for _, server := range servers {
+			server.Stop()
+		}
+	})
+
+	Context("when opening orders", func() {
+
+		It("should send one signed order fragment to each dark node", func() {
+			ord := newOrder()
+			status, err := trader.Open(ord)
+			Ω(err).ShouldNot(HaveOccurred())
+			Ω(status.IsOpen()).Should(BeTrue())
+			Ω(status.Pools).Should(HaveLen(1))
+			Ω(status.Pools[0].N).Should(Equal(int64(4)))
+			Ω(status.Pools[0].K).Should(Equal(int64(3)))
+
+			for i, darkNode := range darkNodes {
+				Ω(status.Pools[0].Opened[i].Err).ShouldNot(HaveOccurred())
+				fragment := darkNode.fragment(ord.ID)
+				Ω(fragment).ShouldNot(BeNil())
+				Ω(fragment.Verify()).ShouldNot(HaveOccurred())
+				Ω(fragment.Trader).Should(Equal(keyPair.ID()))
+			}
+			Ω(status.Pools[0].Opened[3].Err).Should(Equal(ErrUnknownDarkNode))
+		})
+
+		It("should send the encrypted order fragments of the dark pool to each dark node", func() {
+			ord := newOrder()
+			_, err := trader.Open(ord)
+			Ω(err).ShouldNot(HaveOccurred())
+
+			for _, darkNode := range darkNodes {
+				// The dark node that cannot be found has no public key
+				encryptedFragments := darkNode.encryptedFragments(ord.ID)
+				Ω(encryptedFragments).Should(HaveLen(len(darkNodes)))
+				for i, encryptedFragment := range encryptedFragments {
+					Ω(encryptedFragment.To).Should(Equal(darkNodes[i].multiAddress.ID()))
+					fragment, err := rpc.DecryptOrderFragment(encryptedFragment, darkNodes[i].keyPair)
+					Ω(err).ShouldNot(HaveOccurred())
+					Ω(fragment.ID).Should(Equal(darkNodes[i].fragment(ord.ID).ID))
+				}
+			}
+		})
+
+		It("should return an error when too few dark nodes receive an order fragment", func() {
+			servers[0].Stop()
+			status, err := trader.Open(newOrder())
+			Ω(err).Should(Equal(ErrOrderNotOpened))
+			Ω(status.IsOpen()).Should(BeFalse())
+			Ω(status.Pools[0].Opened[0].Err).Should(HaveOccurred())
+		})
+
+		It("should return an error for immediate orders without an open time", func() {
+			_, err := trader.Open(newOrder().WithTimeInForce(order.TimeInForceIOC))
+			Ω(err).Should(Equal(ErrOpenTimeMissing))
+
+			ord := newOrder().WithTimeInForce(order.TimeInForceIOC).WithOpenTime(time.Now())
+			_, err = trader.Open(ord)
+			Ω(err).ShouldNot(HaveOccurred())
+			Ω(darkNodes[0].fragment(ord.ID).OrderOpenTime.Unix()).Should(Equal(ord.OpenTime.Unix()))
+		})
+
+		It("should return an error when there are no dark pools", func() {
+			multiAddress, err := identity.NewMultiAddressFromString(fmt.Sprintf("/ip4/127.0.0.1/tcp/80/republic/%s", keyPair.Address()))
+			Ω(err).ShouldNot(HaveOccurred())
+			trader, err := NewTrader(keyPair, &mockDarkOcean{pools: dark.Pools{dark.NewPool()}}, market.NewDefaultRegistry(), DefaultOptions(multiAddress))
+			Ω(err).ShouldNot(HaveOccurred())
+			_, err = trader.Open(newOrder())
+			Ω(err).Should(Equal(ErrNoDarkPools))
+		})
+
+		It("should return an error for orders in unlisted markets", func() {
+			ord := newOrder()
+			ord = order.NewOrder(ord.Type, ord.Parity, ord.Expiry, order.CurrencyCodeBTC, order.CurrencyCodeETH, ord.Price, ord.MaxVolume, ord.MinVolume, ord.Nonce)
+			_, err := trader.Open(ord)
+			Ω(err).Should(Equal(market.ErrUnknownMarket))
+			Ω(darkNodes[0].fragment(ord.ID)).Should(BeNil())
+		})
+
+		It("should open all orders of a market in the same dark pool", func() {
+			multiAddress, err := identity.NewMultiAddressFromString(fmt.Sprintf("/ip4/127.0.0.1/tcp/80/republic/%s", keyPair.Address()))
+			Ω(err).ShouldNot(HaveOccurred())
+
+			// The dark nodes of the other dark pool cannot be found
+			otherPool := dark.NewPool()
+			for i := 0; i < 4; i++ {
+				_, unknownKeyPair, err := identity.NewAddress()
+				Ω(err).ShouldNot(HaveOccurred())
+				otherPool.Append(dark.NewNode(unknownKeyPair.ID()))
+			}
+			trader, err := NewTrader(keyPair, &mockDarkOcean{pools: dark.Pools{dark.NewPool(), pool, otherPool}}, market.NewDefaultRegistry(), DefaultOptions(multiAddress))
+			Ω(err).ShouldNot(HaveOccurred())
+
+			var nodeIDs []identity.ID
+			for i := 0; i < 3; i++ {
+				ord := newOrder()
+				ord = order.NewOrder(ord.Type, ord.Parity, ord.Expiry, ord.FstCode, ord.SndCode, ord.Price, ord.MaxVolume, ord.MinVolume, trader.NextNonce())
+				status, _ := trader.Open(ord)
+				Ω(status.Pools).Should(HaveLen(1))
+				ids := make([]identity.ID, len(status.Pools[0].Opened))
+				for j, delivery := range status.Pools[0].Opened {
+					ids[j] = delivery.NodeID
+				}
+				if nodeIDs == nil {
+					nodeIDs = ids
+				}
+				Ω(ids).Should(Equal(nodeIDs))
+			}
+		})
+	})
+
+	Context("when cancelling orders", func() {
+
+		It("should send the cancellation to every dark node that received an order fragment", func() {
+			ord := newOrder()
+			_, err := trader.Open(ord)
+			Ω(err).ShouldNot(HaveOccurred())
+
+			status, err := trader.Cancel(ord.ID)
+			Ω(err).ShouldNot(HaveOccurred())
+			Ω(status.IsCancelled()).Should(BeTrue())
+			Ω(status.Pools[0].Cancelled).Should(HaveLen(3))
+			for _, darkNode := range darkNodes {
+				Ω(darkNode.isCancelled(ord.ID)).Should(BeTrue())
+			}
+		})
+
+		It("should return an error for orders that were not opened", func() {
+			_, err := trader.Cancel(newOrder().ID)
+			Ω(err).Should(Equal(ErrUnknownOrder))
+		})
+	})
+
+	Context("when revealing orders", func() {
+
+		It("should send the signed reveal to every dark node that received an order fragment", func() {
+			ord := newOrder()
+			_, err := trader.Open(ord)
+			Ω(err).ShouldNot(HaveOccurred())
+
+			status, err := trader.Reveal(ord, []byte("account"))
+			Ω(err).ShouldNot(HaveOccurred())
+			Ω(status.IsRevealed()).Should(BeTrue())
+			Ω(status.Pools[0].Revealed).Should(HaveLen(3))
+			for _, darkNode := range darkNodes {
+				reveal := darkNode.reveal(ord.ID)
+				Ω(reveal).ShouldNot(BeNil())
+				Ω(reveal.Trader).Should(Equal(keyPair.ID()))
+				Ω(reveal.Account).Should(Equal([]byte("account")))
+			}
+		})
+
+		It("should return an error for orders that were not opened", func() {
+			_, err := trader.Reveal(newOrder(), []byte("account"))
+			Ω(err).Should(Equal(ErrUnknownOrder))
+		})
+	})
+
+	Context("when creating orders", func() {
+
+		It("should return monotonically increasing nonces", func() {
+			previous := trader.NextNonce()
+			for i := 0; i < 100; i++ {
+				nonce := trader.NextNonce()
+				Ω(nonce.Cmp(previous)).Should(Equal(1))
+				previous = nonce
+			}
+		})
+	})
+
+	Context("when querying the status of orders", func() {
+
+		It("should return the deliveries of opened and cancelled orders", func() {
+			ord := newOrder()
+			_, err := trader.Status(ord.ID)
+			Ω(err).Should(Equal(ErrUnknownOrder))
+
+			_, err = trader.Open(ord)
+			Ω(err).ShouldNot(HaveOccurred())
+			status, err := trader.Status(ord.ID)
+			Ω(err).ShouldNot(HaveOccurred())
+			Ω(status.IsOpen()).Should(BeTrue())
+			Ω(status.IsCancelled()).Should(BeFalse())
+
+			_, err = trader.Cancel(ord.ID)
+			Ω(err).ShouldNot(HaveOccurred())
+			status, err = trader.Status(ord.ID)
+			Ω(err).ShouldNot(HaveOccurred())
+			Ω(status.IsCancelled()).Should(BeTrue())
+		})
+	})
+})
+
+type mockDarkOcean struct {
+	pools dark.Pools
+}
+
+func (ocean *mockDarkOcean) Pools() dark.Pools {
+	return ocean.pools
+}
+
+// mockDarkNode records the order fragments, encrypted order fragments,
+// cancellations and reveals that it receives. Cancellations and reveals are
+// verified against the trader that signed the order fragment.
+type mockDarkNode struct {
+	mu           *sync.Mutex
+	keyPair      identity.KeyPair
+	multiAddress identity.MultiAddress
+	fragments    map[string]*order.Fragment
+	encrypted    map[string][]*order.EncryptedFragment
+	cancelled    map[string]bool
+	revealed     map[string]*order.Reveal
+}
+
+func startMockDarkNode(port int) (*mockDarkNode, *grpc.Server, error) {
+	address, keyPair, err := identity.NewAddress()
+	if err != nil {
+		return nil, nil, err
+	}
+	multiAddress, err := identity.NewMultiAddressFromString(fmt.Sprintf("/ip4/127.0.0.1/tcp/%d/republic/%s", port, address))
+	if err != nil {
+		return nil, nil, err
+	}
+	darkNode := &mockDarkNode{
+		mu:           new(sync.Mutex),
+		keyPair:      keyPair,
+		multiAddress: multiAddress,
+		fragments:    map[string]*order.Fragment{},
+		encrypted:    map[string][]*order.EncryptedFragment{},
+		cancelled:    map[string]bool{},
+		revealed:     map[string]*order.Reveal{},
+	}
+
+	server := grpc.NewServer()
+	network.NewDarkService(darkNode, network.Options{MultiAddress: multiAddress}, &logger.Logger{}).Register(server)
+	listener, err := net.Listen("tcp", fmt.Sprintf("127.0.0.1:%d", port))
+	if err != nil {
+		return nil, nil, err
+	}
+	go server.Serve(listener)
+	return darkNode, server, nil
+}
+
+func (darkNode *mockDarkNode) fragment(orderID order.ID) *order.Fragment {
+	darkNode.mu.Lock()
+	defer darkNode.mu.Unlock()
+	return darkNode.fragments[string(orderID)]
+}
+
+func (darkNode *mockDarkNode) encryptedFragments(orderID order.ID) []*order.EncryptedFragment {
+	darkNode.mu.Lock()
+	defer darkNode.mu.Unlock()
+	return darkNode.encrypted[string(orderID)]
+}
+
+func (darkNode *mockDarkNode) isCancelled(orderID order.ID) bool {
+	darkNode.mu.Lock()
+	defer darkNode.mu.Unlock()
+	return darkNode.cancelled[string(orderID)]
+}
+
+func (darkNode *mockDarkNode) reveal(orderID order.ID) *order.Reveal {
+	darkNode.mu.Lock()
+	defer darkNode.mu.Unlock()
+	return darkNode.revealed[string(orderID)]
+}
+
+func (darkNode *mockDarkNode) OnSync(from identity.MultiAddress) ([]*compute.Snapshot, error) {
+	return []*compute.Snapshot{}, nil
+}
+
+func (darkNode *mockDarkNode) OnOpenOrder(from identity.MultiAddress, orderFragment *order.Fragment, encryptedFragments []*order.EncryptedFragment) error {
+	darkNode.mu.Lock()
+	defer darkNode.mu.Unlock()
+	darkNode.fragments[string(orderFragment.OrderID)] = orderFragment
+	darkNode.encrypted[string(orderFragment.OrderID)] = encryptedFragments
+	return nil
+}
+
+func (darkNode *mockDarkNode) OnCancelOrder(from identity.MultiAddress, cancellation *order.Cancellation) error {
+	darkNode.mu.Lock()
+	defer darkNode.mu.Unlock()
+	fragment, ok := darkNode.fragments[string(cancellation.OrderID)]
+	if !ok {
+		return compute.ErrOrderFragmentNotFound
+	}
+	if err := cancellation.VerifySignature(fragment.Trader); err != nil {
+		return err
+	}
+	darkNode.cancelled[string(cancellation.OrderID)] = true
+	return nil
+}
+
+func (darkNode *mockDarkNode) OnNotifications(from identity.MultiAddress, subscription *order.Subscription, done <-chan struct{}) (<-chan *order.Notification, error) {
+	notifications := make(chan *order.Notification)
+	go func() {
+		<-done
+		close(notifications)
+	}()
+	return notifications, nil
+}
+
+func (darkNode *mockDarkNode) OnRevealOrder(from identity.MultiAddress, reveal *order.Reveal) error {
+	darkNode.mu.Lock()
+	defer darkNode.mu.Unlock()
+	if err := reveal.Verify(); err != nil {
+		return err
+	}
+	fragment, ok := darkNode.fragments[string(reveal.Order.ID)]
+	if !ok {
+		return compute.ErrOrderFragmentNotFound
+	}
+	if !bytes.Equal(fragment.Trader, reveal.Trader) {
+		return identity.ErrInvalidSignature
+	}
+	darkNode.revealed[string(reveal.Order.ID)] = reveal
+	return nil
+}
+
+func (darkNode *mockDarkNode) OnRandomFragmentShares(from identity.MultiAddress, randomFragments []*compute.RandomFragment) ([]*compute.RandomFragment, error) {
+	return []*compute.RandomFragment{}, nil
+}
+
+func (darkNode *mockDarkNode) OnResidueFragmentShares(from identity.MultiAddress, residueIDs []compute.ResidueID) ([]*compute.ResidueFragment, error) {
+	return []*compute.ResidueFragment{}, nil
+}
+
+func (darkNode *mockDarkNode) OnComputeResidueFragment(from identity.MultiAddress, residueFragments []*compute.ResidueFragment) error {
+	return nil
+}
+
+func (darkNode *mockDarkNode) OnBroadcastAlphaBetaFragment(from identity.MultiAddress, alphaBetaFragment *compute.AlphaBetaFragment) (*compute.AlphaBetaFragment, error) {
+	return alphaBetaFragment, nil
+}
+
+func (darkNode *mockDarkNode) OnBroadcastDeltaFragment(from identity.MultiAddress, deltaFragment *compute.DeltaFragment) error {
+	return nil
+}
+
+func (darkNode *mockDarkNode) OnUpdateMidpoint(from identity.MultiAddress, midpointUpdate *compute.MidpointUpdate) error {
+	return nil
+}
+
+// newOrder returns an Order in a Market that is listed by default.
+func newOrder() *order.Order {
+	price, err := fixed.EncodePrice("0.01")
+	Ω(err).ShouldNot(HaveOccurred())
+	maxVolume := stackint.FromUint(1000000000)
+	minVolume := stackint.FromUint(100000000)
+	nonce := stackint.Zero()
+	return order.NewOrder(order.TypeLimit, order.ParityBuy, time.Now().Add(time.Hour), order.CurrencyCodeETH, order.CurrencyCodeBTC, &price, &maxVolume, &minVolume, &nonce)
+}